@@ -7,7 +7,7 @@ import type { ExtensionMessage, WebviewMessage } from "@roo-code/types"
 
 import { DEFAULT_FLAGS } from "@/types/index.js"
 
-import { type ExtensionHostOptions, ExtensionHost } from "../extension-host.js"
+import { type ExtensionHostOptions, BudgetLimitReachedError, ExtensionHost } from "../extension-host.js"
 import { ExtensionClient } from "../extension-client.js"
 import { AgentLoopState } from "../agent-state.js"
 
@@ -595,6 +595,35 @@ describe("ExtensionHost", () => {
 			await expect(taskPromise).resolves.toBeUndefined()
 		})
 
+		it("should reject when the budget is used up in non-interactive mode", async () => {
+			const host = createTestHost({ nonInteractive: true, maxCost: 1 })
+			host.markWebviewReady()
+
+			const client = getPrivate(host, "client") as ExtensionClient
+			const taskPromise = host.runTask("test prompt")
+
+			const waitingForInputEvent = {
+				ask: "budget_limit_reached" as const,
+				stateInfo: {
+					state: AgentLoopState.IDLE,
+					isWaitingForInput: true,
+					isRunning: false,
+					isStreaming: false,
+					requiredAction: "answer" as const,
+					description: "Budget limit reached",
+				},
+				message: {
+					ts: 1,
+					type: "ask" as const,
+					ask: "budget_limit_reached" as const,
+					text: '{"metric":"cost"}',
+				},
+			}
+			setTimeout(() => client.getEmitter().emit("waitingForInput", waitingForInputEvent), 10)
+
+			await expect(taskPromise).rejects.toBeInstanceOf(BudgetLimitReachedError)
+		})
+
 		it("should send showTaskWithId for resumeTask and resolve on completion", async () => {
 			const host = createTestHost()
 			host.markWebviewReady()
@@ -660,6 +689,29 @@ describe("ExtensionHost", () => {
 
 			const initialSettings = getPrivate<Record<string, unknown>>(host, "initialSettings")
 			expect(initialSettings.autoApprovalEnabled).toBe(false)
+			expect(initialSettings.denyUnapprovedActions).toBe(false)
+		})
+
+		it("should only approve the actions of the auto-approval profile", () => {
+			const host = createTestHost({
+				nonInteractive: true,
+				autoApprovalProfile: { alwaysAllowReadOnly: true, allowedCommands: ["npm test"] },
+			})
+
+			const initialSettings = getPrivate<Record<string, unknown>>(host, "initialSettings")
+			expect(initialSettings.autoApprovalEnabled).toBe(true)
+			expect(initialSettings.alwaysAllowReadOnly).toBe(true)
+			expect(initialSettings.alwaysAllowWrite).toBe(false)
+			expect(initialSettings.alwaysAllowExecute).toBe(false)
+			expect(initialSettings.allowedCommands).toEqual(["npm test"])
+			expect(initialSettings.denyUnapprovedActions).toBe(true)
+		})
+
+		it("should set the task budget from maxCost", () => {
+			const host = createTestHost({ maxCost: 2.5 })
+
+			const initialSettings = getPrivate<Record<string, unknown>>(host, "initialSettings")
+			expect(initialSettings.taskBudget).toEqual({ maxCost: 2.5 })
 		})
 
 		it("should set reasoning effort when specified", () => {
@@ -231,6 +231,9 @@ export class AskDispatcher {
 			case "auto_approval_max_req_reached":
 				return await this.handleAutoApprovalMaxReached(ts, text)
 
+			case "budget_limit_reached":
+				return await this.handleBudgetLimitReached(ts, text)
+
 			default:
 				return { handled: false }
 		}
@@ -572,6 +575,32 @@ export class AskDispatcher {
 		}
 	}
 
+	/**
+	 * Handle budget limit reached.
+	 */
+	private async handleBudgetLimitReached(ts: number, text: string): Promise<AskHandleResult> {
+		this.outputManager.output("\n[budget limit reached]")
+		if (text) {
+			this.outputManager.output(`  Details: ${text}`)
+		}
+		this.outputManager.markDisplayed(ts, text || "", false)
+
+		if (this.nonInteractive) {
+			// The extension host ends the task, since nobody can raise the budget.
+			return { handled: true }
+		}
+
+		try {
+			const proceed = await this.promptManager.promptForYesNo("Continue with a new budget? (y/n): ")
+			this.sendApprovalResponse(proceed)
+			return { handled: true, response: proceed ? "yesButtonClicked" : "noButtonClicked" }
+		} catch {
+			this.outputManager.output("[Defaulting to: no]")
+			this.sendApprovalResponse(false)
+			return { handled: true, response: "noButtonClicked" }
+		}
+	}
+
 	/**
 	 * Handle task resume prompt.
 	 */
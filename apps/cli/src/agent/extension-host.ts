@@ -17,6 +17,7 @@ import { EventEmitter } from "events"
 import pWaitFor from "p-wait-for"
 
 import type {
+	AutoApprovalProfile,
 	ClineMessage,
 	ExtensionMessage,
 	ReasoningEffortExtended,
@@ -29,6 +30,7 @@ import { DebugLogger, setDebugLogEnabled } from "@roo-code/core/cli"
 import { DEFAULT_FLAGS, type SupportedProvider } from "@/types/index.js"
 import type { User } from "@/lib/sdk/index.js"
 import { getProviderSettings } from "@/lib/utils/provider.js"
+import { getAutoApprovalProfileSettings } from "@/lib/utils/auto-approval.js"
 import { createEphemeralStorageDir } from "@/lib/storage/index.js"
 
 import type { WaitingForInputEvent, TaskCompletedEvent } from "./events.js"
@@ -63,6 +65,16 @@ function findCliPackageRoot(): string {
 
 const CLI_PACKAGE_ROOT = process.env.ROO_CLI_ROOT || findCliPackageRoot()
 
+/**
+ * The error a non-interactive task ends with when its budget has been used up.
+ */
+export class BudgetLimitReachedError extends Error {
+	constructor(details?: string) {
+		super(details ? `Budget limit reached: ${details}` : "Budget limit reached")
+		this.name = "BudgetLimitReachedError"
+	}
+}
+
 export interface ExtensionHostOptions {
 	mode: string
 	reasoningEffort?: ReasoningEffortExtended | "unspecified" | "disabled"
@@ -74,6 +86,15 @@ export interface ExtensionHostOptions {
 	workspacePath: string
 	extensionPath: string
 	nonInteractive?: boolean
+	/**
+	 * When set, only the actions the profile allows are approved and the
+	 * others are denied, instead of approving everything.
+	 */
+	autoApprovalProfile?: AutoApprovalProfile
+	/**
+	 * The cost, in dollars, a task can reach before it stops.
+	 */
+	maxCost?: number
 	/**
 	 * When true, uses a temporary storage directory that is cleaned up on exit.
 	 */
@@ -230,25 +251,38 @@ export class ExtensionHost extends EventEmitter implements ExtensionHostInterfac
 			...getProviderSettings(this.options.provider, this.options.apiKey, this.options.model),
 		}
 
-		this.initialSettings = this.options.nonInteractive
-			? {
-					autoApprovalEnabled: true,
-					alwaysAllowReadOnly: true,
-					alwaysAllowReadOnlyOutsideWorkspace: true,
-					alwaysAllowWrite: true,
-					alwaysAllowWriteOutsideWorkspace: true,
-					alwaysAllowWriteProtected: true,
-					alwaysAllowMcp: true,
-					alwaysAllowModeSwitch: true,
-					alwaysAllowSubtasks: true,
-					alwaysAllowExecute: true,
-					allowedCommands: ["*"],
-					...baseSettings,
-				}
-			: {
-					autoApprovalEnabled: false,
-					...baseSettings,
-				}
+		let approvalSettings: RooCodeSettings
+
+		if (this.options.autoApprovalProfile) {
+			approvalSettings = getAutoApprovalProfileSettings(this.options.autoApprovalProfile)
+		} else if (this.options.nonInteractive) {
+			approvalSettings = {
+				autoApprovalEnabled: true,
+				alwaysAllowReadOnly: true,
+				alwaysAllowReadOnlyOutsideWorkspace: true,
+				alwaysAllowWrite: true,
+				alwaysAllowWriteOutsideWorkspace: true,
+				alwaysAllowWriteProtected: true,
+				allowedWritePaths: [],
+				deniedWritePaths: [],
+				alwaysAllowMcp: true,
+				alwaysAllowModeSwitch: true,
+				alwaysAllowSubtasks: true,
+				alwaysAllowExecute: true,
+				allowedCommands: ["*"],
+				deniedCommands: [],
+			}
+		} else {
+			approvalSettings = { autoApprovalEnabled: false }
+		}
+
+		// Settings persist between runs, so the ones a run may not set are reset.
+		this.initialSettings = {
+			denyUnapprovedActions: false,
+			...approvalSettings,
+			taskBudget: { maxCost: this.options.maxCost },
+			...baseSettings,
+		}
 
 		if (this.options.reasoningEffort && this.options.reasoningEffort !== "unspecified") {
 			if (this.options.reasoningEffort === "disabled") {
@@ -494,6 +528,10 @@ export class ExtensionHost extends EventEmitter implements ExtensionHostInterfac
 				if (messageHandler) {
 					this.client.off("message", messageHandler)
 				}
+
+				if (waitingForInputHandler) {
+					this.client.off("waitingForInput", waitingForInputHandler)
+				}
 			}
 
 			// When exitOnError is enabled, listen for api_req_retry_delayed messages
@@ -511,6 +549,21 @@ export class ExtensionHost extends EventEmitter implements ExtensionHostInterfac
 				this.client.on("message", messageHandler)
 			}
 
+			// In non-interactive mode nobody can raise a budget that has been
+			// used up, so the task ends there.
+			let waitingForInputHandler: ((event: WaitingForInputEvent) => void) | null = null
+
+			if (this.options.nonInteractive) {
+				waitingForInputHandler = ({ ask, message }: WaitingForInputEvent) => {
+					if (ask === "budget_limit_reached") {
+						cleanup()
+						reject(new BudgetLimitReachedError(message.text))
+					}
+				}
+
+				this.client.on("waitingForInput", waitingForInputHandler)
+			}
+
 			this.client.once("taskCompleted", completeHandler)
 			this.client.once("error", errorHandler)
 		})
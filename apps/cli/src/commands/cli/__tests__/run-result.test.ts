@@ -0,0 +1,46 @@
+// pnpm --filter @roo-code/cli test src/commands/cli/__tests__/run-result.test.ts
+
+import type { ClineMessage } from "@roo-code/types"
+
+import { BudgetLimitReachedError } from "@/agent/index.js"
+
+import { buildRunResult, getRunErrorStatus } from "../run-result.js"
+
+const messages: ClineMessage[] = [
+	{ ts: 1, type: "say", say: "text", text: "Fix the test" },
+	{ ts: 2, type: "say", say: "api_req_started", text: JSON.stringify({ tokensIn: 100, tokensOut: 20, cost: 0.5 }) },
+	{ ts: 3, type: "say", say: "completion_result", text: "The test passes" },
+]
+
+describe("buildRunResult", () => {
+	it("reports the completion result, cost and transcript of a run", () => {
+		const result = buildRunResult({ status: "completed", taskId: "task-1", mode: "code", messages, patch: "diff" })
+
+		expect(result).toMatchObject({
+			status: "completed",
+			exitCode: 0,
+			taskId: "task-1",
+			mode: "code",
+			content: "The test passes",
+			cost: { totalCost: 0.5, inputTokens: 100, outputTokens: 20 },
+			patch: "diff",
+			transcript: messages,
+		})
+	})
+
+	it("reports the error a run failed with", () => {
+		const error = new BudgetLimitReachedError('{"metric":"cost"}')
+		const result = buildRunResult({ status: getRunErrorStatus(error), mode: "code", messages, error })
+
+		expect(result.status).toBe("budget_exceeded")
+		expect(result.exitCode).toBe(2)
+		expect(result.content).toBe('Budget limit reached: {"metric":"cost"}')
+	})
+})
+
+describe("getRunErrorStatus", () => {
+	it("tells budget limits apart from other errors", () => {
+		expect(getRunErrorStatus(new BudgetLimitReachedError())).toBe("budget_exceeded")
+		expect(getRunErrorStatus(new Error("API request failed"))).toBe("failed")
+	})
+})
@@ -0,0 +1,63 @@
+import fs from "fs/promises"
+import path from "path"
+
+import { type ClineMessage, type RooCliRunResult, type RooCliRunStatus, rooCliRunExitCodes } from "@roo-code/types"
+import { consolidateTokenUsage } from "@roo-code/core/cli"
+
+import { BudgetLimitReachedError } from "@/agent/index.js"
+
+export interface BuildRunResultOptions {
+	status: RooCliRunStatus
+	exitCode?: number
+	taskId?: string
+	mode: string
+	messages: ClineMessage[]
+	patch?: string
+	error?: Error
+}
+
+/**
+ * The status of a run that ended with an error.
+ */
+export function getRunErrorStatus(error: unknown): RooCliRunStatus {
+	return error instanceof BudgetLimitReachedError ? "budget_exceeded" : "failed"
+}
+
+export function buildRunResult({
+	status,
+	exitCode = rooCliRunExitCodes[status],
+	taskId,
+	mode,
+	messages,
+	patch,
+	error,
+}: BuildRunResultOptions): RooCliRunResult {
+	const { totalCost, totalTokensIn, totalTokensOut, totalCacheWrites, totalCacheReads } =
+		consolidateTokenUsage(messages)
+
+	const completionResult = messages
+		.filter((message) => message.type === "say" && message.say === "completion_result")
+		.at(-1)?.text
+
+	return {
+		status,
+		exitCode,
+		taskId,
+		mode,
+		content: status === "completed" ? completionResult : (error?.message ?? completionResult),
+		cost: {
+			totalCost,
+			inputTokens: totalTokensIn,
+			outputTokens: totalTokensOut,
+			cacheWrites: totalCacheWrites,
+			cacheReads: totalCacheReads,
+		},
+		patch,
+		transcript: messages,
+	}
+}
+
+export async function writeRunResult(filePath: string, result: RooCliRunResult): Promise<void> {
+	await fs.mkdir(path.dirname(filePath), { recursive: true })
+	await fs.writeFile(filePath, JSON.stringify(result, null, 2) + "\n", "utf-8")
+}
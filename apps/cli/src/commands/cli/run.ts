@@ -1,3 +1,4 @@
+import { randomUUID } from "crypto"
 import fs from "fs"
 import path from "path"
 import { fileURLToPath } from "url"
@@ -5,6 +6,7 @@ import { fileURLToPath } from "url"
 import { createElement } from "react"
 import pWaitFor from "p-wait-for"
 
+import { type AutoApprovalProfile, type RooCliRunStatus, rooCliRunExitCodes } from "@roo-code/types"
 import { setLogger } from "@roo-code/vscode-shim"
 
 import {
@@ -23,6 +25,8 @@ import { JsonEventEmitter } from "@/agent/json-event-emitter.js"
 import { createClient } from "@/lib/sdk/index.js"
 import { loadToken, loadSettings } from "@/lib/storage/index.js"
 import { readWorkspaceTaskSessions, resolveWorkspaceResumeSessionId } from "@/lib/task-history/index.js"
+import { loadAutoApprovalProfile } from "@/lib/utils/auto-approval.js"
+import { getWorkspacePatch, snapshotWorkspace } from "@/lib/utils/git.js"
 import { isRecord } from "@/lib/utils/guards.js"
 import { getEnvVarName, getApiKeyFromEnv } from "@/lib/utils/provider.js"
 import { runOnboarding } from "@/lib/utils/onboarding.js"
@@ -33,6 +37,7 @@ import { VERSION } from "@/lib/utils/version.js"
 
 import { ExtensionHost, ExtensionHostOptions } from "@/agent/index.js"
 import { isExpectedControlFlowError } from "./cancellation.js"
+import { buildRunResult, getRunErrorStatus, writeRunResult } from "./run-result.js"
 import { runStdinStreamMode } from "./stdin-stream.js"
 
 const __dirname = path.dirname(fileURLToPath(import.meta.url))
@@ -199,6 +204,28 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 		process.exit(1)
 	}
 
+	if (flagOptions.maxCost !== undefined && !(Number.isFinite(flagOptions.maxCost) && flagOptions.maxCost > 0)) {
+		console.error(`[CLI] Error: Invalid max cost: ${flagOptions.maxCost}; must be a positive number`)
+		process.exit(1)
+	}
+
+	let autoApprovalProfile: AutoApprovalProfile | undefined
+
+	if (flagOptions.autoApprovalProfile) {
+		if (effectiveRequireApproval) {
+			console.error("[CLI] Error: cannot use --auto-approval-profile with --require-approval")
+			process.exit(1)
+		}
+
+		try {
+			autoApprovalProfile = await loadAutoApprovalProfile(flagOptions.autoApprovalProfile)
+		} catch (error) {
+			const message = error instanceof Error ? error.message : String(error)
+			console.error(`[CLI] Error: ${message}`)
+			process.exit(1)
+		}
+	}
+
 	let terminalShell: string | undefined
 	if (flagOptions.terminalShell !== undefined) {
 		const validatedTerminalShell = await validateTerminalShellPath(flagOptions.terminalShell)
@@ -222,6 +249,8 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 		workspacePath: effectiveWorkspacePath,
 		extensionPath: path.resolve(flagOptions.extension || getDefaultExtensionPath(__dirname)),
 		nonInteractive: !effectiveRequireApproval,
+		autoApprovalProfile,
+		maxCost: flagOptions.maxCost,
 		exitOnError: flagOptions.exitOnError,
 		ephemeral: flagOptions.ephemeral,
 		debug: flagOptions.debug,
@@ -370,6 +399,17 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 		process.exit(1)
 	}
 
+	if (flagOptions.resultFile && !flagOptions.print && isTuiSupported) {
+		console.error("[CLI] Error: --result-file requires --print mode")
+		console.error("[CLI] Usage: roo --print --result-file result.json [options] <prompt>")
+		process.exit(1)
+	}
+
+	if (flagOptions.resultFile && flagOptions.stdinPromptStream) {
+		console.error("[CLI] Error: --result-file is not supported with --stdin-prompt-stream")
+		process.exit(1)
+	}
+
 	const useStdinPromptStream = flagOptions.stdinPromptStream
 	let resolvedResumeSessionId: string | undefined
 
@@ -447,6 +487,11 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 		let isShuttingDown = false
 		let hostDisposed = false
 
+		// A run with a result file always has a task id to report.
+		const resultFile = flagOptions.resultFile ? path.resolve(flagOptions.resultFile) : undefined
+		const taskId = resolvedResumeSessionId ?? requestedCreateSessionId ?? (resultFile ? randomUUID() : undefined)
+		const workspaceSnapshot = resultFile ? await snapshotWorkspace(effectiveWorkspacePath) : undefined
+
 		const jsonEmitter = useJsonOutput
 			? new JsonEventEmitter({
 					mode: outputFormat as "json" | "stream-json",
@@ -505,6 +550,33 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 			keepAliveInterval = setInterval(() => {}, SIGNAL_ONLY_EXIT_KEEPALIVE_MS)
 		}
 
+		const writeResult = async (status: RooCliRunStatus, exitCode: number, error?: Error) => {
+			if (!resultFile) {
+				return
+			}
+
+			try {
+				const patch = workspaceSnapshot
+					? await getWorkspacePatch(effectiveWorkspacePath, workspaceSnapshot)
+					: undefined
+
+				await writeRunResult(
+					resultFile,
+					buildRunResult({
+						status,
+						exitCode,
+						taskId,
+						mode: host.client.getCurrentMode() ?? effectiveMode,
+						messages: host.client.getMessages(),
+						patch,
+						error,
+					}),
+				)
+			} catch (writeError) {
+				emitRuntimeError(normalizeError(writeError), "result file")
+			}
+		}
+
 		const disposeHost = async () => {
 			if (hostDisposed) {
 				return
@@ -591,6 +663,7 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 				console.log(`\n[CLI] Received ${signal}, shutting down...`)
 			}
 
+			await writeResult(signal === "SIGINT" || signal === "SIGTERM" ? "cancelled" : "failed", exitCode)
 			await disposeHost()
 			if (jsonEmitter) {
 				await jsonEmitter.flush()
@@ -641,10 +714,11 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 				if (isResumeRequested) {
 					await host.resumeTask(resolvedResumeSessionId!)
 				} else {
-					await host.runTask(prompt!, requestedCreateSessionId)
+					await host.runTask(prompt!, taskId)
 				}
 			}
 
+			await writeResult("completed", rooCliRunExitCodes.completed)
 			await disposeHost()
 			if (jsonEmitter) {
 				await jsonEmitter.flush()
@@ -661,7 +735,10 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 			process.off("unhandledRejection", onUnhandledRejection)
 			process.exit(0)
 		} catch (error) {
+			const status = getRunErrorStatus(error)
+
 			emitRuntimeError(normalizeError(error))
+			await writeResult(status, rooCliRunExitCodes[status], normalizeError(error))
 			await disposeHost()
 			if (jsonEmitter) {
 				await jsonEmitter.flush()
@@ -676,7 +753,7 @@ export async function run(promptArg: string | undefined, flagOptions: FlagOption
 			process.off("SIGTERM", onSigterm)
 			process.off("uncaughtException", onUncaughtException)
 			process.off("unhandledRejection", onUnhandledRejection)
-			process.exit(1)
+			process.exit(rooCliRunExitCodes[status])
 		}
 	}
 }
@@ -44,6 +44,15 @@ program
 	.option("-e, --extension <path>", "Path to the extension bundle directory")
 	.option("-d, --debug", "Enable debug output (includes detailed debug information)", false)
 	.option("-a, --require-approval", "Require manual approval for actions", false)
+	.option(
+		"--auto-approval-profile <path>",
+		"JSON file of the actions to approve (alwaysAllowWrite, allowedCommands, etc.); other actions are denied",
+	)
+	.option("--max-cost <dollars>", "Stop the task once its cost reaches this amount", Number.parseFloat)
+	.option(
+		"--result-file <path>",
+		"Write the run's status, transcript, cost and changes (as a git patch) to a JSON file (requires --print)",
+	)
 	.option("-k, --api-key <key>", "API key for the LLM provider")
 	.option("--provider <provider>", "API provider (roo, anthropic, openai, openrouter, etc.)")
 	.option("-m, --model <model>", "Model to use", DEFAULT_FLAGS.model)
@@ -0,0 +1,64 @@
+// pnpm --filter @roo-code/cli test src/lib/utils/__tests__/auto-approval.test.ts
+
+import fs from "fs"
+import os from "os"
+import path from "path"
+
+import { getAutoApprovalProfileSettings, loadAutoApprovalProfile } from "../auto-approval.js"
+
+describe("loadAutoApprovalProfile", () => {
+	let tempDir: string
+	let profilePath: string
+
+	beforeEach(() => {
+		tempDir = fs.mkdtempSync(path.join(os.tmpdir(), "cli-auto-approval-"))
+		profilePath = path.join(tempDir, "profile.json")
+	})
+
+	afterEach(() => {
+		fs.rmSync(tempDir, { recursive: true, force: true })
+	})
+
+	it("reads a profile", async () => {
+		fs.writeFileSync(profilePath, JSON.stringify({ alwaysAllowReadOnly: true, allowedCommands: ["npm test"] }))
+
+		await expect(loadAutoApprovalProfile(profilePath)).resolves.toEqual({
+			alwaysAllowReadOnly: true,
+			allowedCommands: ["npm test"],
+		})
+	})
+
+	it("rejects a missing file", async () => {
+		await expect(loadAutoApprovalProfile(profilePath)).rejects.toThrow("auto-approval profile does not exist")
+	})
+
+	it("rejects invalid JSON", async () => {
+		fs.writeFileSync(profilePath, "{")
+
+		await expect(loadAutoApprovalProfile(profilePath)).rejects.toThrow("is not valid JSON")
+	})
+
+	it("rejects unknown or invalid settings", async () => {
+		fs.writeFileSync(profilePath, JSON.stringify({ alwaysAllowWrite: "yes", apiKey: "secret" }))
+
+		await expect(loadAutoApprovalProfile(profilePath)).rejects.toThrow(
+			/invalid auto-approval profile .*alwaysAllowWrite/,
+		)
+	})
+})
+
+describe("getAutoApprovalProfileSettings", () => {
+	it("only allows the actions of the profile and denies the others", () => {
+		const settings = getAutoApprovalProfileSettings({ alwaysAllowWrite: true, deniedCommands: ["git push"] })
+
+		expect(settings).toMatchObject({
+			autoApprovalEnabled: true,
+			alwaysAllowReadOnly: false,
+			alwaysAllowWrite: true,
+			alwaysAllowExecute: false,
+			allowedCommands: [],
+			deniedCommands: ["git push"],
+			denyUnapprovedActions: true,
+		})
+	})
+})
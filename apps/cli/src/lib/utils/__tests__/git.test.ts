@@ -0,0 +1,59 @@
+// pnpm --filter @roo-code/cli test src/lib/utils/__tests__/git.test.ts
+
+import { execFileSync } from "child_process"
+import fs from "fs"
+import os from "os"
+import path from "path"
+
+import { getWorkspacePatch, snapshotWorkspace } from "../git.js"
+
+describe("workspace snapshots", () => {
+	let tempDir: string
+
+	const git = (...args: string[]) => execFileSync("git", args, { cwd: tempDir, encoding: "utf8" })
+
+	beforeEach(() => {
+		tempDir = fs.mkdtempSync(path.join(os.tmpdir(), "cli-git-"))
+		git("init", "-q")
+		git("config", "user.email", "test@example.com")
+		git("config", "user.name", "Test")
+		fs.writeFileSync(path.join(tempDir, "a.txt"), "one\n")
+		git("add", "a.txt")
+		git("commit", "-q", "-m", "initial")
+	})
+
+	afterEach(() => {
+		fs.rmSync(tempDir, { recursive: true, force: true })
+	})
+
+	it("returns the changes made since the snapshot, including untracked files", async () => {
+		// Uncommitted changes from before the snapshot aren't part of the patch.
+		fs.writeFileSync(path.join(tempDir, "a.txt"), "two\n")
+		const snapshot = await snapshotWorkspace(tempDir)
+
+		fs.writeFileSync(path.join(tempDir, "a.txt"), "three\n")
+		fs.writeFileSync(path.join(tempDir, "b.txt"), "new\n")
+
+		const patch = await getWorkspacePatch(tempDir, snapshot!)
+
+		expect(patch).toContain("-two\n+three")
+		expect(patch).toContain("+++ b/b.txt")
+	})
+
+	it("doesn't touch the repository's index", async () => {
+		fs.writeFileSync(path.join(tempDir, "b.txt"), "new\n")
+		await snapshotWorkspace(tempDir)
+
+		expect(git("status", "--porcelain")).toBe("?? b.txt\n")
+	})
+
+	it("returns undefined outside of a git repository", async () => {
+		const outsideDir = fs.mkdtempSync(path.join(os.tmpdir(), "cli-no-git-"))
+
+		try {
+			await expect(snapshotWorkspace(outsideDir)).resolves.toBeUndefined()
+		} finally {
+			fs.rmSync(outsideDir, { recursive: true, force: true })
+		}
+	})
+})
@@ -0,0 +1,62 @@
+import fs from "fs/promises"
+
+import { type AutoApprovalProfile, type RooCodeSettings, autoApprovalProfileSchema } from "@roo-code/types"
+
+/**
+ * Reads an auto-approval profile from a JSON file, such as:
+ *
+ * ```json
+ * { "alwaysAllowReadOnly": true, "alwaysAllowWrite": true, "allowedCommands": ["npm test"] }
+ * ```
+ */
+export async function loadAutoApprovalProfile(filePath: string): Promise<AutoApprovalProfile> {
+	let content: string
+
+	try {
+		content = await fs.readFile(filePath, "utf-8")
+	} catch {
+		throw new Error(`auto-approval profile does not exist: ${filePath}`)
+	}
+
+	let json: unknown
+
+	try {
+		json = JSON.parse(content)
+	} catch {
+		throw new Error(`auto-approval profile is not valid JSON: ${filePath}`)
+	}
+
+	const result = autoApprovalProfileSchema.safeParse(json)
+
+	if (!result.success) {
+		const issues = result.error.issues.map((issue) => `${issue.path.join(".") || "(root)"}: ${issue.message}`)
+		throw new Error(`invalid auto-approval profile ${filePath}: ${issues.join("; ")}`)
+	}
+
+	return result.data
+}
+
+/**
+ * The auto-approval settings of a run with a profile. Only the actions the
+ * profile allows run; the extension denies the others, since nobody is there
+ * to approve them.
+ */
+export function getAutoApprovalProfileSettings(profile: AutoApprovalProfile): RooCodeSettings {
+	return {
+		autoApprovalEnabled: true,
+		alwaysAllowReadOnly: profile.alwaysAllowReadOnly ?? false,
+		alwaysAllowReadOnlyOutsideWorkspace: profile.alwaysAllowReadOnlyOutsideWorkspace ?? false,
+		alwaysAllowWrite: profile.alwaysAllowWrite ?? false,
+		alwaysAllowWriteOutsideWorkspace: profile.alwaysAllowWriteOutsideWorkspace ?? false,
+		alwaysAllowWriteProtected: profile.alwaysAllowWriteProtected ?? false,
+		allowedWritePaths: profile.allowedWritePaths ?? [],
+		deniedWritePaths: profile.deniedWritePaths ?? [],
+		alwaysAllowMcp: profile.alwaysAllowMcp ?? false,
+		alwaysAllowModeSwitch: profile.alwaysAllowModeSwitch ?? false,
+		alwaysAllowSubtasks: profile.alwaysAllowSubtasks ?? false,
+		alwaysAllowExecute: profile.alwaysAllowExecute ?? false,
+		allowedCommands: profile.allowedCommands ?? [],
+		deniedCommands: profile.deniedCommands ?? [],
+		denyUnapprovedActions: true,
+	}
+}
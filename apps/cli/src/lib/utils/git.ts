@@ -0,0 +1,56 @@
+import { execFile } from "child_process"
+import fs from "fs/promises"
+import os from "os"
+import path from "path"
+import { promisify } from "util"
+
+const execFileAsync = promisify(execFile)
+
+// Patches of large generated files can be big.
+const MAX_BUFFER = 64 * 1024 * 1024
+
+async function git(cwd: string, args: string[], env?: NodeJS.ProcessEnv): Promise<string> {
+	const { stdout } = await execFileAsync("git", args, {
+		cwd,
+		encoding: "utf8",
+		maxBuffer: MAX_BUFFER,
+		env: env ? { ...process.env, ...env } : undefined,
+	})
+
+	return stdout
+}
+
+/**
+ * Records the state of the workspace, including uncommitted and untracked
+ * files, as a git tree, without touching the repository's index or working
+ * tree.
+ *
+ * @returns the tree's hash, or undefined if the workspace isn't in a git repository
+ */
+export async function snapshotWorkspace(cwd: string): Promise<string | undefined> {
+	const tmpDir = await fs.mkdtemp(path.join(os.tmpdir(), "roo-cli-snapshot-"))
+	const env = { GIT_INDEX_FILE: path.join(tmpDir, "index") }
+
+	try {
+		await git(cwd, ["add", "--all", "--", "."], env)
+		return (await git(cwd, ["write-tree"], env)).trim()
+	} catch {
+		return undefined
+	} finally {
+		await fs.rm(tmpDir, { recursive: true, force: true })
+	}
+}
+
+/**
+ * The changes made to the workspace since `snapshotWorkspace()` returned
+ * `fromTree`, as a patch `git apply` accepts.
+ */
+export async function getWorkspacePatch(cwd: string, fromTree: string): Promise<string | undefined> {
+	const toTree = await snapshotWorkspace(cwd)
+
+	if (!toTree) {
+		return undefined
+	}
+
+	return git(cwd, ["diff", "--binary", "--no-color", "--no-ext-diff", fromTree, toTree])
+}
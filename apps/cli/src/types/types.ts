@@ -30,6 +30,9 @@ export type FlagOptions = {
 	extension?: string
 	debug: boolean
 	requireApproval: boolean
+	autoApprovalProfile?: string
+	maxCost?: number
+	resultFile?: string
 	exitOnError: boolean
 	apiKey?: string
 	provider?: SupportedProvider
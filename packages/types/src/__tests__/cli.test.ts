@@ -2,6 +2,7 @@ import {
 	rooCliControlEventSchema,
 	rooCliFinalOutputSchema,
 	rooCliInputCommandSchema,
+	rooCliRunResultSchema,
 	rooCliStreamEventSchema,
 } from "../cli.js"
 
@@ -101,4 +102,32 @@ describe("CLI types", () => {
 			expect(result.success).toBe(true)
 		})
 	})
+
+	describe("rooCliRunResultSchema", () => {
+		it("validates a run result", () => {
+			const result = rooCliRunResultSchema.safeParse({
+				status: "budget_exceeded",
+				exitCode: 2,
+				taskId: "018f7fc8-7c96-7f7c-98aa-2ec4ff7f6d87",
+				mode: "code",
+				cost: { totalCost: 1.02 },
+				patch: "diff --git a/a.ts b/a.ts\n",
+				transcript: [{ ts: 1, type: "say", say: "text", text: "hello" }],
+			})
+
+			expect(result.success).toBe(true)
+		})
+
+		it("rejects an unknown status", () => {
+			const result = rooCliRunResultSchema.safeParse({
+				status: "timed_out",
+				exitCode: 1,
+				mode: "code",
+				cost: {},
+				transcript: [],
+			})
+
+			expect(result.success).toBe(false)
+		})
+	})
 })
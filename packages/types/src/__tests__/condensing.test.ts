@@ -0,0 +1,46 @@
+import { describe, it, expect } from "vitest"
+import { DEFAULT_CONDENSING_STRATEGY_ID, getAllCondensingStrategies, getCondensingStrategy } from "../condensing.js"
+
+describe("condensing", () => {
+	const custom = { id: "custom-1", name: "Keep messages", keepRecentUserMessages: 5 }
+
+	describe("getCondensingStrategy", () => {
+		it("should use the default strategy when nothing is selected", () => {
+			expect(getCondensingStrategy({}, "code").id).toBe(DEFAULT_CONDENSING_STRATEGY_ID)
+		})
+
+		it("should prefer the mode's strategy over the strategy for all modes", () => {
+			const settings = {
+				condensingStrategy: "preserve-edits",
+				modeCondensingStrategies: { architect: "custom-1" },
+				customCondensingStrategies: [custom],
+			}
+
+			expect(getCondensingStrategy(settings, "architect")).toEqual(custom)
+			expect(getCondensingStrategy(settings, "code").id).toBe("preserve-edits")
+		})
+
+		it("should skip strategies that no longer exist", () => {
+			const settings = { condensingStrategy: "preserve-edits", modeCondensingStrategies: { code: "deleted" } }
+
+			expect(getCondensingStrategy(settings, "code").id).toBe("preserve-edits")
+			expect(getCondensingStrategy({ condensingStrategy: "deleted" }).id).toBe(DEFAULT_CONDENSING_STRATEGY_ID)
+		})
+	})
+
+	describe("getAllCondensingStrategies", () => {
+		it("should not let custom strategies replace built-in ones", () => {
+			const strategies = getAllCondensingStrategies([
+				{ id: DEFAULT_CONDENSING_STRATEGY_ID, name: "Mine" },
+				custom,
+			])
+
+			expect(strategies.map(({ id }) => id)).toEqual([
+				DEFAULT_CONDENSING_STRATEGY_ID,
+				"preserve-edits",
+				"custom-1",
+			])
+			expect(strategies[0].name).toBe("Default")
+		})
+	})
+})
@@ -5,6 +5,7 @@ import type { RooCodeEvents } from "./events.js"
 import type { RooCodeSettings } from "./global-settings.js"
 import type { ProviderSettingsEntry, ProviderSettings } from "./provider-settings.js"
 import type { IpcMessage, IpcServerEvents } from "./ipc.js"
+import type { CodebaseIndexStats, CodebaseReindexOptions } from "./codebase-index.js"
 
 export type RooCodeAPIEvents = RooCodeEvents
 
@@ -133,6 +134,23 @@ export interface RooCodeAPI extends EventEmitter<RooCodeAPIEvents> {
 	 * @throws Error if the profile does not exist
 	 */
 	setActiveProfile(name: string): Promise<string | undefined>
+	/**
+	 * Re-indexes the codebase of one or all workspace folders
+	 * @param options The folder and paths to re-index, and whether to rebuild the index from scratch
+	 * @returns Resolves once indexing has finished
+	 * @throws Error if code indexing is disabled or not configured, or indexing fails
+	 */
+	reindexCodebase(options?: CodebaseReindexOptions): Promise<void>
+	/**
+	 * Returns the indexing progress and size of each workspace folder's codebase index
+	 * @returns One entry per workspace folder with a code index
+	 */
+	getCodebaseIndexStats(): CodebaseIndexStats[]
+	/**
+	 * Clears the codebase index of one or all workspace folders
+	 * @param workspacePath The folder to clear; every folder when omitted
+	 */
+	clearCodebaseIndex(workspacePath?: string): Promise<void>
 }
 
 export interface RooCodeIpcServer extends EventEmitter<IpcServerEvents> {
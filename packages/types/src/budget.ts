@@ -0,0 +1,66 @@
+import { z } from "zod"
+
+/**
+ * BudgetLimits
+ *
+ * Caps on what a task may spend before it pauses and asks whether to continue.
+ * Unset limits don't apply.
+ */
+
+export const budgetLimitsSchema = z.object({
+	maxCost: z.number().min(0).optional(),
+	maxTokens: z.number().int().min(0).optional(),
+	maxRequests: z.number().int().min(0).optional(),
+})
+
+export type BudgetLimits = z.infer<typeof budgetLimitsSchema>
+
+export type BudgetMetric = "cost" | "tokens" | "requests"
+
+/**
+ * Whether a limit comes from the budget of every task or from the budget of the
+ * mode the task is running in.
+ */
+export type BudgetScope = "task" | "mode"
+
+export interface BudgetLimit {
+	metric: BudgetMetric
+	scope: BudgetScope
+	limit: number
+}
+
+export type BudgetUsage = Record<BudgetMetric, number>
+
+const budgetLimitKeys: Record<BudgetMetric, keyof BudgetLimits> = {
+	cost: "maxCost",
+	tokens: "maxTokens",
+	requests: "maxRequests",
+}
+
+/**
+ * Returns the limits that apply to a task running in `mode`. When both the task
+ * and the mode budget cap a metric, the lower limit wins.
+ */
+export function getBudgetLimits(
+	settings: { taskBudget?: BudgetLimits; modeBudgets?: Record<string, BudgetLimits> },
+	mode?: string,
+): BudgetLimit[] {
+	const modeBudget = mode ? settings.modeBudgets?.[mode] : undefined
+
+	return (Object.keys(budgetLimitKeys) as BudgetMetric[]).flatMap((metric) => {
+		const key = budgetLimitKeys[metric]
+		const candidates: BudgetLimit[] = []
+
+		const taskLimit = settings.taskBudget?.[key]
+		if (taskLimit) {
+			candidates.push({ metric, scope: "task", limit: taskLimit })
+		}
+
+		const modeLimit = modeBudget?.[key]
+		if (modeLimit) {
+			candidates.push({ metric, scope: "mode", limit: modeLimit })
+		}
+
+		return candidates.length > 0 ? [candidates.reduce((a, b) => (b.limit < a.limit ? b : a))] : []
+	})
+}
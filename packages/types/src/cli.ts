@@ -1,6 +1,7 @@
 import { z } from "zod"
 
 import { rooCodeSettingsSchema } from "./global-settings.js"
+import { clineMessageSchema } from "./message.js"
 
 /**
  * Roo CLI stdin commands
@@ -180,3 +181,41 @@ export const rooCliFinalOutputSchema = z.object({
 })
 
 export type RooCliFinalOutput = z.infer<typeof rooCliFinalOutputSchema>
+
+/**
+ * Roo CLI run result
+ *
+ * Written to `--result-file` when a task that was run non-interactively ends,
+ * for scripts and CI jobs.
+ */
+
+export const rooCliRunStatuses = ["completed", "failed", "budget_exceeded", "cancelled"] as const
+
+export const rooCliRunStatusSchema = z.enum(rooCliRunStatuses)
+
+export type RooCliRunStatus = z.infer<typeof rooCliRunStatusSchema>
+
+/**
+ * The process exit code of each run status.
+ */
+export const rooCliRunExitCodes: Record<RooCliRunStatus, number> = {
+	completed: 0,
+	failed: 1,
+	budget_exceeded: 2,
+	cancelled: 130,
+}
+
+export const rooCliRunResultSchema = z.object({
+	status: rooCliRunStatusSchema,
+	exitCode: z.number(),
+	taskId: z.string().optional(),
+	mode: z.string(),
+	/** The completion result, or the error the run failed with */
+	content: z.string().optional(),
+	cost: rooCliCostSchema,
+	/** The changes made to the workspace, as a git patch; unset outside of a git repository */
+	patch: z.string().optional(),
+	transcript: z.array(clineMessageSchema),
+})
+
+export type RooCliRunResult = z.infer<typeof rooCliRunResultSchema>
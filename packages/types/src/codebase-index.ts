@@ -12,8 +12,43 @@ export const CODEBASE_INDEX_DEFAULTS = {
 	MAX_SEARCH_SCORE: 1,
 	DEFAULT_SEARCH_MIN_SCORE: 0.4,
 	SEARCH_SCORE_STEP: 0.05,
+	MIN_HYBRID_SEARCH_WEIGHT: 0,
+	MAX_HYBRID_SEARCH_WEIGHT: 1,
+	DEFAULT_HYBRID_SEARCH_WEIGHT: 0.3,
+	HYBRID_SEARCH_WEIGHT_STEP: 0.05,
+	MIN_RERANK_CANDIDATES: 10,
+	MAX_RERANK_CANDIDATES: 200,
+	DEFAULT_RERANK_CANDIDATES: 100,
+	RERANK_CANDIDATES_STEP: 10,
 } as const
 
+/**
+ * RerankerProvider
+ */
+
+export const rerankerProviders = ["cohere", "voyage"] as const
+
+export const rerankerProviderSchema = z.enum(rerankerProviders)
+
+export type RerankerProvider = z.infer<typeof rerankerProviderSchema>
+
+export const defaultRerankerModels: Record<RerankerProvider, string> = {
+	cohere: "rerank-v3.5",
+	voyage: "rerank-2",
+}
+
+/**
+ * CodebaseIndexDocumentType
+ *
+ * Non-code file types that can be included in the index alongside source code.
+ */
+
+export const codebaseIndexDocumentTypes = ["markdown", "config"] as const
+
+export const codebaseIndexDocumentTypeSchema = z.enum(codebaseIndexDocumentTypes)
+
+export type CodebaseIndexDocumentType = z.infer<typeof codebaseIndexDocumentTypeSchema>
+
 /**
  * CodebaseIndexConfig
  */
@@ -42,6 +77,22 @@ export const codebaseIndexConfigSchema = z.object({
 		.min(CODEBASE_INDEX_DEFAULTS.MIN_SEARCH_RESULTS)
 		.max(CODEBASE_INDEX_DEFAULTS.MAX_SEARCH_RESULTS)
 		.optional(),
+	// Share of keyword (BM25) ranking in hybrid search; 0 disables keyword matching
+	codebaseIndexHybridSearchWeight: z
+		.number()
+		.min(CODEBASE_INDEX_DEFAULTS.MIN_HYBRID_SEARCH_WEIGHT)
+		.max(CODEBASE_INDEX_DEFAULTS.MAX_HYBRID_SEARCH_WEIGHT)
+		.optional(),
+	// Optional reranking stage; unset disables reranking
+	codebaseIndexRerankerProvider: rerankerProviderSchema.optional(),
+	codebaseIndexRerankerModelId: z.string().optional(),
+	codebaseIndexRerankerCandidates: z
+		.number()
+		.min(CODEBASE_INDEX_DEFAULTS.MIN_RERANK_CANDIDATES)
+		.max(CODEBASE_INDEX_DEFAULTS.MAX_RERANK_CANDIDATES)
+		.optional(),
+	// Documentation and config file types to index; unset indexes all of them
+	codebaseIndexDocumentTypes: z.array(codebaseIndexDocumentTypeSchema).optional(),
 	// OpenAI Compatible specific fields
 	codebaseIndexOpenAiCompatibleBaseUrl: z.string().optional(),
 	codebaseIndexOpenAiCompatibleModelDimension: z.number().optional(),
@@ -85,6 +136,39 @@ export const codebaseIndexProviderSchema = z.object({
 	codebaseIndexMistralApiKey: z.string().optional(),
 	codebaseIndexVercelAiGatewayApiKey: z.string().optional(),
 	codebaseIndexOpenRouterApiKey: z.string().optional(),
+	codebaseIndexCohereApiKey: z.string().optional(),
+	codebaseIndexVoyageApiKey: z.string().optional(),
 })
 
 export type CodebaseIndexProvider = z.infer<typeof codebaseIndexProviderSchema>
+
+/**
+ * CodebaseIndexStats
+ *
+ * Progress and size of one workspace folder's index, as reported by the index control API and commands.
+ */
+
+export interface CodebaseIndexStats {
+	workspacePath: string
+	systemStatus: string
+	message: string
+	processedItems: number
+	totalItems: number
+	currentItemUnit: string
+	filesIndexed: number
+	chunksIndexed: number
+	lastError?: { message: string; timestamp: number }
+}
+
+/**
+ * CodebaseReindexOptions
+ */
+
+export interface CodebaseReindexOptions {
+	/** Workspace folder to re-index; every enabled folder when omitted */
+	workspacePath?: string
+	/** Files or directories to re-index, absolute or relative to the workspace folder */
+	paths?: string[]
+	/** Clears the index before rebuilding it instead of only re-checking changed files */
+	full?: boolean
+}
@@ -0,0 +1,68 @@
+import { z } from "zod"
+
+/**
+ * CondensingStrategy
+ *
+ * Rules for what intelligent context condensing summarizes and what it carries
+ * over verbatim. Strategies are selected globally or per mode.
+ */
+
+export const condensingStrategySchema = z.object({
+	id: z.string().min(1),
+	name: z.string().min(1),
+	// Shorten older tool results before summarizing, so the summary isn't spent on command output and file contents.
+	condenseOldToolResults: z.boolean().optional(),
+	// Number of most recent user messages to keep verbatim next to the summary.
+	keepRecentUserMessages: z.number().int().min(0).optional(),
+	// Keep the paths and diffs of the file edits made during the task verbatim.
+	preserveFileEdits: z.boolean().optional(),
+	// Replaces the condensing prompt when this strategy is used.
+	prompt: z.string().optional(),
+})
+
+export type CondensingStrategy = z.infer<typeof condensingStrategySchema>
+
+export const DEFAULT_CONDENSING_STRATEGY_ID = "default"
+
+export const builtInCondensingStrategies: CondensingStrategy[] = [
+	{ id: DEFAULT_CONDENSING_STRATEGY_ID, name: "Default" },
+	{
+		id: "preserve-edits",
+		name: "Preserve edits",
+		condenseOldToolResults: true,
+		keepRecentUserMessages: 3,
+		preserveFileEdits: true,
+	},
+]
+
+export function getAllCondensingStrategies(customStrategies?: CondensingStrategy[]): CondensingStrategy[] {
+	const builtInIds = new Set(builtInCondensingStrategies.map((strategy) => strategy.id))
+	return [...builtInCondensingStrategies, ...(customStrategies ?? []).filter(({ id }) => !builtInIds.has(id))]
+}
+
+/**
+ * Returns the strategy used to condense a task running in `mode`: the mode's
+ * strategy, else the strategy selected for all modes, else the default one.
+ * Strategies that no longer exist are skipped.
+ */
+export function getCondensingStrategy(
+	settings: {
+		condensingStrategy?: string
+		modeCondensingStrategies?: Record<string, string>
+		customCondensingStrategies?: CondensingStrategy[]
+	},
+	mode?: string,
+): CondensingStrategy {
+	const strategies = getAllCondensingStrategies(settings.customCondensingStrategies)
+	const candidates = [mode ? settings.modeCondensingStrategies?.[mode] : undefined, settings.condensingStrategy]
+
+	for (const id of candidates) {
+		const strategy = id ? strategies.find((candidate) => candidate.id === id) : undefined
+
+		if (strategy) {
+			return strategy
+		}
+	}
+
+	return builtInCondensingStrategies[0]
+}
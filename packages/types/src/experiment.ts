@@ -6,7 +6,16 @@ import type { Keys, Equals, AssertEqual } from "./type-fu.js"
  * ExperimentId
  */
 
-export const experimentIds = ["preventFocusDisruption", "imageGeneration", "runSlashCommand", "customTools"] as const
+export const experimentIds = [
+	"preventFocusDisruption",
+	"imageGeneration",
+	"runSlashCommand",
+	"customTools",
+	"dryRunEdits",
+	"fastApply",
+	"parallelSubtasks",
+	"scheduledTasks",
+] as const
 
 export const experimentIdsSchema = z.enum(experimentIds)
 
@@ -21,6 +30,10 @@ export const experimentsSchema = z.object({
 	imageGeneration: z.boolean().optional(),
 	runSlashCommand: z.boolean().optional(),
 	customTools: z.boolean().optional(),
+	dryRunEdits: z.boolean().optional(),
+	fastApply: z.boolean().optional(),
+	parallelSubtasks: z.boolean().optional(),
+	scheduledTasks: z.boolean().optional(),
 })
 
 export type Experiments = z.infer<typeof experimentsSchema>
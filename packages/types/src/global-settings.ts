@@ -8,6 +8,8 @@ import {
 	providerSettingsSchema,
 } from "./provider-settings.js"
 import { historyItemSchema } from "./history.js"
+import { budgetLimitsSchema } from "./budget.js"
+import { condensingStrategySchema } from "./condensing.js"
 import { codebaseIndexModelsSchema, codebaseIndexConfigSchema } from "./codebase-index.js"
 import { experimentsSchema } from "./experiment.js"
 import { telemetrySettingsSchema } from "./telemetry.js"
@@ -15,6 +17,7 @@ import { modeConfigSchema } from "./mode.js"
 import { customModePromptsSchema, customSupportPromptsSchema } from "./mode.js"
 import { toolNamesSchema } from "./tool.js"
 import { languagesSchema } from "./vscode.js"
+import { ttsContentSchema } from "./voice.js"
 
 /**
  * Default delay in milliseconds after writes to allow diagnostics to detect potential problems.
@@ -23,6 +26,18 @@ import { languagesSchema } from "./vscode.js"
  */
 export const DEFAULT_WRITE_DELAY_MS = 1000
 
+/**
+ * Default token budget for a single read_file result. Files that exceed it are
+ * returned as an outline with chunks the model can read on demand.
+ * 0 disables chunked reads.
+ */
+export const DEFAULT_READ_FILE_TOKEN_BUDGET = 25_000
+
+/**
+ * Default number of subtasks run_parallel_tasks runs at the same time.
+ */
+export const DEFAULT_MAX_PARALLEL_SUBTASKS = 3
+
 /**
  * Terminal output preview size options for persisted command output.
  *
@@ -93,6 +108,12 @@ export const globalSettingsSchema = z.object({
 	openRouterImageApiKey: z.string().optional(),
 	openRouterImageGenerationSelectedModel: z.string().optional(),
 
+	// Fast-apply model for the fast_edit tool (experimental)
+	fastApplyApiConfigId: z.string().optional(),
+
+	// Parallel subtasks for the run_parallel_tasks tool (experimental)
+	maxParallelSubtasks: z.number().int().min(1).optional(),
+
 	customCondensingPrompt: z.string().optional(),
 
 	autoApprovalEnabled: z.boolean().optional(),
@@ -101,6 +122,8 @@ export const globalSettingsSchema = z.object({
 	alwaysAllowWrite: z.boolean().optional(),
 	alwaysAllowWriteOutsideWorkspace: z.boolean().optional(),
 	alwaysAllowWriteProtected: z.boolean().optional(),
+	allowedWritePaths: z.array(z.string()).optional(),
+	deniedWritePaths: z.array(z.string()).optional(),
 	writeDelayMs: z.number().min(0).optional(),
 	requestDelaySeconds: z.number().optional(),
 	alwaysAllowMcp: z.boolean().optional(),
@@ -109,6 +132,11 @@ export const globalSettingsSchema = z.object({
 	alwaysAllowExecute: z.boolean().optional(),
 	alwaysAllowFollowupQuestions: z.boolean().optional(),
 	followupAutoApproveTimeoutMs: z.number().optional(),
+	/**
+	 * Deny the actions that aren't auto-approved instead of waiting for the user,
+	 * for runs nobody is watching, such as the CLI in CI
+	 */
+	denyUnapprovedActions: z.boolean().optional(),
 	allowedCommands: z.array(z.string()).optional(),
 	deniedCommands: z.array(z.string()).optional(),
 	commandExecutionTimeout: z.number().optional(),
@@ -116,8 +144,19 @@ export const globalSettingsSchema = z.object({
 	preventCompletionWithOpenTodos: z.boolean().optional(),
 	allowedMaxRequests: z.number().nullish(),
 	allowedMaxCost: z.number().nullish(),
+	/**
+	 * Limits every task pauses at, and limits for tasks running in a given mode
+	 */
+	taskBudget: budgetLimitsSchema.optional(),
+	modeBudgets: z.record(z.string(), budgetLimitsSchema).optional(),
 	autoCondenseContext: z.boolean().optional(),
 	autoCondenseContextPercent: z.number().optional(),
+	/**
+	 * The condensing strategy for all modes, overrides per mode, and user-defined strategies
+	 */
+	condensingStrategy: z.string().optional(),
+	modeCondensingStrategies: z.record(z.string(), z.string()).optional(),
+	customCondensingStrategies: z.array(condensingStrategySchema).optional(),
 
 	/**
 	 * Whether to include current time in the environment details
@@ -154,9 +193,19 @@ export const globalSettingsSchema = z.object({
 		.min(MIN_CHECKPOINT_TIMEOUT_SECONDS)
 		.max(MAX_CHECKPOINT_TIMEOUT_SECONDS)
 		.optional(),
+	/**
+	 * Checkpoint retention: tasks inactive for longer lose their checkpoints,
+	 * the least recently active tasks lose theirs while all checkpoints take
+	 * more space, and each task keeps only its most recent checkpoints.
+	 * Unset or 0 means no limit.
+	 */
+	checkpointMaxAgeDays: z.number().int().min(0).optional(),
+	checkpointMaxStorageMb: z.number().int().min(0).optional(),
+	checkpointMaxPerTask: z.number().int().min(0).optional(),
 
 	ttsEnabled: z.boolean().optional(),
 	ttsSpeed: z.number().optional(),
+	ttsContent: ttsContentSchema.optional(),
 	soundEnabled: z.boolean().optional(),
 	soundVolume: z.number().optional(),
 
@@ -166,6 +215,7 @@ export const globalSettingsSchema = z.object({
 	enableSubfolderRules: z.boolean().optional(),
 	maxImageFileSize: z.number().optional(),
 	maxTotalImageSize: z.number().optional(),
+	readFileTokenBudget: z.number().min(0).optional(),
 
 	terminalOutputPreviewSize: z.enum(["small", "medium", "large"]).optional(),
 	terminalShellIntegrationTimeout: z.number().optional(),
@@ -191,6 +241,11 @@ export const globalSettingsSchema = z.object({
 	telemetrySetting: telemetrySettingsSchema.optional(),
 
 	mcpEnabled: z.boolean().optional(),
+	/**
+	 * Whether to tell the task when an MCP resource it read changes on its
+	 * server. Defaults to true.
+	 */
+	mcpResourceUpdateNotifications: z.boolean().optional(),
 
 	mode: z.string().optional(),
 	modeApiConfigs: z.record(z.string(), z.string()).optional(),
@@ -201,6 +256,13 @@ export const globalSettingsSchema = z.object({
 	includeTaskHistoryInEnhance: z.boolean().optional(),
 	historyPreviewCollapsed: z.boolean().optional(),
 	reasoningBlockCollapsed: z.boolean().optional(),
+	/**
+	 * How diffs are shown in the chat.
+	 * - "inline": removed lines above the lines that replace them (default)
+	 * - "sideBySide": the old and new version next to each other
+	 * @default "inline"
+	 */
+	diffViewLayout: z.enum(["inline", "sideBySide"]).optional(),
 	/**
 	 * Controls the keyboard behavior for sending messages in the chat input.
 	 * - "send": Enter sends message, Shift+Enter creates newline (default)
@@ -209,11 +271,17 @@ export const globalSettingsSchema = z.object({
 	 */
 	enterBehavior: z.enum(["send", "newline"]).optional(),
 	profileThresholds: z.record(z.string(), z.number()).optional(),
+	/**
+	 * Ordered ids of the API configuration profiles a task switches to when its
+	 * provider is rate limited, fails with a server error or times out
+	 */
+	failoverApiConfigIds: z.array(z.string()).optional(),
 	hasOpenedModeSelector: z.boolean().optional(),
 	lastModeExportPath: z.string().optional(),
 	lastModeImportPath: z.string().optional(),
 	lastSettingsExportPath: z.string().optional(),
 	lastTaskExportPath: z.string().optional(),
+	lastUsageExportPath: z.string().optional(),
 	lastImageSavePath: z.string().optional(),
 
 	/**
@@ -238,6 +306,34 @@ export type GlobalSettings = z.infer<typeof globalSettingsSchema>
 
 export const GLOBAL_SETTINGS_KEYS = globalSettingsSchema.keyof().options
 
+/**
+ * AutoApprovalProfile
+ *
+ * The auto-approval settings for a task that runs without the user, such as a
+ * scheduled task. A profile replaces the user's auto-approval settings: the
+ * actions it doesn't allow need approval, which nobody is there to give.
+ */
+
+export const autoApprovalProfileSchema = globalSettingsSchema
+	.pick({
+		alwaysAllowReadOnly: true,
+		alwaysAllowReadOnlyOutsideWorkspace: true,
+		alwaysAllowWrite: true,
+		alwaysAllowWriteOutsideWorkspace: true,
+		alwaysAllowWriteProtected: true,
+		allowedWritePaths: true,
+		deniedWritePaths: true,
+		alwaysAllowMcp: true,
+		alwaysAllowModeSwitch: true,
+		alwaysAllowSubtasks: true,
+		alwaysAllowExecute: true,
+		allowedCommands: true,
+		deniedCommands: true,
+	})
+	.strict()
+
+export type AutoApprovalProfile = z.infer<typeof autoApprovalProfileSchema>
+
 /**
  * RooCodeSettings
  */
@@ -258,6 +354,7 @@ export const SECRET_STATE_KEYS = [
 	"awsSessionToken",
 	"openAiApiKey",
 	"ollamaApiKey",
+	"llamaCppApiKey",
 	"geminiApiKey",
 	"openAiNativeApiKey",
 	"deepSeekApiKey",
@@ -275,6 +372,8 @@ export const SECRET_STATE_KEYS = [
 	"codebaseIndexMistralApiKey",
 	"codebaseIndexVercelAiGatewayApiKey",
 	"codebaseIndexOpenRouterApiKey",
+	"codebaseIndexCohereApiKey",
+	"codebaseIndexVoyageApiKey",
 	"sambaNovaApiKey",
 	"zaiApiKey",
 	"fireworksApiKey",
@@ -26,6 +26,20 @@ export const historyItemSchema = z.object({
 	awaitingChildId: z.string().optional(), // Child currently awaited (set when delegated)
 	completedByChildId: z.string().optional(), // Child that completed and resumed this parent
 	completionResultSummary: z.string().optional(), // Summary from completed child
+	pinnedFiles: z.array(z.string()).optional(), // Files kept in the task's context, relative to the workspace
+	tags: z.array(z.string()).optional(), // User-defined labels for finding the task in the history
 })
 
 export type HistoryItem = z.infer<typeof historyItemSchema>
+
+/**
+ * TaskSearchResult
+ *
+ * A task whose conversation matches a full-text history search, with the
+ * excerpt around the first match.
+ */
+
+export interface TaskSearchResult {
+	taskId: string
+	snippet: string
+}
@@ -1,7 +1,9 @@
 export * from "./api.js"
+export * from "./budget.js"
 export * from "./cli.js"
 export * from "./cloud.js"
 export * from "./codebase-index.js"
+export * from "./condensing.js"
 export * from "./context-management.js"
 export * from "./cookie-consent.js"
 export * from "./custom-tool.js"
@@ -19,7 +21,9 @@ export * from "./mcp.js"
 export * from "./message.js"
 export * from "./mode.js"
 export * from "./model.js"
+export * from "./project-config.js"
 export * from "./provider-settings.js"
+export * from "./schedule.js"
 export * from "./task.js"
 export * from "./todo.js"
 export * from "./skills.js"
@@ -28,6 +32,8 @@ export * from "./terminal.js"
 export * from "./tool.js"
 export * from "./tool-params.js"
 export * from "./type-fu.js"
+export * from "./usage.js"
+export * from "./voice.js"
 export * from "./vscode-extension-host.js"
 export * from "./vscode.js"
 export * from "./worktree.js"
@@ -65,6 +65,8 @@ export type McpServer = {
 	source?: "global" | "project"
 	projectPath?: string
 	instructions?: string
+	// Whether the user needs to sign in to the server with OAuth, or has.
+	authStatus?: "required" | "authorized"
 }
 
 export type McpTool = {
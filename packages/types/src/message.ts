@@ -23,6 +23,7 @@ import { z } from "zod"
  * - `mistake_limit_reached`: Too many errors encountered, needs user guidance on how to proceed
  * - `use_mcp_server`: Permission to use Model Context Protocol (MCP) server functionality
  * - `auto_approval_max_req_reached`: Auto-approval limit has been reached, manual approval required
+ * - `budget_limit_reached`: The task or mode budget has been used up, asking whether to continue
  */
 export const clineAsks = [
 	"followup",
@@ -36,6 +37,7 @@ export const clineAsks = [
 	"mistake_limit_reached",
 	"use_mcp_server",
 	"auto_approval_max_req_reached",
+	"budget_limit_reached",
 ] as const
 
 export const clineAskSchema = z.enum(clineAsks)
@@ -53,6 +55,7 @@ export const idleAsks = [
 	"resume_completed_task",
 	"mistake_limit_reached",
 	"auto_approval_max_req_reached",
+	"budget_limit_reached",
 ] as const satisfies readonly ClineAsk[]
 
 export type IdleAsk = (typeof idleAsks)[number]
@@ -122,6 +125,7 @@ export function isNonBlockingAsk(ask: ClineAsk): ask is NonBlockingAsk {
  * - `api_req_retried`: Indicates an API request is being retried after a failure
  * - `api_req_retry_delayed`: Indicates an API request retry has been delayed
  * - `api_req_rate_limit_wait`: Indicates a configured rate-limit wait (not an error)
+ * - `api_req_failover`: Indicates the task switched to the next failover API configuration profile
  * - `api_req_deleted`: Indicates an API request has been deleted/cancelled
  * - `text`: General text message or assistant response
  * - `reasoning`: Assistant's reasoning or thought process (often hidden from user)
@@ -148,6 +152,7 @@ export const clineSays = [
 	"api_req_retried",
 	"api_req_retry_delayed",
 	"api_req_rate_limit_wait",
+	"api_req_failover",
 	"api_req_deleted",
 	"text",
 	"image",
@@ -269,6 +274,10 @@ export const clineMessageSchema = z.object({
 	 */
 	contextTruncation: contextTruncationSchema.optional(),
 	isProtected: z.boolean().optional(),
+	/**
+	 * Pinned messages are kept in the system prompt for the rest of the task.
+	 */
+	isPinned: z.boolean().optional(),
 	apiProtocol: z.union([z.literal("openai"), z.literal("anthropic")]).optional(),
 	isAnswered: z.boolean().optional(),
 })
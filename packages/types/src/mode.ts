@@ -102,16 +102,57 @@ export const modeConfigSchema = z.object({
 	customInstructions: z.string().optional(),
 	groups: groupEntryArraySchema,
 	source: z.enum(["global", "project"]).optional(),
+	// The slug of the mode this mode was derived from, if any.
+	extends: z.string().optional(),
 })
 
 export type ModeConfig = z.infer<typeof modeConfigSchema>
 
+/**
+ * CustomModeDefinition
+ *
+ * A custom mode as written in .roomodes or the global custom modes file. A
+ * mode that extends a built-in or custom mode inherits every field it leaves
+ * out, so only its name and overrides are required.
+ */
+
+export const customModeDefinitionSchema = modeConfigSchema
+	.extend({
+		roleDefinition: z.string().min(1, "Role definition is required").optional(),
+		groups: groupEntryArraySchema.optional(),
+		// Appended to the role definition, e.g. to add to the role of the base mode.
+		appendRoleDefinition: z.string().optional(),
+		// Names of instruction fragments appended to the custom instructions.
+		includeInstructions: z.array(z.string()).optional(),
+	})
+	.superRefine((mode, ctx) => {
+		if (mode.extends) {
+			return
+		}
+
+		if (mode.roleDefinition === undefined) {
+			ctx.addIssue({
+				code: z.ZodIssueCode.custom,
+				path: ["roleDefinition"],
+				message: "Role definition is required",
+			})
+		}
+
+		if (mode.groups === undefined) {
+			ctx.addIssue({ code: z.ZodIssueCode.custom, path: ["groups"], message: "Groups are required" })
+		}
+	})
+
+export type CustomModeDefinition = z.infer<typeof customModeDefinitionSchema>
+
 /**
  * CustomModesSettings
  */
 
 export const customModesSettingsSchema = z.object({
-	customModes: z.array(modeConfigSchema).refine(
+	// Shared instructions that modes include by name with includeInstructions.
+	instructionFragments: z.record(z.string(), z.string()).optional(),
+	customModes: z.array(customModeDefinitionSchema).refine(
 		(modes) => {
 			const slugs = new Set()
 
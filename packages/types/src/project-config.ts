@@ -0,0 +1,65 @@
+import { z } from "zod"
+
+import { autoApprovalProfileSchema } from "./global-settings.js"
+import { customModeDefinitionSchema } from "./mode.js"
+
+/**
+ * ProjectAutoApprovePolicy
+ *
+ * The auto-approval guardrails of a repository. Setting an auto-approval to
+ * `false` turns it off for every contributor; `true` leaves it to each user's
+ * settings. Denied write paths are added to the user's.
+ */
+
+export const projectAutoApprovePolicySchema = autoApprovalProfileSchema
+	.omit({ allowedCommands: true, deniedCommands: true, allowedWritePaths: true })
+	.strict()
+
+export type ProjectAutoApprovePolicy = z.infer<typeof projectAutoApprovePolicySchema>
+
+/**
+ * ProjectConfig
+ *
+ * The configuration a team commits to `.roo/config.yaml` (or `.yml`, `.json`)
+ * so every contributor gets the same guardrails. It is merged with the user's
+ * settings in this order of precedence:
+ *
+ * 1. The organization policy, such as its provider allow list, always applies.
+ * 2. The repository's denied commands, disabled auto-approvals and provider
+ *    allow list apply on top of the user's settings, which can't loosen them.
+ * 3. The repository's rooignore patterns are added to `.rooignore`'s. Its
+ *    allowed commands are only suggested; the user has to accept them.
+ * 4. Modes in `.roomodes` take precedence over the repository's modes with the
+ *    same slug, which take precedence over the user's global modes.
+ */
+
+export const projectConfigSchema = z
+	.object({
+		customModes: z.array(customModeDefinitionSchema).optional(),
+		allowedCommands: z.array(z.string()).optional(),
+		deniedCommands: z.array(z.string()).optional(),
+		autoApprove: projectAutoApprovePolicySchema.optional(),
+		// Extra `.rooignore` lines, including its `@` rules.
+		rooignore: z.array(z.string()).optional(),
+		// The providers that can be used, each with its allowed models or "*" for any model.
+		allowedProviders: z.record(z.string(), z.union([z.literal("*"), z.array(z.string())])).optional(),
+	})
+	.strict()
+
+export type ProjectConfig = z.infer<typeof projectConfigSchema>
+
+/**
+ * The files the project configuration is read from, in order; the first that
+ * exists is used.
+ */
+export const PROJECT_CONFIG_FILE_NAMES = ["config.yaml", "config.yml", "config.json"] as const
+
+/**
+ * The project configuration file of the workspace and what it sets, or why it
+ * couldn't be used.
+ */
+export interface ProjectConfigStatus {
+	filePath: string
+	config?: ProjectConfig
+	error?: string
+}
@@ -2,6 +2,7 @@ import { z } from "zod"
 
 import { modelInfoSchema, reasoningEffortSettingSchema, verbosityLevelsSchema, serviceTierSchema } from "./model.js"
 import { codebaseIndexProviderSchema } from "./codebase-index.js"
+import { voiceInputProviderSchema } from "./voice.js"
 import {
 	anthropicModels,
 	basetenModels,
@@ -20,6 +21,7 @@ import {
 	xaiModels,
 	internationalZAiModels,
 	minimaxModels,
+	openAiResponsesBuiltInTools,
 } from "./providers/index.js"
 
 /**
@@ -117,6 +119,7 @@ export const providerNames = [
 	"fireworks",
 	"gemini",
 	"gemini-cli",
+	"llamacpp",
 	"mistral",
 	"moonshot",
 	"minimax",
@@ -176,6 +179,36 @@ export const providerSettingsEntrySchema = z.object({
 
 export type ProviderSettingsEntry = z.infer<typeof providerSettingsEntrySchema>
 
+/**
+ * Azure authentication
+ *
+ * `entraId` signs in through VS Code's Microsoft account provider and
+ * `managedIdentity` uses the identity of the Azure machine VS Code runs on.
+ */
+
+export const azureAuthMethods = ["apiKey", "entraId", "managedIdentity"] as const
+
+export const azureAuthMethodSchema = z.enum(azureAuthMethods)
+
+export type AzureAuthMethod = z.infer<typeof azureAuthMethodSchema>
+
+export const isAzureEntraAuth = (method?: AzureAuthMethod): method is Exclude<AzureAuthMethod, "apiKey"> =>
+	method === "entraId" || method === "managedIdentity"
+
+/**
+ * Prompt cache breakpoints
+ *
+ * Message segments that get an Anthropic-style `cache_control` breakpoint when
+ * an OpenAI-compatible endpoint or proxy passes them on to a provider that
+ * honors them.
+ */
+
+export const promptCacheBreakpoints = ["systemPrompt", "environmentDetails", "fileReads"] as const
+
+export const promptCacheBreakpointSchema = z.enum(promptCacheBreakpoints)
+
+export type PromptCacheBreakpoint = z.infer<typeof promptCacheBreakpointSchema>
+
 /**
  * ProviderSettings
  */
@@ -185,6 +218,8 @@ const baseProviderSettingsSchema = z.object({
 	todoListEnabled: z.boolean().optional(),
 	modelTemperature: z.number().nullish(),
 	rateLimitSeconds: z.number().optional(),
+	// Requests per minute shared by all tasks using the provider.
+	requestsPerMinute: z.number().int().min(0).optional(),
 	consecutiveMistakeLimit: z.number().min(0).optional(),
 
 	// Model reasoning.
@@ -195,6 +230,13 @@ const baseProviderSettingsSchema = z.object({
 
 	// Model verbosity.
 	verbosity: verbosityLevelsSchema.optional(),
+
+	// Push-to-talk voice input; unset disables it.
+	voiceInputProvider: voiceInputProviderSchema.optional(),
+	voiceInputModelId: z.string().optional(),
+	voiceInputLanguage: z.string().optional(),
+	voiceInputLocalCommand: z.string().optional(),
+	voiceInputLocalModelPath: z.string().optional(),
 })
 
 // Several of the providers share common model config properties.
@@ -223,6 +265,7 @@ const bedrockSchema = apiModelIdProviderModelSchema.extend({
 	awsRegion: z.string().optional(),
 	awsUseCrossRegionInference: z.boolean().optional(),
 	awsUseGlobalInference: z.boolean().optional(), // Enable Global Inference profile routing when supported
+	awsFallbackRegions: z.array(z.string()).optional(), // Regions to retry in when the selected region is throttled
 	awsUsePromptCache: z.boolean().optional(),
 	awsProfile: z.string().optional(),
 	awsUseProfile: z.boolean().optional(),
@@ -242,6 +285,7 @@ const vertexSchema = apiModelIdProviderModelSchema.extend({
 	vertexProjectId: z.string().optional(),
 	vertexRegion: z.string().optional(),
 	vertex1MContext: z.boolean().optional(), // Enable 'context-1m-2025-08-07' beta for 1M context window.
+	geminiContextCachingEnabled: z.boolean().optional(), // Explicit context caching for Gemini models on Vertex.
 })
 
 const openAiSchema = baseProviderSettingsSchema.extend({
@@ -252,9 +296,16 @@ const openAiSchema = baseProviderSettingsSchema.extend({
 	openAiCustomModelInfo: modelInfoSchema.nullish(),
 	openAiUseAzure: z.boolean().optional(),
 	azureApiVersion: z.string().optional(),
+	azureAuthMethod: azureAuthMethodSchema.optional(), // How Azure requests are authenticated, defaults to the API key.
+	azureManagedIdentityClientId: z.string().optional(), // Client id of a user-assigned managed identity.
 	openAiStreamingEnabled: z.boolean().optional(),
 	openAiHostHeader: z.string().optional(), // Keep temporarily for backward compatibility during migration.
 	openAiHeaders: z.record(z.string(), z.string()).optional(),
+	openAiUseResponsesApi: z.boolean().optional(), // Use the Responses API instead of Chat Completions.
+	openAiResponsesStoreEnabled: z.boolean().optional(), // Store responses and continue from the previous response id.
+	openAiResponsesBuiltInTools: z.array(z.enum(openAiResponsesBuiltInTools)).optional(),
+	// Overrides the prompt caching the model uses by default.
+	openAiPromptCacheBreakpoints: z.array(promptCacheBreakpointSchema).optional(),
 })
 
 const ollamaSchema = baseProviderSettingsSchema.extend({
@@ -262,6 +313,17 @@ const ollamaSchema = baseProviderSettingsSchema.extend({
 	ollamaBaseUrl: z.string().optional(),
 	ollamaApiKey: z.string().optional(),
 	ollamaNumCtx: z.number().int().min(128).optional(),
+	ollamaAutoNumCtx: z.boolean().optional(), // Set num_ctx to the model's context window when ollamaNumCtx isn't set.
+	ollamaKeepAlive: z.string().optional(), // How long the model stays loaded after a request, e.g. "30m" or "-1".
+	ollamaPreloadModel: z.boolean().optional(), // Load the model into memory when a task starts.
+})
+
+const llamaCppSchema = baseProviderSettingsSchema.extend({
+	llamaCppBaseUrl: z.string().optional(),
+	llamaCppApiKey: z.string().optional(),
+	llamaCppModelId: z.string().optional(),
+	llamaCppGrammarToolCalls: z.boolean().optional(), // Constrain responses to valid tool calls with a JSON schema grammar.
+	llamaCppSlotId: z.number().int().min(0).optional(), // Pin requests to a server slot to reuse its KV cache.
 })
 
 const vsCodeLmSchema = baseProviderSettingsSchema.extend({
@@ -285,6 +347,7 @@ const lmStudioSchema = baseProviderSettingsSchema.extend({
 const geminiSchema = apiModelIdProviderModelSchema.extend({
 	geminiApiKey: z.string().optional(),
 	googleGeminiBaseUrl: z.string().optional(),
+	geminiContextCachingEnabled: z.boolean().optional(), // Cache the system prompt and conversation across turns.
 })
 
 const geminiCliSchema = apiModelIdProviderModelSchema.extend({
@@ -357,6 +420,7 @@ const litellmSchema = baseProviderSettingsSchema.extend({
 	litellmApiKey: z.string().optional(),
 	litellmModelId: z.string().optional(),
 	litellmUsePromptCache: z.boolean().optional(),
+	litellmPromptCacheBreakpoints: z.array(promptCacheBreakpointSchema).optional(), // Overrides litellmUsePromptCache.
 })
 
 const sambaNovaSchema = apiModelIdProviderModelSchema.extend({
@@ -407,6 +471,7 @@ export const providerSettingsSchemaDiscriminated = z.discriminatedUnion("apiProv
 	ollamaSchema.merge(z.object({ apiProvider: z.literal("ollama") })),
 	vsCodeLmSchema.merge(z.object({ apiProvider: z.literal("vscode-lm") })),
 	lmStudioSchema.merge(z.object({ apiProvider: z.literal("lmstudio") })),
+	llamaCppSchema.merge(z.object({ apiProvider: z.literal("llamacpp") })),
 	geminiSchema.merge(z.object({ apiProvider: z.literal("gemini") })),
 	geminiCliSchema.merge(z.object({ apiProvider: z.literal("gemini-cli") })),
 	openAiCodexSchema.merge(z.object({ apiProvider: z.literal("openai-codex") })),
@@ -441,6 +506,7 @@ export const providerSettingsSchema = z.object({
 	...ollamaSchema.shape,
 	...vsCodeLmSchema.shape,
 	...lmStudioSchema.shape,
+	...llamaCppSchema.shape,
 	...geminiSchema.shape,
 	...geminiCliSchema.shape,
 	...openAiCodexSchema.shape,
@@ -488,6 +554,7 @@ export const modelIdKeys = [
 	"ollamaModelId",
 	"lmStudioModelId",
 	"lmStudioDraftModelId",
+	"llamaCppModelId",
 	"requestyModelId",
 	"unboundModelId",
 	"litellmModelId",
@@ -519,6 +586,7 @@ export const modelIdKeysByProvider: Record<TypicalProvider, ModelIdKey> = {
 	"openai-native": "openAiModelId",
 	ollama: "ollamaModelId",
 	lmstudio: "lmStudioModelId",
+	llamacpp: "llamaCppModelId",
 	gemini: "apiModelId",
 	"gemini-cli": "apiModelId",
 	mistral: "apiModelId",
@@ -657,5 +725,6 @@ export const MODELS_BY_PROVIDER: Record<
 
 	// Local providers; models discovered from localhost endpoints.
 	lmstudio: { id: "lmstudio", label: "LM Studio", models: [] },
+	llamacpp: { id: "llamacpp", label: "llama.cpp", models: [] },
 	ollama: { id: "ollama", label: "Ollama", models: [] },
 }
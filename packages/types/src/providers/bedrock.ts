@@ -571,3 +571,13 @@ export const BEDROCK_SERVICE_TIER_PRICING = {
 	FLEX: 0.5, // 50% discount from standard
 	PRIORITY: 1.75, // 75% premium over standard
 } as const
+
+// Application inference profiles are created by users to track usage and cost of a
+// foundation model or system inference profile.
+// https://docs.aws.amazon.com/bedrock/latest/userguide/inference-profiles-create.html
+export interface BedrockInferenceProfile {
+	arn: string
+	name: string
+	// Id of the foundation model the profile routes to
+	modelId?: string
+}
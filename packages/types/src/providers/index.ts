@@ -5,6 +5,7 @@ export * from "./deepseek.js"
 export * from "./fireworks.js"
 export * from "./gemini.js"
 export * from "./lite-llm.js"
+export * from "./llama-cpp.js"
 export * from "./lm-studio.js"
 export * from "./mistral.js"
 export * from "./moonshot.js"
@@ -99,6 +100,8 @@ export function getProviderDefaultModelId(
 			return "" // Ollama uses dynamic model selection
 		case "lmstudio":
 			return "" // LMStudio uses dynamic model selection
+		case "llamacpp":
+			return "" // llama.cpp serves the model it was started with
 		case "vscode-lm":
 			return vscodeLlmDefaultModelId
 		case "sambanova":
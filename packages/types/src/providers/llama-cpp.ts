@@ -0,0 +1,20 @@
+import type { ModelInfo } from "../model.js"
+
+export const LLAMA_CPP_DEFAULT_TEMPERATURE = 0
+
+// llama.cpp server (llama-server)
+// https://github.com/ggml-org/llama.cpp/tree/master/tools/server
+export const LLAMA_CPP_DEFAULT_BASE_URL = "http://localhost:8080"
+
+// The context size and vision support of the loaded model are read from the server's /props endpoint.
+export const llamaCppDefaultModelInfo: ModelInfo = {
+	maxTokens: 8192,
+	contextWindow: 32_768,
+	supportsImages: false,
+	supportsPromptCache: true,
+	inputPrice: 0,
+	outputPrice: 0,
+	cacheWritesPrice: 0,
+	cacheReadsPrice: 0,
+	description: "Model served by llama.cpp",
+}
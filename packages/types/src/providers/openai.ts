@@ -606,3 +606,8 @@ export const azureOpenAiDefaultApiVersion = "2024-08-01-preview"
 export const OPENAI_NATIVE_DEFAULT_TEMPERATURE = 0
 
 export const OPENAI_AZURE_AI_INFERENCE_PATH = "/models/chat/completions"
+
+// Tools the Responses API runs on the server, passed through by the OpenAI Compatible provider
+export const openAiResponsesBuiltInTools = ["web_search", "code_interpreter"] as const
+
+export type OpenAiResponsesBuiltInTool = (typeof openAiResponsesBuiltInTools)[number]
@@ -0,0 +1,52 @@
+import { z } from "zod"
+
+import { autoApprovalProfileSchema } from "./global-settings.js"
+
+/**
+ * ScheduleTrigger
+ *
+ * When a scheduled task runs: on a cron schedule (five fields, in local time),
+ * when the workspace opens, after a `git pull`, or when files matching a glob
+ * change.
+ */
+
+export const scheduleTriggerSchema = z.discriminatedUnion("type", [
+	z.object({ type: z.literal("cron"), cron: z.string().min(1) }),
+	z.object({ type: z.literal("workspaceOpen") }),
+	z.object({ type: z.literal("gitPull") }),
+	z.object({ type: z.literal("fileChanged"), glob: z.string().min(1) }),
+])
+
+export type ScheduleTrigger = z.infer<typeof scheduleTriggerSchema>
+
+/**
+ * ScheduledTask
+ *
+ * A task defined in `schedules.json` in the global or project `.roo` directory.
+ * The prompt is a template; see `SCHEDULE_PROMPT_VARIABLES`. Project schedules
+ * only run once the user has allowed the project's file.
+ */
+
+export const scheduledTaskSchema = z.object({
+	name: z.string().min(1),
+	trigger: scheduleTriggerSchema,
+	mode: z.string().min(1),
+	prompt: z.string().min(1),
+	autoApprove: autoApprovalProfileSchema.optional(),
+	enabled: z.boolean().optional(),
+})
+
+export type ScheduledTask = z.infer<typeof scheduledTaskSchema>
+
+export const schedulesFileSchema = z.object({
+	schedules: z.array(scheduledTaskSchema),
+})
+
+export type SchedulesFile = z.infer<typeof schedulesFileSchema>
+
+/**
+ * The variables a scheduled task's prompt can use, as `{{name}}`.
+ */
+export const SCHEDULE_PROMPT_VARIABLES = ["date", "time", "lastRun", "workspace", "trigger", "changedFiles"] as const
+
+export type SchedulePromptVariable = (typeof SCHEDULE_PROMPT_VARIABLES)[number]
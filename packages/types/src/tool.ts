@@ -23,10 +23,15 @@ export type ToolGroup = z.infer<typeof toolGroupsSchema>
 
 export const toolNames = [
 	"execute_command",
+	"background_process",
+	"run_tests",
+	"git",
 	"read_file",
 	"read_command_output",
 	"write_to_file",
 	"apply_diff",
+	"fast_edit",
+	"edit_notebook",
 	"edit",
 	"search_and_replace",
 	"search_replace",
@@ -40,7 +45,9 @@ export const toolNames = [
 	"attempt_completion",
 	"switch_mode",
 	"new_task",
+	"run_parallel_tasks",
 	"codebase_search",
+	"find_references",
 	"update_todo_list",
 	"run_slash_command",
 	"skill",
@@ -0,0 +1,46 @@
+import { z } from "zod"
+
+/**
+ * UsageCounters
+ */
+
+export const USAGE_COUNTERS = [
+	"requests",
+	"tokensIn",
+	"tokensOut",
+	"cacheWrites",
+	"cacheReads",
+	"cacheSavings",
+	"cost",
+] as const
+
+export type UsageCounter = (typeof USAGE_COUNTERS)[number]
+
+export type UsageCounters = Record<UsageCounter, number>
+
+/**
+ * UsageEntry
+ *
+ * The API usage of one day in one workspace, mode and model. The usage log
+ * in global storage is a list of these; it outlives the tasks it came from,
+ * so deleting the task history doesn't reset it.
+ */
+
+export const usageEntrySchema = z.object({
+	// The local day of the requests, as YYYY-MM-DD.
+	date: z.string(),
+	workspace: z.string(),
+	mode: z.string(),
+	provider: z.string(),
+	modelId: z.string(),
+	requests: z.number(),
+	tokensIn: z.number(),
+	tokensOut: z.number(),
+	cacheWrites: z.number(),
+	cacheReads: z.number(),
+	// What the cache reads would have cost more as uncached input tokens.
+	cacheSavings: z.number(),
+	cost: z.number(),
+})
+
+export type UsageEntry = z.infer<typeof usageEntrySchema>
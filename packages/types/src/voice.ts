@@ -0,0 +1,40 @@
+import { z } from "zod"
+
+/**
+ * VoiceInputProvider
+ *
+ * How dictated audio is turned into text:
+ * - `provider`: the speech to text endpoint of the profile's provider (OpenAI or OpenAI Compatible)
+ * - `local`: a local whisper.cpp command, so audio never leaves the machine
+ */
+
+export const voiceInputProviders = ["provider", "local"] as const
+
+export const voiceInputProviderSchema = z.enum(voiceInputProviders)
+
+export type VoiceInputProvider = z.infer<typeof voiceInputProviderSchema>
+
+export const DEFAULT_VOICE_INPUT_MODEL_ID = "whisper-1"
+
+export const DEFAULT_WHISPER_COMMAND = "whisper-cli"
+
+/**
+ * VoiceInputState
+ *
+ * Sent to the webview as push-to-talk moves from recording to transcribing.
+ */
+
+export type VoiceInputState = "idle" | "recording" | "transcribing"
+
+/**
+ * TtsContent
+ *
+ * What is read aloud when text to speech is enabled. Questions and approvals
+ * that wait for the user are announced either way.
+ */
+
+export const ttsContents = ["all", "summaries"] as const
+
+export const ttsContentSchema = z.enum(ttsContents)
+
+export type TtsContent = z.infer<typeof ttsContentSchema>
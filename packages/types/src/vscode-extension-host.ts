@@ -2,7 +2,7 @@ import { z } from "zod"
 
 import type { GlobalSettings, RooCodeSettings } from "./global-settings.js"
 import type { ProviderSettings, ProviderSettingsEntry } from "./provider-settings.js"
-import type { HistoryItem } from "./history.js"
+import type { HistoryItem, TaskSearchResult } from "./history.js"
 import type { ModeConfig, PromptComponent } from "./mode.js"
 import type { TelemetrySetting } from "./telemetry.js"
 import type { Experiments } from "./experiment.js"
@@ -18,9 +18,12 @@ import type { CloudUserInfo, CloudOrganizationMembership, OrganizationAllowList,
 import type { SerializedCustomToolDefinition } from "./custom-tool.js"
 import type { GitCommit } from "./git.js"
 import type { McpServer } from "./mcp.js"
+import type { ProjectConfigStatus } from "./project-config.js"
 import type { ModelRecord, RouterModels } from "./model.js"
+import type { BedrockInferenceProfile } from "./providers/bedrock.js"
 import type { OpenAiCodexRateLimitInfo } from "./providers/openai-codex-rate-limits.js"
 import type { SkillMetadata } from "./skills.js"
+import type { UsageEntry } from "./usage.js"
 import type { WorktreeIncludeStatus } from "./worktree.js"
 
 /**
@@ -46,6 +49,7 @@ export interface ExtensionMessage {
 		| "openAiModels"
 		| "ollamaModels"
 		| "lmStudioModels"
+		| "bedrockInferenceProfiles"
 		| "vsCodeLmModels"
 		| "vsCodeLmApiAvailable"
 		| "updatePrompt"
@@ -61,6 +65,8 @@ export interface ExtensionMessage {
 		| "checkpointInitWarning"
 		| "ttsStart"
 		| "ttsStop"
+		| "voiceInputState"
+		| "voiceInputTranscript"
 		| "fileSearchResults"
 		| "toggleApiConfigPin"
 		| "acceptInput"
@@ -104,10 +110,16 @@ export interface ExtensionMessage {
 		| "folderSelected"
 		| "skills"
 		| "fileContent"
+		| "checkpointStorageUsage"
+		| "taskHistorySearchResults"
+		| "usageStats"
 	text?: string
 	/** For fileContent: { path, content, error? } */
 	fileContent?: { path: string; content: string | null; error?: string }
 	payload?: any // eslint-disable-line @typescript-eslint/no-explicit-any
+	checkpointStorage?: CheckpointStorageUsage
+	taskSearchResults?: TaskSearchResult[]
+	usageEntries?: UsageEntry[]
 	checkpointWarning?: {
 		type: "WAIT_TIMEOUT" | "INIT_TIMEOUT"
 		timeout: number
@@ -140,6 +152,7 @@ export interface ExtensionMessage {
 	openAiModels?: string[]
 	ollamaModels?: ModelRecord
 	lmStudioModels?: ModelRecord
+	bedrockInferenceProfiles?: BedrockInferenceProfile[]
 	vsCodeLmModels?: { vendor?: string; family?: string; version?: string; id?: string }[]
 	mcpServers?: McpServer[]
 	commits?: GitCommit[]
@@ -259,18 +272,27 @@ export type ExtensionState = Pick<
 	| "alwaysAllowWrite"
 	| "alwaysAllowWriteOutsideWorkspace"
 	| "alwaysAllowWriteProtected"
+	| "allowedWritePaths"
+	| "deniedWritePaths"
 	| "alwaysAllowMcp"
 	| "alwaysAllowModeSwitch"
 	| "alwaysAllowSubtasks"
 	| "alwaysAllowFollowupQuestions"
 	| "alwaysAllowExecute"
 	| "followupAutoApproveTimeoutMs"
+	| "denyUnapprovedActions"
 	| "allowedCommands"
 	| "deniedCommands"
 	| "allowedMaxRequests"
 	| "allowedMaxCost"
+	| "taskBudget"
+	| "modeBudgets"
+	| "condensingStrategy"
+	| "modeCondensingStrategies"
+	| "customCondensingStrategies"
 	| "ttsEnabled"
 	| "ttsSpeed"
+	| "ttsContent"
 	| "soundEnabled"
 	| "soundVolume"
 	| "terminalOutputPreviewSize"
@@ -289,16 +311,24 @@ export type ExtensionState = Pick<
 	| "customModePrompts"
 	| "customSupportPrompts"
 	| "enhancementApiConfigId"
+	| "fastApplyApiConfigId"
+	| "maxParallelSubtasks"
+	| "checkpointMaxAgeDays"
+	| "checkpointMaxStorageMb"
+	| "checkpointMaxPerTask"
+	| "mcpResourceUpdateNotifications"
 	| "customCondensingPrompt"
 	| "codebaseIndexConfig"
 	| "codebaseIndexModels"
 	| "profileThresholds"
+	| "failoverApiConfigIds"
 	| "includeDiagnosticMessages"
 	| "maxDiagnosticMessages"
 	| "imageGenerationProvider"
 	| "openRouterImageGenerationSelectedModel"
 	| "includeTaskHistoryInEnhance"
 	| "reasoningBlockCollapsed"
+	| "diffViewLayout"
 	| "enterBehavior"
 	| "includeCurrentTime"
 	| "includeCurrentCost"
@@ -330,6 +360,7 @@ export type ExtensionState = Pick<
 	maxReadFileLine?: number // Maximum line limit for read_file tool (-1 for default)
 	maxImageFileSize: number // Maximum size of image files to process in MB
 	maxTotalImageSize: number // Maximum total size for all images in a single read operation in MB
+	readFileTokenBudget?: number // Token budget for a single read_file result (0 disables chunked reads)
 
 	experiments: Experiments // Map of experiment IDs to their enabled state
 
@@ -357,6 +388,8 @@ export type ExtensionState = Pick<
 	publicSharingEnabled: boolean
 	organizationAllowList: OrganizationAllowList
 	organizationSettingsVersion?: number
+	// The repository's shared configuration, from .roo/config
+	projectConfig?: ProjectConfigStatus
 
 	autoCondenseContext: boolean
 	autoCondenseContextPercent: number
@@ -374,6 +407,7 @@ export type ExtensionState = Pick<
 	taskSyncEnabled: boolean
 	openAiCodexIsAuthenticated?: boolean
 	debug?: boolean
+	debugRecorder?: boolean
 
 	/**
 	 * Monotonically increasing sequence number for clineMessages state pushes.
@@ -433,6 +467,9 @@ export interface WebviewMessage {
 		| "showTaskWithId"
 		| "deleteTaskWithId"
 		| "exportTaskWithId"
+		| "exportTaskReport"
+		| "searchTaskHistory"
+		| "updateTaskTags"
 		| "importSettings"
 		| "exportSettings"
 		| "resetState"
@@ -441,6 +478,7 @@ export interface WebviewMessage {
 		| "requestOpenAiModels"
 		| "requestOllamaModels"
 		| "requestLmStudioModels"
+		| "requestBedrockInferenceProfiles"
 		| "requestRooModels"
 		| "requestRooCreditBalance"
 		| "requestVsCodeLmModels"
@@ -460,10 +498,15 @@ export interface WebviewMessage {
 		| "stopTts"
 		| "ttsEnabled"
 		| "ttsSpeed"
+		| "startVoiceInput"
+		| "stopVoiceInput"
+		| "cancelVoiceInput"
 		| "openKeyboardShortcuts"
 		| "openMcpSettings"
 		| "openProjectMcpSettings"
 		| "restartMcpServer"
+		| "authenticateMcpServer"
+		| "signOutMcpServer"
 		| "refreshAllMcpServers"
 		| "toggleToolAlwaysAllow"
 		| "toggleToolEnabledForPrompt"
@@ -476,6 +519,8 @@ export interface WebviewMessage {
 		| "deleteMessageConfirm"
 		| "submitEditedMessage"
 		| "editMessageConfirm"
+		| "pinMessage"
+		| "pinFile"
 		| "taskSyncEnabled"
 		| "searchCommits"
 		| "setApiConfigPassword"
@@ -492,6 +537,11 @@ export interface WebviewMessage {
 		| "openCustomModesSettings"
 		| "checkpointDiff"
 		| "checkpointRestore"
+		| "checkpointRestoreFiles"
+		| "requestCheckpointStorageUsage"
+		| "deleteTaskCheckpoints"
+		| "requestUsageStats"
+		| "exportUsageCsv"
 		| "deleteMcpServer"
 		| "codebaseIndexEnabled"
 		| "telemetrySetting"
@@ -556,12 +606,14 @@ export interface WebviewMessage {
 		| "deniedCommands"
 		| "openDebugApiHistory"
 		| "openDebugUiHistory"
+		| "exportProviderTraffic"
 		| "downloadErrorDiagnostics"
 		| "requestOpenAiCodexRateLimits"
 		| "refreshCustomTools"
 		| "requestModes"
 		| "switchMode"
 		| "debugSetting"
+		| "debugRecorderSetting"
 		// Worktree messages
 		| "listWorktrees"
 		| "createWorktree"
@@ -668,6 +720,11 @@ export interface WebviewMessage {
 		codebaseIndexBedrockProfile?: string
 		codebaseIndexSearchMaxResults?: number
 		codebaseIndexSearchMinScore?: number
+		codebaseIndexHybridSearchWeight?: number
+		codebaseIndexRerankerProvider?: "cohere" | "voyage"
+		codebaseIndexRerankerModelId?: string
+		codebaseIndexRerankerCandidates?: number
+		codebaseIndexDocumentTypes?: Array<"markdown" | "config">
 		codebaseIndexOpenRouterSpecificProvider?: string // OpenRouter provider routing
 
 		// Secret settings
@@ -678,6 +735,8 @@ export interface WebviewMessage {
 		codebaseIndexMistralApiKey?: string
 		codebaseIndexVercelAiGatewayApiKey?: string
 		codebaseIndexOpenRouterApiKey?: string
+		codebaseIndexCohereApiKey?: string
+		codebaseIndexVoyageApiKey?: string
 	}
 	updatedSettings?: RooCodeSettings
 	/** Task configuration applied via `createTask()` when starting a cloud task. */
@@ -700,7 +759,9 @@ export const checkoutDiffPayloadSchema = z.object({
 	ts: z.number().optional(),
 	previousCommitHash: z.string().optional(),
 	commitHash: z.string(),
-	mode: z.enum(["full", "checkpoint", "from-init", "to-current"]),
+	// The checkpoint to compare with in "compare" mode; the user picks one if it's not given.
+	toCommitHash: z.string().optional(),
+	mode: z.enum(["full", "checkpoint", "from-init", "to-current", "compare"]),
 })
 
 export type CheckpointDiffPayload = z.infer<typeof checkoutDiffPayloadSchema>
@@ -713,6 +774,29 @@ export const checkoutRestorePayloadSchema = z.object({
 
 export type CheckpointRestorePayload = z.infer<typeof checkoutRestorePayloadSchema>
 
+export const checkoutRestoreFilesPayloadSchema = z.object({
+	commitHash: z.string(),
+	// Workspace-relative paths; the user picks from the changed files if they're not given.
+	paths: z.array(z.string()).optional(),
+})
+
+export type CheckpointRestoreFilesPayload = z.infer<typeof checkoutRestoreFilesPayloadSchema>
+
+export interface TaskCheckpointStorage {
+	taskId: string
+	// The task's first message, if the task is still in the history
+	task?: string
+	// Bytes on disk
+	size: number
+	lastActivity: number
+	isOpen: boolean
+}
+
+export interface CheckpointStorageUsage {
+	tasks: TaskCheckpointStorage[]
+	totalSize: number
+}
+
 export interface IndexingStatusPayload {
 	state: "Standby" | "Indexing" | "Indexed" | "Error" | "Stopping"
 	message: string
@@ -735,6 +819,7 @@ export type InstallMarketplaceItemWithParametersPayload = z.infer<
 export type WebViewMessagePayload =
 	| CheckpointDiffPayload
 	| CheckpointRestorePayload
+	| CheckpointRestoreFilesPayload
 	| IndexingStatusPayload
 	| IndexClearedPayload
 	| InstallMarketplaceItemWithParametersPayload
@@ -769,7 +854,10 @@ export interface ClineSayTool {
 		| "editedExistingFile"
 		| "appliedDiff"
 		| "newFileCreated"
+		| "stagedChange"
+		| "pendingChanges"
 		| "codebaseSearch"
+		| "findReferences"
 		| "readFile"
 		| "readCommandOutput"
 		| "listFilesTopLevel"
@@ -777,6 +865,7 @@ export interface ClineSayTool {
 		| "searchFiles"
 		| "switchMode"
 		| "newTask"
+		| "runParallelTasks"
 		| "finishTask"
 		| "generateImage"
 		| "imageGenerated"
@@ -806,6 +895,9 @@ export interface ClineSayTool {
 	lineNumber?: number
 	startLine?: number // Starting line for read_file operations (for navigation on click)
 	query?: string
+	language?: string // For codebaseSearch filters
+	kind?: string
+	symbol?: string // For findReferences
 	batchFiles?: Array<{
 		path: string
 		lineSnippet: string
@@ -840,6 +932,8 @@ export interface ClineSayTool {
 	description?: string
 	// Properties for skill tool
 	skill?: string
+	// Properties for runParallelTasks tool
+	subtasks?: Array<{ mode: string; message: string }>
 }
 
 export interface ClineAskUseMcpServer {
@@ -861,6 +955,9 @@ export interface ClineApiReqInfo {
 	cancelReason?: ClineApiReqCancelReason
 	streamingFailedMessage?: string
 	apiProtocol?: "anthropic" | "openai"
+	// The provider and model that served the request
+	provider?: string
+	modelId?: string
 }
 
 export type ClineApiReqCancelReason = "streaming_failed" | "user_cancelled"
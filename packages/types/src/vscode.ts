@@ -47,6 +47,11 @@ export const commandIds = [
 	"acceptInput",
 	"focusPanel",
 	"toggleAutoApprove",
+	"pinFileToTask",
+
+	"reindexCodebase",
+	"showCodebaseIndexStatus",
+	"clearCodebaseIndex",
 ] as const
 
 export type CommandId = (typeof commandIds)[number]
@@ -410,4 +410,37 @@ npm install
 			expect(result.text).toContain("Command 'build'")
 		})
 	})
+
+	describe("commands with arguments", () => {
+		const review = {
+			name: "review",
+			content: "Review @/{{file}} and report issues of {{severity}} severity or higher.",
+			source: "project" as const,
+			filePath: "/project/.roo/commands/review.md",
+			mode: "ask",
+			arguments: [{ name: "file" }, { name: "severity", default: "medium" }],
+		}
+
+		it("should expand the command in place with the rest of its line as arguments", async () => {
+			mockGetCommand.mockResolvedValue(review)
+
+			const result = await callParseMentions("/review src/app.ts high\nThanks!")
+
+			expect(result.text).toBe("Review 'src/app.ts' and report issues of high severity or higher.\nThanks!")
+			expect(result.mode).toBe("ask")
+			expect(result.slashCommandHelp).toBeUndefined()
+			expect(result.contentBlocks).toHaveLength(1)
+			expect(result.contentBlocks[0].path).toBe("src/app.ts")
+		})
+
+		it("should use defaults and report missing arguments", async () => {
+			mockGetCommand.mockResolvedValue(review)
+
+			const result = await callParseMentions("/review")
+
+			expect(result.text).toBe("Review {{file}} and report issues of medium severity or higher.")
+			expect(result.slashCommandHelp).toContain('<command name="review">')
+			expect(result.slashCommandHelp).toContain("Missing arguments: file.")
+		})
+	})
 })
@@ -29,6 +29,12 @@ export const handleUri = async (uri: vscode.Uri) => {
 			}
 			break
 		}
+		case "/mcp/oauth/callback": {
+			await visibleProvider
+				.getMcpHub()
+				?.handleOAuthCallback(query.get("code"), query.get("state"), query.get("error"))
+			break
+		}
 		case "/auth/clerk/callback": {
 			const code = query.get("code")
 			const state = query.get("state")
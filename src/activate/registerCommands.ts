@@ -1,7 +1,8 @@
 import * as vscode from "vscode"
+import * as path from "path"
 import delay from "delay"
 
-import type { CommandId } from "@roo-code/types"
+import type { CodebaseReindexOptions, CommandId } from "@roo-code/types"
 import { TelemetryService } from "@roo-code/telemetry"
 
 import { Package } from "../shared/package"
@@ -195,6 +196,98 @@ const getCommandsMap = ({ context, outputChannel, provider }: RegisterCommandOpt
 			action: "toggleAutoApprove",
 		})
 	},
+	// Pins the file from the explorer context menu, or the active editor's file, to the current task.
+	pinFileToTask: async (uri?: vscode.Uri) => {
+		const visibleProvider = getVisibleProviderOrLog(outputChannel)
+
+		if (!visibleProvider) {
+			return
+		}
+
+		const task = visibleProvider.getCurrentTask()
+
+		if (!task) {
+			vscode.window.showErrorMessage(t("common:errors.pin_file_no_task"))
+			return
+		}
+
+		const fileUri = uri ?? vscode.window.activeTextEditor?.document.uri
+
+		if (fileUri?.scheme !== "file") {
+			return
+		}
+
+		await task.setFilePinned(fileUri.fsPath, true)
+		vscode.window.showInformationMessage(
+			t("common:info.file_pinned", { path: path.relative(task.cwd, fileUri.fsPath) }),
+		)
+	},
+	// The codebase index commands take options when run through `executeCommand`, so scripts can
+	// await them. Failures are rethrown to those callers; from the palette the message is enough.
+	reindexCodebase: async (options?: CodebaseReindexOptions) => {
+		try {
+			await vscode.window.withProgress(
+				{ location: vscode.ProgressLocation.Notification, title: t("embeddings:commands.reindexing") },
+				() => CodeIndexManager.reindexWorkspaces(provider.contextProxy, options),
+			)
+			vscode.window.showInformationMessage(t("embeddings:commands.reindexComplete"))
+		} catch (error) {
+			const errorMessage = error instanceof Error ? error.message : String(error)
+			vscode.window.showErrorMessage(t("embeddings:commands.reindexFailed", { errorMessage }))
+			if (options) {
+				throw error
+			}
+		}
+	},
+	showCodebaseIndexStatus: () => {
+		const stats = CodeIndexManager.getAllIndexStats()
+
+		if (stats.length === 0) {
+			vscode.window.showInformationMessage(t("embeddings:commands.noIndex"))
+			return stats
+		}
+
+		const detail = stats
+			.map(({ workspacePath, systemStatus, filesIndexed, chunksIndexed, lastError }) => {
+				const status = t("embeddings:commands.status", {
+					folder: path.basename(workspacePath),
+					status: systemStatus,
+					files: filesIndexed,
+					chunks: chunksIndexed,
+				})
+				return lastError
+					? `${status}\n${t("embeddings:commands.lastError", { message: lastError.message })}`
+					: status
+			})
+			.join("\n\n")
+
+		vscode.window.showInformationMessage(t("embeddings:commands.statusTitle"), { modal: true, detail })
+		return stats
+	},
+	clearCodebaseIndex: async (options?: { workspacePath?: string }) => {
+		if (!options) {
+			const confirm = t("embeddings:commands.clearConfirmButton")
+			const answer = await vscode.window.showWarningMessage(
+				t("embeddings:commands.clearConfirm"),
+				{ modal: true },
+				confirm,
+			)
+			if (answer !== confirm) {
+				return
+			}
+		}
+
+		try {
+			await CodeIndexManager.clearWorkspaces(options?.workspacePath)
+			vscode.window.showInformationMessage(t("embeddings:commands.cleared"))
+		} catch (error) {
+			const errorMessage = error instanceof Error ? error.message : String(error)
+			vscode.window.showErrorMessage(t("embeddings:commands.clearFailed", { errorMessage }))
+			if (options) {
+				throw error
+			}
+		}
+	},
 })
 
 export const openClineInNewTab = async ({ context, outputChannel }: Omit<RegisterCommandOptions, "provider">) => {
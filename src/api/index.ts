@@ -15,8 +15,10 @@ import {
 	OpenAiHandler,
 	OpenAiCodexHandler,
 	LmStudioHandler,
+	LlamaCppHandler,
 	GeminiHandler,
 	OpenAiNativeHandler,
+	OpenAiResponsesHandler,
 	DeepSeekHandler,
 	MoonshotHandler,
 	MistralHandler,
@@ -41,6 +43,14 @@ export interface SingleCompletionHandler {
 	completePrompt(prompt: string): Promise<string>
 }
 
+/**
+ * Implemented by providers that run models locally and can load the model
+ * into memory ahead of the first request.
+ */
+export interface ModelPreloadHandler {
+	preloadModel(): Promise<void>
+}
+
 export interface ApiHandlerCreateMessageMetadata {
 	/**
 	 * Task ID used for tracking and provider-specific features:
@@ -130,11 +140,13 @@ export function buildApiHandler(configuration: ProviderSettings): ApiHandler {
 				? new AnthropicVertexHandler(options)
 				: new VertexHandler(options)
 		case "openai":
-			return new OpenAiHandler(options)
+			return options.openAiUseResponsesApi ? new OpenAiResponsesHandler(options) : new OpenAiHandler(options)
 		case "ollama":
 			return new NativeOllamaHandler(options)
 		case "lmstudio":
 			return new LmStudioHandler(options)
+		case "llamacpp":
+			return new LlamaCppHandler(options)
 		case "gemini":
 			return new GeminiHandler(options)
 		case "openai-codex":
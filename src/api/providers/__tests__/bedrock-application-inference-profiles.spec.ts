@@ -0,0 +1,101 @@
+// npx vitest run src/api/providers/__tests__/bedrock-application-inference-profiles.spec.ts
+
+vitest.mock("@roo-code/telemetry", () => ({
+	TelemetryService: {
+		instance: {
+			captureException: vitest.fn(),
+		},
+	},
+}))
+
+const mockRuntimeSend = vitest.fn()
+const mockGetInferenceProfile = vitest.fn()
+const mockListInferenceProfiles = vitest.fn()
+
+vitest.mock("@aws-sdk/client-bedrock-runtime", () => ({
+	BedrockRuntimeClient: vitest.fn().mockImplementation((config) => ({ config, send: mockRuntimeSend })),
+	ConverseStreamCommand: vitest.fn((input) => ({ input })),
+	ConverseCommand: vitest.fn((input) => ({ input })),
+}))
+
+vitest.mock("../utils/bedrock-control-plane", () => ({
+	BedrockControlPlaneClient: vitest.fn().mockImplementation(() => ({
+		getInferenceProfile: mockGetInferenceProfile,
+		listInferenceProfiles: mockListInferenceProfiles,
+	})),
+}))
+
+import { bedrockModels } from "@roo-code/types"
+
+import { AwsBedrockHandler, getBedrockApplicationInferenceProfiles } from "../bedrock"
+
+const profileArn = "arn:aws:bedrock:us-east-1:123456789012:application-inference-profile/a1b2c3d4e5f6"
+const modelId = "anthropic.claude-3-5-sonnet-20241022-v2:0"
+
+describe("Bedrock application inference profiles", () => {
+	beforeEach(() => {
+		vitest.clearAllMocks()
+		mockRuntimeSend.mockResolvedValue({ stream: [] })
+	})
+
+	it("prices invocations of a profile as its foundation model while invoking the profile", async () => {
+		mockGetInferenceProfile.mockResolvedValue({
+			models: [
+				{ modelArn: `arn:aws:bedrock:us-east-1::foundation-model/${modelId}` },
+				{ modelArn: `arn:aws:bedrock:us-west-2::foundation-model/${modelId}` },
+			],
+		})
+		const handler = new AwsBedrockHandler({ awsRegion: "us-east-1", awsCustomArn: profileArn })
+
+		for await (const _chunk of handler.createMessage("system", [{ role: "user", content: "Hi" }])) {
+			// Drain the stream
+		}
+
+		expect(mockGetInferenceProfile).toHaveBeenCalledWith(profileArn)
+		expect(mockRuntimeSend.mock.calls[0][0].input.modelId).toBe(profileArn)
+
+		const model = handler.getModel()
+		expect(model.id).toBe(profileArn)
+		expect(model.info.inputPrice).toBe(bedrockModels[modelId].inputPrice)
+		expect(model.info.outputPrice).toBe(bedrockModels[modelId].outputPrice)
+	})
+
+	it("keeps the guessed model info when the profile can't be looked up", async () => {
+		mockGetInferenceProfile.mockRejectedValue(new Error("AccessDeniedException: not allowed"))
+		const handler = new AwsBedrockHandler({ awsRegion: "us-east-1", awsCustomArn: profileArn })
+		const guessed = handler.getModel()
+
+		await handler.completePrompt("Hi")
+
+		expect(handler.getModel()).toEqual(guessed)
+		expect(mockRuntimeSend.mock.calls[0][0].input.modelId).toBe(profileArn)
+	})
+
+	it("lists the application inference profiles of all pages", async () => {
+		mockListInferenceProfiles
+			.mockResolvedValueOnce({
+				inferenceProfileSummaries: [
+					{
+						inferenceProfileArn: profileArn,
+						inferenceProfileName: "team-a",
+						models: [{ modelArn: `arn:aws:bedrock:us-east-1::foundation-model/${modelId}` }],
+					},
+				],
+				nextToken: "page-2",
+			})
+			.mockResolvedValueOnce({
+				inferenceProfileSummaries: [{ inferenceProfileArn: `${profileArn}2`, models: [] }],
+			})
+
+		const profiles = await getBedrockApplicationInferenceProfiles({ awsRegion: "us-east-1" })
+
+		expect(profiles).toEqual([
+			{ arn: profileArn, name: "team-a", modelId },
+			{ arn: `${profileArn}2`, name: `${profileArn}2`, modelId: undefined },
+		])
+		expect(mockListInferenceProfiles.mock.calls.map(([params]) => params)).toEqual([
+			{ typeEquals: "APPLICATION", nextToken: undefined },
+			{ typeEquals: "APPLICATION", nextToken: "page-2" },
+		])
+	})
+})
@@ -0,0 +1,105 @@
+// npx vitest run src/api/providers/__tests__/bedrock-region-fallback.spec.ts
+
+vitest.mock("@roo-code/telemetry", () => ({
+	TelemetryService: {
+		instance: {
+			captureException: vitest.fn(),
+		},
+	},
+}))
+
+const mockSend = vitest.fn()
+
+vitest.mock("@aws-sdk/client-bedrock-runtime", () => ({
+	BedrockRuntimeClient: vitest.fn().mockImplementation((config) => ({
+		config,
+		send: (command: unknown) => mockSend(config.region, command),
+	})),
+	ConverseStreamCommand: vitest.fn((input) => ({ input })),
+	ConverseCommand: vitest.fn((input) => ({ input })),
+}))
+
+import { BedrockRuntimeClient } from "@aws-sdk/client-bedrock-runtime"
+
+import type { ApiHandlerOptions } from "../../../shared/api"
+import { AwsBedrockHandler } from "../bedrock"
+
+const throttlingError = () => Object.assign(new Error("Too many requests"), { name: "ThrottlingException" })
+
+const textResponse = (text: string) => ({
+	stream: [{ contentBlockStart: { start: { text }, contentBlockIndex: 0 } }],
+})
+
+async function collect(stream: AsyncIterable<any>) {
+	const chunks: any[] = []
+	for await (const chunk of stream) {
+		chunks.push(chunk)
+	}
+	return chunks
+}
+
+describe("AwsBedrockHandler region fallback", () => {
+	const options: ApiHandlerOptions = {
+		apiModelId: "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		awsAccessKey: "test-access-key",
+		awsSecretKey: "test-secret-key",
+		awsRegion: "us-east-1",
+		awsUseCrossRegionInference: true,
+		awsFallbackRegions: ["us-east-1", "eu-west-1", "ap-northeast-1"],
+	}
+
+	beforeEach(() => {
+		vitest.clearAllMocks()
+	})
+
+	it("retries a throttled request in the next region with its inference profile", async () => {
+		mockSend.mockRejectedValueOnce(throttlingError()).mockResolvedValueOnce(textResponse("Hello"))
+		const handler = new AwsBedrockHandler(options)
+
+		const chunks = await collect(handler.createMessage("system", [{ role: "user", content: "Hi" }]))
+
+		expect(chunks).toContainEqual({ type: "text", text: "Hello" })
+		expect(mockSend.mock.calls.map(([region, command]) => [region, command.input.modelId])).toEqual([
+			["us-east-1", "us.anthropic.claude-3-5-sonnet-20241022-v2:0"],
+			["eu-west-1", "eu.anthropic.claude-3-5-sonnet-20241022-v2:0"],
+		])
+		// The selected region isn't tried twice
+		expect(BedrockRuntimeClient).toHaveBeenCalledTimes(2)
+	})
+
+	it("tries each fallback region while requests are throttled", async () => {
+		mockSend
+			.mockRejectedValueOnce(throttlingError())
+			.mockRejectedValueOnce(throttlingError())
+			.mockResolvedValueOnce({ output: { message: { content: [{ text: "Done" }] } } })
+		const handler = new AwsBedrockHandler(options)
+
+		expect(await handler.completePrompt("Hi")).toBe("Done")
+		expect(mockSend.mock.calls.map(([region, command]) => [region, command.input.modelId])).toEqual([
+			["us-east-1", "us.anthropic.claude-3-5-sonnet-20241022-v2:0"],
+			["eu-west-1", "eu.anthropic.claude-3-5-sonnet-20241022-v2:0"],
+			["ap-northeast-1", "jp.anthropic.claude-3-5-sonnet-20241022-v2:0"],
+		])
+	})
+
+	it("does not fall back for errors other than throttling", async () => {
+		mockSend.mockRejectedValueOnce(new Error("Access denied"))
+		const handler = new AwsBedrockHandler(options)
+
+		await expect(collect(handler.createMessage("system", [{ role: "user", content: "Hi" }]))).rejects.toThrow()
+		expect(mockSend).toHaveBeenCalledTimes(1)
+	})
+
+	it("does not fall back for custom ARNs", async () => {
+		mockSend.mockRejectedValueOnce(throttlingError())
+		const handler = new AwsBedrockHandler({
+			...options,
+			awsCustomArn: "arn:aws:bedrock:us-east-1:123456789012:provisioned-model/my-model",
+		})
+
+		await expect(collect(handler.createMessage("system", [{ role: "user", content: "Hi" }]))).rejects.toThrow(
+			"Too many requests",
+		)
+		expect(mockSend).toHaveBeenCalledTimes(1)
+	})
+})
@@ -249,6 +249,15 @@ describe("GeminiHandler", () => {
 			expect(cost).toBeCloseTo(expectedCost)
 		})
 
+		it("should add the storage of a context cache for its lifetime", () => {
+			const cacheWriteTokens = 100_000
+			// Storage is priced per hour and caches are kept for five minutes
+			const expectedCost = mockInfo.cacheWritesPrice! * (cacheWriteTokens / 1_000_000) * (5 / 60)
+
+			const cost = handler.calculateCost({ info: mockInfo, inputTokens: 0, outputTokens: 0, cacheWriteTokens })
+			expect(cost).toBeCloseTo(expectedCost)
+		})
+
 		it("should return undefined if pricing info is missing", () => {
 			// Create a copy and explicitly set a price to undefined
 			const incompleteInfo: ModelInfo = { ...mockInfo, outputPrice: undefined }
@@ -257,6 +266,116 @@ describe("GeminiHandler", () => {
 		})
 	})
 
+	describe("context caching", () => {
+		const systemPrompt = "x".repeat(20_000)
+		const firstTurn: Anthropic.Messages.MessageParam[] = [
+			{ role: "user", content: "Read the file" },
+			{ role: "assistant", content: "Here it is" },
+			{ role: "user", content: "Now change it" },
+		]
+		const metadata = { taskId: "task-1" }
+
+		let mockGenerateContentStream: ReturnType<typeof vitest.fn>
+		let mockCreateCache: ReturnType<typeof vitest.fn>
+
+		const consume = async (messages: Anthropic.Messages.MessageParam[], prompt = systemPrompt) => {
+			const chunks: any[] = []
+			for await (const chunk of handler.createMessage(prompt, messages, metadata)) {
+				chunks.push(chunk)
+			}
+			return chunks
+		}
+
+		beforeEach(() => {
+			handler = new GeminiHandler({
+				apiModelId: GEMINI_MODEL_NAME,
+				geminiApiKey: "test-key",
+				geminiContextCachingEnabled: true,
+			})
+
+			mockGenerateContentStream = vitest.fn().mockImplementation(async () => ({
+				[Symbol.asyncIterator]: async function* () {
+					yield { text: "Done" }
+					yield {
+						usageMetadata: { promptTokenCount: 6000, candidatesTokenCount: 5, cachedContentTokenCount: 5000 },
+					}
+				},
+			}))
+			mockCreateCache = vitest.fn().mockResolvedValue({
+				name: "cachedContents/1",
+				usageMetadata: { totalTokenCount: 5000 },
+			})
+
+			handler["client"] = {
+				models: { generateContentStream: mockGenerateContentStream },
+				caches: { create: mockCreateCache, delete: vitest.fn().mockResolvedValue({}) },
+			} as any
+		})
+
+		it("caches the system prompt and earlier messages and sends only the newest one", async () => {
+			const chunks = await consume(firstTurn)
+
+			const cacheConfig = mockCreateCache.mock.calls[0][0].config
+			expect(cacheConfig.systemInstruction).toBe(systemPrompt)
+			expect(cacheConfig.contents).toHaveLength(2)
+			expect(cacheConfig.ttl).toBe("300s")
+
+			const params = mockGenerateContentStream.mock.calls[0][0]
+			expect(params.contents).toEqual([{ role: "user", parts: [{ text: "Now change it" }] }])
+			expect(params.config.cachedContent).toBe("cachedContents/1")
+			expect(params.config.systemInstruction).toBeUndefined()
+			expect(params.config.tools).toBeUndefined()
+
+			expect(chunks).toContainEqual(
+				expect.objectContaining({ type: "usage", cacheReadTokens: 5000, cacheWriteTokens: 5000 }),
+			)
+		})
+
+		it("reuses the cache while the conversation continues it", async () => {
+			await consume(firstTurn)
+			const chunks = await consume([
+				...firstTurn,
+				{ role: "assistant", content: "Changed" },
+				{ role: "user", content: "Thanks" },
+			])
+
+			expect(mockCreateCache).toHaveBeenCalledTimes(1)
+			const params = mockGenerateContentStream.mock.calls[1][0]
+			expect(params.contents).toHaveLength(3)
+			expect(params.config.cachedContent).toBe("cachedContents/1")
+			expect(chunks.find((chunk) => chunk.type === "usage").cacheWriteTokens).toBeUndefined()
+		})
+
+		it("replaces the cache when the system prompt changes", async () => {
+			await consume(firstTurn)
+			await consume(firstTurn, systemPrompt + " changed")
+
+			expect(mockCreateCache).toHaveBeenCalledTimes(2)
+			expect(handler["client"].caches.delete).toHaveBeenCalledWith({ name: "cachedContents/1" })
+		})
+
+		it("does not cache conversations below the minimum size", async () => {
+			await consume(firstTurn, "Short prompt")
+
+			expect(mockCreateCache).not.toHaveBeenCalled()
+			expect(mockGenerateContentStream.mock.calls[0][0].config.systemInstruction).toBe("Short prompt")
+		})
+
+		it("continues without a cache when it cannot be created", async () => {
+			mockCreateCache.mockRejectedValue(new Error("Caching not supported"))
+			const warn = vitest.spyOn(console, "warn").mockImplementation(() => {})
+
+			await consume(firstTurn)
+			await consume(firstTurn)
+
+			expect(mockCreateCache).toHaveBeenCalledTimes(1)
+			const params = mockGenerateContentStream.mock.calls[1][0]
+			expect(params.contents).toHaveLength(3)
+			expect(params.config.cachedContent).toBeUndefined()
+			warn.mockRestore()
+		})
+	})
+
 	describe("error telemetry", () => {
 		const mockMessages: Anthropic.Messages.MessageParam[] = [
 			{
@@ -0,0 +1,192 @@
+// npx vitest run src/api/providers/__tests__/llama-cpp.spec.ts
+
+const mockCreate = vitest.fn()
+
+vitest.mock("openai", () => ({
+	__esModule: true,
+	default: vitest.fn().mockImplementation(() => ({
+		chat: { completions: { create: mockCreate } },
+	})),
+}))
+
+import OpenAI from "openai"
+
+import type { ApiHandlerOptions } from "../../../shared/api"
+import { LlamaCppHandler } from "../llama-cpp"
+
+const tools: OpenAI.Chat.ChatCompletionTool[] = [
+	{
+		type: "function",
+		function: {
+			name: "read_file",
+			description: "Read a file",
+			parameters: { type: "object", properties: { path: { type: "string" } }, required: ["path"] },
+		},
+	},
+]
+
+function streamOf(chunks: any[]) {
+	return {
+		async *[Symbol.asyncIterator]() {
+			yield* chunks
+		},
+	}
+}
+
+function contentChunks(text: string, size = 7) {
+	const chunks = []
+	for (let i = 0; i < text.length; i += size) {
+		chunks.push({ choices: [{ delta: { content: text.slice(i, i + size) }, index: 0 }] })
+	}
+	return chunks
+}
+
+async function collect(stream: AsyncIterable<any>) {
+	const chunks: any[] = []
+	for await (const chunk of stream) {
+		chunks.push(chunk)
+	}
+	return chunks
+}
+
+describe("LlamaCppHandler", () => {
+	const options: ApiHandlerOptions = { llamaCppBaseUrl: "http://localhost:8080/" }
+	const mockFetch = vitest.fn()
+
+	beforeEach(() => {
+		vitest.clearAllMocks()
+		vitest.stubGlobal("fetch", mockFetch)
+		mockFetch.mockResolvedValue({
+			ok: true,
+			json: async () => ({
+				model_path: "/models/qwen2.5-coder-7b-q4_k_m.gguf",
+				default_generation_settings: { n_ctx: 16384 },
+				modalities: { vision: false },
+			}),
+		})
+	})
+
+	afterEach(() => {
+		vitest.unstubAllGlobals()
+	})
+
+	it("constrains tool calls with a JSON schema grammar", async () => {
+		const response = JSON.stringify({
+			message: "Let me read it.",
+			tool_calls: [{ name: "read_file", arguments: { path: "src/index.ts" } }],
+		})
+		mockCreate.mockResolvedValue(
+			streamOf([
+				...contentChunks(response),
+				{
+					choices: [{ delta: {}, finish_reason: "stop", index: 0 }],
+					usage: { prompt_tokens: 1200, completion_tokens: 40 },
+					timings: { cache_n: 1000 },
+				},
+			]),
+		)
+		const handler = new LlamaCppHandler({ ...options, llamaCppSlotId: 1 })
+
+		const chunks = await collect(
+			handler.createMessage("You are helpful.", [{ role: "user", content: "Read src/index.ts" }], {
+				taskId: "task",
+				tools,
+				tool_choice: "auto",
+			}),
+		)
+
+		const params = mockCreate.mock.calls[0][0]
+		expect(params.tools).toBeUndefined()
+		expect(params.json_schema.properties.tool_calls.items.anyOf[0].properties.name).toEqual({
+			const: "read_file",
+		})
+		expect(params.messages[0].content).toContain("## read_file")
+		expect(params).toMatchObject({ cache_prompt: true, id_slot: 1, model: "qwen2.5-coder-7b-q4_k_m.gguf" })
+
+		expect(
+			chunks
+				.filter((chunk) => chunk.type === "text")
+				.map((chunk) => chunk.text)
+				.join(""),
+		).toBe("Let me read it.")
+		expect(chunks).toContainEqual({
+			type: "tool_call",
+			id: expect.stringMatching(/^call_/),
+			name: "read_file",
+			arguments: JSON.stringify({ path: "src/index.ts" }),
+		})
+		expect(chunks).toContainEqual({
+			type: "usage",
+			inputTokens: 1200,
+			outputTokens: 40,
+			cacheReadTokens: 1000,
+		})
+	})
+
+	it("passes on the raw output when the grammar response is incomplete", async () => {
+		mockCreate.mockResolvedValue(streamOf(contentChunks('{"message": "Partial", "tool_calls": [{"name"')))
+		const handler = new LlamaCppHandler(options)
+
+		const chunks = await collect(
+			handler.createMessage("system", [{ role: "user", content: "Hi" }], { taskId: "task", tools }),
+		)
+
+		expect(chunks.some((chunk) => chunk.type === "tool_call")).toBe(false)
+		expect(chunks.at(-1)).toEqual({ type: "text", text: '{"message": "Partial", "tool_calls": [{"name"' })
+	})
+
+	it("uses native tool calls when grammar tool calls are disabled", async () => {
+		mockCreate.mockResolvedValue(
+			streamOf([
+				{
+					choices: [
+						{
+							delta: {
+								tool_calls: [
+									{ index: 0, id: "call_1", function: { name: "read_file", arguments: "{}" } },
+								],
+							},
+							index: 0,
+						},
+					],
+				},
+				{ choices: [{ delta: {}, finish_reason: "tool_calls", index: 0 }] },
+			]),
+		)
+		const handler = new LlamaCppHandler({ ...options, llamaCppGrammarToolCalls: false })
+
+		const chunks = await collect(
+			handler.createMessage("system", [{ role: "user", content: "Hi" }], { taskId: "task", tools }),
+		)
+
+		const params = mockCreate.mock.calls[0][0]
+		expect(params.json_schema).toBeUndefined()
+		expect(params.tools).toHaveLength(1)
+		expect(chunks).toContainEqual(
+			expect.objectContaining({ type: "tool_call_partial", id: "call_1", name: "read_file" }),
+		)
+	})
+
+	it("reads the model info from the server once", async () => {
+		mockCreate.mockResolvedValue(streamOf(contentChunks("Hello")))
+		const handler = new LlamaCppHandler(options)
+
+		await collect(handler.createMessage("system", [{ role: "user", content: "Hi" }]))
+		await collect(handler.createMessage("system", [{ role: "user", content: "Hi again" }]))
+
+		expect(mockFetch).toHaveBeenCalledTimes(1)
+		expect(mockFetch.mock.calls[0][0]).toBe("http://localhost:8080/props")
+		expect(handler.getModel().info).toMatchObject({ contextWindow: 16384, supportsImages: false })
+	})
+
+	it("falls back to the default model info when the server props are unavailable", async () => {
+		mockFetch.mockRejectedValue(new Error("ECONNREFUSED"))
+		mockCreate.mockResolvedValue(streamOf(contentChunks("Hello")))
+		const handler = new LlamaCppHandler({ ...options, llamaCppModelId: "my-model" })
+
+		const chunks = await collect(handler.createMessage("system", [{ role: "user", content: "Hi" }]))
+
+		expect(chunks.map((chunk) => chunk.text).join("")).toBe("Hello")
+		expect(handler.getModel()).toMatchObject({ id: "my-model", info: { contextWindow: 32_768 } })
+	})
+})
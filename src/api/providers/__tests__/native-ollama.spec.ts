@@ -6,10 +6,12 @@ import { getOllamaModels } from "../fetchers/ollama"
 
 // Mock the ollama package
 const mockChat = vitest.fn()
+const mockGenerate = vitest.fn()
 vitest.mock("ollama", () => {
 	return {
 		Ollama: vitest.fn().mockImplementation(() => ({
 			chat: mockChat,
+			generate: mockGenerate,
 		})),
 		Message: vitest.fn(),
 	}
@@ -134,6 +136,71 @@ describe("NativeOllamaHandler", () => {
 			)
 		})
 
+		it("should set num_ctx from the model's context window when ollamaAutoNumCtx is enabled", async () => {
+			handler = new NativeOllamaHandler({
+				ollamaModelId: "llama2",
+				ollamaBaseUrl: "http://localhost:11434",
+				ollamaAutoNumCtx: true,
+			})
+
+			mockChat.mockImplementation(async function* () {
+				yield { message: { content: "Response" } }
+			})
+
+			for await (const _ of handler.createMessage("System", [{ role: "user" as const, content: "Test" }])) {
+				// consume stream
+			}
+
+			expect(mockChat).toHaveBeenCalledWith(
+				expect.objectContaining({
+					options: expect.objectContaining({ num_ctx: 4096 }),
+				}),
+			)
+		})
+
+		it("should prefer ollamaNumCtx over the model's context window", async () => {
+			handler = new NativeOllamaHandler({
+				ollamaModelId: "llama2",
+				ollamaBaseUrl: "http://localhost:11434",
+				ollamaNumCtx: 2048,
+				ollamaAutoNumCtx: true,
+			})
+
+			mockChat.mockImplementation(async function* () {
+				yield { message: { content: "Response" } }
+			})
+
+			for await (const _ of handler.createMessage("System", [{ role: "user" as const, content: "Test" }])) {
+				// consume stream
+			}
+
+			expect(mockChat).toHaveBeenCalledWith(
+				expect.objectContaining({
+					options: expect.objectContaining({ num_ctx: 2048 }),
+				}),
+			)
+		})
+
+		it("should pass keep_alive, sending numeric values as seconds", async () => {
+			mockChat.mockImplementation(async function* () {
+				yield { message: { content: "Response" } }
+			})
+
+			for (const [ollamaKeepAlive, keepAlive] of [
+				["30m", "30m"],
+				["-1", -1],
+				[undefined, undefined],
+			] as const) {
+				handler = new NativeOllamaHandler({ ollamaModelId: "llama2", ollamaKeepAlive })
+
+				for await (const _ of handler.createMessage("System", [{ role: "user" as const, content: "Test" }])) {
+					// consume stream
+				}
+
+				expect(mockChat).toHaveBeenLastCalledWith(expect.objectContaining({ keep_alive: keepAlive }))
+			}
+		})
+
 		it("should handle DeepSeek R1 models with reasoning detection", async () => {
 			const options: ApiHandlerOptions = {
 				apiModelId: "deepseek-r1",
@@ -226,6 +293,34 @@ describe("NativeOllamaHandler", () => {
 		})
 	})
 
+	describe("preloadModel", () => {
+		it("should load the model with the chat request's num_ctx and keep_alive", async () => {
+			handler = new NativeOllamaHandler({
+				ollamaModelId: "llama2",
+				ollamaAutoNumCtx: true,
+				ollamaKeepAlive: "1h",
+				ollamaPreloadModel: true,
+			})
+			mockGenerate.mockResolvedValue({ response: "", done: true })
+
+			await handler.preloadModel()
+
+			expect(mockGenerate).toHaveBeenCalledWith({
+				model: "llama2",
+				prompt: "",
+				stream: false,
+				options: { num_ctx: 4096 },
+				keep_alive: "1h",
+			})
+		})
+
+		it("should not load the model unless ollamaPreloadModel is enabled", async () => {
+			await handler.preloadModel()
+
+			expect(mockGenerate).not.toHaveBeenCalled()
+		})
+	})
+
 	describe("error handling", () => {
 		it("should handle connection refused errors", async () => {
 			const error = new Error("ECONNREFUSED") as any
@@ -0,0 +1,173 @@
+// npx vitest run api/providers/__tests__/openai-responses.spec.ts
+
+vitest.mock("@roo-code/telemetry", () => ({
+	TelemetryService: {
+		instance: {
+			captureException: vitest.fn(),
+		},
+	},
+}))
+
+import { Anthropic } from "@anthropic-ai/sdk"
+
+import { OpenAiResponsesHandler } from "../openai-responses"
+import { ApiHandlerOptions } from "../../../shared/api"
+
+const mockResponsesCreate = vitest.fn()
+
+vitest.mock("openai", () => {
+	const client = vitest.fn().mockImplementation(() => ({
+		responses: {
+			create: mockResponsesCreate,
+		},
+	}))
+	return {
+		__esModule: true,
+		default: client,
+		AzureOpenAI: client,
+	}
+})
+
+function mockResponse(id: string, text: string) {
+	return {
+		[Symbol.asyncIterator]: async function* () {
+			yield { type: "response.output_text.delta", delta: text }
+			yield {
+				type: "response.completed",
+				response: { id, output: [], usage: { input_tokens: 10, output_tokens: 2 } },
+			}
+		},
+	}
+}
+
+async function collect(stream: AsyncIterable<any>) {
+	const chunks: any[] = []
+	for await (const chunk of stream) {
+		chunks.push(chunk)
+	}
+	return chunks
+}
+
+describe("OpenAiResponsesHandler", () => {
+	const options: ApiHandlerOptions = {
+		openAiApiKey: "test-key",
+		openAiBaseUrl: "https://example.com/v1",
+		openAiModelId: "gpt-4.1",
+		openAiUseResponsesApi: true,
+	}
+	const metadata = { taskId: "task-1" }
+	const firstTurn: Anthropic.Messages.MessageParam[] = [{ role: "user", content: "Hello" }]
+	const secondTurn: Anthropic.Messages.MessageParam[] = [
+		...firstTurn,
+		{ role: "assistant", content: "Hi" },
+		{ role: "user", content: "Next" },
+	]
+
+	beforeEach(() => {
+		mockResponsesCreate.mockReset()
+	})
+
+	it("sends the custom model to the Responses API", async () => {
+		mockResponsesCreate.mockImplementationOnce(() => mockResponse("resp_1", "Hi"))
+		const handler = new OpenAiResponsesHandler(options)
+
+		const chunks = await collect(handler.createMessage("system", firstTurn, metadata))
+
+		expect(chunks).toContainEqual({ type: "text", text: "Hi" })
+		const body = mockResponsesCreate.mock.calls[0][0]
+		expect(body.model).toBe("gpt-4.1")
+		expect(body.instructions).toBe("system")
+		expect(body.store).toBe(false)
+		expect(body.previous_response_id).toBeUndefined()
+	})
+
+	it("passes the enabled built-in tools through", async () => {
+		mockResponsesCreate.mockImplementationOnce(() => mockResponse("resp_1", "Hi"))
+		const handler = new OpenAiResponsesHandler({ ...options, openAiResponsesBuiltInTools: ["web_search"] })
+
+		await collect(
+			handler.createMessage("system", firstTurn, {
+				...metadata,
+				tools: [
+					{
+						type: "function",
+						function: { name: "read_file", parameters: { type: "object", properties: {} } },
+					},
+				],
+			}),
+		)
+
+		const { tools } = mockResponsesCreate.mock.calls[0][0]
+		expect(tools.map((tool: any) => tool.name ?? tool.type)).toEqual(["read_file", "web_search"])
+	})
+
+	it("continues from the previous response when storing is enabled", async () => {
+		mockResponsesCreate
+			.mockImplementationOnce(() => mockResponse("resp_1", "Hi"))
+			.mockImplementationOnce(() => mockResponse("resp_2", "Done"))
+		const handler = new OpenAiResponsesHandler({ ...options, openAiResponsesStoreEnabled: true })
+
+		await collect(handler.createMessage("system", firstTurn, metadata))
+		await collect(handler.createMessage("system", secondTurn, metadata))
+
+		expect(mockResponsesCreate.mock.calls[0][0].store).toBe(true)
+		const body = mockResponsesCreate.mock.calls[1][0]
+		expect(body.previous_response_id).toBe("resp_1")
+		expect(body.input).toEqual([{ role: "user", content: [{ type: "input_text", text: "Next" }] }])
+		expect(handler.getResponseId()).toBe("resp_2")
+	})
+
+	it("sends the full conversation when the history no longer matches", async () => {
+		mockResponsesCreate
+			.mockImplementationOnce(() => mockResponse("resp_1", "Hi"))
+			.mockImplementationOnce(() => mockResponse("resp_2", "Done"))
+		const handler = new OpenAiResponsesHandler({ ...options, openAiResponsesStoreEnabled: true })
+
+		await collect(handler.createMessage("system", firstTurn, metadata))
+		const condensed: Anthropic.Messages.MessageParam[] = [
+			{ role: "user", content: "Summary of the conversation" },
+			...secondTurn.slice(1),
+		]
+		await collect(handler.createMessage("system", condensed, metadata))
+
+		const body = mockResponsesCreate.mock.calls[1][0]
+		expect(body.previous_response_id).toBeUndefined()
+		expect(body.input).toHaveLength(3)
+	})
+
+	it("does not continue a response of another task", async () => {
+		mockResponsesCreate
+			.mockImplementationOnce(() => mockResponse("resp_1", "Hi"))
+			.mockImplementationOnce(() => mockResponse("resp_2", "Done"))
+		const handler = new OpenAiResponsesHandler({ ...options, openAiResponsesStoreEnabled: true })
+
+		await collect(handler.createMessage("system", firstTurn, metadata))
+		await collect(handler.createMessage("system", secondTurn, { taskId: "task-2" }))
+
+		expect(mockResponsesCreate.mock.calls[1][0].previous_response_id).toBeUndefined()
+	})
+
+	it("retries with the full conversation when the previous response is gone", async () => {
+		const mockFetch = vitest.fn().mockResolvedValue({
+			ok: false,
+			status: 400,
+			text: async () => JSON.stringify({ error: { message: "Previous response not found" } }),
+		})
+		global.fetch = mockFetch as any
+		mockResponsesCreate
+			.mockImplementationOnce(() => mockResponse("resp_1", "Hi"))
+			.mockRejectedValueOnce(new Error("Previous response not found"))
+			.mockImplementationOnce(() => mockResponse("resp_3", "Done"))
+		const handler = new OpenAiResponsesHandler({ ...options, openAiResponsesStoreEnabled: true })
+
+		await collect(handler.createMessage("system", firstTurn, metadata))
+		const chunks = await collect(handler.createMessage("system", secondTurn, metadata))
+
+		expect(mockFetch).toHaveBeenCalledWith("https://example.com/v1/responses", expect.anything())
+		expect(mockFetch.mock.calls[0][1].headers.Authorization).toBe("Bearer test-key")
+		const retry = mockResponsesCreate.mock.calls[2][0]
+		expect(retry.previous_response_id).toBeUndefined()
+		expect(retry.input).toHaveLength(3)
+		expect(chunks).toContainEqual({ type: "text", text: "Done" })
+	})
+})
@@ -19,6 +19,7 @@ import {
 	type ProviderSettings,
 	type BedrockModelId,
 	type BedrockServiceTier,
+	type BedrockInferenceProfile,
 	bedrockDefaultModelId,
 	bedrockModels,
 	bedrockDefaultPromptRouterModelId,
@@ -41,6 +42,7 @@ import { Package } from "../../shared/package"
 import { MultiPointStrategy } from "../transform/cache-strategy/multi-point-strategy"
 import { ModelInfo as CacheModelInfo } from "../transform/cache-strategy/types"
 import { convertToBedrockConverseMessages as sharedConverter } from "../transform/bedrock-converse-format"
+import { BedrockControlPlaneClient } from "./utils/bedrock-control-plane"
 import { getModelParams } from "../transform/model-params"
 import { shouldUseReasoningBudget } from "../../shared/api"
 import { normalizeToolSchema } from "../../utils/json-schema"
@@ -201,6 +203,8 @@ export class AwsBedrockHandler extends BaseProvider implements SingleCompletionH
 	private client: BedrockRuntimeClient
 	private arnInfo: any
 	private readonly providerName = "Bedrock"
+	// Settles once the model behind an application inference profile ARN has been looked up
+	private applicationInferenceProfileResolution?: Promise<void>
 
 	constructor(options: ProviderSettings) {
 		super()
@@ -251,39 +255,16 @@ export class AwsBedrockHandler extends BaseProvider implements SingleCompletionH
 
 		this.costModelConfig = this.getModel()
 
-		const clientConfig: BedrockRuntimeClientConfig = {
-			userAgentAppId: `RooCode#${Package.version}`,
-			region: this.options.awsRegion,
+		if (this.arnInfo?.modelType === "application-inference-profile") {
+			this.applicationInferenceProfileResolution = this.resolveApplicationInferenceProfile()
+		}
+
+		this.client = new BedrockRuntimeClient({
+			...createBedrockClientConfig(this.options),
 			// Add the endpoint configuration when specified and enabled
 			...(this.options.awsBedrockEndpoint &&
 				this.options.awsBedrockEndpointEnabled && { endpoint: this.options.awsBedrockEndpoint }),
-		}
-
-		if (this.options.awsUseApiKey && this.options.awsApiKey) {
-			// Use API key/token-based authentication if enabled and API key is set
-			clientConfig.token = { token: this.options.awsApiKey }
-			clientConfig.authSchemePreference = ["httpBearerAuth"] // Otherwise there's no end of credential problems.
-			clientConfig.requestHandler = {
-				// This should be the default anyway, but without setting something
-				// this provider fails to work with LiteLLM passthrough.
-				requestTimeout: 0,
-			}
-		} else if (this.options.awsUseProfile && this.options.awsProfile) {
-			// Use profile-based credentials if enabled and profile is set
-			clientConfig.credentials = fromIni({
-				profile: this.options.awsProfile,
-				ignoreCache: true,
-			})
-		} else if (this.options.awsAccessKey && this.options.awsSecretKey) {
-			// Use direct credentials if provided
-			clientConfig.credentials = {
-				accessKeyId: this.options.awsAccessKey,
-				secretAccessKey: this.options.awsSecretKey,
-				...(this.options.awsSessionToken ? { sessionToken: this.options.awsSessionToken } : {}),
-			}
-		}
-
-		this.client = new BedrockRuntimeClient(clientConfig)
+		})
 	}
 
 	// Helper to guess model info from custom modelId string if not in bedrockModels
@@ -356,6 +337,8 @@ export class AwsBedrockHandler extends BaseProvider implements SingleCompletionH
 			}
 		},
 	): ApiStream {
+		await this.applicationInferenceProfileResolution
+
 		const modelConfig = this.getModel()
 		const usePromptCache = Boolean(
 			(this.options.awsUsePromptCache ?? true) && this.supportsAwsPromptCache(modelConfig),
@@ -483,10 +466,9 @@ export class AwsBedrockHandler extends BaseProvider implements SingleCompletionH
 				10 * 60 * 1000,
 			)
 
-			const command = new ConverseStreamCommand(payload)
-			const response = await this.client.send(command, {
-				abortSignal: controller.signal,
-			})
+			const response = await this.sendWithRegionFallback(payload.modelId, (client, modelId) =>
+				client.send(new ConverseStreamCommand({ ...payload, modelId }), { abortSignal: controller.signal }),
+			)
 
 			if (!response.stream) {
 				clearTimeout(timeoutId)
@@ -736,6 +718,8 @@ export class AwsBedrockHandler extends BaseProvider implements SingleCompletionH
 
 	async completePrompt(prompt: string): Promise<string> {
 		try {
+			await this.applicationInferenceProfileResolution
+
 			const modelConfig = this.getModel()
 
 			// For completePrompt, thinking is typically not used, but we should still check
@@ -770,8 +754,9 @@ export class AwsBedrockHandler extends BaseProvider implements SingleCompletionH
 				inferenceConfig,
 			}
 
-			const command = new ConverseCommand(payload)
-			const response = await this.client.send(command)
+			const response = await this.sendWithRegionFallback(payload.modelId, (client, modelId) =>
+				client.send(new ConverseCommand({ ...payload, modelId })),
+			)
 
 			if (
 				response?.output?.message?.content &&
@@ -1048,6 +1033,36 @@ export class AwsBedrockHandler extends BaseProvider implements SingleCompletionH
 		return model
 	}
 
+	/**
+	 * Looks up the foundation model an application inference profile routes to, so that pricing,
+	 * context window and capabilities are those of the model while requests keep being sent to
+	 * the profile ARN and are attributed to it.
+	 */
+	private async resolveApplicationInferenceProfile(): Promise<void> {
+		try {
+			const client = new BedrockControlPlaneClient(createBedrockClientConfig(this.options))
+			const profile = await client.getInferenceProfile(this.options.awsCustomArn as string)
+
+			// Profiles copied from a cross-region profile list the same model once per region
+			const modelArn = profile.models?.[0]?.modelArn
+			if (!modelArn) {
+				return
+			}
+
+			const modelArnInfo = this.parseArn(modelArn)
+			const model = this.getModelById(modelArnInfo.modelId as string, modelArnInfo.modelType)
+			model.id = this.options.awsCustomArn as string
+			this.costModelConfig = model
+		} catch (error) {
+			// Keep the model info guessed from the ARN, the profile may still be invoked
+			logger.warn("Failed to look up the model of the application inference profile", {
+				ctx: "bedrock",
+				customArn: this.options.awsCustomArn,
+				error: error instanceof Error ? error : String(error),
+			})
+		}
+	}
+
 	override getModel(): {
 		id: BedrockModelId | string
 		info: ModelInfo
@@ -1292,6 +1307,81 @@ export class AwsBedrockHandler extends BaseProvider implements SingleCompletionH
 		return false
 	}
 
+	/************************************************************************************
+	 *
+	 *     REGION FALLBACK
+	 *
+	 *************************************************************************************/
+
+	private fallbackClients = new Map<string, BedrockRuntimeClient>()
+
+	/**
+	 * Sends a request in the selected region and, while the request is throttled, in each of the
+	 * configured fallback regions in turn.
+	 */
+	private async sendWithRegionFallback<T>(
+		modelId: string,
+		send: (client: BedrockRuntimeClient, modelId: string) => Promise<T>,
+	): Promise<T> {
+		try {
+			return await send(this.client, modelId)
+		} catch (error) {
+			let lastError = error
+
+			for (const region of this.getFallbackRegions()) {
+				if (this.getErrorType(lastError) !== "THROTTLING") {
+					break
+				}
+
+				logger.info("Bedrock request was throttled, retrying in fallback region", {
+					ctx: "bedrock",
+					selectedRegion: this.options.awsRegion,
+					fallbackRegion: region,
+				})
+
+				try {
+					return await send(this.getFallbackClient(region), this.getModelIdForRegion(modelId, region))
+				} catch (fallbackError) {
+					lastError = fallbackError
+				}
+			}
+
+			throw lastError
+		}
+	}
+
+	private getFallbackRegions(): string[] {
+		// ARNs and VPC endpoints belong to a single region
+		if (this.options.awsCustomArn || (this.options.awsBedrockEndpointEnabled && this.options.awsBedrockEndpoint)) {
+			return []
+		}
+
+		return (this.options.awsFallbackRegions ?? []).filter((region) => region && region !== this.options.awsRegion)
+	}
+
+	private getFallbackClient(region: string): BedrockRuntimeClient {
+		let client = this.fallbackClients.get(region)
+
+		if (!client) {
+			client = new BedrockRuntimeClient(createBedrockClientConfig(this.options, region))
+			this.fallbackClients.set(region, client)
+		}
+
+		return client
+	}
+
+	// Cross-region inference profiles are prefixed with the geography of the region they are invoked in
+	private getModelIdForRegion(modelId: string, region: string): string {
+		const baseModelId = this.parseBaseModelId(modelId)
+
+		if (baseModelId === modelId || modelId.startsWith("global.")) {
+			return modelId
+		}
+
+		const prefix = AwsBedrockHandler.getPrefixForRegion(region)
+		return prefix ? `${prefix}${baseModelId}` : baseModelId
+	}
+
 	/************************************************************************************
 	 *
 	 *     ERROR HANDLING
@@ -1582,3 +1672,77 @@ Please check:
 		}
 	}
 }
+
+/************************************************************************************
+ *
+ *     CLIENT CONFIGURATION
+ *
+ *************************************************************************************/
+
+function createBedrockClientConfig(
+	options: ProviderSettings,
+	region: string | undefined = options.awsRegion,
+): BedrockRuntimeClientConfig {
+	const clientConfig: BedrockRuntimeClientConfig = {
+		userAgentAppId: `RooCode#${Package.version}`,
+		region,
+	}
+
+	if (options.awsUseApiKey && options.awsApiKey) {
+		// Use API key/token-based authentication if enabled and API key is set
+		clientConfig.token = { token: options.awsApiKey }
+		clientConfig.authSchemePreference = ["httpBearerAuth"] // Otherwise there's no end of credential problems.
+		clientConfig.requestHandler = {
+			// This should be the default anyway, but without setting something
+			// this provider fails to work with LiteLLM passthrough.
+			requestTimeout: 0,
+		}
+	} else if (options.awsUseProfile && options.awsProfile) {
+		// Use profile-based credentials if enabled and profile is set
+		clientConfig.credentials = fromIni({
+			profile: options.awsProfile,
+			ignoreCache: true,
+		})
+	} else if (options.awsAccessKey && options.awsSecretKey) {
+		// Use direct credentials if provided
+		clientConfig.credentials = {
+			accessKeyId: options.awsAccessKey,
+			secretAccessKey: options.awsSecretKey,
+			...(options.awsSessionToken ? { sessionToken: options.awsSessionToken } : {}),
+		}
+	}
+
+	return clientConfig
+}
+
+/**
+ * Lists the application inference profiles of the configured account and region, so they can be
+ * picked instead of entering their ARN.
+ */
+export async function getBedrockApplicationInferenceProfiles(
+	options: ProviderSettings,
+): Promise<BedrockInferenceProfile[]> {
+	const client = new BedrockControlPlaneClient(createBedrockClientConfig(options))
+	const profiles: BedrockInferenceProfile[] = []
+	let nextToken: string | undefined
+
+	do {
+		const response = await client.listInferenceProfiles({ typeEquals: "APPLICATION", nextToken })
+
+		for (const profile of response.inferenceProfileSummaries ?? []) {
+			if (!profile.inferenceProfileArn) {
+				continue
+			}
+
+			profiles.push({
+				arn: profile.inferenceProfileArn,
+				name: profile.inferenceProfileName || profile.inferenceProfileArn,
+				modelId: profile.models?.[0]?.modelArn?.split("/").pop(),
+			})
+		}
+
+		nextToken = response.nextToken
+	} while (nextToken)
+
+	return profiles
+}
@@ -1,6 +1,8 @@
+import { createHash } from "crypto"
 import type { Anthropic } from "@anthropic-ai/sdk"
 import {
 	GoogleGenAI,
+	type Content,
 	type GenerateContentResponseUsageMetadata,
 	type GenerateContentParameters,
 	type GenerateContentConfig,
@@ -33,6 +35,22 @@ type GeminiHandlerOptions = ApiHandlerOptions & {
 	isVertex?: boolean
 }
 
+// Explicit caches are kept for a few minutes and replaced as the conversation grows
+const CONTEXT_CACHE_TTL_SECONDS = 300
+const CONTEXT_CACHE_EXPIRY_MARGIN_MS = 30_000
+// Number of uncached contents after which the cache is replaced by one covering them
+const CONTEXT_CACHE_REFRESH_CONTENTS = 10
+// Smallest cache accepted by the API across Gemini models, unless the model specifies its own
+const CONTEXT_CACHE_MIN_TOKENS = 4096
+
+interface GeminiContextCache {
+	name: string
+	prefixHash: string // Model, system instruction and tools the cache was created with
+	contentCount: number
+	contentsHash: string
+	expiresAt: number
+}
+
 export class GeminiHandler extends BaseProvider implements SingleCompletionHandler {
 	protected options: ApiHandlerOptions
 
@@ -40,6 +58,9 @@ export class GeminiHandler extends BaseProvider implements SingleCompletionHandl
 	private lastThoughtSignature?: string
 	private lastResponseId?: string
 	private readonly providerName = "Gemini"
+	// Explicit context caches by task id
+	private contextCaches = new Map<string, GeminiContextCache>()
+	private contextCacheFailedTasks = new Set<string>()
 
 	constructor({ isVertex, ...options }: GeminiHandlerOptions) {
 		super()
@@ -201,7 +222,22 @@ export class GeminiHandler extends BaseProvider implements SingleCompletionHandl
 			}
 		}
 
-		const params: GenerateContentParameters = { model, contents, config }
+		const contextCache = await this.getContextCache(model, info, contents, config, metadata?.taskId)
+
+		// Requests that use a cache must not repeat the instruction and tools stored in it
+		const params: GenerateContentParameters = contextCache
+			? {
+					model,
+					contents: contents.slice(contextCache.contentCount),
+					config: {
+						...config,
+						systemInstruction: undefined,
+						tools: undefined,
+						toolConfig: undefined,
+						cachedContent: contextCache.name,
+					},
+				}
+			: { model, contents, config }
 
 		try {
 			const result = await this.client.models.generateContentStream(params)
@@ -316,6 +352,7 @@ export class GeminiHandler extends BaseProvider implements SingleCompletionHandl
 				const inputTokens = lastUsageMetadata.promptTokenCount ?? 0
 				const outputTokens = lastUsageMetadata.candidatesTokenCount ?? 0
 				const cacheReadTokens = lastUsageMetadata.cachedContentTokenCount
+				const cacheWriteTokens = contextCache?.writeTokens
 				const reasoningTokens = lastUsageMetadata.thoughtsTokenCount
 
 				yield {
@@ -323,12 +360,14 @@ export class GeminiHandler extends BaseProvider implements SingleCompletionHandl
 					inputTokens,
 					outputTokens,
 					cacheReadTokens,
+					cacheWriteTokens,
 					reasoningTokens,
 					totalCost: this.calculateCost({
 						info,
 						inputTokens,
 						outputTokens,
 						cacheReadTokens,
+						cacheWriteTokens,
 						reasoningTokens,
 					}),
 				}
@@ -373,6 +412,97 @@ export class GeminiHandler extends BaseProvider implements SingleCompletionHandl
 		return { id: id.endsWith(":thinking") ? id.replace(":thinking", "") : id, info, ...params }
 	}
 
+	/**
+	 * Returns an explicit context cache holding the system instruction, tools and all but the
+	 * newest contents of the task's conversation, creating one when none can be reused.
+	 *
+	 * The cache of the previous request is reused while the conversation still starts with its
+	 * contents and few contents were added since; otherwise a new cache replaces it. Conversations
+	 * that are too small to be cached are sent without one, and caching errors never fail the request.
+	 */
+	private async getContextCache(
+		model: string,
+		info: ModelInfo,
+		contents: Content[],
+		config: GenerateContentConfig,
+		taskId: string | undefined,
+	): Promise<(GeminiContextCache & { writeTokens?: number }) | undefined> {
+		if (
+			!this.options.geminiContextCachingEnabled ||
+			!info.supportsPromptCache ||
+			!taskId ||
+			this.contextCacheFailedTasks.has(taskId) ||
+			contents.length < 2
+		) {
+			return undefined
+		}
+
+		const prefixHash = hashJson([model, config.systemInstruction, config.tools, config.toolConfig])
+		const existing = this.contextCaches.get(taskId)
+
+		if (
+			existing &&
+			existing.prefixHash === prefixHash &&
+			existing.expiresAt - Date.now() > CONTEXT_CACHE_EXPIRY_MARGIN_MS &&
+			contents.length > existing.contentCount &&
+			contents.length - existing.contentCount <= CONTEXT_CACHE_REFRESH_CONTENTS &&
+			hashJson(contents.slice(0, existing.contentCount)) === existing.contentsHash
+		) {
+			return existing
+		}
+
+		// The newest message is sent with the request, which needs at least one content
+		const cachedContents = contents.slice(0, -1)
+		const estimatedTokens = Math.ceil(
+			JSON.stringify([config.systemInstruction, config.tools, cachedContents]).length / 4,
+		)
+
+		if (estimatedTokens < (info.minTokensPerCachePoint ?? CONTEXT_CACHE_MIN_TOKENS)) {
+			return undefined
+		}
+
+		try {
+			const cache = await this.client.caches.create({
+				model,
+				config: {
+					contents: cachedContents,
+					systemInstruction: config.systemInstruction,
+					tools: config.tools,
+					toolConfig: config.toolConfig,
+					ttl: `${CONTEXT_CACHE_TTL_SECONDS}s`,
+					httpOptions: config.httpOptions,
+				},
+			})
+
+			if (!cache.name) {
+				return undefined
+			}
+
+			const created: GeminiContextCache = {
+				name: cache.name,
+				prefixHash,
+				contentCount: cachedContents.length,
+				contentsHash: hashJson(cachedContents),
+				expiresAt: cache.expireTime
+					? Date.parse(cache.expireTime)
+					: Date.now() + CONTEXT_CACHE_TTL_SECONDS * 1000,
+			}
+			this.contextCaches.set(taskId, created)
+
+			if (existing) {
+				this.client.caches.delete({ name: existing.name }).catch(() => {})
+			}
+
+			return { ...created, writeTokens: cache.usageMetadata?.totalTokenCount ?? estimatedTokens }
+		} catch (error) {
+			// E.g. a model or region without explicit caching; the task continues with implicit caching
+			this.contextCacheFailedTasks.add(taskId)
+			const message = error instanceof Error ? error.message : String(error)
+			console.warn(`[GeminiHandler] Failed to create context cache: ${message}`)
+			return undefined
+		}
+	}
+
 	private extractGroundingSources(groundingMetadata?: GroundingMetadata): GroundingSource[] {
 		const chunks = groundingMetadata?.groundingChunks
 
@@ -468,18 +598,22 @@ export class GeminiHandler extends BaseProvider implements SingleCompletionHandl
 		inputTokens,
 		outputTokens,
 		cacheReadTokens = 0,
+		cacheWriteTokens = 0,
 		reasoningTokens = 0,
 	}: {
 		info: ModelInfo
 		inputTokens: number
 		outputTokens: number
 		cacheReadTokens?: number
+		cacheWriteTokens?: number
 		reasoningTokens?: number
 	}) {
 		// For models with tiered pricing, prices might only be defined in tiers
 		let inputPrice = info.inputPrice
 		let outputPrice = info.outputPrice
 		let cacheReadsPrice = info.cacheReadsPrice
+		// Explicit caches are billed for storage per hour rather than for writing
+		const cacheStoragePrice = info.cacheWritesPrice ?? 0
 
 		// If there's tiered pricing then adjust the input and output token prices
 		// based on the input tokens used.
@@ -510,10 +644,11 @@ export class GeminiHandler extends BaseProvider implements SingleCompletionHandl
 		const billedOutputTokens = outputTokens + reasoningTokens
 
 		let cacheReadCost = cacheReadTokens > 0 ? cacheReadsPrice * (cacheReadTokens / 1_000_000) : 0
+		const cacheWriteCost = cacheStoragePrice * (cacheWriteTokens / 1_000_000) * (CONTEXT_CACHE_TTL_SECONDS / 3600)
 
 		const inputTokensCost = inputPrice * (uncachedInputTokens / 1_000_000)
 		const outputTokensCost = outputPrice * (billedOutputTokens / 1_000_000)
-		const totalCost = inputTokensCost + outputTokensCost + cacheReadCost
+		const totalCost = inputTokensCost + outputTokensCost + cacheReadCost + cacheWriteCost
 
 		const trace: Record<string, { price: number; tokens: number; cost: number }> = {
 			input: { price: inputPrice, tokens: uncachedInputTokens, cost: inputTokensCost },
@@ -524,6 +659,14 @@ export class GeminiHandler extends BaseProvider implements SingleCompletionHandl
 			trace.cacheRead = { price: cacheReadsPrice, tokens: cacheReadTokens, cost: cacheReadCost }
 		}
 
+		if (cacheWriteTokens > 0) {
+			trace.cacheWrite = { price: cacheStoragePrice, tokens: cacheWriteTokens, cost: cacheWriteCost }
+		}
+
 		return totalCost
 	}
 }
+
+function hashJson(value: unknown): string {
+	return createHash("sha256").update(JSON.stringify(value)).digest("hex")
+}
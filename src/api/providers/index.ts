@@ -6,10 +6,12 @@ export { MoonshotHandler } from "./moonshot"
 export { FakeAIHandler } from "./fake-ai"
 export { GeminiHandler } from "./gemini"
 export { LiteLLMHandler } from "./lite-llm"
+export { LlamaCppHandler } from "./llama-cpp"
 export { LmStudioHandler } from "./lm-studio"
 export { MistralHandler } from "./mistral"
 export { OpenAiCodexHandler } from "./openai-codex"
 export { OpenAiNativeHandler } from "./openai-native"
+export { OpenAiResponsesHandler } from "./openai-responses"
 export { OpenAiHandler } from "./openai"
 export { OpenAICompatibleHandler } from "./openai-compatible"
 export type { OpenAICompatibleConfig } from "./openai-compatible"
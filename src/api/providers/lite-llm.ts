@@ -9,6 +9,7 @@ import { ApiHandlerOptions } from "../../shared/api"
 
 import { ApiStream, ApiStreamUsageChunk } from "../transform/stream"
 import { convertToOpenAiMessages } from "../transform/openai-format"
+import { addPromptCacheBreakpoints } from "../transform/caching/breakpoints"
 import { sanitizeOpenAiCallId } from "../../utils/tool-id"
 
 import type { SingleCompletionHandler, ApiHandlerCreateMessageMetadata } from "../index"
@@ -124,7 +125,15 @@ export class LiteLLMHandler extends RouterProvider implements SingleCompletionHa
 		let systemMessage: OpenAI.Chat.ChatCompletionMessageParam
 		let enhancedMessages: OpenAI.Chat.ChatCompletionMessageParam[]
 
-		if (this.options.litellmUsePromptCache && info.supportsPromptCache) {
+		if (this.options.litellmPromptCacheBreakpoints) {
+			const [cachedSystemMessage, ...cachedMessages] = addPromptCacheBreakpoints(
+				systemPrompt,
+				openAiMessages,
+				this.options.litellmPromptCacheBreakpoints,
+			)
+			systemMessage = cachedSystemMessage
+			enhancedMessages = cachedMessages
+		} else if (this.options.litellmUsePromptCache && info.supportsPromptCache) {
 			// Create system message with cache control in the proper format
 			systemMessage = {
 				role: "system",
@@ -0,0 +1,267 @@
+import { randomUUID } from "crypto"
+import { Anthropic } from "@anthropic-ai/sdk"
+import OpenAI from "openai"
+
+import {
+	type ModelInfo,
+	LLAMA_CPP_DEFAULT_BASE_URL,
+	LLAMA_CPP_DEFAULT_TEMPERATURE,
+	llamaCppDefaultModelInfo,
+} from "@roo-code/types"
+
+import type { ApiHandlerOptions } from "../../shared/api"
+
+import { NativeToolCallParser } from "../../core/assistant-message/NativeToolCallParser"
+import { TagMatcher } from "../../utils/tag-matcher"
+
+import { convertToOpenAiMessages } from "../transform/openai-format"
+import {
+	GrammarResponseParser,
+	buildToolCallSchema,
+	convertToGrammarMessages,
+	formatToolsForPrompt,
+} from "../transform/tool-call-grammar"
+import { ApiStream } from "../transform/stream"
+
+import { BaseProvider } from "./base-provider"
+import type { SingleCompletionHandler, ApiHandlerCreateMessageMetadata } from "../index"
+import { getApiRequestTimeout } from "./utils/timeout-config"
+import { handleOpenAIError } from "./utils/openai-error-handler"
+
+// Fields llama-server accepts on top of the OpenAI chat completion parameters
+type LlamaCppRequestParams = {
+	// Reuse the KV cache of the slot for the common prefix of the prompt
+	cache_prompt?: boolean
+	id_slot?: number
+	json_schema?: Record<string, unknown>
+}
+
+type LlamaCppChunk = OpenAI.Chat.ChatCompletionChunk & {
+	// Number of prompt tokens that were taken from the KV cache
+	timings?: { cache_n?: number }
+}
+
+type LlamaCppDelta = OpenAI.Chat.ChatCompletionChunk.Choice.Delta & { reasoning_content?: string }
+
+interface LlamaCppServerProps {
+	model_path?: string
+	default_generation_settings?: { n_ctx?: number }
+	modalities?: { vision?: boolean }
+}
+
+export class LlamaCppHandler extends BaseProvider implements SingleCompletionHandler {
+	protected options: ApiHandlerOptions
+	private client: OpenAI
+	private readonly providerName = "llama.cpp"
+	private readonly baseUrl: string
+	private serverModel: { id?: string; info: Partial<ModelInfo> } = { info: {} }
+	private serverPropsRequest?: Promise<void>
+
+	constructor(options: ApiHandlerOptions) {
+		super()
+		this.options = options
+		this.baseUrl = (this.options.llamaCppBaseUrl || LLAMA_CPP_DEFAULT_BASE_URL).replace(/\/+$/, "")
+
+		this.client = new OpenAI({
+			baseURL: `${this.baseUrl}/v1`,
+			// llama-server only checks the key when started with --api-key
+			apiKey: this.options.llamaCppApiKey || "noop",
+			timeout: getApiRequestTimeout(),
+		})
+	}
+
+	override async *createMessage(
+		systemPrompt: string,
+		messages: Anthropic.Messages.MessageParam[],
+		metadata?: ApiHandlerCreateMessageMetadata,
+	): ApiStream {
+		await this.loadServerProps()
+
+		const tools = metadata?.tools ?? []
+		const useGrammar =
+			(this.options.llamaCppGrammarToolCalls ?? true) && tools.length > 0 && metadata?.tool_choice !== "none"
+
+		const params: OpenAI.Chat.ChatCompletionCreateParamsStreaming & LlamaCppRequestParams = {
+			model: this.getModel().id,
+			messages: useGrammar
+				? [
+						{ role: "system", content: `${systemPrompt}\n\n${formatToolsForPrompt(tools)}` },
+						...convertToGrammarMessages(messages),
+					]
+				: [{ role: "system", content: systemPrompt }, ...convertToOpenAiMessages(messages)],
+			temperature: this.options.modelTemperature ?? LLAMA_CPP_DEFAULT_TEMPERATURE,
+			stream: true,
+			stream_options: { include_usage: true },
+			cache_prompt: true,
+			...(this.options.llamaCppSlotId !== undefined && { id_slot: this.options.llamaCppSlotId }),
+			...(useGrammar
+				? { json_schema: buildToolCallSchema(tools, metadata?.parallelToolCalls ?? true) }
+				: {
+						tools: this.convertToolsForOpenAI(metadata?.tools),
+						tool_choice: metadata?.tool_choice,
+						parallel_tool_calls: metadata?.parallelToolCalls ?? true,
+					}),
+		}
+
+		let stream
+		try {
+			stream = await this.client.chat.completions.create(params)
+		} catch (error) {
+			throw handleOpenAIError(error, this.providerName)
+		}
+
+		const matcher = new TagMatcher(
+			"think",
+			(chunk) =>
+				({
+					type: chunk.matched ? "reasoning" : "text",
+					text: chunk.data,
+				}) as const,
+		)
+		const grammarParser = useGrammar ? new GrammarResponseParser() : undefined
+		let usage: OpenAI.CompletionUsage | undefined
+		let cacheReadTokens: number | undefined
+
+		for await (const chunk of stream as AsyncIterable<LlamaCppChunk>) {
+			const delta = chunk.choices[0]?.delta as LlamaCppDelta | undefined
+			const finishReason = chunk.choices[0]?.finish_reason
+
+			if (delta?.reasoning_content) {
+				yield { type: "reasoning", text: delta.reasoning_content }
+			}
+
+			if (delta?.content) {
+				if (grammarParser) {
+					const text = grammarParser.update(delta.content)
+					if (text) {
+						yield { type: "text", text }
+					}
+				} else {
+					yield* matcher.update(delta.content)
+				}
+			}
+
+			if (delta?.tool_calls) {
+				for (const toolCall of delta.tool_calls) {
+					yield {
+						type: "tool_call_partial",
+						index: toolCall.index,
+						id: toolCall.id,
+						name: toolCall.function?.name,
+						arguments: toolCall.function?.arguments,
+					}
+				}
+			}
+
+			if (finishReason && !grammarParser) {
+				yield* NativeToolCallParser.processFinishReason(finishReason)
+			}
+
+			if (chunk.usage) {
+				usage = chunk.usage
+			}
+
+			if (chunk.timings?.cache_n !== undefined) {
+				cacheReadTokens = chunk.timings.cache_n
+			}
+		}
+
+		if (grammarParser) {
+			yield* this.emitGrammarResponse(grammarParser)
+		} else {
+			yield* matcher.final()
+		}
+
+		if (usage) {
+			yield {
+				type: "usage",
+				inputTokens: usage.prompt_tokens || 0,
+				outputTokens: usage.completion_tokens || 0,
+				cacheReadTokens,
+			}
+		}
+	}
+
+	private *emitGrammarResponse(parser: GrammarResponseParser): ApiStream {
+		const response = parser.final()
+
+		// Without a complete response the raw output is passed on, so the missing tool call is
+		// reported and the request retried like for any other provider.
+		if (!response) {
+			yield { type: "text", text: parser.rawText }
+			return
+		}
+
+		if (response.message) {
+			yield { type: "text", text: response.message }
+		}
+
+		for (const toolCall of response.toolCalls) {
+			yield {
+				type: "tool_call",
+				id: `call_${randomUUID()}`,
+				name: toolCall.name,
+				arguments: JSON.stringify(toolCall.arguments ?? {}),
+			}
+		}
+	}
+
+	/**
+	 * Reads the context size and capabilities of the loaded model from the server once.
+	 */
+	private loadServerProps(): Promise<void> {
+		this.serverPropsRequest ??= (async () => {
+			try {
+				const apiKey = this.options.llamaCppApiKey
+				const response = await fetch(`${this.baseUrl}/props`, {
+					headers: apiKey ? { Authorization: `Bearer ${apiKey}` } : {},
+				})
+
+				if (!response.ok) {
+					return
+				}
+
+				const props = (await response.json()) as LlamaCppServerProps
+				const contextWindow = props.default_generation_settings?.n_ctx
+
+				this.serverModel = {
+					id: props.model_path?.split(/[\\/]/).pop(),
+					info: {
+						...(contextWindow && { contextWindow }),
+						...(props.modalities?.vision !== undefined && { supportsImages: props.modalities.vision }),
+					},
+				}
+			} catch (error) {
+				// Older servers don't expose /props, the defaults are used instead
+				console.debug("[llama.cpp] Failed to read server props:", error)
+			}
+		})()
+
+		return this.serverPropsRequest
+	}
+
+	override getModel(): { id: string; info: ModelInfo } {
+		const info = { ...llamaCppDefaultModelInfo, ...this.serverModel.info }
+
+		return {
+			id: this.options.llamaCppModelId || this.serverModel.id || "llama.cpp",
+			info: { ...info, maxTokens: Math.min(info.maxTokens ?? 8192, Math.floor(info.contextWindow / 4)) },
+		}
+	}
+
+	async completePrompt(prompt: string): Promise<string> {
+		let response
+		try {
+			response = await this.client.chat.completions.create({
+				model: this.getModel().id,
+				messages: [{ role: "user", content: prompt }],
+				temperature: this.options.modelTemperature ?? LLAMA_CPP_DEFAULT_TEMPERATURE,
+				stream: false,
+			})
+		} catch (error) {
+			throw handleOpenAIError(error, this.providerName)
+		}
+
+		return response.choices[0]?.message.content || ""
+	}
+}
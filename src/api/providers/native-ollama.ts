@@ -7,7 +7,7 @@ import { BaseProvider } from "./base-provider"
 import type { ApiHandlerOptions } from "../../shared/api"
 import { getOllamaModels } from "./fetchers/ollama"
 import { TagMatcher } from "../../utils/tag-matcher"
-import type { SingleCompletionHandler, ApiHandlerCreateMessageMetadata } from "../index"
+import type { SingleCompletionHandler, ModelPreloadHandler, ApiHandlerCreateMessageMetadata } from "../index"
 
 interface OllamaChatOptions {
 	temperature: number
@@ -145,7 +145,7 @@ function convertToOllamaMessages(anthropicMessages: Anthropic.Messages.MessagePa
 	return ollamaMessages
 }
 
-export class NativeOllamaHandler extends BaseProvider implements SingleCompletionHandler {
+export class NativeOllamaHandler extends BaseProvider implements SingleCompletionHandler, ModelPreloadHandler {
 	protected options: ApiHandlerOptions
 	private client: Ollama | undefined
 	protected models: Record<string, ModelInfo> = {}
@@ -207,7 +207,6 @@ export class NativeOllamaHandler extends BaseProvider implements SingleCompletio
 	): ApiStream {
 		const client = this.ensureClient()
 		const { id: modelId } = await this.fetchModel()
-		const useR1Format = modelId.toLowerCase().includes("deepseek-r1")
 
 		const ollamaMessages: Message[] = [
 			{ role: "system", content: systemPrompt },
@@ -224,22 +223,13 @@ export class NativeOllamaHandler extends BaseProvider implements SingleCompletio
 		)
 
 		try {
-			// Build options object conditionally
-			const chatOptions: OllamaChatOptions = {
-				temperature: this.options.modelTemperature ?? (useR1Format ? DEEP_SEEK_DEFAULT_TEMPERATURE : 0),
-			}
-
-			// Only include num_ctx if explicitly set via ollamaNumCtx
-			if (this.options.ollamaNumCtx !== undefined) {
-				chatOptions.num_ctx = this.options.ollamaNumCtx
-			}
-
 			// Create the actual API request promise
 			const stream = await client.chat({
 				model: modelId,
 				messages: ollamaMessages,
 				stream: true,
-				options: chatOptions,
+				options: this.getChatOptions(),
+				keep_alive: this.getKeepAlive(),
 				tools: this.convertToolsToOllama(metadata?.tools),
 			})
 
@@ -344,27 +334,81 @@ export class NativeOllamaHandler extends BaseProvider implements SingleCompletio
 		}
 	}
 
+	/**
+	 * Load the model into memory so the first request of a task doesn't wait
+	 * for it. Ollama loads a model when it gets a generate request without a
+	 * prompt; the request uses the same num_ctx as the chat requests, since a
+	 * different context size makes Ollama reload the model.
+	 */
+	async preloadModel(): Promise<void> {
+		if (!this.options.ollamaPreloadModel) {
+			return
+		}
+
+		const client = this.ensureClient()
+		const { id: modelId } = await this.fetchModel()
+
+		if (!modelId) {
+			return
+		}
+
+		const { num_ctx } = this.getChatOptions()
+
+		await client.generate({
+			model: modelId,
+			prompt: "",
+			stream: false,
+			options: num_ctx !== undefined ? { num_ctx } : undefined,
+			keep_alive: this.getKeepAlive(),
+		})
+	}
+
+	private getChatOptions(): OllamaChatOptions {
+		const { id: modelId, info } = this.getModel()
+		const useR1Format = modelId.toLowerCase().includes("deepseek-r1")
+
+		const chatOptions: OllamaChatOptions = {
+			temperature: this.options.modelTemperature ?? (useR1Format ? DEEP_SEEK_DEFAULT_TEMPERATURE : 0),
+		}
+
+		// Only include num_ctx if explicitly set via ollamaNumCtx, or derived
+		// from the model's context window when ollamaAutoNumCtx is enabled.
+		// Otherwise Ollama uses its own default, which is usually far smaller
+		// than the context window Roo Code plans for.
+		if (this.options.ollamaNumCtx !== undefined) {
+			chatOptions.num_ctx = this.options.ollamaNumCtx
+		} else if (this.options.ollamaAutoNumCtx && this.models[modelId]) {
+			chatOptions.num_ctx = info.contextWindow
+		}
+
+		return chatOptions
+	}
+
+	/**
+	 * Ollama reads a number as seconds and a string as a duration ("30m"), so
+	 * plain numbers such as "-1" (keep loaded indefinitely) are sent as numbers.
+	 */
+	private getKeepAlive(): string | number | undefined {
+		const keepAlive = this.options.ollamaKeepAlive?.trim()
+
+		if (!keepAlive) {
+			return undefined
+		}
+
+		return /^-?\d+$/.test(keepAlive) ? Number(keepAlive) : keepAlive
+	}
+
 	async completePrompt(prompt: string): Promise<string> {
 		try {
 			const client = this.ensureClient()
 			const { id: modelId } = await this.fetchModel()
-			const useR1Format = modelId.toLowerCase().includes("deepseek-r1")
-
-			// Build options object conditionally
-			const chatOptions: OllamaChatOptions = {
-				temperature: this.options.modelTemperature ?? (useR1Format ? DEEP_SEEK_DEFAULT_TEMPERATURE : 0),
-			}
-
-			// Only include num_ctx if explicitly set via ollamaNumCtx
-			if (this.options.ollamaNumCtx !== undefined) {
-				chatOptions.num_ctx = this.options.ollamaNumCtx
-			}
 
 			const response = await client.chat({
 				model: modelId,
 				messages: [{ role: "user", content: prompt }],
 				stream: false,
-				options: chatOptions,
+				options: this.getChatOptions(),
+				keep_alive: this.getKeepAlive(),
 			})
 
 			return response.message?.content || ""
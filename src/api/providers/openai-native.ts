@@ -34,8 +34,8 @@ export type OpenAiNativeModel = ReturnType<OpenAiNativeHandler["getModel"]>
 
 export class OpenAiNativeHandler extends BaseProvider implements SingleCompletionHandler {
 	protected options: ApiHandlerOptions
-	private client: OpenAI
-	private readonly providerName = "OpenAI Native"
+	protected client: OpenAI
+	protected readonly providerName: string = "OpenAI Native"
 	// Session ID for request tracking (persists for the lifetime of the handler)
 	private readonly sessionId: string
 	/**
@@ -222,7 +222,7 @@ export class OpenAiNativeHandler extends BaseProvider implements SingleCompletio
 		yield* this.executeRequest(requestBody, model, metadata, systemPrompt, messages)
 	}
 
-	private buildRequestBody(
+	protected buildRequestBody(
 		model: OpenAiNativeModel,
 		formattedInput: any,
 		systemPrompt: string,
@@ -554,9 +554,7 @@ export class OpenAiNativeHandler extends BaseProvider implements SingleCompletio
 		systemPrompt?: string,
 		messages?: Anthropic.Messages.MessageParam[],
 	): ApiStream {
-		const apiKey = this.options.openAiNativeApiKey ?? "not-provided"
-		const baseUrl = this.options.openAiNativeBaseUrl || "https://api.openai.com"
-		const url = `${baseUrl}/v1/responses`
+		const { url, headers } = await this.getResponsesApiEndpoint()
 
 		// Create AbortController for cancellation
 		this.abortController = new AbortController()
@@ -570,7 +568,7 @@ export class OpenAiNativeHandler extends BaseProvider implements SingleCompletio
 				method: "POST",
 				headers: {
 					"Content-Type": "application/json",
-					Authorization: `Bearer ${apiKey}`,
+					...headers,
 					originator: "roo-code",
 					session_id: taskId || this.sessionId,
 					"User-Agent": userAgent,
@@ -662,6 +660,15 @@ export class OpenAiNativeHandler extends BaseProvider implements SingleCompletio
 		}
 	}
 
+	/**
+	 * Returns the URL and authentication headers used when streaming falls back to a manual SSE request.
+	 */
+	protected async getResponsesApiEndpoint(): Promise<{ url: string; headers: Record<string, string> }> {
+		const apiKey = this.options.openAiNativeApiKey ?? "not-provided"
+		const baseUrl = this.options.openAiNativeBaseUrl || "https://api.openai.com"
+		return { url: `${baseUrl}/v1/responses`, headers: { Authorization: `Bearer ${apiKey}` } }
+	}
+
 	/**
 	 * Handles the streaming response from the Responses API.
 	 *
@@ -1453,7 +1460,8 @@ export class OpenAiNativeHandler extends BaseProvider implements SingleCompletio
 
 		// The o3 models are named like "o3-mini-[reasoning-effort]", which are
 		// not valid model ids, so we need to strip the suffix.
-		return { id: id.startsWith("o3-mini") ? "o3-mini" : id, info, ...params, verbosity: params.verbosity }
+		const resolvedId: string = id.startsWith("o3-mini") ? "o3-mini" : id
+		return { id: resolvedId, info, ...params, verbosity: params.verbosity }
 	}
 
 	/**
@@ -0,0 +1,222 @@
+import { createHash } from "crypto"
+import { Anthropic } from "@anthropic-ai/sdk"
+import OpenAI, { AzureOpenAI } from "openai"
+
+import {
+	type ModelInfo,
+	type OpenAiResponsesBuiltInTool,
+	type ReasoningEffortExtended,
+	azureOpenAiDefaultApiVersion,
+	isAzureEntraAuth,
+	openAiModelInfoSaneDefaults,
+} from "@roo-code/types"
+
+import type { ApiHandlerOptions } from "../../shared/api"
+
+import { ApiStream } from "../transform/stream"
+import { getModelParams } from "../transform/model-params"
+
+import { DEFAULT_HEADERS } from "./constants"
+import { OpenAiNativeHandler, type OpenAiNativeModel } from "./openai-native"
+import type { ApiHandlerCreateMessageMetadata } from "../index"
+import { getApiRequestTimeout } from "./utils/timeout-config"
+import { type AzureTokenProvider, createAuthorizedFetch, createAzureTokenProvider } from "./utils/azure-auth"
+
+const BUILT_IN_TOOLS: Record<OpenAiResponsesBuiltInTool, Record<string, unknown>> = {
+	web_search: { type: "web_search" },
+	code_interpreter: { type: "code_interpreter", container: { type: "auto" } },
+}
+
+interface ResponsesContinuation {
+	taskId: string
+	responseId: string
+	// Number and hash of the input items the response continued from
+	inputLength: number
+	inputHash: string
+}
+
+/**
+ * OpenAI Compatible provider mode that sends requests to the Responses API
+ * (`/responses`) instead of Chat Completions.
+ *
+ * Request building and stream handling are shared with the OpenAI Native provider,
+ * so reasoning items and encrypted reasoning work the same way. On top of that, the
+ * configured built-in tools are passed through to the server and, when storing is
+ * enabled, follow-up requests of a task only send the new input items together with
+ * the `previous_response_id` of the last response.
+ */
+export class OpenAiResponsesHandler extends OpenAiNativeHandler {
+	protected override readonly providerName = "OpenAI"
+	private continuation: ResponsesContinuation | undefined
+	// Input of the request in flight, recorded as the continuation once its response completes
+	private pendingInput: { inputLength: number; inputHash: string } | undefined
+	private usedPreviousResponseId = false
+	private azureTokenProvider: AzureTokenProvider | undefined
+
+	constructor(options: ApiHandlerOptions) {
+		super(options)
+
+		const baseURL = this.options.openAiBaseUrl || "https://api.openai.com/v1"
+		const apiKey = this.options.openAiApiKey ?? "not-provided"
+		const headers = {
+			...DEFAULT_HEADERS,
+			...(this.options.openAiHeaders || {}),
+		}
+		const timeout = getApiRequestTimeout()
+
+		if (isAzureEntraAuth(this.options.azureAuthMethod)) {
+			this.azureTokenProvider = createAzureTokenProvider(
+				this.options.azureAuthMethod,
+				this.options.azureManagedIdentityClientId,
+			)
+		}
+
+		this.client = this.isAzureOpenAi()
+			? new AzureOpenAI({
+					baseURL,
+					...(this.azureTokenProvider ? { azureADTokenProvider: this.azureTokenProvider } : { apiKey }),
+					apiVersion: this.options.azureApiVersion || azureOpenAiDefaultApiVersion,
+					defaultHeaders: headers,
+					timeout,
+				})
+			: new OpenAI({
+					baseURL,
+					apiKey,
+					defaultHeaders: headers,
+					timeout,
+					...(this.azureTokenProvider ? { fetch: createAuthorizedFetch(this.azureTokenProvider) } : {}),
+				})
+	}
+
+	override async *createMessage(
+		systemPrompt: string,
+		messages: Anthropic.Messages.MessageParam[],
+		metadata?: ApiHandlerCreateMessageMetadata,
+	): ApiStream {
+		let didYield = false
+
+		try {
+			for await (const chunk of super.createMessage(systemPrompt, messages, metadata)) {
+				didYield = true
+				yield chunk
+			}
+		} catch (error) {
+			// Stored responses expire and can be deleted, so a failed continuation is
+			// repeated once with the full conversation.
+			if (!this.usedPreviousResponseId || didYield) {
+				this.continuation = undefined
+				throw error
+			}
+
+			this.continuation = undefined
+			yield* super.createMessage(systemPrompt, messages, metadata)
+		}
+
+		const responseId = this.getResponseId()
+		this.continuation =
+			this.pendingInput && responseId && metadata?.taskId
+				? { taskId: metadata.taskId, responseId, ...this.pendingInput }
+				: undefined
+	}
+
+	protected override buildRequestBody(
+		model: OpenAiNativeModel,
+		formattedInput: any,
+		systemPrompt: string,
+		verbosity: any,
+		reasoningEffort: ReasoningEffortExtended | undefined,
+		metadata?: ApiHandlerCreateMessageMetadata,
+	): any {
+		const body = super.buildRequestBody(model, formattedInput, systemPrompt, verbosity, reasoningEffort, metadata)
+
+		const builtInTools = (this.options.openAiResponsesBuiltInTools ?? []).map((tool) => BUILT_IN_TOOLS[tool])
+		if (builtInTools.length > 0) {
+			body.tools = [...(body.tools ?? []), ...builtInTools]
+		}
+
+		this.pendingInput = undefined
+		this.usedPreviousResponseId = false
+
+		if (!this.options.openAiResponsesStoreEnabled || !Array.isArray(formattedInput)) {
+			return body
+		}
+
+		body.store = true
+		this.pendingInput = { inputLength: formattedInput.length, inputHash: hashInput(formattedInput) }
+
+		const newInput = this.getInputAfterContinuation(formattedInput, metadata?.taskId)
+		if (newInput) {
+			body.input = newInput
+			body.previous_response_id = this.continuation!.responseId
+			this.usedPreviousResponseId = true
+		}
+
+		return body
+	}
+
+	/**
+	 * Returns the input items that follow the last response, or undefined when the
+	 * conversation does not continue it, e.g. after the context was condensed.
+	 */
+	private getInputAfterContinuation(formattedInput: any[], taskId: string | undefined): any[] | undefined {
+		const continuation = this.continuation
+		if (!continuation || continuation.taskId !== taskId || formattedInput.length <= continuation.inputLength) {
+			return undefined
+		}
+
+		if (hashInput(formattedInput.slice(0, continuation.inputLength)) !== continuation.inputHash) {
+			return undefined
+		}
+
+		// The output of the last response is stored on the server, so everything up to
+		// the next user message or tool result is skipped.
+		const rest = formattedInput.slice(continuation.inputLength)
+		const start = rest.findIndex((item) => item.role === "user" || item.type === "function_call_output")
+		return start === -1 ? undefined : rest.slice(start)
+	}
+
+	protected override async getResponsesApiEndpoint(): Promise<{ url: string; headers: Record<string, string> }> {
+		const baseUrl = (this.options.openAiBaseUrl || "https://api.openai.com/v1").replace(/\/+$/, "")
+		const apiKey = this.options.openAiApiKey ?? "not-provided"
+		const isAzureOpenAi = this.isAzureOpenAi()
+		const apiVersion = this.options.azureApiVersion || azureOpenAiDefaultApiVersion
+
+		const auth = this.azureTokenProvider
+			? { Authorization: `Bearer ${await this.azureTokenProvider()}` }
+			: isAzureOpenAi
+				? { "api-key": apiKey }
+				: { Authorization: `Bearer ${apiKey}` }
+
+		return {
+			url: isAzureOpenAi ? `${baseUrl}/responses?api-version=${apiVersion}` : `${baseUrl}/responses`,
+			headers: { ...(this.options.openAiHeaders || {}), ...auth },
+		}
+	}
+
+	override getModel() {
+		const id = this.options.openAiModelId ?? ""
+		const info: ModelInfo = this.options.openAiCustomModelInfo ?? openAiModelInfoSaneDefaults
+		const params = getModelParams({
+			format: "openai",
+			modelId: id,
+			model: info,
+			settings: this.options,
+			defaultTemperature: 0,
+		})
+		return { id, info, ...params, verbosity: params.verbosity }
+	}
+
+	private isAzureOpenAi(): boolean {
+		let host = ""
+		try {
+			host = new URL(this.options.openAiBaseUrl ?? "").host
+		} catch {
+			// Not a valid URL, so not an Azure endpoint either
+		}
+		return host === "azure.com" || host.endsWith(".azure.com") || !!this.options.openAiUseAzure
+	}
+}
+
+function hashInput(items: unknown[]): string {
+	return createHash("sha256").update(JSON.stringify(items)).digest("hex")
+}
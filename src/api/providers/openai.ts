@@ -8,6 +8,7 @@ import {
 	openAiModelInfoSaneDefaults,
 	DEEP_SEEK_DEFAULT_TEMPERATURE,
 	OPENAI_AZURE_AI_INFERENCE_PATH,
+	isAzureEntraAuth,
 } from "@roo-code/types"
 
 import type { ApiHandlerOptions } from "../../shared/api"
@@ -16,6 +17,7 @@ import { TagMatcher } from "../../utils/tag-matcher"
 
 import { convertToOpenAiMessages } from "../transform/openai-format"
 import { convertToR1Format } from "../transform/r1-format"
+import { addPromptCacheBreakpoints } from "../transform/caching/breakpoints"
 import { ApiStream, ApiStreamUsageChunk } from "../transform/stream"
 import { getModelParams } from "../transform/model-params"
 
@@ -23,6 +25,7 @@ import { DEFAULT_HEADERS } from "./constants"
 import { BaseProvider } from "./base-provider"
 import type { SingleCompletionHandler, ApiHandlerCreateMessageMetadata } from "../index"
 import { getApiRequestTimeout } from "./utils/timeout-config"
+import { createAuthorizedFetch, createAzureTokenProvider } from "./utils/azure-auth"
 import { handleOpenAIError } from "./utils/openai-error-handler"
 
 // TODO: Rename this to OpenAICompatibleHandler. Also, I think the
@@ -50,6 +53,12 @@ export class OpenAiHandler extends BaseProvider implements SingleCompletionHandl
 
 		const timeout = getApiRequestTimeout()
 
+		// With Entra ID the API key is replaced by a token that is acquired and refreshed per request
+		const azureTokenProvider = isAzureEntraAuth(this.options.azureAuthMethod)
+			? createAzureTokenProvider(this.options.azureAuthMethod, this.options.azureManagedIdentityClientId)
+			: undefined
+		const authorizedFetch = azureTokenProvider ? { fetch: createAuthorizedFetch(azureTokenProvider) } : {}
+
 		if (isAzureAiInference) {
 			// Azure AI Inference Service (e.g., for DeepSeek) uses a different path structure
 			this.client = new OpenAI({
@@ -58,13 +67,14 @@ export class OpenAiHandler extends BaseProvider implements SingleCompletionHandl
 				defaultHeaders: headers,
 				defaultQuery: { "api-version": this.options.azureApiVersion || "2024-05-01-preview" },
 				timeout,
+				...authorizedFetch,
 			})
 		} else if (isAzureOpenAi) {
 			// Azure API shape slightly differs from the core API shape:
 			// https://github.com/openai/openai-node?tab=readme-ov-file#microsoft-azure-openai
 			this.client = new AzureOpenAI({
 				baseURL,
-				apiKey,
+				...(azureTokenProvider ? { azureADTokenProvider: azureTokenProvider } : { apiKey }),
 				apiVersion: this.options.azureApiVersion || azureOpenAiDefaultApiVersion,
 				defaultHeaders: headers,
 				timeout,
@@ -75,6 +85,7 @@ export class OpenAiHandler extends BaseProvider implements SingleCompletionHandl
 				apiKey,
 				defaultHeaders: headers,
 				timeout,
+				...authorizedFetch,
 			})
 		}
 	}
@@ -106,6 +117,12 @@ export class OpenAiHandler extends BaseProvider implements SingleCompletionHandl
 
 			if (deepseekReasoner) {
 				convertedMessages = convertToR1Format([{ role: "user", content: systemPrompt }, ...messages])
+			} else if (this.options.openAiPromptCacheBreakpoints) {
+				convertedMessages = addPromptCacheBreakpoints(
+					systemPrompt,
+					convertToOpenAiMessages(messages),
+					this.options.openAiPromptCacheBreakpoints,
+				)
 			} else {
 				if (modelInfo.supportsPromptCache) {
 					systemMessage = {
@@ -0,0 +1,102 @@
+// npx vitest run api/providers/utils/__tests__/azure-auth.spec.ts
+
+import * as vscode from "vscode"
+
+import { AZURE_COGNITIVE_SERVICES_SCOPE, createAuthorizedFetch, createAzureTokenProvider } from "../azure-auth"
+
+vitest.mock("vscode", () => ({
+	authentication: {
+		getSession: vitest.fn(),
+	},
+}))
+
+const tokenResponse = (token: string, expiresInSeconds: number) => ({
+	ok: true,
+	json: async () => ({ access_token: token, expires_on: String(Math.floor(Date.now() / 1000) + expiresInSeconds) }),
+})
+
+describe("createAzureTokenProvider", () => {
+	let mockFetch: ReturnType<typeof vitest.fn>
+
+	beforeEach(() => {
+		vitest.clearAllMocks()
+		mockFetch = vitest.fn()
+		global.fetch = mockFetch as any
+		delete process.env.IDENTITY_ENDPOINT
+		delete process.env.IDENTITY_HEADER
+	})
+
+	it("uses the VS Code Microsoft account session for Entra ID", async () => {
+		;(vscode.authentication.getSession as any).mockResolvedValue({ accessToken: "session-token" })
+
+		const getToken = createAzureTokenProvider("entraId")
+
+		expect(await getToken()).toBe("session-token")
+		expect(vscode.authentication.getSession).toHaveBeenCalledWith("microsoft", [AZURE_COGNITIVE_SERVICES_SCOPE], {
+			createIfNone: true,
+		})
+	})
+
+	it("requests managed identity tokens from the instance metadata service and caches them", async () => {
+		mockFetch.mockResolvedValue(tokenResponse("imds-token", 3600))
+
+		const getToken = createAzureTokenProvider("managedIdentity", "client-1")
+
+		expect(await getToken()).toBe("imds-token")
+		expect(await getToken()).toBe("imds-token")
+		expect(mockFetch).toHaveBeenCalledTimes(1)
+
+		const [url, init] = mockFetch.mock.calls[0]
+		expect(url.origin).toBe("http://169.254.169.254")
+		expect(url.searchParams.get("resource")).toBe("https://cognitiveservices.azure.com")
+		expect(url.searchParams.get("client_id")).toBe("client-1")
+		expect(init.headers).toEqual({ Metadata: "true" })
+	})
+
+	it("refreshes managed identity tokens that are about to expire", async () => {
+		mockFetch
+			.mockResolvedValueOnce(tokenResponse("expiring-token", 60))
+			.mockResolvedValueOnce(tokenResponse("fresh-token", 3600))
+
+		const getToken = createAzureTokenProvider("managedIdentity")
+
+		expect(await getToken()).toBe("expiring-token")
+		expect(await getToken()).toBe("fresh-token")
+	})
+
+	it("uses the App Service identity endpoint when available", async () => {
+		process.env.IDENTITY_ENDPOINT = "http://localhost:4141/msi/token"
+		process.env.IDENTITY_HEADER = "secret"
+		mockFetch.mockResolvedValue(tokenResponse("app-service-token", 3600))
+
+		expect(await createAzureTokenProvider("managedIdentity")()).toBe("app-service-token")
+
+		const [url, init] = mockFetch.mock.calls[0]
+		expect(url.toString()).toContain("http://localhost:4141/msi/token?api-version=2019-08-01")
+		expect(init.headers).toEqual({ "X-IDENTITY-HEADER": "secret" })
+	})
+
+	it("reports failed token requests", async () => {
+		mockFetch.mockResolvedValue({ ok: false, status: 400, text: async () => "Identity not found" })
+
+		await expect(createAzureTokenProvider("managedIdentity")()).rejects.toThrow(
+			"Managed identity token request failed (400): Identity not found",
+		)
+	})
+})
+
+describe("createAuthorizedFetch", () => {
+	it("replaces the API key with a bearer token", async () => {
+		const mockFetch = vitest.fn().mockResolvedValue({ ok: true })
+		global.fetch = mockFetch as any
+
+		const authorizedFetch = createAuthorizedFetch(async () => "token")
+		await authorizedFetch("https://example.openai.azure.com/models", {
+			headers: { Authorization: "Bearer not-provided", "api-key": "not-provided" },
+		})
+
+		const headers: Headers = mockFetch.mock.calls[0][1].headers
+		expect(headers.get("Authorization")).toBe("Bearer token")
+		expect(headers.has("api-key")).toBe(false)
+	})
+})
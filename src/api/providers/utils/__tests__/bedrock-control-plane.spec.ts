@@ -0,0 +1,61 @@
+import { BedrockControlPlaneClient } from "../bedrock-control-plane"
+
+describe("BedrockControlPlaneClient", () => {
+	const fetchMock = vi.fn()
+
+	beforeEach(() => {
+		fetchMock.mockReset()
+		vi.stubGlobal("fetch", fetchMock)
+	})
+
+	afterEach(() => {
+		vi.unstubAllGlobals()
+	})
+
+	const credentials = { accessKeyId: "AKIDEXAMPLE", secretAccessKey: "secret" }
+
+	const profileArn = "arn:aws:bedrock:us-east-1:123:application-inference-profile/abc"
+
+	it("signs requests to the control plane of the region", async () => {
+		fetchMock.mockResolvedValue(new Response(JSON.stringify({ inferenceProfileArn: "arn" }), { status: 200 }))
+		const client = new BedrockControlPlaneClient({ region: "us-east-1", credentials })
+
+		const profile = await client.getInferenceProfile(profileArn)
+
+		expect(profile).toEqual({ inferenceProfileArn: "arn" })
+		const [url, init] = fetchMock.mock.calls[0]
+		expect(url).toBe(
+			"https://bedrock.us-east-1.amazonaws.com/inference-profiles/" +
+				"arn%3Aaws%3Abedrock%3Aus-east-1%3A123%3Aapplication-inference-profile%2Fabc",
+		)
+		expect(init.headers.authorization).toMatch(
+			/^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE\/\d+\/us-east-1\/bedrock\//,
+		)
+		expect(init.headers.host).toBeUndefined()
+	})
+
+	it("sends the API key as a bearer token", async () => {
+		fetchMock.mockResolvedValue(new Response(JSON.stringify({}), { status: 200 }))
+		const client = new BedrockControlPlaneClient({ region: "us-west-2", token: { token: "api-key" } })
+
+		await client.listInferenceProfiles({ typeEquals: "APPLICATION", nextToken: "page 2" })
+
+		const [url, init] = fetchMock.mock.calls[0]
+		expect(url).toBe(
+			"https://bedrock.us-west-2.amazonaws.com/inference-profiles?type=APPLICATION&nextToken=page%202",
+		)
+		expect(init.headers.authorization).toBe("Bearer api-key")
+	})
+
+	it("names errors after the AWS error type", async () => {
+		fetchMock.mockResolvedValue(
+			new Response("not allowed", {
+				status: 403,
+				headers: { "x-amzn-errortype": "AccessDeniedException:http://internal.amazon.com/coral/" },
+			}),
+		)
+		const client = new BedrockControlPlaneClient({ region: "us-east-1", credentials })
+
+		await expect(client.getInferenceProfile("abc")).rejects.toThrow("AccessDeniedException: not allowed")
+	})
+})
@@ -0,0 +1,94 @@
+import * as vscode from "vscode"
+
+import type { AzureAuthMethod } from "@roo-code/types"
+
+const AZURE_COGNITIVE_SERVICES_RESOURCE = "https://cognitiveservices.azure.com"
+export const AZURE_COGNITIVE_SERVICES_SCOPE = `${AZURE_COGNITIVE_SERVICES_RESOURCE}/.default`
+
+// Azure virtual machines serve tokens of their managed identity from the instance metadata service
+const IMDS_TOKEN_URL = "http://169.254.169.254/metadata/identity/oauth2/token"
+// Tokens are renewed ahead of their expiry so that no request is sent with an expired one
+const TOKEN_REFRESH_MARGIN_MS = 5 * 60 * 1000
+
+export type AzureTokenProvider = () => Promise<string>
+
+/**
+ * Creates a function that returns a Microsoft Entra ID access token for Azure OpenAI.
+ *
+ * `entraId` asks VS Code's Microsoft account provider for a session, which prompts the user to
+ * sign in once and is kept fresh by VS Code. `managedIdentity` requests tokens for the identity
+ * of the Azure machine or App Service VS Code runs on and caches them until shortly before
+ * they expire.
+ *
+ * @param method Configured authentication method
+ * @param managedIdentityClientId Client id of a user-assigned managed identity
+ * @returns Provider that resolves to a bearer token on every call
+ */
+export function createAzureTokenProvider(
+	method: Exclude<AzureAuthMethod, "apiKey">,
+	managedIdentityClientId?: string,
+): AzureTokenProvider {
+	if (method === "entraId") {
+		return getMicrosoftAccountToken
+	}
+
+	let cached: { token: string; expiresAt: number } | undefined
+	let pending: Promise<{ token: string; expiresAt: number }> | undefined
+
+	return async () => {
+		if (cached && cached.expiresAt - Date.now() > TOKEN_REFRESH_MARGIN_MS) {
+			return cached.token
+		}
+
+		// Concurrent requests share one token request
+		pending ??= fetchManagedIdentityToken(managedIdentityClientId).finally(() => {
+			pending = undefined
+		})
+		cached = await pending
+		return cached.token
+	}
+}
+
+/**
+ * Wraps fetch so every request is sent with a fresh bearer token, for clients that only
+ * accept a static API key.
+ */
+export function createAuthorizedFetch(getToken: AzureTokenProvider): typeof fetch {
+	return async (input, init) => {
+		const headers = new Headers(init?.headers)
+		headers.set("Authorization", `Bearer ${await getToken()}`)
+		headers.delete("api-key")
+		return fetch(input, { ...init, headers })
+	}
+}
+
+async function getMicrosoftAccountToken(): Promise<string> {
+	const session = await vscode.authentication.getSession("microsoft", [AZURE_COGNITIVE_SERVICES_SCOPE], {
+		createIfNone: true,
+	})
+	return session.accessToken
+}
+
+async function fetchManagedIdentityToken(clientId?: string): Promise<{ token: string; expiresAt: number }> {
+	// App Service, Functions and Container Apps expose their own identity endpoint
+	const { IDENTITY_ENDPOINT: identityEndpoint, IDENTITY_HEADER: identityHeader } = process.env
+	const isAppService = !!identityEndpoint && !!identityHeader
+
+	const url = new URL(isAppService ? identityEndpoint! : IMDS_TOKEN_URL)
+	url.searchParams.set("api-version", isAppService ? "2019-08-01" : "2018-02-01")
+	url.searchParams.set("resource", AZURE_COGNITIVE_SERVICES_RESOURCE)
+	if (clientId) {
+		url.searchParams.set("client_id", clientId)
+	}
+
+	const response = await fetch(url, {
+		headers: isAppService ? { "X-IDENTITY-HEADER": identityHeader! } : { Metadata: "true" },
+	})
+
+	if (!response.ok) {
+		throw new Error(`Managed identity token request failed (${response.status}): ${await response.text()}`)
+	}
+
+	const body = (await response.json()) as { access_token: string; expires_on: string | number }
+	return { token: body.access_token, expiresAt: Number(body.expires_on) * 1000 }
+}
@@ -0,0 +1,91 @@
+import type { BedrockRuntimeClientConfig } from "@aws-sdk/client-bedrock-runtime"
+import { fromNodeProviderChain } from "@aws-sdk/credential-providers"
+import { Sha256 } from "@aws-crypto/sha256-js"
+import { SignatureV4 } from "@smithy/signature-v4"
+
+/**
+ * The fields of a Bedrock inference profile that are used to pick and price it.
+ */
+export interface BedrockInferenceProfileSummary {
+	inferenceProfileArn?: string
+	inferenceProfileName?: string
+	models?: { modelArn?: string }[]
+}
+
+/**
+ * Escapes a path segment or query value the way SigV4 expects (RFC 3986).
+ */
+function escapeUri(value: string): string {
+	return encodeURIComponent(value).replace(/[!'()*]/g, (c) => `%${c.charCodeAt(0).toString(16).toUpperCase()}`)
+}
+
+/**
+ * Sends the few read-only Bedrock control plane requests Roo needs, signed with the same
+ * credentials as the runtime client. The control plane (bedrock.<region>.amazonaws.com) is a
+ * separate service from the runtime, and the SDK client for it isn't a dependency.
+ */
+export class BedrockControlPlaneClient {
+	constructor(private readonly config: BedrockRuntimeClientConfig) {}
+
+	/**
+	 * Gets an inference profile by its ARN or id
+	 */
+	getInferenceProfile(inferenceProfileIdentifier: string): Promise<BedrockInferenceProfileSummary> {
+		return this.get(`/inference-profiles/${escapeUri(inferenceProfileIdentifier)}`, {})
+	}
+
+	/**
+	 * Lists one page of the inference profiles of the account and region
+	 */
+	listInferenceProfiles(params: {
+		typeEquals: "APPLICATION" | "SYSTEM_DEFINED"
+		nextToken?: string
+	}): Promise<{ inferenceProfileSummaries?: BedrockInferenceProfileSummary[]; nextToken?: string }> {
+		return this.get("/inference-profiles", {
+			type: params.typeEquals,
+			...(params.nextToken ? { nextToken: params.nextToken } : {}),
+		})
+	}
+
+	private async get<T>(path: string, query: Record<string, string>): Promise<T> {
+		const region = this.config.region
+		if (typeof region !== "string" || !region) {
+			throw new Error("An AWS region is required to call the Bedrock control plane")
+		}
+
+		const hostname = `bedrock.${region}.amazonaws.com`
+		let headers: Record<string, string> = { host: hostname, accept: "application/json" }
+
+		const token = this.config.token
+		if (token && "token" in token) {
+			headers = { ...headers, authorization: `Bearer ${token.token}` }
+		} else {
+			const signer = new SignatureV4({
+				service: "bedrock",
+				region,
+				credentials: this.config.credentials ?? fromNodeProviderChain(),
+				sha256: Sha256,
+			})
+			const signed = await signer.sign({ method: "GET", protocol: "https:", hostname, path, query, headers })
+			headers = signed.headers
+		}
+
+		const queryString = Object.entries(query)
+			.map(([key, value]) => `${escapeUri(key)}=${escapeUri(value)}`)
+			.join("&")
+		// fetch sets the host header itself
+		const { host: _host, ...requestHeaders } = headers
+		const response = await fetch(`https://${hostname}${path}${queryString ? `?${queryString}` : ""}`, {
+			headers: requestHeaders,
+		})
+
+		if (!response.ok) {
+			// Errors are named like the SDK exceptions, e.g. "AccessDeniedException"
+			const errorType = response.headers.get("x-amzn-errortype")?.split(":")[0] || `HTTP ${response.status}`
+			const body = await response.text().catch(() => "")
+			throw new Error(`${errorType}: ${body}`)
+		}
+
+		return (await response.json()) as T
+	}
+}
@@ -0,0 +1,83 @@
+// npx vitest run src/api/transform/__tests__/tool-call-grammar.spec.ts
+
+import { GrammarResponseParser, buildToolCallSchema, convertToGrammarMessages } from "../tool-call-grammar"
+
+describe("tool-call-grammar", () => {
+	describe("buildToolCallSchema", () => {
+		it("limits the response to a single tool call without parallel tool calls", () => {
+			const schema = buildToolCallSchema(
+				[{ type: "function", function: { name: "list_files", parameters: { type: "object" } } }],
+				false,
+			) as any
+
+			expect(schema.properties.tool_calls).toMatchObject({ minItems: 1, maxItems: 1 })
+			expect(schema.properties.tool_calls.items.anyOf).toEqual([
+				{
+					type: "object",
+					properties: { name: { const: "list_files" }, arguments: { type: "object" } },
+					required: ["name", "arguments"],
+					additionalProperties: false,
+				},
+			])
+		})
+	})
+
+	describe("convertToGrammarMessages", () => {
+		it("shows previous tool calls in the response format and tool results as user content", () => {
+			const messages = convertToGrammarMessages([
+				{
+					role: "assistant",
+					content: [
+						{ type: "text", text: "Reading the file." },
+						{ type: "tool_use", id: "toolu_1", name: "read_file", input: { path: "a.ts" } },
+					],
+				},
+				{
+					role: "user",
+					content: [{ type: "tool_result", tool_use_id: "toolu_1", content: "export {}" }],
+				},
+			])
+
+			expect(messages).toEqual([
+				{
+					role: "assistant",
+					content: JSON.stringify({
+						message: "Reading the file.",
+						tool_calls: [{ name: "read_file", arguments: { path: "a.ts" } }],
+					}),
+				},
+				{
+					role: "user",
+					content: [
+						{ type: "text", text: "Result of read_file:" },
+						{ type: "text", text: "export {}" },
+					],
+				},
+			])
+		})
+	})
+
+	describe("GrammarResponseParser", () => {
+		it("streams the message before the tool calls are complete", () => {
+			const parser = new GrammarResponseParser()
+
+			expect(parser.update('{"message": "Hel')).toBe("Hel")
+			expect(parser.update('lo\\')).toBe("lo")
+			expect(parser.update('n", "tool_calls": [{"name": "attempt_completion"')).toBe("\n")
+			expect(parser.update(', "arguments": {"result": "Done"}}]}')).toBe("")
+
+			expect(parser.final()).toEqual({
+				message: "",
+				toolCalls: [{ name: "attempt_completion", arguments: { result: "Done" } }],
+			})
+		})
+
+		it("returns undefined for an incomplete response", () => {
+			const parser = new GrammarResponseParser()
+			parser.update('{"message": "Hi", "tool_calls": [')
+
+			expect(parser.final()).toBeUndefined()
+			expect(parser.rawText).toBe('{"message": "Hi", "tool_calls": [')
+		})
+	})
+})
@@ -0,0 +1,120 @@
+// npx vitest run src/api/transform/caching/__tests__/breakpoints.spec.ts
+
+import OpenAI from "openai"
+
+import { addPromptCacheBreakpoints, LARGE_FILE_READ_CHARS } from "../breakpoints"
+
+describe("addPromptCacheBreakpoints", () => {
+	const systemPrompt = "You are a helpful assistant."
+	const cacheControl = { type: "ephemeral" }
+	const environmentDetails = "<environment_details>\n# Current Time\n</environment_details>"
+	const largeFile = "x".repeat(LARGE_FILE_READ_CHARS)
+
+	const readFile = (id: string, content: string): OpenAI.Chat.ChatCompletionMessageParam[] => [
+		{
+			role: "assistant",
+			content: null,
+			tool_calls: [{ id, type: "function", function: { name: "read_file", arguments: "{}" } }],
+		},
+		{ role: "tool", tool_call_id: id, content },
+	]
+
+	it("leaves every segment unmarked without breakpoints", () => {
+		const messages: OpenAI.Chat.ChatCompletionMessageParam[] = [{ role: "user", content: "Hello" }]
+
+		expect(addPromptCacheBreakpoints(systemPrompt, messages, [])).toEqual([
+			{ role: "system", content: systemPrompt },
+			{ role: "user", content: "Hello" },
+		])
+	})
+
+	it("marks the system prompt", () => {
+		const [systemMessage] = addPromptCacheBreakpoints(systemPrompt, [], ["systemPrompt"])
+
+		expect(systemMessage).toEqual({
+			role: "system",
+			content: [{ type: "text", text: systemPrompt, cache_control: cacheControl }],
+		})
+	})
+
+	it("marks the environment details of the last two user messages", () => {
+		const messages: OpenAI.Chat.ChatCompletionMessageParam[] = [
+			{ role: "user", content: "First" },
+			{ role: "assistant", content: "Ok" },
+			{
+				role: "user",
+				content: [
+					{ type: "text", text: "Second" },
+					{ type: "text", text: environmentDetails },
+				],
+			},
+			{ role: "assistant", content: "Ok" },
+			{ role: "user", content: [{ type: "image_url", image_url: { url: "data:image/png;base64,AA==" } }] },
+		]
+
+		const result = addPromptCacheBreakpoints(systemPrompt, messages, ["environmentDetails"])
+
+		expect(result[1]).toEqual({ role: "user", content: "First" })
+		expect(result[3].content).toEqual([
+			{ type: "text", text: "Second" },
+			{ type: "text", text: environmentDetails, cache_control: cacheControl },
+		])
+		expect(result[5].content).toEqual([
+			{ type: "image_url", image_url: { url: "data:image/png;base64,AA==" } },
+			{ type: "text", text: "...", cache_control: cacheControl },
+		])
+	})
+
+	it("marks large file reads with the breakpoints that are left", () => {
+		const messages: OpenAI.Chat.ChatCompletionMessageParam[] = [
+			{ role: "user", content: "Read the files" },
+			...readFile("call_1", largeFile),
+			...readFile("call_2", largeFile),
+			...readFile("call_3", "small"),
+			{ role: "user", content: environmentDetails },
+		]
+
+		const result = addPromptCacheBreakpoints(systemPrompt, messages, [
+			"systemPrompt",
+			"environmentDetails",
+			"fileReads",
+		])
+
+		// System prompt and two user messages leave one breakpoint for the most recent large read.
+		expect(result[3]).toEqual({ role: "tool", tool_call_id: "call_1", content: largeFile })
+		expect(result[5]).toEqual({
+			role: "tool",
+			tool_call_id: "call_2",
+			content: [{ type: "text", text: largeFile, cache_control: cacheControl }],
+		})
+		expect(result[7]).toEqual({ role: "tool", tool_call_id: "call_3", content: "small" })
+	})
+
+	it("does not mark results of other tools", () => {
+		const messages: OpenAI.Chat.ChatCompletionMessageParam[] = [
+			{
+				role: "assistant",
+				content: null,
+				tool_calls: [
+					{ id: "call_1", type: "function", function: { name: "execute_command", arguments: "{}" } },
+				],
+			},
+			{ role: "tool", tool_call_id: "call_1", content: largeFile },
+		]
+
+		const result = addPromptCacheBreakpoints(systemPrompt, messages, ["fileReads"])
+
+		expect(result[2]).toEqual({ role: "tool", tool_call_id: "call_1", content: largeFile })
+	})
+
+	it("does not modify the original messages", () => {
+		const messages: OpenAI.Chat.ChatCompletionMessageParam[] = [
+			{ role: "user", content: [{ type: "text", text: environmentDetails }] },
+		]
+		const original = structuredClone(messages)
+
+		addPromptCacheBreakpoints(systemPrompt, messages, ["environmentDetails"])
+
+		expect(messages).toEqual(original)
+	})
+})
@@ -0,0 +1,108 @@
+import OpenAI from "openai"
+
+import type { PromptCacheBreakpoint } from "@roo-code/types"
+
+// Anthropic accepts at most four cache breakpoints per request.
+const MAX_BREAKPOINTS = 4
+
+// Smaller file reads aren't worth spending a breakpoint on.
+export const LARGE_FILE_READ_CHARS = 8_000
+
+type TextPart = OpenAI.Chat.ChatCompletionContentPartText & { cache_control?: { type: "ephemeral" } }
+
+const withCacheControl = (part: OpenAI.Chat.ChatCompletionContentPartText): TextPart => ({
+	...part,
+	cache_control: { type: "ephemeral" },
+})
+
+/**
+ * Adds `cache_control` breakpoints to the configured segments of a chat
+ * completion request, for OpenAI-compatible endpoints that pass them on to
+ * Anthropic-style prompt caching. Breakpoints go to the system prompt, then to
+ * the environment details of the last two user messages (which caches the
+ * conversation up to there), then to the most recent large file reads, until
+ * the four breakpoints Anthropic allows are used up.
+ *
+ * @returns The system message followed by `messages`
+ */
+export function addPromptCacheBreakpoints(
+	systemPrompt: string,
+	messages: OpenAI.Chat.ChatCompletionMessageParam[],
+	breakpoints: PromptCacheBreakpoint[],
+): OpenAI.Chat.ChatCompletionMessageParam[] {
+	let remaining = MAX_BREAKPOINTS
+	const result = [...messages]
+
+	let systemMessage: OpenAI.Chat.ChatCompletionSystemMessageParam = { role: "system", content: systemPrompt }
+
+	if (breakpoints.includes("systemPrompt")) {
+		systemMessage = { role: "system", content: [withCacheControl({ type: "text", text: systemPrompt })] }
+		remaining--
+	}
+
+	if (breakpoints.includes("environmentDetails")) {
+		const lastUserIndices = result.flatMap((msg, index) => (msg.role === "user" ? [index] : [])).slice(-2)
+
+		for (const index of lastUserIndices) {
+			result[index] = addEnvironmentDetailsBreakpoint(result[index] as OpenAI.Chat.ChatCompletionUserMessageParam)
+			remaining--
+		}
+	}
+
+	if (breakpoints.includes("fileReads")) {
+		const fileReadIds = new Set(
+			result.flatMap((msg) =>
+				msg.role === "assistant"
+					? (msg.tool_calls ?? [])
+							.filter((call) => call.type === "function" && call.function.name === "read_file")
+							.map((call) => call.id)
+					: [],
+			),
+		)
+
+		for (let index = result.length - 1; index >= 0 && remaining > 0; index--) {
+			const msg = result[index]
+
+			if (msg.role !== "tool" || !fileReadIds.has(msg.tool_call_id)) {
+				continue
+			}
+
+			const parts: OpenAI.Chat.ChatCompletionContentPartText[] =
+				typeof msg.content === "string" ? [{ type: "text", text: msg.content }] : msg.content
+
+			if (parts.reduce((length, part) => length + part.text.length, 0) < LARGE_FILE_READ_CHARS) {
+				continue
+			}
+
+			result[index] = { ...msg, content: [...parts.slice(0, -1), withCacheControl(parts[parts.length - 1])] }
+			remaining--
+		}
+	}
+
+	return [systemMessage, ...result]
+}
+
+function addEnvironmentDetailsBreakpoint(
+	msg: OpenAI.Chat.ChatCompletionUserMessageParam,
+): OpenAI.Chat.ChatCompletionUserMessageParam {
+	const parts: OpenAI.Chat.ChatCompletionContentPart[] =
+		typeof msg.content === "string" ? [{ type: "text", text: msg.content }] : [...msg.content]
+
+	// Environment details are appended last, so fall back to the last text part
+	// for messages that don't include them.
+	const textIndices = parts.flatMap((part, index) => (part.type === "text" ? [index] : []))
+	const environmentDetailsIndex = [...textIndices]
+		.reverse()
+		.find((index) =>
+			(parts[index] as OpenAI.Chat.ChatCompletionContentPartText).text.startsWith("<environment_details>"),
+		)
+	const index = environmentDetailsIndex ?? textIndices[textIndices.length - 1]
+
+	if (index === undefined) {
+		parts.push(withCacheControl({ type: "text", text: "..." }))
+	} else {
+		parts[index] = withCacheControl(parts[index] as OpenAI.Chat.ChatCompletionContentPartText)
+	}
+
+	return { ...msg, content: parts }
+}
@@ -0,0 +1,216 @@
+import { Anthropic } from "@anthropic-ai/sdk"
+import OpenAI from "openai"
+
+/**
+ * Tool calling for servers that constrain sampling with a JSON schema grammar, such as
+ * llama.cpp. Instead of relying on the chat template to emit tool calls, the model is told
+ * about the tools in the system prompt and its whole response is constrained to a JSON
+ * object with a message for the user and the tool calls to make. Small local models can't
+ * produce malformed tool calls that way.
+ */
+
+type FunctionTool = Extract<OpenAI.Chat.ChatCompletionTool, { type: "function" }>
+
+export interface GrammarToolCall {
+	name: string
+	arguments: Record<string, unknown>
+}
+
+export interface GrammarResponse {
+	message: string
+	tool_calls: GrammarToolCall[]
+}
+
+function getFunctionTools(tools: OpenAI.Chat.ChatCompletionTool[]): FunctionTool[] {
+	return tools.filter((tool): tool is FunctionTool => tool.type === "function")
+}
+
+/**
+ * Builds the JSON schema the response must match. Every tool call is constrained to the
+ * name and parameter schema of one of the tools.
+ */
+export function buildToolCallSchema(
+	tools: OpenAI.Chat.ChatCompletionTool[],
+	parallelToolCalls = true,
+): Record<string, unknown> {
+	const toolCallSchemas = getFunctionTools(tools).map(({ function: fn }) => ({
+		type: "object",
+		properties: {
+			name: { const: fn.name },
+			arguments: fn.parameters ?? { type: "object" },
+		},
+		required: ["name", "arguments"],
+		additionalProperties: false,
+	}))
+
+	return {
+		type: "object",
+		properties: {
+			message: { type: "string" },
+			tool_calls: {
+				type: "array",
+				items: { anyOf: toolCallSchemas },
+				minItems: 1,
+				...(parallelToolCalls ? {} : { maxItems: 1 }),
+			},
+		},
+		required: ["message", "tool_calls"],
+		additionalProperties: false,
+	}
+}
+
+/**
+ * Describes the tools and the expected response format for the system prompt, since the
+ * tool definitions aren't passed to the chat template.
+ */
+export function formatToolsForPrompt(tools: OpenAI.Chat.ChatCompletionTool[]): string {
+	const descriptions = getFunctionTools(tools).map(({ function: fn }) => {
+		const parameters = JSON.stringify(fn.parameters ?? { type: "object" })
+		return `## ${fn.name}\n${fn.description ?? ""}\nParameters: ${parameters}`
+	})
+
+	return [
+		"# Tool Calls",
+		'Respond with a single JSON object. Put what you want to tell the user in "message" and the tools to call in "tool_calls", each with the "name" of the tool and its "arguments". Results of the tool calls are returned in the next user message.',
+		"# Available Tools",
+		...descriptions,
+	].join("\n\n")
+}
+
+/**
+ * Converts the conversation so that previous tool calls are shown to the model in the same
+ * JSON format it has to answer in, and tool results become regular user content.
+ */
+export function convertToGrammarMessages(
+	messages: Anthropic.Messages.MessageParam[],
+): OpenAI.Chat.ChatCompletionMessageParam[] {
+	const toolNames = new Map<string, string>()
+	const result: OpenAI.Chat.ChatCompletionMessageParam[] = []
+
+	for (const message of messages) {
+		if (typeof message.content === "string") {
+			result.push({ role: message.role, content: message.content })
+			continue
+		}
+
+		if (message.role === "assistant") {
+			const text = message.content.flatMap((block) => (block.type === "text" ? [block.text] : [])).join("\n")
+			const toolCalls = message.content.flatMap((block) => {
+				if (block.type !== "tool_use") {
+					return []
+				}
+
+				toolNames.set(block.id, block.name)
+				return [{ name: block.name, arguments: block.input as Record<string, unknown> }]
+			})
+
+			result.push({
+				role: "assistant",
+				content: toolCalls.length > 0 ? JSON.stringify({ message: text, tool_calls: toolCalls }) : text,
+			})
+			continue
+		}
+
+		const parts: OpenAI.Chat.ChatCompletionContentPart[] = []
+
+		for (const block of message.content) {
+			if (block.type === "text") {
+				parts.push({ type: "text", text: block.text })
+			} else if (block.type === "image") {
+				parts.push(toImagePart(block))
+			} else if (block.type === "tool_result") {
+				const name = toolNames.get(block.tool_use_id) ?? "tool"
+				parts.push({ type: "text", text: `Result of ${name}${block.is_error ? " (error)" : ""}:` })
+
+				if (typeof block.content === "string") {
+					parts.push({ type: "text", text: block.content })
+					continue
+				}
+
+				for (const item of block.content ?? []) {
+					if (item.type === "text") {
+						parts.push({ type: "text", text: item.text })
+					} else if (item.type === "image") {
+						parts.push(toImagePart(item))
+					}
+				}
+			}
+		}
+
+		result.push({ role: "user", content: parts })
+	}
+
+	return result
+}
+
+function toImagePart(block: Anthropic.Messages.ImageBlockParam): OpenAI.Chat.ChatCompletionContentPartImage {
+	return {
+		type: "image_url",
+		image_url: { url: `data:${block.source.media_type};base64,${block.source.data}` },
+	}
+}
+
+/**
+ * Incrementally parses a streamed grammar response, so the message can be shown while the
+ * model is still writing it.
+ */
+export class GrammarResponseParser {
+	private text = ""
+	private emittedMessageLength = 0
+
+	/**
+	 * Adds streamed content and returns the part of the message that became available.
+	 */
+	update(delta: string): string {
+		this.text += delta
+		return this.takeMessageDelta(this.getPartialMessage())
+	}
+
+	/**
+	 * Parses the complete response. Returns undefined when the output isn't valid, e.g.
+	 * because generation stopped at the token limit.
+	 */
+	final(): { message: string; toolCalls: GrammarToolCall[] } | undefined {
+		let response: GrammarResponse
+
+		try {
+			response = JSON.parse(this.text)
+		} catch {
+			return undefined
+		}
+
+		if (typeof response?.message !== "string" || !Array.isArray(response.tool_calls)) {
+			return undefined
+		}
+
+		return { message: this.takeMessageDelta(response.message), toolCalls: response.tool_calls }
+	}
+
+	get rawText(): string {
+		return this.text
+	}
+
+	private takeMessageDelta(message: string | undefined): string {
+		if (!message || message.length <= this.emittedMessageLength) {
+			return ""
+		}
+
+		const delta = message.slice(this.emittedMessageLength)
+		this.emittedMessageLength = message.length
+		return delta
+	}
+
+	private getPartialMessage(): string | undefined {
+		const match = this.text.match(/^\s*\{\s*"message"\s*:\s*"((?:[^"\\]|\\.)*)/)
+		if (!match) {
+			return undefined
+		}
+
+		try {
+			// Fails while an escape sequence is incomplete, which is picked up on the next update
+			return JSON.parse(`"${match[1]}"`)
+		} catch {
+			return undefined
+		}
+	}
+}
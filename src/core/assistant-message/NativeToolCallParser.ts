@@ -464,6 +464,38 @@ export class NativeToolCallParser {
 				}
 				break
 
+			case "background_process":
+				if (partialArgs.action) {
+					nativeArgs = {
+						action: partialArgs.action,
+						command: partialArgs.command,
+						cwd: partialArgs.cwd,
+						process_id: partialArgs.process_id,
+					}
+				}
+				break
+
+			case "run_tests":
+				nativeArgs = {
+					path: partialArgs.path,
+					command: partialArgs.command,
+				}
+				break
+
+			case "git":
+				if (partialArgs.action) {
+					nativeArgs = {
+						action: partialArgs.action,
+						branch: partialArgs.branch,
+						files: Array.isArray(partialArgs.files) ? partialArgs.files : undefined,
+						message: partialArgs.message,
+						title: partialArgs.title,
+						body: partialArgs.body,
+						base: partialArgs.base,
+					}
+				}
+				break
+
 			case "write_to_file":
 				if (partialArgs.path || partialArgs.content) {
 					nativeArgs = {
@@ -496,6 +528,17 @@ export class NativeToolCallParser {
 					nativeArgs = {
 						query: partialArgs.query,
 						path: partialArgs.path,
+						language: partialArgs.language,
+						kind: partialArgs.kind,
+					}
+				}
+				break
+
+			case "find_references":
+				if (partialArgs.symbol !== undefined) {
+					nativeArgs = {
+						symbol: partialArgs.symbol,
+						path: partialArgs.path,
 					}
 				}
 				break
@@ -587,6 +630,28 @@ export class NativeToolCallParser {
 				}
 				break
 
+			case "edit_notebook":
+				if (partialArgs.path !== undefined || partialArgs.new_source !== undefined) {
+					nativeArgs = {
+						path: partialArgs.path,
+						cell_index: this.coerceOptionalNumber(partialArgs.cell_index),
+						new_source: partialArgs.new_source,
+						cell_type: partialArgs.cell_type,
+						edit_mode: partialArgs.edit_mode,
+					}
+				}
+				break
+
+			case "fast_edit":
+				if (partialArgs.path !== undefined || partialArgs.code_edit !== undefined) {
+					nativeArgs = {
+						path: partialArgs.path,
+						instructions: partialArgs.instructions,
+						code_edit: partialArgs.code_edit,
+					}
+				}
+				break
+
 			case "edit":
 			case "search_and_replace":
 				if (
@@ -637,6 +702,14 @@ export class NativeToolCallParser {
 				}
 				break
 
+			case "run_parallel_tasks":
+				if (Array.isArray(partialArgs.tasks)) {
+					nativeArgs = {
+						tasks: partialArgs.tasks,
+					}
+				}
+				break
+
 			default:
 				break
 		}
@@ -793,11 +866,66 @@ export class NativeToolCallParser {
 					}
 					break
 
+				case "background_process":
+					if (args.action !== undefined) {
+						nativeArgs = {
+							action: args.action,
+							command: args.command,
+							cwd: args.cwd,
+							process_id: args.process_id,
+						} as NativeArgsFor<TName>
+					}
+					break
+
+				case "run_tests":
+					nativeArgs = {
+						path: args.path,
+						command: args.command,
+					} as NativeArgsFor<TName>
+					break
+
+				case "git":
+					if (args.action !== undefined) {
+						nativeArgs = {
+							action: args.action,
+							branch: args.branch,
+							files: Array.isArray(args.files) ? args.files : undefined,
+							message: args.message,
+							title: args.title,
+							body: args.body,
+							base: args.base,
+						} as NativeArgsFor<TName>
+					}
+					break
+
 				case "apply_diff":
 					if (args.path !== undefined && args.diff !== undefined) {
 						nativeArgs = {
 							path: args.path,
 							diff: args.diff,
+							files: Array.isArray(args.files) ? args.files : undefined,
+						} as NativeArgsFor<TName>
+					}
+					break
+
+				case "edit_notebook":
+					if (args.path !== undefined && args.cell_index !== undefined && args.edit_mode !== undefined) {
+						nativeArgs = {
+							path: args.path,
+							cell_index: this.coerceOptionalNumber(args.cell_index),
+							new_source: args.new_source,
+							cell_type: args.cell_type,
+							edit_mode: args.edit_mode,
+						} as NativeArgsFor<TName>
+					}
+					break
+
+				case "fast_edit":
+					if (args.path !== undefined && args.instructions !== undefined && args.code_edit !== undefined) {
+						nativeArgs = {
+							path: args.path,
+							instructions: args.instructions,
+							code_edit: args.code_edit,
 						} as NativeArgsFor<TName>
 					}
 					break
@@ -832,6 +960,17 @@ export class NativeToolCallParser {
 						nativeArgs = {
 							query: args.query,
 							path: args.path,
+							language: args.language,
+							kind: args.kind,
+						} as NativeArgsFor<TName>
+					}
+					break
+
+				case "find_references":
+					if (args.symbol !== undefined) {
+						nativeArgs = {
+							symbol: args.symbol,
+							path: args.path,
 						} as NativeArgsFor<TName>
 					}
 					break
@@ -986,6 +1125,14 @@ export class NativeToolCallParser {
 					}
 					break
 
+				case "run_parallel_tasks":
+					if (Array.isArray(args.tasks)) {
+						nativeArgs = {
+							tasks: args.tasks,
+						} as NativeArgsFor<TName>
+					}
+					break
+
 				default:
 					if (customToolRegistry.has(resolvedName)) {
 						nativeArgs = args as NativeArgsFor<TName>
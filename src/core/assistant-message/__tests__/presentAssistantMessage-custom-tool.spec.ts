@@ -65,6 +65,9 @@ describe("presentAssistantMessage - Custom Tool Recording", () => {
 			toolRepetitionDetector: {
 				check: vi.fn().mockReturnValue({ allowExecution: true }),
 			},
+			getProviderState() {
+				return this.providerRef.deref()?.getState()
+			},
 			providerRef: {
 				deref: () => ({
 					getState: vi.fn().mockResolvedValue({
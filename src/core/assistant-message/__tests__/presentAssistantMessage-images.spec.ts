@@ -49,6 +49,10 @@ describe("presentAssistantMessage - Image Handling in Native Tool Calling", () =
 			toolRepetitionDetector: {
 				check: vi.fn().mockReturnValue({ allowExecution: true }),
 			},
+			pendingEdits: { size: 0 },
+			getProviderState() {
+				return this.providerRef.deref()?.getState()
+			},
 			providerRef: {
 				deref: () => ({
 					getState: vi.fn().mockResolvedValue({
@@ -0,0 +1,146 @@
+// npx vitest src/core/assistant-message/__tests__/presentAssistantMessage-pending-edits.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest"
+import { presentAssistantMessage } from "../presentAssistantMessage"
+import { PendingEdits } from "../../pending-edits/PendingEdits"
+import { runParallelTasksTool } from "../../tools/RunParallelTasksTool"
+
+// Mock dependencies
+vi.mock("../../task/Task")
+vi.mock("../../tools/validateToolUse", () => ({
+	validateToolUse: vi.fn(),
+	isValidToolName: vi.fn((toolName: string) => toolName === "run_parallel_tasks"),
+}))
+vi.mock("../../tools/RunParallelTasksTool", () => ({
+	runParallelTasksTool: {
+		handle: vi.fn(),
+	},
+}))
+vi.mock("@roo-code/telemetry", () => ({
+	TelemetryService: {
+		instance: {
+			captureToolUsage: vi.fn(),
+			captureConsecutiveMistakeError: vi.fn(),
+		},
+	},
+}))
+
+describe("presentAssistantMessage - Staged edits before parallel subtasks", () => {
+	let cwd: string
+	let mockTask: any
+	let contentSeenBySubtasks: string | undefined
+
+	const toolCallId = "tool_call_parallel_123"
+
+	beforeEach(async () => {
+		vi.clearAllMocks()
+
+		cwd = await fs.mkdtemp(path.join(os.tmpdir(), "pending-edits-"))
+		await fs.writeFile(path.join(cwd, "a.ts"), "const a = 1\n")
+
+		mockTask = {
+			taskId: "test-task-id",
+			instanceId: "test-instance",
+			cwd,
+			abort: false,
+			presentAssistantMessageLocked: false,
+			presentAssistantMessageHasPendingUpdates: false,
+			currentStreamingContentIndex: 0,
+			currentStreamingDidCheckpoint: false,
+			assistantMessageContent: [
+				{
+					type: "tool_use",
+					id: toolCallId,
+					name: "run_parallel_tasks",
+					params: {},
+					nativeArgs: { tasks: [{ mode: "code", message: "Write the tests" }] },
+					partial: false,
+				},
+			],
+			userMessageContent: [],
+			didCompleteReadingStream: false,
+			didRejectTool: false,
+			didAlreadyUseTool: false,
+			didEditFile: false,
+			consecutiveMistakeCount: 0,
+			clineMessages: [],
+			pendingEdits: new PendingEdits(),
+			api: {
+				getModel: () => ({ id: "test-model", info: {} }),
+			},
+			recordToolUsage: vi.fn(),
+			recordToolError: vi.fn(),
+			checkpointSave: vi.fn().mockResolvedValue(undefined),
+			fileContextTracker: { trackFileContext: vi.fn().mockResolvedValue(undefined) },
+			toolRepetitionDetector: {
+				check: vi.fn().mockReturnValue({ allowExecution: true }),
+			},
+			getProviderState() {
+				return this.providerRef.deref()?.getState()
+			},
+			providerRef: {
+				deref: () => ({
+					getState: vi.fn().mockResolvedValue({
+						mode: "code",
+						customModes: [],
+						experiments: { dryRunEdits: true },
+					}),
+				}),
+			},
+			say: vi.fn().mockResolvedValue(undefined),
+			ask: vi.fn().mockResolvedValue({ response: "yesButtonClicked" }),
+		}
+
+		mockTask.pushToolResultToUserContent = vi.fn().mockImplementation((toolResult: any) => {
+			mockTask.userMessageContent.push(toolResult)
+			return true
+		})
+
+		await mockTask.pendingEdits.stage("a.ts", path.join(cwd, "a.ts"), "const a = 10\n")
+
+		contentSeenBySubtasks = undefined
+		vi.mocked(runParallelTasksTool.handle).mockImplementation(async () => {
+			contentSeenBySubtasks = await fs.readFile(path.join(cwd, "a.ts"), "utf-8")
+		})
+	})
+
+	afterEach(async () => {
+		await fs.rm(cwd, { recursive: true, force: true })
+	})
+
+	it("writes the approved staged edits before any subtask starts", async () => {
+		await presentAssistantMessage(mockTask)
+
+		expect(mockTask.ask).toHaveBeenCalledWith(
+			"tool",
+			expect.stringContaining('"pendingChanges"'),
+			false,
+			undefined,
+			false,
+		)
+		expect(mockTask.ask.mock.invocationCallOrder[0]).toBeLessThan(
+			vi.mocked(runParallelTasksTool.handle).mock.invocationCallOrder[0],
+		)
+		expect(contentSeenBySubtasks).toBe("const a = 10\n")
+		expect(mockTask.pendingEdits.size).toBe(0)
+		expect(mockTask.didEditFile).toBe(true)
+	})
+
+	it("doesn't start the subtasks when the staged edits are rejected", async () => {
+		mockTask.ask.mockResolvedValue({ response: "noButtonClicked" })
+
+		await presentAssistantMessage(mockTask)
+
+		expect(runParallelTasksTool.handle).not.toHaveBeenCalled()
+		expect(await fs.readFile(path.join(cwd, "a.ts"), "utf-8")).toBe("const a = 1\n")
+		expect(mockTask.pendingEdits.size).toBe(0)
+		expect(mockTask.didRejectTool).toBe(true)
+
+		const toolResult = mockTask.userMessageContent.find((item: any) => item.tool_use_id === toolCallId)
+		expect(toolResult.content).toContain("the tool was not run")
+	})
+})
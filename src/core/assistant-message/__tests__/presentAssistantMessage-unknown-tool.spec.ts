@@ -45,6 +45,9 @@ describe("presentAssistantMessage - Unknown Tool Handling", () => {
 			toolRepetitionDetector: {
 				check: vi.fn().mockReturnValue({ allowExecution: true }),
 			},
+			getProviderState() {
+				return this.providerRef.deref()?.getState()
+			},
 			providerRef: {
 				deref: () => ({
 					getState: vi.fn().mockResolvedValue({
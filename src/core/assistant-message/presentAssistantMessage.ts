@@ -19,6 +19,8 @@ import { readFileTool } from "../tools/ReadFileTool"
 import { readCommandOutputTool } from "../tools/ReadCommandOutputTool"
 import { writeToFileTool } from "../tools/WriteToFileTool"
 import { editTool } from "../tools/EditTool"
+import { fastEditTool } from "../tools/FastEditTool"
+import { editNotebookTool } from "../tools/EditNotebookTool"
 import { searchReplaceTool } from "../tools/SearchReplaceTool"
 import { editFileTool } from "../tools/EditFileTool"
 import { applyPatchTool } from "../tools/ApplyPatchTool"
@@ -30,6 +32,7 @@ import { askFollowupQuestionTool } from "../tools/AskFollowupQuestionTool"
 import { switchModeTool } from "../tools/SwitchModeTool"
 import { attemptCompletionTool, AttemptCompletionCallbacks } from "../tools/AttemptCompletionTool"
 import { newTaskTool } from "../tools/NewTaskTool"
+import { runParallelTasksTool } from "../tools/RunParallelTasksTool"
 import { updateTodoListTool } from "../tools/UpdateTodoListTool"
 import { runSlashCommandTool } from "../tools/RunSlashCommandTool"
 import { skillTool } from "../tools/SkillTool"
@@ -37,6 +40,11 @@ import { generateImageTool } from "../tools/GenerateImageTool"
 import { applyDiffTool as applyDiffToolClass } from "../tools/ApplyDiffTool"
 import { isValidToolName, validateToolUse } from "../tools/validateToolUse"
 import { codebaseSearchTool } from "../tools/CodebaseSearchTool"
+import { findReferencesTool } from "../tools/FindReferencesTool"
+import { backgroundProcessTool } from "../tools/BackgroundProcessTool"
+import { runTestsTool } from "../tools/RunTestsTool"
+import { gitTool } from "../tools/GitTool"
+import { PENDING_EDITS_REVIEW_TOOLS, reviewPendingEdits } from "../pending-edits/review"
 
 import { formatResponse } from "../prompts/responses"
 import { sanitizeToolUseId } from "../../utils/tool-id"
@@ -273,6 +281,7 @@ export async function presentAssistantMessage(cline: Task) {
 				askApproval,
 				handleError,
 				pushToolResult,
+				toolCallId: mcpBlock.id,
 			})
 			break
 		}
@@ -321,13 +330,21 @@ export async function presentAssistantMessage(cline: Task) {
 			}
 
 			// Fetch state early so it's available for toolDescription and validation
-			const state = await cline.providerRef.deref()?.getState()
+			const state = await cline.getProviderState()
 			const { mode, customModes, experiments: stateExperiments, disabledTools } = state ?? {}
 
 			const toolDescription = (): string => {
 				switch (block.name) {
 					case "execute_command":
 						return `[${block.name} for '${block.params.command}']`
+					case "background_process":
+						return block.params.action === "start"
+							? `[${block.name} start '${block.params.command}']`
+							: `[${block.name} ${block.params.action}]`
+					case "run_tests":
+						return block.params.path ? `[${block.name} for '${block.params.path}']` : `[${block.name}]`
+					case "git":
+						return `[${block.name} ${block.params.action}]`
 					case "read_file":
 						// Prefer native typed args when available; fall back to legacy params
 						// Check if nativeArgs exists (native protocol)
@@ -339,11 +356,18 @@ export async function presentAssistantMessage(cline: Task) {
 						return `[${block.name} for '${block.params.path}']`
 					case "apply_diff":
 						// Native-only: tool args are structured (no XML payloads).
+						const additionalFiles = (block as ToolUse<"apply_diff">).nativeArgs?.files?.length
+						if (additionalFiles) {
+							return `[${block.name} for '${block.params.path}' and ${additionalFiles} more files]`
+						}
 						return block.params?.path ? `[${block.name} for '${block.params.path}']` : `[${block.name}]`
 					case "search_files":
 						return `[${block.name} for '${block.params.regex}'${
 							block.params.file_pattern ? ` in '${block.params.file_pattern}'` : ""
 						}]`
+					case "fast_edit":
+					case "edit_notebook":
+						return `[${block.name} for '${block.params.path}']`
 					case "edit":
 					case "search_and_replace":
 						return `[${block.name} for '${block.params.file_path}']`
@@ -367,6 +391,8 @@ export async function presentAssistantMessage(cline: Task) {
 						return `[${block.name} to '${block.params.mode_slug}'${block.params.reason ? ` because: ${block.params.reason}` : ""}]`
 					case "codebase_search":
 						return `[${block.name} for '${block.params.query}']`
+					case "find_references":
+						return `[${block.name} for '${block.params.symbol}']`
 					case "read_command_output":
 						return `[${block.name} for '${block.params.artifact_id}']`
 					case "update_todo_list":
@@ -377,6 +403,8 @@ export async function presentAssistantMessage(cline: Task) {
 						const modeName = getModeBySlug(mode, customModes)?.name ?? mode
 						return `[${block.name} in ${modeName} mode: '${message}']`
 					}
+					case "run_parallel_tasks":
+						return `[${block.name}]`
 					case "run_slash_command":
 						return `[${block.name} for '${block.params.command}'${block.params.args ? ` with args: ${block.params.args}` : ""}]`
 					case "skill":
@@ -675,6 +703,22 @@ export async function presentAssistantMessage(cline: Task) {
 				}
 			}
 
+			// Staged dry-run edits are reviewed before a tool that depends on the files on disk.
+			if (!block.partial && PENDING_EDITS_REVIEW_TOOLS.has(block.name) && cline.pendingEdits.size > 0) {
+				await checkpointSaveAndMark(cline)
+				const review = await reviewPendingEdits(cline)
+
+				if (!review.approved) {
+					cline.didRejectTool = true
+					pushToolResult(formatResponse.toolResult(review.result ?? "", review.images))
+					break
+				}
+
+				if (review.result) {
+					approvalFeedback = { text: review.result, images: review.images }
+				}
+			}
+
 			switch (block.name) {
 				case "write_to_file":
 					await checkpointSaveAndMark(cline)
@@ -699,6 +743,22 @@ export async function presentAssistantMessage(cline: Task) {
 						pushToolResult,
 					})
 					break
+				case "edit_notebook":
+					await checkpointSaveAndMark(cline)
+					await editNotebookTool.handle(cline, block as ToolUse<"edit_notebook">, {
+						askApproval,
+						handleError,
+						pushToolResult,
+					})
+					break
+				case "fast_edit":
+					await checkpointSaveAndMark(cline)
+					await fastEditTool.handle(cline, block as ToolUse<"fast_edit">, {
+						askApproval,
+						handleError,
+						pushToolResult,
+					})
+					break
 				case "edit":
 				case "search_and_replace":
 					await checkpointSaveAndMark(cline)
@@ -754,6 +814,13 @@ export async function presentAssistantMessage(cline: Task) {
 						pushToolResult,
 					})
 					break
+				case "find_references":
+					await findReferencesTool.handle(cline, block as ToolUse<"find_references">, {
+						askApproval,
+						handleError,
+						pushToolResult,
+					})
+					break
 				case "search_files":
 					await searchFilesTool.handle(cline, block as ToolUse<"search_files">, {
 						askApproval,
@@ -768,6 +835,27 @@ export async function presentAssistantMessage(cline: Task) {
 						pushToolResult,
 					})
 					break
+				case "background_process":
+					await backgroundProcessTool.handle(cline, block as ToolUse<"background_process">, {
+						askApproval,
+						handleError,
+						pushToolResult,
+					})
+					break
+				case "run_tests":
+					await runTestsTool.handle(cline, block as ToolUse<"run_tests">, {
+						askApproval,
+						handleError,
+						pushToolResult,
+					})
+					break
+				case "git":
+					await gitTool.handle(cline, block as ToolUse<"git">, {
+						askApproval,
+						handleError,
+						pushToolResult,
+					})
+					break
 				case "read_command_output":
 					await readCommandOutputTool.handle(cline, block as ToolUse<"read_command_output">, {
 						askApproval,
@@ -780,6 +868,7 @@ export async function presentAssistantMessage(cline: Task) {
 						askApproval,
 						handleError,
 						pushToolResult,
+						toolCallId: block.id,
 					})
 					break
 				case "access_mcp_resource":
@@ -812,6 +901,14 @@ export async function presentAssistantMessage(cline: Task) {
 						toolCallId: block.id,
 					})
 					break
+				case "run_parallel_tasks":
+					await checkpointSaveAndMark(cline)
+					await runParallelTasksTool.handle(cline, block as ToolUse<"run_parallel_tasks">, {
+						askApproval,
+						handleError,
+						pushToolResult,
+					})
+					break
 				case "attempt_completion": {
 					const completionCallbacks: AttemptCompletionCallbacks = {
 						askApproval,
@@ -0,0 +1,47 @@
+import type { ClineSayTool } from "@roo-code/types"
+
+import { isWritePathAutoApproved } from "../paths"
+
+const write = (path: string): ClineSayTool => ({ tool: "editedExistingFile", path })
+
+describe("isWritePathAutoApproved", () => {
+	it("should approve any path when no patterns are set", () => {
+		expect(isWritePathAutoApproved(write("src/index.ts"))).toBe(true)
+		expect(isWritePathAutoApproved(write("../outside.ts"), [], [])).toBe(true)
+	})
+
+	it("should only approve paths matching an allowed pattern", () => {
+		const allowed = ["src/**", "tests/**"]
+
+		expect(isWritePathAutoApproved(write("src/core/index.ts"), allowed)).toBe(true)
+		expect(isWritePathAutoApproved(write("tests/index.spec.ts"), allowed)).toBe(true)
+		expect(isWritePathAutoApproved(write("package.json"), allowed)).toBe(false)
+		expect(isWritePathAutoApproved(write("../src/index.ts"), allowed)).toBe(false)
+		expect(isWritePathAutoApproved(write("/tmp/src/index.ts"), allowed)).toBe(false)
+	})
+
+	it("should never approve paths matching a denied pattern", () => {
+		const denied = [".env*", ".github/**"]
+
+		expect(isWritePathAutoApproved(write(".env"), ["**"], denied)).toBe(false)
+		expect(isWritePathAutoApproved(write("packages/api/.env.local"), [], denied)).toBe(false)
+		expect(isWritePathAutoApproved(write(".github/workflows/ci.yml"), [], denied)).toBe(false)
+		expect(isWritePathAutoApproved(write("src/env.ts"), [], denied)).toBe(true)
+	})
+
+	it("should require every file of a batch to be approved", () => {
+		const batch = (...paths: string[]): ClineSayTool => ({
+			tool: "appliedDiff",
+			path: paths[0],
+			batchDiffs: paths.map((path) => ({ path, changeCount: 1, key: path, content: "" })),
+		})
+
+		expect(isWritePathAutoApproved(batch("src/a.ts", "src/b.ts"), ["src/**"])).toBe(true)
+		expect(isWritePathAutoApproved(batch("src/a.ts", "scripts/b.ts"), ["src/**"])).toBe(false)
+	})
+
+	it("should normalize Windows separators and leading ./", () => {
+		expect(isWritePathAutoApproved(write("src\\core\\index.ts"), ["src/**"])).toBe(true)
+		expect(isWritePathAutoApproved(write("./src/index.ts"), ["src/**"])).toBe(true)
+	})
+})
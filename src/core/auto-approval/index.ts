@@ -1,4 +1,5 @@
 import {
+	type AutoApprovalProfile,
 	type ClineAsk,
 	type ClineSayTool,
 	type McpServerUse,
@@ -10,6 +11,7 @@ import {
 import { ClineAskResponse } from "../../shared/WebviewMessage"
 
 import { isWriteToolAction, isReadOnlyToolAction } from "./tools"
+import { isWritePathAutoApproved } from "./paths"
 import { isMcpToolAlwaysAllowed } from "./mcp"
 import { getCommandDecision } from "./commands"
 
@@ -29,6 +31,8 @@ export type AutoApprovalStateOptions =
 	| "alwaysAllowReadOnlyOutsideWorkspace" // For `alwaysAllowReadOnly`.
 	| "alwaysAllowWriteOutsideWorkspace" // For `alwaysAllowWrite`.
 	| "alwaysAllowWriteProtected"
+	| "allowedWritePaths" // For `alwaysAllowWrite`.
+	| "deniedWritePaths"
 	| "followupAutoApproveTimeoutMs" // For `alwaysAllowFollowupQuestions`.
 	| "mcpServers" // For `alwaysAllowMcp`.
 	| "allowedCommands" // For `alwaysAllowExecute`.
@@ -157,7 +161,7 @@ export async function checkAutoApproval({
 			return state.alwaysAllowModeSwitch === true ? { decision: "approve" } : { decision: "ask" }
 		}
 
-		if (["newTask", "finishTask"].includes(tool?.tool)) {
+		if (["newTask", "runParallelTasks", "finishTask"].includes(tool?.tool)) {
 			return state.alwaysAllowSubtasks === true ? { decision: "approve" } : { decision: "ask" }
 		}
 
@@ -173,7 +177,8 @@ export async function checkAutoApproval({
 		if (isWriteToolAction(tool)) {
 			return state.alwaysAllowWrite === true &&
 				(!isOutsideWorkspace || state.alwaysAllowWriteOutsideWorkspace === true) &&
-				(!isProtected || state.alwaysAllowWriteProtected === true)
+				(!isProtected || state.alwaysAllowWriteProtected === true) &&
+				isWritePathAutoApproved(tool, state.allowedWritePaths, state.deniedWritePaths)
 				? { decision: "approve" }
 				: { decision: "ask" }
 		}
@@ -182,4 +187,33 @@ export async function checkAutoApproval({
 	return { decision: "ask" }
 }
 
+/**
+ * Replaces the auto-approval settings in `state` with those of `profile`. The
+ * actions the profile doesn't allow need approval, while the user's denied
+ * commands and write paths still apply on top of the profile's.
+ */
+export function applyAutoApprovalProfile<T extends Pick<ExtensionState, AutoApprovalState | AutoApprovalStateOptions>>(
+	state: T,
+	profile: AutoApprovalProfile,
+): T {
+	return {
+		...state,
+		autoApprovalEnabled: true,
+		alwaysAllowReadOnly: profile.alwaysAllowReadOnly ?? false,
+		alwaysAllowReadOnlyOutsideWorkspace: profile.alwaysAllowReadOnlyOutsideWorkspace ?? false,
+		alwaysAllowWrite: profile.alwaysAllowWrite ?? false,
+		alwaysAllowWriteOutsideWorkspace: profile.alwaysAllowWriteOutsideWorkspace ?? false,
+		alwaysAllowWriteProtected: profile.alwaysAllowWriteProtected ?? false,
+		allowedWritePaths: profile.allowedWritePaths ?? [],
+		deniedWritePaths: [...(state.deniedWritePaths ?? []), ...(profile.deniedWritePaths ?? [])],
+		alwaysAllowMcp: profile.alwaysAllowMcp ?? false,
+		alwaysAllowModeSwitch: profile.alwaysAllowModeSwitch ?? false,
+		alwaysAllowSubtasks: profile.alwaysAllowSubtasks ?? false,
+		alwaysAllowExecute: profile.alwaysAllowExecute ?? false,
+		alwaysAllowFollowupQuestions: false,
+		allowedCommands: profile.allowedCommands ?? [],
+		deniedCommands: [...(state.deniedCommands ?? []), ...(profile.deniedCommands ?? [])],
+	}
+}
+
 export { AutoApprovalHandler } from "./AutoApprovalHandler"
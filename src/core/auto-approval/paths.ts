@@ -0,0 +1,49 @@
+import ignore from "ignore"
+
+import type { ClineSayTool } from "@roo-code/types"
+
+/**
+ * Returns the workspace-relative paths a write tool action changes, including
+ * every file of a batch.
+ */
+function getWritePaths(tool: ClineSayTool): string[] {
+	const paths = tool.batchDiffs?.map(({ path }) => path) ?? []
+	return paths.length > 0 ? paths : tool.path ? [tool.path] : []
+}
+
+/**
+ * Decides whether the paths of a write can be auto-approved. The patterns use
+ * .gitignore syntax, so `src/**` matches everything under src and `.env*`
+ * matches env files in any directory. A write is only auto-approved if no path
+ * matches a denied pattern and, when allowed patterns are set, every path
+ * matches one of them. Paths outside the workspace never match a pattern.
+ */
+export function isWritePathAutoApproved(
+	tool: ClineSayTool,
+	allowedWritePaths: string[] = [],
+	deniedWritePaths: string[] = [],
+): boolean {
+	const allowed = allowedWritePaths.map((pattern) => pattern.trim()).filter(Boolean)
+	const denied = deniedWritePaths.map((pattern) => pattern.trim()).filter(Boolean)
+
+	if (allowed.length === 0 && denied.length === 0) {
+		return true
+	}
+
+	const paths = getWritePaths(tool).map((path) => path.replace(/\\/g, "/").replace(/^\.\//, ""))
+
+	if (paths.length === 0) {
+		return false
+	}
+
+	const allowedMatcher = ignore().add(allowed)
+	const deniedMatcher = ignore().add(denied)
+
+	return paths.every((path) => {
+		if (!ignore.isPathValid(path)) {
+			return allowed.length === 0
+		}
+
+		return !deniedMatcher.ignores(path) && (allowed.length === 0 || allowedMatcher.ignores(path))
+	})
+}
@@ -1,7 +1,13 @@
 import { describe, it, expect, vi, beforeEach, afterEach, Mock } from "vitest"
 import { Task } from "../../task/Task"
 import { ClineProvider } from "../../webview/ClineProvider"
-import { checkpointSave, checkpointRestore, checkpointDiff, getCheckpointService } from "../index"
+import {
+	checkpointSave,
+	checkpointRestore,
+	checkpointRestoreFiles,
+	checkpointDiff,
+	getCheckpointService,
+} from "../index"
 import { MessageManager } from "../../message-manager"
 import * as vscode from "vscode"
 
@@ -11,6 +17,7 @@ vi.mock("vscode", () => ({
 		showErrorMessage: vi.fn(),
 		createTextEditorDecorationType: vi.fn(() => ({})),
 		showInformationMessage: vi.fn(),
+		showQuickPick: vi.fn(),
 	},
 	Uri: {
 		file: vi.fn((path: string) => ({ fsPath: path })),
@@ -70,6 +77,8 @@ describe("Checkpoint functionality", () => {
 			isInitialized: true,
 			saveCheckpoint: vi.fn().mockResolvedValue({ commit: "test-commit-hash" }),
 			restoreCheckpoint: vi.fn().mockResolvedValue(undefined),
+			restoreFiles: vi.fn().mockResolvedValue(undefined),
+			hasCheckpoint: vi.fn().mockResolvedValue(true),
 			getDiff: vi.fn().mockResolvedValue([]),
 			on: vi.fn(),
 			initShadowGit: vi.fn().mockResolvedValue(undefined),
@@ -282,6 +291,16 @@ describe("Checkpoint functionality", () => {
 			expect(mockCheckpointService.restoreCheckpoint).not.toHaveBeenCalled()
 		})
 
+		it("should not restore a checkpoint removed by the retention policy", async () => {
+			mockCheckpointService.hasCheckpoint.mockResolvedValue(false)
+
+			await checkpointRestore(mockTask, { ts: 2, commitHash: "abc123", mode: "restore" })
+
+			expect(mockCheckpointService.restoreCheckpoint).not.toHaveBeenCalled()
+			expect(vscode.window.showInformationMessage).toHaveBeenCalledWith("common:errors.checkpoint_pruned")
+			expect(mockTask.enableCheckpoints).toBe(true)
+		})
+
 		it("should disable checkpoints on error", async () => {
 			mockCheckpointService.restoreCheckpoint.mockRejectedValue(new Error("Restore failed"))
 
@@ -403,6 +422,87 @@ describe("Checkpoint functionality", () => {
 			expect(mockTask.enableCheckpoints).toBe(false)
 			expect(mockProvider.log).toHaveBeenCalledWith("[checkpointDiff] disabling checkpoints for this task")
 		})
+
+		it("should diff two checkpoints in compare mode, earlier first", async () => {
+			mockCheckpointService.getDiff.mockResolvedValue([
+				{
+					paths: { absolute: "/test/file.ts", relative: "file.ts" },
+					content: { before: "old content", after: "new content" },
+				},
+			])
+
+			await checkpointDiff(mockTask, { ts: 4, commitHash: "commit2", toCommitHash: "commit1", mode: "compare" })
+
+			expect(vscode.window.showQuickPick).not.toHaveBeenCalled()
+			expect(mockCheckpointService.getDiff).toHaveBeenCalledWith({ from: "commit1", to: "commit2" })
+			expect(vscode.commands.executeCommand).toHaveBeenCalledWith(
+				"vscode.changes",
+				"common:errors.checkpoint_diff_between",
+				expect.any(Array),
+			)
+		})
+
+		it("should let the user pick the other checkpoint in compare mode", async () => {
+			vi.mocked(vscode.window.showQuickPick).mockImplementation(async (items: any) => items[0])
+
+			await checkpointDiff(mockTask, { ts: 2, commitHash: "commit1", mode: "compare" })
+
+			const items = vi.mocked(vscode.window.showQuickPick).mock.calls[0][0]
+			expect(items).toEqual([expect.objectContaining({ commitHash: "commit2" })])
+			expect(mockCheckpointService.getDiff).toHaveBeenCalledWith({ from: "commit1", to: "commit2" })
+		})
+
+		it("should not diff when no other checkpoint is picked", async () => {
+			vi.mocked(vscode.window.showQuickPick).mockResolvedValue(undefined)
+
+			await checkpointDiff(mockTask, { ts: 2, commitHash: "commit1", mode: "compare" })
+
+			expect(mockCheckpointService.getDiff).not.toHaveBeenCalled()
+		})
+	})
+
+	describe("checkpointRestoreFiles", () => {
+		it("should restore the given files without rewinding the task", async () => {
+			await checkpointRestoreFiles(mockTask, { commitHash: "commit1", paths: ["src/a.ts"] })
+
+			expect(mockCheckpointService.restoreFiles).toHaveBeenCalledWith("commit1", ["src/a.ts"])
+			expect(mockCheckpointService.restoreCheckpoint).not.toHaveBeenCalled()
+			expect(mockProvider.cancelTask).not.toHaveBeenCalled()
+			expect(vscode.window.showInformationMessage).toHaveBeenCalledWith("common:info.checkpoint_files_restored")
+		})
+
+		it("should let the user pick from the files changed since the checkpoint", async () => {
+			mockCheckpointService.getDiff.mockResolvedValue([
+				{ paths: { absolute: "/test/a.ts", relative: "a.ts" }, content: { before: "a", after: "b" } },
+				{ paths: { absolute: "/test/b.ts", relative: "b.ts" }, content: { before: "", after: "c" } },
+			])
+			vi.mocked(vscode.window.showQuickPick).mockResolvedValue([{ label: "b.ts" }] as any)
+
+			await checkpointRestoreFiles(mockTask, { commitHash: "commit1" })
+
+			expect(mockCheckpointService.getDiff).toHaveBeenCalledWith({ from: "commit1" })
+			expect(mockCheckpointService.restoreFiles).toHaveBeenCalledWith("commit1", ["b.ts"])
+		})
+
+		it("should not restore anything when no files are picked", async () => {
+			mockCheckpointService.getDiff.mockResolvedValue([
+				{ paths: { absolute: "/test/a.ts", relative: "a.ts" }, content: { before: "a", after: "b" } },
+			])
+			vi.mocked(vscode.window.showQuickPick).mockResolvedValue(undefined)
+
+			await checkpointRestoreFiles(mockTask, { commitHash: "commit1" })
+
+			expect(mockCheckpointService.restoreFiles).not.toHaveBeenCalled()
+		})
+
+		it("should show an error without disabling checkpoints when the restore fails", async () => {
+			mockCheckpointService.restoreFiles.mockRejectedValue(new Error("Restore failed"))
+
+			await checkpointRestoreFiles(mockTask, { commitHash: "commit1", paths: ["a.ts"] })
+
+			expect(vscode.window.showErrorMessage).toHaveBeenCalledWith("common:errors.checkpoint_failed")
+			expect(mockTask.enableCheckpoints).toBe(true)
+		})
 	})
 
 	describe("getCheckpointService", () => {
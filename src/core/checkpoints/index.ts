@@ -253,6 +253,11 @@ export async function checkpointRestore(
 	const provider = task.providerRef.deref()
 
 	try {
+		if (!(await service.hasCheckpoint(commitHash))) {
+			vscode.window.showInformationMessage(t("common:errors.checkpoint_pruned"))
+			return
+		}
+
 		await service.restoreCheckpoint(commitHash)
 		TelemetryService.instance.captureCheckpointRestored(task.taskId)
 		await provider?.postMessageToWebview({ type: "currentCheckpointUpdated", text: commitHash })
@@ -301,6 +306,58 @@ export async function checkpointRestore(
 	}
 }
 
+export type CheckpointRestoreFilesOptions = {
+	commitHash: string
+	// Workspace-relative paths; the user picks from the files changed since the checkpoint if they're not given.
+	paths?: string[]
+}
+
+/**
+ * Restores the given files to their state in a checkpoint without rewinding
+ * the task or touching the rest of the workspace.
+ */
+export async function checkpointRestoreFiles(task: Task, { commitHash, paths }: CheckpointRestoreFilesOptions) {
+	const service = await getCheckpointService(task)
+
+	if (!service) {
+		return
+	}
+
+	try {
+		if (!(await service.hasCheckpoint(commitHash))) {
+			vscode.window.showInformationMessage(t("common:errors.checkpoint_pruned"))
+			return
+		}
+
+		if (!paths) {
+			const changes = await service.getDiff({ from: commitHash })
+
+			if (!changes.length) {
+				vscode.window.showInformationMessage(t("common:errors.checkpoint_no_changes"))
+				return
+			}
+
+			const picked = await vscode.window.showQuickPick(
+				changes.map((change) => ({ label: change.paths.relative })),
+				{ canPickMany: true, placeHolder: t("common:checkpoints.select_files") },
+			)
+
+			if (!picked?.length) {
+				return
+			}
+
+			paths = picked.map(({ label }) => label)
+		}
+
+		await service.restoreFiles(commitHash, paths)
+		TelemetryService.instance.captureCheckpointRestored(task.taskId)
+		vscode.window.showInformationMessage(t("common:info.checkpoint_files_restored", { count: paths.length }))
+	} catch (err) {
+		task.providerRef.deref()?.log(`[checkpointRestoreFiles] ${err.message}`)
+		vscode.window.showErrorMessage(t("common:errors.checkpoint_failed"))
+	}
+}
+
 export type CheckpointDiffOptions = {
 	ts?: number
 	previousCommitHash?: string
@@ -310,11 +367,36 @@ export type CheckpointDiffOptions = {
 	 * checkpoint: Compare the selected checkpoint to the next checkpoint.
 	 * to-current: Compare the selected checkpoint to the current workspace.
 	 * full: Compare from the first checkpoint to the current workspace.
+	 * compare: Compare the selected checkpoint to another checkpoint.
 	 */
-	mode: "from-init" | "checkpoint" | "to-current" | "full"
+	mode: "from-init" | "checkpoint" | "to-current" | "full" | "compare"
+	// The other checkpoint in "compare" mode; the user picks one if it's not given.
+	toCommitHash?: string
+}
+
+async function pickCheckpoint(task: Task, excludeCommitHash: string) {
+	const items = task.clineMessages
+		.filter(({ say, text }) => say === "checkpoint_saved" && text && text !== excludeCommitHash)
+		.map(({ ts, text }) => ({
+			label: new Date(ts).toLocaleString(),
+			description: text!.slice(0, 8),
+			commitHash: text!,
+		}))
+		.reverse()
+
+	if (items.length === 0) {
+		vscode.window.showInformationMessage(t("common:errors.checkpoint_no_other"))
+		return undefined
+	}
+
+	const picked = await vscode.window.showQuickPick(items, { placeHolder: t("common:checkpoints.select_checkpoint") })
+	return picked?.commitHash
 }
 
-export async function checkpointDiff(task: Task, { ts, previousCommitHash, commitHash, mode }: CheckpointDiffOptions) {
+export async function checkpointDiff(
+	task: Task,
+	{ ts, previousCommitHash, commitHash, mode, toCommitHash }: CheckpointDiffOptions,
+) {
 	const service = await getCheckpointService(task)
 
 	if (!service) {
@@ -356,6 +438,20 @@ export async function checkpointDiff(task: Task, { ts, previousCommitHash, commi
 			toHash = undefined
 			title = t("common:errors.checkpoint_diff_since_first")
 			break
+		case "compare": {
+			const otherHash = toCommitHash ?? (await pickCheckpoint(task, commitHash))
+
+			if (!otherHash) {
+				return
+			}
+
+			// Show the changes from the earlier checkpoint to the later one.
+			const isOtherEarlier = checkpoints.indexOf(otherHash) < idx
+			fromHash = isOtherEarlier ? otherHash : commitHash
+			toHash = isOtherEarlier ? commitHash : otherHash
+			title = t("common:errors.checkpoint_diff_between")
+			break
+		}
 	}
 
 	if (!fromHash) {
@@ -364,6 +460,13 @@ export async function checkpointDiff(task: Task, { ts, previousCommitHash, commi
 	}
 
 	try {
+		for (const hash of [fromHash, toHash]) {
+			if (hash && !(await service.hasCheckpoint(hash))) {
+				vscode.window.showInformationMessage(t("common:errors.checkpoint_pruned"))
+				return
+			}
+		}
+
 		const changes = await service.getDiff({ from: fromHash, to: toHash })
 
 		if (!changes?.length) {
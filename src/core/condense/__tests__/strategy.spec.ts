@@ -0,0 +1,157 @@
+// npx vitest src/core/condense/__tests__/strategy.spec.ts
+
+import { Anthropic } from "@anthropic-ai/sdk"
+import type { ModelInfo } from "@roo-code/types"
+import { TelemetryService } from "@roo-code/telemetry"
+
+import { BaseProvider } from "../../../api/providers/base-provider"
+import { ApiMessage } from "../../task-persistence/apiMessages"
+import { summarizeConversation } from "../index"
+import {
+	shortenOldToolResults,
+	extractRecentUserMessages,
+	extractFileEdits,
+	RECENT_TOOL_RESULT_MESSAGES,
+	OLD_TOOL_RESULT_CHARS,
+} from "../strategy"
+
+class MockApiHandler extends BaseProvider {
+	requests: Anthropic.Messages.MessageParam[][] = []
+
+	createMessage(_systemPrompt: string, messages: Anthropic.Messages.MessageParam[]): any {
+		this.requests.push(messages)
+
+		return {
+			async *[Symbol.asyncIterator]() {
+				yield { type: "text", text: "Summary" }
+			},
+		}
+	}
+
+	getModel(): { id: string; info: ModelInfo } {
+		return { id: "test-model", info: { contextWindow: 100000, supportsPromptCache: false } }
+	}
+
+	override async countTokens(): Promise<number> {
+		return 10
+	}
+}
+
+const toolUse = (id: string, name: string, input: Record<string, unknown>): ApiMessage => ({
+	role: "assistant",
+	content: [{ type: "tool_use", id, name, input }],
+})
+
+const toolResult = (id: string, content: string, is_error?: boolean): ApiMessage => ({
+	role: "user",
+	content: [{ type: "tool_result", tool_use_id: id, content, ...(is_error && { is_error }) }],
+})
+
+describe("condensing strategy", () => {
+	beforeEach(() => {
+		if (!TelemetryService.hasInstance()) {
+			TelemetryService.createInstance([])
+		}
+	})
+
+	describe("shortenOldToolResults", () => {
+		it("should shorten long tool results outside of the most recent tool-result messages", () => {
+			const output = "x".repeat(OLD_TOOL_RESULT_CHARS + 100)
+			const messages = Array.from({ length: RECENT_TOOL_RESULT_MESSAGES + 1 }, (_, i) => [
+				toolUse(`t${i}`, "execute_command", { command: "ls" }),
+				toolResult(`t${i}`, output),
+			]).flat()
+
+			const result = shortenOldToolResults(messages)
+			const contents = result
+				.filter((msg) => msg.role === "user")
+				.map((msg) => ((msg.content as Anthropic.Messages.ToolResultBlockParam[])[0].content as string).length)
+
+			expect(contents[0]).toBeLessThan(output.length)
+			expect((result[1].content as any)[0].content).toContain("[... 100 characters omitted]")
+			expect(contents.slice(1).every((length) => length === output.length)).toBe(true)
+			// The input is left untouched
+			expect((messages[1].content as any)[0].content).toBe(output)
+		})
+	})
+
+	describe("extractRecentUserMessages", () => {
+		it("should return the last user messages, including answers given through tool results", () => {
+			const messages: ApiMessage[] = [
+				{ role: "user", content: [{ type: "text", text: "<user_message>\nFirst\n</user_message>" }] },
+				toolUse("t1", "ask_followup_question", { question: "Which file?" }),
+				toolResult("t1", "<user_message>\nsrc/app.ts\n</user_message>"),
+				{ role: "user", content: "<user_message>\nThird\n</user_message>", isSummary: true },
+			]
+
+			expect(extractRecentUserMessages(messages, 1)).toEqual(["<user_message>\nsrc/app.ts\n</user_message>"])
+			expect(extractRecentUserMessages(messages, 5)).toHaveLength(2)
+			expect(extractRecentUserMessages(messages, 0)).toEqual([])
+		})
+	})
+
+	describe("extractFileEdits", () => {
+		it("should keep successful edits and only the path of written files", () => {
+			const messages: ApiMessage[] = [
+				toolUse("t1", "write_to_file", { path: "src/new.ts", content: "export {}" }),
+				toolResult("t1", "ok"),
+				toolUse("t2", "apply_diff", { path: "src/app.ts", diff: "-a\n+b" }),
+				toolResult("t2", "ok"),
+				toolUse("t3", "apply_diff", { path: "src/broken.ts", diff: "-c\n+d" }),
+				toolResult("t3", "no match", true),
+				toolUse("t4", "read_file", { path: "src/app.ts" }),
+			]
+
+			expect(extractFileEdits(messages)).toEqual([
+				"[Tool Use: write_to_file]\npath: src/new.ts",
+				"[Tool Use: apply_diff]\npath: src/app.ts\ndiff: -a\n+b",
+			])
+		})
+	})
+
+	describe("summarizeConversation", () => {
+		const messages: ApiMessage[] = [
+			{ role: "user", content: "<user_message>\nFix the bug\n</user_message>" },
+			toolUse("t1", "apply_diff", { path: "src/app.ts", diff: "-a\n+b" }),
+			toolResult("t1", "ok"),
+			{ role: "assistant", content: "Done" },
+			{ role: "user", content: "<user_message>\nAlso add a test\n</user_message>" },
+		]
+
+		it("should carry user messages and file edits over verbatim", async () => {
+			const result = await summarizeConversation({
+				messages,
+				apiHandler: new MockApiHandler(),
+				systemPrompt: "System prompt",
+				taskId: "task-1",
+				condensingStrategy: { id: "s", name: "S", keepRecentUserMessages: 1, preserveFileEdits: true },
+			})
+
+			const summary = result.messages.find((msg) => msg.isSummary)!
+			const texts = (summary.content as Anthropic.Messages.TextBlockParam[]).map((block) => block.text)
+
+			expect(texts).toHaveLength(3)
+			expect(texts[1]).toContain("## Recent User Messages")
+			expect(texts[1]).toContain("Also add a test")
+			expect(texts[1]).not.toContain("Fix the bug")
+			expect(texts[2]).toContain("## File Edits")
+			expect(texts[2]).toContain("diff: -a\n+b")
+		})
+
+		it("should use the strategy's prompt instead of the custom condensing prompt", async () => {
+			const apiHandler = new MockApiHandler()
+
+			await summarizeConversation({
+				messages,
+				apiHandler,
+				systemPrompt: "System prompt",
+				taskId: "task-1",
+				customCondensingPrompt: "Custom prompt",
+				condensingStrategy: { id: "s", name: "S", prompt: "Strategy prompt" },
+			})
+
+			const request = apiHandler.requests[0]
+			expect(request[request.length - 1].content).toBe("Strategy prompt")
+		})
+	})
+})
@@ -1,6 +1,7 @@
 import Anthropic from "@anthropic-ai/sdk"
 import crypto from "crypto"
 
+import type { CondensingStrategy } from "@roo-code/types"
 import { TelemetryService } from "@roo-code/telemetry"
 
 import { t } from "../../i18n"
@@ -11,6 +12,7 @@ import { findLast } from "../../shared/array"
 import { supportPrompt } from "../../shared/support-prompt"
 import { RooIgnoreController } from "../ignore/RooIgnoreController"
 import { generateFoldedFileContext } from "./foldedFileContext"
+import { shortenOldToolResults, extractRecentUserMessages, extractFileEdits } from "./strategy"
 
 export type { FoldedFileContextResult, FoldedFileContextOptions } from "./foldedFileContext"
 
@@ -228,6 +230,7 @@ export type SummarizeConversationOptions = {
 	taskId: string
 	isAutomaticTrigger?: boolean
 	customCondensingPrompt?: string
+	condensingStrategy?: CondensingStrategy
 	metadata?: ApiHandlerCreateMessageMetadata
 	environmentDetails?: string
 	filesReadByRoo?: string[]
@@ -244,6 +247,8 @@ export type SummarizeConversationOptions = {
  * - All messages are still stored but tagged with condenseParent
  * - <command> blocks from the original task are preserved across condensings
  * - File context (folded code definitions) can be preserved for continuity
+ * - The condensing strategy can shorten old tool results before summarizing, and
+ *   carry recent user messages and file edits over verbatim
  *
  * Environment details handling:
  * - For AUTOMATIC condensing (isAutomaticTrigger=true): Environment details are included
@@ -261,17 +266,17 @@ export async function summarizeConversation(options: SummarizeConversationOption
 		taskId,
 		isAutomaticTrigger,
 		customCondensingPrompt,
+		condensingStrategy,
 		metadata,
 		environmentDetails,
 		filesReadByRoo,
 		cwd,
 		rooIgnoreController,
 	} = options
-	TelemetryService.instance.captureContextCondensed(
-		taskId,
-		isAutomaticTrigger ?? false,
-		!!customCondensingPrompt?.trim(),
-	)
+	// The strategy's own prompt takes precedence over the custom condensing prompt
+	const condensingPrompt = condensingStrategy?.prompt?.trim() || customCondensingPrompt?.trim()
+
+	TelemetryService.instance.captureContextCondensed(taskId, isAutomaticTrigger ?? false, !!condensingPrompt)
 
 	const response: SummarizeResponse = { messages, cost: 0, summary: "" }
 
@@ -296,7 +301,7 @@ export async function summarizeConversation(options: SummarizeConversationOption
 
 	// Use custom prompt if provided and non-empty, otherwise use the default CONDENSE prompt
 	// This respects user's custom condensing prompt setting
-	const condenseInstructions = customCondensingPrompt?.trim() || supportPrompt.default.CONDENSE
+	const condenseInstructions = condensingPrompt || supportPrompt.default.CONDENSE
 
 	const finalRequestMessage: Anthropic.MessageParam = {
 		role: "user",
@@ -305,7 +310,9 @@ export async function summarizeConversation(options: SummarizeConversationOption
 
 	// Inject synthetic tool_results for orphan tool_calls to prevent API rejections
 	// (e.g., when user triggers condense after receiving attempt_completion but before responding)
-	const messagesWithToolResults = injectSyntheticToolResults(messagesToSummarize)
+	const messagesWithToolResults = injectSyntheticToolResults(
+		condensingStrategy?.condenseOldToolResults ? shortenOldToolResults(messagesToSummarize) : messagesToSummarize,
+	)
 
 	// Transform tool_use and tool_result blocks to text representations.
 	// This is necessary because some providers (like Bedrock via LiteLLM) require the `tools` parameter
@@ -414,6 +421,34 @@ ${commandBlocks}
 		})
 	}
 
+	// Carry over what the condensing strategy keeps verbatim. File edits are taken from the
+	// full history, which still contains condensed messages, so they survive repeated condensing.
+	const recentUserMessages = extractRecentUserMessages(messages, condensingStrategy?.keepRecentUserMessages ?? 0)
+
+	if (recentUserMessages.length > 0) {
+		summaryContent.push({
+			type: "text",
+			text: `<system-reminder>
+## Recent User Messages
+The user's most recent messages before the conversation was condensed, verbatim:
+${recentUserMessages.join("\n\n")}
+</system-reminder>`,
+		})
+	}
+
+	const fileEdits = condensingStrategy?.preserveFileEdits ? extractFileEdits(messages) : []
+
+	if (fileEdits.length > 0) {
+		summaryContent.push({
+			type: "text",
+			text: `<system-reminder>
+## File Edits
+The file edits made so far in this task, verbatim:
+${fileEdits.join("\n\n")}
+</system-reminder>`,
+		})
+	}
+
 	// Generate and add folded file context (smart code folding) if file paths are provided
 	// Each file gets its own <system-reminder> block as a separate content block
 	if (filesReadByRoo && filesReadByRoo.length > 0 && cwd) {
@@ -0,0 +1,167 @@
+import Anthropic from "@anthropic-ai/sdk"
+
+import type { ToolName } from "@roo-code/types"
+
+import { ApiMessage } from "../task-persistence/apiMessages"
+import { toolUseToText } from "./index"
+
+// Tool results in this many of the most recent tool-result messages are summarized in full.
+export const RECENT_TOOL_RESULT_MESSAGES = 4
+// Older tool results are cut down to this many characters.
+export const OLD_TOOL_RESULT_CHARS = 500
+// Upper bound for the file edits carried over verbatim; older edits are dropped first.
+export const MAX_FILE_EDITS_CHARS = 20_000
+
+const FILE_EDIT_TOOLS: ToolName[] = [
+	"write_to_file",
+	"apply_diff",
+	"fast_edit",
+	"edit_notebook",
+	"edit",
+	"search_and_replace",
+	"search_replace",
+	"edit_file",
+	"apply_patch",
+]
+
+const shortenText = (text: string) =>
+	text.length > OLD_TOOL_RESULT_CHARS
+		? `${text.slice(0, OLD_TOOL_RESULT_CHARS)}\n[... ${text.length - OLD_TOOL_RESULT_CHARS} characters omitted]`
+		: text
+
+/**
+ * Cuts down tool results outside of the most recent tool-result messages, so
+ * that the summary concentrates on the conversation instead of retelling
+ * command output and file contents.
+ */
+export function shortenOldToolResults(messages: ApiMessage[]): ApiMessage[] {
+	const toolResultMessageIndices = messages.flatMap((msg, index) =>
+		msg.role === "user" && Array.isArray(msg.content) && msg.content.some((block) => block.type === "tool_result")
+			? [index]
+			: [],
+	)
+	const oldIndices = new Set(toolResultMessageIndices.slice(0, -RECENT_TOOL_RESULT_MESSAGES))
+
+	return messages.map((msg, index) => {
+		if (!oldIndices.has(index) || !Array.isArray(msg.content)) {
+			return msg
+		}
+
+		return {
+			...msg,
+			content: msg.content.map((block) => {
+				if (block.type !== "tool_result") {
+					return block
+				}
+
+				if (typeof block.content === "string") {
+					return { ...block, content: shortenText(block.content) }
+				}
+
+				return {
+					...block,
+					content: block.content?.map((part) =>
+						part.type === "text" ? { ...part, text: shortenText(part.text) } : part,
+					),
+				}
+			}),
+		}
+	})
+}
+
+/**
+ * Returns the last `count` `<user_message>` blocks of the conversation,
+ * including the answers and feedback the user gave through tool results.
+ */
+export function extractRecentUserMessages(messages: ApiMessage[], count: number): string[] {
+	if (count <= 0) {
+		return []
+	}
+
+	const userMessageRegex = /<user_message>[\s\S]*?<\/user_message>/g
+	const userMessages: string[] = []
+
+	for (const msg of messages) {
+		// Summaries quote earlier user messages, which are already counted where they were sent.
+		if (msg.role !== "user" || msg.isSummary) {
+			continue
+		}
+
+		const texts =
+			typeof msg.content === "string"
+				? [msg.content]
+				: msg.content.flatMap((block) => {
+						if (block.type === "text") {
+							return [block.text]
+						}
+						if (block.type === "tool_result") {
+							return typeof block.content === "string"
+								? [block.content]
+								: (block.content ?? []).flatMap((part) => (part.type === "text" ? [part.text] : []))
+						}
+						return []
+					})
+
+		for (const text of texts) {
+			userMessages.push(...(text.match(userMessageRegex) ?? []))
+		}
+	}
+
+	return userMessages.slice(-count)
+}
+
+/**
+ * Returns the file edits made in the conversation as text, most recent last.
+ * Failed edits are skipped, and write_to_file only records the path since the
+ * file can be read back. Older edits are dropped once `MAX_FILE_EDITS_CHARS` is
+ * reached.
+ *
+ * Callers pass the full history (including condensed messages), so edits
+ * survive repeated condensing.
+ */
+export function extractFileEdits(messages: ApiMessage[]): string[] {
+	const failedToolUseIds = new Set<string>()
+
+	for (const msg of messages) {
+		if (msg.role === "user" && Array.isArray(msg.content)) {
+			for (const block of msg.content) {
+				if (block.type === "tool_result" && block.is_error) {
+					failedToolUseIds.add(block.tool_use_id)
+				}
+			}
+		}
+	}
+
+	const edits = messages.flatMap((msg) =>
+		msg.role === "assistant" && Array.isArray(msg.content)
+			? msg.content
+					.filter(
+						(block): block is Anthropic.Messages.ToolUseBlockParam =>
+							block.type === "tool_use" &&
+							FILE_EDIT_TOOLS.includes(block.name as ToolName) &&
+							!failedToolUseIds.has(block.id),
+					)
+					.map((block) => {
+						if (block.name === "write_to_file") {
+							const { path } = (block.input ?? {}) as { path?: string }
+							return `[Tool Use: write_to_file]\npath: ${path}`
+						}
+						return toolUseToText(block)
+					})
+			: [],
+	)
+
+	const kept: string[] = []
+	let length = 0
+
+	for (const edit of [...edits].reverse()) {
+		if (length + edit.length > MAX_FILE_EDITS_CHARS) {
+			break
+		}
+
+		kept.unshift(edit)
+		length += edit.length
+	}
+
+	return kept
+}
@@ -5,8 +5,16 @@ import * as os from "os"
 
 import * as yaml from "yaml"
 import stripBom from "strip-bom"
+import deepEqual from "fast-deep-equal"
 
-import { type ModeConfig, type PromptComponent, customModesSettingsSchema, modeConfigSchema } from "@roo-code/types"
+import {
+	type CustomModeDefinition,
+	type ModeConfig,
+	type PromptComponent,
+	customModesSettingsSchema,
+	modeConfigSchema,
+	DEFAULT_MODES,
+} from "@roo-code/types"
 
 import { fileExistsAtPath } from "../../utils/fs"
 import { getWorkspacePath } from "../../utils/path"
@@ -16,8 +24,18 @@ import { GlobalFileNames } from "../../shared/globalFileNames"
 import { ensureSettingsDirectoryExists } from "../../utils/globalContext"
 import { t } from "../../i18n"
 
+import { INHERITED_MODE_FIELDS, applyModeChanges, resolveCustomModes } from "./customModeInheritance"
+
 const ROOMODES_FILENAME = ".roomodes"
 
+// The modes and instruction fragments defined in .roomodes or the global custom modes file
+interface CustomModesFile {
+	modes: CustomModeDefinition[]
+	instructionFragments: Record<string, string>
+}
+
+const EMPTY_CUSTOM_MODES_FILE: CustomModesFile = { modes: [], instructionFragments: {} }
+
 // Type definitions for import/export functionality
 interface RuleFile {
 	relativePath: string
@@ -52,10 +70,13 @@ export class CustomModesManager {
 	private writeQueue: Array<() => Promise<void>> = []
 	private cachedModes: ModeConfig[] | null = null
 	private cachedAt: number = 0
+	private reportedInheritanceErrors = ""
 
 	constructor(
 		private readonly context: vscode.ExtensionContext,
 		private readonly onUpdate: () => Promise<void>,
+		// The modes of the repository's shared configuration in .roo/config.
+		private readonly getSharedModes: () => CustomModeDefinition[] = () => [],
 	) {
 		this.watchCustomModesFiles().catch((error) => {
 			console.error("[CustomModesManager] Failed to setup file watchers:", error)
@@ -103,6 +124,18 @@ export class CustomModesManager {
 		return exists ? roomodesPath : undefined
 	}
 
+	/**
+	 * Loads the project modes: those in .roomodes, then those in .roo/config.
+	 * Modes in .roomodes take precedence over those with the same slug.
+	 */
+	private async loadProjectModes(): Promise<CustomModesFile> {
+		const roomodesPath = await this.getWorkspaceRoomodes()
+		const roomodesFile = roomodesPath ? await this.loadModesFromFile(roomodesPath) : EMPTY_CUSTOM_MODES_FILE
+		const sharedModes = this.getSharedModes().map((mode) => ({ ...mode, source: "project" as const }))
+
+		return { ...roomodesFile, modes: [...roomodesFile.modes, ...sharedModes] }
+	}
+
 	/**
 	 * Regex pattern for problematic characters that need to be cleaned from YAML content
 	 * Includes:
@@ -180,14 +213,14 @@ export class CustomModesManager {
 		}
 	}
 
-	private async loadModesFromFile(filePath: string): Promise<ModeConfig[]> {
+	private async loadModesFromFile(filePath: string): Promise<CustomModesFile> {
 		try {
 			const content = await fs.readFile(filePath, "utf-8")
 			const settings = this.parseYamlSafely(content, filePath)
 
 			// Ensure settings has customModes property
 			if (!settings || typeof settings !== "object" || !settings.customModes) {
-				return []
+				return EMPTY_CUSTOM_MODES_FILE
 			}
 
 			const result = customModesSettingsSchema.safeParse(settings)
@@ -204,7 +237,7 @@ export class CustomModesManager {
 					vscode.window.showErrorMessage(t("common:customModes.errors.schemaValidationError", { issues }))
 				}
 
-				return []
+				return EMPTY_CUSTOM_MODES_FILE
 			}
 
 			// Determine source based on file path
@@ -212,23 +245,31 @@ export class CustomModesManager {
 			const source = isRoomodes ? ("project" as const) : ("global" as const)
 
 			// Add source to each mode
-			return result.data.customModes.map((mode) => ({ ...mode, source }))
+			return {
+				modes: result.data.customModes.map((mode) => ({ ...mode, source })),
+				instructionFragments: result.data.instructionFragments ?? {},
+			}
 		} catch (error) {
 			// Only log if the error wasn't already handled in parseYamlSafely
 			if (!(error as any).alreadyHandled) {
 				const errorMsg = `Failed to load modes from ${filePath}: ${error instanceof Error ? error.message : String(error)}`
 				console.error(`[CustomModesManager] ${errorMsg}`)
 			}
-			return []
+			return EMPTY_CUSTOM_MODES_FILE
 		}
 	}
 
-	private async mergeCustomModes(projectModes: ModeConfig[], globalModes: ModeConfig[]): Promise<ModeConfig[]> {
+	/**
+	 * Merges the project and global modes, with project modes taking precedence,
+	 * and resolves the modes that extend other modes. Instruction fragments from
+	 * both files can be included by any mode.
+	 */
+	private async mergeCustomModes(projectFile: CustomModesFile, globalFile: CustomModesFile): Promise<ModeConfig[]> {
 		const slugs = new Set<string>()
-		const merged: ModeConfig[] = []
+		const merged: CustomModeDefinition[] = []
 
 		// Add project mode (takes precedence)
-		for (const mode of projectModes) {
+		for (const mode of projectFile.modes) {
 			if (!slugs.has(mode.slug)) {
 				slugs.add(mode.slug)
 				merged.push({ ...mode, source: "project" })
@@ -236,14 +277,29 @@ export class CustomModesManager {
 		}
 
 		// Add non-duplicate global modes
-		for (const mode of globalModes) {
+		for (const mode of globalFile.modes) {
 			if (!slugs.has(mode.slug)) {
 				slugs.add(mode.slug)
 				merged.push({ ...mode, source: "global" })
 			}
 		}
 
-		return merged
+		const { modes, errors } = resolveCustomModes(merged, {
+			...globalFile.instructionFragments,
+			...projectFile.instructionFragments,
+		})
+
+		// Modes are merged on every load, so only show an error when it changes.
+		const reportedErrors = errors.join("\n")
+
+		if (reportedErrors && reportedErrors !== this.reportedInheritanceErrors) {
+			console.error(`[CustomModesManager] Failed to resolve custom modes:\n${reportedErrors}`)
+			vscode.window.showErrorMessage(t("common:customModes.errors.inheritanceError", { errors: reportedErrors }))
+		}
+
+		this.reportedInheritanceErrors = reportedErrors
+
+		return modes
 	}
 
 	public async getCustomModesFilePath(): Promise<string> {
@@ -294,12 +350,14 @@ export class CustomModesManager {
 					return
 				}
 
-				// Get modes from .roomodes if it exists (takes precedence)
-				const roomodesPath = await this.getWorkspaceRoomodes()
-				const roomodesModes = roomodesPath ? await this.loadModesFromFile(roomodesPath) : []
+				// Get the project modes (take precedence)
+				const roomodesFile = await this.loadProjectModes()
 
 				// Merge modes from both sources (.roomodes takes precedence)
-				const mergedModes = await this.mergeCustomModes(roomodesModes, result.data.customModes)
+				const mergedModes = await this.mergeCustomModes(roomodesFile, {
+					modes: result.data.customModes,
+					instructionFragments: result.data.instructionFragments ?? {},
+				})
 				await this.context.globalState.update("customModes", mergedModes)
 				this.clearCache()
 				await this.onUpdate()
@@ -322,10 +380,10 @@ export class CustomModesManager {
 
 			const handleRoomodesChange = async () => {
 				try {
-					const settingsModes = await this.loadModesFromFile(settingsPath)
-					const roomodesModes = await this.loadModesFromFile(roomodesPath)
+					const settingsFile = await this.loadModesFromFile(settingsPath)
+					const roomodesFile = await this.loadProjectModes()
 					// .roomodes takes precedence
-					const mergedModes = await this.mergeCustomModes(roomodesModes, settingsModes)
+					const mergedModes = await this.mergeCustomModes(roomodesFile, settingsFile)
 					await this.context.globalState.update("customModes", mergedModes)
 					this.clearCache()
 					await this.onUpdate()
@@ -338,9 +396,10 @@ export class CustomModesManager {
 			this.disposables.push(roomodesWatcher.onDidCreate(handleRoomodesChange))
 			this.disposables.push(
 				roomodesWatcher.onDidDelete(async () => {
-					// When .roomodes is deleted, refresh with only settings modes
+					// When .roomodes is deleted, refresh without its modes
 					try {
-						const settingsModes = await this.loadModesFromFile(settingsPath)
+						const settingsFile = await this.loadModesFromFile(settingsPath)
+						const settingsModes = await this.mergeCustomModes(await this.loadProjectModes(), settingsFile)
 						await this.context.globalState.update("customModes", settingsModes)
 						this.clearCache()
 						await this.onUpdate()
@@ -363,35 +422,13 @@ export class CustomModesManager {
 
 		// Get modes from settings file.
 		const settingsPath = await this.getCustomModesFilePath()
-		const settingsModes = await this.loadModesFromFile(settingsPath)
-
-		// Get modes from .roomodes if it exists.
-		const roomodesPath = await this.getWorkspaceRoomodes()
-		const roomodesModes = roomodesPath ? await this.loadModesFromFile(roomodesPath) : []
+		const settingsFile = await this.loadModesFromFile(settingsPath)
 
-		// Create maps to store modes by source.
-		const projectModes = new Map<string, ModeConfig>()
-		const globalModes = new Map<string, ModeConfig>()
-
-		// Add project modes (they take precedence).
-		for (const mode of roomodesModes) {
-			projectModes.set(mode.slug, { ...mode, source: "project" as const })
-		}
-
-		// Add global modes.
-		for (const mode of settingsModes) {
-			if (!projectModes.has(mode.slug)) {
-				globalModes.set(mode.slug, { ...mode, source: "global" as const })
-			}
-		}
+		// Get modes from .roomodes and .roo/config if they exist.
+		const roomodesFile = await this.loadProjectModes()
 
 		// Combine modes in the correct order: project modes first, then global modes.
-		const mergedModes = [
-			...roomodesModes.map((mode) => ({ ...mode, source: "project" as const })),
-			...settingsModes
-				.filter((mode) => !projectModes.has(mode.slug))
-				.map((mode) => ({ ...mode, source: "global" as const })),
-		]
+		const mergedModes = await this.mergeCustomModes(roomodesFile, settingsFile)
 
 		await this.context.globalState.update("customModes", mergedModes)
 
@@ -438,16 +475,21 @@ export class CustomModesManager {
 				targetPath = await this.getCustomModesFilePath()
 			}
 
-			await this.queueWrite(async () => {
-				// Ensure source is set correctly based on target file.
-				const modeWithSource = {
-					...config,
-					source: isProjectMode ? ("project" as const) : ("global" as const),
-				}
+			const source = isProjectMode ? ("project" as const) : ("global" as const)
+			// A mode that extends another mode only stores the fields it overrides.
+			const definition = config.extends ? await this.omitInheritedFields(config) : config
+			// The mode as it was resolved from its file, to tell which fields were changed.
+			const resolved = (await this.getCustomModes()).find(
+				(mode) => mode.slug === slug && (mode.source ?? "global") === source,
+			)
 
+			await this.queueWrite(async () => {
 				await this.updateModesInFile(targetPath, (modes) => {
+					const existing = modes.find((m) => m.slug === slug)
+					const updated = existing && resolved ? applyModeChanges(existing, resolved, definition) : definition
 					const updatedModes = modes.filter((m) => m.slug !== slug)
-					updatedModes.push(modeWithSource)
+					// Ensure source is set correctly based on target file.
+					updatedModes.push({ ...updated, source })
 					return updatedModes
 				})
 
@@ -462,7 +504,35 @@ export class CustomModesManager {
 		}
 	}
 
-	private async updateModesInFile(filePath: string, operation: (modes: ModeConfig[]) => ModeConfig[]): Promise<void> {
+	/**
+	 * Removes the fields of a mode that are the same as in the mode it extends,
+	 * so they keep following the base mode when it changes.
+	 */
+	private async omitInheritedFields(config: ModeConfig): Promise<CustomModeDefinition> {
+		const customModes = config.extends !== config.slug ? await this.getCustomModes() : []
+		const base =
+			customModes.find((mode) => mode.slug === config.extends) ??
+			DEFAULT_MODES.find((mode) => mode.slug === config.extends)
+
+		if (!base) {
+			return config
+		}
+
+		const definition: CustomModeDefinition = { ...config }
+
+		for (const field of INHERITED_MODE_FIELDS) {
+			if (deepEqual(definition[field], base[field])) {
+				delete definition[field]
+			}
+		}
+
+		return definition
+	}
+
+	private async updateModesInFile(
+		filePath: string,
+		operation: (modes: CustomModeDefinition[]) => CustomModeDefinition[],
+	): Promise<void> {
 		let content = "{}"
 
 		try {
@@ -493,13 +563,12 @@ export class CustomModesManager {
 		await fs.writeFile(filePath, yaml.stringify(settings, { lineWidth: 0 }), "utf-8")
 	}
 
-	private async refreshMergedState(): Promise<void> {
+	public async refreshMergedState(): Promise<void> {
 		const settingsPath = await this.getCustomModesFilePath()
-		const roomodesPath = await this.getWorkspaceRoomodes()
 
-		const settingsModes = await this.loadModesFromFile(settingsPath)
-		const roomodesModes = roomodesPath ? await this.loadModesFromFile(roomodesPath) : []
-		const mergedModes = await this.mergeCustomModes(roomodesModes, settingsModes)
+		const settingsFile = await this.loadModesFromFile(settingsPath)
+		const roomodesFile = await this.loadProjectModes()
+		const mergedModes = await this.mergeCustomModes(roomodesFile, settingsFile)
 
 		await this.context.globalState.update("customModes", mergedModes)
 
@@ -513,12 +582,12 @@ export class CustomModesManager {
 			const settingsPath = await this.getCustomModesFilePath()
 			const roomodesPath = await this.getWorkspaceRoomodes()
 
-			const settingsModes = await this.loadModesFromFile(settingsPath)
-			const roomodesModes = roomodesPath ? await this.loadModesFromFile(roomodesPath) : []
+			const settingsFile = await this.loadModesFromFile(settingsPath)
+			const roomodesFile = roomodesPath ? await this.loadModesFromFile(roomodesPath) : EMPTY_CUSTOM_MODES_FILE
 
 			// Find the mode in either file
-			const projectMode = roomodesModes.find((m) => m.slug === slug)
-			const globalMode = settingsModes.find((m) => m.slug === slug)
+			const projectMode = roomodesFile.modes.find((m) => m.slug === slug)
+			const globalMode = settingsFile.modes.find((m) => m.slug === slug)
 
 			if (!projectMode && !globalMode) {
 				throw new Error(t("common:customModes.errors.modeNotFound"))
@@ -558,7 +627,7 @@ export class CustomModesManager {
 	 * @param slug - The mode slug
 	 * @param mode - The mode configuration to determine the scope
 	 */
-	private async deleteRulesFolder(slug: string, mode: ModeConfig, fromMarketplace = false): Promise<void> {
+	private async deleteRulesFolder(slug: string, mode: CustomModeDefinition, fromMarketplace = false): Promise<void> {
 		try {
 			// Determine the scope based on source (project or global)
 			const scope = mode.source || "global"
@@ -0,0 +1,223 @@
+import * as vscode from "vscode"
+import * as path from "path"
+import fs from "fs/promises"
+
+import * as yaml from "yaml"
+import stripBom from "strip-bom"
+
+import {
+	type OrganizationAllowList,
+	type ProjectAutoApprovePolicy,
+	type ProjectConfig,
+	type ProjectConfigStatus,
+	PROJECT_CONFIG_FILE_NAMES,
+	projectConfigSchema,
+} from "@roo-code/types"
+
+import { fileExistsAtPath } from "../../utils/fs"
+import { getWorkspacePath } from "../../utils/path"
+import { getProjectRooDirectoryForCwd } from "../../services/roo-config"
+import { t } from "../../i18n"
+
+/**
+ * Reads the project configuration of a workspace from `.roo/config.yaml`,
+ * `.roo/config.yml` or `.roo/config.json`.
+ *
+ * @returns undefined if there is no configuration file, otherwise the file
+ * with its configuration or the reason it is invalid
+ */
+export async function loadProjectConfig(cwd: string): Promise<ProjectConfigStatus | undefined> {
+	const rooDir = getProjectRooDirectoryForCwd(cwd)
+
+	for (const fileName of PROJECT_CONFIG_FILE_NAMES) {
+		const filePath = path.join(rooDir, fileName)
+
+		try {
+			if (!(await fileExistsAtPath(filePath))) {
+				continue
+			}
+
+			const content = stripBom(await fs.readFile(filePath, "utf8"))
+			const data = fileName.endsWith(".json") ? JSON.parse(content) : yaml.parse(content)
+			const result = projectConfigSchema.safeParse(data ?? {})
+
+			if (!result.success) {
+				const error = result.error.issues
+					.map((issue) => `${issue.path.join(".") || "config"}: ${issue.message}`)
+					.join("; ")
+
+				return { filePath, error }
+			}
+
+			return { filePath, config: result.data }
+		} catch (error) {
+			return { filePath, error: error instanceof Error ? error.message : String(error) }
+		}
+	}
+
+	return undefined
+}
+
+type ProjectConfigSettings = ProjectAutoApprovePolicy & { allowedCommands?: string[]; deniedCommands?: string[] }
+
+const union = (values: string[] | undefined, added: string[]) => [...new Set([...(values ?? []), ...added])]
+
+/**
+ * Applies the repository's guardrails to the user's settings: the
+ * auto-approvals it turns off are off, and its denied write paths and
+ * commands are added to the user's. The user's allowed commands that the
+ * repository denies are dropped, so they can't take precedence.
+ *
+ * A repository can only tighten the settings; its allowed commands are
+ * suggestions the user adds themselves (see `ProjectConfigNotice`).
+ */
+export function applyProjectConfig<T extends ProjectConfigSettings>(settings: T, config: ProjectConfig | undefined): T {
+	if (!config) {
+		return settings
+	}
+
+	const result = { ...settings }
+	const { deniedWritePaths, ...approvals } = config.autoApprove ?? {}
+
+	for (const [key, value] of Object.entries(approvals)) {
+		if (value === false) {
+			Object.assign(result, { [key]: false })
+		}
+	}
+
+	if (deniedWritePaths?.length) {
+		result.deniedWritePaths = union(settings.deniedWritePaths, deniedWritePaths)
+	}
+
+	const deniedCommands = config.deniedCommands ?? []
+
+	if (deniedCommands.length > 0) {
+		const denied = deniedCommands.map((command) => command.toLowerCase())
+		result.deniedCommands = union(settings.deniedCommands, deniedCommands)
+		result.allowedCommands = settings.allowedCommands?.filter(
+			(command) => !denied.some((prefix) => command.toLowerCase().startsWith(prefix)),
+		)
+	}
+
+	return result
+}
+
+/**
+ * Restricts the providers and models of an allow list to those the
+ * repository allows as well.
+ */
+export function applyProjectAllowList(
+	allowList: OrganizationAllowList,
+	allowedProviders: ProjectConfig["allowedProviders"],
+): OrganizationAllowList {
+	if (!allowedProviders) {
+		return allowList
+	}
+
+	const providers: OrganizationAllowList["providers"] = {}
+
+	for (const [provider, models] of Object.entries(allowedProviders)) {
+		const allowed = allowList.allowAll ? { allowAll: true } : allowList.providers[provider]
+
+		if (!allowed) {
+			continue
+		}
+
+		if (models === "*") {
+			providers[provider] = allowed
+		} else if (allowed.allowAll) {
+			providers[provider] = { allowAll: false, models }
+		} else {
+			const allowedModels = models.filter((model) => allowed.models?.includes(model))
+			providers[provider] = { allowAll: false, models: allowedModels }
+		}
+	}
+
+	return { allowAll: false, providers }
+}
+
+/**
+ * Loads the repository's shared configuration from `.roo/config` and reloads
+ * it when the file changes.
+ */
+export class ProjectConfigManager {
+	private status: ProjectConfigStatus | undefined
+	private reportedError: string | undefined
+	private disposables: vscode.Disposable[] = []
+
+	constructor(private readonly onUpdate: () => Promise<void>) {}
+
+	async initialize(): Promise<void> {
+		await this.load()
+		this.watch()
+
+		// The settings read before it was loaded didn't include the configuration.
+		if (this.status) {
+			await this.onUpdate()
+		}
+	}
+
+	getStatus(): ProjectConfigStatus | undefined {
+		return this.status
+	}
+
+	/**
+	 * The configuration, or undefined if there is none or it is invalid.
+	 */
+	getConfig(): ProjectConfig | undefined {
+		return this.status?.config
+	}
+
+	private async load(): Promise<void> {
+		const cwd = getWorkspacePath()
+		this.status = cwd ? await loadProjectConfig(cwd) : undefined
+
+		// The file is loaded again on every change, so only show an error when it changes.
+		const error = this.status?.error
+
+		if (error && error !== this.reportedError) {
+			vscode.window.showErrorMessage(
+				t("common:projectConfig.invalid", { path: path.basename(this.status!.filePath), error }),
+			)
+		}
+
+		this.reportedError = error
+	}
+
+	private watch(): void {
+		// Skip if test environment is detected
+		if (process.env.NODE_ENV === "test") {
+			return
+		}
+
+		const cwd = getWorkspacePath()
+
+		if (!cwd) {
+			return
+		}
+
+		const watcher = vscode.workspace.createFileSystemWatcher(
+			new vscode.RelativePattern(cwd, `.roo/{${PROJECT_CONFIG_FILE_NAMES.join(",")}}`),
+		)
+
+		const reload = async () => {
+			try {
+				await this.load()
+				await this.onUpdate()
+			} catch (error) {
+				console.error(`[ProjectConfigManager] Error reloading the project configuration:`, error)
+			}
+		}
+
+		this.disposables.push(watcher.onDidChange(reload), watcher.onDidCreate(reload), watcher.onDidDelete(reload))
+		this.disposables.push(watcher)
+	}
+
+	dispose(): void {
+		for (const disposable of this.disposables) {
+			disposable.dispose()
+		}
+
+		this.disposables = []
+	}
+}
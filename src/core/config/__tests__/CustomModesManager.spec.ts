@@ -147,6 +147,61 @@ describe("CustomModesManager", () => {
 			expect(mode2?.roleDefinition).toBe("Role 2 Override")
 		})
 
+		it("should resolve modes that extend other modes", async () => {
+			const settingsModes = [
+				{ slug: "reviewer", name: "Reviewer", roleDefinition: "Reviewer role", groups: ["read"] },
+			]
+
+			const roomodesModes = [
+				{
+					slug: "security-reviewer",
+					name: "Security Reviewer",
+					extends: "reviewer",
+					appendRoleDefinition: "You focus on security.",
+					includeInstructions: ["owasp"],
+				},
+			]
+
+			;(fs.readFile as Mock).mockImplementation(async (path: string) => {
+				if (path === mockSettingsPath) {
+					return yaml.stringify({ customModes: settingsModes })
+				}
+				if (path === mockRoomodes) {
+					return yaml.stringify({
+						instructionFragments: { owasp: "Check for the OWASP Top 10." },
+						customModes: roomodesModes,
+					})
+				}
+				throw new Error("File not found")
+			})
+
+			const modes = await manager.getCustomModes()
+
+			expect(modes[0]).toEqual({
+				slug: "security-reviewer",
+				name: "Security Reviewer",
+				extends: "reviewer",
+				roleDefinition: "Reviewer role\n\nYou focus on security.",
+				customInstructions: "Check for the OWASP Top 10.",
+				groups: ["read"],
+				source: "project",
+			})
+		})
+
+		it("should report modes that extend a mode that doesn't exist", async () => {
+			;(fs.readFile as Mock).mockImplementation(async (path: string) => {
+				if (path === mockSettingsPath) {
+					return yaml.stringify({ customModes: [{ slug: "orphan", name: "Orphan", extends: "missing" }] })
+				}
+				throw new Error("File not found")
+			})
+
+			const modes = await manager.getCustomModes()
+
+			expect(modes).toEqual([])
+			expect(vscode.window.showErrorMessage).toHaveBeenCalledWith("customModes.errors.inheritanceError")
+		})
+
 		it("should handle missing .roomodes file", async () => {
 			const settingsModes = [{ slug: "mode1", name: "Mode 1", roleDefinition: "Role 1", groups: ["read"] }]
 
@@ -517,6 +572,97 @@ describe("CustomModesManager", () => {
 			expect(mockOnUpdate).toHaveBeenCalled()
 		})
 
+		it("should only store the fields that differ from the base mode", async () => {
+			let settingsContent: any = {
+				customModes: [
+					{ slug: "base", name: "Base", roleDefinition: "Base role", groups: ["read", "edit"] },
+					{ slug: "child", name: "Child", extends: "base" },
+				],
+			}
+
+			;(fs.readFile as Mock).mockImplementation(async (path: string) => {
+				if (path === mockSettingsPath) {
+					return yaml.stringify(settingsContent)
+				}
+				throw new Error("File not found")
+			})
+			;(fs.writeFile as Mock).mockImplementation(async (path: string, content: string) => {
+				if (path === mockSettingsPath) {
+					settingsContent = yaml.parse(content)
+				}
+			})
+
+			await manager.updateCustomMode("child", {
+				slug: "child",
+				name: "Child",
+				extends: "base",
+				roleDefinition: "Base role",
+				customInstructions: "Only review.",
+				groups: ["read", "edit"],
+				source: "global",
+			})
+
+			expect(settingsContent.customModes).toContainEqual({
+				slug: "child",
+				name: "Child",
+				extends: "base",
+				customInstructions: "Only review.",
+				source: "global",
+			})
+		})
+
+		it("should keep the definition of the fields that weren't edited", async () => {
+			const security = {
+				slug: "security",
+				name: "Security",
+				extends: "reviewer",
+				appendRoleDefinition: "You focus on security.",
+				includeInstructions: ["owasp"],
+			}
+			let settingsContent: any = {
+				instructionFragments: { owasp: "Check for the OWASP Top 10." },
+				customModes: [
+					{ slug: "reviewer", name: "Reviewer", roleDefinition: "Reviewer role", groups: ["read"] },
+					security,
+				],
+			}
+
+			;(fs.readFile as Mock).mockImplementation(async (path: string) => {
+				if (path === mockSettingsPath) {
+					return yaml.stringify(settingsContent)
+				}
+				throw new Error("File not found")
+			})
+			;(fs.writeFile as Mock).mockImplementation(async (path: string, content: string) => {
+				if (path === mockSettingsPath) {
+					settingsContent = yaml.parse(content)
+				}
+			})
+
+			// The settings UI saves the mode as it was resolved, with a new name.
+			const resolved = (await manager.getCustomModes()).find((mode) => mode.slug === "security")!
+			await manager.updateCustomMode("security", { ...resolved, name: "Security Reviewer" })
+
+			expect(settingsContent.customModes).toContainEqual({
+				...security,
+				name: "Security Reviewer",
+				source: "global",
+			})
+
+			// Editing the combined role definition replaces it, including what was appended.
+			const renamed = (await manager.getCustomModes()).find((mode) => mode.slug === "security")!
+			await manager.updateCustomMode("security", { ...renamed, roleDefinition: "You audit the code." })
+
+			expect(settingsContent.customModes).toContainEqual({
+				slug: "security",
+				name: "Security Reviewer",
+				extends: "reviewer",
+				roleDefinition: "You audit the code.",
+				includeInstructions: ["owasp"],
+				source: "global",
+			})
+		})
+
 		it("creates .roomodes file when adding project-specific mode", async () => {
 			const projectMode: ModeConfig = {
 				slug: "project-mode",
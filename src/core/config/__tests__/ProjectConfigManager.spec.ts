@@ -0,0 +1,161 @@
+// npx vitest run src/core/config/__tests__/ProjectConfigManager.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+
+import { type ProjectConfig, ORGANIZATION_ALLOW_ALL } from "@roo-code/types"
+
+import { applyProjectAllowList, applyProjectConfig, loadProjectConfig } from "../ProjectConfigManager"
+
+describe("loadProjectConfig", () => {
+	let cwd: string
+
+	const writeConfig = async (fileName: string, content: string) => {
+		await fs.mkdir(path.join(cwd, ".roo"), { recursive: true })
+		await fs.writeFile(path.join(cwd, ".roo", fileName), content)
+	}
+
+	beforeEach(async () => {
+		cwd = await fs.mkdtemp(path.join(os.tmpdir(), "project-config-"))
+	})
+
+	afterEach(async () => {
+		await fs.rm(cwd, { recursive: true, force: true })
+	})
+
+	it("returns undefined without a configuration file", async () => {
+		expect(await loadProjectConfig(cwd)).toBeUndefined()
+	})
+
+	it("reads a YAML configuration", async () => {
+		await writeConfig(
+			"config.yaml",
+			`deniedCommands:
+  - git push
+autoApprove:
+  alwaysAllowExecute: false
+rooignore:
+  - dist/
+`,
+		)
+
+		expect(await loadProjectConfig(cwd)).toEqual({
+			filePath: path.join(cwd, ".roo", "config.yaml"),
+			config: { deniedCommands: ["git push"], autoApprove: { alwaysAllowExecute: false }, rooignore: ["dist/"] },
+		})
+	})
+
+	it("prefers the YAML file over the JSON file", async () => {
+		await writeConfig("config.json", JSON.stringify({ allowedCommands: ["npm test"] }))
+		await writeConfig("config.yaml", "allowedCommands: [npm run lint]")
+
+		expect((await loadProjectConfig(cwd))?.config).toEqual({ allowedCommands: ["npm run lint"] })
+	})
+
+	it("reads a JSON configuration", async () => {
+		await writeConfig("config.json", JSON.stringify({ allowedProviders: { anthropic: "*" } }))
+
+		expect((await loadProjectConfig(cwd))?.config).toEqual({ allowedProviders: { anthropic: "*" } })
+	})
+
+	it("treats an empty file as an empty configuration", async () => {
+		await writeConfig("config.yaml", "")
+
+		expect((await loadProjectConfig(cwd))?.config).toEqual({})
+	})
+
+	it("reports unknown and invalid settings", async () => {
+		await writeConfig("config.yaml", "deniedCommands: git push\nalwaysAllowEverything: true")
+
+		const status = await loadProjectConfig(cwd)
+
+		expect(status?.config).toBeUndefined()
+		expect(status?.error).toContain("deniedCommands")
+		expect(status?.error).toContain("alwaysAllowEverything")
+	})
+
+	it("reports files that can't be parsed", async () => {
+		await writeConfig("config.json", "{ not json")
+
+		const status = await loadProjectConfig(cwd)
+
+		expect(status?.filePath).toBe(path.join(cwd, ".roo", "config.json"))
+		expect(status?.error).toBeTruthy()
+	})
+})
+
+describe("applyProjectConfig", () => {
+	it("returns the settings unchanged without a configuration", () => {
+		const settings = { alwaysAllowExecute: true, allowedCommands: ["git"] }
+
+		expect(applyProjectConfig(settings, undefined)).toBe(settings)
+	})
+
+	it("turns off the auto-approvals the repository disables", () => {
+		const settings = { alwaysAllowExecute: true, alwaysAllowWrite: true, alwaysAllowMcp: false }
+		const config = { autoApprove: { alwaysAllowExecute: false, alwaysAllowWrite: true, alwaysAllowMcp: true } }
+
+		expect(applyProjectConfig(settings, config)).toEqual({
+			alwaysAllowExecute: false,
+			alwaysAllowWrite: true,
+			alwaysAllowMcp: false,
+		})
+	})
+
+	it("adds the denied commands and drops the allowed commands they cover", () => {
+		const settings = { allowedCommands: ["git", "git push --force", "npm test"], deniedCommands: ["rm"] }
+		const config = { deniedCommands: ["Git Push"] }
+
+		expect(applyProjectConfig(settings, config)).toEqual({
+			allowedCommands: ["git", "npm test"],
+			deniedCommands: ["rm", "Git Push"],
+		})
+	})
+
+	it("adds the denied write paths but not the allowed commands", () => {
+		const settings = { allowedCommands: ["git"], deniedWritePaths: ["*.lock"] }
+		const config = { allowedCommands: ["git", "npm test"], autoApprove: { deniedWritePaths: [".env", "*.lock"] } }
+
+		expect(applyProjectConfig(settings, config)).toEqual({
+			allowedCommands: ["git"],
+			deniedWritePaths: ["*.lock", ".env"],
+		})
+	})
+})
+
+describe("applyProjectAllowList", () => {
+	it("returns the allow list unchanged without allowed providers", () => {
+		expect(applyProjectAllowList(ORGANIZATION_ALLOW_ALL, undefined)).toBe(ORGANIZATION_ALLOW_ALL)
+	})
+
+	it("restricts an unrestricted allow list to the repository's providers", () => {
+		expect(applyProjectAllowList(ORGANIZATION_ALLOW_ALL, { anthropic: "*", openai: ["gpt-4o"] })).toEqual({
+			allowAll: false,
+			providers: { anthropic: { allowAll: true }, openai: { allowAll: false, models: ["gpt-4o"] } },
+		})
+	})
+
+	it("keeps only the providers and models the organization allows as well", () => {
+		const allowList = {
+			allowAll: false,
+			providers: {
+				anthropic: { allowAll: false, models: ["claude-sonnet", "claude-opus"] },
+				openai: { allowAll: true },
+			},
+		}
+		const allowedProviders: ProjectConfig["allowedProviders"] = {
+			anthropic: ["claude-sonnet", "claude-haiku"],
+			openai: "*",
+			gemini: "*",
+		}
+
+		expect(applyProjectAllowList(allowList, allowedProviders)).toEqual({
+			allowAll: false,
+			providers: {
+				anthropic: { allowAll: false, models: ["claude-sonnet"] },
+				openai: { allowAll: true },
+			},
+		})
+	})
+})
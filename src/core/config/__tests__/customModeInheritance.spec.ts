@@ -0,0 +1,113 @@
+// npx vitest core/config/__tests__/customModeInheritance.spec.ts
+
+import { type CustomModeDefinition, DEFAULT_MODES } from "@roo-code/types"
+
+import { resolveCustomModes } from "../customModeInheritance"
+
+const code = DEFAULT_MODES.find((mode) => mode.slug === "code")!
+
+describe("resolveCustomModes", () => {
+	it("should leave modes without a base unchanged", () => {
+		const mode: CustomModeDefinition = { slug: "docs", name: "Docs", roleDefinition: "Writer", groups: ["read"] }
+
+		expect(resolveCustomModes([mode])).toEqual({ modes: [mode], errors: [] })
+	})
+
+	it("should inherit the fields a mode doesn't set from a built-in mode", () => {
+		const { modes, errors } = resolveCustomModes([
+			{ slug: "api", name: "API Developer", extends: "code", whenToUse: "Use for API work." },
+		])
+
+		expect(errors).toEqual([])
+		expect(modes).toEqual([
+			{
+				slug: "api",
+				name: "API Developer",
+				extends: "code",
+				roleDefinition: code.roleDefinition,
+				whenToUse: "Use for API work.",
+				description: code.description,
+				customInstructions: code.customInstructions,
+				groups: code.groups,
+			},
+		])
+	})
+
+	it("should append to the inherited role definition and replace the groups", () => {
+		const { modes } = resolveCustomModes([
+			{
+				slug: "tests",
+				name: "Test Writer",
+				extends: "code",
+				appendRoleDefinition: "You specialize in tests.",
+				groups: ["read", ["edit", { fileRegex: "\\.spec\\.ts$" }]],
+			},
+		])
+
+		expect(modes[0].roleDefinition).toBe(`${code.roleDefinition}\n\nYou specialize in tests.`)
+		expect(modes[0].groups).toEqual(["read", ["edit", { fileRegex: "\\.spec\\.ts$" }]])
+		expect(modes[0]).not.toHaveProperty("appendRoleDefinition")
+	})
+
+	it("should extend custom modes in any order", () => {
+		const { modes } = resolveCustomModes([
+			{ slug: "child", name: "Child", extends: "parent", customInstructions: "Child instructions" },
+			{ slug: "parent", name: "Parent", roleDefinition: "Parent role", groups: ["read", "edit"] },
+		])
+
+		expect(modes[0]).toMatchObject({
+			slug: "child",
+			roleDefinition: "Parent role",
+			customInstructions: "Child instructions",
+			groups: ["read", "edit"],
+		})
+	})
+
+	it("should extend the built-in mode when a custom mode overrides and extends the same slug", () => {
+		const { modes } = resolveCustomModes([
+			{ slug: "code", name: "Code", extends: "code", appendRoleDefinition: "Follow the team style guide." },
+		])
+
+		expect(modes[0].roleDefinition).toBe(`${code.roleDefinition}\n\nFollow the team style guide.`)
+	})
+
+	it("should append included instruction fragments", () => {
+		const fragments = { testing: "Always add tests.", security: "Never log secrets." }
+		const { modes } = resolveCustomModes(
+			[
+				{
+					slug: "backend",
+					name: "Backend",
+					roleDefinition: "Backend developer",
+					customInstructions: "Use the repository pattern.",
+					includeInstructions: ["testing", "security"],
+					groups: ["read"],
+				},
+			],
+			fragments,
+		)
+
+		expect(modes[0].customInstructions).toBe(
+			"Use the repository pattern.\n\nAlways add tests.\n\nNever log secrets.",
+		)
+		expect(modes[0]).not.toHaveProperty("includeInstructions")
+	})
+
+	it("should leave out modes that can't be resolved", () => {
+		const { modes, errors } = resolveCustomModes([
+			{ slug: "a", name: "A", extends: "b" },
+			{ slug: "b", name: "B", extends: "a" },
+			{ slug: "c", name: "C", extends: "missing" },
+			{ slug: "d", name: "D", extends: "c" },
+			{ slug: "e", name: "E", roleDefinition: "E", groups: [], includeInstructions: ["unknown"] },
+			{ slug: "f", name: "F", extends: "code" },
+		])
+
+		expect(modes.map(({ slug }) => slug)).toEqual(["f"])
+		expect(errors).toEqual([
+			"Mode 'b' can't extend 'a' because the modes extend each other: a → b → a.",
+			"Mode 'c' extends 'missing', which doesn't exist.",
+			"Mode 'e' includes the instruction fragment 'unknown', which doesn't exist.",
+		])
+	})
+})
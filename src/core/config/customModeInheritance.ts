@@ -0,0 +1,160 @@
+import deepEqual from "fast-deep-equal"
+
+import { type CustomModeDefinition, type ModeConfig, DEFAULT_MODES } from "@roo-code/types"
+
+/**
+ * The fields a mode inherits from the mode it extends, unless it sets them.
+ */
+export const INHERITED_MODE_FIELDS = [
+	"roleDefinition",
+	"whenToUse",
+	"description",
+	"customInstructions",
+	"groups",
+] as const
+
+export interface ResolvedCustomModes {
+	modes: ModeConfig[]
+	errors: string[]
+}
+
+/**
+ * Resolves custom mode definitions into complete modes.
+ *
+ * A mode with `extends` starts from the custom or built-in mode with that slug
+ * and overrides the fields it sets. A custom mode that extends its own slug
+ * extends the built-in mode it overrides. `appendRoleDefinition` is appended
+ * to the (inherited) role definition, and the fragments named in
+ * `includeInstructions` are appended to the (inherited) custom instructions.
+ *
+ * Modes whose base doesn't exist, whose bases form a cycle or that include an
+ * unknown fragment are left out, and the reason is returned in `errors`.
+ */
+export function resolveCustomModes(
+	definitions: CustomModeDefinition[],
+	instructionFragments: Record<string, string> = {},
+): ResolvedCustomModes {
+	const definitionsBySlug = new Map(definitions.map((definition) => [definition.slug, definition]))
+	const resolved = new Map<string, ModeConfig | undefined>()
+	const errors: string[] = []
+
+	// Resolves each mode once, so a base shared by several modes reports its errors once.
+	const resolve = (definition: CustomModeDefinition, chain: string[]): ModeConfig | undefined => {
+		if (!resolved.has(definition.slug)) {
+			resolved.set(definition.slug, build(definition, chain))
+		}
+
+		return resolved.get(definition.slug)
+	}
+
+	const build = (definition: CustomModeDefinition, chain: string[]): ModeConfig | undefined => {
+		const { appendRoleDefinition, includeInstructions = [], ...config } = definition
+		let base: ModeConfig | undefined
+
+		if (config.extends) {
+			const customBase = config.extends !== config.slug ? definitionsBySlug.get(config.extends) : undefined
+
+			if (customBase && chain.includes(customBase.slug)) {
+				errors.push(
+					`Mode '${config.slug}' can't extend '${config.extends}' because the modes extend each other: ${[...chain, config.slug, config.extends].join(" → ")}.`,
+				)
+				return undefined
+			}
+
+			base = customBase
+				? resolve(customBase, [...chain, config.slug])
+				: DEFAULT_MODES.find((mode) => mode.slug === config.extends)
+
+			if (!base) {
+				// A custom base that failed to resolve has already reported why.
+				if (!customBase) {
+					errors.push(`Mode '${config.slug}' extends '${config.extends}', which doesn't exist.`)
+				}
+				return undefined
+			}
+		}
+
+		const missingFragment = includeInstructions.find((name) => instructionFragments[name] === undefined)
+
+		if (missingFragment) {
+			errors.push(
+				`Mode '${config.slug}' includes the instruction fragment '${missingFragment}', which doesn't exist.`,
+			)
+			return undefined
+		}
+
+		const mode = { ...config } as ModeConfig
+		const fields: Record<string, unknown> = mode
+
+		for (const field of INHERITED_MODE_FIELDS) {
+			if (fields[field] === undefined && base?.[field] !== undefined) {
+				fields[field] = base[field]
+			}
+		}
+
+		if (appendRoleDefinition) {
+			mode.roleDefinition = [mode.roleDefinition, appendRoleDefinition].filter(Boolean).join("\n\n")
+		}
+
+		if (includeInstructions.length > 0) {
+			const fragments = includeInstructions.map((name) => instructionFragments[name])
+			mode.customInstructions = [mode.customInstructions, ...fragments].filter(Boolean).join("\n\n")
+		}
+
+		return mode
+	}
+
+	const modes: ModeConfig[] = []
+
+	for (const definition of definitions) {
+		const mode = resolve(definition, [])
+
+		if (mode) {
+			modes.push(mode)
+		}
+	}
+
+	return { modes, errors }
+}
+
+/**
+ * Applies the changes made to a resolved mode to the definition it was
+ * resolved from, so the fields that weren't changed stay as they are in the
+ * file. `appendRoleDefinition` and `includeInstructions` are kept unless the
+ * role definition or custom instructions they add to were changed, since the
+ * changed text already includes what they added.
+ *
+ * @param resolved The mode as it was resolved from `definition`
+ * @param updated The mode to save, without the fields it inherits
+ */
+export function applyModeChanges(
+	definition: CustomModeDefinition,
+	resolved: ModeConfig,
+	updated: CustomModeDefinition,
+): CustomModeDefinition {
+	const result: CustomModeDefinition = { ...definition }
+	const fields: Record<string, unknown> = result
+	const keys = new Set([...Object.keys(resolved), ...Object.keys(updated)]) as Set<keyof ModeConfig>
+
+	for (const key of keys) {
+		if (key === "source" || deepEqual(resolved[key], updated[key])) {
+			continue
+		}
+
+		if (updated[key] === undefined) {
+			delete fields[key]
+		} else {
+			fields[key] = updated[key]
+		}
+
+		if (key === "roleDefinition") {
+			delete result.appendRoleDefinition
+		}
+
+		if (key === "customInstructions") {
+			delete result.includeInstructions
+		}
+	}
+
+	return result
+}
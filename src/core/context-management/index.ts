@@ -6,7 +6,7 @@ import { TelemetryService } from "@roo-code/telemetry"
 import { ApiHandler, ApiHandlerCreateMessageMetadata } from "../../api"
 import { MAX_CONDENSE_THRESHOLD, MIN_CONDENSE_THRESHOLD, summarizeConversation, SummarizeResponse } from "../condense"
 import { ApiMessage } from "../task-persistence/apiMessages"
-import { ANTHROPIC_DEFAULT_MAX_TOKENS } from "@roo-code/types"
+import { ANTHROPIC_DEFAULT_MAX_TOKENS, type CondensingStrategy } from "@roo-code/types"
 import { RooIgnoreController } from "../ignore/RooIgnoreController"
 
 /**
@@ -217,6 +217,8 @@ export type ContextManagementOptions = {
 	systemPrompt: string
 	taskId: string
 	customCondensingPrompt?: string
+	/** Optional strategy deciding what condensing summarizes and what it keeps verbatim */
+	condensingStrategy?: CondensingStrategy
 	profileThresholds: Record<string, number>
 	currentProfileId: string
 	/** Optional metadata to pass through to the condensing API call (tools, taskId, etc.) */
@@ -255,6 +257,7 @@ export async function manageContext({
 	systemPrompt,
 	taskId,
 	customCondensingPrompt,
+	condensingStrategy,
 	profileThresholds,
 	currentProfileId,
 	metadata,
@@ -314,6 +317,7 @@ export async function manageContext({
 				taskId,
 				isAutomaticTrigger: true,
 				customCondensingPrompt,
+				condensingStrategy,
 				metadata,
 				environmentDetails,
 				filesReadByRoo,
@@ -0,0 +1,45 @@
+import type { McpHub, McpResourceUpdate } from "../../services/mcp/McpHub"
+
+// McpResourceTracker
+//
+// Tracks the MCP resources that a task has read with access_mcp_resource. When
+// the server of one of them reports that it changed, the resource is marked as
+// updated so the next environment details can tell Roo to read it again,
+// much like FileContextTracker does for files modified outside of Roo.
+export class McpResourceTracker {
+	private readResources = new Set<string>()
+	private updatedResources = new Map<string, McpResourceUpdate>()
+	private unsubscribe?: () => void
+
+	private static key(serverName: string, uri: string) {
+		return `${serverName}\n${uri}`
+	}
+
+	// Called after Roo reads a resource, which also makes it up to date again.
+	trackResource(mcpHub: McpHub, serverName: string, uri: string): void {
+		const key = McpResourceTracker.key(serverName, uri)
+		this.readResources.add(key)
+		this.updatedResources.delete(key)
+
+		this.unsubscribe ??= mcpHub.onDidUpdateResource((update) => {
+			const updatedKey = McpResourceTracker.key(update.serverName, update.uri)
+
+			if (this.readResources.has(updatedKey)) {
+				this.updatedResources.set(updatedKey, update)
+			}
+		})
+	}
+
+	getAndClearUpdatedResources(): McpResourceUpdate[] {
+		const resources = Array.from(this.updatedResources.values())
+		this.updatedResources.clear()
+		return resources
+	}
+
+	dispose(): void {
+		this.unsubscribe?.()
+		this.unsubscribe = undefined
+		this.readResources.clear()
+		this.updatedResources.clear()
+	}
+}
@@ -0,0 +1,117 @@
+import * as path from "path"
+import fs from "fs/promises"
+import { isBinaryFile } from "isbinaryfile"
+
+import type { ClineMessage } from "@roo-code/types"
+
+import { addLineNumbers } from "../../integrations/misc/extract-text"
+import { RooIgnoreController } from "../ignore/RooIgnoreController"
+
+// Pinned files larger than this are cut off, so a single pin can't fill the context window.
+export const MAX_PINNED_FILE_CHARS = 50_000
+
+// PinnedContext
+//
+// Holds the files and chat messages the user pinned to a task. Pinned items are
+// added to the system prompt on every request instead of the conversation, so they
+// are never truncated or condensed away. Files are read again whenever they change
+// on disk, so the model always sees their current contents.
+export class PinnedContext {
+	private readonly cwd: string
+	private files: string[]
+	private fileCache = new Map<string, { mtimeMs: number; content: string }>()
+
+	constructor(cwd: string, files: string[] = []) {
+		this.cwd = cwd
+		this.files = [...files]
+	}
+
+	getFiles(): string[] {
+		return [...this.files]
+	}
+
+	// Returns whether the pinned files changed.
+	setFilePinned(filePath: string, pinned: boolean): boolean {
+		const relPath = path.isAbsolute(filePath) ? path.relative(this.cwd, filePath) : path.normalize(filePath)
+		const relPosixPath = relPath.split(path.sep).join("/")
+
+		if (pinned === this.files.includes(relPosixPath)) {
+			return false
+		}
+
+		if (pinned) {
+			this.files.push(relPosixPath)
+		} else {
+			this.files = this.files.filter((file) => file !== relPosixPath)
+			this.fileCache.delete(relPosixPath)
+		}
+
+		return true
+	}
+
+	private async readFile(relPath: string): Promise<string> {
+		const absolutePath = path.resolve(this.cwd, relPath)
+
+		try {
+			const { mtimeMs } = await fs.stat(absolutePath)
+			const cached = this.fileCache.get(relPath)
+
+			if (cached?.mtimeMs === mtimeMs) {
+				return cached.content
+			}
+
+			let content: string
+
+			if (await isBinaryFile(absolutePath)) {
+				content = "(Binary file, contents not shown)"
+			} else {
+				const text = await fs.readFile(absolutePath, "utf8")
+				content = addLineNumbers(text.slice(0, MAX_PINNED_FILE_CHARS))
+
+				if (text.length > MAX_PINNED_FILE_CHARS) {
+					content += `\n[... ${text.length - MAX_PINNED_FILE_CHARS} characters omitted, use read_file for the rest]`
+				}
+			}
+
+			this.fileCache.set(relPath, { mtimeMs, content })
+			return content
+		} catch (error) {
+			this.fileCache.delete(relPath)
+			return `(Failed to read file: ${error instanceof Error ? error.message : String(error)})`
+		}
+	}
+
+	/**
+	 * Returns the system prompt section with the current contents of the pinned
+	 * files and the text of the pinned messages, or an empty string when nothing
+	 * is pinned. Files blocked by .rooignore are left out.
+	 */
+	async format(messages: ClineMessage[], rooIgnoreController?: RooIgnoreController): Promise<string> {
+		const files = this.files.filter((file) => rooIgnoreController?.validateAccess(file) ?? true)
+		const pinnedMessages = messages.filter((message) => message.isPinned && message.text)
+
+		if (files.length === 0 && pinnedMessages.length === 0) {
+			return ""
+		}
+
+		const blocks = await Promise.all(
+			files.map(async (file) => `<pinned_file path="${file}">\n${await this.readFile(file)}\n</pinned_file>`),
+		)
+
+		for (const message of pinnedMessages) {
+			// The first message is the task the user gave.
+			const from = message === messages[0] || message.say === "user_feedback" ? "user" : "assistant"
+			blocks.push(`<pinned_message from="${from}">\n${message.text}\n</pinned_message>`)
+		}
+
+		return `
+
+====
+
+PINNED CONTEXT
+
+The user pinned the following files and messages to this task. They stay in your context for the whole task, and pinned files always show their current contents, so you don't need to read them again with a tool.
+
+${blocks.join("\n\n")}`
+	}
+}
@@ -0,0 +1,71 @@
+// npx vitest run src/core/context-tracking/__tests__/McpResourceTracker.spec.ts
+
+import type { McpHub, McpResourceUpdate } from "../../../services/mcp/McpHub"
+
+import { McpResourceTracker } from "../McpResourceTracker"
+
+const createMcpHub = () => {
+	const listeners = new Set<(update: McpResourceUpdate) => void>()
+
+	const mcpHub = {
+		onDidUpdateResource: vi.fn((listener: (update: McpResourceUpdate) => void) => {
+			listeners.add(listener)
+			return () => listeners.delete(listener)
+		}),
+	} as unknown as McpHub
+
+	const update = (serverName: string, uri: string) =>
+		listeners.forEach((listener) => listener({ serverName, source: "global", uri }))
+
+	return { mcpHub, listeners, update }
+}
+
+describe("McpResourceTracker", () => {
+	it("reports the changes to resources the task read", () => {
+		const { mcpHub, update } = createMcpHub()
+		const tracker = new McpResourceTracker()
+
+		tracker.trackResource(mcpHub, "tickets", "ticket://1")
+		update("tickets", "ticket://1")
+		update("tickets", "ticket://2")
+		update("docs", "ticket://1")
+
+		expect(tracker.getAndClearUpdatedResources()).toEqual([
+			{ serverName: "tickets", source: "global", uri: "ticket://1" },
+		])
+		expect(tracker.getAndClearUpdatedResources()).toEqual([])
+	})
+
+	it("reports a resource once however often it changes", () => {
+		const { mcpHub, update } = createMcpHub()
+		const tracker = new McpResourceTracker()
+
+		tracker.trackResource(mcpHub, "tickets", "ticket://1")
+		update("tickets", "ticket://1")
+		update("tickets", "ticket://1")
+
+		expect(tracker.getAndClearUpdatedResources()).toHaveLength(1)
+	})
+
+	it("forgets a change once the resource is read again", () => {
+		const { mcpHub, update } = createMcpHub()
+		const tracker = new McpResourceTracker()
+
+		tracker.trackResource(mcpHub, "tickets", "ticket://1")
+		update("tickets", "ticket://1")
+		tracker.trackResource(mcpHub, "tickets", "ticket://1")
+
+		expect(tracker.getAndClearUpdatedResources()).toEqual([])
+		expect(mcpHub.onDidUpdateResource).toHaveBeenCalledTimes(1)
+	})
+
+	it("stops listening when disposed", () => {
+		const { mcpHub, listeners } = createMcpHub()
+		const tracker = new McpResourceTracker()
+
+		tracker.trackResource(mcpHub, "tickets", "ticket://1")
+		tracker.dispose()
+
+		expect(listeners.size).toBe(0)
+	})
+})
@@ -0,0 +1,78 @@
+// npx vitest src/core/context-tracking/__tests__/PinnedContext.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+
+import type { ClineMessage } from "@roo-code/types"
+
+import type { RooIgnoreController } from "../../ignore/RooIgnoreController"
+import { PinnedContext } from "../PinnedContext"
+
+describe("PinnedContext", () => {
+	let cwd: string
+
+	beforeEach(async () => {
+		cwd = await fs.mkdtemp(path.join(os.tmpdir(), "pinned-context-"))
+		await fs.mkdir(path.join(cwd, "src"))
+		await fs.writeFile(path.join(cwd, "src", "app.ts"), "const a = 1\n")
+	})
+
+	afterEach(async () => {
+		await fs.rm(cwd, { recursive: true, force: true })
+	})
+
+	it("should be empty when nothing is pinned", async () => {
+		expect(await new PinnedContext(cwd).format([])).toBe("")
+	})
+
+	it("should store pinned files relative to the workspace", () => {
+		const pinnedContext = new PinnedContext(cwd)
+
+		expect(pinnedContext.setFilePinned(path.join(cwd, "src", "app.ts"), true)).toBe(true)
+		expect(pinnedContext.setFilePinned("src/app.ts", true)).toBe(false)
+		expect(pinnedContext.getFiles()).toEqual(["src/app.ts"])
+
+		expect(pinnedContext.setFilePinned("src/app.ts", false)).toBe(true)
+		expect(pinnedContext.getFiles()).toEqual([])
+	})
+
+	it("should include the current contents of pinned files", async () => {
+		const pinnedContext = new PinnedContext(cwd, ["src/app.ts"])
+
+		expect(await pinnedContext.format([])).toContain('<pinned_file path="src/app.ts">\n1 | const a = 1')
+
+		// Make sure the modification time changes, even on file systems with coarse timestamps.
+		const filePath = path.join(cwd, "src", "app.ts")
+		await fs.writeFile(filePath, "const b = 2\n")
+		await fs.utimes(filePath, new Date(), new Date(Date.now() + 10_000))
+
+		const result = await pinnedContext.format([])
+		expect(result).toContain("1 | const b = 2")
+		expect(result).not.toContain("const a = 1")
+	})
+
+	it("should include pinned messages", async () => {
+		const messages: ClineMessage[] = [
+			{ ts: 1, type: "say", say: "text", text: "Build the app", isPinned: true },
+			{ ts: 2, type: "say", say: "text", text: "Not pinned" },
+			{ ts: 3, type: "say", say: "text", text: "I will use React", isPinned: true },
+			{ ts: 4, type: "say", say: "user_feedback", text: "Use Vue instead", isPinned: true },
+		]
+
+		const result = await new PinnedContext(cwd).format(messages)
+
+		expect(result).toContain('<pinned_message from="user">\nBuild the app\n</pinned_message>')
+		expect(result).toContain('<pinned_message from="assistant">\nI will use React\n</pinned_message>')
+		expect(result).toContain('<pinned_message from="user">\nUse Vue instead\n</pinned_message>')
+		expect(result).not.toContain("Not pinned")
+	})
+
+	it("should leave out files blocked by .rooignore", async () => {
+		const rooIgnoreController = {
+			validateAccess: (filePath: string) => !filePath.startsWith("src/"),
+		} as unknown as RooIgnoreController
+
+		expect(await new PinnedContext(cwd, ["src/app.ts"]).format([], rooIgnoreController)).toBe("")
+	})
+})
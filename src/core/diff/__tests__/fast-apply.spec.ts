@@ -0,0 +1,103 @@
+// npx vitest run src/core/diff/__tests__/fast-apply.spec.ts
+
+import type { ProviderSettings } from "@roo-code/types"
+
+import { singleCompletionHandler } from "../../../utils/single-completion-handler"
+import { fastApply, parseFastApplyResponse, validateFastApplyResult } from "../fast-apply"
+
+vi.mock("../../../utils/single-completion-handler", () => ({
+	singleCompletionHandler: vi.fn(),
+}))
+
+const apiConfiguration: ProviderSettings = { apiProvider: "openai", openAiModelId: "morph-v3-fast" }
+
+const lines = (count: number) => Array.from({ length: count }, (_, i) => `const line${i} = ${i}`).join("\n")
+
+describe("parseFastApplyResponse", () => {
+	it("should return a plain response unchanged", () => {
+		expect(parseFastApplyResponse("const a = 1\n")).toBe("const a = 1\n")
+	})
+
+	it("should remove a code fence", () => {
+		const response = "```typescript\nconst a = 1\nconst b = 2\n```\n"
+
+		expect(parseFastApplyResponse(response)).toBe("const a = 1\nconst b = 2")
+	})
+
+	it("should remove an updated_code wrapper and normalize line endings", () => {
+		expect(parseFastApplyResponse("<updated_code>\r\nconst a = 1\r\n</updated_code>")).toBe("const a = 1")
+	})
+})
+
+describe("validateFastApplyResult", () => {
+	it("should accept a complete merge", () => {
+		expect(validateFastApplyResult("const a = 1", "const a = 2", "const a = 2")).toBeUndefined()
+	})
+
+	it("should reject an empty result", () => {
+		expect(validateFastApplyResult("const a = 1", "const a = 2", "  \n")).toContain("empty")
+	})
+
+	it("should reject a result that still contains a marker", () => {
+		const edit = "// ... existing code ...\nconst b = 2"
+
+		expect(validateFastApplyResult("const a = 1\nconst b = 1", edit, edit)).toContain("marker")
+		// A marker that was already part of the file is kept.
+		const original = "# ... existing code ...\nb = 1"
+		expect(validateFastApplyResult(original, edit, original.replace("1", "2"))).toBeUndefined()
+	})
+
+	it("should reject a result that dropped most of the file", () => {
+		const edit = "// ... existing code ...\nconst line5 = 50\n// ... existing code ..."
+
+		expect(validateFastApplyResult(lines(40), edit, lines(10))).toContain("dropped code")
+		expect(validateFastApplyResult(lines(40), "const line5 = 50", lines(10))).toBeUndefined()
+	})
+})
+
+describe("fastApply", () => {
+	beforeEach(() => {
+		vi.mocked(singleCompletionHandler).mockReset()
+	})
+
+	it("should send the original and the edit to the fast-apply model", async () => {
+		vi.mocked(singleCompletionHandler).mockResolvedValue("const a = 2\nconst b = 1")
+
+		const original = "const a = 1\nconst b = 1\n"
+		const result = await fastApply(apiConfiguration, original, "I am changing a.", "const a = 2")
+
+		expect(result).toEqual({ success: true, content: "const a = 2\nconst b = 1\n" })
+
+		const prompt = vi.mocked(singleCompletionHandler).mock.calls[0][1]
+		expect(vi.mocked(singleCompletionHandler).mock.calls[0][0]).toBe(apiConfiguration)
+		expect(prompt).toContain("<instructions>\nI am changing a.\n</instructions>")
+		expect(prompt).toContain("<original_code>\nconst a = 1\nconst b = 1\n\n</original_code>")
+		expect(prompt).toContain("<update>\nconst a = 2\n</update>")
+	})
+
+	it("should keep a missing trailing newline missing", async () => {
+		vi.mocked(singleCompletionHandler).mockResolvedValue("const a = 2\n")
+
+		expect(await fastApply(apiConfiguration, "const a = 1", "", "const a = 2")).toEqual({
+			success: true,
+			content: "const a = 2",
+		})
+	})
+
+	it("should report a failed request", async () => {
+		vi.mocked(singleCompletionHandler).mockRejectedValue(new Error("401 Unauthorized"))
+
+		expect(await fastApply(apiConfiguration, "const a = 1", "", "const a = 2")).toEqual({
+			success: false,
+			error: "The fast-apply model request failed: 401 Unauthorized",
+		})
+	})
+
+	it("should report an invalid merge", async () => {
+		vi.mocked(singleCompletionHandler).mockResolvedValue("")
+
+		const result = await fastApply(apiConfiguration, "const a = 1", "", "const a = 2")
+
+		expect(result.success).toBe(false)
+	})
+})
@@ -0,0 +1,114 @@
+import type { ProviderSettings } from "@roo-code/types"
+
+import { singleCompletionHandler } from "../../utils/single-completion-handler"
+
+/**
+ * Fast-apply merges a loose edit, written with "... existing code ..." markers
+ * for the unchanged parts, into a file using a separate, cheap model. Unlike a
+ * search/replace diff it doesn't need the unchanged code to match exactly.
+ */
+
+// Matches the placeholder comments the main model uses for unchanged code, e.g.
+// "// ... existing code ..." or "# ... rest of the file ...".
+const EXISTING_CODE_MARKER = /^\s*(?:\/\/|#|--|\/\*|<!--|;)?\s*\.\.\.\s*(?:existing|rest of|unchanged)\b[^\n]*$/im
+
+export function buildFastApplyPrompt(originalContent: string, instructions: string, codeEdit: string): string {
+	return `Merge the update into the original code and output the complete updated file.
+
+The update shows only the changed parts of the file. Comments such as "// ... existing code ..." stand for the unchanged code between them, which must be copied from the original exactly. Keep everything the update doesn't change, including comments, whitespace and formatting. Output only the merged file, with no explanation and no markdown code fences.
+
+<instructions>
+${instructions}
+</instructions>
+
+<original_code>
+${originalContent}
+</original_code>
+
+<update>
+${codeEdit}
+</update>`
+}
+
+/**
+ * Extracts the merged file from the model's response, removing a code fence
+ * or an <updated_code> wrapper the model added despite the instructions.
+ */
+export function parseFastApplyResponse(response: string): string {
+	let content = response.replace(/\r\n/g, "\n")
+
+	const wrapped = content.match(/<updated_code>\n?([\s\S]*?)\n?<\/updated_code>/)
+
+	if (wrapped) {
+		content = wrapped[1]
+	}
+
+	const fenced = content.match(/^\s*```[^\n]*\n([\s\S]*?)\n```\s*$/)
+
+	if (fenced) {
+		content = fenced[1]
+	}
+
+	return content
+}
+
+/**
+ * Returns why a merged file looks wrong, or undefined if it looks complete.
+ * Fast-apply models occasionally copy the markers or drop most of the file.
+ */
+export function validateFastApplyResult(originalContent: string, codeEdit: string, merged: string): string | undefined {
+	if (!merged.trim()) {
+		return "The fast-apply model returned an empty file."
+	}
+
+	if (EXISTING_CODE_MARKER.test(merged) && !EXISTING_CODE_MARKER.test(originalContent)) {
+		return "The fast-apply model left an '... existing code ...' marker in the file instead of merging the edit."
+	}
+
+	const originalLines = originalContent.split("\n").length
+	const mergedLines = merged.split("\n").length
+
+	// An edit with markers keeps the code between them, so a much shorter file means the merge dropped code.
+	if (EXISTING_CODE_MARKER.test(codeEdit) && originalLines >= 20 && mergedLines < originalLines / 2) {
+		return `The merged file has ${mergedLines} lines, down from ${originalLines}, so the fast-apply model likely dropped code.`
+	}
+
+	return undefined
+}
+
+export type FastApplyResult = { success: true; content: string } | { success: false; error: string }
+
+export async function fastApply(
+	apiConfiguration: ProviderSettings,
+	originalContent: string,
+	instructions: string,
+	codeEdit: string,
+): Promise<FastApplyResult> {
+	let response: string
+
+	try {
+		response = await singleCompletionHandler(
+			apiConfiguration,
+			buildFastApplyPrompt(originalContent, instructions, codeEdit),
+		)
+	} catch (error) {
+		return {
+			success: false,
+			error: `The fast-apply model request failed: ${error instanceof Error ? error.message : String(error)}`,
+		}
+	}
+
+	let content = parseFastApplyResponse(response)
+	const error = validateFastApplyResult(originalContent, codeEdit, content)
+
+	if (error) {
+		return { success: false, error }
+	}
+
+	// Keep the original's trailing newline, which models tend to drop or add.
+	if (originalContent.endsWith("\n") !== content.endsWith("\n")) {
+		content = originalContent.endsWith("\n") ? `${content}\n` : content.replace(/\n$/, "")
+	}
+
+	return { success: true, content }
+}
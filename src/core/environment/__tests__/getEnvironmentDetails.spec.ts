@@ -11,8 +11,10 @@ import { getApiMetrics } from "../../../shared/getApiMetrics"
 import { listFiles } from "../../../services/glob/list-files"
 import { TerminalRegistry } from "../../../integrations/terminal/TerminalRegistry"
 import { Terminal } from "../../../integrations/terminal/Terminal"
+import type { BackgroundProcessManager } from "../../../integrations/terminal/BackgroundProcessManager"
 import { arePathsEqual } from "../../../utils/path"
 import { FileContextTracker } from "../../context-tracking/FileContextTracker"
+import { McpResourceTracker } from "../../context-tracking/McpResourceTracker"
 import { ApiHandler } from "../../../api/index"
 import { ClineProvider } from "../../webview/ClineProvider"
 import { RooIgnoreController } from "../../ignore/RooIgnoreController"
@@ -94,6 +96,12 @@ describe("getEnvironmentDetails", () => {
 			fileContextTracker: {
 				getAndClearRecentlyModifiedFiles: vi.fn().mockReturnValue([]),
 			} as unknown as FileContextTracker,
+			mcpResourceTracker: {
+				getAndClearUpdatedResources: vi.fn().mockReturnValue([]),
+			} as unknown as McpResourceTracker,
+			backgroundProcesses: {
+				list: vi.fn().mockReturnValue([]),
+			} as unknown as BackgroundProcessManager,
 			rooIgnoreController: {
 				filterPaths: vi.fn((paths: string[]) => paths.join("\n")),
 				cwd: mockCwd,
@@ -218,6 +226,41 @@ describe("getEnvironmentDetails", () => {
 		expect(result).toContain("modified2.ts")
 	})
 
+	it("should include the MCP resources that changed since they were read", async () => {
+		;(mockCline.mcpResourceTracker!.getAndClearUpdatedResources as Mock).mockReturnValue([
+			{ serverName: "tickets", source: "global", uri: "ticket://1" },
+		])
+
+		const result = await getEnvironmentDetails(mockCline as Task)
+
+		expect(result).toContain("# Updated MCP Resources")
+		expect(result).toContain("tickets: ticket://1")
+	})
+
+	it("should not include changed MCP resources when the notifications are turned off", async () => {
+		mockProvider.getState.mockResolvedValue({ ...mockState, mcpResourceUpdateNotifications: false })
+		;(mockCline.mcpResourceTracker!.getAndClearUpdatedResources as Mock).mockReturnValue([
+			{ serverName: "tickets", source: "global", uri: "ticket://1" },
+		])
+
+		const result = await getEnvironmentDetails(mockCline as Task)
+
+		expect(result).not.toContain("# Updated MCP Resources")
+	})
+
+	it("should include the status of background processes", async () => {
+		;(mockCline.backgroundProcesses!.list as Mock).mockReturnValue([
+			{ id: 1, command: "npm run dev", status: "running" },
+			{ id: 2, command: "tsc --watch", status: "exited", exitCode: 2 },
+		])
+
+		const result = await getEnvironmentDetails(mockCline as Task)
+
+		expect(result).toContain("# Background Processes")
+		expect(result).toContain("- Process 1: `npm run dev` (running)")
+		expect(result).toContain("- Process 2: `tsc --watch` (exited with exit code 2)")
+	})
+
 	it("should include active terminal information", async () => {
 		const mockActiveTerminal = {
 			id: "terminal-1",
@@ -13,6 +13,7 @@ import { getApiMetrics } from "../../shared/getApiMetrics"
 import { listFiles } from "../../services/glob/list-files"
 import { TerminalRegistry } from "../../integrations/terminal/TerminalRegistry"
 import { Terminal } from "../../integrations/terminal/Terminal"
+import { formatBackgroundProcess } from "../../integrations/terminal/BackgroundProcessManager"
 import { arePathsEqual } from "../../utils/path"
 import { formatResponse } from "../prompts/responses"
 import { getGitStatus } from "../../utils/git"
@@ -155,6 +156,17 @@ export async function getEnvironmentDetails(cline: Task, includeFileDetails: boo
 		}
 	}
 
+	// Background processes only report their status; their output is read on demand.
+	const backgroundProcesses = cline.backgroundProcesses.list()
+
+	if (backgroundProcesses.length > 0) {
+		terminalDetails += "\n\n# Background Processes"
+
+		for (const backgroundProcess of backgroundProcesses) {
+			terminalDetails += `\n- ${formatBackgroundProcess(backgroundProcess)}`
+		}
+	}
+
 	// console.log(`[Task#getEnvironmentDetails] terminalDetails: ${terminalDetails}`)
 
 	// Add recently modified files section.
@@ -168,6 +180,17 @@ export async function getEnvironmentDetails(cline: Task, includeFileDetails: boo
 		}
 	}
 
+	// Add the MCP resources that changed on their server since Roo read them.
+	const updatedMcpResources = cline.mcpResourceTracker.getAndClearUpdatedResources()
+
+	if (state?.mcpResourceUpdateNotifications !== false && updatedMcpResources.length > 0) {
+		details +=
+			"\n\n# Updated MCP Resources\nThese MCP resources have changed on their server since you last accessed them (use access_mcp_resource to read the current contents if you still need them):"
+		for (const { serverName, uri } of updatedMcpResources) {
+			details += `\n${serverName}: ${uri}`
+		}
+	}
+
 	if (terminalDetails) {
 		details += terminalDetails
 	}
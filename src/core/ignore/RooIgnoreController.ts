@@ -5,8 +5,72 @@ import fsSync from "fs"
 import ignore, { Ignore } from "ignore"
 import * as vscode from "vscode"
 
+import { PROJECT_CONFIG_FILE_NAMES } from "@roo-code/types"
+
+import { loadProjectConfig } from "../config/ProjectConfigManager"
+
 export const LOCK_TEXT_SYMBOL = "\u{1F512}"
 
+// Token counts for size rules are estimated from the file size.
+const BYTES_PER_TOKEN = 4
+
+// The directives of the .rooignore lines that are rules rather than path patterns
+const RULE_DIRECTIVES = new Set(["@max-size", "@token-budget", "@ignore-generated"])
+
+// Files matched by `@ignore-generated` by name: lockfiles, minified bundles, source maps and generated code.
+const GENERATED_FILE_PATTERNS = [
+	"*.min.js",
+	"*.min.mjs",
+	"*.min.css",
+	"*.map",
+	"*.bundle.js",
+	"*.chunk.js",
+	"*.generated.*",
+	"*.pb.go",
+	"*_pb2.py",
+	"*.g.dart",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"bun.lockb",
+	"Cargo.lock",
+	"poetry.lock",
+	"Pipfile.lock",
+	"composer.lock",
+	"Gemfile.lock",
+	"go.sum",
+]
+
+// Markers in the first bytes of a file that tell it was generated.
+const GENERATED_HEADER_REGEX = /@generated\b|\bDO NOT EDIT\b|\bauto-?generated\b/i
+const GENERATED_HEADER_BYTES = 1024
+
+// Script and style files whose first line is at least this long are treated as minified.
+// Must not exceed GENERATED_HEADER_BYTES, since only the header is read.
+const MINIFIED_EXTENSIONS = [".js", ".mjs", ".cjs", ".css"]
+const MINIFIED_LINE_LENGTH = 1000
+
+const SIZE_UNITS: Record<string, number> = { b: 1, kb: 1024, mb: 1024 ** 2, gb: 1024 ** 3 }
+
+/**
+ * Parses a size like "500KB", "1.5 MB" or "2048" (bytes).
+ * @returns the size in bytes, or undefined if it is not a valid size
+ */
+export function parseFileSize(value: string): number | undefined {
+	const match = value.trim().match(/^(\d+(?:\.\d+)?)\s*(b|kb|mb|gb)?$/i)
+	return match ? Math.floor(parseFloat(match[1]) * SIZE_UNITS[(match[2] ?? "b").toLowerCase()]) : undefined
+}
+
+/**
+ * A size limit from a `@max-size` or `@token-budget` rule, optionally scoped to
+ * the files matched by gitignore-style patterns.
+ */
+interface SizeRule {
+	maxBytes: number
+	scope?: Ignore
+}
+
 /**
  * Controls LLM access to files by enforcing ignore patterns.
  * Designed to be instantiated once in Cline.ts and passed to file manipulation services.
@@ -15,6 +79,10 @@ export const LOCK_TEXT_SYMBOL = "\u{1F512}"
 export class RooIgnoreController {
 	private cwd: string
 	private ignoreInstance: Ignore
+	private sizeRules: SizeRule[] = []
+	private ignoreGenerated = false
+	private generatedFileNames = ignore().add(GENERATED_FILE_PATTERNS)
+	private generatedCache = new Map<string, { mtimeMs: number; generated: boolean }>()
 	private disposables: vscode.Disposable[] = []
 	rooIgnoreContent: string | undefined
 
@@ -35,42 +103,56 @@ export class RooIgnoreController {
 	}
 
 	/**
-	 * Set up the file watcher for .rooignore changes
+	 * Set up the file watchers for .rooignore and .roo/config changes
 	 */
 	private setupFileWatcher(): void {
-		const rooignorePattern = new vscode.RelativePattern(this.cwd, ".rooignore")
-		const fileWatcher = vscode.workspace.createFileSystemWatcher(rooignorePattern)
-
-		// Watch for changes and updates
-		this.disposables.push(
-			fileWatcher.onDidChange(() => {
-				this.loadRooIgnore()
-			}),
-			fileWatcher.onDidCreate(() => {
-				this.loadRooIgnore()
-			}),
-			fileWatcher.onDidDelete(() => {
-				this.loadRooIgnore()
-			}),
-		)
+		const patterns = [".rooignore", `.roo/{${PROJECT_CONFIG_FILE_NAMES.join(",")}}`]
 
-		// Add fileWatcher itself to disposables
-		this.disposables.push(fileWatcher)
+		for (const pattern of patterns) {
+			const fileWatcher = vscode.workspace.createFileSystemWatcher(new vscode.RelativePattern(this.cwd, pattern))
+
+			// Watch for changes and updates
+			this.disposables.push(
+				fileWatcher.onDidChange(() => {
+					this.loadRooIgnore()
+				}),
+				fileWatcher.onDidCreate(() => {
+					this.loadRooIgnore()
+				}),
+				fileWatcher.onDidDelete(() => {
+					this.loadRooIgnore()
+				}),
+			)
+
+			// Add fileWatcher itself to disposables
+			this.disposables.push(fileWatcher)
+		}
 	}
 
 	/**
-	 * Load custom patterns from .rooignore if it exists
+	 * Load custom patterns from .rooignore and the rooignore additions of
+	 * .roo/config if they exist
 	 */
 	private async loadRooIgnore(): Promise<void> {
 		try {
-			// Reset ignore instance to prevent duplicate patterns
+			// Reset ignore instance and rules to prevent duplicates
 			this.ignoreInstance = ignore()
+			this.sizeRules = []
+			this.ignoreGenerated = false
+			this.generatedCache.clear()
 			const ignorePath = path.join(this.cwd, ".rooignore")
-			if (await fileExistsAtPath(ignorePath)) {
-				const content = await fs.readFile(ignorePath, "utf8")
+			const hasRooIgnore = await fileExistsAtPath(ignorePath)
+			const fileContent = hasRooIgnore ? await fs.readFile(ignorePath, "utf8") : undefined
+			const sharedPatterns = (await loadProjectConfig(this.cwd))?.config?.rooignore ?? []
+
+			if (fileContent !== undefined || sharedPatterns.length > 0) {
+				const content = [...(fileContent !== undefined ? [fileContent] : []), ...sharedPatterns].join("\n")
 				this.rooIgnoreContent = content
-				this.ignoreInstance.add(content)
-				this.ignoreInstance.add(".rooignore")
+				this.ignoreInstance.add(this.parseRules(content))
+
+				if (hasRooIgnore) {
+					this.ignoreInstance.add(".rooignore")
+				}
 			} else {
 				this.rooIgnoreContent = undefined
 			}
@@ -80,6 +162,119 @@ export class RooIgnoreController {
 		}
 	}
 
+	/**
+	 * Collects the rules in .rooignore that go beyond path patterns:
+	 *
+	 * - `@max-size <size> [patterns...]`: ignore files larger than <size>, e.g. 500KB
+	 * - `@token-budget <tokens> [patterns...]`: ignore files estimated at more than <tokens> tokens
+	 * - `@ignore-generated`: ignore lockfiles, minified and generated files
+	 *
+	 * Patterns scope a rule to the matching files, e.g. `@token-budget 20000 docs/`.
+	 * Invalid rules are skipped. Other lines starting with `@` are path patterns,
+	 * such as `@types/` or `@myorg/`.
+	 *
+	 * @returns the remaining path patterns
+	 */
+	private parseRules(content: string): string {
+		const patterns: string[] = []
+
+		for (const line of content.split(/\r?\n/)) {
+			const [directive, value, ...scope] = line.trim().split(/\s+/)
+
+			if (!RULE_DIRECTIVES.has(directive)) {
+				patterns.push(line)
+				continue
+			}
+
+			if (directive === "@ignore-generated") {
+				this.ignoreGenerated = true
+				continue
+			}
+
+			const maxBytes =
+				directive === "@max-size"
+					? parseFileSize(value ?? "")
+					: directive === "@token-budget" && /^\d+$/.test(value ?? "")
+						? parseInt(value, 10) * BYTES_PER_TOKEN
+						: undefined
+
+			if (maxBytes === undefined) {
+				console.warn(`Ignoring invalid .rooignore rule: ${line}`)
+				continue
+			}
+
+			this.sizeRules.push({ maxBytes, scope: scope.length > 0 ? ignore().add(scope) : undefined })
+		}
+
+		return patterns.join("\n")
+	}
+
+	/**
+	 * Check a file against the size and generated-file rules.
+	 * @param absolutePath - Resolved path of the file
+	 * @param relativePath - Path of the file relative to cwd, in POSIX format
+	 * @returns true if the file is blocked by a rule
+	 */
+	private isBlockedByFileRules(absolutePath: string, relativePath: string): boolean {
+		if (this.sizeRules.length === 0 && !this.ignoreGenerated) {
+			return false
+		}
+
+		let stats: fsSync.Stats
+		try {
+			stats = fsSync.statSync(absolutePath)
+		} catch {
+			return false
+		}
+
+		// The rules are about file contents, so they never block directories.
+		if (!stats.isFile()) {
+			return false
+		}
+
+		const exceedsSize = this.sizeRules.some(
+			({ maxBytes, scope }) => stats.size > maxBytes && (!scope || scope.ignores(relativePath)),
+		)
+
+		return exceedsSize || (this.ignoreGenerated && this.isGeneratedFile(absolutePath, relativePath, stats))
+	}
+
+	private isGeneratedFile(absolutePath: string, relativePath: string, stats: fsSync.Stats): boolean {
+		if (this.generatedFileNames.ignores(relativePath)) {
+			return true
+		}
+
+		const cached = this.generatedCache.get(absolutePath)
+		if (cached?.mtimeMs === stats.mtimeMs) {
+			return cached.generated
+		}
+
+		let generated = false
+		const isScriptOrStyle = MINIFIED_EXTENSIONS.includes(path.extname(absolutePath).toLowerCase())
+		let fd: number | undefined
+
+		try {
+			fd = fsSync.openSync(absolutePath, "r")
+			const buffer = Buffer.alloc(Math.min(GENERATED_HEADER_BYTES, stats.size))
+			const header = buffer.subarray(0, fsSync.readSync(fd, buffer, 0, buffer.length, 0)).toString("utf8")
+
+			generated =
+				GENERATED_HEADER_REGEX.test(header) ||
+				(isScriptOrStyle &&
+					header.length >= MINIFIED_LINE_LENGTH &&
+					!header.slice(0, MINIFIED_LINE_LENGTH).includes("\n"))
+		} catch {
+			// Unreadable files are left to the other checks
+		} finally {
+			if (fd !== undefined) {
+				fsSync.closeSync(fd)
+			}
+		}
+
+		this.generatedCache.set(absolutePath, { mtimeMs: stats.mtimeMs, generated })
+		return generated
+	}
+
 	/**
 	 * Check if a file should be accessible to the LLM
 	 * Automatically resolves symlinks
@@ -107,8 +302,8 @@ export class RooIgnoreController {
 			// Convert real path to relative for .rooignore checking
 			const relativePath = path.relative(this.cwd, realPath).toPosix()
 
-			// Check if the real path is ignored
-			return !this.ignoreInstance.ignores(relativePath)
+			// Check if the real path is ignored, by pattern or by the size and generated-file rules
+			return !this.ignoreInstance.ignores(relativePath) && !this.isBlockedByFileRules(realPath, relativePath)
 		} catch (error) {
 			// Allow access to files outside cwd or on errors (backward compatibility)
 			return true
@@ -208,6 +403,11 @@ export class RooIgnoreController {
 			return undefined
 		}
 
-		return `# .rooignore\n\n(The following is provided by a root-level .rooignore file where the user has specified files and directories that should not be accessed. When using list_files, you'll notice a ${LOCK_TEXT_SYMBOL} next to files that are blocked. Attempting to access the file's contents e.g. through read_file will result in an error.)\n\n${this.rooIgnoreContent}\n.rooignore`
+		const rulesNote =
+			this.sizeRules.length > 0 || this.ignoreGenerated
+				? " Lines starting with @max-size, @token-budget or @ignore-generated are rules that also block files by size, estimated token count, or because they are generated or minified."
+				: ""
+
+		return `# .rooignore\n\n(The following is provided by a root-level .rooignore file where the user has specified files and directories that should not be accessed. When using list_files, you'll notice a ${LOCK_TEXT_SYMBOL} next to files that are blocked. Attempting to access the file's contents e.g. through read_file will result in an error.${rulesNote})\n\n${this.rooIgnoreContent}\n.rooignore`
 	}
 }
@@ -120,6 +120,23 @@ describe("RooIgnoreController", () => {
 			expect(controller.validateAccess("secrets.json")).toBe(true)
 		})
 
+		/**
+		 * Tests the rooignore additions of the repository's .roo/config
+		 */
+		it("should add the rooignore patterns of .roo/config", async () => {
+			const configPath = path.join(TEST_CWD, ".roo", "config.yaml")
+			mockFileExists.mockImplementation(async (filePath) => filePath === configPath)
+			mockReadFile.mockResolvedValue("rooignore:\n  - secrets.json\n  - dist/")
+
+			await controller.initialize()
+
+			expect(mockReadFile).toHaveBeenCalledWith(configPath, "utf8")
+			expect(controller.rooIgnoreContent).toBe("secrets.json\ndist/")
+			expect(controller.validateAccess("secrets.json")).toBe(false)
+			expect(controller.validateAccess("dist/index.js")).toBe(false)
+			expect(controller.validateAccess("src/app.ts")).toBe(true)
+		})
+
 		/**
 		 * Tests the file watcher setup
 		 */
@@ -246,6 +263,97 @@ describe("RooIgnoreController", () => {
 		})
 	})
 
+	describe("size and generated-file rules", () => {
+		const files: Record<string, { size: number; header?: string }> = {
+			"src/app.ts": { size: 10 * 1024, header: "export const app = 1\n" },
+			"src/big.ts": { size: 600 * 1024, header: "export const big = 1\n" },
+			"docs/guide.md": { size: 100 * 1024, header: "# Guide\n" },
+			"src/api.gen.ts": { size: 1024, header: "// Code generated by protoc. DO NOT EDIT.\n" },
+			"dist/app.js": { size: 50 * 1024, header: "var a=1;".repeat(200) },
+			"package-lock.json": { size: 1024, header: "{\n" },
+			src: { size: 4096 },
+		}
+
+		beforeEach(() => {
+			mockFileExists.mockResolvedValue(true)
+
+			vi.mocked(fsSync.statSync).mockImplementation(((filePath: string) => {
+				const file = files[path.relative(TEST_CWD, filePath)]
+				if (!file) {
+					throw new Error("ENOENT")
+				}
+				return { size: file.size, mtimeMs: 1, isFile: () => file.header !== undefined }
+			}) as any)
+
+			// File descriptors are the index of the file
+			const names = Object.keys(files)
+			vi.mocked(fsSync.openSync).mockImplementation((filePath) =>
+				names.indexOf(path.relative(TEST_CWD, filePath.toString())),
+			)
+			vi.mocked(fsSync.readSync).mockImplementation(((fd: number, buffer: Buffer) =>
+				Buffer.from(files[names[fd]].header ?? "").copy(buffer)) as any)
+		})
+
+		it("should ignore files larger than @max-size", async () => {
+			mockReadFile.mockResolvedValue("node_modules\n@max-size 500KB")
+			await controller.initialize()
+
+			expect(controller.validateAccess("src/big.ts")).toBe(false)
+			expect(controller.validateAccess("src/app.ts")).toBe(true)
+			expect(controller.validateAccess("src")).toBe(true)
+			expect(controller.validateAccess("node_modules/package.json")).toBe(false)
+		})
+
+		it("should only apply scoped @token-budget rules to matching files", async () => {
+			mockReadFile.mockResolvedValue("@token-budget 20000 docs/")
+			await controller.initialize()
+
+			// 100KB is estimated at ~25,600 tokens
+			expect(controller.validateAccess("docs/guide.md")).toBe(false)
+			expect(controller.validateAccess("src/big.ts")).toBe(true)
+		})
+
+		it("should ignore lockfiles, minified and generated files with @ignore-generated", async () => {
+			mockReadFile.mockResolvedValue("@ignore-generated")
+			await controller.initialize()
+
+			expect(controller.validateAccess("package-lock.json")).toBe(false)
+			expect(controller.validateAccess("src/api.gen.ts")).toBe(false)
+			expect(controller.validateAccess("dist/app.js")).toBe(false)
+			expect(controller.validateAccess("src/app.ts")).toBe(true)
+		})
+
+		it("should skip invalid rules", async () => {
+			const consoleSpy = vi.spyOn(console, "warn").mockImplementation(() => {})
+			mockReadFile.mockResolvedValue("@max-size lots\n@token-budget 1.5k")
+			await controller.initialize()
+
+			expect(consoleSpy).toHaveBeenCalledTimes(2)
+			expect(controller.validateAccess("src/big.ts")).toBe(true)
+			consoleSpy.mockRestore()
+		})
+
+		it("should mention the rules in the instructions", async () => {
+			mockReadFile.mockResolvedValue("@ignore-generated")
+			await controller.initialize()
+
+			expect(controller.getInstructions()).toContain(
+				"Lines starting with @max-size, @token-budget or @ignore-generated",
+			)
+		})
+
+		it("should keep other lines starting with @ as path patterns", async () => {
+			mockReadFile.mockResolvedValue("@myorg/
+@types/node/")
+			await controller.initialize()
+
+			expect(controller.validateAccess("@myorg/secrets.ts")).toBe(false)
+			expect(controller.validateAccess("packages/@myorg/app.ts")).toBe(false)
+			expect(controller.validateAccess("@types/node/index.d.ts")).toBe(false)
+			expect(controller.validateAccess("src/app.ts")).toBe(true)
+		})
+	})
+
 	describe("validateCommand", () => {
 		beforeEach(async () => {
 			// Setup .rooignore content
@@ -17,6 +17,7 @@ import { FileContextTracker } from "../context-tracking/FileContextTracker"
 
 import { RooIgnoreController } from "../ignore/RooIgnoreController"
 import { getCommand, type Command } from "../../services/command/commands"
+import { expandCommandInvocations } from "../../services/command/arguments"
 import { buildSkillResult, resolveSkillContentForMode, type SkillLookup } from "../../services/skills/skillInvocation"
 import type { SkillContent } from "../../shared/skills"
 
@@ -150,10 +151,24 @@ export async function parseMentions(
 		}
 	}
 
-	// Only replace text for commands that actually exist (keep "see below" for commands)
+	// Commands with arguments expand in place, taking the rest of their line as
+	// the arguments, so that the mentions in their prompt are resolved below.
 	let parsedText = text
+	const missingArguments = new Map<string, string[]>()
+	for (const command of validCommands.values()) {
+		if (command.arguments) {
+			const { prompt, missing } = expandCommandInvocations(parsedText, command)
+			parsedText = prompt
+			if (missing.length > 0) {
+				missingArguments.set(command.name, missing)
+			}
+		}
+	}
+
+	// Only replace text for commands that actually exist (keep "see below" for commands)
 	for (const [match, commandName] of commandMatches) {
-		if (validCommands.has(commandName) || validSkills.has(commandName)) {
+		const command = validCommands.get(commandName)
+		if ((command && !command.arguments) || validSkills.has(commandName)) {
 			parsedText = parsedText.replace(match, `Command '${commandName}' (see below for command content)`)
 		}
 	}
@@ -234,6 +249,14 @@ export async function parseMentions(
 	// Process valid command mentions using cached results
 	let slashCommandHelp = ""
 	for (const [commandName, command] of validCommands) {
+		if (command.arguments) {
+			const missing = missingArguments.get(commandName)
+			if (missing) {
+				slashCommandHelp += `\n\n<command name="${commandName}">\nMissing arguments: ${missing.join(", ")}. Ask the user for them before you continue.\n</command>`
+			}
+			continue
+		}
+
 		try {
 			let commandOutput = ""
 			if (command.description) {
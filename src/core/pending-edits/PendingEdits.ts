@@ -0,0 +1,121 @@
+import fs from "fs/promises"
+
+import { createDirectoriesForFile, fileExistsAtPath } from "../../utils/fs"
+
+export interface PendingEdit {
+	relPath: string
+	absolutePath: string
+	// The content on disk before the first staged edit, or undefined for files the edits create
+	originalContent?: string
+	newContent: string
+}
+
+export interface PendingEditsApplyResult {
+	written: PendingEdit[]
+	error?: { relPath: string; message: string; unrestored: string[] }
+}
+
+/**
+ * File edits staged by the editing tools in dry-run mode. Nothing is written
+ * until the edits are applied, so later edits and reads of a staged file build
+ * on its staged content instead of the content on disk.
+ */
+export class PendingEdits {
+	private edits = new Map<string, PendingEdit>()
+
+	public get size(): number {
+		return this.edits.size
+	}
+
+	public list(): PendingEdit[] {
+		return [...this.edits.values()]
+	}
+
+	public get(absolutePath: string): PendingEdit | undefined {
+		return this.edits.get(absolutePath)
+	}
+
+	public async exists(absolutePath: string): Promise<boolean> {
+		return this.edits.has(absolutePath) || (await fileExistsAtPath(absolutePath))
+	}
+
+	/**
+	 * Returns the staged content of the file, or its content on disk if it has no staged edits.
+	 */
+	public async readFile(absolutePath: string): Promise<string> {
+		return this.edits.get(absolutePath)?.newContent ?? (await fs.readFile(absolutePath, "utf-8"))
+	}
+
+	public async stage(relPath: string, absolutePath: string, newContent: string): Promise<PendingEdit> {
+		const existing = this.edits.get(absolutePath)
+
+		if (existing) {
+			existing.newContent = newContent
+			return existing
+		}
+
+		const fileExists = await fileExistsAtPath(absolutePath)
+		const originalContent = fileExists ? await fs.readFile(absolutePath, "utf-8") : undefined
+		const edit: PendingEdit = { relPath, absolutePath, originalContent, newContent }
+
+		this.edits.set(absolutePath, edit)
+
+		return edit
+	}
+
+	public discard(): PendingEdit[] {
+		const discarded = this.list()
+		this.edits.clear()
+		return discarded
+	}
+
+	/**
+	 * Writes every staged edit. If a write fails, the files written so far are
+	 * restored, so either all of the edits are applied or none of them are.
+	 * The staged edits are cleared either way.
+	 */
+	public async apply(): Promise<PendingEditsApplyResult> {
+		const edits = this.discard()
+		const written: PendingEdit[] = []
+
+		try {
+			for (const edit of edits) {
+				// Recorded before writing, since a failed write may still have changed the file.
+				written.push(edit)
+
+				if (edit.originalContent === undefined) {
+					await createDirectoriesForFile(edit.absolutePath)
+				}
+
+				await fs.writeFile(edit.absolutePath, edit.newContent, "utf-8")
+			}
+		} catch (error) {
+			const failed = written[written.length - 1]
+			const unrestored: string[] = []
+
+			for (const edit of written) {
+				try {
+					if (edit.originalContent === undefined) {
+						await fs.rm(edit.absolutePath, { force: true })
+					} else {
+						await fs.writeFile(edit.absolutePath, edit.originalContent, "utf-8")
+					}
+				} catch (restoreError) {
+					console.error(`[PendingEdits] Failed to restore ${edit.relPath}:`, restoreError)
+					unrestored.push(edit.relPath)
+				}
+			}
+
+			return {
+				written: [],
+				error: {
+					relPath: failed.relPath,
+					message: error instanceof Error ? error.message : String(error),
+					unrestored,
+				},
+			}
+		}
+
+		return { written }
+	}
+}
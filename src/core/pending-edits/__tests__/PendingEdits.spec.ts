@@ -0,0 +1,100 @@
+// npx vitest src/core/pending-edits/__tests__/PendingEdits.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+
+import { PendingEdits } from "../PendingEdits"
+import { PENDING_EDITS_REVIEW_TOOLS } from "../review"
+
+describe("PendingEdits", () => {
+	let cwd: string
+	let pendingEdits: PendingEdits
+
+	const resolve = (relPath: string) => path.join(cwd, relPath)
+	const readFile = (relPath: string) => fs.readFile(resolve(relPath), "utf-8")
+
+	beforeEach(async () => {
+		cwd = await fs.mkdtemp(path.join(os.tmpdir(), "pending-edits-"))
+		await fs.writeFile(resolve("a.ts"), "const a = 1\n")
+		pendingEdits = new PendingEdits()
+	})
+
+	afterEach(async () => {
+		vi.restoreAllMocks()
+		await fs.rm(cwd, { recursive: true, force: true })
+	})
+
+	it("should read and report staged files without writing them", async () => {
+		await pendingEdits.stage("a.ts", resolve("a.ts"), "const a = 2\n")
+		await pendingEdits.stage("src/b.ts", resolve("src/b.ts"), "const b = 1\n")
+
+		expect(await pendingEdits.readFile(resolve("a.ts"))).toBe("const a = 2\n")
+		expect(await pendingEdits.exists(resolve("src/b.ts"))).toBe(true)
+		expect(await pendingEdits.exists(resolve("c.ts"))).toBe(false)
+		expect(await readFile("a.ts")).toBe("const a = 1\n")
+		expect(await fs.stat(resolve("src")).catch(() => undefined)).toBeUndefined()
+	})
+
+	it("should keep the content on disk before the first staged edit", async () => {
+		await pendingEdits.stage("a.ts", resolve("a.ts"), "const a = 2\n")
+		await pendingEdits.stage("a.ts", resolve("a.ts"), "const a = 3\n")
+
+		expect(pendingEdits.size).toBe(1)
+		expect(pendingEdits.get(resolve("a.ts"))).toMatchObject({
+			originalContent: "const a = 1\n",
+			newContent: "const a = 3\n",
+		})
+	})
+
+	it("should write every staged edit when applied", async () => {
+		await pendingEdits.stage("a.ts", resolve("a.ts"), "const a = 2\n")
+		await pendingEdits.stage("src/b.ts", resolve("src/b.ts"), "const b = 1\n")
+
+		const { written, error } = await pendingEdits.apply()
+
+		expect(error).toBeUndefined()
+		expect(written.map(({ relPath }) => relPath)).toEqual(["a.ts", "src/b.ts"])
+		expect(await readFile("a.ts")).toBe("const a = 2\n")
+		expect(await readFile("src/b.ts")).toBe("const b = 1\n")
+		expect(pendingEdits.size).toBe(0)
+	})
+
+	it("should restore the files written so far when a write fails", async () => {
+		await pendingEdits.stage("a.ts", resolve("a.ts"), "const a = 2\n")
+		await pendingEdits.stage("src/b.ts", resolve("src/b.ts"), "const b = 1\n")
+		await pendingEdits.stage("c.ts", resolve("c.ts"), "const c = 1\n")
+
+		const writeFile = fs.writeFile
+		vi.spyOn(fs, "writeFile").mockImplementation(async (file, data, options) => {
+			if (String(file).endsWith("c.ts")) {
+				throw new Error("disk full")
+			}
+
+			return writeFile(file, data, options)
+		})
+
+		const { written, error } = await pendingEdits.apply()
+
+		expect(written).toEqual([])
+		expect(error).toEqual({ relPath: "c.ts", message: "disk full", unrestored: [] })
+		expect(await readFile("a.ts")).toBe("const a = 1\n")
+		expect(await pendingEdits.exists(resolve("src/b.ts"))).toBe(false)
+		expect(pendingEdits.size).toBe(0)
+	})
+
+	it("should drop the staged edits when discarded", async () => {
+		await pendingEdits.stage("a.ts", resolve("a.ts"), "const a = 2\n")
+
+		expect(pendingEdits.discard().map(({ relPath }) => relPath)).toEqual(["a.ts"])
+		expect(pendingEdits.size).toBe(0)
+		expect(await pendingEdits.readFile(resolve("a.ts"))).toBe("const a = 1\n")
+	})
+})
+
+describe("PENDING_EDITS_REVIEW_TOOLS", () => {
+	it("should review the staged edits before subtasks start", () => {
+		expect(PENDING_EDITS_REVIEW_TOOLS.has("new_task")).toBe(true)
+		expect(PENDING_EDITS_REVIEW_TOOLS.has("run_parallel_tasks")).toBe(true)
+	})
+})
@@ -0,0 +1,138 @@
+import path from "path"
+
+import type { ClineSayTool } from "@roo-code/types"
+
+import { Task } from "../task/Task"
+import { formatResponse } from "../prompts/responses"
+import { RecordSource } from "../context-tracking/FileContextTrackerTypes"
+import { convertNewFileToUnifiedDiff, computeDiffStats, sanitizeUnifiedDiff } from "../diff/stats"
+import { getReadablePath } from "../../utils/path"
+import { isPathOutsideWorkspace } from "../../utils/pathUtils"
+import { EXPERIMENT_IDS, experiments } from "../../shared/experiments"
+
+/**
+ * Tools that act on the files on disk, so the staged edits are reviewed before
+ * they run. The editing tools that can't stage their edits are included so
+ * they don't edit a file with staged changes.
+ */
+export const PENDING_EDITS_REVIEW_TOOLS: ReadonlySet<string> = new Set([
+	"attempt_completion",
+	"execute_command",
+	"background_process",
+	"run_tests",
+	"git",
+	"new_task",
+	"run_parallel_tasks",
+	"search_replace",
+	"edit_file",
+	"apply_patch",
+])
+
+export interface PendingEditsReview {
+	approved: boolean
+	// The tool result when the edits were not applied, or the user's feedback when they were
+	result?: string
+	images?: string[]
+}
+
+export async function isDryRunEnabled(task: Task): Promise<boolean> {
+	const state = await task.providerRef.deref()?.getState()
+	return experiments.isEnabled(state?.experiments ?? {}, EXPERIMENT_IDS.DRY_RUN_EDITS)
+}
+
+function createUnifiedDiff(relPath: string, originalContent: string | undefined, newContent: string): string {
+	return sanitizeUnifiedDiff(
+		originalContent === undefined
+			? convertNewFileToUnifiedDiff(newContent, relPath)
+			: formatResponse.createPrettyPatch(relPath, originalContent, newContent),
+	)
+}
+
+/**
+ * Stages an edit instead of writing it and shows its diff. Returns the tool result.
+ */
+export async function stagePendingEdit(task: Task, relPath: string, newContent: string): Promise<string> {
+	const absolutePath = path.resolve(task.cwd, relPath)
+	const previousContent = (await task.pendingEdits.exists(absolutePath))
+		? await task.pendingEdits.readFile(absolutePath)
+		: undefined
+
+	await task.pendingEdits.stage(relPath, absolutePath, newContent)
+
+	const unified = createUnifiedDiff(relPath, previousContent, newContent)
+
+	await task.say(
+		"tool",
+		JSON.stringify({
+			tool: "stagedChange",
+			path: getReadablePath(task.cwd, relPath),
+			content: unified,
+			diffStats: computeDiffStats(unified) || undefined,
+			isOutsideWorkspace: isPathOutsideWorkspace(absolutePath),
+		} satisfies ClineSayTool),
+	)
+
+	task.consecutiveMistakeCount = 0
+
+	return `The changes to ${relPath} were staged but not written to disk. Further edits and read_file already see the staged content. The user reviews all staged changes at once before the next command, subtask or attempt_completion.`
+}
+
+/**
+ * Asks the user to approve all staged edits as a batch, then writes them or
+ * discards them.
+ */
+export async function reviewPendingEdits(task: Task): Promise<PendingEditsReview> {
+	const edits = task.pendingEdits.list()
+	const paths = edits.map(({ relPath }) => relPath).join(", ")
+	const isProtected = edits.some(({ relPath }) => task.rooProtectedController?.isWriteProtected(relPath))
+
+	const message = JSON.stringify({
+		tool: "pendingChanges",
+		batchDiffs: edits.map(({ relPath, originalContent, newContent }) => {
+			const unified = createUnifiedDiff(relPath, originalContent, newContent)
+
+			return {
+				path: getReadablePath(task.cwd, relPath),
+				changeCount: 1,
+				key: relPath,
+				content: unified,
+				diffStats: computeDiffStats(unified) || undefined,
+			}
+		}),
+		isProtected,
+	} satisfies ClineSayTool)
+
+	const { response, text, images } = await task.ask("tool", message, false, undefined, isProtected)
+
+	if (text) {
+		await task.say("user_feedback", text, images)
+	}
+
+	if (response !== "yesButtonClicked") {
+		task.pendingEdits.discard()
+		const rejection = `The user rejected the staged changes to ${paths}. They were discarded and these files are unchanged, and the tool was not run.`
+		const result = text ? `${rejection}\n\n${formatResponse.toolDeniedWithFeedback(text)}` : rejection
+		return { approved: false, result, images }
+	}
+
+	const { written, error } = await task.pendingEdits.apply()
+
+	if (error) {
+		const restoreStatus =
+			error.unrestored.length === 0
+				? "All files were restored, so no files were changed."
+				: `These files could not be restored: ${error.unrestored.join(", ")}.`
+		const errorMessage = `Failed to write the staged changes to ${error.relPath}: ${error.message}\n\n${restoreStatus} The staged changes were discarded.`
+
+		await task.say("error", errorMessage)
+		return { approved: false, result: formatResponse.toolError(errorMessage) }
+	}
+
+	for (const { relPath } of written) {
+		await task.fileContextTracker.trackFileContext(relPath, "roo_edited" as RecordSource)
+	}
+
+	task.didEditFile = true
+
+	return { approved: true, result: text, images }
+}
@@ -96,6 +96,14 @@ Otherwise, if you have not completed the task and do not need additional informa
 			available_servers: availableServers.length > 0 ? availableServers : [],
 		}),
 
+	duplicateMcpToolResult: (serverName: string, toolName: string) =>
+		JSON.stringify({
+			status: "unchanged",
+			message: "The result is identical to the one of the earlier call with the same arguments, which is still in the conversation above",
+			server: serverName,
+			tool: toolName,
+		}),
+
 	toolResult: (
 		text: string,
 		images?: string[],
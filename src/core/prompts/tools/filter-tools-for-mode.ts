@@ -274,6 +274,7 @@ export function filterNativeToolsForMode(
 		!(codeIndexManager.isFeatureEnabled && codeIndexManager.isFeatureConfigured && codeIndexManager.isInitialized)
 	) {
 		allowedToolNames.delete("codebase_search")
+		allowedToolNames.delete("find_references")
 	}
 
 	// Conditionally exclude update_todo_list if disabled in settings
@@ -286,6 +287,16 @@ export function filterNativeToolsForMode(
 		allowedToolNames.delete("generate_image")
 	}
 
+	// Conditionally exclude fast_edit if experiment is not enabled
+	if (!experiments?.fastApply) {
+		allowedToolNames.delete("fast_edit")
+	}
+
+	// Conditionally exclude run_parallel_tasks if experiment is not enabled
+	if (!experiments?.parallelSubtasks) {
+		allowedToolNames.delete("run_parallel_tasks")
+	}
+
 	// Conditionally exclude run_slash_command if experiment is not enabled
 	if (!experiments?.runSlashCommand) {
 		allowedToolNames.delete("run_slash_command")
@@ -362,7 +373,7 @@ export function isToolAllowedInMode(
 	// Check if it's an always-available tool
 	if (ALWAYS_AVAILABLE_TOOLS.includes(toolName)) {
 		// But still check for conditional exclusions
-		if (toolName === "codebase_search") {
+		if (toolName === "codebase_search" || toolName === "find_references") {
 			return !!(
 				codeIndexManager &&
 				codeIndexManager.isFeatureEnabled &&
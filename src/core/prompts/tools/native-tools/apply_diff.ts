@@ -1,6 +1,8 @@
 import type OpenAI from "openai"
 
-const APPLY_DIFF_DESCRIPTION = `Apply precise, targeted modifications to an existing file using one or more search/replace blocks. This tool is for surgical edits only; the 'SEARCH' block must exactly match the existing content, including whitespace and indentation. To make multiple targeted changes, provide multiple SEARCH/REPLACE blocks in the 'diff' parameter. Use the 'read_file' tool first if you are not confident in the exact content to search for.`
+const APPLY_DIFF_DESCRIPTION = `Apply precise, targeted modifications to an existing file using one or more search/replace blocks. This tool is for surgical edits only; the 'SEARCH' block must exactly match the existing content, including whitespace and indentation. To make multiple targeted changes, provide multiple SEARCH/REPLACE blocks in the 'diff' parameter. Use the 'read_file' tool first if you are not confident in the exact content to search for.
+
+To change several files together, for example in a refactor, list the other files in the 'files' parameter. All files are then edited as a single transaction: if any search block fails to match, or the user rejects the changes, no file is changed, so fix the failing diffs and retry all files together.`
 
 const DIFF_PARAMETER_DESCRIPTION = `A string containing one or more search/replace blocks defining the changes. The ':start_line:' is required and indicates the starting line number of the original content. You must not add a start line for the replacement content. Each block must follow this format:
 <<<<<<< SEARCH
@@ -11,6 +13,8 @@ const DIFF_PARAMETER_DESCRIPTION = `A string containing one or more search/repla
 [new content to replace with]
 >>>>>>> REPLACE`
 
+const FILES_PARAMETER_DESCRIPTION = `Optional additional files to edit in the same transaction as 'path'. Each entry has its own 'path' and 'diff', in the same format as above. Either all files are changed or none.`
+
 export const apply_diff = {
 	type: "function",
 	function: {
@@ -27,6 +31,25 @@ export const apply_diff = {
 					type: "string",
 					description: DIFF_PARAMETER_DESCRIPTION,
 				},
+				files: {
+					type: "array",
+					description: FILES_PARAMETER_DESCRIPTION,
+					items: {
+						type: "object",
+						properties: {
+							path: {
+								type: "string",
+								description: "The path of the file to modify, relative to the current workspace directory.",
+							},
+							diff: {
+								type: "string",
+								description: "The search/replace blocks to apply to this file.",
+							},
+						},
+						required: ["path", "diff"],
+						additionalProperties: false,
+					},
+				},
 			},
 			required: ["path", "diff"],
 			additionalProperties: false,
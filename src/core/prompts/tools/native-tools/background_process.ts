@@ -0,0 +1,65 @@
+import type OpenAI from "openai"
+
+const BACKGROUND_PROCESS_DESCRIPTION = `Start, inspect and stop long-running processes such as dev servers, file watchers and local databases without blocking the task. Use this instead of execute_command for commands that don't exit on their own. The processes are stopped when the task ends; the environment details list them with their status.
+
+Actions:
+- start: Starts the command in the background and returns its first few seconds of output. Requires command; cwd is optional. Starting a process needs the same approval as execute_command.
+- list: Lists the processes started in this task with their ids and status.
+- read_output: Returns the output produced since the output was last read, or the end of the output if there is nothing new. Requires process_id.
+- stop: Stops the process and its child processes. Requires process_id.
+
+Parameters:
+- action: (required) One of start, list, read_output or stop.
+- command: (start) The command to run, e.g. "npm run dev".
+- cwd: (start) The working directory to run the command in. Defaults to the current workspace directory.
+- process_id: (read_output, stop) The id of the process returned by start or list.
+
+Example: Starting a dev server
+{ "action": "start", "command": "npm run dev", "cwd": null, "process_id": null }
+
+Example: Checking the dev server output after editing a file
+{ "action": "read_output", "command": null, "cwd": null, "process_id": 1 }
+
+Example: Stopping the dev server
+{ "action": "stop", "command": null, "cwd": null, "process_id": 1 }`
+
+const ACTION_PARAMETER_DESCRIPTION = `The operation to perform`
+
+const COMMAND_PARAMETER_DESCRIPTION = `Command to run in the background (start)`
+
+const CWD_PARAMETER_DESCRIPTION = `Optional working directory for the command (start)`
+
+const PROCESS_ID_PARAMETER_DESCRIPTION = `Id of the background process (read_output, stop)`
+
+export default {
+	type: "function",
+	function: {
+		name: "background_process",
+		description: BACKGROUND_PROCESS_DESCRIPTION,
+		strict: true,
+		parameters: {
+			type: "object",
+			properties: {
+				action: {
+					type: "string",
+					enum: ["start", "list", "read_output", "stop"],
+					description: ACTION_PARAMETER_DESCRIPTION,
+				},
+				command: {
+					type: ["string", "null"],
+					description: COMMAND_PARAMETER_DESCRIPTION,
+				},
+				cwd: {
+					type: ["string", "null"],
+					description: CWD_PARAMETER_DESCRIPTION,
+				},
+				process_id: {
+					type: ["integer", "null"],
+					description: PROCESS_ID_PARAMETER_DESCRIPTION,
+				},
+			},
+			required: ["action", "command", "cwd", "process_id"],
+			additionalProperties: false,
+		},
+	},
+} satisfies OpenAI.Chat.ChatCompletionTool
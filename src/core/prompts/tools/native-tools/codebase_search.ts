@@ -6,17 +6,26 @@ const CODEBASE_SEARCH_DESCRIPTION = `Find files most relevant to the search quer
 
 Parameters:
 - query: (required) The search query. Reuse the user's exact wording/question format unless there's a clear reason not to.
-- path: (optional) Limit search to specific subdirectory (relative to the current workspace directory). Leave empty for entire workspace.
+- path: (optional) Limit search to specific subdirectory or glob (relative to the current workspace directory), e.g. "src/api" or "src/api/**/*.ts". Leave empty for entire workspace.
+- language: (optional) Only return code in this language, e.g. "go", "typescript" or "python". A file extension such as "ts" also works.
+- kind: (optional) Only return code blocks that define this kind of symbol, e.g. "function", "method", "class", "interface" or "struct".
 
 Example: Searching for user authentication code
-{ "query": "User login and password hashing", "path": "src/auth" }
+{ "query": "User login and password hashing", "path": "src/auth", "language": null, "kind": null }
+
+Example: Searching for Go request handlers in the API layer
+{ "query": "HTTP request handler validation", "path": "src/api/**", "language": "go", "kind": "function" }
 
 Example: Searching entire workspace
-{ "query": "database connection pooling", "path": null }`
+{ "query": "database connection pooling", "path": null, "language": null, "kind": null }`
 
 const QUERY_PARAMETER_DESCRIPTION = `Meaning-based search query describing the information you need`
 
-const PATH_PARAMETER_DESCRIPTION = `Optional subdirectory (relative to the workspace) to limit the search scope`
+const PATH_PARAMETER_DESCRIPTION = `Optional subdirectory or glob (relative to the workspace) to limit the search scope`
+
+const LANGUAGE_PARAMETER_DESCRIPTION = `Optional language (e.g. "go", "typescript") to limit results to`
+
+const KIND_PARAMETER_DESCRIPTION = `Optional definition kind (e.g. "function", "method", "class") to limit results to`
 
 export default {
 	type: "function",
@@ -35,8 +44,16 @@ export default {
 					type: ["string", "null"],
 					description: PATH_PARAMETER_DESCRIPTION,
 				},
+				language: {
+					type: ["string", "null"],
+					description: LANGUAGE_PARAMETER_DESCRIPTION,
+				},
+				kind: {
+					type: ["string", "null"],
+					description: KIND_PARAMETER_DESCRIPTION,
+				},
 			},
-			required: ["query", "path"],
+			required: ["query", "path", "language", "kind"],
 			additionalProperties: false,
 		},
 	},
@@ -0,0 +1,66 @@
+import type OpenAI from "openai"
+
+const EDIT_NOTEBOOK_DESCRIPTION = `Edit a single cell of a Jupyter notebook (.ipynb). Always use this tool instead of apply_diff, edit or write_to_file for notebooks: the other tools edit the notebook's raw JSON, which easily corrupts it. Outputs, metadata and all other cells are kept.
+
+read_file shows a notebook as numbered cells, e.g. <cell index="2" type="code">. Cell indices are 0-based and shift after an insert or delete, so read the notebook again before further edits by index.
+
+Parameters:
+- path: (required) The path of the notebook to edit (relative to the current workspace directory)
+- cell_index: (required) The 0-based index of the cell to replace or delete. For insert, the new cell is placed at this index, before the cell there; use the number of cells to append.
+- new_source: (required) The complete new source of the cell, without the <cell> tags. Use null for delete.
+- cell_type: (required) "code", "markdown" or "raw". For insert it sets the new cell's type (default "code"); for replace it changes the cell's type, or use null to keep it.
+- edit_mode: (required) "replace" to change a cell's source, "insert" to add a new cell, or "delete" to remove a cell.
+
+Example: Replacing the source of cell 3
+{ "path": "analysis.ipynb", "cell_index": 3, "new_source": "df = pd.read_csv(\\"data.csv\\")\\ndf.describe()", "cell_type": null, "edit_mode": "replace" }
+
+Example: Adding a markdown cell at the start
+{ "path": "analysis.ipynb", "cell_index": 0, "new_source": "# Sales analysis", "cell_type": "markdown", "edit_mode": "insert" }`
+
+const PATH_PARAMETER_DESCRIPTION = `The path of the notebook to edit (relative to the current workspace directory)`
+
+const CELL_INDEX_PARAMETER_DESCRIPTION = `The 0-based index of the cell to replace or delete, or where to insert the new cell`
+
+const NEW_SOURCE_PARAMETER_DESCRIPTION = `The complete new source of the cell, or null for delete`
+
+const CELL_TYPE_PARAMETER_DESCRIPTION = `The type of the new cell for insert, or the type to change the cell to for replace; null keeps the type`
+
+const EDIT_MODE_PARAMETER_DESCRIPTION = `Whether to replace, insert or delete the cell`
+
+export default {
+	type: "function",
+	function: {
+		name: "edit_notebook",
+		description: EDIT_NOTEBOOK_DESCRIPTION,
+		strict: true,
+		parameters: {
+			type: "object",
+			properties: {
+				path: {
+					type: "string",
+					description: PATH_PARAMETER_DESCRIPTION,
+				},
+				cell_index: {
+					type: "integer",
+					description: CELL_INDEX_PARAMETER_DESCRIPTION,
+				},
+				new_source: {
+					type: ["string", "null"],
+					description: NEW_SOURCE_PARAMETER_DESCRIPTION,
+				},
+				cell_type: {
+					type: ["string", "null"],
+					enum: ["code", "markdown", "raw", null],
+					description: CELL_TYPE_PARAMETER_DESCRIPTION,
+				},
+				edit_mode: {
+					type: "string",
+					enum: ["replace", "insert", "delete"],
+					description: EDIT_MODE_PARAMETER_DESCRIPTION,
+				},
+			},
+			required: ["path", "cell_index", "new_source", "cell_type", "edit_mode"],
+			additionalProperties: false,
+		},
+	},
+} satisfies OpenAI.Chat.ChatCompletionTool
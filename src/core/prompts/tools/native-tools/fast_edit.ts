@@ -0,0 +1,49 @@
+import type OpenAI from "openai"
+
+const FAST_EDIT_DESCRIPTION = `Edit an existing file by writing only the changed parts. A separate fast-apply model merges your edit into the file, so the unchanged code doesn't have to be repeated or matched exactly.
+
+Write the code you want to change with a little surrounding context, and replace each unchanged section between your changes with a "... existing code ..." comment in the file's comment syntax (e.g. "// ... existing code ..." or "# ... existing code ..."). Never leave out unchanged code without a marker, as the merge may then delete it. To delete code, show the lines around it without it and say so in the instructions.
+
+Prefer this tool over apply_diff for large edits, edits scattered across a file, or files whose whitespace is hard to match exactly. Use write_to_file to create new files.
+
+Parameters:
+- path: (required) The path of the file to edit (relative to the current workspace directory)
+- instructions: (required) A single sentence in the first person describing the edit, used to resolve ambiguity in the merge, e.g. "I am adding a timeout option to fetchUser."
+- code_edit: (required) The changed code, with "... existing code ..." markers for the unchanged parts
+
+Example: Adding a parameter and a check to a function
+{ "path": "src/api.ts", "instructions": "I am adding a timeout option to fetchUser.", "code_edit": "// ... existing code ...\\nexport async function fetchUser(id: string, timeout = 5000) {\\n\\tif (timeout <= 0) {\\n\\t\\tthrow new Error(\\"timeout must be positive\\")\\n\\t}\\n// ... existing code ...\\n\\tconst response = await fetch(url, { signal: AbortSignal.timeout(timeout) })\\n// ... existing code ..." }`
+
+const PATH_PARAMETER_DESCRIPTION = `The path of the file to edit (relative to the current workspace directory)`
+
+const INSTRUCTIONS_PARAMETER_DESCRIPTION = `A single sentence in the first person describing the edit`
+
+const CODE_EDIT_PARAMETER_DESCRIPTION = `The changed code, with "... existing code ..." comments standing for the unchanged parts of the file`
+
+export default {
+	type: "function",
+	function: {
+		name: "fast_edit",
+		description: FAST_EDIT_DESCRIPTION,
+		strict: true,
+		parameters: {
+			type: "object",
+			properties: {
+				path: {
+					type: "string",
+					description: PATH_PARAMETER_DESCRIPTION,
+				},
+				instructions: {
+					type: "string",
+					description: INSTRUCTIONS_PARAMETER_DESCRIPTION,
+				},
+				code_edit: {
+					type: "string",
+					description: CODE_EDIT_PARAMETER_DESCRIPTION,
+				},
+			},
+			required: ["path", "instructions", "code_edit"],
+			additionalProperties: false,
+		},
+	},
+} satisfies OpenAI.Chat.ChatCompletionTool
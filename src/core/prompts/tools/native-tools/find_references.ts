@@ -0,0 +1,43 @@
+import type OpenAI from "openai"
+
+const FIND_REFERENCES_DESCRIPTION = `Find where a function, method, type or interface is defined and where it is used, using the symbol graph built while indexing the codebase. Use this to answer questions like "who calls this function" or "what implements this interface" instead of searching the whole repository with search_files. Results list each definition and each call or other reference with its file, line and the enclosing function.
+
+References are matched by name, not resolved by type, so a common name such as "get" may also match unrelated symbols. Files that have not been (re)indexed since this tool became available have no symbols yet.
+
+Parameters:
+- symbol: (required) The symbol name. Qualify methods with their type or receiver to narrow the definitions, e.g. "Repository.FindByID".
+- path: (optional) Limit results to a specific subdirectory (relative to the current workspace directory). Leave empty for entire workspace.
+
+Example: Finding the callers of a method
+{ "symbol": "Repository.FindByID", "path": null }
+
+Example: Finding uses of an interface within a directory
+{ "symbol": "UserStore", "path": "internal/service" }`
+
+const SYMBOL_PARAMETER_DESCRIPTION = `Name of the function, method, type or interface, optionally qualified (e.g. Repository.FindByID)`
+
+const PATH_PARAMETER_DESCRIPTION = `Optional subdirectory (relative to the workspace) to limit the results`
+
+export default {
+	type: "function",
+	function: {
+		name: "find_references",
+		description: FIND_REFERENCES_DESCRIPTION,
+		strict: true,
+		parameters: {
+			type: "object",
+			properties: {
+				symbol: {
+					type: "string",
+					description: SYMBOL_PARAMETER_DESCRIPTION,
+				},
+				path: {
+					type: ["string", "null"],
+					description: PATH_PARAMETER_DESCRIPTION,
+				},
+			},
+			required: ["symbol", "path"],
+			additionalProperties: false,
+		},
+	},
+} satisfies OpenAI.Chat.ChatCompletionTool
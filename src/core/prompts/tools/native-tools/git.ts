@@ -0,0 +1,86 @@
+import type OpenAI from "openai"
+
+const GIT_DESCRIPTION = `Create a branch, commit changes or open a pull request. Each action is shown to the user as the git commands it runs and needs their approval, like execute_command. Prefer this over execute_command for these git operations.
+
+Actions:
+- create_branch: Creates the branch from the current HEAD and switches to it. Requires branch.
+- commit: Stages the given files and commits only them with the message. Other staged changes are left staged. Requires files and message.
+- create_pull_request: Pushes the current branch to its remote and opens a pull request (GitHub, using the gh CLI) or merge request (GitLab, using the glab CLI). Requires title; body and base are optional. Commit the changes on their own branch first.
+
+Parameters:
+- action: (required) One of create_branch, commit or create_pull_request.
+- branch: (create_branch) The name of the new branch, e.g. "fix/login-redirect".
+- files: (commit) The paths to commit, relative to the current workspace directory. Deleted files can be included.
+- message: (commit) The commit message: a short summary line, optionally followed by a blank line and a description.
+- title: (create_pull_request) The title of the pull request.
+- body: (create_pull_request) The description of the pull request in markdown.
+- base: (create_pull_request) The branch to merge into. Defaults to the default branch of the repository.
+
+Example: Creating a branch
+{ "action": "create_branch", "branch": "fix/login-redirect", "files": null, "message": null, "title": null, "body": null, "base": null }
+
+Example: Committing two files
+{ "action": "commit", "branch": null, "files": ["src/auth/login.ts", "src/auth/__tests__/login.spec.ts"], "message": "Redirect to the requested page after login", "title": null, "body": null, "base": null }
+
+Example: Opening a pull request
+{ "action": "create_pull_request", "branch": null, "files": null, "message": null, "title": "Redirect to the requested page after login", "body": "Users were always sent to the dashboard after logging in.", "base": "main" }`
+
+const ACTION_PARAMETER_DESCRIPTION = `The git operation to perform`
+
+const BRANCH_PARAMETER_DESCRIPTION = `Name of the branch to create (create_branch)`
+
+const FILES_PARAMETER_DESCRIPTION = `Paths (relative to the workspace) to stage and commit (commit)`
+
+const MESSAGE_PARAMETER_DESCRIPTION = `Commit message (commit)`
+
+const TITLE_PARAMETER_DESCRIPTION = `Pull request title (create_pull_request)`
+
+const BODY_PARAMETER_DESCRIPTION = `Optional pull request description in markdown (create_pull_request)`
+
+const BASE_PARAMETER_DESCRIPTION = `Optional branch to merge into (create_pull_request)`
+
+export default {
+	type: "function",
+	function: {
+		name: "git",
+		description: GIT_DESCRIPTION,
+		strict: true,
+		parameters: {
+			type: "object",
+			properties: {
+				action: {
+					type: "string",
+					enum: ["create_branch", "commit", "create_pull_request"],
+					description: ACTION_PARAMETER_DESCRIPTION,
+				},
+				branch: {
+					type: ["string", "null"],
+					description: BRANCH_PARAMETER_DESCRIPTION,
+				},
+				files: {
+					type: ["array", "null"],
+					items: { type: "string" },
+					description: FILES_PARAMETER_DESCRIPTION,
+				},
+				message: {
+					type: ["string", "null"],
+					description: MESSAGE_PARAMETER_DESCRIPTION,
+				},
+				title: {
+					type: ["string", "null"],
+					description: TITLE_PARAMETER_DESCRIPTION,
+				},
+				body: {
+					type: ["string", "null"],
+					description: BODY_PARAMETER_DESCRIPTION,
+				},
+				base: {
+					type: ["string", "null"],
+					description: BASE_PARAMETER_DESCRIPTION,
+				},
+			},
+			required: ["action", "branch", "files", "message", "title", "body", "base"],
+			additionalProperties: false,
+		},
+	},
+} satisfies OpenAI.Chat.ChatCompletionTool
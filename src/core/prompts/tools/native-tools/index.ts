@@ -4,15 +4,22 @@ import { apply_diff } from "./apply_diff"
 import applyPatch from "./apply_patch"
 import askFollowupQuestion from "./ask_followup_question"
 import attemptCompletion from "./attempt_completion"
+import backgroundProcess from "./background_process"
 import codebaseSearch from "./codebase_search"
 import editTool from "./edit"
+import editNotebook from "./edit_notebook"
 import executeCommand from "./execute_command"
+import fastEdit from "./fast_edit"
+import findReferences from "./find_references"
 import generateImage from "./generate_image"
+import git from "./git"
 import listFiles from "./list_files"
 import newTask from "./new_task"
 import readCommandOutput from "./read_command_output"
 import { createReadFileTool, type ReadFileToolOptions } from "./read_file"
+import runParallelTasks from "./run_parallel_tasks"
 import runSlashCommand from "./run_slash_command"
+import runTests from "./run_tests"
 import skill from "./skill"
 import searchReplace from "./search_replace"
 import edit_file from "./edit_file"
@@ -52,14 +59,21 @@ export function getNativeTools(options: NativeToolsOptions = {}): OpenAI.Chat.Ch
 		applyPatch,
 		askFollowupQuestion,
 		attemptCompletion,
+		backgroundProcess,
 		codebaseSearch,
+		editNotebook,
 		executeCommand,
+		fastEdit,
+		findReferences,
 		generateImage,
+		git,
 		listFiles,
 		newTask,
 		readCommandOutput,
 		createReadFileTool(readFileOptions),
+		runParallelTasks,
 		runSlashCommand,
+		runTests,
 		skill,
 		searchReplace,
 		edit_file,
@@ -0,0 +1,55 @@
+import type OpenAI from "openai"
+
+const RUN_PARALLEL_TASKS_DESCRIPTION = `Run several independent subtasks at the same time, each in its own mode with its own conversation, and wait for all of them to finish. Returns the completion result of every subtask, or why it failed. Use this instead of new_task when the subtasks don't depend on each other's results, e.g. researching separate parts of the codebase or changing unrelated files.
+
+Subtasks run in the background:
+- Each subtask only sees its message, so include all the context it needs.
+- Subtasks can only use actions that are auto-approved. Actions that need approval are declined, and a subtask that needs the user for anything else stops.
+- Subtasks can't create subtasks or switch modes.
+- Subtasks must not edit the same files.
+
+CRITICAL: This tool MUST be called alone. Do NOT call this tool alongside other tools in the same message turn.
+
+Example: Researching two areas at once
+{ "tasks": [{ "mode": "ask", "message": "Explain how authentication works in src/auth and list its entry points." }, { "mode": "ask", "message": "Summarize the database schema defined in src/db/schema.ts." }] }`
+
+const TASKS_PARAMETER_DESCRIPTION = `The subtasks to run, at least two`
+
+const MODE_PARAMETER_DESCRIPTION = `Slug of the mode to run the subtask in (e.g., code, debug, architect)`
+
+const MESSAGE_PARAMETER_DESCRIPTION = `Complete instructions and context for the subtask`
+
+export default {
+	type: "function",
+	function: {
+		name: "run_parallel_tasks",
+		description: RUN_PARALLEL_TASKS_DESCRIPTION,
+		strict: true,
+		parameters: {
+			type: "object",
+			properties: {
+				tasks: {
+					type: "array",
+					description: TASKS_PARAMETER_DESCRIPTION,
+					items: {
+						type: "object",
+						properties: {
+							mode: {
+								type: "string",
+								description: MODE_PARAMETER_DESCRIPTION,
+							},
+							message: {
+								type: "string",
+								description: MESSAGE_PARAMETER_DESCRIPTION,
+							},
+						},
+						required: ["mode", "message"],
+						additionalProperties: false,
+					},
+				},
+			},
+			required: ["tasks"],
+			additionalProperties: false,
+		},
+	},
+} satisfies OpenAI.Chat.ChatCompletionTool
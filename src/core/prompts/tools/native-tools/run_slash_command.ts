@@ -4,7 +4,7 @@ const RUN_SLASH_COMMAND_DESCRIPTION = `Execute a slash command to get specific i
 
 const COMMAND_PARAMETER_DESCRIPTION = `Name of the slash command to run (e.g., init, test, deploy)`
 
-const ARGS_PARAMETER_DESCRIPTION = `Optional additional context or arguments for the command`
+const ARGS_PARAMETER_DESCRIPTION = `Optional additional context or arguments for the command. For a command with named arguments (see its argument hint), give them in order or as name=value, quoting values with spaces`
 
 export default {
 	type: "function",
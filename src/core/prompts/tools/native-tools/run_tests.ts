@@ -0,0 +1,46 @@
+import type OpenAI from "openai"
+
+const RUN_TESTS_DESCRIPTION = `Run the project's tests and get back only the failing tests, each with its file, line, test name and failure message. Prefer this over execute_command for running tests: the raw output of a test run is long, and this tool keeps it out of your context.
+
+The test command is detected from the workspace: vitest or jest (package.json), go test (go.mod), cargo test (Cargo.toml), pytest (pytest.ini, conftest.py, pyproject.toml, setup.cfg or tox.ini), or the package.json test script. Failures of the package.json test script can't be parsed, so the tool then returns the output instead.
+
+Parameters:
+- path: (optional) A test file or directory, relative to the current workspace directory, to run only its tests. Ignored for cargo test and custom commands.
+- command: (optional) A test command to run instead of the detected one, e.g. to pass extra flags. Commands that run vitest, jest, go test or pytest are still parsed.
+
+Example: Running all tests
+{ "path": null, "command": null }
+
+Example: Running the tests of a single file
+{ "path": "src/utils/__tests__/math.spec.ts", "command": null }
+
+Example: Running a single Go test
+{ "path": null, "command": "go test ./internal/store -run TestFindByID" }`
+
+const PATH_PARAMETER_DESCRIPTION = `Optional test file or directory (relative to the workspace) to limit the run to`
+
+const COMMAND_PARAMETER_DESCRIPTION = `Optional test command to run instead of the detected one`
+
+export default {
+	type: "function",
+	function: {
+		name: "run_tests",
+		description: RUN_TESTS_DESCRIPTION,
+		strict: true,
+		parameters: {
+			type: "object",
+			properties: {
+				path: {
+					type: ["string", "null"],
+					description: PATH_PARAMETER_DESCRIPTION,
+				},
+				command: {
+					type: ["string", "null"],
+					description: COMMAND_PARAMETER_DESCRIPTION,
+				},
+			},
+			required: ["path", "command"],
+			additionalProperties: false,
+		},
+	},
+} satisfies OpenAI.Chat.ChatCompletionTool
@@ -0,0 +1,136 @@
+import type { ClineMessage, HistoryItem, TaskSearchResult } from "@roo-code/types"
+
+import { type ApiMessage, readApiMessages } from "./apiMessages"
+import { readTaskMessages } from "./taskMessages"
+
+/** How much of the tasks' text to keep in memory between searches. */
+const MAX_CACHED_CHARS = 50_000_000
+
+/** The number of characters around the match shown in a result. */
+const SNIPPET_CONTEXT_CHARS = 60
+
+/**
+ * The text of a task that full-text search looks at: the messages shown in
+ * the chat, plus the tool results, which were only sent to the model.
+ */
+export function getSearchableText(messages: ClineMessage[], apiMessages: ApiMessage[]): string {
+	const parts = messages.map((message) => message.text ?? "")
+
+	for (const message of apiMessages) {
+		if (message.role !== "user" || !Array.isArray(message.content)) {
+			continue
+		}
+
+		for (const block of message.content) {
+			if (block.type !== "tool_result") {
+				continue
+			}
+
+			if (typeof block.content === "string") {
+				parts.push(block.content)
+			} else {
+				for (const item of block.content ?? []) {
+					if (item.type === "text") {
+						parts.push(item.text)
+					}
+				}
+			}
+		}
+	}
+
+	return parts.filter(Boolean).join("\n")
+}
+
+/**
+ * If the text contains every word of the query, ignoring case, returns the
+ * excerpt around the first match of the first word.
+ */
+export function matchSearchableText(text: string, query: string): string | undefined {
+	const terms = query.toLowerCase().split(/\s+/).filter(Boolean)
+	const lowerText = text.toLowerCase()
+
+	if (terms.length === 0 || !terms.every((term) => lowerText.includes(term))) {
+		return undefined
+	}
+
+	const index = lowerText.indexOf(terms[0])
+	const start = Math.max(0, index - SNIPPET_CONTEXT_CHARS)
+	const end = Math.min(text.length, index + terms[0].length + SNIPPET_CONTEXT_CHARS)
+	const excerpt = text.slice(start, end).replace(/\s+/g, " ").trim()
+
+	return `${start > 0 ? "…" : ""}${excerpt}${end < text.length ? "…" : ""}`
+}
+
+/**
+ * Full-text search over the conversations of the tasks in the history.
+ *
+ * The text of each task is read from its task directory the first time it's
+ * searched, and kept in memory until the task changes, so searching again
+ * as the user types doesn't read every task from disk. The oldest entries
+ * are evicted once the cache grows past MAX_CACHED_CHARS.
+ */
+export class TaskSearchIndex {
+	private cache = new Map<string, { ts: number; text: string }>()
+	private cachedChars = 0
+
+	constructor(private readonly globalStoragePath: string) {}
+
+	async search(items: HistoryItem[], query: string): Promise<TaskSearchResult[]> {
+		const results: TaskSearchResult[] = []
+
+		if (!query.trim()) {
+			return results
+		}
+
+		for (const item of items) {
+			const snippet = matchSearchableText(await this.getText(item), query)
+
+			if (snippet) {
+				results.push({ taskId: item.id, snippet })
+			}
+		}
+
+		return results
+	}
+
+	private async getText(item: HistoryItem): Promise<string> {
+		const cached = this.cache.get(item.id)
+
+		// The history item's timestamp moves with each change to the task.
+		if (cached?.ts === item.ts) {
+			return cached.text
+		}
+
+		const options = { taskId: item.id, globalStoragePath: this.globalStoragePath }
+		const [messages, apiMessages] = await Promise.all([readTaskMessages(options), readApiMessages(options)])
+		const text = getSearchableText(messages, apiMessages)
+
+		this.delete(item.id)
+		this.cache.set(item.id, { ts: item.ts, text })
+		this.cachedChars += text.length
+
+		for (const [taskId] of this.cache) {
+			if (this.cachedChars <= MAX_CACHED_CHARS || taskId === item.id) {
+				break
+			}
+
+			this.delete(taskId)
+		}
+
+		return text
+	}
+
+	private delete(taskId: string) {
+		const cached = this.cache.get(taskId)
+
+		if (cached) {
+			this.cachedChars -= cached.text.length
+			this.cache.delete(taskId)
+		}
+	}
+
+	clear(): void {
+		this.cache.clear()
+		this.cachedChars = 0
+	}
+}
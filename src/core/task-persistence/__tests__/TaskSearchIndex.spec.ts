@@ -0,0 +1,118 @@
+// cd src && npx vitest run core/task-persistence/__tests__/TaskSearchIndex.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import * as fs from "fs/promises"
+
+import type { HistoryItem } from "@roo-code/types"
+
+import { TaskSearchIndex, getSearchableText, matchSearchableText } from "../TaskSearchIndex"
+
+let tmpBaseDir: string
+
+const writeTask = async (taskId: string, uiMessages: unknown[], apiMessages: unknown[] = []) => {
+	const taskDir = path.join(tmpBaseDir, "tasks", taskId)
+	await fs.mkdir(taskDir, { recursive: true })
+	await fs.writeFile(path.join(taskDir, "ui_messages.json"), JSON.stringify(uiMessages))
+	await fs.writeFile(path.join(taskDir, "api_conversation_history.json"), JSON.stringify(apiMessages))
+}
+
+const historyItem = (id: string, ts = 1): HistoryItem => ({
+	id,
+	number: 1,
+	ts,
+	task: `Task ${id}`,
+	tokensIn: 0,
+	tokensOut: 0,
+	totalCost: 0,
+})
+
+beforeEach(async () => {
+	tmpBaseDir = await fs.mkdtemp(path.join(os.tmpdir(), "roo-test-search-"))
+})
+
+afterEach(async () => {
+	await fs.rm(tmpBaseDir, { recursive: true, force: true })
+})
+
+describe("getSearchableText", () => {
+	it("includes the chat messages and the tool results", () => {
+		const text = getSearchableText(
+			[{ ts: 1, type: "say", say: "text", text: "Let me look at the config" }],
+			[
+				{ role: "assistant", content: [{ type: "text", text: "Not included" }] },
+				{
+					role: "user",
+					content: [
+						{ type: "tool_result", tool_use_id: "1", content: "port: 8080" },
+						{ type: "tool_result", tool_use_id: "2", content: [{ type: "text", text: "host: localhost" }] },
+					],
+				},
+			],
+		)
+
+		expect(text).toBe("Let me look at the config\nport: 8080\nhost: localhost")
+	})
+})
+
+describe("matchSearchableText", () => {
+	it("requires every word of the query, ignoring case", () => {
+		expect(matchSearchableText("The Database migration failed", "database failed")).toBeDefined()
+		expect(matchSearchableText("The Database migration failed", "database succeeded")).toBeUndefined()
+		expect(matchSearchableText("Anything", "  ")).toBeUndefined()
+	})
+
+	it("returns the excerpt around the first match", () => {
+		const text = `${"a".repeat(100)} needle\n\nin the   haystack ${"b".repeat(100)}`
+
+		const snippet = matchSearchableText(text, "needle")!
+
+		expect(snippet.startsWith("…")).toBe(true)
+		expect(snippet.endsWith("…")).toBe(true)
+		expect(snippet).toContain("needle in the haystack")
+	})
+})
+
+describe("TaskSearchIndex", () => {
+	it("finds the tasks whose conversation matches", async () => {
+		await writeTask("task-1", [{ ts: 1, type: "say", say: "text", text: "Refactor the parser" }])
+		await writeTask(
+			"task-2",
+			[{ ts: 1, type: "say", say: "text", text: "Read the file" }],
+			[
+				{
+					role: "user",
+					content: [{ type: "tool_result", tool_use_id: "1", content: "function parseArgs() {}" }],
+				},
+			],
+		)
+		await writeTask("task-3", [{ ts: 1, type: "say", say: "text", text: "Update the README" }])
+
+		const index = new TaskSearchIndex(tmpBaseDir)
+		const items = ["task-1", "task-2", "task-3"].map((id) => historyItem(id))
+
+		const results = await index.search(items, "pars")
+
+		expect(results.map(({ taskId }) => taskId)).toEqual(["task-1", "task-2"])
+		expect(results[1].snippet).toBe("function parseArgs() {}")
+	})
+
+	it("reads a task again once it changed", async () => {
+		await writeTask("task-1", [{ ts: 1, type: "say", say: "text", text: "First version" }])
+
+		const index = new TaskSearchIndex(tmpBaseDir)
+		expect(await index.search([historyItem("task-1", 1)], "second")).toEqual([])
+
+		await writeTask("task-1", [{ ts: 1, type: "say", say: "text", text: "Second version" }])
+
+		// Unchanged tasks come from the cache.
+		expect(await index.search([historyItem("task-1", 1)], "second")).toEqual([])
+		expect(await index.search([historyItem("task-1", 2)], "second")).toHaveLength(1)
+	})
+
+	it("returns nothing for an empty query", async () => {
+		await writeTask("task-1", [{ ts: 1, type: "say", say: "text", text: "Anything" }])
+
+		expect(await new TaskSearchIndex(tmpBaseDir).search([historyItem("task-1")], "")).toEqual([])
+	})
+})
@@ -2,3 +2,4 @@ export { type ApiMessage, readApiMessages, saveApiMessages } from "./apiMessages
 export { readTaskMessages, saveTaskMessages } from "./taskMessages"
 export { taskMetadata } from "./taskMetadata"
 export { TaskHistoryStore } from "./TaskHistoryStore"
+export { TaskSearchIndex } from "./TaskSearchIndex"
@@ -0,0 +1,159 @@
+import type { ProviderSettings } from "@roo-code/types"
+
+const WINDOW_MS = 60_000
+const BASE_BACKOFF_MS = 5_000
+const MAX_BACKOFF_MS = 10 * 60_000
+// Spread out the tasks that resume when a pause ends so they don't all hit the provider at once
+const RESUME_JITTER_MS = 1_000
+
+interface ProviderRateLimitState {
+	// Start times of the requests reserved in the last minute (or later), in ascending order
+	slots: number[]
+	pausedUntil: number
+	consecutiveRateLimits: number
+}
+
+/**
+ * Coordinates the requests of all tasks that share a provider, so concurrent
+ * tasks (e.g. an orchestrator's subtasks) honor a combined requests-per-minute
+ * budget and back off together instead of starving each other when the
+ * provider starts returning 429s.
+ */
+export class ProviderRateLimiter {
+	private states = new Map<string, ProviderRateLimitState>()
+
+	constructor(
+		private readonly now: () => number = Date.now,
+		private readonly random: () => number = Math.random,
+	) {}
+
+	/**
+	 * Reserve the next request slot for a provider. Slots are handed out in the
+	 * order they are reserved.
+	 *
+	 * @param key - The provider key, see `getProviderRateLimitKey`
+	 * @param requestsPerMinute - The provider's budget; 0 or undefined means unlimited
+	 * @returns Milliseconds to wait before sending the request
+	 */
+	reserve(key: string, requestsPerMinute?: number): number {
+		const now = this.now()
+		const state = this.getState(key)
+
+		let start = now
+
+		if (state.pausedUntil > now) {
+			start = state.pausedUntil + this.random() * RESUME_JITTER_MS
+		}
+
+		if (requestsPerMinute && requestsPerMinute > 0) {
+			state.slots = state.slots.filter((slot) => slot > now - WINDOW_MS)
+
+			// Queue behind requests that were reserved earlier but haven't started yet.
+			const lastSlot = state.slots[state.slots.length - 1] ?? 0
+			start = Math.max(start, lastSlot)
+
+			// The request can start once the oldest of the last `requestsPerMinute` requests leaves the window.
+			if (state.slots.length >= requestsPerMinute) {
+				start = Math.max(start, state.slots[state.slots.length - requestsPerMinute] + WINDOW_MS)
+			}
+
+			state.slots.push(start)
+		}
+
+		return Math.max(0, start - now)
+	}
+
+	/**
+	 * Pause all requests to a provider after it rate limited one of them.
+	 *
+	 * @param retryAfterMs - How long the provider asked us to wait, if it did
+	 * @returns Milliseconds until the provider accepts requests again
+	 */
+	reportRateLimited(key: string, retryAfterMs?: number): number {
+		const now = this.now()
+		const state = this.getState(key)
+
+		// Concurrent requests tend to fail together; only the first one of a
+		// burst counts towards the backoff.
+		if (state.pausedUntil <= now) {
+			state.consecutiveRateLimits++
+		}
+
+		const backoff = Math.min(BASE_BACKOFF_MS * Math.pow(2, state.consecutiveRateLimits - 1), MAX_BACKOFF_MS)
+		const jitteredBackoff = backoff / 2 + (this.random() * backoff) / 2
+		const pause = retryAfterMs !== undefined && retryAfterMs > 0 ? retryAfterMs : jitteredBackoff
+
+		state.pausedUntil = Math.max(state.pausedUntil, now + pause)
+		return state.pausedUntil - now
+	}
+
+	/**
+	 * Reset the backoff once a request to the provider succeeds.
+	 */
+	reportSuccess(key: string): void {
+		const state = this.states.get(key)
+
+		if (state) {
+			state.consecutiveRateLimits = 0
+		}
+	}
+
+	/**
+	 * @returns Milliseconds until a paused provider accepts requests again, 0 if it isn't paused
+	 */
+	getPauseRemaining(key: string): number {
+		return Math.max(0, (this.states.get(key)?.pausedUntil ?? 0) - this.now())
+	}
+
+	private getState(key: string): ProviderRateLimitState {
+		let state = this.states.get(key)
+
+		if (!state) {
+			state = { slots: [], pausedUntil: 0, consecutiveRateLimits: 0 }
+			this.states.set(key, state)
+		}
+
+		return state
+	}
+}
+
+/**
+ * Tasks using the same provider share a rate limit, regardless of the profile
+ * they were started with.
+ */
+export function getProviderRateLimitKey(apiConfiguration?: ProviderSettings): string {
+	return apiConfiguration?.apiProvider ?? "anthropic"
+}
+
+/**
+ * Read how long a 429 response asked us to wait, from a Retry-After header or
+ * a Google RetryInfo error detail.
+ */
+export function getRetryAfterMs(error: any): number | undefined {
+	const retryInfo = error?.errorDetails?.find((d: any) => d["@type"] === "type.googleapis.com/google.rpc.RetryInfo")
+	const match = retryInfo?.retryDelay?.match?.(/^(\d+)s$/)
+
+	if (match) {
+		return Number(match[1]) * 1000
+	}
+
+	const headers = error?.headers
+	const retryAfter: unknown =
+		typeof headers?.get === "function" ? headers.get("retry-after") : headers?.["retry-after"]
+
+	if (typeof retryAfter !== "string" || !retryAfter.trim()) {
+		return undefined
+	}
+
+	const seconds = Number(retryAfter)
+
+	if (!Number.isNaN(seconds)) {
+		return seconds * 1000
+	}
+
+	// Retry-After can also be an HTTP date.
+	const date = Date.parse(retryAfter)
+	return Number.isNaN(date) ? undefined : Math.max(0, date - Date.now())
+}
+
+export const providerRateLimiter = new ProviderRateLimiter()
@@ -35,6 +35,7 @@ import {
 	type ModelInfo,
 	type ClineApiReqCancelReason,
 	type ClineApiReqInfo,
+	type AutoApprovalProfile,
 	RooCodeEventName,
 	TelemetryEventName,
 	TaskStatus,
@@ -44,6 +45,7 @@ import {
 	isRetiredProvider,
 	isIdleAsk,
 	isInteractiveAsk,
+	isNonBlockingAsk,
 	isResumableAsk,
 	QueuedMessage,
 	DEFAULT_CONSECUTIVE_MISTAKE_LIMIT,
@@ -53,12 +55,15 @@ import {
 	ConsecutiveMistakeError,
 	MAX_MCP_TOOLS_THRESHOLD,
 	countEnabledMcpTools,
+	getCondensingStrategy,
+	type UsageCounters,
+	USAGE_COUNTERS,
 } from "@roo-code/types"
 import { TelemetryService } from "@roo-code/telemetry"
 import { CloudService } from "@roo-code/cloud"
 
 // api
-import { ApiHandler, ApiHandlerCreateMessageMetadata, buildApiHandler } from "../../api"
+import { ApiHandler, ApiHandlerCreateMessageMetadata, ModelPreloadHandler, buildApiHandler } from "../../api"
 import { ApiStream, GroundingSource } from "../../api/transform/stream"
 import { maybeRemoveImageBlocks } from "../../api/transform/image-cleaning"
 
@@ -84,12 +89,15 @@ import { findToolName } from "../../integrations/misc/export-markdown"
 import { RooTerminalProcess } from "../../integrations/terminal/types"
 import { TerminalRegistry } from "../../integrations/terminal/TerminalRegistry"
 import { OutputInterceptor } from "../../integrations/terminal/OutputInterceptor"
+import { BackgroundProcessManager } from "../../integrations/terminal/BackgroundProcessManager"
+import { PendingEdits } from "../pending-edits/PendingEdits"
 
 // utils
-import { calculateApiCostAnthropic, calculateApiCostOpenAI } from "../../shared/cost"
+import { calculateApiCostAnthropic, calculateApiCostOpenAI, calculateCacheSavings } from "../../shared/cost"
 import { getWorkspacePath } from "../../utils/path"
 import { sanitizeToolUseId } from "../../utils/tool-id"
 import { getTaskDirectoryPath } from "../../utils/storage"
+import { recordProviderTraffic } from "../../utils/providerTrafficRecorder"
 
 // prompts
 import { formatResponse } from "../prompts/responses"
@@ -100,6 +108,8 @@ import { buildNativeToolsArrayWithRestrictions } from "./build-tools"
 import { ToolRepetitionDetector } from "../tools/ToolRepetitionDetector"
 import { restoreTodoListForTask } from "../tools/UpdateTodoListTool"
 import { FileContextTracker } from "../context-tracking/FileContextTracker"
+import { McpResourceTracker } from "../context-tracking/McpResourceTracker"
+import { PinnedContext } from "../context-tracking/PinnedContext"
 import { RooIgnoreController } from "../ignore/RooIgnoreController"
 import { RooProtectedController } from "../protect/RooProtectedController"
 import { type AssistantMessageContent, presentAssistantMessage } from "../assistant-message"
@@ -117,18 +127,23 @@ import {
 } from "../task-persistence"
 import { getEnvironmentDetails } from "../environment/getEnvironmentDetails"
 import { checkContextWindowExceededError } from "../context/context-management/context-error-handling"
+import { getNextFailoverProfile, isFailoverError } from "./provider-failover"
+import { TaskBudgetHandler } from "./TaskBudgetHandler"
+import { getProviderRateLimitKey, getRetryAfterMs, providerRateLimiter } from "./ProviderRateLimiter"
 import {
 	type CheckpointDiffOptions,
 	type CheckpointRestoreOptions,
+	type CheckpointRestoreFilesOptions,
 	getCheckpointService,
 	checkpointSave,
 	checkpointRestore,
+	checkpointRestoreFiles,
 	checkpointDiff,
 } from "../checkpoints"
 import { processUserContentMentions } from "../mentions/processUserContentMentions"
 import { getMessagesSinceLastSummary, summarizeConversation, getEffectiveApiHistory } from "../condense"
 import { MessageQueueService } from "../message-queue/MessageQueueService"
-import { AutoApprovalHandler, checkAutoApproval } from "../auto-approval"
+import { AutoApprovalHandler, applyAutoApprovalProfile, checkAutoApproval } from "../auto-approval"
 import { MessageManager } from "../message-manager"
 import { validateAndFixToolResultIds } from "./validateToolResultIds"
 import { mergeConsecutiveApiMessages } from "./mergeConsecutiveApiMessages"
@@ -137,6 +152,13 @@ const MAX_EXPONENTIAL_BACKOFF_SECONDS = 600 // 10 minutes
 const DEFAULT_USAGE_COLLECTION_TIMEOUT_MS = 5000 // 5 seconds
 const FORCED_CONTEXT_REDUCTION_PERCENT = 75 // Keep 75% of context (remove 25%) on context window errors
 const MAX_CONTEXT_WINDOW_RETRIES = 3 // Maximum retries for context window errors
+// Tools that would change the foreground task or the provider's mode
+const BACKGROUND_TASK_DISABLED_TOOLS = ["new_task", "run_parallel_tasks", "switch_mode"]
+const BACKGROUND_TASK_DENIED_MESSAGE =
+	"This task runs in the background, so the user can't approve actions or answer questions. Only auto-approved actions can run. Continue without this, or finish with attempt_completion and explain what is left to do."
+
+const UNAPPROVED_ACTION_DENIED_MESSAGE =
+	"This task runs unattended, so nobody can approve actions. Only auto-approved actions can run. Continue without this, or finish with attempt_completion and explain what is left to do."
 
 export interface TaskOptions extends CreateTaskOptions {
 	provider: ClineProvider
@@ -157,6 +179,12 @@ export interface TaskOptions extends CreateTaskOptions {
 	workspacePath?: string
 	/** Initial status for the task's history item (e.g., "active" for child tasks) */
 	initialStatus?: "active" | "delegated" | "completed"
+	/** The mode to run a new task in, instead of the provider's current mode */
+	mode?: string
+	/** Whether the task runs in the background, alongside the current task (see `isBackgroundTask`) */
+	background?: boolean
+	/** Auto-approval settings to use instead of the user's (see `autoApproval`) */
+	autoApproval?: AutoApprovalProfile
 }
 
 export class Task extends EventEmitter<TaskEvents> implements TaskLike {
@@ -176,6 +204,19 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 	readonly taskNumber: number
 	readonly workspacePath: string
 
+	/**
+	 * Background tasks (parallel subtasks and scheduled tasks) run alongside
+	 * the current task and are never shown in the chat, so they keep their own
+	 * mode and can't ask the user anything. See `getProviderState()` and `ask()`.
+	 */
+	readonly isBackgroundTask: boolean
+
+	/**
+	 * The auto-approval profile of a scheduled task, which replaces the user's
+	 * auto-approval settings for this task.
+	 */
+	readonly autoApproval?: AutoApprovalProfile
+
 	/**
 	 * The mode associated with this task. Persisted across sessions
 	 * to maintain user context when reopening tasks from history.
@@ -285,6 +326,10 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 	api: ApiHandler
 	private static lastGlobalApiRequestTime?: number
 	private autoApprovalHandler: AutoApprovalHandler
+	private budgetHandler: TaskBudgetHandler
+	// The usage of each API request added to the usage log so far, by the
+	// timestamp of its `api_req_started` message, so updates only add what changed.
+	private loggedRequestUsage = new Map<number, UsageCounters>()
 
 	/**
 	 * Reset the global API request timestamp. This should only be used for testing.
@@ -298,12 +343,20 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 	rooIgnoreController?: RooIgnoreController
 	rooProtectedController?: RooProtectedController
 	fileContextTracker: FileContextTracker
+	mcpResourceTracker = new McpResourceTracker()
+	// The latest result of each cacheable MCP tool call, by server, tool and
+	// arguments, so identical results aren't added to the context again.
+	mcpToolResults = new Map<string, { toolCallId: string; text: string }>()
+	pinnedContext: PinnedContext
 	terminalProcess?: RooTerminalProcess
+	backgroundProcesses = new BackgroundProcessManager()
 
 	// Editing
 	diffViewProvider: DiffViewProvider
 	diffStrategy?: DiffStrategy
 	didEditFile: boolean = false
+	// Edits staged for review in dry-run mode
+	pendingEdits = new PendingEdits()
 
 	// LLM Messages & Chat Messages
 	apiConversationHistory: ApiMessage[] = []
@@ -436,6 +489,9 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		initialTodos,
 		workspacePath,
 		initialStatus,
+		mode,
+		background = false,
+		autoApproval,
 	}: TaskOptions) {
 		super()
 
@@ -478,6 +534,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		this.rooIgnoreController = new RooIgnoreController(this.cwd)
 		this.rooProtectedController = new RooProtectedController(this.cwd)
 		this.fileContextTracker = new FileContextTracker(provider, this.taskId)
+		this.pinnedContext = new PinnedContext(this.cwd, historyItem?.pinnedFiles)
 
 		this.rooIgnoreController.initialize().catch((error) => {
 			console.error("Failed to initialize RooIgnoreController:", error)
@@ -486,6 +543,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		this.apiConfiguration = apiConfiguration
 		this.api = buildApiHandler(this.apiConfiguration)
 		this.autoApprovalHandler = new AutoApprovalHandler()
+		this.budgetHandler = new TaskBudgetHandler()
 
 		this.consecutiveMistakeLimit = consecutiveMistakeLimit ?? DEFAULT_CONSECUTIVE_MISTAKE_LIMIT
 		this.providerRef = new WeakRef(provider)
@@ -497,6 +555,8 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		this.parentTask = parentTask
 		this.taskNumber = taskNumber
 		this.initialStatus = initialStatus
+		this.isBackgroundTask = background
+		this.autoApproval = autoApproval
 
 		// Store the task's mode and API config name when it's created.
 		// For history items, use the stored values; for new tasks, we'll set them
@@ -508,10 +568,11 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 			this.taskApiConfigReady = Promise.resolve()
 			TelemetryService.instance.captureTaskRestarted(this.taskId)
 		} else {
-			// For new tasks, don't set the mode/apiConfigName yet - wait for async initialization.
-			this._taskMode = undefined
+			// For new tasks, don't set the mode/apiConfigName yet - wait for async initialization,
+			// unless the mode was given.
+			this._taskMode = mode
 			this._taskApiConfigName = undefined
-			this.taskModeReady = this.initializeTaskMode(provider)
+			this.taskModeReady = mode ? Promise.resolve() : this.initializeTaskMode(provider)
 			this.taskApiConfigReady = this.initializeTaskApiConfigName(provider)
 			TelemetryService.instance.captureTaskCreated(this.taskId)
 		}
@@ -1231,6 +1292,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 				mode: this._taskMode || defaultModeSlug, // Use the task's own mode, not the current provider mode.
 				apiConfigName: this._taskApiConfigName, // Use the task's own provider profile, not the current provider profile.
 				initialStatus: this.initialStatus,
+				pinnedFiles: this.pinnedContext.getFiles(),
 			})
 
 			// Emit token/tool usage updates using debounced function
@@ -1248,6 +1310,27 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		}
 	}
 
+	public async setMessagePinned(ts: number, pinned: boolean) {
+		const message = this.findMessageByTimestamp(ts)
+
+		if (!message) {
+			return
+		}
+
+		message.isPinned = pinned || undefined
+		await this.saveClineMessages()
+		await this.updateClineMessage(message)
+	}
+
+	public async setFilePinned(filePath: string, pinned: boolean) {
+		if (!this.pinnedContext.setFilePinned(filePath, pinned)) {
+			return
+		}
+
+		await this.saveClineMessages()
+		await this.providerRef.deref()?.postStateToWebview()
+	}
+
 	private findMessageByTimestamp(ts: number): ClineMessage | undefined {
 		for (let i = this.clineMessages.length - 1; i >= 0; i--) {
 			if (this.clineMessages[i].ts === ts) {
@@ -1362,7 +1445,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 
 		// Automatically approve if the ask according to the user's settings.
 		const provider = this.providerRef.deref()
-		const state = provider ? await provider.getState() : undefined
+		const state = await this.getProviderState()
 		const approval = await checkAutoApproval({ state, ask: type, text, isProtected })
 
 		if (approval.decision === "approve") {
@@ -1377,6 +1460,19 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 				this.autoApprovalTimeoutRef = undefined
 			}, approval.timeout)
 			timeouts.push(this.autoApprovalTimeoutRef)
+		} else if (state?.denyUnapprovedActions && isInteractiveAsk(type) && type !== "followup") {
+			// Nobody is there to approve, e.g. in a CI run of the CLI.
+			this.denyAsk({ text: UNAPPROVED_ACTION_DENIED_MESSAGE })
+		} else if (this.isBackgroundTask && !isNonBlockingAsk(type)) {
+			// Nobody sees a background task's asks. Decline approvals and
+			// questions so the model can carry on, and stop on anything else
+			// (e.g. a failed request or the mistake limit) instead of hanging.
+			if (isInteractiveAsk(type)) {
+				this.denyAsk({ text: BACKGROUND_TASK_DENIED_MESSAGE })
+			} else {
+				await this.abortTask()
+				throw new Error(`[RooCode#ask] background task ${this.taskId}.${this.instanceId} needs the user (${type})`)
+			}
 		}
 
 		// The state is mutable if the message is complete and the task will
@@ -1653,9 +1749,10 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		const systemPrompt = await this.getSystemPrompt()
 
 		// Get condensing configuration
-		const state = await this.providerRef.deref()?.getState()
+		const state = await this.getProviderState()
 		const customCondensingPrompt = state?.customSupportPrompts?.CONDENSE
 		const { mode, apiConfiguration } = state ?? {}
+		const condensingStrategy = getCondensingStrategy(state ?? {}, mode)
 
 		const { contextTokens: prevContextTokens } = this.getTokenUsage()
 
@@ -1710,6 +1807,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 			taskId: this.taskId,
 			isAutomaticTrigger: false,
 			customCondensingPrompt,
+			condensingStrategy,
 			metadata,
 			environmentDetails,
 			filesReadByRoo,
@@ -1879,6 +1977,23 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 	// Lifecycle
 	// Start / Resume / Abort / Dispose
 
+	/**
+	 * Start loading the model of providers that run it locally, so it is ready
+	 * by the time the first request is sent. Runs in the background; a failed
+	 * preload only means the first request loads the model instead.
+	 */
+	private preloadModel(): void {
+		if (!("preloadModel" in this.api)) {
+			return
+		}
+
+		;(this.api as ApiHandler & ModelPreloadHandler).preloadModel().catch((error) => {
+			console.warn(
+				`[Task#${this.taskId}] Failed to preload model: ${error instanceof Error ? error.message : String(error)}`,
+			)
+		})
+	}
+
 	/**
 	 * Get enabled MCP tools count for this task.
 	 * Returns the count along with the number of servers contributing.
@@ -1936,6 +2051,8 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 
 	private async startTask(task?: string, images?: string[]): Promise<void> {
 		try {
+			this.preloadModel()
+
 			// `conversationHistory` (for API) and `clineMessages` (for webview)
 			// need to be in sync.
 			// If the extension process were killed, then on restart the
@@ -2000,6 +2117,8 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 
 	private async resumeTaskFromHistory() {
 		try {
+			this.preloadModel()
+
 			const modifiedClineMessages = await this.getSavedClineMessages()
 
 			// Remove any resume messages that may have been added before.
@@ -2338,6 +2457,11 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 			console.error("Error releasing terminals:", error)
 		}
 
+		// Stop the background processes started by this task.
+		this.backgroundProcesses.dispose().catch((error) => {
+			console.error("Error stopping background processes:", error)
+		})
+
 		// Cleanup command output artifacts
 		getTaskDirectoryPath(this.globalStoragePath, this.taskId)
 			.then((taskDir) => {
@@ -2364,6 +2488,8 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 			console.error("Error disposing file context tracker:", error)
 		}
 
+		this.mcpResourceTracker.dispose()
+
 		try {
 			// If we're not streaming then `abortStream` won't be called.
 			if (this.isStreaming && this.diffViewProvider.isEditing) {
@@ -2728,7 +2854,23 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 						cost: totalCost ?? costResult.totalCost,
 						cancelReason,
 						streamingFailedMessage,
+						provider: apiProvider,
+						modelId,
 					} satisfies ClineApiReqInfo)
+
+					this.logRequestUsage(
+						this.clineMessages[lastApiReqIndex].ts,
+						{ provider: apiProvider ?? "unknown", modelId: modelId ?? "unknown" },
+						{
+							requests: 1,
+							tokensIn: costResult.totalInputTokens,
+							tokensOut: costResult.totalOutputTokens,
+							cacheWrites: cacheWriteTokens,
+							cacheReads: cacheReadTokens,
+							cacheSavings: calculateCacheSavings(streamModelInfo, cacheReadTokens),
+							cost: totalCost ?? costResult.totalCost,
+						},
+					)
 				}
 
 				const abortStream = async (cancelReason: ClineApiReqCancelReason, streamingFailedMessage?: string) => {
@@ -3268,9 +3410,11 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 								`[Task#${this.taskId}.${this.instanceId}] Stream failed, will retry: ${streamingFailedMessage}`,
 							)
 
-							// Apply exponential backoff similar to first-chunk errors when auto-resubmit is enabled
+							// Apply exponential backoff similar to first-chunk errors when auto-resubmit is enabled,
+							// unless the retry goes to the next failover profile
 							const stateForBackoff = await this.providerRef.deref()?.getState()
-							if (stateForBackoff?.autoApprovalEnabled) {
+							const didFailover = await this.failoverToNextProfile(error)
+							if (!didFailover && stateForBackoff?.autoApprovalEnabled) {
 								await this.backoffAndAnnounce(currentItem.retryAttempt ?? 0, error)
 
 								// Check if task was aborted during the backoff
@@ -3741,6 +3885,28 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		return false
 	}
 
+	/**
+	 * The provider state as this task sees it. The provider's mode follows the
+	 * current task, so a background task overrides it with its own mode and
+	 * disables the tools it can't use. A scheduled task also brings its own
+	 * auto-approval settings.
+	 */
+	public async getProviderState() {
+		const state = await this.providerRef.deref()?.getState()
+
+		if (!state || !this.isBackgroundTask) {
+			return state
+		}
+
+		const backgroundState = {
+			...state,
+			mode: this.taskMode,
+			disabledTools: [...(state.disabledTools ?? []), ...BACKGROUND_TASK_DISABLED_TOOLS],
+		}
+
+		return this.autoApproval ? applyAutoApprovalProfile(backgroundState, this.autoApproval) : backgroundState
+	}
+
 	private async getSystemPrompt(): Promise<string> {
 		const { mcpEnabled } = (await this.providerRef.deref()?.getState()) ?? {}
 		let mcpHub: McpHub | undefined
@@ -3766,7 +3932,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 
 		const rooIgnoreInstructions = this.rooIgnoreController?.getInstructions()
 
-		const state = await this.providerRef.deref()?.getState()
+		const state = await this.getProviderState()
 
 		const {
 			mode,
@@ -3788,7 +3954,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 
 			const modelInfo = this.api.getModel().info
 
-			return SYSTEM_PROMPT(
+			const systemPrompt = await SYSTEM_PROMPT(
 				provider.context,
 				this.cwd,
 				false,
@@ -3815,6 +3981,9 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 				this.api.getModel().id,
 				provider.getSkillsManager(),
 			)
+
+			// Pinned context goes last and is rebuilt on every request, so it is never condensed away.
+			return systemPrompt + (await this.pinnedContext.format(this.clineMessages, this.rooIgnoreController))
 		})()
 	}
 
@@ -3826,7 +3995,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 	}
 
 	private async handleContextWindowExceededError(): Promise<void> {
-		const state = await this.providerRef.deref()?.getState()
+		const state = await this.getProviderState()
 		const { profileThresholds = {}, mode, apiConfiguration } = state ?? {}
 
 		const { contextTokens } = this.getTokenUsage()
@@ -3898,6 +4067,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 				autoCondenseContextPercent: FORCED_CONTEXT_REDUCTION_PERCENT,
 				systemPrompt: await this.getSystemPrompt(),
 				taskId: this.taskId,
+				condensingStrategy: getCondensingStrategy(state ?? {}, mode),
 				profileThresholds,
 				currentProfileId,
 				metadata,
@@ -3951,7 +4121,8 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 	}
 
 	/**
-	 * Enforce the user-configured provider rate limit.
+	 * Enforce the user-configured provider rate limit and wait for a slot in the
+	 * requests-per-minute budget shared by all tasks using the same provider.
 	 *
 	 * NOTE: This is intentionally treated as expected behavior and is surfaced via
 	 * the `api_req_rate_limit_wait` say type (not an error).
@@ -3960,20 +4131,27 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		const state = await this.providerRef.deref()?.getState()
 		const rateLimitSeconds =
 			state?.apiConfiguration?.rateLimitSeconds ?? this.apiConfiguration?.rateLimitSeconds ?? 0
+		const requestsPerMinute =
+			state?.apiConfiguration?.requestsPerMinute ?? this.apiConfiguration?.requestsPerMinute ?? 0
 
-		if (rateLimitSeconds <= 0 || !Task.lastGlobalApiRequestTime) {
-			return
+		let rateLimitDelay = 0
+
+		// Retry flows have their own delay messaging that already covers the rate limit window.
+		if (rateLimitSeconds > 0 && Task.lastGlobalApiRequestTime && retryAttempt === 0) {
+			const now = performance.now()
+			const timeSinceLastRequest = now - Task.lastGlobalApiRequestTime
+			rateLimitDelay = Math.ceil(
+				Math.min(rateLimitSeconds, Math.max(0, rateLimitSeconds * 1000 - timeSinceLastRequest) / 1000),
+			)
 		}
 
-		const now = performance.now()
-		const timeSinceLastRequest = now - Task.lastGlobalApiRequestTime
-		const rateLimitDelay = Math.ceil(
-			Math.min(rateLimitSeconds, Math.max(0, rateLimitSeconds * 1000 - timeSinceLastRequest) / 1000),
+		const queueDelay = Math.ceil(
+			providerRateLimiter.reserve(getProviderRateLimitKey(this.apiConfiguration), requestsPerMinute) / 1000,
 		)
+		const totalDelay = Math.max(rateLimitDelay, queueDelay)
 
-		// Only show the countdown UX on the first attempt. Retry flows have their own delay messaging.
-		if (rateLimitDelay > 0 && retryAttempt === 0) {
-			for (let i = rateLimitDelay; i > 0; i--) {
+		if (totalDelay > 0) {
+			for (let i = totalDelay; i > 0; i--) {
 				// Send structured JSON data for i18n-safe transport
 				const delayMessage = JSON.stringify({ seconds: i })
 				await this.say("api_req_rate_limit_wait", delayMessage, undefined, true)
@@ -3988,7 +4166,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		retryAttempt: number = 0,
 		options: { skipProviderRateLimit?: boolean } = {},
 	): ApiStream {
-		const state = await this.providerRef.deref()?.getState()
+		const state = await this.getProviderState()
 
 		const {
 			apiConfiguration,
@@ -4002,6 +4180,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 
 		// Get condensing configuration for automatic triggers.
 		const customCondensingPrompt = state?.customSupportPrompts?.CONDENSE
+		const condensingStrategy = getCondensingStrategy(state ?? {}, mode)
 
 		if (!options.skipProviderRateLimit) {
 			await this.maybeWaitForProviderRateLimit(retryAttempt)
@@ -4123,6 +4302,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 					systemPrompt,
 					taskId: this.taskId,
 					customCondensingPrompt,
+					condensingStrategy,
 					profileThresholds,
 					currentProfileId,
 					metadata: contextMgmtMetadata,
@@ -4211,6 +4391,18 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 			throw new Error("Auto-approval limit reached and user did not approve continuation")
 		}
 
+		// Check the task and mode budgets
+		const budgetResult = await this.budgetHandler.checkBudgetLimits(
+			state,
+			this._taskMode ?? mode,
+			this.combineMessages(this.clineMessages.slice(1)),
+			async (type, data) => this.ask(type, data),
+		)
+
+		if (!budgetResult.shouldProceed) {
+			throw new Error("Budget limit reached and user did not approve continuation")
+		}
+
 		// Whether we include tools is determined by whether we have any tools to send.
 		const modelInfo = this.api.getModel().info
 
@@ -4275,10 +4467,13 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		this.skipPrevResponseIdOnce = false
 
 		// The provider accepts reasoning items alongside standard messages; cast to the expected parameter type.
-		const stream = this.api.createMessage(
-			systemPrompt,
-			cleanConversationHistory as unknown as Anthropic.Messages.MessageParam[],
-			metadata,
+		const stream = recordProviderTraffic(
+			this.api.createMessage(
+				systemPrompt,
+				cleanConversationHistory as unknown as Anthropic.Messages.MessageParam[],
+				metadata,
+			),
+			{ taskId: this.taskId, globalStoragePath: this.globalStoragePath },
 		)
 		const iterator = stream[Symbol.asyncIterator]()
 
@@ -4307,6 +4502,7 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 			const firstChunk = await Promise.race([firstChunkPromise, abortPromise])
 			yield firstChunk.value
 			this.isWaitingForFirstChunk = false
+			providerRateLimiter.reportSuccess(getProviderRateLimitKey(this.apiConfiguration))
 		} catch (error) {
 			this.isWaitingForFirstChunk = false
 			this.currentRequestAbortController = undefined
@@ -4325,6 +4521,12 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 				return
 			}
 
+			// Move to the next failover profile without waiting when the provider itself is unavailable
+			if (await this.failoverToNextProfile(error)) {
+				yield* this.attemptApiRequest()
+				return
+			}
+
 			// note that this api_req_failed ask is unique in that we only present this option if the api hasn't streamed any content yet (ie it fails on the first chunk due), as it would allow them to hit a retry button. However if the api failed mid-stream, it could be in any arbitrary state where some tools may have executed, so that error is handled differently and requires cancelling the task entirely.
 			if (autoApprovalEnabled) {
 				// Apply shared exponential backoff and countdown UX
@@ -4375,6 +4577,50 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		yield* iterator
 	}
 
+	/**
+	 * Switches this task to the next profile of the failover chain when the error means its
+	 * provider is unavailable. The active profile of the extension is left unchanged.
+	 *
+	 * @param error - The error the API request failed with
+	 * @returns Whether the task switched profiles, in which case the request can be retried right away
+	 */
+	private async failoverToNextProfile(error: any): Promise<boolean> {
+		const provider = this.providerRef.deref()
+
+		if (!provider || this.abort || !isFailoverError(error)) {
+			return false
+		}
+
+		try {
+			const state = await provider.getState()
+			const { failoverApiConfigIds = [], listApiConfigMeta = [] } = state ?? {}
+			const fromName = this._taskApiConfigName ?? state?.currentApiConfigName
+			const fromProfile = listApiConfigMeta.find((profile) => profile.name === fromName)
+			const nextProfile = getNextFailoverProfile(failoverApiConfigIds, fromProfile?.id, listApiConfigMeta)
+
+			if (!nextProfile) {
+				return false
+			}
+
+			const { name, id, ...providerSettings } = await provider.providerSettingsManager.getProfile({
+				id: nextProfile.id,
+			})
+
+			this.updateApiConfiguration(providerSettings)
+			this.setTaskApiConfigName(name)
+
+			console.warn(`[Task#${this.taskId}.${this.instanceId}] Failing over from profile ${fromName} to ${name}`)
+			await this.say(
+				"api_req_failover",
+				JSON.stringify({ from: fromName, to: name, error: error?.message ?? String(error) }),
+			)
+			return true
+		} catch (err) {
+			console.error(`[Task#${this.taskId}.${this.instanceId}] Failed to switch to the failover profile:`, err)
+			return false
+		}
+	}
+
 	// Shared exponential backoff for retries (first-chunk and mid-stream)
 	private async backoffAndAnnounce(retryAttempt: number, error: any): Promise<void> {
 		try {
@@ -4394,18 +4640,19 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 				rateLimitDelay = Math.ceil(Math.min(rateLimit, Math.max(0, rateLimit * 1000 - elapsed) / 1000))
 			}
 
-			// Prefer RetryInfo on 429 if present
+			// On 429 pause every task using this provider, preferring the delay the provider asked for
+			let pauseDelay = 0
 			if (error?.status === 429) {
-				const retryInfo = error?.errorDetails?.find(
-					(d: any) => d["@type"] === "type.googleapis.com/google.rpc.RetryInfo",
-				)
-				const match = retryInfo?.retryDelay?.match?.(/^(\d+)s$/)
-				if (match) {
-					exponentialDelay = Number(match[1]) + 1
+				const retryAfterMs = getRetryAfterMs(error)
+				if (retryAfterMs !== undefined) {
+					exponentialDelay = Math.ceil(retryAfterMs / 1000) + 1
 				}
+
+				const rateLimitKey = getProviderRateLimitKey(this.apiConfiguration)
+				pauseDelay = Math.ceil(providerRateLimiter.reportRateLimited(rateLimitKey, retryAfterMs) / 1000)
 			}
 
-			const finalDelay = Math.max(exponentialDelay, rateLimitDelay)
+			const finalDelay = Math.max(exponentialDelay, rateLimitDelay, pauseDelay)
 			if (finalDelay <= 0) {
 				return
 			}
@@ -4600,6 +4847,10 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		return checkpointRestore(this, options)
 	}
 
+	public async checkpointRestoreFiles(options: CheckpointRestoreFilesOptions) {
+		return checkpointRestoreFiles(this, options)
+	}
+
 	public async checkpointDiff(options: CheckpointDiffOptions) {
 		return checkpointDiff(this, options)
 	}
@@ -4614,6 +4865,27 @@ export class Task extends EventEmitter<TaskEvents> implements TaskLike {
 		return getApiMetrics(this.combineMessages(this.clineMessages.slice(1)))
 	}
 
+	/**
+	 * Adds the usage of an API request to the usage log. A request's usage is
+	 * updated as its response streams in, so only what changed since it was
+	 * last logged is added.
+	 */
+	private logRequestUsage(ts: number, model: { provider: string; modelId: string }, usage: UsageCounters) {
+		const usageTracker = this.providerRef.deref()?.usageTracker
+
+		if (!usageTracker) {
+			return
+		}
+
+		const logged = this.loggedRequestUsage.get(ts)
+		const added = Object.fromEntries(
+			USAGE_COUNTERS.map((counter) => [counter, usage[counter] - (logged?.[counter] ?? 0)]),
+		) as UsageCounters
+		this.loggedRequestUsage.set(ts, usage)
+
+		usageTracker.record(ts, { workspace: this.cwd, mode: this._taskMode || defaultModeSlug, ...model }, added)
+	}
+
 	public recordToolUsage(toolName: ToolName) {
 		if (!this.toolUsage[toolName]) {
 			this.toolUsage[toolName] = { attempts: 0, failures: 0 }
@@ -0,0 +1,86 @@
+import {
+	type BudgetLimit,
+	type BudgetLimits,
+	type BudgetUsage,
+	type ClineAsk,
+	type ClineMessage,
+	getBudgetLimits,
+} from "@roo-code/types"
+
+import { getApiMetrics } from "../../shared/getApiMetrics"
+import { ClineAskResponse } from "../../shared/WebviewMessage"
+
+// Use epsilon for floating-point comparison of costs
+const EPSILON = 0.0001
+
+export interface BudgetCheckResult {
+	shouldProceed: boolean
+	exceededLimit?: BudgetLimit
+}
+
+/**
+ * Adds up what a task has spent so far.
+ */
+export function getBudgetUsage(messages: ClineMessage[]): BudgetUsage {
+	const { totalCost, totalTokensIn, totalTokensOut } = getApiMetrics(messages)
+
+	return {
+		cost: totalCost,
+		tokens: totalTokensIn + totalTokensOut,
+		requests: messages.filter((msg) => msg.type === "say" && msg.say === "api_req_started").length,
+	}
+}
+
+/**
+ * Pauses a task when it runs over its budget. Continuing grants the task another
+ * budget of the same size, counted from the point the user chose to continue.
+ */
+export class TaskBudgetHandler {
+	private baseline: BudgetUsage = { cost: 0, tokens: 0, requests: 0 }
+
+	/**
+	 * Check the budget before an API request and ask the user whether to continue if it's used up
+	 *
+	 * @param settings - The task and mode budgets
+	 * @param mode - The mode the task is running in
+	 * @param messages - The task's messages, including the `api_req_started` of the request being made
+	 */
+	async checkBudgetLimits(
+		settings: { taskBudget?: BudgetLimits; modeBudgets?: Record<string, BudgetLimits> } | undefined,
+		mode: string | undefined,
+		messages: ClineMessage[],
+		askForApproval: (
+			type: ClineAsk,
+			data: string,
+		) => Promise<{ response: ClineAskResponse; text?: string; images?: string[] }>,
+	): Promise<BudgetCheckResult> {
+		const limits = getBudgetLimits(settings ?? {}, mode)
+
+		if (limits.length === 0) {
+			return { shouldProceed: true }
+		}
+
+		const usage = getBudgetUsage(messages)
+		const exceededLimit = limits.find(
+			({ metric, limit }) => usage[metric] - this.baseline[metric] > limit + (metric === "cost" ? EPSILON : 0),
+		)
+
+		if (!exceededLimit) {
+			return { shouldProceed: true }
+		}
+
+		const { response } = await askForApproval(
+			"budget_limit_reached",
+			JSON.stringify({ ...exceededLimit, mode, usage: usage[exceededLimit.metric] }),
+		)
+
+		// If we get past the promise, it means the user approved and did not start a new task
+		if (response === "yesButtonClicked") {
+			// The request being made is part of the new budget
+			this.baseline = { ...usage, requests: usage.requests - 1 }
+			return { shouldProceed: true, exceededLimit }
+		}
+
+		return { shouldProceed: false, exceededLimit }
+	}
+}
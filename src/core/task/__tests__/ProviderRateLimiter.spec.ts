@@ -0,0 +1,103 @@
+// npx vitest run src/core/task/__tests__/ProviderRateLimiter.spec.ts
+
+import { ProviderRateLimiter, getProviderRateLimitKey, getRetryAfterMs } from "../ProviderRateLimiter"
+
+describe("ProviderRateLimiter", () => {
+	let now: number
+	let random: number
+	let limiter: ProviderRateLimiter
+
+	beforeEach(() => {
+		now = 1_000_000
+		random = 0
+		limiter = new ProviderRateLimiter(() => now, () => random)
+	})
+
+	it("does not delay requests without a budget", () => {
+		expect(limiter.reserve("openai")).toBe(0)
+		expect(limiter.reserve("openai", 0)).toBe(0)
+	})
+
+	it("queues requests once the requests per minute budget is used up", () => {
+		expect(limiter.reserve("openai", 2)).toBe(0)
+		now += 10_000
+		expect(limiter.reserve("openai", 2)).toBe(0)
+
+		// The third request has to wait until the first one leaves the window.
+		now += 10_000
+		expect(limiter.reserve("openai", 2)).toBe(40_000)
+
+		// The fourth request queues behind the third one.
+		expect(limiter.reserve("openai", 2)).toBe(50_000)
+	})
+
+	it("keeps separate budgets per provider", () => {
+		limiter.reserve("openai", 1)
+
+		expect(limiter.reserve("anthropic", 1)).toBe(0)
+		expect(limiter.reserve("openai", 1)).toBe(60_000)
+	})
+
+	it("pauses every request to a rate limited provider", () => {
+		expect(limiter.reportRateLimited("openai", 30_000)).toBe(30_000)
+
+		expect(limiter.getPauseRemaining("openai")).toBe(30_000)
+		expect(limiter.reserve("openai")).toBe(30_000)
+		expect(limiter.reserve("anthropic")).toBe(0)
+	})
+
+	it("spreads out the requests resuming after a pause", () => {
+		limiter.reportRateLimited("openai", 30_000)
+
+		random = 0.5
+		expect(limiter.reserve("openai")).toBe(30_500)
+	})
+
+	it("backs off exponentially with jitter until a request succeeds", () => {
+		random = 1
+		expect(limiter.reportRateLimited("openai")).toBe(5_000)
+
+		// Concurrent failures during the pause don't increase the backoff.
+		expect(limiter.reportRateLimited("openai")).toBe(5_000)
+
+		now += 5_000
+		expect(limiter.reportRateLimited("openai")).toBe(10_000)
+
+		now += 10_000
+		random = 0
+		expect(limiter.reportRateLimited("openai")).toBe(10_000)
+
+		now += 10_000
+		limiter.reportSuccess("openai")
+		random = 1
+		expect(limiter.reportRateLimited("openai")).toBe(5_000)
+	})
+})
+
+describe("getRetryAfterMs", () => {
+	it("reads Google RetryInfo details", () => {
+		const error = {
+			status: 429,
+			errorDetails: [{ "@type": "type.googleapis.com/google.rpc.RetryInfo", retryDelay: "12s" }],
+		}
+
+		expect(getRetryAfterMs(error)).toBe(12_000)
+	})
+
+	it("reads the Retry-After header", () => {
+		expect(getRetryAfterMs({ status: 429, headers: new Headers({ "retry-after": "7" }) })).toBe(7_000)
+		expect(getRetryAfterMs({ status: 429, headers: { "retry-after": "3" } })).toBe(3_000)
+	})
+
+	it("returns undefined without a retry delay", () => {
+		expect(getRetryAfterMs({ status: 429 })).toBeUndefined()
+	})
+})
+
+describe("getProviderRateLimitKey", () => {
+	it("shares the rate limit between profiles of the same provider", () => {
+		expect(getProviderRateLimitKey({ apiProvider: "openai", openAiBaseUrl: "https://a" })).toBe(
+			getProviderRateLimitKey({ apiProvider: "openai", openAiBaseUrl: "https://b" }),
+		)
+	})
+})
@@ -0,0 +1,111 @@
+import { ClineMessage } from "@roo-code/types"
+
+import { TaskBudgetHandler } from "../TaskBudgetHandler"
+
+vi.mock("../../../shared/getApiMetrics", () => ({
+	getApiMetrics: vi.fn(),
+}))
+
+import { getApiMetrics } from "../../../shared/getApiMetrics"
+
+const apiRequest = (ts: number): ClineMessage => ({ type: "say", say: "api_req_started", ts, text: "{}" })
+
+describe("TaskBudgetHandler", () => {
+	let handler: TaskBudgetHandler
+	let mockAskForApproval: any
+	const mockGetApiMetrics = getApiMetrics as any
+
+	beforeEach(() => {
+		handler = new TaskBudgetHandler()
+		mockAskForApproval = vi.fn()
+		vi.clearAllMocks()
+
+		mockGetApiMetrics.mockReturnValue({ totalCost: 0, totalTokensIn: 0, totalTokensOut: 0 })
+	})
+
+	it("should proceed when no budget is set", async () => {
+		const result = await handler.checkBudgetLimits({}, "code", [apiRequest(1)], mockAskForApproval)
+
+		expect(result.shouldProceed).toBe(true)
+		expect(mockAskForApproval).not.toHaveBeenCalled()
+	})
+
+	it("should proceed while usage is within the budget", async () => {
+		mockGetApiMetrics.mockReturnValue({ totalCost: 2, totalTokensIn: 100, totalTokensOut: 50 })
+
+		const result = await handler.checkBudgetLimits(
+			{ taskBudget: { maxCost: 2, maxTokens: 150, maxRequests: 2 } },
+			"code",
+			[apiRequest(1), apiRequest(2)],
+			mockAskForApproval,
+		)
+
+		expect(result.shouldProceed).toBe(true)
+		expect(mockAskForApproval).not.toHaveBeenCalled()
+	})
+
+	it("should ask when the task budget is exceeded", async () => {
+		mockGetApiMetrics.mockReturnValue({ totalCost: 0, totalTokensIn: 1000, totalTokensOut: 500 })
+		mockAskForApproval.mockResolvedValue({ response: "noButtonClicked" })
+
+		const result = await handler.checkBudgetLimits(
+			{ taskBudget: { maxTokens: 1000 } },
+			"code",
+			[apiRequest(1)],
+			mockAskForApproval,
+		)
+
+		expect(result.shouldProceed).toBe(false)
+		expect(result.exceededLimit).toEqual({ metric: "tokens", scope: "task", limit: 1000 })
+		expect(mockAskForApproval).toHaveBeenCalledWith(
+			"budget_limit_reached",
+			JSON.stringify({ metric: "tokens", scope: "task", limit: 1000, mode: "code", usage: 1500 }),
+		)
+	})
+
+	it("should only apply a mode budget to tasks running in that mode", async () => {
+		const settings = { modeBudgets: { architect: { maxRequests: 1 } } }
+		const messages = [apiRequest(1), apiRequest(2)]
+		mockAskForApproval.mockResolvedValue({ response: "noButtonClicked" })
+
+		const codeResult = await handler.checkBudgetLimits(settings, "code", messages, mockAskForApproval)
+		expect(codeResult.shouldProceed).toBe(true)
+
+		const architectResult = await handler.checkBudgetLimits(settings, "architect", messages, mockAskForApproval)
+		expect(architectResult.shouldProceed).toBe(false)
+		expect(architectResult.exceededLimit).toEqual({ metric: "requests", scope: "mode", limit: 1 })
+	})
+
+	it("should use the lower of the task and mode limits", async () => {
+		mockGetApiMetrics.mockReturnValue({ totalCost: 3, totalTokensIn: 0, totalTokensOut: 0 })
+		mockAskForApproval.mockResolvedValue({ response: "noButtonClicked" })
+
+		const result = await handler.checkBudgetLimits(
+			{ taskBudget: { maxCost: 5 }, modeBudgets: { code: { maxCost: 2 } } },
+			"code",
+			[apiRequest(1)],
+			mockAskForApproval,
+		)
+
+		expect(result.exceededLimit).toEqual({ metric: "cost", scope: "mode", limit: 2 })
+	})
+
+	it("should grant another budget once the user continues", async () => {
+		const settings = { taskBudget: { maxRequests: 2 } }
+		mockAskForApproval.mockResolvedValue({ response: "yesButtonClicked" })
+
+		const messages = [apiRequest(1), apiRequest(2), apiRequest(3)]
+		const result = await handler.checkBudgetLimits(settings, "code", messages, mockAskForApproval)
+		expect(result.shouldProceed).toBe(true)
+		expect(mockAskForApproval).toHaveBeenCalledTimes(1)
+
+		// The request that triggered the prompt counts towards the new budget
+		messages.push(apiRequest(4))
+		await handler.checkBudgetLimits(settings, "code", messages, mockAskForApproval)
+		expect(mockAskForApproval).toHaveBeenCalledTimes(1)
+
+		messages.push(apiRequest(5))
+		await handler.checkBudgetLimits(settings, "code", messages, mockAskForApproval)
+		expect(mockAskForApproval).toHaveBeenCalledTimes(2)
+	})
+})
@@ -0,0 +1,151 @@
+// npx vitest run src/core/task/__tests__/parallel-subtasks.spec.ts
+
+import { EventEmitter } from "events"
+
+import { RooCodeEventName, type ClineMessage } from "@roo-code/types"
+
+import type { Task } from "../Task"
+import { formatParallelSubtaskResults, runParallelSubtasks, type ParallelSubtask } from "../parallel-subtasks"
+
+class FakeTask extends EventEmitter {
+	abort = false
+	clineMessages: ClineMessage[] = []
+	start = vi.fn()
+
+	constructor(public readonly taskId: string) {
+		super()
+	}
+
+	abortTask = vi.fn(async () => {
+		if (this.abort) {
+			return
+		}
+
+		this.abort = true
+		this.emit(RooCodeEventName.TaskAborted)
+	})
+
+	complete(result: string) {
+		this.clineMessages.push({ ts: Date.now(), type: "say", say: "completion_result", text: result })
+		this.emit(RooCodeEventName.TaskCompleted)
+	}
+}
+
+const flush = () => new Promise((resolve) => setTimeout(resolve, 0))
+
+describe("runParallelSubtasks", () => {
+	let parent: FakeTask
+	let children: FakeTask[]
+
+	const createSubtask = vi.fn(async (subtask: ParallelSubtask) => {
+		const child = new FakeTask(`child-${children.length + 1}`)
+		children.push(child)
+		return child as unknown as Task
+	})
+
+	const subtasks: ParallelSubtask[] = [
+		{ mode: "code", message: "one" },
+		{ mode: "ask", message: "two" },
+		{ mode: "code", message: "three" },
+	]
+
+	beforeEach(() => {
+		parent = new FakeTask("parent")
+		children = []
+		createSubtask.mockClear()
+	})
+
+	it("runs at most maxConcurrency subtasks at a time", async () => {
+		const promise = runParallelSubtasks(parent as unknown as Task, subtasks, { maxConcurrency: 2, createSubtask })
+		await flush()
+
+		expect(children).toHaveLength(2)
+		expect(children.every((child) => child.start.mock.calls.length === 1)).toBe(true)
+
+		children[1].complete("second")
+		await flush()
+
+		expect(children).toHaveLength(3)
+
+		children[0].complete("first")
+		children[2].complete("third")
+
+		const results = await promise
+
+		expect(results).toEqual([
+			{ mode: "code", taskId: "child-1", status: "completed", result: "first" },
+			{ mode: "ask", taskId: "child-2", status: "completed", result: "second" },
+			{ mode: "code", taskId: "child-3", status: "completed", result: "third" },
+		])
+		expect(children.every((child) => child.abortTask.mock.calls.length === 1)).toBe(true)
+	})
+
+	it("reports subtasks that stop on a question as failed", async () => {
+		const onSettled = vi.fn(async () => {})
+		const promise = runParallelSubtasks(parent as unknown as Task, subtasks.slice(0, 1), {
+			maxConcurrency: 1,
+			createSubtask,
+			onSettled,
+		})
+		await flush()
+
+		children[0].clineMessages.push({ ts: Date.now(), type: "ask", ask: "followup", text: "Which file?" })
+		await children[0].abortTask()
+
+		const results = await promise
+
+		expect(results[0]).toEqual({
+			mode: "code",
+			taskId: "child-1",
+			status: "failed",
+			result: "It stopped because it needed the user (followup).",
+		})
+		expect(onSettled).toHaveBeenCalledWith(results[0], 0)
+	})
+
+	it("reports subtasks that cannot be created as failed", async () => {
+		createSubtask.mockRejectedValueOnce(new Error("Provider not available"))
+
+		const results = await runParallelSubtasks(parent as unknown as Task, subtasks.slice(0, 1), {
+			maxConcurrency: 1,
+			createSubtask,
+		})
+
+		expect(results[0]).toEqual({
+			mode: "code",
+			status: "failed",
+			result: "It couldn't be started: Provider not available",
+		})
+	})
+
+	it("cancels running subtasks and skips the rest when the parent is aborted", async () => {
+		const promise = runParallelSubtasks(parent as unknown as Task, subtasks, { maxConcurrency: 1, createSubtask })
+		await flush()
+
+		await parent.abortTask()
+
+		const results = await promise
+
+		expect(children).toHaveLength(1)
+		expect(children[0].abortTask).toHaveBeenCalled()
+		expect(results.map((result) => result.result)).toEqual([
+			"It was cancelled.",
+			"The parent task was cancelled before it started.",
+			"The parent task was cancelled before it started.",
+		])
+		expect(parent.listenerCount(RooCodeEventName.TaskAborted)).toBe(0)
+	})
+})
+
+describe("formatParallelSubtaskResults", () => {
+	it("labels each result with its position, mode and task id", () => {
+		const text = formatParallelSubtaskResults([
+			{ mode: "code", taskId: "child-1", status: "completed", result: "Done." },
+			{ mode: "ask", status: "failed", result: "It was cancelled." },
+		])
+
+		expect(text).toBe(
+			"Subtask 1 of 2 (code mode, child-1) completed.\n\nResult:\nDone.\n\nSubtask 2 of 2 (ask mode) failed. It was cancelled.",
+		)
+	})
+})
@@ -0,0 +1,55 @@
+import { getNextFailoverProfile, isFailoverError } from "../provider-failover"
+
+describe("isFailoverError", () => {
+	it("fails over on rate limits, server errors and request timeouts", () => {
+		expect(isFailoverError({ status: 429, message: "Too many requests" })).toBe(true)
+		expect(isFailoverError({ status: 503, message: "Service unavailable" })).toBe(true)
+		expect(isFailoverError({ response: { status: 502 } })).toBe(true)
+		expect(isFailoverError({ status: 408 })).toBe(true)
+	})
+
+	it("fails over on timeouts and unreachable providers", () => {
+		const timeout = Object.assign(new Error("Connection error."), { name: "APIConnectionTimeoutError" })
+
+		expect(isFailoverError(timeout)).toBe(true)
+		expect(isFailoverError(new Error("Request timed out after 600000ms"))).toBe(true)
+		expect(isFailoverError({ message: "fetch failed", cause: { code: "ECONNREFUSED" } })).toBe(true)
+	})
+
+	it("does not fail over on errors caused by the request", () => {
+		expect(isFailoverError({ status: 400, message: "context length exceeded" })).toBe(false)
+		expect(isFailoverError({ status: 401, message: "Invalid API key" })).toBe(false)
+		expect(isFailoverError(new Error("Request cancelled by user"))).toBe(false)
+		expect(isFailoverError(undefined)).toBe(false)
+	})
+})
+
+describe("getNextFailoverProfile", () => {
+	const profiles = [
+		{ id: "anthropic", name: "Anthropic" },
+		{ id: "openrouter", name: "OpenRouter" },
+		{ id: "ollama", name: "Local Ollama" },
+	]
+
+	it("follows the chain from the current profile", () => {
+		const chain = ["anthropic", "openrouter", "ollama"]
+
+		expect(getNextFailoverProfile(chain, "anthropic", profiles)?.name).toBe("OpenRouter")
+		expect(getNextFailoverProfile(chain, "openrouter", profiles)?.name).toBe("Local Ollama")
+		expect(getNextFailoverProfile(chain, "ollama", profiles)).toBeUndefined()
+	})
+
+	it("starts the chain from the beginning for a profile outside of it", () => {
+		expect(getNextFailoverProfile(["openrouter", "ollama"], "anthropic", profiles)?.name).toBe("OpenRouter")
+		expect(getNextFailoverProfile(["openrouter"], undefined, profiles)?.name).toBe("OpenRouter")
+	})
+
+	it("skips profiles that no longer exist", () => {
+		expect(getNextFailoverProfile(["deleted", "ollama"], "anthropic", profiles)?.name).toBe("Local Ollama")
+		expect(getNextFailoverProfile(["deleted"], "anthropic", profiles)).toBeUndefined()
+	})
+
+	it("returns nothing without a chain", () => {
+		expect(getNextFailoverProfile([], "anthropic", profiles)).toBeUndefined()
+	})
+})
@@ -0,0 +1,38 @@
+import { RooCodeEventName } from "@roo-code/types"
+
+import { findLast } from "../../shared/array"
+
+import type { Task } from "./Task"
+
+export interface BackgroundTaskResult {
+	status: "completed" | "failed"
+	// The task's completion result, or why it failed
+	result: string
+}
+
+/**
+ * Starts a background task and waits until it completes, taking its result
+ * from its completion message, or until it is aborted.
+ */
+export function runBackgroundTask(task: Task): Promise<BackgroundTaskResult> {
+	return new Promise((resolve) => {
+		task.once(RooCodeEventName.TaskCompleted, () => {
+			const completion = findLast(task.clineMessages, (m) => m.type === "say" && m.say === "completion_result")
+			resolve({ status: "completed", result: completion?.text ?? "" })
+
+			// The task has nothing left to do once it has returned its result.
+			void task.abortTask()
+		})
+
+		task.once(RooCodeEventName.TaskAborted, () => {
+			const lastMessage = task.clineMessages.at(-1)
+			const reason =
+				lastMessage?.type === "ask"
+					? `It stopped because it needed the user (${lastMessage.ask}).`
+					: "It was cancelled."
+			resolve({ status: "failed", result: reason })
+		})
+
+		task.start()
+	})
+}
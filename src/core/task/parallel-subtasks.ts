@@ -0,0 +1,100 @@
+import pLimit from "p-limit"
+
+import { RooCodeEventName } from "@roo-code/types"
+
+import type { Task } from "./Task"
+import { runBackgroundTask, type BackgroundTaskResult } from "./background-tasks"
+
+export interface ParallelSubtask {
+	mode: string
+	message: string
+}
+
+export interface ParallelSubtaskResult extends BackgroundTaskResult {
+	mode: string
+	taskId?: string
+}
+
+interface RunParallelSubtasksOptions {
+	maxConcurrency: number
+	createSubtask: (subtask: ParallelSubtask) => Promise<Task>
+	onSettled?: (result: ParallelSubtaskResult, index: number) => Promise<void>
+}
+
+/**
+ * Runs subtasks in the background, at most `maxConcurrency` at a time, and
+ * resolves with their results in the order of `subtasks` once all of them
+ * have completed or failed. The subtasks' API requests still go through the
+ * shared provider rate limiter, like those of any other task.
+ *
+ * Aborting the parent aborts the running subtasks and skips the rest.
+ */
+export async function runParallelSubtasks(
+	parent: Task,
+	subtasks: ParallelSubtask[],
+	{ maxConcurrency, createSubtask, onSettled }: RunParallelSubtasksOptions,
+): Promise<ParallelSubtaskResult[]> {
+	const limit = pLimit(Math.max(1, maxConcurrency))
+	const running = new Set<Task>()
+
+	const abortRunning = () => {
+		for (const child of running) {
+			void child.abortTask()
+		}
+	}
+
+	parent.on(RooCodeEventName.TaskAborted, abortRunning)
+
+	const run = async (subtask: ParallelSubtask): Promise<ParallelSubtaskResult> => {
+		if (parent.abort) {
+			return { mode: subtask.mode, status: "failed", result: "The parent task was cancelled before it started." }
+		}
+
+		let child: Task
+
+		try {
+			child = await createSubtask(subtask)
+		} catch (error) {
+			const message = error instanceof Error ? error.message : String(error)
+			return { mode: subtask.mode, status: "failed", result: `It couldn't be started: ${message}` }
+		}
+
+		running.add(child)
+
+		try {
+			return { mode: subtask.mode, taskId: child.taskId, ...(await runBackgroundTask(child)) }
+		} finally {
+			running.delete(child)
+		}
+	}
+
+	try {
+		return await Promise.all(
+			subtasks.map((subtask, index) =>
+				limit(async () => {
+					const result = await run(subtask)
+					await onSettled?.(result, index)
+					return result
+				}),
+			),
+		)
+	} finally {
+		parent.off(RooCodeEventName.TaskAborted, abortRunning)
+	}
+}
+
+/**
+ * Formats the results of run_parallel_tasks for the parent, like the result a
+ * delegated subtask returns.
+ */
+export function formatParallelSubtaskResults(results: ParallelSubtaskResult[]): string {
+	return results
+		.map((result, index) => {
+			const label = `Subtask ${index + 1} of ${results.length} (${result.mode} mode${result.taskId ? `, ${result.taskId}` : ""})`
+
+			return result.status === "completed"
+				? `${label} completed.\n\nResult:\n${result.result}`
+				: `${label} failed. ${result.result}`
+		})
+		.join("\n\n")
+}
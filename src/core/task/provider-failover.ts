@@ -0,0 +1,60 @@
+import type { ProviderSettingsEntry } from "@roo-code/types"
+
+const TIMEOUT_ERROR_NAMES = new Set(["TimeoutError", "APIConnectionTimeoutError"])
+// Network errors of a provider that is down or unreachable, e.g. a local Ollama that is not running
+const UNAVAILABLE_ERROR_CODES = new Set(["ETIMEDOUT", "ESOCKETTIMEDOUT", "ECONNRESET", "ECONNREFUSED"])
+const TIMEOUT_MESSAGE = /\btimed?\s*out\b|\btimeout\b/i
+
+/**
+ * Whether an API error means the provider itself is unavailable, so the request is worth
+ * repeating on another provider: rate limits, server errors, timeouts and refused connections.
+ * Errors caused by the request, such as invalid parameters or a context window overflow, fail
+ * on any provider.
+ */
+export function isFailoverError(error: unknown): boolean {
+	if (!error || typeof error !== "object") {
+		return false
+	}
+
+	const err = error as Record<string, any>
+	const status = Number(err.status ?? err.response?.status ?? err.error?.status)
+	if (status === 408 || status === 429 || (status >= 500 && status < 600)) {
+		return true
+	}
+
+	const code = String(err.code ?? err.cause?.code ?? "")
+	if (UNAVAILABLE_ERROR_CODES.has(code) || TIMEOUT_ERROR_NAMES.has(err.name)) {
+		return true
+	}
+
+	return TIMEOUT_MESSAGE.test(String(err.message ?? ""))
+}
+
+/**
+ * Picks the profile a task fails over to from the configured chain.
+ *
+ * The chain is followed from the position of the current profile, so each profile is tried at
+ * most once and a task on a fallback never goes back to an earlier one. A current profile that is
+ * not part of the chain starts it from the beginning. Ids of deleted profiles are skipped.
+ *
+ * @param chain Ordered profile ids from the `failoverApiConfigIds` setting
+ * @param currentProfileId Id of the profile the task is using
+ * @param profiles Available profiles
+ * @returns The next profile, or undefined when the chain is exhausted
+ */
+export function getNextFailoverProfile(
+	chain: string[],
+	currentProfileId: string | undefined,
+	profiles: ProviderSettingsEntry[],
+): ProviderSettingsEntry | undefined {
+	const position = currentProfileId ? chain.indexOf(currentProfileId) : -1
+
+	for (const id of chain.slice(position + 1)) {
+		const profile = profiles.find((candidate) => candidate.id === id)
+		if (profile && id !== currentProfileId) {
+			return profile
+		}
+	}
+
+	return undefined
+}
@@ -1,26 +1,57 @@
 import path from "path"
 import fs from "fs/promises"
+import * as vscode from "vscode"
+import delay from "delay"
 
 import { type ClineSayTool, DEFAULT_WRITE_DELAY_MS } from "@roo-code/types"
 import { TelemetryService } from "@roo-code/telemetry"
 
 import { getReadablePath } from "../../utils/path"
+import { isPathOutsideWorkspace } from "../../utils/pathUtils"
 import { Task } from "../task/Task"
 import { formatResponse } from "../prompts/responses"
-import { fileExistsAtPath } from "../../utils/fs"
 import { RecordSource } from "../context-tracking/FileContextTrackerTypes"
 import { unescapeHtmlEntities } from "../../utils/text-normalization"
 import { EXPERIMENT_IDS, experiments } from "../../shared/experiments"
 import { computeDiffStats, sanitizeUnifiedDiff } from "../diff/stats"
-import type { ToolUse } from "../../shared/tools"
+import { isDryRunEnabled, stagePendingEdit } from "../pending-edits/review"
+import { diagnosticsToProblemsString, getNewDiagnostics } from "../../integrations/diagnostics"
+import type { DiffResult, ToolUse } from "../../shared/tools"
 
 import { BaseTool, ToolCallbacks } from "./BaseTool"
 
-interface ApplyDiffParams {
+interface ApplyDiffFileParams {
 	path: string
 	diff: string
 }
 
+interface ApplyDiffParams extends ApplyDiffFileParams {
+	// Additional files to edit in the same transaction as `path`
+	files?: ApplyDiffFileParams[]
+}
+
+interface TransactionFile {
+	relPath: string
+	absolutePath: string
+	diff: string
+	originalContent: string
+	newContent: string
+}
+
+function formatDiffError(absolutePath: string, diffResult: DiffResult): string {
+	const failedParts = diffResult.failParts?.filter((part) => !part.success) ?? []
+	const details = (failedParts.length > 0 ? failedParts : [diffResult]).map((part) => {
+		if (part.success) {
+			return ""
+		}
+
+		const errorDetails = part.details ? JSON.stringify(part.details, null, 2) : ""
+		return `<error_details>\n${part.error}${errorDetails ? `\n\nDetails:\n${errorDetails}` : ""}\n</error_details>`
+	})
+
+	return `Unable to apply diff to file: ${absolutePath}\n\n${details.join("\n")}`
+}
+
 export class ApplyDiffTool extends BaseTool<"apply_diff"> {
 	readonly name = "apply_diff" as const
 
@@ -28,6 +59,10 @@ export class ApplyDiffTool extends BaseTool<"apply_diff"> {
 		const { askApproval, handleError, pushToolResult } = callbacks
 		let { path: relPath, diff: diffContent } = params
 
+		if (params.files?.length) {
+			return this.executeTransaction([{ path: relPath, diff: diffContent }, ...params.files], task, callbacks)
+		}
+
 		if (diffContent && !task.api.getModel().id.includes("claude")) {
 			diffContent = unescapeHtmlEntities(diffContent)
 		}
@@ -56,7 +91,7 @@ export class ApplyDiffTool extends BaseTool<"apply_diff"> {
 			}
 
 			const absolutePath = path.resolve(task.cwd, relPath)
-			const fileExists = await fileExistsAtPath(absolutePath)
+			const fileExists = await task.pendingEdits.exists(absolutePath)
 
 			if (!fileExists) {
 				task.consecutiveMistakeCount++
@@ -68,7 +103,7 @@ export class ApplyDiffTool extends BaseTool<"apply_diff"> {
 				return
 			}
 
-			const originalContent: string = await fs.readFile(absolutePath, "utf-8")
+			const originalContent: string = await task.pendingEdits.readFile(absolutePath)
 
 			// Apply the diff to the original content
 			const diffResult = (await task.diffStrategy?.applyDiff(
@@ -120,6 +155,11 @@ export class ApplyDiffTool extends BaseTool<"apply_diff"> {
 			task.consecutiveMistakeCount = 0
 			task.consecutiveMistakeCountForApplyDiff.delete(relPath)
 
+			if (await isDryRunEnabled(task)) {
+				pushToolResult(await stagePendingEdit(task, relPath, diffResult.content))
+				return
+			}
+
 			// Generate backend-unified diff for display in chat/webview
 			const unifiedPatchRaw = formatResponse.createPrettyPatch(relPath, originalContent, diffResult.content)
 			const unifiedPatch = sanitizeUnifiedDiff(unifiedPatchRaw)
@@ -269,6 +309,243 @@ export class ApplyDiffTool extends BaseTool<"apply_diff"> {
 		}
 	}
 
+	/**
+	 * Applies diffs to several files as a single transaction. All diffs are applied
+	 * in memory first, and nothing is written unless every search block of every
+	 * file matches and the user approves the whole batch. If writing a file fails,
+	 * the files written so far are restored to their original contents, so the
+	 * workspace is never left half-edited.
+	 */
+	private async executeTransaction(
+		edits: ApplyDiffFileParams[],
+		task: Task,
+		callbacks: ToolCallbacks,
+	): Promise<void> {
+		const { askApproval, handleError, pushToolResult } = callbacks
+		const shouldUnescape = !task.api.getModel().id.includes("claude")
+
+		try {
+			const files: TransactionFile[] = []
+			const errors: string[] = []
+			let repeatedFailure = false
+
+			for (const edit of edits) {
+				const relPath = edit.path
+				const diff = edit.diff && shouldUnescape ? unescapeHtmlEntities(edit.diff) : edit.diff
+
+				if (!relPath || !diff) {
+					task.consecutiveMistakeCount++
+					task.recordToolError("apply_diff")
+					pushToolResult(await task.sayAndCreateMissingParamError("apply_diff", relPath ? "diff" : "path"))
+					return
+				}
+
+				if (!task.rooIgnoreController?.validateAccess(relPath)) {
+					await task.say("rooignore_error", relPath)
+					pushToolResult(formatResponse.rooIgnoreError(relPath))
+					return
+				}
+
+				const absolutePath = path.resolve(task.cwd, relPath)
+
+				if (files.some((file) => file.absolutePath === absolutePath)) {
+					errors.push(
+						`The file ${relPath} is listed more than once. Put all changes to a file in a single diff.`,
+					)
+					continue
+				}
+
+				if (!(await task.pendingEdits.exists(absolutePath))) {
+					errors.push(`File does not exist at path: ${absolutePath}`)
+					continue
+				}
+
+				const originalContent = await task.pendingEdits.readFile(absolutePath)
+				const diffResult: DiffResult = (await task.diffStrategy?.applyDiff(
+					originalContent,
+					diff,
+					parseInt(diff.match(/:start_line:(\d+)/)?.[1] ?? ""),
+				)) ?? {
+					success: false,
+					error: "No diff strategy available",
+				}
+
+				// Unlike a single-file edit, a transaction doesn't keep the blocks that matched
+				// when others failed, since that would leave the files half-edited.
+				if (!diffResult.success || diffResult.failParts?.some((part) => !part.success)) {
+					const currentCount = (task.consecutiveMistakeCountForApplyDiff.get(relPath) || 0) + 1
+					task.consecutiveMistakeCountForApplyDiff.set(relPath, currentCount)
+					TelemetryService.instance.captureDiffApplicationError(task.taskId, currentCount)
+					repeatedFailure ||= currentCount >= 2
+					errors.push(formatDiffError(absolutePath, diffResult))
+					continue
+				}
+
+				files.push({ relPath, absolutePath, diff, originalContent, newContent: diffResult.content })
+			}
+
+			if (errors.length > 0) {
+				const formattedError = `Unable to apply the diffs as a transaction, so no files were changed.\n\n${errors.join(
+					"\n\n",
+				)}\n\nFix the failing diffs, then apply the changes to all files again in a single apply_diff.`
+
+				task.consecutiveMistakeCount++
+
+				if (repeatedFailure) {
+					await task.say("diff_error", formattedError)
+				}
+
+				task.recordToolError("apply_diff", formattedError)
+				pushToolResult(formattedError)
+				return
+			}
+
+			task.consecutiveMistakeCount = 0
+
+			for (const file of files) {
+				task.consecutiveMistakeCountForApplyDiff.delete(file.relPath)
+			}
+
+			if (await isDryRunEnabled(task)) {
+				const results: string[] = []
+
+				for (const file of files) {
+					results.push(await stagePendingEdit(task, file.relPath, file.newContent))
+				}
+
+				pushToolResult(results.join("\n\n"))
+				return
+			}
+
+			const isWriteProtected = files.some((file) => task.rooProtectedController?.isWriteProtected(file.relPath))
+
+			const completeMessage = JSON.stringify({
+				tool: "appliedDiff",
+				path: getReadablePath(task.cwd, files[0].relPath),
+				batchDiffs: files.map((file) => {
+					const unifiedPatch = sanitizeUnifiedDiff(
+						formatResponse.createPrettyPatch(file.relPath, file.originalContent, file.newContent),
+					)
+
+					return {
+						path: getReadablePath(task.cwd, file.relPath),
+						changeCount: (file.diff.match(/<<<<<<< SEARCH/g) || []).length,
+						key: file.relPath,
+						content: unifiedPatch,
+						diffStats: computeDiffStats(unifiedPatch) || undefined,
+						diffs: [{ content: file.diff }],
+					}
+				}),
+				isOutsideWorkspace: files.some((file) => isPathOutsideWorkspace(file.absolutePath)),
+				isProtected: isWriteProtected,
+			} satisfies ClineSayTool)
+
+			const didApprove = await askApproval("tool", completeMessage, undefined, isWriteProtected)
+
+			if (!didApprove) {
+				this.resetPartialState()
+				task.processQueuedMessages()
+				return
+			}
+
+			const provider = task.providerRef.deref()
+			const state = await provider?.getState()
+			const diagnosticsEnabled = state?.diagnosticsEnabled ?? true
+			const preDiagnostics = vscode.languages.getDiagnostics()
+			const written: TransactionFile[] = []
+
+			try {
+				for (const file of files) {
+					// Recorded before writing, since a failed write may still have changed the file.
+					written.push(file)
+					await fs.writeFile(file.absolutePath, file.newContent, "utf-8")
+				}
+			} catch (error) {
+				const failedFile = written[written.length - 1]
+				const unrestored = await this.restoreFiles(written)
+				let formattedError = `Failed to write ${failedFile.relPath}: ${
+					error instanceof Error ? error.message : String(error)
+				}\n\n`
+
+				if (unrestored.length === 0) {
+					formattedError += "All files were restored to their contents before the edit, so no files were changed."
+				} else {
+					formattedError += `These files could not be restored to their contents before the edit: ${unrestored.join(
+						", ",
+					)}.${task.enableCheckpoints ? " They can be restored from the checkpoint saved before the edit." : ""}`
+				}
+
+				await task.say("error", formattedError)
+				task.recordToolError("apply_diff", formattedError)
+				pushToolResult(formatResponse.toolError(formattedError))
+				this.resetPartialState()
+				task.processQueuedMessages()
+				return
+			}
+
+			let problems = ""
+
+			if (diagnosticsEnabled) {
+				// Open the documents in memory so that diagnostics are computed for them.
+				await Promise.all(
+					files.map((file) => vscode.workspace.openTextDocument(vscode.Uri.file(file.absolutePath))),
+				)
+
+				try {
+					await delay(Math.max(0, state?.writeDelayMs ?? DEFAULT_WRITE_DELAY_MS))
+				} catch (error) {
+					console.warn(`Failed to apply write delay: ${error}`)
+				}
+
+				problems = await diagnosticsToProblemsString(
+					getNewDiagnostics(preDiagnostics, vscode.languages.getDiagnostics()),
+					[vscode.DiagnosticSeverity.Error],
+					task.cwd,
+					state?.includeDiagnosticMessages ?? true,
+					state?.maxDiagnosticMessages ?? 50,
+				)
+			}
+
+			for (const file of files) {
+				await task.fileContextTracker.trackFileContext(file.relPath, "roo_edited" as RecordSource)
+			}
+
+			// Used to determine if we should wait for busy terminal to update before sending api request
+			task.didEditFile = true
+
+			pushToolResult(
+				JSON.stringify({
+					files: files.map((file) => ({ path: file.relPath, operation: "modified" })),
+					notice: "All diffs were applied. You do not need to re-read the files, as you have seen all changes. Proceed with the task using these changes as the new baseline.",
+					...(problems ? { problems: `\n\nNew problems detected after saving the files:\n${problems}` } : {}),
+				}),
+			)
+
+			this.resetPartialState()
+			task.processQueuedMessages()
+		} catch (error) {
+			await handleError("applying diffs", error as Error)
+			this.resetPartialState()
+			task.processQueuedMessages()
+		}
+	}
+
+	// Restores the files to their original contents, returning the paths of those that could not be restored.
+	private async restoreFiles(files: TransactionFile[]): Promise<string[]> {
+		const unrestored: string[] = []
+
+		for (const file of files) {
+			try {
+				await fs.writeFile(file.absolutePath, file.originalContent, "utf-8")
+			} catch (error) {
+				console.error(`[ApplyDiffTool] Failed to restore ${file.relPath}:`, error)
+				unrestored.push(file.relPath)
+			}
+		}
+
+		return unrestored
+	}
+
 	override async handlePartial(task: Task, block: ToolUse<"apply_diff">): Promise<void> {
 		const relPath: string | undefined = block.params.path
 		const diffContent: string | undefined = block.params.diff
@@ -278,6 +555,11 @@ export class ApplyDiffTool extends BaseTool<"apply_diff"> {
 			return
 		}
 
+		// Staged edits are shown once the tool runs.
+		if (await isDryRunEnabled(task)) {
+			return
+		}
+
 		const sharedMessageProps: ClineSayTool = {
 			tool: "appliedDiff",
 			path: getReadablePath(task.cwd, relPath),
@@ -80,6 +80,13 @@ export class AttemptCompletionTool extends BaseTool<"attempt_completion"> {
 
 			await task.say("completion_result", result, undefined, false)
 
+			// Parallel subtasks return their result to the parent once they complete.
+			if (task.isBackgroundTask) {
+				pushToolResult("")
+				this.emitTaskCompleted(task)
+				return
+			}
+
 			// Check for subtask using parentTaskId (metadata-driven delegation)
 			if (task.parentTaskId) {
 				// Check if this subtask has already completed and returned to parent
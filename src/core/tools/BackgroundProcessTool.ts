@@ -0,0 +1,163 @@
+import fs from "fs/promises"
+import * as path from "path"
+
+import { Task } from "../task/Task"
+import { formatResponse } from "../prompts/responses"
+import { unescapeHtmlEntities } from "../../utils/text-normalization"
+import { formatBackgroundProcess } from "../../integrations/terminal/BackgroundProcessManager"
+import type { ToolUse } from "../../shared/tools"
+
+import { BaseTool, ToolCallbacks } from "./BaseTool"
+
+// Output returned per read; the model can read again for more.
+const OUTPUT_LINE_LIMIT = 200
+const OUTPUT_CHARACTER_LIMIT = 20_000
+// Gives a process time to print its startup output, or to fail, before the result is returned
+const STARTUP_WAIT_MS = 3_000
+
+interface BackgroundProcessParams {
+	action: "start" | "list" | "read_output" | "stop"
+	command?: string | null
+	cwd?: string | null
+	process_id?: number | null
+}
+
+export class BackgroundProcessTool extends BaseTool<"background_process"> {
+	readonly name = "background_process" as const
+
+	async execute(params: BackgroundProcessParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
+		const { askApproval, handleError, pushToolResult } = callbacks
+
+		try {
+			switch (params.action) {
+				case "start":
+					return await this.start(params, task, askApproval, pushToolResult)
+				case "list": {
+					const processes = task.backgroundProcesses.list()
+					task.consecutiveMistakeCount = 0
+					pushToolResult(
+						processes.length > 0
+							? processes.map(formatBackgroundProcess).join("\n")
+							: "No background processes have been started in this task.",
+					)
+					return
+				}
+				case "read_output":
+				case "stop": {
+					const processId = params.process_id
+
+					if (typeof processId !== "number") {
+						task.consecutiveMistakeCount++
+						task.recordToolError("background_process")
+						pushToolResult(await task.sayAndCreateMissingParamError("background_process", "process_id"))
+						return
+					}
+
+					if (!task.backgroundProcesses.get(processId)) {
+						task.consecutiveMistakeCount++
+						task.recordToolError("background_process")
+						pushToolResult(
+							formatResponse.toolError(
+								`There is no background process ${processId}. Use the list action to see the processes.`,
+							),
+						)
+						return
+					}
+
+					task.consecutiveMistakeCount = 0
+
+					if (params.action === "stop") {
+						const info = await task.backgroundProcesses.stop(processId)
+						pushToolResult(formatBackgroundProcess(info!))
+						return
+					}
+
+					pushToolResult(await this.readOutput(task, processId))
+					return
+				}
+				default:
+					task.consecutiveMistakeCount++
+					task.recordToolError("background_process")
+					pushToolResult(await task.sayAndCreateMissingParamError("background_process", "action"))
+			}
+		} catch (error) {
+			await handleError("managing background process", error as Error)
+		}
+	}
+
+	private async start(
+		params: BackgroundProcessParams,
+		task: Task,
+		askApproval: ToolCallbacks["askApproval"],
+		pushToolResult: ToolCallbacks["pushToolResult"],
+	): Promise<void> {
+		if (!params.command?.trim()) {
+			task.consecutiveMistakeCount++
+			task.recordToolError("background_process")
+			pushToolResult(await task.sayAndCreateMissingParamError("background_process", "command"))
+			return
+		}
+
+		const command = unescapeHtmlEntities(params.command.trim())
+		const ignoredFileAttemptedToAccess = task.rooIgnoreController?.validateCommand(command)
+
+		if (ignoredFileAttemptedToAccess) {
+			await task.say("rooignore_error", ignoredFileAttemptedToAccess)
+			pushToolResult(formatResponse.rooIgnoreError(ignoredFileAttemptedToAccess))
+			return
+		}
+
+		const workingDir = params.cwd ? path.resolve(task.cwd, params.cwd) : task.cwd
+
+		try {
+			await fs.access(workingDir)
+		} catch {
+			task.consecutiveMistakeCount++
+			task.recordToolError("background_process")
+			pushToolResult(formatResponse.toolError(`Working directory '${workingDir}' does not exist.`))
+			return
+		}
+
+		task.consecutiveMistakeCount = 0
+
+		// Asking as a command applies the command auto-approval and the allowed and denied commands.
+		const didApprove = await askApproval("command", command)
+
+		if (!didApprove) {
+			return
+		}
+
+		const { id } = task.backgroundProcesses.start(command, workingDir)
+		await task.backgroundProcesses.waitForExit(id, STARTUP_WAIT_MS)
+
+		task.recordToolUsage("background_process")
+		pushToolResult(
+			`Started in ${workingDir}. Read its output with the read_output action and stop it with the stop action when it is no longer needed.\n\n${await this.readOutput(task, id)}`,
+		)
+	}
+
+	private async readOutput(task: Task, processId: number): Promise<string> {
+		const { output, isNew } = task.backgroundProcesses.readOutput(
+			processId,
+			OUTPUT_LINE_LIMIT,
+			OUTPUT_CHARACTER_LIMIT,
+		)!
+		const info = formatBackgroundProcess(task.backgroundProcesses.get(processId)!)
+
+		if (!output.trim()) {
+			return `${info}\nNo output yet.`
+		}
+
+		await task.say("command_output", output)
+
+		return `${info}\n${isNew ? "New output" : "No new output since the last read. Last output"}:\n${output}`
+	}
+
+	override async handlePartial(task: Task, block: ToolUse<"background_process">): Promise<void> {
+		if (block.params.action === "start" && block.params.command) {
+			await task.ask("command", block.params.command, block.partial).catch(() => {})
+		}
+	}
+}
+
+export const backgroundProcessTool = new BackgroundProcessTool()
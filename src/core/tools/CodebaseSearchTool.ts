@@ -5,7 +5,7 @@ import { Task } from "../task/Task"
 import { CodeIndexManager } from "../../services/code-index/manager"
 import { getWorkspacePath } from "../../utils/path"
 import { formatResponse } from "../prompts/responses"
-import { VectorStoreSearchResult } from "../../services/code-index/interfaces"
+import { WorkspaceSearchResult } from "../../services/code-index/interfaces"
 import type { ToolUse } from "../../shared/tools"
 
 import { BaseTool, ToolCallbacks } from "./BaseTool"
@@ -13,6 +13,8 @@ import { BaseTool, ToolCallbacks } from "./BaseTool"
 interface CodebaseSearchParams {
 	query: string
 	path?: string
+	language?: string
+	kind?: string
 }
 
 export class CodebaseSearchTool extends BaseTool<"codebase_search"> {
@@ -20,7 +22,7 @@ export class CodebaseSearchTool extends BaseTool<"codebase_search"> {
 
 	async execute(params: CodebaseSearchParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
 		const { askApproval, handleError, pushToolResult } = callbacks
-		const { query, path: directoryPrefix } = params
+		const { query, path: directoryPrefix, language, kind } = params
 
 		const workspacePath = task.cwd && task.cwd.trim() !== "" ? task.cwd : getWorkspacePath()
 
@@ -40,6 +42,8 @@ export class CodebaseSearchTool extends BaseTool<"codebase_search"> {
 			tool: "codebaseSearch",
 			query: query,
 			path: directoryPrefix,
+			language,
+			kind,
 			isOutsideWorkspace: false,
 		}
 
@@ -70,10 +74,17 @@ export class CodebaseSearchTool extends BaseTool<"codebase_search"> {
 				throw new Error("Code Indexing is not configured (Missing OpenAI Key or Qdrant URL).")
 			}
 
-			const searchResults: VectorStoreSearchResult[] = await manager.searchIndex(query, directoryPrefix)
+			// Search the index of every workspace folder, not only the one holding the active editor
+			const searchResults: WorkspaceSearchResult[] = await CodeIndexManager.searchAllWorkspaces(
+				query,
+				directoryPrefix,
+				{ language, kind },
+			)
 
 			if (!searchResults || searchResults.length === 0) {
-				pushToolResult(`No relevant code snippets found for the query: "${query}"`)
+				// Points indexed before the language and kind fields existed never match these filters
+				const filterNote = language || kind ? ". Try again without the language or kind filter" : ""
+				pushToolResult(`No relevant code snippets found for the query: "${query}"${filterNote}`)
 				return
 			}
 
@@ -88,17 +99,31 @@ export class CodebaseSearchTool extends BaseTool<"codebase_search"> {
 					startLine: number
 					endLine: number
 					codeChunk: string
+					workspaceRoot?: string
 				}>
 			}
 
+			const workspaceFolders = vscode.workspace.workspaceFolders ?? []
+			const isMultiRoot = workspaceFolders.length > 1
+
 			searchResults.forEach((result) => {
 				if (!result.payload) return
 				if (!("filePath" in result.payload)) return
 
-				const relativePath = vscode.workspace.asRelativePath(result.payload.filePath, false)
+				// In multi-root workspaces, paths are made relative to the task's cwd so they
+				// can be used with other tools, and each result names the folder it belongs to
+				const absolutePath = path.resolve(result.workspacePath, result.payload.filePath)
+				const relativePath = isMultiRoot
+					? path.relative(workspacePath, absolutePath).toPosix()
+					: vscode.workspace.asRelativePath(result.payload.filePath, false)
+				const workspaceRoot = isMultiRoot
+					? (workspaceFolders.find((folder) => folder.uri.fsPath === result.workspacePath)?.name ??
+						path.basename(result.workspacePath))
+					: undefined
 
 				jsonResult.results.push({
 					filePath: relativePath,
+					...(workspaceRoot && { workspaceRoot }),
 					score: result.score,
 					startLine: result.payload.startLine,
 					endLine: result.payload.endLine,
@@ -115,7 +140,7 @@ Results:
 ${jsonResult.results
 	.map(
 		(result) => `File path: ${result.filePath}
-Score: ${result.score}
+${result.workspaceRoot ? `Workspace root: ${result.workspaceRoot}\n` : ""}Score: ${result.score}
 Lines: ${result.startLine}-${result.endLine}
 Code Chunk: ${result.codeChunk}
 `,
@@ -136,6 +161,8 @@ Code Chunk: ${result.codeChunk}
 			tool: "codebaseSearch",
 			query: query,
 			path: directoryPrefix,
+			language: block.params.language,
+			kind: block.params.kind,
 			isOutsideWorkspace: false,
 		}
 
@@ -0,0 +1,266 @@
+import path from "path"
+
+import { type ClineSayTool, DEFAULT_WRITE_DELAY_MS } from "@roo-code/types"
+
+import { getReadablePath } from "../../utils/path"
+import { isPathOutsideWorkspace } from "../../utils/pathUtils"
+import { Task } from "../task/Task"
+import { formatResponse } from "../prompts/responses"
+import { RecordSource } from "../context-tracking/FileContextTrackerTypes"
+import { EXPERIMENT_IDS, experiments } from "../../shared/experiments"
+import { sanitizeUnifiedDiff, computeDiffStats } from "../diff/stats"
+import { isDryRunEnabled, stagePendingEdit } from "../pending-edits/review"
+import {
+	editNotebookCell,
+	isNotebookPath,
+	type NotebookCellType,
+	type NotebookEditMode,
+} from "../../integrations/misc/notebook"
+import type { ToolUse } from "../../shared/tools"
+
+import { BaseTool, ToolCallbacks } from "./BaseTool"
+
+interface EditNotebookParams {
+	path: string
+	cell_index: number
+	new_source?: string | null
+	cell_type?: NotebookCellType | null
+	edit_mode: NotebookEditMode
+}
+
+export class EditNotebookTool extends BaseTool<"edit_notebook"> {
+	readonly name = "edit_notebook" as const
+
+	async execute(params: EditNotebookParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
+		const { path: relPath, cell_index: cellIndex, new_source: newSource, cell_type: cellType } = params
+		const editMode = params.edit_mode
+		const { askApproval, handleError, pushToolResult } = callbacks
+
+		try {
+			// Validate required parameters
+			if (!relPath) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("edit_notebook")
+				pushToolResult(await task.sayAndCreateMissingParamError("edit_notebook", "path"))
+				return
+			}
+
+			if (cellIndex === undefined) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("edit_notebook")
+				pushToolResult(await task.sayAndCreateMissingParamError("edit_notebook", "cell_index"))
+				return
+			}
+
+			if (!editMode) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("edit_notebook")
+				pushToolResult(await task.sayAndCreateMissingParamError("edit_notebook", "edit_mode"))
+				return
+			}
+
+			if (editMode !== "delete" && (newSource === undefined || newSource === null)) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("edit_notebook")
+				pushToolResult(await task.sayAndCreateMissingParamError("edit_notebook", "new_source"))
+				return
+			}
+
+			if (!isNotebookPath(relPath)) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("edit_notebook")
+				pushToolResult(
+					formatResponse.toolError(
+						`'${relPath}' is not a Jupyter notebook. edit_notebook only edits .ipynb files; use apply_diff or edit for other files.`,
+					),
+				)
+				return
+			}
+
+			const accessAllowed = task.rooIgnoreController?.validateAccess(relPath)
+
+			if (!accessAllowed) {
+				await task.say("rooignore_error", relPath)
+				pushToolResult(formatResponse.rooIgnoreError(relPath))
+				return
+			}
+
+			// Check if file is write-protected
+			const isWriteProtected = task.rooProtectedController?.isWriteProtected(relPath) || false
+
+			const absolutePath = path.resolve(task.cwd, relPath)
+
+			const fileExists = await task.pendingEdits.exists(absolutePath)
+			if (!fileExists) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("edit_notebook")
+				const errorMessage = `File not found: ${relPath}. Use write_to_file to create a new notebook.`
+				await task.say("error", errorMessage)
+				pushToolResult(formatResponse.toolError(errorMessage))
+				return
+			}
+
+			let fileContent: string
+			try {
+				fileContent = await task.pendingEdits.readFile(absolutePath)
+			} catch (error) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("edit_notebook")
+				const errorMessage = `Failed to read file '${relPath}'. Please verify file permissions and try again.`
+				await task.say("error", errorMessage)
+				pushToolResult(formatResponse.toolError(errorMessage))
+				return
+			}
+
+			let newContent: string
+			try {
+				newContent = editNotebookCell(fileContent, {
+					cellIndex,
+					editMode,
+					newSource: newSource?.replace(/\r\n/g, "\n"),
+					cellType: cellType ?? undefined,
+				})
+			} catch (error) {
+				task.consecutiveMistakeCount++
+				const errorMessage =
+					error instanceof SyntaxError
+						? `'${relPath}' is not valid JSON, so its cells can't be edited: ${error.message}`
+						: (error as Error).message
+				task.recordToolError("edit_notebook", errorMessage)
+				pushToolResult(formatResponse.toolError(errorMessage))
+				return
+			}
+
+			// Check if any changes were made
+			if (newContent === fileContent) {
+				pushToolResult(`No changes needed for '${relPath}'`)
+				return
+			}
+
+			task.consecutiveMistakeCount = 0
+
+			if (await isDryRunEnabled(task)) {
+				pushToolResult(await stagePendingEdit(task, relPath, newContent))
+				return
+			}
+
+			// Initialize diff view
+			task.diffViewProvider.editType = "modify"
+			task.diffViewProvider.originalContent = fileContent
+
+			// Generate and validate diff
+			const diff = formatResponse.createPrettyPatch(relPath, fileContent, newContent)
+			if (!diff) {
+				pushToolResult(`No changes needed for '${relPath}'`)
+				await task.diffViewProvider.reset()
+				return
+			}
+
+			// Check if preventFocusDisruption experiment is enabled
+			const provider = task.providerRef.deref()
+			const state = await provider?.getState()
+			const diagnosticsEnabled = state?.diagnosticsEnabled ?? true
+			const writeDelayMs = state?.writeDelayMs ?? DEFAULT_WRITE_DELAY_MS
+			const isPreventFocusDisruptionEnabled = experiments.isEnabled(
+				state?.experiments ?? {},
+				EXPERIMENT_IDS.PREVENT_FOCUS_DISRUPTION,
+			)
+
+			const sanitizedDiff = sanitizeUnifiedDiff(diff)
+			const diffStats = computeDiffStats(sanitizedDiff) || undefined
+			const isOutsideWorkspace = isPathOutsideWorkspace(absolutePath)
+
+			const sharedMessageProps: ClineSayTool = {
+				tool: "appliedDiff",
+				path: getReadablePath(task.cwd, relPath),
+				diff: sanitizedDiff,
+				isOutsideWorkspace,
+			}
+
+			const completeMessage = JSON.stringify({
+				...sharedMessageProps,
+				content: sanitizedDiff,
+				isProtected: isWriteProtected,
+				diffStats,
+			} satisfies ClineSayTool)
+
+			// Show diff view if focus disruption prevention is disabled
+			if (!isPreventFocusDisruptionEnabled) {
+				await task.diffViewProvider.open(relPath)
+				await task.diffViewProvider.update(newContent, true)
+				task.diffViewProvider.scrollToFirstDiff()
+			}
+
+			const didApprove = await askApproval("tool", completeMessage, undefined, isWriteProtected)
+
+			if (!didApprove) {
+				// Revert changes if diff view was shown
+				if (!isPreventFocusDisruptionEnabled) {
+					await task.diffViewProvider.revertChanges()
+				}
+				pushToolResult("Changes were rejected by the user.")
+				await task.diffViewProvider.reset()
+				return
+			}
+
+			// Save the changes
+			if (isPreventFocusDisruptionEnabled) {
+				// Direct file write without diff view or opening the file
+				await task.diffViewProvider.saveDirectly(relPath, newContent, false, diagnosticsEnabled, writeDelayMs)
+			} else {
+				// Call saveChanges to update the DiffViewProvider properties
+				await task.diffViewProvider.saveChanges(diagnosticsEnabled, writeDelayMs)
+			}
+
+			// Track file edit operation
+			await task.fileContextTracker.trackFileContext(relPath, "roo_edited" as RecordSource)
+
+			task.didEditFile = true
+
+			// Get the formatted response message
+			const message = await task.diffViewProvider.pushToolWriteResult(task, task.cwd, false)
+			pushToolResult(message)
+
+			// Record successful tool usage and cleanup
+			task.recordToolUsage("edit_notebook")
+			await task.diffViewProvider.reset()
+			this.resetPartialState()
+
+			// Process any queued messages after file edit completes
+			task.processQueuedMessages()
+		} catch (error) {
+			await handleError("edit_notebook", error as Error)
+			await task.diffViewProvider.reset()
+			this.resetPartialState()
+		}
+	}
+
+	override async handlePartial(task: Task, block: ToolUse<"edit_notebook">): Promise<void> {
+		const relPath: string | undefined = block.params.path
+
+		// Wait for path to stabilize before showing UI (prevents truncated paths)
+		if (!this.hasPathStabilized(relPath)) {
+			return
+		}
+
+		// Staged edits are shown once the tool runs.
+		if (await isDryRunEnabled(task)) {
+			return
+		}
+
+		// relPath is guaranteed non-null after hasPathStabilized
+		const absolutePath = path.resolve(task.cwd, relPath!)
+		const isOutsideWorkspace = isPathOutsideWorkspace(absolutePath)
+
+		const sharedMessageProps: ClineSayTool = {
+			tool: "appliedDiff",
+			path: getReadablePath(task.cwd, relPath!),
+			diff: block.params.cell_index !== undefined ? `cell ${block.params.cell_index}` : undefined,
+			isOutsideWorkspace,
+		}
+
+		await task.ask("tool", JSON.stringify(sharedMessageProps), block.partial).catch(() => {})
+	}
+}
+
+export const editNotebookTool = new EditNotebookTool()
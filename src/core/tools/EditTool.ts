@@ -1,4 +1,3 @@
-import fs from "fs/promises"
 import path from "path"
 
 import { type ClineSayTool, DEFAULT_WRITE_DELAY_MS } from "@roo-code/types"
@@ -8,9 +7,9 @@ import { isPathOutsideWorkspace } from "../../utils/pathUtils"
 import { Task } from "../task/Task"
 import { formatResponse } from "../prompts/responses"
 import { RecordSource } from "../context-tracking/FileContextTrackerTypes"
-import { fileExistsAtPath } from "../../utils/fs"
 import { EXPERIMENT_IDS, experiments } from "../../shared/experiments"
 import { sanitizeUnifiedDiff, computeDiffStats } from "../diff/stats"
+import { isDryRunEnabled, stagePendingEdit } from "../pending-edits/review"
 import type { ToolUse } from "../../shared/tools"
 
 import { BaseTool, ToolCallbacks } from "./BaseTool"
@@ -77,7 +76,7 @@ export class EditTool extends BaseTool<"edit"> {
 
 			const absolutePath = path.resolve(task.cwd, relPath)
 
-			const fileExists = await fileExistsAtPath(absolutePath)
+			const fileExists = await task.pendingEdits.exists(absolutePath)
 			if (!fileExists) {
 				task.consecutiveMistakeCount++
 				task.recordToolError("edit")
@@ -89,7 +88,7 @@ export class EditTool extends BaseTool<"edit"> {
 
 			let fileContent: string
 			try {
-				fileContent = await fs.readFile(absolutePath, "utf8")
+				fileContent = await task.pendingEdits.readFile(absolutePath)
 				// Normalize line endings to LF for consistent matching
 				fileContent = fileContent.replace(/\r\n/g, "\n")
 			} catch (error) {
@@ -150,6 +149,11 @@ export class EditTool extends BaseTool<"edit"> {
 
 			task.consecutiveMistakeCount = 0
 
+			if (await isDryRunEnabled(task)) {
+				pushToolResult(await stagePendingEdit(task, relPath, newContent))
+				return
+			}
+
 			// Initialize diff view
 			task.diffViewProvider.editType = "modify"
 			task.diffViewProvider.originalContent = fileContent
@@ -251,6 +255,11 @@ export class EditTool extends BaseTool<"edit"> {
 			return
 		}
 
+		// Staged edits are shown once the tool runs.
+		if (await isDryRunEnabled(task)) {
+			return
+		}
+
 		// relPath is guaranteed non-null after hasPathStabilized
 		const absolutePath = path.resolve(task.cwd, relPath!)
 		const isOutsideWorkspace = isPathOutsideWorkspace(absolutePath)
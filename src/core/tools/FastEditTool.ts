@@ -0,0 +1,264 @@
+import path from "path"
+
+import { type ClineSayTool, type ProviderSettings, DEFAULT_WRITE_DELAY_MS } from "@roo-code/types"
+
+import { getReadablePath } from "../../utils/path"
+import { isPathOutsideWorkspace } from "../../utils/pathUtils"
+import { Task } from "../task/Task"
+import { formatResponse } from "../prompts/responses"
+import { RecordSource } from "../context-tracking/FileContextTrackerTypes"
+import { EXPERIMENT_IDS, experiments } from "../../shared/experiments"
+import { sanitizeUnifiedDiff, computeDiffStats } from "../diff/stats"
+import { fastApply } from "../diff/fast-apply"
+import { isDryRunEnabled, stagePendingEdit } from "../pending-edits/review"
+import type { ToolUse } from "../../shared/tools"
+
+import { BaseTool, ToolCallbacks } from "./BaseTool"
+
+interface FastEditParams {
+	path: string
+	instructions: string
+	code_edit: string
+}
+
+export class FastEditTool extends BaseTool<"fast_edit"> {
+	readonly name = "fast_edit" as const
+
+	async execute(params: FastEditParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
+		const { path: relPath, instructions, code_edit: codeEdit } = params
+		const { askApproval, handleError, pushToolResult } = callbacks
+
+		try {
+			// Validate required parameters
+			if (!relPath) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("fast_edit")
+				pushToolResult(await task.sayAndCreateMissingParamError("fast_edit", "path"))
+				return
+			}
+
+			if (!codeEdit) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("fast_edit")
+				pushToolResult(await task.sayAndCreateMissingParamError("fast_edit", "code_edit"))
+				return
+			}
+
+			const accessAllowed = task.rooIgnoreController?.validateAccess(relPath)
+
+			if (!accessAllowed) {
+				await task.say("rooignore_error", relPath)
+				pushToolResult(formatResponse.rooIgnoreError(relPath))
+				return
+			}
+
+			// Check if file is write-protected
+			const isWriteProtected = task.rooProtectedController?.isWriteProtected(relPath) || false
+
+			const absolutePath = path.resolve(task.cwd, relPath)
+
+			const fileExists = await task.pendingEdits.exists(absolutePath)
+			if (!fileExists) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("fast_edit")
+				const errorMessage = `File not found: ${relPath}. Use write_to_file to create new files.`
+				await task.say("error", errorMessage)
+				pushToolResult(formatResponse.toolError(errorMessage))
+				return
+			}
+
+			let fileContent: string
+			try {
+				fileContent = await task.pendingEdits.readFile(absolutePath)
+			} catch (error) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("fast_edit")
+				const errorMessage = `Failed to read file '${relPath}'. Please verify file permissions and try again.`
+				await task.say("error", errorMessage)
+				pushToolResult(formatResponse.toolError(errorMessage))
+				return
+			}
+
+			// Merge with LF line endings and restore the file's line endings afterwards
+			const usesCrlf = fileContent.includes("\r\n")
+			const normalizedContent = fileContent.replace(/\r\n/g, "\n")
+
+			const provider = task.providerRef.deref()
+			const state = await provider?.getState()
+
+			const result = await fastApply(
+				await this.getFastApplyConfiguration(task, state?.fastApplyApiConfigId, state?.listApiConfigMeta),
+				normalizedContent,
+				instructions ?? "",
+				codeEdit.replace(/\r\n/g, "\n"),
+			)
+
+			if (!result.success) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("fast_edit", result.error)
+				await task.say("error", `Fast apply failed for '${relPath}': ${result.error}`)
+				pushToolResult(
+					formatResponse.toolError(
+						`${result.error} The file was not changed. Retry with more context around the changes, or use apply_diff instead.`,
+					),
+				)
+				return
+			}
+
+			const newContent = usesCrlf ? result.content.replace(/\n/g, "\r\n") : result.content
+
+			// Check if any changes were made
+			if (newContent === fileContent) {
+				pushToolResult(`No changes needed for '${relPath}'`)
+				return
+			}
+
+			task.consecutiveMistakeCount = 0
+
+			if (await isDryRunEnabled(task)) {
+				pushToolResult(await stagePendingEdit(task, relPath, newContent))
+				return
+			}
+
+			// Initialize diff view
+			task.diffViewProvider.editType = "modify"
+			task.diffViewProvider.originalContent = fileContent
+
+			// Generate and validate diff
+			const diff = formatResponse.createPrettyPatch(relPath, fileContent, newContent)
+			if (!diff) {
+				pushToolResult(`No changes needed for '${relPath}'`)
+				await task.diffViewProvider.reset()
+				return
+			}
+
+			// Check if preventFocusDisruption experiment is enabled
+			const diagnosticsEnabled = state?.diagnosticsEnabled ?? true
+			const writeDelayMs = state?.writeDelayMs ?? DEFAULT_WRITE_DELAY_MS
+			const isPreventFocusDisruptionEnabled = experiments.isEnabled(
+				state?.experiments ?? {},
+				EXPERIMENT_IDS.PREVENT_FOCUS_DISRUPTION,
+			)
+
+			const sanitizedDiff = sanitizeUnifiedDiff(diff)
+			const diffStats = computeDiffStats(sanitizedDiff) || undefined
+			const isOutsideWorkspace = isPathOutsideWorkspace(absolutePath)
+
+			const sharedMessageProps: ClineSayTool = {
+				tool: "appliedDiff",
+				path: getReadablePath(task.cwd, relPath),
+				diff: sanitizedDiff,
+				isOutsideWorkspace,
+			}
+
+			const completeMessage = JSON.stringify({
+				...sharedMessageProps,
+				content: sanitizedDiff,
+				isProtected: isWriteProtected,
+				diffStats,
+			} satisfies ClineSayTool)
+
+			// Show diff view if focus disruption prevention is disabled
+			if (!isPreventFocusDisruptionEnabled) {
+				await task.diffViewProvider.open(relPath)
+				await task.diffViewProvider.update(newContent, true)
+				task.diffViewProvider.scrollToFirstDiff()
+			}
+
+			const didApprove = await askApproval("tool", completeMessage, undefined, isWriteProtected)
+
+			if (!didApprove) {
+				// Revert changes if diff view was shown
+				if (!isPreventFocusDisruptionEnabled) {
+					await task.diffViewProvider.revertChanges()
+				}
+				pushToolResult("Changes were rejected by the user.")
+				await task.diffViewProvider.reset()
+				return
+			}
+
+			// Save the changes
+			if (isPreventFocusDisruptionEnabled) {
+				// Direct file write without diff view or opening the file
+				await task.diffViewProvider.saveDirectly(relPath, newContent, false, diagnosticsEnabled, writeDelayMs)
+			} else {
+				// Call saveChanges to update the DiffViewProvider properties
+				await task.diffViewProvider.saveChanges(diagnosticsEnabled, writeDelayMs)
+			}
+
+			// Track file edit operation
+			await task.fileContextTracker.trackFileContext(relPath, "roo_edited" as RecordSource)
+
+			task.didEditFile = true
+
+			// Get the formatted response message
+			const message = await task.diffViewProvider.pushToolWriteResult(task, task.cwd, false)
+			pushToolResult(message)
+
+			// Record successful tool usage and cleanup
+			task.recordToolUsage("fast_edit")
+			await task.diffViewProvider.reset()
+			this.resetPartialState()
+
+			// Process any queued messages after file edit completes
+			task.processQueuedMessages()
+		} catch (error) {
+			await handleError("fast_edit", error as Error)
+			await task.diffViewProvider.reset()
+			this.resetPartialState()
+		}
+	}
+
+	/**
+	 * Uses the API configuration profile chosen for fast apply, falling back to
+	 * the task's own configuration when none is set or it no longer exists.
+	 */
+	private async getFastApplyConfiguration(
+		task: Task,
+		fastApplyApiConfigId: string | undefined,
+		listApiConfigMeta: { id: string }[] = [],
+	): Promise<ProviderSettings> {
+		const provider = task.providerRef.deref()
+
+		if (provider && fastApplyApiConfigId && listApiConfigMeta.find(({ id }) => id === fastApplyApiConfigId)) {
+			const { name: _, ...providerSettings } = await provider.providerSettingsManager.getProfile({
+				id: fastApplyApiConfigId,
+			})
+
+			if (providerSettings.apiProvider) {
+				return providerSettings
+			}
+		}
+
+		return task.apiConfiguration
+	}
+
+	override async handlePartial(task: Task, block: ToolUse<"fast_edit">): Promise<void> {
+		const relPath: string | undefined = block.params.path
+
+		// Wait for path to stabilize before showing UI (prevents truncated paths)
+		if (!this.hasPathStabilized(relPath)) {
+			return
+		}
+
+		// Staged edits are shown once the tool runs.
+		if (await isDryRunEnabled(task)) {
+			return
+		}
+
+		// relPath is guaranteed non-null after hasPathStabilized
+		const absolutePath = path.resolve(task.cwd, relPath!)
+		const isOutsideWorkspace = isPathOutsideWorkspace(absolutePath)
+
+		const sharedMessageProps: ClineSayTool = {
+			tool: "appliedDiff",
+			path: getReadablePath(task.cwd, relPath!),
+			diff: block.params.instructions,
+			isOutsideWorkspace,
+		}
+
+		await task.ask("tool", JSON.stringify(sharedMessageProps), block.partial).catch(() => {})
+	}
+}
+
+export const fastEditTool = new FastEditTool()
@@ -0,0 +1,137 @@
+import * as vscode from "vscode"
+import path from "path"
+
+import { Task } from "../task/Task"
+import { CodeIndexManager } from "../../services/code-index/manager"
+import { getWorkspacePath } from "../../utils/path"
+import { formatResponse } from "../prompts/responses"
+import type { ToolUse } from "../../shared/tools"
+
+import { BaseTool, ToolCallbacks } from "./BaseTool"
+
+// Keeps the result small enough for the context window; narrow with `path` for more
+const MAX_REFERENCES = 100
+
+interface FindReferencesParams {
+	symbol: string
+	path?: string
+}
+
+export class FindReferencesTool extends BaseTool<"find_references"> {
+	readonly name = "find_references" as const
+
+	async execute(params: FindReferencesParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
+		const { askApproval, handleError, pushToolResult } = callbacks
+		const { symbol, path: directoryPrefix } = params
+
+		const workspacePath = task.cwd && task.cwd.trim() !== "" ? task.cwd : getWorkspacePath()
+
+		if (!workspacePath) {
+			await handleError("find_references", new Error("Could not determine workspace path."))
+			return
+		}
+
+		if (!symbol) {
+			task.consecutiveMistakeCount++
+			task.didToolFailInCurrentTurn = true
+			pushToolResult(await task.sayAndCreateMissingParamError("find_references", "symbol"))
+			return
+		}
+
+		const sharedMessageProps = {
+			tool: "findReferences",
+			symbol,
+			path: directoryPrefix,
+			isOutsideWorkspace: false,
+		}
+
+		const didApprove = await askApproval("tool", JSON.stringify(sharedMessageProps))
+		if (!didApprove) {
+			pushToolResult(formatResponse.toolDenied())
+			return
+		}
+
+		task.consecutiveMistakeCount = 0
+
+		try {
+			const context = task.providerRef.deref()?.context
+			if (!context) {
+				throw new Error("Extension context is not available.")
+			}
+
+			const manager = CodeIndexManager.getInstance(context)
+
+			if (!manager) {
+				throw new Error("CodeIndexManager is not available.")
+			}
+
+			if (!manager.isFeatureEnabled) {
+				throw new Error("Code Indexing is disabled in the settings.")
+			}
+			if (!manager.isFeatureConfigured) {
+				throw new Error("Code Indexing is not configured (Missing OpenAI Key or Qdrant URL).")
+			}
+
+			const { definitions, references } = CodeIndexManager.findReferencesInAllWorkspaces(symbol, directoryPrefix)
+
+			if (definitions.length === 0 && references.length === 0) {
+				pushToolResult(
+					`No definitions or references found for "${symbol}". The symbol graph only covers files indexed with tree-sitter; use search_files for other files.`,
+				)
+				return
+			}
+
+			// Paths are made relative to the task's cwd so they can be used with other tools
+			const isMultiRoot = (vscode.workspace.workspaceFolders ?? []).length > 1
+			const displayPath = (location: { workspacePath: string; filePath: string }) =>
+				isMultiRoot
+					? path.relative(workspacePath, path.resolve(location.workspacePath, location.filePath)).toPosix()
+					: vscode.workspace.asRelativePath(location.filePath, false)
+
+			const calls = references.filter((reference) => reference.isCall)
+			const otherReferences = references.filter((reference) => !reference.isCall)
+			const shownCalls = calls.slice(0, MAX_REFERENCES)
+			const shownOthers = otherReferences.slice(0, Math.max(0, MAX_REFERENCES - shownCalls.length))
+			const hiddenCount = references.length - shownCalls.length - shownOthers.length
+
+			const formatDefinition = (definition: (typeof definitions)[number]) => {
+				const name = definition.container ? `${definition.container}.${definition.name}` : definition.name
+				return `${displayPath(definition)}:${definition.line} ${definition.kind} ${name}`
+			}
+			const formatReference = (reference: (typeof references)[number]) =>
+				`${displayPath(reference)}:${reference.line}${reference.caller ? ` in ${reference.caller}` : ""}`
+			const formatList = (items: string[]) => items.join("\n") || "None found"
+
+			const sections = [
+				`Symbol: ${symbol}`,
+				`Definitions (${definitions.length}):\n${formatList(definitions.map(formatDefinition))}`,
+				`Calls (${calls.length}):\n${formatList(shownCalls.map(formatReference))}`,
+				`Other references (${otherReferences.length}):\n${formatList(shownOthers.map(formatReference))}`,
+			]
+
+			if (hiddenCount > 0) {
+				sections.push(`${hiddenCount} more references not shown. Use the path parameter to narrow the results.`)
+			}
+
+			pushToolResult(sections.join("\n\n"))
+		} catch (error: any) {
+			await handleError("find_references", error)
+		}
+	}
+
+	override async handlePartial(task: Task, block: ToolUse<"find_references">): Promise<void> {
+		const symbol: string | undefined = block.params.symbol
+		const directoryPrefix: string | undefined = block.params.path
+
+		const sharedMessageProps = {
+			tool: "findReferences",
+			symbol,
+			path: directoryPrefix,
+			isOutsideWorkspace: false,
+		}
+
+		await task.ask("tool", JSON.stringify(sharedMessageProps), block.partial).catch(() => {})
+	}
+}
+
+export const findReferencesTool = new FindReferencesTool()
@@ -0,0 +1,157 @@
+import * as path from "path"
+
+import { Task } from "../task/Task"
+import { formatResponse } from "../prompts/responses"
+import { checkGitInstalled } from "../../utils/git"
+import {
+	type GitStep,
+	formatGitSteps,
+	planCommit,
+	planCreateBranch,
+	planPullRequest,
+	runGitSteps,
+} from "../../integrations/git/workflow"
+import type { ToolUse } from "../../shared/tools"
+
+import { BaseTool, ToolCallbacks } from "./BaseTool"
+
+export type GitAction = "create_branch" | "commit" | "create_pull_request"
+
+interface GitParams {
+	action: GitAction
+	branch?: string | null
+	files?: string[] | null
+	message?: string | null
+	title?: string | null
+	body?: string | null
+	base?: string | null
+}
+
+export class GitTool extends BaseTool<"git"> {
+	readonly name = "git" as const
+
+	async execute(params: GitParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
+		const { askApproval, handleError, pushToolResult } = callbacks
+
+		const missingParam = async (paramName: string) => {
+			task.consecutiveMistakeCount++
+			task.recordToolError("git")
+			pushToolResult(await task.sayAndCreateMissingParamError("git", paramName))
+		}
+
+		let steps: GitStep[]
+
+		try {
+			if (!(await checkGitInstalled())) {
+				pushToolResult(formatResponse.toolError("Git is not installed."))
+				return
+			}
+
+			switch (params.action) {
+				case "create_branch": {
+					const branch = params.branch?.trim()
+
+					if (!branch) {
+						return await missingParam("branch")
+					}
+
+					steps = await planCreateBranch(task.cwd, branch)
+					break
+				}
+				case "commit": {
+					const files = (params.files ?? []).map((file) => file.trim()).filter(Boolean)
+					const message = params.message?.trim()
+
+					if (files.length === 0) {
+						return await missingParam("files")
+					}
+
+					if (!message) {
+						return await missingParam("message")
+					}
+
+					for (const file of files) {
+						if (path.relative(task.cwd, path.resolve(task.cwd, file)).startsWith("..")) {
+							task.consecutiveMistakeCount++
+							task.recordToolError("git")
+							pushToolResult(formatResponse.toolError(`${file} is outside of the workspace.`))
+							return
+						}
+
+						if (!task.rooIgnoreController?.validateAccess(file)) {
+							await task.say("rooignore_error", file)
+							pushToolResult(formatResponse.rooIgnoreError(file))
+							return
+						}
+					}
+
+					steps = planCommit(files, message)
+					break
+				}
+				case "create_pull_request": {
+					const title = params.title?.trim()
+
+					if (!title) {
+						return await missingParam("title")
+					}
+
+					steps = await planPullRequest(task.cwd, {
+						title,
+						body: params.body?.trim() ?? "",
+						base: params.base?.trim() || undefined,
+					})
+					break
+				}
+				default:
+					return await missingParam("action")
+			}
+		} catch (error) {
+			// Invalid branch names, missing remotes and the like are for the model to fix.
+			task.consecutiveMistakeCount++
+			task.recordToolError("git")
+			pushToolResult(formatResponse.toolError(error instanceof Error ? error.message : String(error)))
+			return
+		}
+
+		task.consecutiveMistakeCount = 0
+
+		// Asking as a command applies the command auto-approval and the allowed and denied commands.
+		const didApprove = await askApproval("command", formatGitSteps(steps))
+
+		if (!didApprove) {
+			return
+		}
+
+		try {
+			const abortController = new AbortController()
+			const abortCheck = setInterval(() => task.abort && abortController.abort(), 500)
+			const result = await runGitSteps(task.cwd, steps, abortController.signal).finally(() =>
+				clearInterval(abortCheck),
+			)
+
+			await task.say("command_output", result.output)
+
+			if (result.failed) {
+				task.didToolFailInCurrentTurn = true
+				pushToolResult(
+					formatResponse.toolError(
+						`\`${result.failed.command}\` failed with exit code ${result.failed.exitCode ?? "unknown"}. No later steps were run.\n\n${result.output}`,
+					),
+				)
+				return
+			}
+
+			task.recordToolUsage("git")
+			pushToolResult(formatResponse.toolResult(result.output))
+		} catch (error) {
+			await handleError("running git", error as Error)
+		}
+	}
+
+	override async handlePartial(task: Task, block: ToolUse<"git">): Promise<void> {
+		// The commands are only known once every parameter has arrived.
+		return
+	}
+}
+
+export const gitTool = new GitTool()
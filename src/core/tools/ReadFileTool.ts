@@ -13,7 +13,7 @@ import * as fs from "fs/promises"
 import { isBinaryFile } from "isbinaryfile"
 
 import type { ReadFileParams, ReadFileMode, ReadFileToolParams, FileEntry, LineRange } from "@roo-code/types"
-import { isLegacyReadFileParams, type ClineSayTool } from "@roo-code/types"
+import { DEFAULT_READ_FILE_TOKEN_BUDGET, isLegacyReadFileParams, type ClineSayTool } from "@roo-code/types"
 
 import { Task } from "../task/Task"
 import { formatResponse } from "../prompts/responses"
@@ -22,7 +22,9 @@ import { isPathOutsideWorkspace } from "../../utils/pathUtils"
 import { getReadablePath } from "../../utils/path"
 import { extractTextFromFile, addLineNumbers, getSupportedBinaryFormats } from "../../integrations/misc/extract-text"
 import { readWithIndentation, readWithSlice } from "../../integrations/misc/indentation-reader"
+import { formatNotebook, isNotebookPath } from "../../integrations/misc/notebook"
 import { DEFAULT_LINE_LIMIT } from "../prompts/tools/native-tools/read_file"
+import { parseSourceCodeDefinitionsForFile } from "../../services/tree-sitter"
 import type { ToolUse, PushToolResult } from "../../shared/tools"
 
 import {
@@ -33,6 +35,7 @@ import {
 	processImageFile,
 	ImageMemoryTracker,
 } from "./helpers/imageHelpers"
+import { splitIntoChunks, formatChunkedRead } from "./helpers/chunkedRead"
 import { BaseTool, ToolCallbacks } from "./BaseTool"
 
 // ─── Types ────────────────────────────────────────────────────────────────────
@@ -171,6 +174,7 @@ export class ReadFileTool extends BaseTool<"read_file"> {
 			const {
 				maxImageFileSize = DEFAULT_MAX_IMAGE_FILE_SIZE_MB,
 				maxTotalImageSize = DEFAULT_MAX_TOTAL_IMAGE_SIZE_MB,
+				readFileTokenBudget = DEFAULT_READ_FILE_TOKEN_BUDGET,
 			} = state ?? {}
 
 			for (const fileResult of fileResults) {
@@ -181,9 +185,12 @@ export class ReadFileTool extends BaseTool<"read_file"> {
 				const entry = fileResult.entry!
 
 				try {
+					// Edits staged in dry-run mode are read instead of the file on disk
+					const stagedContent = task.pendingEdits.get(fullPath)?.newContent
+
 					// Check if path is a directory
-					const stats = await fs.stat(fullPath)
-					if (stats.isDirectory()) {
+					const stats = stagedContent === undefined ? await fs.stat(fullPath) : undefined
+					if (stats?.isDirectory()) {
 						const errorMsg = `Cannot read '${relPath}' because it is a directory. Use list_files tool instead.`
 						updateFileResult(relPath, {
 							status: "error",
@@ -195,7 +202,7 @@ export class ReadFileTool extends BaseTool<"read_file"> {
 					}
 
 					// Check for binary file
-					const isBinary = await isBinaryFile(fullPath)
+					const isBinary = stagedContent === undefined && (await isBinaryFile(fullPath))
 
 					if (isBinary) {
 						await this.handleBinaryFile(
@@ -214,9 +221,11 @@ export class ReadFileTool extends BaseTool<"read_file"> {
 					// Read text file content with lossy UTF-8 conversion
 					// Reading as Buffer first allows graceful handling of non-UTF8 bytes
 					// (they become U+FFFD replacement characters instead of throwing)
-					const buffer = await fs.readFile(fullPath)
-					const fileContent = buffer.toString("utf-8")
-					const result = this.processTextFile(fileContent, entry)
+					const rawContent = stagedContent ?? (await fs.readFile(fullPath)).toString("utf-8")
+					const fileContent = isNotebookPath(relPath) ? this.formatNotebookContent(rawContent) : rawContent
+					const result =
+						(await this.readWithTokenBudget(task, fullPath, fileContent, entry, readFileTokenBudget)) ??
+						this.processTextFile(fileContent, entry)
 
 					await task.fileContextTracker.trackFileContext(relPath, "read_tool" as RecordSource)
 
@@ -263,6 +272,75 @@ export class ReadFileTool extends BaseTool<"read_file"> {
 		}
 	}
 
+	/**
+	 * Keeps slice reads within the token budget. Returns undefined when the read
+	 * fits, so it is processed as usual.
+	 *
+	 * A file read without offset or limit that exceeds the budget is returned as
+	 * an outline of its definitions plus the chunks to read it in. An explicit
+	 * range that exceeds the budget is cut down to its first chunk.
+	 */
+	private async readWithTokenBudget(
+		task: Task,
+		fullPath: string,
+		content: string,
+		entry: InternalFileEntry,
+		tokenBudget: number,
+	): Promise<string | undefined> {
+		if ((entry.mode ?? "slice") !== "slice" || tokenBudget <= 0) {
+			return undefined
+		}
+
+		const lines = content.split("\n")
+		const isWholeFile = entry.offset === undefined && entry.limit === undefined
+		const offset1 = entry.offset ?? 1
+		const selectedLines = isWholeFile
+			? lines
+			: lines.slice(offset1 - 1, offset1 - 1 + (entry.limit ?? DEFAULT_LINE_LIMIT))
+
+		if (selectedLines.length === 0) {
+			return undefined
+		}
+
+		const tokens = await task.api.countTokens([{ type: "text", text: selectedLines.join("\n") }])
+
+		if (tokens <= tokenBudget) {
+			return undefined
+		}
+
+		const chunks = splitIntoChunks(selectedLines, tokens, tokenBudget, offset1)
+
+		// A single line can't be split further, and is cut to MAX_LINE_LENGTH anyway.
+		if (chunks.length === 1) {
+			return undefined
+		}
+
+		if (!isWholeFile) {
+			const [{ startLine, endLine }] = chunks
+			const limit = endLine - startLine + 1
+			const result = this.processTextFile(content, { ...entry, offset: startLine, limit })
+			return `Note: The requested lines (~${tokens} tokens) exceed the read token budget of ${tokenBudget} tokens, so only lines ${startLine}-${endLine} are shown. Continue with offset=${endLine + 1}.\n\n${result}`
+		}
+
+		const outline = await parseSourceCodeDefinitionsForFile(fullPath, task.rooIgnoreController).catch(
+			() => undefined,
+		)
+
+		return formatChunkedRead({ totalLines: lines.length, totalTokens: tokens, tokenBudget, outline, chunks })
+	}
+
+	/**
+	 * Shows a notebook as numbered cells instead of JSON, so the model edits it
+	 * with edit_notebook. A notebook that can't be parsed is shown as is.
+	 */
+	private formatNotebookContent(content: string): string {
+		try {
+			return formatNotebook(content)
+		} catch {
+			return content
+		}
+	}
+
 	/**
 	 * Process a text file according to the requested mode.
 	 */
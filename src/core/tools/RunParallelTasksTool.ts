@@ -0,0 +1,127 @@
+import { DEFAULT_MAX_PARALLEL_SUBTASKS } from "@roo-code/types"
+
+import { Task } from "../task/Task"
+import { getModeBySlug } from "../../shared/modes"
+import { formatResponse } from "../prompts/responses"
+import {
+	formatParallelSubtaskResults,
+	runParallelSubtasks,
+	type ParallelSubtask,
+	type ParallelSubtaskResult,
+} from "../task/parallel-subtasks"
+import { BaseTool, ToolCallbacks } from "./BaseTool"
+import type { ToolUse } from "../../shared/tools"
+
+interface RunParallelTasksParams {
+	tasks: ParallelSubtask[]
+}
+
+export class RunParallelTasksTool extends BaseTool<"run_parallel_tasks"> {
+	readonly name = "run_parallel_tasks" as const
+
+	async execute(params: RunParallelTasksParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
+		const { tasks } = params
+		const { askApproval, handleError, pushToolResult } = callbacks
+
+		try {
+			if (!Array.isArray(tasks) || tasks.length === 0) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("run_parallel_tasks")
+				task.didToolFailInCurrentTurn = true
+				pushToolResult(await task.sayAndCreateMissingParamError("run_parallel_tasks", "tasks"))
+				return
+			}
+
+			const provider = task.providerRef.deref()
+
+			if (!provider) {
+				pushToolResult(formatResponse.toolError("Provider reference lost"))
+				return
+			}
+
+			const state = await provider.getState()
+
+			const incomplete = tasks.findIndex((subtask) => !subtask?.mode || !subtask?.message)
+
+			if (incomplete !== -1) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("run_parallel_tasks")
+				task.didToolFailInCurrentTurn = true
+				pushToolResult(formatResponse.toolError(`Task ${incomplete + 1} needs both a mode and a message.`))
+				return
+			}
+
+			const invalidMode = tasks.find((subtask) => !getModeBySlug(subtask.mode, state?.customModes))
+
+			if (invalidMode) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("run_parallel_tasks")
+				task.didToolFailInCurrentTurn = true
+				pushToolResult(formatResponse.toolError(`Invalid mode: ${invalidMode.mode}`))
+				return
+			}
+
+			task.consecutiveMistakeCount = 0
+
+			// Un-escape one level of backslashes before '@' for hierarchical subtasks, as new_task does.
+			const subtasks = tasks.map(({ mode, message }) => ({ mode, message: message.replace(/\\\\@/g, "\\@") }))
+
+			const toolMessage = JSON.stringify({
+				tool: "runParallelTasks",
+				subtasks: tasks.map(({ mode, message }) => ({
+					mode: getModeBySlug(mode, state?.customModes)?.name ?? mode,
+					message,
+				})),
+			})
+
+			const didApprove = await askApproval("tool", toolMessage)
+
+			if (!didApprove) {
+				return
+			}
+
+			const results = await runParallelSubtasks(task, subtasks, {
+				maxConcurrency: state?.maxParallelSubtasks ?? DEFAULT_MAX_PARALLEL_SUBTASKS,
+				createSubtask: ({ mode, message }) => provider.createBackgroundTask({ message, mode, parent: task }),
+				onSettled: (result, index) => this.reportResult(task, result, index, subtasks.length),
+			})
+
+			task.recordToolUsage("run_parallel_tasks")
+			pushToolResult(formatParallelSubtaskResults(results))
+		} catch (error) {
+			await handleError("running parallel subtasks", error)
+		}
+	}
+
+	private async reportResult(task: Task, result: ParallelSubtaskResult, index: number, total: number) {
+		if (result.status === "completed" && result.taskId) {
+			await task.providerRef.deref()?.completeBackgroundTask(result.taskId)
+		}
+
+		if (task.abort) {
+			return
+		}
+
+		const text =
+			result.status === "completed"
+				? result.result
+				: `Subtask ${index + 1} of ${total} (${result.mode} mode) failed. ${result.result}`
+
+		await task.say(result.status === "completed" ? "subtask_result" : "error", text)
+	}
+
+	override async handlePartial(task: Task, block: ToolUse<"run_parallel_tasks">): Promise<void> {
+		const tasks = block.nativeArgs?.tasks
+
+		const partialMessage = JSON.stringify({
+			tool: "runParallelTasks",
+			subtasks: Array.isArray(tasks)
+				? tasks.map((subtask) => ({ mode: subtask?.mode ?? "", message: subtask?.message ?? "" }))
+				: [],
+		})
+
+		await task.ask("tool", partialMessage, block.partial).catch(() => {})
+	}
+}
+
+export const runParallelTasksTool = new RunParallelTasksTool()
@@ -1,6 +1,7 @@
 import { Task } from "../task/Task"
 import { formatResponse } from "../prompts/responses"
 import { getCommand, getCommandNames } from "../../services/command/commands"
+import { expandCommand } from "../../services/command/arguments"
 import { EXPERIMENT_IDS, experiments } from "../../shared/experiments"
 import { BaseTool, ToolCallbacks } from "./BaseTool"
 import type { ToolUse } from "../../shared/tools"
@@ -126,8 +127,17 @@ export class RunSlashCommandTool extends BaseTool<"run_slash_command"> {
 				result += `\nProvided arguments: ${args}`
 			}
 
+			// Commands with named arguments return their prompt with the arguments filled in.
+			const { prompt, missing } = command.arguments
+				? expandCommand(command, args ?? "")
+				: { prompt: command.content, missing: [] }
+
+			if (missing.length > 0) {
+				result += `\nMissing arguments: ${missing.join(", ")}`
+			}
+
 			result += `\nSource: ${command.source}`
-			result += `\n\n--- Command Content ---\n\n${command.content}`
+			result += `\n\n--- Command Content ---\n\n${prompt}`
 
 			// Return the command content as the tool result
 			pushToolResult(result)
@@ -0,0 +1,229 @@
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+import * as vscode from "vscode"
+import { execa } from "execa"
+
+import { Task } from "../task/Task"
+import { formatResponse } from "../prompts/responses"
+import { unescapeHtmlEntities } from "../../utils/text-normalization"
+import { truncateOutput } from "../../integrations/misc/extract-text"
+import { Terminal } from "../../integrations/terminal/Terminal"
+import {
+	type TestCommand,
+	detectTestCommand,
+	inferTestFramework,
+	withStructuredOutput,
+} from "../../integrations/test-runner/detect"
+import { type TestResults, parseTestResults } from "../../integrations/test-runner/parse"
+import { Package } from "../../shared/package"
+import type { ToolUse } from "../../shared/tools"
+
+import { BaseTool, ToolCallbacks } from "./BaseTool"
+
+// Keeps the result small; the model can run a single file with `path` to see the rest.
+const MAX_REPORTED_FAILURES = 20
+const MAX_FAILURE_MESSAGE_LINES = 30
+// Output shown to the model when the results can't be parsed
+const OUTPUT_TAIL_LINES = 100
+const OUTPUT_TAIL_CHARACTERS = 10_000
+const OUTPUT_UPDATE_INTERVAL_MS = 500
+
+interface RunTestsParams {
+	path?: string | null
+	command?: string | null
+}
+
+interface TestRun {
+	output: string
+	exitCode?: number
+	timedOut: boolean
+	cancelled: boolean
+}
+
+export function formatTestRunResult(
+	{ command, framework }: TestCommand,
+	run: TestRun,
+	results: TestResults | undefined,
+): string {
+	const header = `Ran \`${command}\`${framework === "unknown" ? "" : ` (${framework})`}.`
+
+	if (run.timedOut || run.cancelled) {
+		const reason = run.timedOut ? "exceeding the command execution timeout" : "the task was cancelled"
+		return `${header} The tests were stopped after ${reason}. Output so far:\n${truncateOutput(
+			run.output,
+			OUTPUT_TAIL_LINES,
+			OUTPUT_TAIL_CHARACTERS,
+		)}`
+	}
+
+	const exitStatus = `Exit code: ${run.exitCode ?? "unknown"}`
+
+	// Without results, or when the run failed without a failing test (e.g. a syntax error), show the output.
+	if (!results || (results.failed === 0 && run.exitCode !== 0)) {
+		return `${header} ${exitStatus}. The test results could not be parsed. Output:\n${truncateOutput(
+			run.output,
+			OUTPUT_TAIL_LINES,
+			OUTPUT_TAIL_CHARACTERS,
+		)}`
+	}
+
+	const counts = `${results.failed} failed, ${results.passed} passed, ${results.skipped} skipped`
+
+	if (results.failed === 0) {
+		return `${header} All tests passed (${counts}). ${exitStatus}`
+	}
+
+	const failures = results.failures.slice(0, MAX_REPORTED_FAILURES).map((failure, index) => {
+		const location = failure.file ? `${failure.file}${failure.line ? `:${failure.line}` : ""} - ` : ""
+		const lines = failure.message.split("\n")
+		const message = lines.slice(0, MAX_FAILURE_MESSAGE_LINES)
+
+		if (lines.length > MAX_FAILURE_MESSAGE_LINES) {
+			message.push(`[... ${lines.length - MAX_FAILURE_MESSAGE_LINES} more lines]`)
+		}
+
+		return `${index + 1}. ${location}${failure.name}\n${message.join("\n")}`
+	})
+
+	if (results.failures.length > MAX_REPORTED_FAILURES) {
+		failures.push(
+			`... ${results.failures.length - MAX_REPORTED_FAILURES} more failing tests. Run a single file or directory with the path parameter to see them.`,
+		)
+	}
+
+	return `${header} ${counts}. ${exitStatus}\n\nFailing tests:\n\n${failures.join("\n\n")}`
+}
+
+export class RunTestsTool extends BaseTool<"run_tests"> {
+	readonly name = "run_tests" as const
+
+	async execute(params: RunTestsParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
+		const { askApproval, handleError, pushToolResult } = callbacks
+		const testPath = params.path?.trim() || undefined
+		const customCommand = params.command?.trim() ? unescapeHtmlEntities(params.command.trim()) : undefined
+		const reportFile = path.join(os.tmpdir(), `roo-test-report-${task.taskId}-${Date.now()}.json`)
+
+		try {
+			const testCommand = customCommand
+				? withStructuredOutput(customCommand, inferTestFramework(customCommand), reportFile)
+				: await detectTestCommand(task.cwd, { testPath, reportFile })
+
+			if (!testCommand) {
+				task.consecutiveMistakeCount++
+				task.recordToolError("run_tests")
+				pushToolResult(
+					formatResponse.toolError(
+						"Could not detect how to run the tests in this workspace. Pass the test command in the command parameter.",
+					),
+				)
+				return
+			}
+
+			const ignoredFileAttemptedToAccess = task.rooIgnoreController?.validateCommand(testCommand.command)
+
+			if (ignoredFileAttemptedToAccess) {
+				await task.say("rooignore_error", ignoredFileAttemptedToAccess)
+				pushToolResult(formatResponse.rooIgnoreError(ignoredFileAttemptedToAccess))
+				return
+			}
+
+			task.consecutiveMistakeCount = 0
+
+			const didApprove = await askApproval("command", testCommand.command)
+
+			if (!didApprove) {
+				return
+			}
+
+			const run = await this.runTests(task, testCommand.command)
+			const report = testCommand.reportFile ? await this.readReport(testCommand.reportFile) : undefined
+			const results = parseTestResults(testCommand.framework, run.output, { cwd: task.cwd, report })
+
+			pushToolResult(formatTestRunResult(testCommand, run, results))
+		} catch (error) {
+			await handleError("running tests", error as Error)
+		} finally {
+			await fs.rm(reportFile, { force: true }).catch(() => {})
+		}
+	}
+
+	private async runTests(task: Task, command: string): Promise<TestRun> {
+		// Get command execution timeout from VSCode configuration (in seconds)
+		const timeoutSeconds = vscode.workspace.getConfiguration(Package.name).get<number>("commandExecutionTimeout", 0)
+		const abortController = new AbortController()
+		const abortCheck = setInterval(() => task.abort && abortController.abort(), OUTPUT_UPDATE_INTERVAL_MS)
+
+		const subprocess = execa({
+			shell: true,
+			cwd: task.cwd,
+			all: true,
+			reject: false,
+			// Ignore stdin to ensure non-interactive mode and prevent hanging
+			stdin: "ignore",
+			timeout: timeoutSeconds > 0 ? timeoutSeconds * 1000 : undefined,
+			cancelSignal: abortController.signal,
+			env: {
+				...process.env,
+				// Keep test runners out of watch mode and the output free of colors
+				CI: "1",
+				FORCE_COLOR: "0",
+				NO_COLOR: "1",
+			},
+		})`${command}`
+
+		let output = ""
+		let lastUpdate = 0
+		// Output messages are chained so that a late partial update can't follow the final one.
+		let sayChain: Promise<void> = Promise.resolve()
+
+		const sayOutput = (partial: boolean) => {
+			const text = Terminal.compressTerminalOutput(output)
+			sayChain = sayChain
+				.then(async () => {
+					await task.say("command_output", text, undefined, partial, undefined, undefined, {
+						isNonInteractive: true,
+					})
+				})
+				.catch((error) => console.error("[RunTestsTool] Failed to publish test output:", error))
+		}
+
+		subprocess.all?.on("data", (chunk: Buffer) => {
+			output += chunk.toString()
+
+			if (Date.now() - lastUpdate >= OUTPUT_UPDATE_INTERVAL_MS) {
+				lastUpdate = Date.now()
+				sayOutput(true)
+			}
+		})
+
+		try {
+			const result = await subprocess
+			output = typeof result.all === "string" ? result.all : output
+			sayOutput(false)
+			await sayChain
+
+			return { output, exitCode: result.exitCode, timedOut: result.timedOut, cancelled: result.isCanceled }
+		} finally {
+			clearInterval(abortCheck)
+		}
+	}
+
+	private async readReport(reportFile: string): Promise<unknown> {
+		try {
+			return JSON.parse(await fs.readFile(reportFile, "utf-8"))
+		} catch {
+			// The tests didn't get far enough to write a report
+			return undefined
+		}
+	}
+
+	override async handlePartial(task: Task, block: ToolUse<"run_tests">): Promise<void> {
+		// The detected command is only known once the tool runs.
+		if (block.params.command) {
+			await task.ask("command", block.params.command, block.partial).catch(() => {})
+		}
+	}
+}
+
+export const runTestsTool = new RunTestsTool()
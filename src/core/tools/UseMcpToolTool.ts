@@ -1,10 +1,14 @@
+import stringify from "safe-stable-stringify"
+
 import type { ClineAskUseMcpServer, McpExecutionStatus } from "@roo-code/types"
 
 import { Task } from "../task/Task"
 import { formatResponse } from "../prompts/responses"
+import { getEffectiveApiHistory } from "../condense"
 import { t } from "../../i18n"
 import type { ToolUse } from "../../shared/tools"
 import { toolNamesMatch } from "../../utils/mcp-name"
+import { sanitizeToolUseId } from "../../utils/tool-id"
 
 import { BaseTool, ToolCallbacks } from "./BaseTool"
 
@@ -27,7 +31,7 @@ export class UseMcpToolTool extends BaseTool<"use_mcp_tool"> {
 	readonly name = "use_mcp_tool" as const
 
 	async execute(params: UseMcpToolParams, task: Task, callbacks: ToolCallbacks): Promise<void> {
-		const { askApproval, handleError, pushToolResult } = callbacks
+		const { askApproval, handleError, pushToolResult, toolCallId } = callbacks
 
 		try {
 			// Validate parameters
@@ -74,6 +78,7 @@ export class UseMcpToolTool extends BaseTool<"use_mcp_tool"> {
 				parsedArguments,
 				executionId,
 				pushToolResult,
+				toolCallId,
 			)
 		} catch (error) {
 			await handleError("executing MCP tool", error as Error)
@@ -297,6 +302,7 @@ export class UseMcpToolTool extends BaseTool<"use_mcp_tool"> {
 		parsedArguments: Record<string, unknown> | undefined,
 		executionId: string,
 		pushToolResult: (content: string | Array<any>) => void,
+		toolCallId?: string,
 	): Promise<void> {
 		await task.say("mcp_server_request_started")
 
@@ -346,7 +352,59 @@ export class UseMcpToolTool extends BaseTool<"use_mcp_tool"> {
 		}
 
 		await task.say("mcp_server_response", toolResultPretty, images)
-		pushToolResult(formatResponse.toolResult(toolResultPretty, images))
+
+		const isDuplicate =
+			!!toolResult &&
+			!toolResult.isError &&
+			images.length === 0 &&
+			this.isDuplicateResult(task, serverName, toolName, parsedArguments, toolResultPretty, toolCallId)
+
+		pushToolResult(
+			isDuplicate
+				? formatResponse.duplicateMcpToolResult(serverName, toolName)
+				: formatResponse.toolResult(toolResultPretty, images),
+		)
+	}
+
+	/**
+	 * Whether a cacheable tool returned the same result as an earlier call with
+	 * the same arguments whose result is still in the context, in which case
+	 * the model can be referred to it instead of getting the payload again.
+	 */
+	private isDuplicateResult(
+		task: Task,
+		serverName: string,
+		toolName: string,
+		parsedArguments: Record<string, unknown> | undefined,
+		text: string,
+		toolCallId?: string,
+	): boolean {
+		const mcpHub = task.providerRef.deref()?.getMcpHub()
+
+		if (!toolCallId || !mcpHub?.isToolCacheable(serverName, toolName)) {
+			return false
+		}
+
+		const key = `${serverName}:${toolName}:${stringify(parsedArguments ?? {})}`
+		const previous = task.mcpToolResults.get(key)
+
+		if (previous && previous.text === text && this.hasToolResultInContext(task, previous.toolCallId)) {
+			return true
+		}
+
+		task.mcpToolResults.set(key, { toolCallId, text })
+		return false
+	}
+
+	private hasToolResultInContext(task: Task, toolCallId: string): boolean {
+		const toolUseId = sanitizeToolUseId(toolCallId)
+
+		return getEffectiveApiHistory(task.apiConversationHistory).some(
+			(message) =>
+				message.role === "user" &&
+				Array.isArray(message.content) &&
+				message.content.some((block) => block.type === "tool_result" && block.tool_use_id === toolUseId),
+		)
 	}
 }
 
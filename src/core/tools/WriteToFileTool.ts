@@ -14,6 +14,7 @@ import { isPathOutsideWorkspace } from "../../utils/pathUtils"
 import { unescapeHtmlEntities } from "../../utils/text-normalization"
 import { EXPERIMENT_IDS, experiments } from "../../shared/experiments"
 import { convertNewFileToUnifiedDiff, computeDiffStats, sanitizeUnifiedDiff } from "../diff/stats"
+import { isDryRunEnabled, stagePendingEdit } from "../pending-edits/review"
 import type { ToolUse } from "../../shared/tools"
 
 import { BaseTool, ToolCallbacks } from "./BaseTool"
@@ -56,6 +57,7 @@ export class WriteToFileTool extends BaseTool<"write_to_file"> {
 		}
 
 		const isWriteProtected = task.rooProtectedController?.isWriteProtected(relPath) || false
+		const isDryRun = await isDryRunEnabled(task)
 
 		let fileExists: boolean
 		const absolutePath = path.resolve(task.cwd, relPath)
@@ -69,7 +71,7 @@ export class WriteToFileTool extends BaseTool<"write_to_file"> {
 
 		// Create parent directories early for new files to prevent ENOENT errors
 		// in subsequent operations (e.g., diffViewProvider.open, fs.readFile)
-		if (!fileExists) {
+		if (!fileExists && !isDryRun) {
 			await createDirectoriesForFile(absolutePath)
 		}
 
@@ -99,6 +101,14 @@ export class WriteToFileTool extends BaseTool<"write_to_file"> {
 		try {
 			task.consecutiveMistakeCount = 0
 
+			if (isDryRun) {
+				const content = everyLineHasLineNumbers(newContent) ? stripLineNumbers(newContent) : newContent
+				pushToolResult(await stagePendingEdit(task, relPath, content))
+				await task.diffViewProvider.reset()
+				this.resetPartialState()
+				return
+			}
+
 			const provider = task.providerRef.deref()
 			const state = await provider?.getState()
 			const diagnosticsEnabled = state?.diagnosticsEnabled ?? true
@@ -209,7 +219,10 @@ export class WriteToFileTool extends BaseTool<"write_to_file"> {
 			EXPERIMENT_IDS.PREVENT_FOCUS_DISRUPTION,
 		)
 
-		if (isPreventFocusDisruptionEnabled) {
+		const isDryRun = experiments.isEnabled(state?.experiments ?? {}, EXPERIMENT_IDS.DRY_RUN_EDITS)
+
+		// Staged edits are shown once the tool runs.
+		if (isPreventFocusDisruptionEnabled || isDryRun) {
 			return
 		}
 
@@ -0,0 +1,251 @@
+// npx vitest src/core/tools/__tests__/applyDiffTool.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+import * as vscode from "vscode"
+
+import { checkAutoApproval } from "../../auto-approval"
+import { MultiSearchReplaceDiffStrategy } from "../../diff/strategies/multi-search-replace"
+import type { ToolUse } from "../../../shared/tools"
+import { PendingEdits } from "../../pending-edits/PendingEdits"
+import { applyDiffTool } from "../ApplyDiffTool"
+
+vi.mock("delay", () => ({
+	default: vi.fn(),
+}))
+
+vi.mock("@roo-code/telemetry", () => ({
+	TelemetryService: {
+		instance: {
+			captureDiffApplicationError: vi.fn(),
+		},
+	},
+}))
+
+vi.mock("vscode", () => ({
+	languages: {
+		getDiagnostics: vi.fn().mockReturnValue([]),
+	},
+	workspace: {
+		workspaceFolders: [],
+		openTextDocument: vi.fn().mockResolvedValue({}),
+	},
+	Uri: {
+		file: vi.fn((fsPath: string) => ({ fsPath })),
+	},
+	DiagnosticSeverity: {
+		Error: 0,
+	},
+}))
+
+const diff = (search: string, replace: string) =>
+	`<<<<<<< SEARCH\n:start_line:1\n-------\n${search}\n=======\n${replace}\n>>>>>>> REPLACE`
+
+describe("applyDiffTool transactions", () => {
+	let cwd: string
+	let mockTask: any
+	let mockAskApproval: ReturnType<typeof vi.fn>
+	let mockPushToolResult: ReturnType<typeof vi.fn>
+
+	const readFile = (relPath: string) => fs.readFile(path.join(cwd, relPath), "utf-8")
+
+	async function executeApplyDiff(files: Array<{ path: string; diff: string }>) {
+		const [first, ...rest] = files
+		const block: ToolUse<"apply_diff"> = {
+			type: "tool_use",
+			name: "apply_diff",
+			params: { path: first.path, diff: first.diff },
+			nativeArgs: { ...first, files: rest },
+			partial: false,
+		}
+
+		await applyDiffTool.handle(mockTask, block, {
+			askApproval: mockAskApproval,
+			handleError: vi.fn(),
+			pushToolResult: mockPushToolResult,
+		})
+
+		return mockPushToolResult.mock.calls[0]?.[0] as string | undefined
+	}
+
+	beforeEach(async () => {
+		vi.clearAllMocks()
+
+		cwd = await fs.mkdtemp(path.join(os.tmpdir(), "apply-diff-"))
+		await fs.writeFile(path.join(cwd, "a.ts"), "const a = 1\n")
+		await fs.writeFile(path.join(cwd, "b.ts"), "const b = 2\n")
+		;(vscode.workspace as any).workspaceFolders = [{ uri: { fsPath: cwd } }]
+
+		mockTask = {
+			cwd,
+			taskId: "task-1",
+			consecutiveMistakeCount: 0,
+			consecutiveMistakeCountForApplyDiff: new Map(),
+			didEditFile: false,
+			pendingEdits: new PendingEdits(),
+			enableCheckpoints: true,
+			api: { getModel: () => ({ id: "test-model" }) },
+			diffStrategy: new MultiSearchReplaceDiffStrategy(),
+			providerRef: {
+				deref: () => ({ getState: vi.fn().mockResolvedValue({ diagnosticsEnabled: true, writeDelayMs: 0 }) }),
+			},
+			rooIgnoreController: { validateAccess: vi.fn().mockReturnValue(true) },
+			rooProtectedController: { isWriteProtected: vi.fn().mockReturnValue(false) },
+			fileContextTracker: { trackFileContext: vi.fn().mockResolvedValue(undefined) },
+			say: vi.fn().mockResolvedValue(undefined),
+			recordToolError: vi.fn(),
+			recordToolUsage: vi.fn(),
+			processQueuedMessages: vi.fn(),
+			sayAndCreateMissingParamError: vi.fn().mockResolvedValue("Missing param error"),
+		}
+
+		mockAskApproval = vi.fn().mockResolvedValue(true)
+		mockPushToolResult = vi.fn()
+	})
+
+	afterEach(async () => {
+		vi.restoreAllMocks()
+		await fs.rm(cwd, { recursive: true, force: true })
+	})
+
+	it("should apply the diffs to all files after a single approval", async () => {
+		const result = await executeApplyDiff([
+			{ path: "a.ts", diff: diff("const a = 1", "const a = 10") },
+			{ path: "b.ts", diff: diff("const b = 2", "const b = 20") },
+		])
+
+		expect(mockAskApproval).toHaveBeenCalledTimes(1)
+		const message = JSON.parse(mockAskApproval.mock.calls[0][1])
+		expect(message.batchDiffs.map((file: { key: string }) => file.key)).toEqual(["a.ts", "b.ts"])
+		expect(message.isOutsideWorkspace).toBe(false)
+
+		expect(await readFile("a.ts")).toBe("const a = 10\n")
+		expect(await readFile("b.ts")).toBe("const b = 20\n")
+		expect(JSON.parse(result!).files).toEqual([
+			{ path: "a.ts", operation: "modified" },
+			{ path: "b.ts", operation: "modified" },
+		])
+		expect(mockTask.fileContextTracker.trackFileContext).toHaveBeenCalledTimes(2)
+		expect(mockTask.didEditFile).toBe(true)
+	})
+
+	it("should not change any file when a diff fails to match", async () => {
+		const result = await executeApplyDiff([
+			{ path: "a.ts", diff: diff("const a = 1", "const a = 10") },
+			{ path: "b.ts", diff: diff("const missing = 3", "const b = 20") },
+		])
+
+		expect(mockAskApproval).not.toHaveBeenCalled()
+		expect(result).toContain("no files were changed")
+		expect(result).toContain(`Unable to apply diff to file: ${path.join(cwd, "b.ts")}`)
+		expect(await readFile("a.ts")).toBe("const a = 1\n")
+		expect(await readFile("b.ts")).toBe("const b = 2\n")
+		expect(mockTask.consecutiveMistakeCount).toBe(1)
+	})
+
+	it("should reject a file that is listed more than once", async () => {
+		const result = await executeApplyDiff([
+			{ path: "a.ts", diff: diff("const a = 1", "const a = 10") },
+			{ path: "a.ts", diff: diff("const a = 1", "const a = 11") },
+		])
+
+		expect(result).toContain("The file a.ts is listed more than once")
+		expect(await readFile("a.ts")).toBe("const a = 1\n")
+	})
+
+	it("should not change any file when the user rejects the changes", async () => {
+		mockAskApproval.mockResolvedValue(false)
+
+		await executeApplyDiff([
+			{ path: "a.ts", diff: diff("const a = 1", "const a = 10") },
+			{ path: "b.ts", diff: diff("const b = 2", "const b = 20") },
+		])
+
+		expect(await readFile("a.ts")).toBe("const a = 1\n")
+		expect(await readFile("b.ts")).toBe("const b = 2\n")
+		expect(mockTask.didEditFile).toBe(false)
+	})
+
+	it("should restore the files written so far when a write fails", async () => {
+		const writeFile = fs.writeFile
+		vi.spyOn(fs, "writeFile").mockImplementation(async (file, data, options) => {
+			if (String(file).endsWith("b.ts") && data === "const b = 20\n") {
+				throw new Error("disk full")
+			}
+
+			return writeFile(file, data, options)
+		})
+
+		const result = await executeApplyDiff([
+			{ path: "a.ts", diff: diff("const a = 1", "const a = 10") },
+			{ path: "b.ts", diff: diff("const b = 2", "const b = 20") },
+		])
+
+		expect(result).toContain("Failed to write b.ts: disk full")
+		expect(result).toContain("All files were restored")
+		expect(await readFile("a.ts")).toBe("const a = 1\n")
+		expect(await readFile("b.ts")).toBe("const b = 2\n")
+		expect(mockTask.didEditFile).toBe(false)
+	})
+
+	it.each([
+		["a relative path", (outsidePath: string) => path.relative(cwd, outsidePath)],
+		["an absolute path", (outsidePath: string) => outsidePath],
+	])("should not auto-approve a transaction that writes outside the workspace through %s", async (_, toPath) => {
+		const outsideDir = await fs.mkdtemp(path.join(os.tmpdir(), "apply-diff-outside-"))
+		const outsidePath = path.join(outsideDir, "c.ts")
+		await fs.writeFile(outsidePath, "const c = 3\n")
+
+		try {
+			await executeApplyDiff([
+				{ path: "a.ts", diff: diff("const a = 1", "const a = 10") },
+				{ path: toPath(outsidePath), diff: diff("const c = 3", "const c = 30") },
+			])
+
+			const [ask, text, , isProtected] = mockAskApproval.mock.calls[0]
+			expect(JSON.parse(text).isOutsideWorkspace).toBe(true)
+
+			const state = { autoApprovalEnabled: true, alwaysAllowWrite: true }
+			expect(await checkAutoApproval({ state, ask, text, isProtected })).toEqual({ decision: "ask" })
+			expect(
+				await checkAutoApproval({
+					state: { ...state, alwaysAllowWriteOutsideWorkspace: true },
+					ask,
+					text,
+					isProtected,
+				}),
+			).toEqual({ decision: "approve" })
+		} finally {
+			await fs.rm(outsideDir, { recursive: true, force: true })
+		}
+	})
+
+	it("should stage the diffs without writing them in dry-run mode", async () => {
+		mockTask.providerRef = {
+			deref: () => ({ getState: vi.fn().mockResolvedValue({ experiments: { dryRunEdits: true } }) }),
+		}
+
+		const result = await executeApplyDiff([
+			{ path: "a.ts", diff: diff("const a = 1", "const a = 10") },
+			{ path: "b.ts", diff: diff("const b = 2", "const b = 20") },
+		])
+
+		expect(mockAskApproval).not.toHaveBeenCalled()
+		expect(result).toContain("The changes to a.ts were staged but not written to disk.")
+		expect(await readFile("a.ts")).toBe("const a = 1\n")
+		expect(await readFile("b.ts")).toBe("const b = 2\n")
+		expect(mockTask.pendingEdits.list().map(({ relPath }: { relPath: string }) => relPath)).toEqual([
+			"a.ts",
+			"b.ts",
+		])
+		expect(mockTask.didEditFile).toBe(false)
+
+		// A later diff applies to the staged content.
+		mockPushToolResult.mockClear()
+		await executeApplyDiff([{ path: "a.ts", diff: diff("const a = 10", "const a = 100") }])
+
+		expect(await mockTask.pendingEdits.readFile(path.join(cwd, "a.ts"))).toBe("const a = 100\n")
+		expect(mockTask.pendingEdits.get(path.join(cwd, "a.ts")).originalContent).toBe("const a = 1\n")
+	})
+})
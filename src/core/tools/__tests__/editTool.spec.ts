@@ -7,6 +7,7 @@ import { fileExistsAtPath } from "../../../utils/fs"
 import { isPathOutsideWorkspace } from "../../../utils/pathUtils"
 import { getReadablePath } from "../../../utils/path"
 import { ToolUse, ToolResponse } from "../../../shared/tools"
+import { PendingEdits } from "../../pending-edits/PendingEdits"
 import { editTool } from "../EditTool"
 
 vi.mock("fs/promises", () => ({
@@ -104,6 +105,7 @@ describe("editTool", () => {
 		mockTask.cwd = "/"
 		mockTask.consecutiveMistakeCount = 0
 		mockTask.didEditFile = false
+		mockTask.pendingEdits = new PendingEdits()
 		mockTask.providerRef = {
 			deref: vi.fn().mockReturnValue({
 				getState: vi.fn().mockResolvedValue({
@@ -26,6 +26,8 @@ import {
 } from "../helpers/imageHelpers"
 import { extractTextFromFile, addLineNumbers, getSupportedBinaryFormats } from "../../../integrations/misc/extract-text"
 import { readWithIndentation, readWithSlice } from "../../../integrations/misc/indentation-reader"
+import { parseSourceCodeDefinitionsForFile } from "../../../services/tree-sitter"
+import { PendingEdits } from "../../pending-edits/PendingEdits"
 
 // ─── Mocks ────────────────────────────────────────────────────────────────────
 
@@ -60,6 +62,10 @@ vi.mock("../../../integrations/misc/indentation-reader", () => ({
 	readWithSlice: vi.fn(),
 }))
 
+vi.mock("../../../services/tree-sitter", () => ({
+	parseSourceCodeDefinitionsForFile: vi.fn(),
+}))
+
 vi.mock("../helpers/imageHelpers", () => ({
 	DEFAULT_MAX_IMAGE_FILE_SIZE_MB: 5,
 	DEFAULT_MAX_TOTAL_IMAGE_SIZE_MB: 20,
@@ -124,6 +130,7 @@ const mockedReadWithIndentation = vi.mocked(readWithIndentation)
 const mockedIsSupportedImageFormat = vi.mocked(isSupportedImageFormat)
 const mockedValidateImageForProcessing = vi.mocked(validateImageForProcessing)
 const mockedProcessImageFile = vi.mocked(processImageFile)
+const mockedParseSourceCodeDefinitionsForFile = vi.mocked(parseSourceCodeDefinitionsForFile)
 
 // ─── Test Helpers ─────────────────────────────────────────────────────────────
 
@@ -132,10 +139,19 @@ interface MockTaskOptions {
 	rooIgnoreAllowed?: boolean
 	maxImageFileSize?: number
 	maxTotalImageSize?: number
+	readFileTokenBudget?: number
+	tokenCount?: number
 }
 
 function createMockTask(options: MockTaskOptions = {}) {
-	const { supportsImages = false, rooIgnoreAllowed = true, maxImageFileSize = 5, maxTotalImageSize = 20 } = options
+	const {
+		supportsImages = false,
+		rooIgnoreAllowed = true,
+		maxImageFileSize = 5,
+		maxTotalImageSize = 20,
+		readFileTokenBudget,
+		tokenCount = 10,
+	} = options
 
 	return {
 		cwd: "/test/workspace",
@@ -143,6 +159,7 @@ function createMockTask(options: MockTaskOptions = {}) {
 			getModel: vi.fn().mockReturnValue({
 				info: { supportsImages },
 			}),
+			countTokens: vi.fn().mockResolvedValue(tokenCount),
 		},
 		consecutiveMistakeCount: 0,
 		didToolFailInCurrentTurn: false,
@@ -157,11 +174,13 @@ function createMockTask(options: MockTaskOptions = {}) {
 		fileContextTracker: {
 			trackFileContext: vi.fn().mockResolvedValue(undefined),
 		},
+		pendingEdits: new PendingEdits(),
 		providerRef: {
 			deref: vi.fn().mockReturnValue({
 				getState: vi.fn().mockResolvedValue({
 					maxImageFileSize,
 					maxTotalImageSize,
+					readFileTokenBudget,
 				}),
 			}),
 		},
@@ -579,6 +598,72 @@ describe("ReadFileTool", () => {
 
 			expect(callbacks.pushToolResult).toHaveBeenCalledWith(expect.stringContaining("empty"))
 		})
+
+		it("should read the staged content of a file with staged edits", async () => {
+			const mockTask = createMockTask()
+			const callbacks = createMockCallbacks()
+
+			vi.spyOn(mockTask.pendingEdits, "get").mockReturnValue({
+				relPath: "new.ts",
+				absolutePath: "/test/workspace/new.ts",
+				newContent: "staged content",
+			})
+
+			await readFileTool.execute({ path: "new.ts" }, mockTask as any, callbacks)
+
+			expect(mockTask.pendingEdits.get).toHaveBeenCalledWith("/test/workspace/new.ts")
+			expect(mockedFsStat).not.toHaveBeenCalled()
+			expect(mockedFsReadFile).not.toHaveBeenCalled()
+			expect(mockedReadWithSlice.mock.calls[0][0]).toBe("staged content")
+		})
+	})
+
+	describe("token budget", () => {
+		const content = Array.from({ length: 100 }, (_, i) => `line ${i + 1}`).join("\n")
+
+		beforeEach(() => {
+			mockedIsBinaryFile.mockResolvedValue(false)
+			mockedFsReadFile.mockResolvedValue(Buffer.from(content))
+		})
+
+		it("should return an outline and chunks when the file exceeds the budget", async () => {
+			const mockTask = createMockTask({ readFileTokenBudget: 1000, tokenCount: 3000 })
+			const callbacks = createMockCallbacks()
+			mockedParseSourceCodeDefinitionsForFile.mockResolvedValue("# large.ts\n1--50 | function first()")
+
+			await readFileTool.execute({ path: "large.ts" }, mockTask as any, callbacks)
+
+			const result = callbacks.pushToolResult.mock.calls[0][0]
+			expect(mockedReadWithSlice).not.toHaveBeenCalled()
+			expect(result).toContain("more than the read token budget of 1000 tokens")
+			expect(result).toContain("1--50 | function first()")
+			expect(result).toContain("1. offset=1 limit=")
+			expect(result).toContain("3. offset=")
+		})
+
+		it("should cut an explicit range down to its first chunk", async () => {
+			const mockTask = createMockTask({ readFileTokenBudget: 1000, tokenCount: 2000 })
+			const callbacks = createMockCallbacks()
+
+			await readFileTool.execute({ path: "large.ts", offset: 11, limit: 50 }, mockTask as any, callbacks)
+
+			const [, offset0, limit] = mockedReadWithSlice.mock.calls[0]
+			expect(offset0).toBe(10)
+			expect(limit).toBeLessThan(50)
+			expect(callbacks.pushToolResult).toHaveBeenCalledWith(
+				expect.stringContaining(`Continue with offset=${11 + limit}`),
+			)
+		})
+
+		it("should read as usual when the file fits the budget or the budget is disabled", async () => {
+			for (const options of [{ tokenCount: 500 }, { readFileTokenBudget: 0, tokenCount: 100_000 }]) {
+				const callbacks = createMockCallbacks()
+
+				await readFileTool.execute({ path: "test.ts" }, createMockTask(options) as any, callbacks)
+
+				expect(callbacks.pushToolResult).toHaveBeenCalledWith(expect.stringContaining("1 | test content"))
+			}
+		})
 	})
 
 	describe("approval flow", () => {
@@ -0,0 +1,55 @@
+// npx vitest src/core/tools/__tests__/runTestsTool.spec.ts
+
+import { formatTestRunResult } from "../RunTestsTool"
+
+describe("formatTestRunResult", () => {
+	const testCommand = { framework: "vitest" as const, command: "npx vitest run" }
+	const run = { output: "raw output", exitCode: 1, timedOut: false, cancelled: false }
+
+	it("should list only the failing tests", () => {
+		const result = formatTestRunResult(testCommand, run, {
+			passed: 10,
+			failed: 1,
+			skipped: 0,
+			failures: [{ name: "math adds", file: "src/math.spec.ts", line: 12, message: "expected 3 to be 4" }],
+		})
+
+		expect(result).toContain("Ran `npx vitest run` (vitest). 1 failed, 10 passed, 0 skipped. Exit code: 1")
+		expect(result).toContain("1. src/math.spec.ts:12 - math adds\nexpected 3 to be 4")
+		expect(result).not.toContain("raw output")
+	})
+
+	it("should cap the number of failures and the length of their messages", () => {
+		const failures = Array.from({ length: 25 }, (_, i) => ({
+			name: `test ${i}`,
+			message: Array.from({ length: 40 }, (_, line) => `line ${line}`).join("\n"),
+		}))
+
+		const result = formatTestRunResult(testCommand, run, { passed: 0, failed: 25, skipped: 0, failures })
+
+		expect(result).toContain("20. test 19")
+		expect(result).not.toContain("21. test 20")
+		expect(result).toContain("[... 10 more lines]")
+		expect(result).toContain("... 5 more failing tests.")
+	})
+
+	it("should show the output when the results can't be parsed", () => {
+		expect(formatTestRunResult(testCommand, run, undefined)).toContain(
+			"The test results could not be parsed. Output:\nraw output",
+		)
+		expect(
+			formatTestRunResult(testCommand, run, { passed: 0, failed: 0, skipped: 0, failures: [] }),
+		).toContain("raw output")
+	})
+
+	it("should report passing runs and stopped runs", () => {
+		const passed = { passed: 3, failed: 0, skipped: 1, failures: [] }
+
+		expect(formatTestRunResult(testCommand, { ...run, exitCode: 0 }, passed)).toBe(
+			"Ran `npx vitest run` (vitest). All tests passed (0 failed, 3 passed, 1 skipped). Exit code: 0",
+		)
+		expect(formatTestRunResult(testCommand, { ...run, timedOut: true }, undefined)).toContain(
+			"The tests were stopped after exceeding the command execution timeout",
+		)
+	})
+})
@@ -23,6 +23,7 @@ vi.mock("../../prompts/responses", () => ({
 			const list = availableServers.length > 0 ? availableServers.join(", ") : "No servers available"
 			return `Server '${server}' is not configured. Available servers: ${list}`
 		}),
+		duplicateMcpToolResult: vi.fn((server: string, tool: string) => `Unchanged result of ${server}:${tool}`),
 	},
 }))
 
@@ -298,6 +299,88 @@ describe("useMcpToolTool", () => {
 		})
 	})
 
+	describe("duplicate results", () => {
+		const block: ToolUse = {
+			type: "tool_use",
+			name: "use_mcp_tool",
+			params: {
+				server_name: "test_server",
+				tool_name: "list_projects",
+				arguments: "{}",
+			},
+			nativeArgs: {
+				server_name: "test_server",
+				tool_name: "list_projects",
+				arguments: {},
+			},
+			partial: false,
+		}
+
+		let isToolCacheable: ReturnType<typeof vi.fn>
+
+		const callTool = (toolCallId: string) =>
+			useMcpToolTool.handle(mockTask as Task, block as any, {
+				askApproval: mockAskApproval,
+				handleError: mockHandleError,
+				pushToolResult: mockPushToolResult,
+				toolCallId,
+			})
+
+		// What presentAssistantMessage does once the turn's tool results are sent.
+		const addToolResultToHistory = (toolCallId: string) => {
+			mockTask.apiConversationHistory!.push({
+				role: "user",
+				content: [{ type: "tool_result", tool_use_id: toolCallId, content: "..." }],
+			})
+		}
+
+		beforeEach(() => {
+			mockAskApproval.mockResolvedValue(true)
+			isToolCacheable = vi.fn().mockReturnValue(true)
+
+			mockProviderRef.deref.mockReturnValue({
+				getMcpHub: () => ({
+					callTool: vi.fn().mockResolvedValue({ content: [{ type: "text", text: "project-a" }] }),
+					isToolCacheable,
+				}),
+				postMessageToWebview: vi.fn(),
+			})
+
+			mockTask.apiConversationHistory = []
+			mockTask.mcpToolResults = new Map()
+		})
+
+		it("refers to the earlier result when a cacheable tool returns it again", async () => {
+			await callTool("call-1")
+			addToolResultToHistory("call-1")
+			await callTool("call-2")
+
+			expect(isToolCacheable).toHaveBeenCalledWith("test_server", "list_projects")
+			expect(mockPushToolResult).toHaveBeenNthCalledWith(1, "Tool result: project-a")
+			expect(mockPushToolResult).toHaveBeenNthCalledWith(2, "Unchanged result of test_server:list_projects")
+			// The user still sees the full response.
+			expect(mockTask.say).toHaveBeenLastCalledWith("mcp_server_response", "project-a", [])
+		})
+
+		it("sends the result again once the earlier one is no longer in the context", async () => {
+			await callTool("call-1")
+			// e.g. the earlier result was condensed away
+			await callTool("call-2")
+
+			expect(mockPushToolResult).toHaveBeenNthCalledWith(2, "Tool result: project-a")
+		})
+
+		it("always sends the results of tools that aren't cacheable", async () => {
+			isToolCacheable.mockReturnValue(false)
+
+			await callTool("call-1")
+			addToolResultToHistory("call-1")
+			await callTool("call-2")
+
+			expect(mockPushToolResult).toHaveBeenNthCalledWith(2, "Tool result: project-a")
+		})
+	})
+
 	describe("error handling", () => {
 		it("should handle unexpected errors", async () => {
 			const block: ToolUse = {
@@ -50,7 +50,12 @@ export class AccessMcpResourceTool extends BaseTool<"access_mcp_resource"> {
 
 			// Now execute the tool
 			await task.say("mcp_server_request_started")
-			const resourceResult = await task.providerRef.deref()?.getMcpHub()?.readResource(server_name, uri)
+			const mcpHub = task.providerRef.deref()?.getMcpHub()
+			const resourceResult = await mcpHub?.readResource(server_name, uri)
+
+			if (mcpHub && resourceResult) {
+				task.mcpResourceTracker.trackResource(mcpHub, server_name, uri)
+			}
 
 			const resourceResultPretty =
 				resourceResult?.contents
@@ -0,0 +1,64 @@
+import { describe, it, expect } from "vitest"
+import { splitIntoChunks, formatChunkedRead, MAX_LISTED_CHUNKS } from "../chunkedRead"
+
+describe("chunkedRead", () => {
+	describe("splitIntoChunks", () => {
+		it("should split lines into consecutive chunks within the budget", () => {
+			// 10 lines of 9 characters plus newline, 10 tokens each
+			const lines = Array.from({ length: 10 }, () => "x".repeat(9))
+
+			const chunks = splitIntoChunks(lines, 100, 30, 5)
+
+			expect(chunks.map(({ startLine, endLine }) => [startLine, endLine])).toEqual([
+				[5, 7],
+				[8, 10],
+				[11, 13],
+				[14, 14],
+			])
+			expect(chunks.every(({ tokens }) => tokens <= 30)).toBe(true)
+		})
+
+		it("should keep a line that exceeds the budget on its own as a single chunk", () => {
+			const chunks = splitIntoChunks(["short", "x".repeat(100), "short"], 1000, 100)
+
+			expect(chunks.map(({ startLine, endLine }) => [startLine, endLine])).toEqual([
+				[1, 1],
+				[2, 2],
+				[3, 3],
+			])
+		})
+	})
+
+	describe("formatChunkedRead", () => {
+		it("should include the outline and how to read each chunk", () => {
+			const result = formatChunkedRead({
+				totalLines: 200,
+				totalTokens: 5000,
+				tokenBudget: 3000,
+				outline: "# app.ts\n1--120 | export class App",
+				chunks: [
+					{ startLine: 1, endLine: 120, tokens: 2900 },
+					{ startLine: 121, endLine: 200, tokens: 2100 },
+				],
+			})
+
+			expect(result).toContain("It has 200 lines (~5000 tokens), more than the read token budget of 3000 tokens.")
+			expect(result).toContain("1--120 | export class App")
+			expect(result).toContain("1. offset=1 limit=120 (lines 1-120, ~2900 tokens)")
+			expect(result).toContain("2. offset=121 limit=80 (lines 121-200, ~2100 tokens)")
+		})
+
+		it("should leave out the outline when there is none and cap the chunk list", () => {
+			const chunks = Array.from({ length: MAX_LISTED_CHUNKS + 5 }, (_, i) => ({
+				startLine: i * 10 + 1,
+				endLine: i * 10 + 10,
+				tokens: 100,
+			}))
+
+			const result = formatChunkedRead({ totalLines: 550, totalTokens: 5500, tokenBudget: 100, chunks })
+
+			expect(result).not.toContain("Outline")
+			expect(result).toContain(`... 5 more chunks, starting at offset=${MAX_LISTED_CHUNKS * 10 + 1}`)
+		})
+	})
+})
@@ -0,0 +1,107 @@
+import { MAX_LINE_LENGTH } from "../../prompts/tools/native-tools/read_file"
+
+/**
+ * Maximum number of chunks listed in a chunked read. Files with more chunks
+ * end the list with a note on how to continue past the last listed chunk.
+ */
+export const MAX_LISTED_CHUNKS = 50
+
+export interface FileChunk {
+	/** 1-based first line of the chunk */
+	startLine: number
+	/** 1-based last line of the chunk (inclusive) */
+	endLine: number
+	/** Estimated tokens of the chunk */
+	tokens: number
+}
+
+/**
+ * Splits lines into consecutive chunks of at most `chunkTokens` tokens each.
+ *
+ * Tokens are only counted once, for the whole text, so each line gets its
+ * share of `totalTokens` by length. Lines are cut to `MAX_LINE_LENGTH` when
+ * read, and counted that way. A single line that exceeds the budget on its
+ * own still makes a chunk.
+ *
+ * @param lines - The lines to split
+ * @param totalTokens - Tokens of `lines` joined with newlines
+ * @param chunkTokens - Token budget of a chunk
+ * @param firstLine - 1-based line number of `lines[0]` (default: 1)
+ */
+export function splitIntoChunks(
+	lines: string[],
+	totalTokens: number,
+	chunkTokens: number,
+	firstLine: number = 1,
+): FileChunk[] {
+	const totalChars = lines.reduce((sum, line) => sum + line.length + 1, 0)
+	const tokensPerChar = totalChars > 0 ? totalTokens / totalChars : 0
+	const lineTokens = (line: string) => (Math.min(line.length, MAX_LINE_LENGTH) + 1) * tokensPerChar
+
+	const chunks: FileChunk[] = []
+	let startIndex = 0
+	let tokens = 0
+
+	const pushChunk = (endIndex: number) =>
+		chunks.push({ startLine: firstLine + startIndex, endLine: firstLine + endIndex, tokens: Math.ceil(tokens) })
+
+	lines.forEach((line, index) => {
+		const next = lineTokens(line)
+
+		if (index > startIndex && tokens + next > chunkTokens) {
+			pushChunk(index - 1)
+			startIndex = index
+			tokens = 0
+		}
+
+		tokens += next
+	})
+
+	if (lines.length > 0) {
+		pushChunk(lines.length - 1)
+	}
+
+	return chunks
+}
+
+/**
+ * Formats the result of reading a file that exceeds the token budget: the
+ * outline of its definitions, if any, and the chunks to read it in.
+ */
+export function formatChunkedRead({
+	totalLines,
+	totalTokens,
+	tokenBudget,
+	outline,
+	chunks,
+}: {
+	totalLines: number
+	totalTokens: number
+	tokenBudget: number
+	outline?: string
+	chunks: FileChunk[]
+}): string {
+	const listed = chunks.slice(0, MAX_LISTED_CHUNKS)
+	const chunkList = listed.map(
+		({ startLine, endLine, tokens }, index) =>
+			`${index + 1}. offset=${startLine} limit=${endLine - startLine + 1} (lines ${startLine}-${endLine}, ~${tokens} tokens)`,
+	)
+
+	if (chunks.length > listed.length) {
+		const nextLine = listed[listed.length - 1].endLine + 1
+		chunkList.push(`... ${chunks.length - listed.length} more chunks, starting at offset=${nextLine}`)
+	}
+
+	const sections = [
+		`IMPORTANT: File not read. It has ${totalLines} lines (~${totalTokens} tokens), more than the read token budget of ${tokenBudget} tokens.`,
+		`Use the outline to find the parts you need, then read them with the read_file tool's offset and limit, e.g. one of the chunks below. You can also use indentation mode to read a single definition.`,
+	]
+
+	if (outline) {
+		sections.push(`Outline (line ranges and definitions):\n${outline}`)
+	}
+
+	sections.push(`Chunks:\n${chunkList.join("\n")}`)
+
+	return sections.join("\n\n")
+}
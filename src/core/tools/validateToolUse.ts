@@ -73,6 +73,9 @@ const EDIT_OPERATION_PARAMS = [
 	"patch", // Used by apply_patch
 	"old_string", // Used by search_replace and edit_file
 	"new_string", // Used by search_replace and edit_file
+	"code_edit", // Used by fast_edit
+	"new_source", // Used by edit_notebook
+	"edit_mode", // Used by edit_notebook
 ] as const
 
 // Markers used in apply_patch format to identify file operations
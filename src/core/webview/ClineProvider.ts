@@ -29,6 +29,7 @@ import {
 	type TerminalActionId,
 	type TerminalActionPromptType,
 	type HistoryItem,
+	type TaskSearchResult,
 	type CloudUserInfo,
 	type CloudOrganizationMembership,
 	type CreateTaskOptions,
@@ -37,6 +38,7 @@ import {
 	type ExtensionMessage,
 	type ExtensionState,
 	type MarketplaceInstalledMetadata,
+	type AutoApprovalProfile,
 	RooCodeEventName,
 	requestyDefaultModelId,
 	openRouterDefaultModelId,
@@ -64,6 +66,7 @@ import { ProfileValidator } from "../../shared/ProfileValidator"
 
 import { Terminal } from "../../integrations/terminal/Terminal"
 import { downloadTask, getTaskFileName } from "../../integrations/misc/export-markdown"
+import { downloadTaskReport, getTaskReportFileName } from "../../integrations/misc/export-report"
 import { resolveDefaultSaveUri, saveLastExportPath } from "../../utils/export"
 import { getTheme } from "../../integrations/theme/getTheme"
 import WorkspaceTracker from "../../integrations/workspace/WorkspaceTracker"
@@ -76,6 +79,10 @@ import { CodeIndexManager } from "../../services/code-index/manager"
 import type { IndexProgressUpdate } from "../../services/code-index/interfaces/manager"
 import { MdmService } from "../../services/mdm/MdmService"
 import { SkillsManager } from "../../services/skills/SkillsManager"
+import { TaskScheduler } from "../../services/scheduler/TaskScheduler"
+import { CheckpointRetentionManager } from "../../services/checkpoints/CheckpointRetentionManager"
+import { UsageTracker, formatUsageCsv, getUsageDate } from "../../services/usage/UsageTracker"
+import { VoiceInput } from "../../services/voice/VoiceInput"
 
 import { fileExistsAtPath } from "../../utils/fs"
 import { setTtsEnabled, setTtsSpeed } from "../../utils/tts"
@@ -93,11 +100,18 @@ import { forceFullModelDetailsLoad, hasLoadedFullDetails } from "../../api/provi
 import { ContextProxy } from "../config/ContextProxy"
 import { ProviderSettingsManager } from "../config/ProviderSettingsManager"
 import { CustomModesManager } from "../config/CustomModesManager"
+import { ProjectConfigManager, applyProjectAllowList, applyProjectConfig } from "../config/ProjectConfigManager"
 import { Task } from "../task/Task"
 
 import { webviewMessageHandler } from "./webviewMessageHandler"
 import type { ClineMessage, TodoItem } from "@roo-code/types"
-import { readApiMessages, saveApiMessages, saveTaskMessages, TaskHistoryStore } from "../task-persistence"
+import {
+	readApiMessages,
+	saveApiMessages,
+	saveTaskMessages,
+	TaskHistoryStore,
+	TaskSearchIndex,
+} from "../task-persistence"
 import { readTaskMessages } from "../task-persistence/taskMessages"
 import { getNonce } from "./getNonce"
 import { getUri } from "./getUri"
@@ -142,6 +156,8 @@ export class ClineProvider
 	private _workspaceTracker?: WorkspaceTracker // workSpaceTracker read-only for access outside this class
 	protected mcpHub?: McpHub // Change from private to protected
 	protected skillsManager?: SkillsManager
+	private taskScheduler?: TaskScheduler
+	private checkpointRetentionManager?: CheckpointRetentionManager
 	private marketplaceManager: MarketplaceManager
 	private mdmService?: MdmService
 	private taskCreationCallback: (task: Task) => void
@@ -151,6 +167,11 @@ export class ClineProvider
 
 	private recentTasksCache?: string[]
 	public readonly taskHistoryStore: TaskHistoryStore
+	public readonly usageTracker: UsageTracker
+	public readonly voiceInput = new VoiceInput((state) =>
+		this.postMessageToWebview({ type: "voiceInputState", text: state }),
+	)
+	private taskSearchIndex?: TaskSearchIndex
 	private taskHistoryStoreInitialized = false
 	private globalStateWriteThroughTimer: ReturnType<typeof setTimeout> | null = null
 	private static readonly GLOBAL_STATE_WRITE_THROUGH_DEBOUNCE_MS = 5000 // 5 seconds
@@ -172,6 +193,7 @@ export class ClineProvider
 	public readonly latestAnnouncementId = "apr-2026-v3.52.0-poe-xai-minimax" // v3.52.0 Poe provider, xAI improvements, and MiniMax fixes
 	public readonly providerSettingsManager: ProviderSettingsManager
 	public readonly customModesManager: CustomModesManager
+	public readonly projectConfigManager: ProjectConfigManager
 
 	constructor(
 		readonly context: vscode.ExtensionContext,
@@ -200,6 +222,8 @@ export class ClineProvider
 			this.log(`Failed to initialize TaskHistoryStore: ${error}`)
 		})
 
+		this.usageTracker = new UsageTracker(this.contextProxy.globalStorageUri.fsPath)
+
 		// Start configuration loading (which might trigger indexing) in the background.
 		// Don't await, allowing activation to continue immediately.
 
@@ -211,8 +235,21 @@ export class ClineProvider
 
 		this.providerSettingsManager = new ProviderSettingsManager(this.context)
 
-		this.customModesManager = new CustomModesManager(this.context, async () => {
-			await this.postStateToWebviewWithoutClineMessages()
+		// The repository's shared configuration in .roo/config, which can also define modes.
+		this.projectConfigManager = new ProjectConfigManager(async () => {
+			await this.customModesManager.refreshMergedState()
+		})
+
+		this.customModesManager = new CustomModesManager(
+			this.context,
+			async () => {
+				await this.postStateToWebviewWithoutClineMessages()
+			},
+			() => this.projectConfigManager.getConfig()?.customModes ?? [],
+		)
+
+		this.projectConfigManager.initialize().catch((error) => {
+			this.log(`Failed to initialize the project configuration: ${error}`)
 		})
 
 		// Initialize MCP Hub through the singleton manager
@@ -231,6 +268,22 @@ export class ClineProvider
 			this.log(`Failed to initialize Skills Manager: ${error}`)
 		})
 
+		// Scheduled tasks run once per window, so only the sidebar provider runs them.
+		if (this.renderContext === "sidebar") {
+			this.taskScheduler = new TaskScheduler(this)
+			this.taskScheduler.initialize().catch((error) => {
+				this.log(`Failed to initialize Task Scheduler: ${error}`)
+			})
+		}
+
+		// Any provider can show and clean up checkpoint storage, but only the
+		// sidebar's applies the retention settings in the background.
+		this.checkpointRetentionManager = new CheckpointRetentionManager(this)
+
+		if (this.renderContext === "sidebar") {
+			this.checkpointRetentionManager.initialize()
+		}
+
 		this.marketplaceManager = new MarketplaceManager(this.context, this.customModesManager)
 
 		// Forward <most> task events to the provider.
@@ -584,6 +637,13 @@ export class ClineProvider
 		return this.clineStack.map((cline) => cline.taskId)
 	}
 
+	/**
+	 * The ids of the tasks on the stack of any provider in this window.
+	 */
+	public getOpenTaskIds(): Set<string> {
+		return new Set([...ClineProvider.activeInstances].flatMap((instance) => instance.getCurrentTaskStack()))
+	}
+
 	// Pending Edit Operations Management
 
 	/**
@@ -709,9 +769,17 @@ export class ClineProvider
 		this.mcpHub = undefined
 		await this.skillsManager?.dispose()
 		this.skillsManager = undefined
+		await this.taskScheduler?.dispose()
+		this.taskScheduler = undefined
+		this.checkpointRetentionManager?.dispose()
+		this.checkpointRetentionManager = undefined
 		this.marketplaceManager?.cleanup()
 		this.customModesManager?.dispose()
+		this.projectConfigManager?.dispose()
 		this.taskHistoryStore.dispose()
+		await this.usageTracker.dispose()
+		await this.voiceInput.cancel()
+		this.taskSearchIndex?.clear()
 		this.flushGlobalStateWriteThrough()
 		this.log("Disposed all disposables")
 		ClineProvider.activeInstances.delete(this)
@@ -1843,6 +1911,26 @@ export class ClineProvider
 		await this.postMessageToWebview({ type: "action", action: "chatButtonClicked" })
 	}
 
+	/**
+	 * Searches the conversations of the tasks in the history, including the
+	 * tool results, for all words of the query.
+	 */
+	async searchTaskHistory(query: string): Promise<TaskSearchResult[]> {
+		this.taskSearchIndex ??= new TaskSearchIndex(this.contextProxy.globalStorageUri.fsPath)
+		return this.taskSearchIndex.search(this.taskHistoryStore.getAll(), query)
+	}
+
+	async updateTaskTags(id: string, tags: string[]) {
+		const historyItem = this.taskHistoryStore.get(id)
+
+		if (!historyItem) {
+			return
+		}
+
+		const uniqueTags = [...new Set(tags.map((tag) => tag.trim()).filter(Boolean))]
+		await this.updateTaskHistory({ ...historyItem, tags: uniqueTags.length > 0 ? uniqueTags : undefined })
+	}
+
 	async exportTaskWithId(id: string) {
 		const { historyItem, apiConversationHistory } = await this.getTaskWithId(id)
 		const fileName = getTaskFileName(historyItem.ts)
@@ -1857,6 +1945,57 @@ export class ClineProvider
 		}
 	}
 
+	/**
+	 * Exports a report of a task with its conversation, the file changes and
+	 * the tokens and cost of each request.
+	 */
+	async exportTaskReport(id: string) {
+		const { historyItem } = await this.getTaskWithId(id)
+		const currentTask = this.getCurrentTask()
+
+		// The messages of the current task may not have been saved yet.
+		const messages =
+			currentTask?.taskId === id
+				? currentTask.clineMessages
+				: await readTaskMessages({ taskId: id, globalStoragePath: this.contextProxy.globalStorageUri.fsPath })
+
+		const defaultUri = await resolveDefaultSaveUri(
+			this.contextProxy,
+			"lastTaskExportPath",
+			getTaskReportFileName(historyItem.ts),
+			{ useWorkspace: false, fallbackDir: path.join(os.homedir(), "Downloads") },
+		)
+		const saveUri = await downloadTaskReport(historyItem, messages, defaultUri)
+
+		if (saveUri) {
+			await saveLastExportPath(this.contextProxy, "lastTaskExportPath", saveUri)
+		}
+	}
+
+	/**
+	 * Asks where to save the usage log and writes it there as CSV.
+	 *
+	 * @param since - The first day to export, as YYYY-MM-DD; everything is exported without it
+	 */
+	async exportUsageCsv(since?: string) {
+		const entries = (await this.usageTracker.getEntries()).filter((entry) => !since || entry.date >= since)
+
+		const defaultUri = await resolveDefaultSaveUri(
+			this.contextProxy,
+			"lastUsageExportPath",
+			`roo_usage_${getUsageDate(Date.now())}.csv`,
+			{ useWorkspace: false, fallbackDir: path.join(os.homedir(), "Downloads") },
+		)
+		const saveUri = await vscode.window.showSaveDialog({ filters: { CSV: ["csv"] }, defaultUri })
+
+		if (!saveUri) {
+			return
+		}
+
+		await vscode.workspace.fs.writeFile(saveUri, Buffer.from(formatUsageCsv(entries)))
+		await saveLastExportPath(this.contextProxy, "lastUsageExportPath", saveUri)
+	}
+
 	/* Condenses a task's message history to use fewer tokens. */
 	async condenseTaskContext(taskId: string) {
 		let task: Task | undefined
@@ -2136,6 +2275,8 @@ export class ClineProvider
 			alwaysAllowWrite,
 			alwaysAllowWriteOutsideWorkspace,
 			alwaysAllowWriteProtected,
+			allowedWritePaths,
+			deniedWritePaths,
 			alwaysAllowExecute,
 			allowedCommands,
 			deniedCommands,
@@ -2144,13 +2285,22 @@ export class ClineProvider
 			alwaysAllowSubtasks,
 			allowedMaxRequests,
 			allowedMaxCost,
+			taskBudget,
+			modeBudgets,
 			autoCondenseContext,
 			autoCondenseContextPercent,
+			condensingStrategy,
+			modeCondensingStrategies,
+			customCondensingStrategies,
 			soundEnabled,
 			ttsEnabled,
 			ttsSpeed,
+			ttsContent,
 			enableCheckpoints,
 			checkpointTimeout,
+			checkpointMaxAgeDays,
+			checkpointMaxStorageMb,
+			checkpointMaxPerTask,
 			taskHistory,
 			soundVolume,
 			writeDelayMs,
@@ -2163,6 +2313,7 @@ export class ClineProvider
 			terminalZshP10k,
 			terminalZdotdir,
 			mcpEnabled,
+			mcpResourceUpdateNotifications,
 			currentApiConfigName,
 			listApiConfigMeta,
 			pinnedApiConfigs,
@@ -2170,6 +2321,8 @@ export class ClineProvider
 			customModePrompts,
 			customSupportPrompts,
 			enhancementApiConfigId,
+			fastApplyApiConfigId,
+			maxParallelSubtasks,
 			autoApprovalEnabled,
 			customModes,
 			experiments,
@@ -2182,8 +2335,10 @@ export class ClineProvider
 			language,
 			maxImageFileSize,
 			maxTotalImageSize,
+			readFileTokenBudget,
 			historyPreviewCollapsed,
 			reasoningBlockCollapsed,
+			diffViewLayout,
 			enterBehavior,
 			cloudUserInfo,
 			cloudIsAuthenticated,
@@ -2195,6 +2350,7 @@ export class ClineProvider
 			codebaseIndexConfig,
 			codebaseIndexModels,
 			profileThresholds,
+			failoverApiConfigIds,
 			alwaysAllowFollowupQuestions,
 			followupAutoApproveTimeoutMs,
 			includeDiagnosticMessages,
@@ -2248,14 +2404,21 @@ export class ClineProvider
 			alwaysAllowWrite: alwaysAllowWrite ?? false,
 			alwaysAllowWriteOutsideWorkspace: alwaysAllowWriteOutsideWorkspace ?? false,
 			alwaysAllowWriteProtected: alwaysAllowWriteProtected ?? false,
+			allowedWritePaths: allowedWritePaths ?? [],
+			deniedWritePaths: deniedWritePaths ?? [],
 			alwaysAllowExecute: alwaysAllowExecute ?? false,
 			alwaysAllowMcp: alwaysAllowMcp ?? false,
 			alwaysAllowModeSwitch: alwaysAllowModeSwitch ?? false,
 			alwaysAllowSubtasks: alwaysAllowSubtasks ?? false,
 			allowedMaxRequests,
 			allowedMaxCost,
+			taskBudget,
+			modeBudgets,
 			autoCondenseContext: autoCondenseContext ?? true,
 			autoCondenseContextPercent: autoCondenseContextPercent ?? 100,
+			condensingStrategy,
+			modeCondensingStrategies,
+			customCondensingStrategies,
 			uriScheme: vscode.env.uriScheme,
 			currentTaskId: currentTask?.taskId,
 			currentTaskItem: currentTask?.taskId ? this.taskHistoryStore.get(currentTask.taskId) : undefined,
@@ -2266,8 +2429,12 @@ export class ClineProvider
 			soundEnabled: soundEnabled ?? false,
 			ttsEnabled: ttsEnabled ?? false,
 			ttsSpeed: ttsSpeed ?? 1.0,
+			ttsContent: ttsContent ?? "all",
 			enableCheckpoints: enableCheckpoints ?? true,
 			checkpointTimeout: checkpointTimeout ?? DEFAULT_CHECKPOINT_TIMEOUT_SECONDS,
+			checkpointMaxAgeDays,
+			checkpointMaxStorageMb,
+			checkpointMaxPerTask,
 			shouldShowAnnouncement:
 				telemetrySetting !== "unset" && lastShownAnnouncementId !== this.latestAnnouncementId,
 			allowedCommands: mergedAllowedCommands,
@@ -2283,6 +2450,7 @@ export class ClineProvider
 			terminalZshP10k: terminalZshP10k ?? false,
 			terminalZdotdir: terminalZdotdir ?? false,
 			mcpEnabled: mcpEnabled ?? true,
+			mcpResourceUpdateNotifications: mcpResourceUpdateNotifications ?? true,
 			currentApiConfigName: currentApiConfigName ?? "default",
 			listApiConfigMeta: listApiConfigMeta ?? [],
 			pinnedApiConfigs: pinnedApiConfigs ?? {},
@@ -2290,6 +2458,8 @@ export class ClineProvider
 			customModePrompts: customModePrompts ?? {},
 			customSupportPrompts: customSupportPrompts ?? {},
 			enhancementApiConfigId,
+			fastApplyApiConfigId,
+			maxParallelSubtasks,
 			autoApprovalEnabled: autoApprovalEnabled ?? false,
 			customModes,
 			experiments: experiments ?? experimentDefault,
@@ -2307,9 +2477,11 @@ export class ClineProvider
 			renderContext: this.renderContext,
 			maxImageFileSize: maxImageFileSize ?? 5,
 			maxTotalImageSize: maxTotalImageSize ?? 20,
+			readFileTokenBudget,
 			settingsImportedAt: this.settingsImportedAt,
 			historyPreviewCollapsed: historyPreviewCollapsed ?? false,
 			reasoningBlockCollapsed: reasoningBlockCollapsed ?? true,
+			diffViewLayout: diffViewLayout ?? "inline",
 			enterBehavior: enterBehavior ?? "send",
 			cloudUserInfo,
 			cloudIsAuthenticated: cloudIsAuthenticated ?? false,
@@ -2331,6 +2503,11 @@ export class ClineProvider
 				codebaseIndexOpenAiCompatibleBaseUrl: codebaseIndexConfig?.codebaseIndexOpenAiCompatibleBaseUrl,
 				codebaseIndexSearchMaxResults: codebaseIndexConfig?.codebaseIndexSearchMaxResults,
 				codebaseIndexSearchMinScore: codebaseIndexConfig?.codebaseIndexSearchMinScore,
+				codebaseIndexHybridSearchWeight: codebaseIndexConfig?.codebaseIndexHybridSearchWeight,
+				codebaseIndexRerankerProvider: codebaseIndexConfig?.codebaseIndexRerankerProvider,
+				codebaseIndexRerankerModelId: codebaseIndexConfig?.codebaseIndexRerankerModelId,
+				codebaseIndexRerankerCandidates: codebaseIndexConfig?.codebaseIndexRerankerCandidates,
+				codebaseIndexDocumentTypes: codebaseIndexConfig?.codebaseIndexDocumentTypes,
 				codebaseIndexBedrockRegion: codebaseIndexConfig?.codebaseIndexBedrockRegion,
 				codebaseIndexBedrockProfile: codebaseIndexConfig?.codebaseIndexBedrockProfile,
 				codebaseIndexOpenRouterSpecificProvider: codebaseIndexConfig?.codebaseIndexOpenRouterSpecificProvider,
@@ -2338,7 +2515,9 @@ export class ClineProvider
 			// Only set mdmCompliant if there's an actual MDM policy
 			// undefined means no MDM policy, true means compliant, false means non-compliant
 			mdmCompliant: this.mdmService?.requiresCloudAuth() ? this.checkMdmCompliance() : undefined,
+			projectConfig: this.projectConfigManager.getStatus(),
 			profileThresholds: profileThresholds ?? {},
+			failoverApiConfigIds: failoverApiConfigIds ?? [],
 			cloudApiUrl: getRooCodeApiUrl(),
 			hasOpenedModeSelector: this.getGlobalState("hasOpenedModeSelector") ?? false,
 			lockApiConfigAcrossModes: lockApiConfigAcrossModes ?? false,
@@ -2363,6 +2542,7 @@ export class ClineProvider
 				}
 			})(),
 			debug: vscode.workspace.getConfiguration(Package.name).get<boolean>("debug", false),
+			debugRecorder: vscode.workspace.getConfiguration(Package.name).get<boolean>("debugRecorder", false),
 		}
 	}
 
@@ -2378,7 +2558,9 @@ export class ClineProvider
 			"clineMessages" | "renderContext" | "hasOpenedModeSelector" | "version" | "shouldShowAnnouncement"
 		>
 	> {
-		const stateValues = this.contextProxy.getValues()
+		// The guardrails of the repository's .roo/config apply on top of the user's settings.
+		const projectConfig = this.projectConfigManager.getConfig()
+		const stateValues = applyProjectConfig(this.contextProxy.getValues(), projectConfig)
 		const customModes = await this.customModesManager.getCustomModes()
 
 		// Determine apiProvider with the same logic as before, while filtering retired providers.
@@ -2405,6 +2587,8 @@ export class ClineProvider
 			)
 		}
 
+		organizationAllowList = applyProjectAllowList(organizationAllowList, projectConfig?.allowedProviders)
+
 		let cloudUserInfo: CloudUserInfo | null = null
 
 		try {
@@ -2479,25 +2663,37 @@ export class ClineProvider
 			alwaysAllowWrite: stateValues.alwaysAllowWrite ?? false,
 			alwaysAllowWriteOutsideWorkspace: stateValues.alwaysAllowWriteOutsideWorkspace ?? false,
 			alwaysAllowWriteProtected: stateValues.alwaysAllowWriteProtected ?? false,
+			allowedWritePaths: stateValues.allowedWritePaths ?? [],
+			deniedWritePaths: stateValues.deniedWritePaths ?? [],
 			alwaysAllowExecute: stateValues.alwaysAllowExecute ?? false,
 			alwaysAllowMcp: stateValues.alwaysAllowMcp ?? false,
 			alwaysAllowModeSwitch: stateValues.alwaysAllowModeSwitch ?? false,
 			alwaysAllowSubtasks: stateValues.alwaysAllowSubtasks ?? false,
 			alwaysAllowFollowupQuestions: stateValues.alwaysAllowFollowupQuestions ?? false,
 			followupAutoApproveTimeoutMs: stateValues.followupAutoApproveTimeoutMs ?? 60000,
+			denyUnapprovedActions: stateValues.denyUnapprovedActions ?? false,
 			diagnosticsEnabled: stateValues.diagnosticsEnabled ?? true,
 			allowedMaxRequests: stateValues.allowedMaxRequests,
 			allowedMaxCost: stateValues.allowedMaxCost,
+			taskBudget: stateValues.taskBudget,
+			modeBudgets: stateValues.modeBudgets,
 			autoCondenseContext: stateValues.autoCondenseContext ?? true,
 			autoCondenseContextPercent: stateValues.autoCondenseContextPercent ?? 100,
+			condensingStrategy: stateValues.condensingStrategy,
+			modeCondensingStrategies: stateValues.modeCondensingStrategies,
+			customCondensingStrategies: stateValues.customCondensingStrategies,
 			taskHistory: this.taskHistoryStore.getAll(),
 			allowedCommands: stateValues.allowedCommands,
 			deniedCommands: stateValues.deniedCommands,
 			soundEnabled: stateValues.soundEnabled ?? false,
 			ttsEnabled: stateValues.ttsEnabled ?? false,
 			ttsSpeed: stateValues.ttsSpeed ?? 1.0,
+			ttsContent: stateValues.ttsContent ?? "all",
 			enableCheckpoints: stateValues.enableCheckpoints ?? true,
 			checkpointTimeout: stateValues.checkpointTimeout ?? DEFAULT_CHECKPOINT_TIMEOUT_SECONDS,
+			checkpointMaxAgeDays: stateValues.checkpointMaxAgeDays,
+			checkpointMaxStorageMb: stateValues.checkpointMaxStorageMb,
+			checkpointMaxPerTask: stateValues.checkpointMaxPerTask,
 			soundVolume: stateValues.soundVolume,
 			writeDelayMs: stateValues.writeDelayMs ?? DEFAULT_WRITE_DELAY_MS,
 			terminalShellIntegrationTimeout:
@@ -2512,6 +2708,7 @@ export class ClineProvider
 			mode: stateValues.mode ?? defaultModeSlug,
 			language: stateValues.language ?? formatLanguage(vscode.env.language),
 			mcpEnabled: stateValues.mcpEnabled ?? true,
+			mcpResourceUpdateNotifications: stateValues.mcpResourceUpdateNotifications ?? true,
 			mcpServers: this.mcpHub?.getAllServers() ?? [],
 			currentApiConfigName: stateValues.currentApiConfigName ?? "default",
 			listApiConfigMeta: stateValues.listApiConfigMeta ?? [],
@@ -2520,6 +2717,8 @@ export class ClineProvider
 			customModePrompts: stateValues.customModePrompts ?? {},
 			customSupportPrompts: stateValues.customSupportPrompts ?? {},
 			enhancementApiConfigId: stateValues.enhancementApiConfigId,
+			fastApplyApiConfigId: stateValues.fastApplyApiConfigId,
+			maxParallelSubtasks: stateValues.maxParallelSubtasks,
 			experiments: stateValues.experiments ?? experimentDefault,
 			autoApprovalEnabled: stateValues.autoApprovalEnabled ?? false,
 			customModes,
@@ -2531,8 +2730,10 @@ export class ClineProvider
 			enableSubfolderRules: stateValues.enableSubfolderRules ?? false,
 			maxImageFileSize: stateValues.maxImageFileSize ?? 5,
 			maxTotalImageSize: stateValues.maxTotalImageSize ?? 20,
+			readFileTokenBudget: stateValues.readFileTokenBudget,
 			historyPreviewCollapsed: stateValues.historyPreviewCollapsed ?? false,
 			reasoningBlockCollapsed: stateValues.reasoningBlockCollapsed ?? true,
+			diffViewLayout: stateValues.diffViewLayout ?? "inline",
 			enterBehavior: stateValues.enterBehavior ?? "send",
 			cloudUserInfo,
 			cloudIsAuthenticated,
@@ -2556,12 +2757,18 @@ export class ClineProvider
 					stateValues.codebaseIndexConfig?.codebaseIndexOpenAiCompatibleBaseUrl,
 				codebaseIndexSearchMaxResults: stateValues.codebaseIndexConfig?.codebaseIndexSearchMaxResults,
 				codebaseIndexSearchMinScore: stateValues.codebaseIndexConfig?.codebaseIndexSearchMinScore,
+				codebaseIndexHybridSearchWeight: stateValues.codebaseIndexConfig?.codebaseIndexHybridSearchWeight,
+				codebaseIndexRerankerProvider: stateValues.codebaseIndexConfig?.codebaseIndexRerankerProvider,
+				codebaseIndexRerankerModelId: stateValues.codebaseIndexConfig?.codebaseIndexRerankerModelId,
+				codebaseIndexRerankerCandidates: stateValues.codebaseIndexConfig?.codebaseIndexRerankerCandidates,
+				codebaseIndexDocumentTypes: stateValues.codebaseIndexConfig?.codebaseIndexDocumentTypes,
 				codebaseIndexBedrockRegion: stateValues.codebaseIndexConfig?.codebaseIndexBedrockRegion,
 				codebaseIndexBedrockProfile: stateValues.codebaseIndexConfig?.codebaseIndexBedrockProfile,
 				codebaseIndexOpenRouterSpecificProvider:
 					stateValues.codebaseIndexConfig?.codebaseIndexOpenRouterSpecificProvider,
 			},
 			profileThresholds: stateValues.profileThresholds ?? {},
+			failoverApiConfigIds: stateValues.failoverApiConfigIds ?? [],
 			lockApiConfigAcrossModes: this.context.workspaceState.get("lockApiConfigAcrossModes", false),
 			includeDiagnosticMessages: stateValues.includeDiagnosticMessages ?? true,
 			maxDiagnosticMessages: stateValues.maxDiagnosticMessages ?? 50,
@@ -2747,6 +2954,10 @@ export class ClineProvider
 		return this.mcpHub
 	}
 
+	public getCheckpointRetentionManager(): CheckpointRetentionManager | undefined {
+		return this.checkpointRetentionManager
+	}
+
 	public getSkillsManager(): SkillsManager | undefined {
 		return this.skillsManager
 	}
@@ -3559,6 +3770,92 @@ export class ClineProvider
 		}
 	}
 
+	/**
+	 * Create a task that runs in the background alongside the current task, as
+	 * a subtask of `parent` (see run_parallel_tasks) or on its own (see
+	 * `TaskScheduler`). Unlike delegation, the current task stays current and
+	 * the provider's mode doesn't change: the task runs in its own mode, with
+	 * the profile saved for that mode, and with `autoApproval` in place of the
+	 * user's auto-approval settings if given. The caller starts it.
+	 */
+	public async createBackgroundTask(params: {
+		message: string
+		mode: string
+		parent?: Task
+		autoApproval?: AutoApprovalProfile
+	}): Promise<Task> {
+		const { message, mode, parent, autoApproval } = params
+		const state = await this.getState()
+		const { organizationAllowList, checkpointTimeout, experiments } = state
+
+		let apiConfiguration = parent?.apiConfiguration ?? state.apiConfiguration
+		const lockApiConfigAcrossModes = this.context.workspaceState.get("lockApiConfigAcrossModes", false)
+		const modeConfigId = lockApiConfigAcrossModes
+			? undefined
+			: await this.providerSettingsManager.getModeConfigId(mode)
+
+		if (modeConfigId) {
+			try {
+				const { name: _, ...providerSettings } = await this.providerSettingsManager.getProfile({
+					id: modeConfigId,
+				})
+
+				if (
+					providerSettings.apiProvider &&
+					ProfileValidator.isProfileAllowed(providerSettings, organizationAllowList)
+				) {
+					apiConfiguration = providerSettings
+				}
+			} catch (error) {
+				this.log(
+					`[createBackgroundTask] Failed to load the profile for mode '${mode}', using the parent's: ${
+						error instanceof Error ? error.message : String(error)
+					}`,
+				)
+			}
+		}
+
+		const task = new Task({
+			provider: this,
+			apiConfiguration,
+			// Checkpoints of concurrent tasks would race on the same shadow repository.
+			enableCheckpoints: false,
+			checkpointTimeout,
+			consecutiveMistakeLimit: apiConfiguration.consecutiveMistakeLimit,
+			task: message,
+			experiments,
+			rootTask: parent ? (parent.rootTask ?? parent) : undefined,
+			parentTask: parent,
+			onCreated: this.taskCreationCallback,
+			initialStatus: "active",
+			mode,
+			background: true,
+			autoApproval,
+			startTask: false,
+		})
+
+		this.log(`[createBackgroundTask] background task ${task.taskId}.${task.instanceId} instantiated`)
+
+		return task
+	}
+
+	/**
+	 * Mark a background task as completed in the task history once it has
+	 * returned its result.
+	 */
+	public async completeBackgroundTask(taskId: string): Promise<void> {
+		try {
+			const { historyItem } = await this.getTaskWithId(taskId)
+			await this.updateTaskHistory({ ...historyItem, status: "completed" })
+		} catch (err) {
+			this.log(
+				`[completeBackgroundTask] Failed to persist completed status for ${taskId}: ${
+					(err as Error)?.message ?? String(err)
+				}`,
+			)
+		}
+	}
+
 	/**
 	 * Convert a file path to a webview-accessible URI
 	 * This method safely converts file paths to URIs that can be loaded in the webview
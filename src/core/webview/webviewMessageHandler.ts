@@ -21,6 +21,7 @@ import {
 	ExperimentId,
 	checkoutDiffPayloadSchema,
 	checkoutRestorePayloadSchema,
+	checkoutRestoreFilesPayloadSchema,
 } from "@roo-code/types"
 import { customToolRegistry } from "@roo-code/core"
 import { CloudService } from "@roo-code/cloud"
@@ -59,6 +60,7 @@ import { playTts, setTtsEnabled, setTtsSpeed, stopTts } from "../../utils/tts"
 import { searchCommits } from "../../utils/git"
 import { exportSettings, importSettingsWithFeedback } from "../config/importExport"
 import { getOpenAiModels } from "../../api/providers/openai"
+import { getBedrockApplicationInferenceProfiles } from "../../api/providers/bedrock"
 import { getVsCodeLmModels } from "../../api/providers/vscode-lm"
 import { openMention } from "../mentions"
 import { resolveImageMentions } from "../mentions/resolveImageMentions"
@@ -882,6 +884,47 @@ export const webviewMessageHandler = async (
 		case "exportTaskWithId":
 			provider.exportTaskWithId(message.text!)
 			break
+		case "exportTaskReport": {
+			// Without a task id, the current task is exported.
+			const reportTaskId = message.text ?? provider.getCurrentTask()?.taskId
+
+			if (reportTaskId) {
+				try {
+					await provider.exportTaskReport(reportTaskId)
+				} catch (error) {
+					const errorMessage = error instanceof Error ? error.message : String(error)
+					provider.log(`Error exporting task report: ${errorMessage}`)
+					vscode.window.showErrorMessage(
+						t("common:errors.export_task_report_failed", { error: errorMessage }),
+					)
+				}
+			}
+			break
+		}
+		case "searchTaskHistory": {
+			try {
+				const taskSearchResults = await provider.searchTaskHistory(message.query ?? "")
+
+				await provider.postMessageToWebview({
+					type: "taskHistorySearchResults",
+					taskSearchResults,
+					requestId: message.requestId,
+				})
+			} catch (error) {
+				await provider.postMessageToWebview({
+					type: "taskHistorySearchResults",
+					taskSearchResults: [],
+					requestId: message.requestId,
+					error: error instanceof Error ? error.message : String(error),
+				})
+			}
+			break
+		}
+		case "updateTaskTags":
+			if (message.text) {
+				await provider.updateTaskTags(message.text, message.values?.tags ?? [])
+			}
+			break
 		case "getTaskWithAggregatedCosts": {
 			try {
 				const taskId = message.text
@@ -1199,6 +1242,23 @@ export const webviewMessageHandler = async (
 			}
 
 			break
+		case "requestBedrockInferenceProfiles": {
+			// The settings view sends its unsaved configuration so profiles can be listed before saving.
+			const bedrockConfig = message.apiConfiguration ?? (await provider.getState()).apiConfiguration
+
+			try {
+				const bedrockInferenceProfiles = await getBedrockApplicationInferenceProfiles(bedrockConfig)
+				provider.postMessageToWebview({ type: "bedrockInferenceProfiles", bedrockInferenceProfiles })
+			} catch (error) {
+				provider.postMessageToWebview({
+					type: "bedrockInferenceProfiles",
+					bedrockInferenceProfiles: [],
+					error: error instanceof Error ? error.message : String(error),
+				})
+			}
+
+			break
+		}
 		case "requestVsCodeLmModels":
 			const vsCodeLmModels = await getVsCodeLmModels()
 			// TODO: Cache like we do for OpenRouter, etc?
@@ -1317,6 +1377,53 @@ export const webviewMessageHandler = async (
 
 			break
 		}
+		case "checkpointRestoreFiles": {
+			// Unlike a full restore this doesn't rewind the task, so it keeps running.
+			const result = checkoutRestoreFilesPayloadSchema.safeParse(message.payload)
+
+			if (result.success) {
+				await provider.getCurrentTask()?.checkpointRestoreFiles(result.data)
+			}
+
+			break
+		}
+		case "requestCheckpointStorageUsage": {
+			const checkpointStorage = await provider.getCheckpointRetentionManager()?.getUsage()
+			await provider.postMessageToWebview({ type: "checkpointStorageUsage", checkpointStorage })
+			break
+		}
+		case "requestUsageStats":
+			await provider.postMessageToWebview({
+				type: "usageStats",
+				usageEntries: await provider.usageTracker.getEntries(),
+			})
+			break
+		case "exportUsageCsv":
+			try {
+				await provider.exportUsageCsv(message.text)
+			} catch (error) {
+				const errorMessage = error instanceof Error ? error.message : String(error)
+				provider.log(`Error exporting usage: ${errorMessage}`)
+				vscode.window.showErrorMessage(t("common:errors.export_usage_failed", { error: errorMessage }))
+			}
+			break
+		case "deleteTaskCheckpoints": {
+			// Without a task id, this deletes the checkpoints of all tasks that aren't open.
+			const manager = provider.getCheckpointRetentionManager()
+
+			try {
+				await manager?.deleteCheckpoints(message.text ? [message.text] : undefined)
+			} catch (error) {
+				provider.log(`Error deleting checkpoints: ${error instanceof Error ? error.message : String(error)}`)
+				vscode.window.showErrorMessage(t("common:errors.checkpoint_delete_failed"))
+			}
+
+			await provider.postMessageToWebview({
+				type: "checkpointStorageUsage",
+				checkpointStorage: await manager?.getUsage(),
+			})
+			break
+		}
 		case "cancelTask":
 			await provider.cancelTask()
 			break
@@ -1440,6 +1547,20 @@ export const webviewMessageHandler = async (
 			}
 			break
 		}
+		case "authenticateMcpServer": {
+			await provider.getMcpHub()?.authenticateServer(message.serverName!, message.source as "global" | "project")
+			break
+		}
+		case "signOutMcpServer": {
+			try {
+				await provider.getMcpHub()?.signOutServer(message.serverName!, message.source as "global" | "project")
+			} catch (error) {
+				provider.log(
+					`Failed to sign out of MCP server ${message.serverName}: ${JSON.stringify(error, Object.getOwnPropertyNames(error), 2)}`,
+				)
+			}
+			break
+		}
 		case "toggleToolAlwaysAllow": {
 			try {
 				await provider
@@ -1536,6 +1657,28 @@ export const webviewMessageHandler = async (
 		case "stopTts":
 			stopTts()
 			break
+		case "startVoiceInput":
+		case "stopVoiceInput":
+			try {
+				if (message.type === "startVoiceInput") {
+					await provider.voiceInput.start()
+				} else {
+					const { apiConfiguration } = await provider.getState()
+					const transcript = await provider.voiceInput.stop(apiConfiguration)
+
+					if (transcript) {
+						await provider.postMessageToWebview({ type: "voiceInputTranscript", text: transcript })
+					}
+				}
+			} catch (error) {
+				const errorMessage = error instanceof Error ? error.message : String(error)
+				provider.log(`Error with voice input: ${errorMessage}`)
+				vscode.window.showErrorMessage(t("common:errors.voice_input_failed", { error: errorMessage }))
+			}
+			break
+		case "cancelVoiceInput":
+			await provider.voiceInput.cancel()
+			break
 
 		case "updateVSCodeSetting": {
 			const { setting, value } = message
@@ -1989,6 +2132,16 @@ export const webviewMessageHandler = async (
 				)
 			}
 			break
+		case "pinMessage":
+			if (typeof message.messageTs === "number") {
+				await provider.getCurrentTask()?.setMessagePinned(message.messageTs, message.bool ?? false)
+			}
+			break
+		case "pinFile":
+			if (message.text) {
+				await provider.getCurrentTask()?.setFilePinned(message.text, message.bool ?? false)
+			}
+			break
 		case "getListApiConfiguration":
 			try {
 				const listApiConfig = await provider.providerSettingsManager.listConfig()
@@ -2345,6 +2498,13 @@ export const webviewMessageHandler = async (
 			await provider.postStateToWebview()
 			break
 		}
+		case "debugRecorderSetting": {
+			await vscode.workspace
+				.getConfiguration(Package.name)
+				.update("debugRecorder", message.bool ?? false, vscode.ConfigurationTarget.Global)
+			await provider.postStateToWebview()
+			break
+		}
 		case "cloudButtonClicked": {
 			// Navigate to the cloud tab.
 			provider.postMessageToWebview({ type: "action", action: "cloudButtonClicked" })
@@ -2532,6 +2692,11 @@ export const webviewMessageHandler = async (
 					codebaseIndexBedrockProfile: settings.codebaseIndexBedrockProfile,
 					codebaseIndexSearchMaxResults: settings.codebaseIndexSearchMaxResults,
 					codebaseIndexSearchMinScore: settings.codebaseIndexSearchMinScore,
+					codebaseIndexHybridSearchWeight: settings.codebaseIndexHybridSearchWeight,
+					codebaseIndexRerankerProvider: settings.codebaseIndexRerankerProvider,
+					codebaseIndexRerankerModelId: settings.codebaseIndexRerankerModelId,
+					codebaseIndexRerankerCandidates: settings.codebaseIndexRerankerCandidates,
+					codebaseIndexDocumentTypes: settings.codebaseIndexDocumentTypes,
 					codebaseIndexOpenRouterSpecificProvider: settings.codebaseIndexOpenRouterSpecificProvider,
 				}
 
@@ -2575,6 +2740,18 @@ export const webviewMessageHandler = async (
 						settings.codebaseIndexOpenRouterApiKey,
 					)
 				}
+				if (settings.codebaseIndexCohereApiKey !== undefined) {
+					await provider.contextProxy.storeSecret(
+						"codebaseIndexCohereApiKey",
+						settings.codebaseIndexCohereApiKey,
+					)
+				}
+				if (settings.codebaseIndexVoyageApiKey !== undefined) {
+					await provider.contextProxy.storeSecret(
+						"codebaseIndexVoyageApiKey",
+						settings.codebaseIndexVoyageApiKey,
+					)
+				}
 
 				// Send success response first - settings are saved regardless of validation
 				await provider.postMessageToWebview({
@@ -2713,6 +2890,8 @@ export const webviewMessageHandler = async (
 				"codebaseIndexVercelAiGatewayApiKey",
 			))
 			const hasOpenRouterApiKey = !!(await provider.context.secrets.get("codebaseIndexOpenRouterApiKey"))
+			const hasCohereApiKey = !!(await provider.context.secrets.get("codebaseIndexCohereApiKey"))
+			const hasVoyageApiKey = !!(await provider.context.secrets.get("codebaseIndexVoyageApiKey"))
 
 			provider.postMessageToWebview({
 				type: "codeIndexSecretStatus",
@@ -2724,6 +2903,8 @@ export const webviewMessageHandler = async (
 					hasMistralApiKey,
 					hasVercelAiGatewayApiKey,
 					hasOpenRouterApiKey,
+					hasCohereApiKey,
+					hasVoyageApiKey,
 				},
 			})
 			break
@@ -3401,6 +3582,53 @@ export const webviewMessageHandler = async (
 			break
 		}
 
+		case "exportProviderTraffic": {
+			const currentTask = provider.getCurrentTask()
+			if (!currentTask) {
+				vscode.window.showErrorMessage("No active task to export provider traffic for")
+				break
+			}
+
+			try {
+				const { getProviderTrafficFilePath, readProviderTraffic, formatProviderTraffic } = await import(
+					"../../utils/providerTrafficRecorder"
+				)
+				const globalStoragePath = provider.contextProxy.globalStorageUri.fsPath
+				const entries = await readProviderTraffic(
+					await getProviderTrafficFilePath(globalStoragePath, currentTask.taskId),
+				)
+
+				if (entries.length === 0) {
+					vscode.window.showInformationMessage("No provider traffic has been recorded for this task")
+					break
+				}
+
+				const defaultUri = resolveDefaultSaveUri(
+					provider.contextProxy,
+					"lastTaskExportPath",
+					`roo-provider-traffic-${currentTask.taskId.slice(0, 8)}.har`,
+					{ useWorkspace: false, fallbackDir: path.join(os.homedir(), "Downloads") },
+				)
+				const saveUri = await vscode.window.showSaveDialog({
+					defaultUri,
+					filters: { HAR: ["har"], JSON: ["json"] },
+				})
+
+				if (!saveUri) {
+					break
+				}
+
+				const format = path.extname(saveUri.fsPath).toLowerCase() === ".json" ? "json" : "har"
+				await fs.writeFile(saveUri.fsPath, formatProviderTraffic(entries, format), "utf8")
+				await saveLastExportPath(provider.contextProxy, "lastTaskExportPath", saveUri)
+			} catch (error) {
+				const errorMessage = error instanceof Error ? error.message : String(error)
+				provider.log(`Error exporting provider traffic: ${errorMessage}`)
+				vscode.window.showErrorMessage(`Failed to export provider traffic: ${errorMessage}`)
+			}
+			break
+		}
+
 		case "downloadErrorDiagnostics": {
 			const currentTask = provider.getCurrentTask()
 			if (!currentTask) {
@@ -25,6 +25,7 @@ import { customToolRegistry } from "@roo-code/core"
 import "./utils/path" // Necessary to have access to String.prototype.toPosix.
 import { createOutputChannelLogger, createDualLogger } from "./utils/outputChannelLogger"
 import { initializeNetworkProxy } from "./utils/networkProxy"
+import { initializeProviderTrafficRecorder } from "./utils/providerTrafficRecorder"
 
 import { Package } from "./shared/package"
 import { formatLanguage } from "./shared/language"
@@ -128,6 +129,10 @@ export async function activate(context: vscode.ExtensionContext) {
 	// Only applied in debug mode (F5).
 	await initializeNetworkProxy(context, outputChannel)
 
+	// Wrap fetch for the opt-in provider traffic recorder. This has to happen
+	// before provider clients are created since some of them capture fetch.
+	initializeProviderTrafficRecorder(context)
+
 	// Set extension path for custom tool registry to find bundled esbuild
 	customToolRegistry.setExtensionPath(context.extensionPath)
 
@@ -171,26 +176,42 @@ export async function activate(context: vscode.ExtensionContext) {
 	// Initialize code index managers for all workspace folders.
 	const codeIndexManagers: CodeIndexManager[] = []
 
-	if (vscode.workspace.workspaceFolders) {
-		for (const folder of vscode.workspace.workspaceFolders) {
-			const manager = CodeIndexManager.getInstance(context, folder.uri.fsPath)
+	const initializeCodeIndexManager = (folder: vscode.WorkspaceFolder) => {
+		const manager = CodeIndexManager.getInstance(context, folder.uri.fsPath)
 
-			if (manager) {
-				codeIndexManagers.push(manager)
+		if (manager) {
+			codeIndexManagers.push(manager)
 
-				// Initialize in background; do not block extension activation
-				void manager.initialize(contextProxy).catch((error) => {
-					const message = error instanceof Error ? error.message : String(error)
-					outputChannel.appendLine(
-						`[CodeIndexManager] Error during background CodeIndexManager configuration/indexing for ${folder.uri.fsPath}: ${message}`,
-					)
-				})
+			// Initialize in background; do not block extension activation
+			void manager.initialize(contextProxy).catch((error) => {
+				const message = error instanceof Error ? error.message : String(error)
+				outputChannel.appendLine(
+					`[CodeIndexManager] Error during background CodeIndexManager configuration/indexing for ${folder.uri.fsPath}: ${message}`,
+				)
+			})
 
-				context.subscriptions.push(manager)
-			}
+			context.subscriptions.push(manager)
+		}
+	}
+
+	if (vscode.workspace.workspaceFolders) {
+		for (const folder of vscode.workspace.workspaceFolders) {
+			initializeCodeIndexManager(folder)
 		}
 	}
 
+	// Keep one index per folder as folders are added to or removed from a multi-root workspace.
+	context.subscriptions.push(
+		vscode.workspace.onDidChangeWorkspaceFolders(({ added, removed }) => {
+			for (const folder of removed) {
+				CodeIndexManager.disposeInstance(folder.uri.fsPath)
+			}
+			for (const folder of added) {
+				initializeCodeIndexManager(folder)
+			}
+		}),
+	)
+
 	// Initialize the provider *before* the Roo Code Cloud service.
 	const provider = new ClineProvider(context, outputChannel, "sidebar", contextProxy, mdmService)
 
@@ -14,6 +14,8 @@ import {
 	type ProviderSettingsEntry,
 	type TaskEvent,
 	type CreateTaskOptions,
+	type CodebaseIndexStats,
+	type CodebaseReindexOptions,
 	RooCodeEventName,
 	TaskCommandName,
 	isSecretStateKey,
@@ -28,6 +30,7 @@ import { ClineProvider } from "../core/webview/ClineProvider"
 import { openClineInNewTab } from "../activate/registerCommands"
 import { getCommands } from "../services/command/commands"
 import { getModels } from "../api/providers/fetchers/modelCache"
+import { CodeIndexManager } from "../services/code-index/manager"
 
 export class API extends EventEmitter<RooCodeEvents> implements RooCodeAPI {
 	private readonly outputChannel: vscode.OutputChannel
@@ -566,4 +569,18 @@ export class API extends EventEmitter<RooCodeEvents> implements RooCodeAPI {
 		await this.sidebarProvider.activateProviderProfile({ name })
 		return this.getActiveProfile()
 	}
+
+	// Codebase Index Management
+
+	public async reindexCodebase(options?: CodebaseReindexOptions): Promise<void> {
+		await CodeIndexManager.reindexWorkspaces(this.sidebarProvider.contextProxy, options)
+	}
+
+	public getCodebaseIndexStats(): CodebaseIndexStats[] {
+		return CodeIndexManager.getAllIndexStats()
+	}
+
+	public async clearCodebaseIndex(workspacePath?: string): Promise<void> {
+		await CodeIndexManager.clearWorkspaces(workspacePath)
+	}
 }
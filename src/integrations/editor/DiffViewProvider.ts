@@ -18,6 +18,17 @@ import { DecorationController } from "./DecorationController"
 export const DIFF_VIEW_URI_SCHEME = "cline-diff"
 export const DIFF_VIEW_LABEL_CHANGES = "Original ↔ Roo's Changes"
 
+// The number of characters two texts share at the start of their first line.
+function getCommonPrefixLength(a: string, b: string): number {
+	let length = 0
+
+	while (length < a.length && a[length] === b[length] && a[length] !== "\n") {
+		length++
+	}
+
+	return length
+}
+
 // TODO: https://github.com/cline/cline/pull/3354
 export class DiffViewProvider {
 	// Properties to store the results of saveChanges
@@ -587,11 +598,16 @@ export class DiffViewProvider {
 
 		let lineCount = 0
 
-		for (const part of diffs) {
+		for (const [index, part] of diffs.entries()) {
 			if (part.added || part.removed) {
+				// In a long line, a changed word can be far off screen, so reveal
+				// the first character that changed rather than the line's start.
+				const next = diffs[index + 1]
+				const column = part.removed && next?.added ? getCommonPrefixLength(part.value, next.value) : 0
+
 				// Found the first diff, scroll to it without stealing focus.
 				this.activeDiffEditor.revealRange(
-					new vscode.Range(lineCount, 0, lineCount, 0),
+					new vscode.Range(lineCount, column, lineCount, column),
 					vscode.TextEditorRevealType.InCenter,
 				)
 
@@ -182,6 +182,32 @@ describe("DiffViewProvider", () => {
 		})
 	})
 
+	describe("scrollToFirstDiff method", () => {
+		it("should reveal the first changed character of a line", () => {
+			;(diffViewProvider as any).originalContent = "one\nconst value = computeTotal(items, 1)\nthree\n"
+			;(diffViewProvider as any).activeDiffEditor.document.getText.mockReturnValue(
+				"one\nconst value = computeTotal(items, 2)\nthree\n",
+			)
+
+			diffViewProvider.scrollToFirstDiff()
+
+			expect(vscode.Range).toHaveBeenCalledWith(1, 34, 1, 34)
+			expect((diffViewProvider as any).activeDiffEditor.revealRange).toHaveBeenCalledWith(
+				expect.anything(),
+				vscode.TextEditorRevealType.InCenter,
+			)
+		})
+
+		it("should reveal the start of added lines", () => {
+			;(diffViewProvider as any).originalContent = "one\n"
+			;(diffViewProvider as any).activeDiffEditor.document.getText.mockReturnValue("one\ntwo\n")
+
+			diffViewProvider.scrollToFirstDiff()
+
+			expect(vscode.Range).toHaveBeenCalledWith(1, 0, 1, 0)
+		})
+	})
+
 	describe("open method", () => {
 		it("should pre-open file as text document before executing diff command", async () => {
 			// Setup
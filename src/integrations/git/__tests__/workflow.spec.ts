@@ -0,0 +1,116 @@
+// npx vitest src/integrations/git/__tests__/workflow.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+import { execa } from "execa"
+
+import {
+	detectHostingProvider,
+	formatGitSteps,
+	planCommit,
+	planCreateBranch,
+	planPullRequest,
+	runGitSteps,
+} from "../workflow"
+
+describe("detectHostingProvider", () => {
+	it("should detect the provider from https, ssh and scp-like remotes", () => {
+		expect(detectHostingProvider("https://github.com/RooCodeInc/Roo-Code.git")).toBe("github")
+		expect(detectHostingProvider("git@github.com:RooCodeInc/Roo-Code.git")).toBe("github")
+		expect(detectHostingProvider("ssh://git@gitlab.example.com:2222/team/app.git")).toBe("gitlab")
+		expect(detectHostingProvider("https://bitbucket.org/team/app.git")).toBeUndefined()
+		expect(detectHostingProvider("https://example.com/github/app.git")).toBeUndefined()
+	})
+})
+
+describe("formatGitSteps", () => {
+	it("should quote arguments that need it", () => {
+		expect(formatGitSteps(planCommit(["src/a.ts", "docs/read me.md"], "Fix the user's login"))).toBe(
+			"git add -- src/a.ts 'docs/read me.md' && git commit --message 'Fix the user'\\''s login' -- src/a.ts 'docs/read me.md'",
+		)
+	})
+})
+
+describe("git workflow", () => {
+	let cwd: string
+
+	const git = (...args: string[]) => execa("git", args, { cwd })
+
+	beforeEach(async () => {
+		cwd = await fs.mkdtemp(path.join(os.tmpdir(), "git-workflow-"))
+		await git("init", "--initial-branch", "main")
+		await git("config", "user.name", "Roo")
+		await git("config", "user.email", "roo@example.com")
+		await fs.writeFile(path.join(cwd, "README.md"), "# App\n")
+		await git("add", "README.md")
+		await git("commit", "--message", "Initial commit")
+	})
+
+	afterEach(async () => {
+		await fs.rm(cwd, { recursive: true, force: true })
+	})
+
+	it("should create and switch to a new branch", async () => {
+		const steps = await planCreateBranch(cwd, "fix/login")
+
+		expect(steps).toEqual([["git", "switch", "--create", "fix/login"]])
+		expect((await runGitSteps(cwd, steps)).failed).toBeUndefined()
+		expect((await git("branch", "--show-current")).stdout).toBe("fix/login")
+	})
+
+	it("should reject invalid and existing branch names", async () => {
+		await expect(planCreateBranch(cwd, "fix..login")).rejects.toThrow("is not a valid branch name")
+		await expect(planCreateBranch(cwd, "main")).rejects.toThrow('A branch named "main" already exists')
+	})
+
+	it("should commit only the given files", async () => {
+		await fs.writeFile(path.join(cwd, "a.ts"), "export const a = 1\n")
+		await fs.writeFile(path.join(cwd, "b.ts"), "export const b = 1\n")
+
+		const result = await runGitSteps(cwd, planCommit(["a.ts"], "Add a"))
+
+		expect(result.failed).toBeUndefined()
+		expect(result.output).toContain("$ git commit --message 'Add a' -- a.ts")
+		expect((await git("show", "--name-only", "--format=%s", "HEAD")).stdout).toBe("Add a\n\na.ts")
+		expect((await git("status", "--porcelain")).stdout).toBe("?? b.ts")
+	})
+
+	it("should stop at the first failing step", async () => {
+		const result = await runGitSteps(cwd, [
+			["git", "add", "--", "missing.ts"],
+			["git", "commit", "--message", "Add missing", "--", "missing.ts"],
+		])
+
+		expect(result.failed).toEqual({ command: "git add -- missing.ts", exitCode: 128 })
+		expect(result.output).not.toContain("git commit")
+	})
+
+	it("should push and open a pull request with the CLI of the remote's provider", async () => {
+		await git("switch", "--create", "fix/login")
+		await git("remote", "add", "origin", "git@github.com:RooCodeInc/Roo-Code.git")
+
+		const github = await planPullRequest(cwd, { title: "Fix login", body: "Details", base: "main" })
+
+		expect(formatGitSteps(github)).toBe(
+			"git push --set-upstream origin fix/login && gh pr create --head fix/login --title 'Fix login' --body Details --base main",
+		)
+
+		await git("remote", "set-url", "origin", "https://gitlab.com/team/app.git")
+
+		const gitlab = await planPullRequest(cwd, { title: "Fix login", body: "" })
+
+		expect(formatGitSteps(gitlab)).toBe(
+			"git push --set-upstream origin fix/login && glab mr create --source-branch fix/login --title 'Fix login' --description '' --yes",
+		)
+	})
+
+	it("should not open a pull request from the base branch or without a remote", async () => {
+		await expect(planPullRequest(cwd, { title: "Fix login", body: "", base: "main" })).rejects.toThrow(
+			'The current branch is the base branch "main"',
+		)
+		await expect(planPullRequest(cwd, { title: "Fix login", body: "" })).rejects.toThrow(
+			'The repository has no remote named "origin"',
+		)
+	})
+})
@@ -0,0 +1,151 @@
+import { execa } from "execa"
+
+export type GitHostingProvider = "github" | "gitlab"
+
+// A program and its arguments, run without a shell so arguments need no escaping
+export type GitStep = string[]
+
+export interface GitStepsResult {
+	output: string
+	// The step that failed, if any; later steps are not run
+	failed?: { command: string; exitCode?: number }
+}
+
+const CLI_INSTALL_HINTS: Record<string, string> = {
+	gh: "The GitHub CLI (gh) is not installed. Install it from https://cli.github.com and run `gh auth login`.",
+	glab: "The GitLab CLI (glab) is not installed. Install it from https://gitlab.com/gitlab-org/cli and run `glab auth login`.",
+	git: "Git is not installed.",
+}
+
+async function git(cwd: string, ...args: string[]): Promise<string | undefined> {
+	const result = await execa("git", args, { cwd, reject: false, stdin: "ignore" })
+	return result.exitCode === 0 ? result.stdout.trim() : undefined
+}
+
+function quoteArgument(argument: string): string {
+	return /^[\w@%+=:,./-]+$/.test(argument) ? argument : `'${argument.replace(/'/g, `'\\''`)}'`
+}
+
+/**
+ * Formats steps as a shell command, for approval and for the command allow and
+ * deny lists.
+ */
+export function formatGitSteps(steps: GitStep[]): string {
+	return steps.map((step) => step.map(quoteArgument).join(" ")).join(" && ")
+}
+
+export function detectHostingProvider(remoteUrl: string): GitHostingProvider | undefined {
+	// Matches https://host/..., ssh://git@host/... and scp-like git@host:... remotes
+	const host = remoteUrl.match(/^(?:[a-z+]+:\/\/)?(?:[^@/]+@)?([^/:]+)/i)?.[1]?.toLowerCase() ?? ""
+
+	if (host.includes("github")) {
+		return "github"
+	}
+
+	if (host.includes("gitlab")) {
+		return "gitlab"
+	}
+
+	return undefined
+}
+
+export async function planCreateBranch(cwd: string, branch: string): Promise<GitStep[]> {
+	if ((await git(cwd, "check-ref-format", "--branch", branch)) === undefined) {
+		throw new Error(`"${branch}" is not a valid branch name.`)
+	}
+
+	if ((await git(cwd, "rev-parse", "--verify", "--quiet", `refs/heads/${branch}`)) !== undefined) {
+		throw new Error(`A branch named "${branch}" already exists.`)
+	}
+
+	return [["git", "switch", "--create", branch]]
+}
+
+/**
+ * Stages the files and commits only them, leaving other staged changes staged.
+ */
+export function planCommit(files: string[], message: string): GitStep[] {
+	return [
+		["git", "add", "--", ...files],
+		["git", "commit", "--message", message, "--", ...files],
+	]
+}
+
+/**
+ * Pushes the current branch and opens a pull request (GitHub) or merge request
+ * (GitLab) for it with the CLI of the hosting provider of its remote.
+ */
+export async function planPullRequest(
+	cwd: string,
+	{ title, body, base }: { title: string; body: string; base?: string },
+): Promise<GitStep[]> {
+	const branch = await git(cwd, "symbolic-ref", "--quiet", "--short", "HEAD")
+
+	if (!branch) {
+		throw new Error("HEAD is not on a branch. Create a branch for the changes first.")
+	}
+
+	if (branch === base) {
+		throw new Error(`The current branch is the base branch "${base}". Create a branch for the changes first.`)
+	}
+
+	const remote = (await git(cwd, "config", "--get", `branch.${branch}.remote`)) ?? "origin"
+	const remoteUrl = await git(cwd, "remote", "get-url", remote)
+
+	if (!remoteUrl) {
+		throw new Error(`The repository has no remote named "${remote}".`)
+	}
+
+	const push = ["git", "push", "--set-upstream", remote, branch]
+
+	switch (detectHostingProvider(remoteUrl)) {
+		case "github": {
+			const create = ["gh", "pr", "create", "--head", branch, "--title", title, "--body", body]
+			return [push, base ? [...create, "--base", base] : create]
+		}
+		case "gitlab": {
+			const create = ["glab", "mr", "create", "--source-branch", branch, "--title", title, "--description", body]
+			return [push, [...create, ...(base ? ["--target-branch", base] : []), "--yes"]]
+		}
+		default:
+			throw new Error(`Pull requests can only be created for GitHub and GitLab remotes, not ${remoteUrl}.`)
+	}
+}
+
+/**
+ * Runs the steps in order, stopping at the first step that fails.
+ */
+export async function runGitSteps(cwd: string, steps: GitStep[], signal?: AbortSignal): Promise<GitStepsResult> {
+	const outputs: string[] = []
+
+	for (const [program, ...args] of steps) {
+		const command = formatGitSteps([[program, ...args]])
+		const result = await execa(program, args, {
+			cwd,
+			all: true,
+			reject: false,
+			stdin: "ignore",
+			cancelSignal: signal,
+			env: {
+				...process.env,
+				// Fail instead of waiting for input that can't be given
+				GIT_TERMINAL_PROMPT: "0",
+				GH_PROMPT_DISABLED: "1",
+				NO_COLOR: "1",
+			},
+		})
+
+		const output = (result.all ?? "").trim()
+		outputs.push(`$ ${command}${output ? `\n${output}` : ""}`)
+
+		if ((result as { code?: string }).code === "ENOENT") {
+			outputs.push(CLI_INSTALL_HINTS[program] ?? `${program} is not installed.`)
+		}
+
+		if (result.failed) {
+			return { output: outputs.join("\n\n"), failed: { command, exitCode: result.exitCode } }
+		}
+	}
+
+	return { output: outputs.join("\n\n") }
+}
@@ -0,0 +1,163 @@
+// npx vitest run src/integrations/misc/__tests__/export-report.spec.ts
+
+import type { ClineMessage, HistoryItem } from "@roo-code/types"
+
+import { buildTaskReport, formatTaskReportMarkdown, getTaskReportFileName } from "../export-report"
+
+const historyItem: HistoryItem = {
+	id: "task-1",
+	number: 1,
+	ts: 2_000,
+	task: "Fix the failing test",
+	tokensIn: 0,
+	tokensOut: 0,
+	totalCost: 0,
+	mode: "code",
+}
+
+const apiRequest = (ts: number, info: Record<string, unknown>): ClineMessage => ({
+	ts,
+	type: "say",
+	say: "api_req_started",
+	text: JSON.stringify(info),
+})
+
+const messages: ClineMessage[] = [
+	{ ts: 1_000, type: "say", say: "text", text: "Fix the failing test" },
+	apiRequest(1_100, {
+		provider: "anthropic",
+		modelId: "claude-sonnet",
+		tokensIn: 1000,
+		tokensOut: 200,
+		cacheWrites: 100,
+		cacheReads: 500,
+		cost: 0.01,
+	}),
+	{ ts: 1_200, type: "say", say: "text", text: "Let me run the tests." },
+	{ ts: 1_300, type: "ask", ask: "command", text: "npm test" },
+	{ ts: 1_400, type: "say", say: "command_output", text: "1 failing" },
+	apiRequest(1_500, { provider: "openai", modelId: "gpt-4o", tokensIn: 2000, tokensOut: 300, cost: 0.02 }),
+	{
+		ts: 1_600,
+		type: "ask",
+		ask: "tool",
+		text: JSON.stringify({
+			tool: "appliedDiff",
+			path: "src/sum.ts",
+			diff: "-return a - b\n+return a + b",
+			originalContent: "return a - b",
+		}),
+	},
+	{ ts: 1_700, type: "ask", ask: "tool", text: JSON.stringify({ tool: "readFile", path: "src/sum.ts" }) },
+	apiRequest(1_800, {
+		provider: "anthropic",
+		modelId: "claude-sonnet",
+		tokensIn: 1000,
+		tokensOut: 100,
+		cacheReads: 900,
+		cost: 0.005,
+	}),
+	// A request that never got a response.
+	apiRequest(1_850, { provider: "anthropic", modelId: "claude-sonnet" }),
+	{ ts: 1_900, type: "say", say: "completion_result", text: "The test passes now." },
+]
+
+describe("buildTaskReport", () => {
+	it("adds up the usage of all requests", () => {
+		const report = buildTaskReport(historyItem, messages)
+
+		expect(report.requests).toHaveLength(3)
+		expect(report.totals).toMatchObject({
+			requests: 3,
+			tokensIn: 4000,
+			tokensOut: 600,
+			cacheWrites: 100,
+			cacheReads: 1400,
+		})
+		expect(report.totals.cost).toBeCloseTo(0.035)
+	})
+
+	it("groups the usage by provider and model, most expensive first", () => {
+		const { usageByModel } = buildTaskReport(historyItem, messages)
+
+		expect(usageByModel.map(({ provider, modelId, requests }) => [provider, modelId, requests])).toEqual([
+			["openai", "gpt-4o", 1],
+			["anthropic", "claude-sonnet", 2],
+		])
+		expect(usageByModel[1].cost).toBeCloseTo(0.015)
+	})
+
+	it("groups requests without a model as unknown", () => {
+		const { usageByModel } = buildTaskReport(historyItem, [apiRequest(1, { tokensIn: 10, tokensOut: 5 })])
+
+		expect(usageByModel).toEqual([
+			{
+				provider: "unknown",
+				modelId: "unknown",
+				requests: 1,
+				tokensIn: 10,
+				tokensOut: 5,
+				cacheWrites: 0,
+				cacheReads: 0,
+				cost: 0,
+			},
+		])
+	})
+
+	it("lists the conversation with the commands, tools and file changes", () => {
+		const { task, conversation } = buildTaskReport(historyItem, messages)
+
+		expect(task).toMatchObject({ id: "task-1", startedAt: 1_000, updatedAt: 2_000, mode: "code" })
+		expect(conversation).toEqual([
+			{ ts: 1_000, type: "user", text: "Fix the failing test", images: undefined },
+			{ ts: 1_200, type: "assistant", text: "Let me run the tests.", images: undefined },
+			{ ts: 1_300, type: "command", command: "npm test", output: "1 failing" },
+			{
+				ts: 1_600,
+				type: "file_change",
+				tool: "appliedDiff",
+				path: "src/sum.ts",
+				diff: "-return a - b\n+return a + b",
+			},
+			{ ts: 1_700, type: "tool", tool: "readFile", path: "src/sum.ts", details: {} },
+			{ ts: 1_900, type: "completion", text: "The test passes now." },
+		])
+	})
+
+	it("skips partial messages", () => {
+		const { conversation } = buildTaskReport(historyItem, [
+			{ ts: 1, type: "say", say: "text", text: "Fix the failing test" },
+			{ ts: 2, type: "say", say: "text", text: "Let me", partial: true },
+		])
+
+		expect(conversation).toHaveLength(1)
+	})
+})
+
+describe("formatTaskReportMarkdown", () => {
+	it("includes the usage tables and the conversation", () => {
+		const markdown = formatTaskReportMarkdown(buildTaskReport(historyItem, messages))
+
+		expect(markdown).toContain("# Task report: Fix the failing test")
+		expect(markdown).toContain("- **Total cost:** $0.0350")
+		expect(markdown).toContain("- **Cache:** 1400 read, 100 written (35% hit rate)")
+		expect(markdown).toContain("| anthropic | claude-sonnet | 2 | 2000 | 300 | 1400 | 100 | $0.0150 |")
+		expect(markdown).toContain("| gpt-4o | 2000 | 300 | 0 | 0 | 0% | $0.0200 |")
+		expect(markdown).toContain("```diff\n-return a - b\n+return a + b\n```")
+		expect(markdown).toContain("```sh\nnpm test\n```\n\nOutput:\n\n```\n1 failing\n```")
+	})
+
+	it("uses a longer fence for text that contains backticks", () => {
+		const markdown = formatTaskReportMarkdown(
+			buildTaskReport(historyItem, [{ ts: 1, type: "say", say: "error", text: "```\nboom\n```" }]),
+		)
+
+		expect(markdown).toContain("````\n```\nboom\n```\n````")
+	})
+})
+
+describe("getTaskReportFileName", () => {
+	it("marks the file as a report", () => {
+		expect(getTaskReportFileName(Date.now())).toMatch(/_report\.md$/)
+	})
+})
@@ -0,0 +1,150 @@
+// npx vitest run src/integrations/misc/__tests__/notebook.spec.ts
+
+import { editNotebookCell, formatNotebook, isNotebookPath } from "../notebook"
+
+const notebook = {
+	cells: [
+		{ cell_type: "markdown", id: "a1", metadata: {}, source: ["# Analysis\n", "Loads the data."] },
+		{
+			cell_type: "code",
+			execution_count: 2,
+			id: "b2",
+			metadata: { tags: ["setup"] },
+			outputs: [
+				{ name: "stdout", output_type: "stream", text: ["loaded 3 rows\n"] },
+				{ data: { "image/png": "iVBORw0KGgo=" }, metadata: {}, output_type: "display_data" },
+			],
+			source: ["import pandas as pd\n", "df = pd.read_csv('data.csv')"],
+		},
+	],
+	metadata: { kernelspec: { name: "python3" }, language_info: { name: "python" } },
+	nbformat: 4,
+	nbformat_minor: 5,
+}
+
+const content = JSON.stringify(notebook, null, 1) + "\n"
+
+const edit = (cellEdit: Parameters<typeof editNotebookCell>[1]) => JSON.parse(editNotebookCell(content, cellEdit))
+
+describe("isNotebookPath", () => {
+	it("should match .ipynb files", () => {
+		expect(isNotebookPath("notebooks/analysis.ipynb")).toBe(true)
+		expect(isNotebookPath("Analysis.IPYNB")).toBe(true)
+		expect(isNotebookPath("analysis.py")).toBe(false)
+	})
+})
+
+describe("formatNotebook", () => {
+	it("should show the cells with their index, type and outputs", () => {
+		expect(formatNotebook(content)).toBe(
+			[
+				"Jupyter notebook with 2 cells (python). Use edit_notebook to change cells by index.",
+				'<cell index="0" type="markdown">',
+				"# Analysis",
+				"Loads the data.",
+				"</cell>",
+				'<cell index="1" type="code" execution_count="2">',
+				"import pandas as pd",
+				"df = pd.read_csv('data.csv')",
+				"<output>",
+				"loaded 3 rows",
+				"[image/png output]",
+				"</output>",
+				"</cell>",
+			].join("\n"),
+		)
+	})
+
+	it("should accept sources stored as a single string", () => {
+		const single = JSON.stringify({ cells: [{ cell_type: "code", source: "x = 1", outputs: [] }] })
+
+		expect(formatNotebook(single)).toContain('<cell index="0" type="code">\nx = 1\n</cell>')
+	})
+
+	it("should truncate long outputs", () => {
+		const output = { name: "stdout", output_type: "stream", text: "x".repeat(3000) }
+		const long = JSON.stringify({ cells: [{ cell_type: "code", source: "print(x)", outputs: [output] }] })
+
+		expect(formatNotebook(long)).toContain("[... 1000 characters of output omitted]")
+	})
+
+	it("should reject JSON that isn't a notebook", () => {
+		expect(() => formatNotebook('{"name": "package"}')).toThrow("not a Jupyter notebook")
+	})
+})
+
+describe("editNotebookCell", () => {
+	it("should replace a cell's source and keep its outputs and metadata", () => {
+		const result = edit({ cellIndex: 1, editMode: "replace", newSource: "import numpy as np\nx = np.ones(3)\n" })
+
+		expect(result.cells[1]).toEqual({
+			...notebook.cells[1],
+			source: ["import numpy as np\n", "x = np.ones(3)\n"],
+		})
+		expect(result.cells[0]).toEqual(notebook.cells[0])
+		expect(result.metadata).toEqual(notebook.metadata)
+	})
+
+	it("should keep the file's formatting", () => {
+		const newSource = "# Analysis\nLoads the data."
+		const unchanged = editNotebookCell(content, { cellIndex: 0, editMode: "replace", newSource })
+		const indented = JSON.stringify(notebook, null, 2)
+		const crlf = content.replace(/\n/g, "\r\n")
+
+		expect(unchanged).toBe(content)
+		expect(editNotebookCell(indented, { cellIndex: 1, editMode: "delete" })).toBe(
+			JSON.stringify({ ...notebook, cells: [notebook.cells[0]] }, null, 2),
+		)
+		expect(editNotebookCell(crlf, { cellIndex: 1, editMode: "delete" })).toMatch(/^\{\r\n "cells"[\s\S]*\}\r\n$/)
+	})
+
+	it("should insert a new cell with an id", () => {
+		const result = edit({ cellIndex: 2, editMode: "insert", newSource: "df.describe()" })
+
+		expect(result.cells).toHaveLength(3)
+		expect(result.cells[2]).toEqual({
+			cell_type: "code",
+			execution_count: null,
+			id: expect.stringMatching(/^[0-9a-f]{8}$/),
+			metadata: {},
+			outputs: [],
+			source: ["df.describe()"],
+		})
+	})
+
+	it("should insert a markdown cell without outputs", () => {
+		const result = edit({ cellIndex: 0, editMode: "insert", newSource: "Intro", cellType: "markdown" })
+
+		expect(result.cells[0]).toEqual({
+			cell_type: "markdown",
+			id: expect.any(String),
+			metadata: {},
+			source: ["Intro"],
+		})
+		expect(result.cells[1]).toEqual(notebook.cells[0])
+	})
+
+	it("should drop outputs when a code cell becomes markdown", () => {
+		const result = edit({ cellIndex: 1, editMode: "replace", newSource: "Notes", cellType: "markdown" })
+
+		expect(result.cells[1]).toEqual({
+			cell_type: "markdown",
+			id: "b2",
+			metadata: { tags: ["setup"] },
+			source: ["Notes"],
+		})
+	})
+
+	it("should delete a cell", () => {
+		const result = edit({ cellIndex: 0, editMode: "delete" })
+
+		expect(result.cells).toEqual([notebook.cells[1]])
+	})
+
+	it("should reject an index out of range", () => {
+		expect(() => edit({ cellIndex: 2, editMode: "replace", newSource: "x" })).toThrow(
+			"Cell index 2 is out of range. The notebook has 2 cells, so cell_index must be between 0 and 1.",
+		)
+		expect(() => edit({ cellIndex: -1, editMode: "insert", newSource: "x" })).toThrow("out of range")
+	})
+})
@@ -0,0 +1,353 @@
+import * as vscode from "vscode"
+
+import type { ClineApiReqInfo, ClineMessage, ClineSayTool, HistoryItem } from "@roo-code/types"
+
+import { safeJsonParse } from "@roo-code/core"
+
+import { getTaskFileName } from "./export-markdown"
+
+// Tools whose messages carry the diff or the contents of a file change.
+const FILE_CHANGE_TOOLS = new Set<ClineSayTool["tool"]>(["editedExistingFile", "appliedDiff", "newFileCreated"])
+
+export interface TaskReportUsage {
+	requests: number
+	tokensIn: number
+	tokensOut: number
+	cacheWrites: number
+	cacheReads: number
+	cost: number
+}
+
+export interface TaskReportRequest {
+	ts: number
+	provider?: string
+	modelId?: string
+	tokensIn: number
+	tokensOut: number
+	cacheWrites: number
+	cacheReads: number
+	cost: number
+	cancelReason?: string
+}
+
+export type TaskReportEntry = { ts: number } & (
+	| { type: "user" | "assistant" | "reasoning" | "completion" | "error"; text: string; images?: number }
+	| { type: "tool"; tool: string; path?: string; details: Record<string, unknown>; output?: string }
+	| { type: "file_change"; tool: string; path?: string; diff: string }
+	| { type: "command"; command: string; output?: string }
+)
+
+/**
+ * A self-contained report of a task: what was asked and done, and what each
+ * API request cost.
+ */
+export interface TaskReport {
+	task: {
+		id: string
+		title: string
+		startedAt: number
+		updatedAt: number
+		workspace?: string
+		mode?: string
+		apiConfigName?: string
+	}
+	totals: TaskReportUsage
+	usageByModel: (TaskReportUsage & { provider: string; modelId: string })[]
+	requests: TaskReportRequest[]
+	conversation: TaskReportEntry[]
+}
+
+const emptyUsage = (): TaskReportUsage => ({
+	requests: 0,
+	tokensIn: 0,
+	tokensOut: 0,
+	cacheWrites: 0,
+	cacheReads: 0,
+	cost: 0,
+})
+
+const addUsage = (usage: TaskReportUsage, request: TaskReportRequest) => {
+	usage.requests++
+	usage.tokensIn += request.tokensIn
+	usage.tokensOut += request.tokensOut
+	usage.cacheWrites += request.cacheWrites
+	usage.cacheReads += request.cacheReads
+	usage.cost += request.cost
+}
+
+/**
+ * Builds the report of a task from its chat messages.
+ */
+export function buildTaskReport(historyItem: HistoryItem, messages: ClineMessage[]): TaskReport {
+	const requests: TaskReportRequest[] = []
+	const conversation: TaskReportEntry[] = []
+	// Outputs are attached to the command or tool they came from.
+	let lastCall: Extract<TaskReportEntry, { type: "command" | "tool" }> | undefined
+
+	messages.forEach((message, index) => {
+		if (message.partial) {
+			return
+		}
+
+		const { ts, text = "" } = message
+		const kind = message.type === "say" ? message.say : message.ask
+
+		switch (kind) {
+			case "api_req_started": {
+				const info = safeJsonParse<ClineApiReqInfo>(text) ?? {}
+
+				// Requests without usage never got a response.
+				if (info.tokensIn !== undefined || info.tokensOut !== undefined) {
+					requests.push({
+						ts,
+						provider: info.provider,
+						modelId: info.modelId,
+						tokensIn: info.tokensIn ?? 0,
+						tokensOut: info.tokensOut ?? 0,
+						cacheWrites: info.cacheWrites ?? 0,
+						cacheReads: info.cacheReads ?? 0,
+						cost: info.cost ?? 0,
+						cancelReason: info.cancelReason,
+					})
+				}
+				break
+			}
+			case "text":
+				// The first message is the task itself.
+				conversation.push({
+					ts,
+					type: index === 0 ? "user" : "assistant",
+					text,
+					images: message.images?.length,
+				})
+				break
+			case "user_feedback":
+				conversation.push({ ts, type: "user", text, images: message.images?.length })
+				break
+			case "followup":
+				conversation.push({
+					ts,
+					type: "assistant",
+					text: safeJsonParse<{ question?: string }>(text)?.question ?? text,
+				})
+				break
+			case "reasoning":
+				conversation.push({ ts, type: "reasoning", text })
+				break
+			case "completion_result":
+				if (text) {
+					conversation.push({ ts, type: "completion", text })
+				}
+				break
+			case "error":
+			case "api_req_failed":
+				conversation.push({ ts, type: "error", text })
+				break
+			case "command":
+				lastCall = { ts, type: "command", command: text }
+				conversation.push(lastCall)
+				break
+			case "command_output":
+			case "mcp_server_response":
+				if (lastCall) {
+					lastCall.output = lastCall.output ? `${lastCall.output}\n${text}` : text
+				}
+				break
+			case "use_mcp_server": {
+				const { serverName, toolName, uri, ...details } = safeJsonParse<Record<string, unknown>>(text) ?? {}
+				lastCall = { ts, type: "tool", tool: `mcp:${serverName}/${toolName ?? uri}`, details }
+				conversation.push(lastCall)
+				break
+			}
+			case "tool": {
+				const tool = safeJsonParse<ClineSayTool>(text)
+
+				if (!tool) {
+					break
+				}
+
+				// The original contents of edited files are left out, they can be large.
+				const { tool: name, path, diff, content, originalContent: _originalContent, ...details } = tool
+
+				if (FILE_CHANGE_TOOLS.has(name) && (diff || content)) {
+					conversation.push({ ts, type: "file_change", tool: name, path, diff: (diff ?? content)! })
+				} else {
+					lastCall = {
+						ts,
+						type: "tool",
+						tool: name,
+						path,
+						details: content ? { ...details, content } : details,
+					}
+					conversation.push(lastCall)
+				}
+				break
+			}
+		}
+	})
+
+	const totals = emptyUsage()
+	const usageByModel = new Map<string, TaskReport["usageByModel"][number]>()
+
+	for (const request of requests) {
+		addUsage(totals, request)
+
+		const provider = request.provider ?? "unknown"
+		const modelId = request.modelId ?? "unknown"
+		const key = `${provider}\n${modelId}`
+
+		if (!usageByModel.has(key)) {
+			usageByModel.set(key, { provider, modelId, ...emptyUsage() })
+		}
+
+		addUsage(usageByModel.get(key)!, request)
+	}
+
+	return {
+		task: {
+			id: historyItem.id,
+			title: historyItem.task,
+			startedAt: messages[0]?.ts ?? historyItem.ts,
+			updatedAt: historyItem.ts,
+			workspace: historyItem.workspace,
+			mode: historyItem.mode,
+			apiConfigName: historyItem.apiConfigName,
+		},
+		totals,
+		usageByModel: [...usageByModel.values()].sort((a, b) => b.cost - a.cost),
+		requests,
+		conversation,
+	}
+}
+
+const formatCost = (cost: number) => `$${cost.toFixed(4)}`
+
+// The share of the input tokens that were read from the prompt cache.
+const formatCacheHitRate = ({ tokensIn, cacheReads }: { tokensIn: number; cacheReads: number }) =>
+	tokensIn > 0 ? `${Math.round((cacheReads / tokensIn) * 100)}%` : "-"
+
+const formatDate = (ts: number) => new Date(ts).toISOString()
+
+// A fence longer than any backtick run in the text, so it can't be closed early.
+const codeBlock = (text: string, language = "") => {
+	const longestRun = Math.max(2, ...(text.match(/`+/g) ?? []).map((run) => run.length))
+	const fence = "`".repeat(longestRun + 1)
+	return `${fence}${language}\n${text}\n${fence}`
+}
+
+const formatEntryMarkdown = (entry: TaskReportEntry): string => {
+	const heading = (title: string) => `### ${title} · ${formatDate(entry.ts)}`
+
+	switch (entry.type) {
+		case "user":
+		case "assistant": {
+			const images = entry.images ? `\n\n_${entry.images} image(s) attached_` : ""
+			return `${heading(entry.type === "user" ? "User" : "Assistant")}\n\n${entry.text}${images}`
+		}
+		case "reasoning":
+			return `${heading("Reasoning")}\n\n${entry.text
+				.split("\n")
+				.map((line) => `> ${line}`)
+				.join("\n")}`
+		case "completion":
+			return `${heading("Completion")}\n\n${entry.text}`
+		case "error":
+			return `${heading("Error")}\n\n${codeBlock(entry.text)}`
+		case "command": {
+			const output = entry.output ? `\n\nOutput:\n\n${codeBlock(entry.output)}` : ""
+			return `${heading("Command")}\n\n${codeBlock(entry.command, "sh")}${output}`
+		}
+		case "file_change":
+			return `${heading(`File change: ${entry.path ?? entry.tool}`)}\n\n${codeBlock(entry.diff, "diff")}`
+		case "tool": {
+			const title = entry.path ? `${entry.tool} ${entry.path}` : entry.tool
+			const details =
+				Object.keys(entry.details).length > 0
+					? `\n\n${codeBlock(JSON.stringify(entry.details, null, 2), "json")}`
+					: ""
+			const output = entry.output ? `\n\nOutput:\n\n${codeBlock(entry.output)}` : ""
+			return `${heading(`Tool: ${title}`)}${details}${output}`
+		}
+	}
+}
+
+/**
+ * Formats the report as Markdown, with the usage tables first and then the
+ * conversation.
+ */
+export function formatTaskReportMarkdown(report: TaskReport): string {
+	const { task, totals } = report
+
+	const overview = [
+		`# Task report: ${task.title.split("\n")[0]}`,
+		"",
+		`- **Task ID:** ${task.id}`,
+		`- **Started:** ${formatDate(task.startedAt)}`,
+		`- **Last activity:** ${formatDate(task.updatedAt)}`,
+		...(task.workspace ? [`- **Workspace:** ${task.workspace}`] : []),
+		...(task.mode ? [`- **Mode:** ${task.mode}`] : []),
+		...(task.apiConfigName ? [`- **Provider profile:** ${task.apiConfigName}`] : []),
+		`- **Requests:** ${totals.requests}`,
+		`- **Tokens:** ${totals.tokensIn} in, ${totals.tokensOut} out`,
+		`- **Cache:** ${totals.cacheReads} read, ${totals.cacheWrites} written (${formatCacheHitRate(totals)} hit rate)`,
+		`- **Total cost:** ${formatCost(totals.cost)}`,
+	]
+
+	const usageByModel = [
+		"## Cost by provider and model",
+		"",
+		"| Provider | Model | Requests | Tokens in | Tokens out | Cache reads | Cache writes | Cost |",
+		"| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |",
+		...report.usageByModel.map(
+			(usage) =>
+				`| ${usage.provider} | ${usage.modelId} | ${usage.requests} | ${usage.tokensIn} | ${usage.tokensOut} | ${usage.cacheReads} | ${usage.cacheWrites} | ${formatCost(usage.cost)} |`,
+		),
+	]
+
+	const requests = [
+		"## Requests",
+		"",
+		"| # | Time | Model | Tokens in | Tokens out | Cache reads | Cache writes | Cache hit rate | Cost |",
+		"| ---: | --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |",
+		...report.requests.map(
+			(request, index) =>
+				`| ${index + 1} | ${formatDate(request.ts)} | ${request.modelId ?? "unknown"} | ${request.tokensIn} | ${request.tokensOut} | ${request.cacheReads} | ${request.cacheWrites} | ${formatCacheHitRate(request)} | ${formatCost(request.cost)}${request.cancelReason ? ` (${request.cancelReason})` : ""} |`,
+		),
+	]
+
+	const conversation = ["## Conversation", "", report.conversation.map(formatEntryMarkdown).join("\n\n")]
+
+	return [overview, usageByModel, requests, conversation].map((section) => section.join("\n")).join("\n\n") + "\n"
+}
+
+/**
+ * Asks where to save the report of a task and writes it there. The format is
+ * chosen with the file type: JSON for `.json` files, Markdown otherwise.
+ */
+export async function downloadTaskReport(
+	historyItem: HistoryItem,
+	messages: ClineMessage[],
+	defaultUri: vscode.Uri,
+): Promise<vscode.Uri | undefined> {
+	const saveUri = await vscode.window.showSaveDialog({
+		filters: { Markdown: ["md"], JSON: ["json"] },
+		defaultUri,
+	})
+
+	if (!saveUri) {
+		return undefined
+	}
+
+	const report = buildTaskReport(historyItem, messages)
+	const content = saveUri.path.toLowerCase().endsWith(".json")
+		? JSON.stringify(report, null, 2)
+		: formatTaskReportMarkdown(report)
+
+	await vscode.workspace.fs.writeFile(saveUri, Buffer.from(content))
+	vscode.window.showTextDocument(saveUri, { preview: true })
+	return saveUri
+}
+
+export function getTaskReportFileName(dateTs: number): string {
+	return getTaskFileName(dateTs).replace(/\.md$/, "_report.md")
+}
@@ -6,6 +6,7 @@ import fs from "fs/promises"
 import { isBinaryFile } from "isbinaryfile"
 import { extractTextFromXLSX } from "./extract-text-from-xlsx"
 import { readWithSlice } from "./indentation-reader"
+import { formatNotebook } from "./notebook"
 import { DEFAULT_LINE_LIMIT } from "../../core/prompts/tools/native-tools/read_file"
 
 async function extractTextFromPDF(filePath: string): Promise<string> {
@@ -21,16 +22,7 @@ async function extractTextFromDOCX(filePath: string): Promise<string> {
 
 async function extractTextFromIPYNB(filePath: string): Promise<string> {
 	const data = await fs.readFile(filePath, "utf8")
-	const notebook = JSON.parse(data)
-	let extractedText = ""
-
-	for (const cell of notebook.cells) {
-		if ((cell.cell_type === "markdown" || cell.cell_type === "code") && cell.source) {
-			extractedText += cell.source.join("\n") + "\n"
-		}
-	}
-
-	return addLineNumbers(extractedText)
+	return addLineNumbers(formatNotebook(data))
 }
 
 /**
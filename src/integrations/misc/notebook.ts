@@ -0,0 +1,203 @@
+import { randomUUID } from "crypto"
+
+/**
+ * Support for reading and editing Jupyter notebooks cell by cell. Notebooks are
+ * shown to the model as a list of numbered cells instead of raw JSON, and edits
+ * change the source of a single cell, so outputs, metadata and the file's JSON
+ * formatting are kept.
+ */
+
+export type NotebookCellType = "code" | "markdown" | "raw"
+
+export type NotebookEditMode = "replace" | "insert" | "delete"
+
+interface NotebookOutput {
+	output_type: string
+	name?: string
+	text?: string | string[]
+	data?: Record<string, unknown>
+	ename?: string
+	evalue?: string
+}
+
+interface NotebookCell {
+	cell_type: string
+	source: string | string[]
+	metadata?: Record<string, unknown>
+	outputs?: NotebookOutput[]
+	execution_count?: number | null
+	id?: string
+	[key: string]: unknown
+}
+
+interface Notebook {
+	cells: NotebookCell[]
+	metadata?: {
+		kernelspec?: { name?: string }
+		language_info?: { name?: string }
+		[key: string]: unknown
+	}
+	nbformat?: number
+	nbformat_minor?: number
+	[key: string]: unknown
+}
+
+export interface NotebookCellEdit {
+	cellIndex: number
+	editMode: NotebookEditMode
+	newSource?: string
+	cellType?: NotebookCellType
+}
+
+// Outputs are summarized so a plot or a long log doesn't fill the context.
+const MAX_OUTPUT_CHARS = 2000
+
+export function isNotebookPath(filePath: string): boolean {
+	return filePath.toLowerCase().endsWith(".ipynb")
+}
+
+export function parseNotebook(content: string): Notebook {
+	const notebook = JSON.parse(content)
+
+	if (!notebook || typeof notebook !== "object" || !Array.isArray(notebook.cells)) {
+		throw new Error("The file is not a Jupyter notebook: it has no cells array.")
+	}
+
+	return notebook
+}
+
+function joinSource(source: string | string[] | undefined): string {
+	return Array.isArray(source) ? source.join("") : (source ?? "")
+}
+
+// nbformat stores multiline strings as a list of lines, each ending in a newline except the last.
+function splitSource(source: string): string[] {
+	return source.match(/[^\n]*\n|[^\n]+$/g) ?? []
+}
+
+function formatOutput(output: NotebookOutput): string {
+	switch (output.output_type) {
+		case "stream":
+			return joinSource(output.text)
+		case "error":
+			return `${output.ename}: ${output.evalue}`
+		case "execute_result":
+		case "display_data": {
+			const data = output.data ?? {}
+			const text = data["text/plain"] as string | string[] | undefined
+
+			if (text !== undefined) {
+				return joinSource(text)
+			}
+
+			const mimeTypes = Object.keys(data)
+			return mimeTypes.length > 0 ? `[${mimeTypes.join(", ")} output]` : ""
+		}
+		default:
+			return ""
+	}
+}
+
+function formatOutputs(outputs: NotebookOutput[]): string {
+	const text = outputs
+		.map(formatOutput)
+		.filter(Boolean)
+		.map((output) => output.replace(/\n$/, ""))
+		.join("\n")
+
+	return text.length > MAX_OUTPUT_CHARS
+		? `${text.slice(0, MAX_OUTPUT_CHARS)}\n[... ${text.length - MAX_OUTPUT_CHARS} characters of output omitted]`
+		: text
+}
+
+/**
+ * Renders a notebook as numbered cells with their type, source and a summary
+ * of their outputs, for the model to read instead of the notebook's JSON.
+ */
+export function formatNotebook(content: string): string {
+	const notebook = parseNotebook(content)
+	const language = notebook.metadata?.language_info?.name ?? notebook.metadata?.kernelspec?.name
+	const cellCount = `${notebook.cells.length} ${notebook.cells.length === 1 ? "cell" : "cells"}`
+	const header = `Jupyter notebook with ${cellCount}${language ? ` (${language})` : ""}. Use edit_notebook to change cells by index.`
+
+	const cells = notebook.cells.map((cell, index) => {
+		const executionCount = cell.execution_count != null ? ` execution_count="${cell.execution_count}"` : ""
+		const source = joinSource(cell.source).replace(/\n$/, "")
+		const outputs = cell.cell_type === "code" && cell.outputs?.length ? formatOutputs(cell.outputs) : ""
+
+		return [
+			`<cell index="${index}" type="${cell.cell_type}"${executionCount}>`,
+			source,
+			...(outputs ? ["<output>", outputs, "</output>"] : []),
+			"</cell>",
+		].join("\n")
+	})
+
+	return [header, ...cells].join("\n")
+}
+
+function createCell(notebook: Notebook, cellType: NotebookCellType, source: string): NotebookCell {
+	// Cell ids are required from nbformat 4.5 on.
+	const hasIds = (notebook.nbformat ?? 4) > 4 || (notebook.nbformat === 4 && (notebook.nbformat_minor ?? 0) >= 5)
+	const id = hasIds ? { id: randomUUID().replace(/-/g, "").slice(0, 8) } : {}
+
+	return cellType === "code"
+		? { cell_type: cellType, execution_count: null, ...id, metadata: {}, outputs: [], source: splitSource(source) }
+		: { cell_type: cellType, ...id, metadata: {}, source: splitSource(source) }
+}
+
+function changeCellType(cell: NotebookCell, cellType: NotebookCellType): NotebookCell {
+	if (cell.cell_type === cellType) {
+		return cell
+	}
+
+	const { outputs: _outputs, execution_count: _executionCount, ...rest } = cell
+
+	// Only code cells have outputs and an execution count.
+	return cellType === "code"
+		? { ...rest, cell_type: cellType, execution_count: null, outputs: [] }
+		: { ...rest, cell_type: cellType }
+}
+
+/**
+ * Replaces, inserts or deletes a single cell and returns the notebook's new
+ * JSON. Other cells are left untouched, and an edited cell keeps its metadata
+ * and outputs. The indentation and trailing newline of the file are kept.
+ */
+export function editNotebookCell(content: string, edit: NotebookCellEdit): string {
+	const notebook = parseNotebook(content)
+	const { cellIndex, editMode, newSource = "", cellType } = edit
+	const cellCount = notebook.cells.length
+	const maxIndex = editMode === "insert" ? cellCount : cellCount - 1
+
+	if (!Number.isInteger(cellIndex) || cellIndex < 0 || cellIndex > maxIndex) {
+		throw new Error(
+			cellCount === 0 && editMode !== "insert"
+				? "The notebook has no cells. Use edit_mode 'insert' with cell_index 0 to add one."
+				: `Cell index ${cellIndex} is out of range. The notebook has ${cellCount} cells, so cell_index must be between 0 and ${maxIndex}.`,
+		)
+	}
+
+	switch (editMode) {
+		case "insert":
+			notebook.cells.splice(cellIndex, 0, createCell(notebook, cellType ?? "code", newSource))
+			break
+		case "delete":
+			notebook.cells.splice(cellIndex, 1)
+			break
+		case "replace": {
+			const cell = notebook.cells[cellIndex]
+			const retyped = cellType ? changeCellType(cell, cellType) : cell
+			notebook.cells[cellIndex] = { ...retyped, source: splitSource(newSource) }
+			break
+		}
+		default:
+			throw new Error(`Unknown edit_mode '${editMode}'. Use 'replace', 'insert' or 'delete'.`)
+	}
+
+	const indent = content.match(/^\{\r?\n([ \t]+)"/)?.[1] ?? 1
+	const newline = content.includes("\r\n") ? "\r\n" : "\n"
+	const json = JSON.stringify(notebook, null, indent).replace(/\n/g, newline)
+
+	return /\r?\n$/.test(content) ? `${json}${newline}` : json
+}
@@ -0,0 +1,216 @@
+import { execa } from "execa"
+import psTree from "ps-tree"
+import process from "process"
+import stripAnsi from "strip-ansi"
+
+import { truncateOutput } from "../misc/extract-text"
+import { BaseTerminal } from "./BaseTerminal"
+
+// Only the end of the output is kept, so a chatty dev server can't grow without bound.
+const MAX_BUFFERED_CHARACTERS = 1_000_000
+// How long a process gets to exit after SIGTERM before it is killed
+const STOP_TIMEOUT_MS = 5_000
+
+export type BackgroundProcessStatus = "running" | "exited" | "stopped"
+
+export interface BackgroundProcessInfo {
+	id: number
+	command: string
+	cwd: string
+	pid?: number
+	status: BackgroundProcessStatus
+	exitCode?: number
+	startedAt: number
+}
+
+export interface BackgroundProcessOutput {
+	output: string
+	// Whether the output was produced since it was last read
+	isNew: boolean
+}
+
+interface BackgroundProcess extends BackgroundProcessInfo {
+	output: string
+	// Characters trimmed from the start of the output, so read positions stay valid after trimming
+	trimmedCharacters: number
+	readPosition: number
+	exited: Promise<void>
+}
+
+export function formatBackgroundProcess({ id, command, status, exitCode }: BackgroundProcessInfo): string {
+	const exitStatus = status === "exited" && exitCode !== undefined ? ` with exit code ${exitCode}` : ""
+	return `Process ${id}: \`${command}\` (${status}${exitStatus})`
+}
+
+function killProcessTree(pid: number, signal: NodeJS.Signals): Promise<void> {
+	return new Promise((resolve) => {
+		psTree(pid, (err, children) => {
+			const pids = err ? [] : children.map((child) => parseInt(child.PID))
+
+			for (const target of [...pids, pid]) {
+				try {
+					process.kill(target, signal)
+				} catch {
+					// The process already exited
+				}
+			}
+
+			resolve()
+		})
+	})
+}
+
+/**
+ * Runs commands that don't exit on their own, such as dev servers and watchers,
+ * outside of the terminal so they don't block the task. Each task has its own
+ * manager, and the processes are stopped when the task is disposed.
+ */
+export class BackgroundProcessManager {
+	private processes = new Map<number, BackgroundProcess>()
+	private nextId = 1
+
+	public start(command: string, cwd: string): BackgroundProcessInfo {
+		const subprocess = execa({
+			shell: BaseTerminal.getExecaShellPath() || true,
+			cwd,
+			all: true,
+			reject: false,
+			// Ignore stdin to ensure non-interactive mode and prevent hanging
+			stdin: "ignore",
+			env: {
+				...process.env,
+				FORCE_COLOR: "0",
+				NO_COLOR: "1",
+			},
+		})`${command}`
+
+		const backgroundProcess: BackgroundProcess = {
+			id: this.nextId++,
+			command,
+			cwd,
+			pid: subprocess.pid,
+			status: "running",
+			startedAt: Date.now(),
+			output: "",
+			trimmedCharacters: 0,
+			readPosition: 0,
+			exited: Promise.resolve(),
+		}
+
+		subprocess.all?.on("data", (chunk: Buffer) => this.appendOutput(backgroundProcess, chunk.toString()))
+
+		backgroundProcess.exited = subprocess
+			.then((result) => {
+				backgroundProcess.exitCode = result.exitCode
+
+				// Spawn errors, e.g. a missing working directory, don't produce any output.
+				if (result.failed && result.exitCode === undefined && !result.signal) {
+					this.appendOutput(backgroundProcess, `${result.shortMessage}\n`)
+				}
+			})
+			.catch(() => {})
+			.finally(() => {
+				if (backgroundProcess.status === "running") {
+					backgroundProcess.status = "exited"
+				}
+			})
+
+		this.processes.set(backgroundProcess.id, backgroundProcess)
+
+		return this.toInfo(backgroundProcess)
+	}
+
+	public list(): BackgroundProcessInfo[] {
+		return [...this.processes.values()].map((backgroundProcess) => this.toInfo(backgroundProcess))
+	}
+
+	public get(id: number): BackgroundProcessInfo | undefined {
+		const backgroundProcess = this.processes.get(id)
+		return backgroundProcess ? this.toInfo(backgroundProcess) : undefined
+	}
+
+	/**
+	 * Waits up to the timeout for the process to exit. Returns whether it exited.
+	 */
+	public async waitForExit(id: number, timeoutMs: number): Promise<boolean> {
+		const backgroundProcess = this.processes.get(id)
+
+		if (!backgroundProcess) {
+			return true
+		}
+
+		let timeoutId: NodeJS.Timeout | undefined
+
+		const timeout = new Promise<"timeout">((resolve) => {
+			timeoutId = setTimeout(() => resolve("timeout"), timeoutMs)
+		})
+
+		const result = await Promise.race([backgroundProcess.exited, timeout])
+		clearTimeout(timeoutId)
+
+		return result !== "timeout"
+	}
+
+	/**
+	 * Returns the output produced since the last read, or the end of the output
+	 * if there is nothing new, limited to the given number of lines and characters.
+	 */
+	public readOutput(id: number, lineLimit: number, characterLimit: number): BackgroundProcessOutput | undefined {
+		const backgroundProcess = this.processes.get(id)
+
+		if (!backgroundProcess) {
+			return undefined
+		}
+
+		const end = backgroundProcess.trimmedCharacters + backgroundProcess.output.length
+		const start = Math.max(backgroundProcess.readPosition - backgroundProcess.trimmedCharacters, 0)
+		const isNew = backgroundProcess.readPosition < end
+		const output = isNew ? backgroundProcess.output.slice(start) : backgroundProcess.output
+
+		backgroundProcess.readPosition = end
+
+		return { output: truncateOutput(output, lineLimit, characterLimit), isNew }
+	}
+
+	public async stop(id: number): Promise<BackgroundProcessInfo | undefined> {
+		const backgroundProcess = this.processes.get(id)
+
+		if (!backgroundProcess) {
+			return undefined
+		}
+
+		if (backgroundProcess.status === "running" && backgroundProcess.pid) {
+			backgroundProcess.status = "stopped"
+			await killProcessTree(backgroundProcess.pid, "SIGTERM")
+
+			if (!(await this.waitForExit(id, STOP_TIMEOUT_MS))) {
+				await killProcessTree(backgroundProcess.pid, "SIGKILL")
+			}
+		}
+
+		return this.toInfo(backgroundProcess)
+	}
+
+	/**
+	 * Stops every running process.
+	 */
+	public async dispose(): Promise<void> {
+		const running = [...this.processes.values()].filter(({ status }) => status === "running")
+		await Promise.all(running.map(({ id }) => this.stop(id)))
+	}
+
+	private appendOutput(backgroundProcess: BackgroundProcess, chunk: string) {
+		backgroundProcess.output += stripAnsi(chunk)
+
+		const excess = backgroundProcess.output.length - MAX_BUFFERED_CHARACTERS
+
+		if (excess > 0) {
+			backgroundProcess.output = backgroundProcess.output.slice(excess)
+			backgroundProcess.trimmedCharacters += excess
+		}
+	}
+
+	private toInfo({ id, command, cwd, pid, status, exitCode, startedAt }: BackgroundProcess): BackgroundProcessInfo {
+		return { id, command, cwd, pid, status, exitCode, startedAt }
+	}
+}
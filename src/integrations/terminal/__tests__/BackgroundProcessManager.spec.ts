@@ -0,0 +1,70 @@
+// npx vitest src/integrations/terminal/__tests__/BackgroundProcessManager.spec.ts
+
+import * as os from "os"
+
+import { BackgroundProcessManager, formatBackgroundProcess } from "../BackgroundProcessManager"
+
+const node = (script: string) => `node -e "${script}"`
+
+describe("BackgroundProcessManager", () => {
+	let manager: BackgroundProcessManager
+
+	beforeEach(() => {
+		manager = new BackgroundProcessManager()
+	})
+
+	afterEach(async () => {
+		await manager.dispose()
+	})
+
+	it("should report the output and exit code of a process", async () => {
+		const { id } = manager.start(node("console.log('ready'); process.exit(3)"), os.tmpdir())
+
+		expect(await manager.waitForExit(id, 10_000)).toBe(true)
+		expect(manager.get(id)).toMatchObject({ status: "exited", exitCode: 3 })
+		expect(manager.readOutput(id, 100, 10_000)).toEqual({ output: "ready\n", isNew: true })
+		expect(manager.readOutput(id, 100, 10_000)).toEqual({ output: "ready\n", isNew: false })
+	})
+
+	it("should only return the output produced since the last read", async () => {
+		const { id } = manager.start(node("console.log('one'); setTimeout(() => console.log('two'), 300)"), os.tmpdir())
+
+		await vi.waitFor(() => expect(manager.readOutput(id, 100, 10_000)?.output).toBe("one\n"), { timeout: 10_000 })
+		await manager.waitForExit(id, 10_000)
+
+		expect(manager.readOutput(id, 100, 10_000)).toEqual({ output: "two\n", isNew: true })
+	})
+
+	it("should stop a running process", async () => {
+		const { id } = manager.start(node("setInterval(() => console.log('tick'), 50)"), os.tmpdir())
+
+		expect(await manager.waitForExit(id, 200)).toBe(false)
+		expect(manager.get(id)?.status).toBe("running")
+
+		await manager.stop(id)
+
+		expect(await manager.waitForExit(id, 1_000)).toBe(true)
+		expect(manager.get(id)?.status).toBe("stopped")
+	})
+
+	it("should stop every running process when disposed", async () => {
+		const first = manager.start(node("setInterval(() => {}, 1000)"), os.tmpdir())
+		const second = manager.start(node("setInterval(() => {}, 1000)"), os.tmpdir())
+
+		await manager.dispose()
+
+		expect(manager.list().map(({ id, status }) => ({ id, status }))).toEqual([
+			{ id: first.id, status: "stopped" },
+			{ id: second.id, status: "stopped" },
+		])
+	})
+
+	it("should format a process for the model", () => {
+		const info = { id: 2, command: "tsc --watch", cwd: "/repo", startedAt: 0 }
+
+		expect(formatBackgroundProcess({ ...info, status: "running" })).toBe("Process 2: `tsc --watch` (running)")
+		expect(formatBackgroundProcess({ ...info, status: "exited", exitCode: 1 })).toBe(
+			"Process 2: `tsc --watch` (exited with exit code 1)",
+		)
+	})
+})
@@ -0,0 +1,112 @@
+// npx vitest src/integrations/test-runner/__tests__/detect.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+
+import { detectTestCommand, inferTestFramework, withStructuredOutput } from "../detect"
+
+describe("detectTestCommand", () => {
+	let cwd: string
+	const reportFile = "/tmp/report.json"
+
+	const writeFile = (name: string, content = "") => fs.writeFile(path.join(cwd, name), content)
+
+	beforeEach(async () => {
+		cwd = await fs.mkdtemp(path.join(os.tmpdir(), "test-runner-"))
+	})
+
+	afterEach(async () => {
+		await fs.rm(cwd, { recursive: true, force: true })
+	})
+
+	it("should run vitest with the package manager of the project and a JSON report", async () => {
+		await writeFile("package.json", JSON.stringify({ devDependencies: { vitest: "^3.0.0" } }))
+		await writeFile("pnpm-lock.yaml")
+
+		expect(await detectTestCommand(cwd, { testPath: "src/math.spec.ts", reportFile })).toEqual({
+			framework: "vitest",
+			command: `pnpm exec vitest run src/math.spec.ts --reporter=default --reporter=json --outputFile.json=${reportFile}`,
+			reportFile,
+		})
+	})
+
+	it("should run jest with a JSON report", async () => {
+		await writeFile("package.json", JSON.stringify({ scripts: { test: "jest --coverage" } }))
+
+		expect(await detectTestCommand(cwd, { reportFile })).toEqual({
+			framework: "jest",
+			command: `npx jest --json --outputFile=${reportFile}`,
+			reportFile,
+		})
+	})
+
+	it("should run the packages of a Go path with JSON events", async () => {
+		await writeFile("go.mod", "module example.com/app")
+
+		expect((await detectTestCommand(cwd, { reportFile }))?.command).toBe("go test -json ./...")
+		expect((await detectTestCommand(cwd, { testPath: "internal/store/", reportFile }))?.command).toBe(
+			"go test -json ./internal/store/...",
+		)
+		expect((await detectTestCommand(cwd, { testPath: "internal/store/store_test.go", reportFile }))?.command).toBe(
+			"go test -json ./internal/store",
+		)
+	})
+
+	it("should detect pytest from its configuration", async () => {
+		await writeFile("pyproject.toml", "[tool.pytest.ini_options]\naddopts = '-q'\n")
+
+		expect(await detectTestCommand(cwd, { testPath: "tests/test_math.py", reportFile })).toEqual({
+			framework: "pytest",
+			command: "pytest --tb=short tests/test_math.py -rf",
+		})
+	})
+
+	it("should fall back to the test script of package.json", async () => {
+		await writeFile("package.json", JSON.stringify({ scripts: { test: "mocha" } }))
+		await writeFile("yarn.lock")
+
+		expect(await detectTestCommand(cwd, { reportFile })).toEqual({ framework: "unknown", command: "yarn test" })
+	})
+
+	it("should quote paths with shell metacharacters", async () => {
+		const originalPlatform = process.platform
+		Object.defineProperty(process, "platform", { value: "linux" })
+		await writeFile("pytest.ini")
+
+		try {
+			const { command } = (await detectTestCommand(cwd, { testPath: `tests/$(touch x) "it's".py`, reportFile }))!
+
+			expect(command).toBe(`pytest --tb=short 'tests/$(touch x) "it'\\''s".py' -rf`)
+		} finally {
+			Object.defineProperty(process, "platform", { value: originalPlatform })
+		}
+	})
+
+	it("should return undefined without a test setup", async () => {
+		const test = 'echo "Error: no test specified" && exit 1'
+		await writeFile("package.json", JSON.stringify({ scripts: { test } }))
+
+		expect(await detectTestCommand(cwd, { reportFile })).toBeUndefined()
+	})
+})
+
+describe("custom test commands", () => {
+	it("should infer the framework of a command", () => {
+		expect(inferTestFramework("pnpm vitest run src")).toBe("vitest")
+		expect(inferTestFramework("go test ./... -run TestFind")).toBe("go")
+		expect(inferTestFramework("cargo +nightly test")).toBe("cargo")
+		expect(inferTestFramework("make test")).toBe("unknown")
+	})
+
+	it("should add structured output flags the command doesn't have", () => {
+		expect(withStructuredOutput("go test ./store -run TestFind", "go", "/tmp/r.json").command).toBe(
+			"go test -json ./store -run TestFind",
+		)
+		expect(withStructuredOutput("npx vitest run --reporter=verbose", "vitest", "/tmp/r.json")).toEqual({
+			framework: "vitest",
+			command: "npx vitest run --reporter=verbose",
+		})
+		expect(withStructuredOutput("pytest -ra", "pytest", "/tmp/r.json").command).toBe("pytest -ra")
+	})
+})
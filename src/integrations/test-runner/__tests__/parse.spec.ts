@@ -0,0 +1,203 @@
+// npx vitest src/integrations/test-runner/__tests__/parse.spec.ts
+
+import {
+	parseCargoOutput,
+	parseGoTestOutput,
+	parseJestReport,
+	parsePytestOutput,
+	parseTestResults,
+} from "../parse"
+
+describe("parseJestReport", () => {
+	it("should list failed tests and test files that failed to run", () => {
+		const results = parseJestReport(
+			{
+				numPassedTests: 3,
+				numPendingTests: 1,
+				numTodoTests: 1,
+				testResults: [
+					{
+						name: "/repo/src/math.spec.ts",
+						status: "failed",
+						assertionResults: [
+							{ title: "adds", ancestorTitles: ["math"], status: "passed" },
+							{
+								fullName: "math subtracts",
+								title: "subtracts",
+								status: "failed",
+								failureMessages: [
+									"\u001b[31mAssertionError: expected 1 to be 2\u001b[39m\n    at /repo/src/math.spec.ts:12:5",
+								],
+							},
+						],
+					},
+					{
+						name: "/repo/src/broken.spec.ts",
+						status: "failed",
+						message: "SyntaxError: Unexpected token (3:1)",
+						assertionResults: [],
+					},
+				],
+			},
+			"/repo",
+		)
+
+		expect(results).toEqual({
+			passed: 3,
+			failed: 2,
+			skipped: 2,
+			failures: [
+				{
+					name: "math subtracts",
+					file: "src/math.spec.ts",
+					line: 12,
+					message: "AssertionError: expected 1 to be 2\n    at /repo/src/math.spec.ts:12:5",
+				},
+				{
+					name: "(test file failed to run)",
+					file: "src/broken.spec.ts",
+					line: undefined,
+					message: "SyntaxError: Unexpected token (3:1)",
+				},
+			],
+		})
+	})
+
+	it("should only parse reports from vitest and jest", () => {
+		expect(parseTestResults("vitest", "", { cwd: "/repo", report: undefined })).toBeUndefined()
+		expect(parseTestResults("jest", "", { cwd: "/repo", report: { testResults: [] } })?.failed).toBe(0)
+	})
+})
+
+describe("parseGoTestOutput", () => {
+	const event = (event: Record<string, string>) => JSON.stringify({ Package: "example.com/app/store", ...event })
+
+	it("should report failing subtests with their output", () => {
+		const output = [
+			event({ Action: "run", Test: "TestFind" }),
+			event({ Action: "output", Test: "TestFind", Output: "=== RUN   TestFind\n" }),
+			event({ Action: "run", Test: "TestFind/missing" }),
+			event({
+				Action: "output",
+				Test: "TestFind/missing",
+				Output: "    store_test.go:42: expected nil, got error\n",
+			}),
+			event({ Action: "output", Test: "TestFind/missing", Output: "--- FAIL: TestFind/missing (0.00s)\n" }),
+			event({ Action: "fail", Test: "TestFind/missing" }),
+			event({ Action: "fail", Test: "TestFind" }),
+			event({ Action: "pass", Test: "TestSave" }),
+			event({ Action: "skip", Test: "TestSlow" }),
+			event({ Action: "fail" }),
+		].join("\n")
+
+		expect(parseGoTestOutput(output)).toEqual({
+			passed: 1,
+			failed: 1,
+			skipped: 1,
+			failures: [
+				{
+					name: "TestFind/missing (example.com/app/store)",
+					file: "store_test.go",
+					line: 42,
+					message: "store_test.go:42: expected nil, got error",
+				},
+			],
+		})
+	})
+
+	it("should report packages that failed to build", () => {
+		const output = [
+			"# example.com/app/store",
+			"store/store.go:10:2: undefined: missing",
+			event({ Action: "output", Output: "FAIL\texample.com/app/store [build failed]\n" }),
+			event({ Action: "fail" }),
+		].join("\n")
+
+		const results = parseGoTestOutput(output)
+
+		expect(results?.failures).toHaveLength(1)
+		expect(results?.failures[0].message).toContain("store/store.go:10:2: undefined: missing")
+	})
+
+	it("should return undefined for output without events", () => {
+		expect(parseGoTestOutput("go: cannot find main module")).toBeUndefined()
+	})
+})
+
+describe("parsePytestOutput", () => {
+	it("should report failures with their traceback", () => {
+		const output = `============================= test session starts ==============================
+collected 3 items
+
+tests/test_math.py .F.                                                   [100%]
+
+=================================== FAILURES ===================================
+_______________________________ TestMath.test_add _______________________________
+tests/test_math.py:12: in test_add
+    assert add(1, 2) == 4
+E   assert 3 == 4
+=========================== short test summary info ============================
+FAILED tests/test_math.py::TestMath::test_add - assert 3 == 4
+========================= 1 failed, 2 passed in 0.05s ==========================`
+
+		expect(parsePytestOutput(output)).toEqual({
+			passed: 2,
+			failed: 1,
+			skipped: 0,
+			failures: [
+				{
+					name: "TestMath::test_add",
+					file: "tests/test_math.py",
+					line: 12,
+					message: "tests/test_math.py:12: in test_add\n    assert add(1, 2) == 4\nE   assert 3 == 4",
+				},
+			],
+		})
+	})
+
+	it("should report passing runs", () => {
+		expect(parsePytestOutput("5 passed, 1 skipped in 0.20s")).toEqual({
+			passed: 5,
+			failed: 0,
+			skipped: 1,
+			failures: [],
+		})
+	})
+})
+
+describe("parseCargoOutput", () => {
+	it("should report failing tests with the location of the panic", () => {
+		const output = `running 2 tests
+test tests::adds ... ok
+test tests::subtracts ... FAILED
+
+failures:
+
+---- tests::subtracts stdout ----
+thread 'tests::subtracts' panicked at src/lib.rs:10:9:
+assertion \`left == right\` failed
+
+failures:
+    tests::subtracts
+
+test result: FAILED. 1 passed; 1 failed; 0 ignored; 0 measured; 0 filtered out; finished in 0.00s`
+
+		expect(parseCargoOutput(output)).toEqual({
+			passed: 1,
+			failed: 1,
+			skipped: 0,
+			failures: [
+				{
+					name: "tests::subtracts",
+					file: "src/lib.rs",
+					line: 10,
+					message: "thread 'tests::subtracts' panicked at src/lib.rs:10:9:\nassertion `left == right` failed",
+				},
+			],
+		})
+	})
+
+	it("should return undefined when the tests did not run", () => {
+		expect(parseCargoOutput("error[E0425]: cannot find value `x` in this scope")).toBeUndefined()
+	})
+})
@@ -0,0 +1,201 @@
+import * as path from "path"
+import fs from "fs/promises"
+
+import { fileExistsAtPath } from "../../utils/fs"
+
+export type TestFramework = "vitest" | "jest" | "go" | "pytest" | "cargo" | "unknown"
+
+export interface TestCommand {
+	framework: TestFramework
+	command: string
+	// File the JSON report is written to, for frameworks that write one
+	reportFile?: string
+}
+
+interface PackageJson {
+	scripts?: Record<string, string>
+	dependencies?: Record<string, string>
+	devDependencies?: Record<string, string>
+}
+
+// The test script `npm init` creates, which only fails
+const NPM_DEFAULT_TEST_SCRIPT = /no test specified/
+
+/**
+ * Quotes an argument for the shell the test command runs in. Nothing is
+ * special between single quotes in sh; cmd.exe only honors double quotes,
+ * which can't be part of a Windows file name.
+ */
+function quote(value: string): string {
+	if (/^[\w@+=:,./-]+$/.test(value)) {
+		return value
+	}
+
+	return process.platform === "win32" ? `"${value}"` : `'${value.replace(/'/g, `'\\''`)}'`
+}
+
+async function readPackageJson(cwd: string): Promise<PackageJson | undefined> {
+	try {
+		return JSON.parse(await fs.readFile(path.join(cwd, "package.json"), "utf-8"))
+	} catch {
+		return undefined
+	}
+}
+
+async function fileContains(filePath: string, pattern: RegExp): Promise<boolean> {
+	try {
+		return pattern.test(await fs.readFile(filePath, "utf-8"))
+	} catch {
+		return false
+	}
+}
+
+async function getPackageManager(cwd: string): Promise<"npm" | "pnpm" | "yarn" | "bun"> {
+	if (await fileExistsAtPath(path.join(cwd, "pnpm-lock.yaml"))) {
+		return "pnpm"
+	}
+
+	if (await fileExistsAtPath(path.join(cwd, "yarn.lock"))) {
+		return "yarn"
+	}
+
+	if (
+		(await fileExistsAtPath(path.join(cwd, "bun.lockb"))) ||
+		(await fileExistsAtPath(path.join(cwd, "bun.lock")))
+	) {
+		return "bun"
+	}
+
+	return "npm"
+}
+
+async function usesPytest(cwd: string): Promise<boolean> {
+	return (
+		(await fileExistsAtPath(path.join(cwd, "pytest.ini"))) ||
+		(await fileExistsAtPath(path.join(cwd, "conftest.py"))) ||
+		(await fileContains(path.join(cwd, "pyproject.toml"), /\[tool\.pytest|\bpytest\b/)) ||
+		(await fileContains(path.join(cwd, "setup.cfg"), /\[tool:pytest\]/)) ||
+		(await fileContains(path.join(cwd, "tox.ini"), /\[pytest\]/))
+	)
+}
+
+/**
+ * Infers the test framework a command runs, so that its output can be parsed.
+ */
+export function inferTestFramework(command: string): TestFramework {
+	if (/\bvitest\b/.test(command)) {
+		return "vitest"
+	}
+
+	if (/\bjest\b/.test(command)) {
+		return "jest"
+	}
+
+	if (/\bgo\s+test\b/.test(command)) {
+		return "go"
+	}
+
+	if (/\bpytest\b/.test(command)) {
+		return "pytest"
+	}
+
+	if (/\bcargo\s+(?:\+\S+\s+)?test\b/.test(command)) {
+		return "cargo"
+	}
+
+	return "unknown"
+}
+
+/**
+ * Adds the flags that make a test command write structured results: a JSON
+ * report for vitest and jest, JSON events for go test and the short summary of
+ * failures for pytest. Flags the command already has are left alone.
+ */
+export function withStructuredOutput(command: string, framework: TestFramework, reportFile: string): TestCommand {
+	switch (framework) {
+		case "vitest":
+			if (/--reporter\b/.test(command)) {
+				return { framework, command }
+			}
+
+			return {
+				framework,
+				command: `${command} --reporter=default --reporter=json --outputFile.json=${quote(reportFile)}`,
+				reportFile,
+			}
+		case "jest":
+			if (/--json\b/.test(command)) {
+				return { framework, command }
+			}
+
+			return { framework, command: `${command} --json --outputFile=${quote(reportFile)}`, reportFile }
+		case "go":
+			return {
+				framework,
+				command: /\s-json\b/.test(command) ? command : command.replace(/\bgo\s+test\b/, "go test -json"),
+			}
+		case "pytest":
+			return { framework, command: /\s-r\S*[faA]/.test(command) ? command : `${command} -rf` }
+		default:
+			return { framework, command }
+	}
+}
+
+/**
+ * Detects how to run the tests of the project in `cwd`, optionally limited to
+ * the tests in `testPath` (a file or directory relative to `cwd`). Returns
+ * undefined when no test setup is found.
+ */
+export async function detectTestCommand(
+	cwd: string,
+	{ testPath, reportFile }: { testPath?: string; reportFile: string },
+): Promise<TestCommand | undefined> {
+	const target = testPath ? ` ${quote(testPath)}` : ""
+	const packageJson = await readPackageJson(cwd)
+
+	if (packageJson) {
+		const dependencies = { ...packageJson.dependencies, ...packageJson.devDependencies }
+		const testScript = packageJson.scripts?.test ?? ""
+		const packageManager = await getPackageManager(cwd)
+		const exec = { npm: "npx", pnpm: "pnpm exec", yarn: "yarn", bun: "bunx" }[packageManager]
+
+		if (dependencies.vitest || /\bvitest\b/.test(testScript)) {
+			return withStructuredOutput(`${exec} vitest run${target}`, "vitest", reportFile)
+		}
+
+		if (dependencies.jest || /\bjest\b/.test(testScript)) {
+			return withStructuredOutput(`${exec} jest${target}`, "jest", reportFile)
+		}
+	}
+
+	if (await fileExistsAtPath(path.join(cwd, "go.mod"))) {
+		const goPath = testPath?.replace(/^\.\/|\/$/g, "")
+		let packages = "./..."
+
+		// A file runs the tests of its package, a directory those of all packages below it.
+		if (goPath?.endsWith(".go")) {
+			packages = quote(`./${path.posix.dirname(goPath)}`)
+		} else if (goPath && goPath !== ".") {
+			packages = quote(`./${goPath}/...`)
+		}
+
+		return withStructuredOutput(`go test ${packages}`, "go", reportFile)
+	}
+
+	if (await fileExistsAtPath(path.join(cwd, "Cargo.toml"))) {
+		// cargo test filters by test name, not by path
+		return { framework: "cargo", command: "cargo test" }
+	}
+
+	if (await usesPytest(cwd)) {
+		return withStructuredOutput(`pytest --tb=short${target}`, "pytest", reportFile)
+	}
+
+	const testScript = packageJson?.scripts?.test
+
+	if (testScript && !NPM_DEFAULT_TEST_SCRIPT.test(testScript)) {
+		return { framework: "unknown", command: `${await getPackageManager(cwd)} test` }
+	}
+
+	return undefined
+}
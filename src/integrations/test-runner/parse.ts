@@ -0,0 +1,337 @@
+import * as path from "path"
+import stripAnsi from "strip-ansi"
+
+import type { TestFramework } from "./detect"
+
+export interface TestFailure {
+	name: string
+	file?: string
+	line?: number
+	message: string
+}
+
+export interface TestResults {
+	passed: number
+	failed: number
+	skipped: number
+	failures: TestFailure[]
+}
+
+// The JSON report of jest, which vitest's json reporter also writes
+interface JestReport {
+	numPassedTests?: number
+	numPendingTests?: number
+	numTodoTests?: number
+	testResults: Array<{
+		name: string
+		status: string
+		message?: string
+		assertionResults?: Array<{
+			fullName?: string
+			title: string
+			ancestorTitles?: string[]
+			status: string
+			failureMessages?: string[]
+			location?: { line: number; column: number } | null
+		}>
+	}>
+}
+
+interface GoTestEvent {
+	Action: string
+	Package?: string
+	Test?: string
+	Output?: string
+}
+
+const escapeRegExp = (value: string) => value.replace(/[.*+?^${}()|[\]\\]/g, "\\$&")
+
+function toRelativePath(file: string, cwd: string): string {
+	return path.isAbsolute(file) ? path.relative(cwd, file).split(path.sep).join("/") : file
+}
+
+// Finds the line of `file` in a stack trace, e.g. "at src/math.spec.ts:12:5".
+function findLine(text: string, file: string): number | undefined {
+	const match = text.match(new RegExp(`${escapeRegExp(path.basename(file))}:(\\d+)(?::\\d+)?`))
+	return match ? parseInt(match[1]) : undefined
+}
+
+export function parseJestReport(report: JestReport, cwd: string): TestResults {
+	const failures: TestFailure[] = []
+
+	for (const testResult of report.testResults) {
+		const file = toRelativePath(testResult.name, cwd)
+		const failed = (testResult.assertionResults ?? []).filter((assertion) => assertion.status === "failed")
+
+		for (const assertion of failed) {
+			const message = stripAnsi((assertion.failureMessages ?? []).join("\n")).trim()
+
+			failures.push({
+				name: assertion.fullName || [...(assertion.ancestorTitles ?? []), assertion.title].join(" > "),
+				file,
+				line: assertion.location?.line ?? findLine(message, file),
+				message,
+			})
+		}
+
+		// A file that failed without failed tests couldn't run, e.g. because it doesn't compile.
+		if (testResult.status === "failed" && failed.length === 0) {
+			const message = stripAnsi(testResult.message ?? "").trim()
+			failures.push({ name: "(test file failed to run)", file, line: findLine(message, file), message })
+		}
+	}
+
+	return {
+		passed: report.numPassedTests ?? 0,
+		failed: failures.length,
+		skipped: (report.numPendingTests ?? 0) + (report.numTodoTests ?? 0),
+		failures,
+	}
+}
+
+/**
+ * Parses the JSON events of `go test -json`. Output that isn't JSON, such as
+ * build errors printed by older Go versions, is added to the failures of the
+ * packages that failed without a failing test.
+ */
+export function parseGoTestOutput(output: string): TestResults | undefined {
+	const outputs = new Map<string, string[]>()
+	const failedTests: Array<{ pkg: string; test: string }> = []
+	const failedPackages: string[] = []
+	const otherOutput: string[] = []
+	let passed = 0
+	let skipped = 0
+	let hasEvents = false
+
+	for (const line of output.split("\n")) {
+		let event: GoTestEvent | undefined
+
+		try {
+			event = JSON.parse(line)
+		} catch {
+			// Not an event
+		}
+
+		if (!event || typeof event.Action !== "string") {
+			if (line.trim()) {
+				otherOutput.push(line)
+			}
+
+			continue
+		}
+
+		hasEvents = true
+		const pkg = event.Package ?? ""
+		const key = `${pkg}\t${event.Test ?? ""}`
+
+		switch (event.Action) {
+			case "output":
+				outputs.set(key, [...(outputs.get(key) ?? []), event.Output ?? ""])
+				break
+			case "pass":
+				passed += event.Test ? 1 : 0
+				break
+			case "skip":
+				skipped += event.Test ? 1 : 0
+				break
+			case "fail":
+				if (event.Test) {
+					failedTests.push({ pkg, test: event.Test })
+				} else {
+					failedPackages.push(pkg)
+				}
+
+				break
+		}
+	}
+
+	if (!hasEvents) {
+		return undefined
+	}
+
+	const failures: TestFailure[] = []
+
+	for (const { pkg, test } of failedTests) {
+		// A test fails when one of its subtests fails, so only report the subtest.
+		if (failedTests.some((other) => other.pkg === pkg && other.test.startsWith(`${test}/`))) {
+			continue
+		}
+
+		const message = (outputs.get(`${pkg}\t${test}`) ?? [])
+			.join("")
+			.split("\n")
+			.filter((line) => !/^\s*(?:=== (?:RUN|PAUSE|CONT|NAME)|--- (?:FAIL|PASS|SKIP):)/.test(line))
+			.join("\n")
+			.trim()
+		const location = message.match(/^\s*([\w.-]+_test\.go):(\d+):/m)
+
+		failures.push({
+			name: `${test} (${pkg})`,
+			file: location?.[1],
+			line: location ? parseInt(location[2]) : undefined,
+			message,
+		})
+	}
+
+	for (const pkg of failedPackages) {
+		if (failedTests.some((failed) => failed.pkg === pkg)) {
+			continue
+		}
+
+		const packageOutput = (outputs.get(`${pkg}\t`) ?? []).join("")
+		const message = [...otherOutput, packageOutput].join("\n").trim()
+		failures.push({ name: `${pkg} (package failed to build or run)`, message })
+	}
+
+	return { passed, failed: failures.length, skipped, failures }
+}
+
+/**
+ * Parses the output of pytest. Failures come from the short test summary
+ * (`-rf`), with the traceback of each from its section of the output.
+ */
+export function parsePytestOutput(output: string): TestResults | undefined {
+	const lines = stripAnsi(output).split("\n")
+	const summary = [...lines]
+		.reverse()
+		.find((line) => /\b(?:passed|failed|errors?|skipped|no tests ran)\b.* in [\d.]+s\b/.test(line))
+	const sections = new Map<string, string[]>()
+	const failures: TestFailure[] = []
+	let section: string[] | undefined
+
+	for (const line of lines) {
+		const header = line.match(/^_{3,} (.+?) _{3,}$/)
+
+		if (header) {
+			section = []
+			sections.set(header[1], section)
+			continue
+		}
+
+		// Other `=== title ===` lines end the failures section.
+		if (/^={3,}/.test(line)) {
+			section = undefined
+			continue
+		}
+
+		section?.push(line)
+
+		const failed = line.match(/^(?:FAILED|ERROR) ([^\s:]+)(?:::(.+?))?(?: - (.*))?$/)
+
+		if (failed && !section) {
+			const [, file, test, summaryMessage] = failed
+			// Sections are titled like "TestClass.test_name", or "ERROR collecting file.py" for collection errors.
+			const body = sections.get(test ? test.replace(/::/g, ".") : `ERROR collecting ${file}`)?.join("\n").trim()
+			const locations = [...(body ?? "").matchAll(/^([^\s:]+\.py):(\d+):/gm)]
+			const location = locations.filter((match) => match[1] === file).pop() ?? locations.pop()
+
+			failures.push({
+				name: test ?? "(collection error)",
+				file,
+				line: location ? parseInt(location[2]) : undefined,
+				message: body || summaryMessage || "",
+			})
+		}
+	}
+
+	if (!summary && failures.length === 0) {
+		return undefined
+	}
+
+	const count = (pattern: RegExp) => parseInt(summary?.match(pattern)?.[1] ?? "0")
+
+	return {
+		passed: count(/(\d+) passed/),
+		failed: failures.length,
+		skipped: count(/(\d+) skipped/),
+		failures,
+	}
+}
+
+/**
+ * Parses the output of `cargo test`, summing the results of all test binaries.
+ */
+export function parseCargoOutput(output: string): TestResults | undefined {
+	const lines = stripAnsi(output).split("\n")
+	const blocks = new Map<string, string[]>()
+	const failedTests: string[] = []
+	let passed = 0
+	let skipped = 0
+	let hasResults = false
+	let block: string[] | undefined
+
+	for (const line of lines) {
+		const header = line.match(/^---- (\S+) stdout ----$/)
+
+		if (header) {
+			block = []
+			blocks.set(header[1], block)
+			continue
+		}
+
+		if (line === "failures:") {
+			block = undefined
+			continue
+		}
+
+		block?.push(line)
+
+		const failed = line.match(/^test (\S+) \.\.\. FAILED$/)
+
+		if (failed) {
+			failedTests.push(failed[1])
+		}
+
+		const result = line.match(/^test result: \w+\. (\d+) passed; (\d+) failed; (\d+) ignored/)
+
+		if (result) {
+			hasResults = true
+			passed += parseInt(result[1])
+			skipped += parseInt(result[3])
+		}
+	}
+
+	if (!hasResults && failedTests.length === 0) {
+		return undefined
+	}
+
+	const failures = failedTests.map((test) => {
+		const message = (blocks.get(test) ?? []).join("\n").trim()
+		const location = message.match(/panicked at (?:'.*?', )?([^\s:]+):(\d+):\d+/)
+
+		return {
+			name: test,
+			file: location?.[1],
+			line: location ? parseInt(location[2]) : undefined,
+			message,
+		}
+	})
+
+	return { passed, failed: failures.length, skipped, failures }
+}
+
+/**
+ * Parses the results of a test run. Returns undefined when the output of the
+ * framework can't be parsed, e.g. because the tests didn't run at all.
+ */
+export function parseTestResults(
+	framework: TestFramework,
+	output: string,
+	{ cwd, report }: { cwd: string; report?: unknown },
+): TestResults | undefined {
+	switch (framework) {
+		case "vitest":
+		case "jest":
+			return Array.isArray((report as JestReport | undefined)?.testResults)
+				? parseJestReport(report as JestReport, cwd)
+				: undefined
+		case "go":
+			return parseGoTestOutput(output)
+		case "pytest":
+			return parsePytestOutput(output)
+		case "cargo":
+			return parseCargoOutput(output)
+		default:
+			return undefined
+	}
+}
@@ -0,0 +1,150 @@
+import type { CheckpointStorageUsage } from "@roo-code/types"
+
+import type { ClineProvider } from "../../core/webview/ClineProvider"
+import {
+	type CheckpointRetentionResult,
+	applyCheckpointRetention,
+	deleteTaskCheckpoints,
+	getTaskCheckpointsDirs,
+} from "./storage"
+
+// Give the window time to start before the first run.
+const INITIAL_DELAY_MS = 60_000
+const INTERVAL_MS = 6 * 60 * 60 * 1000
+
+/**
+ * Keeps the checkpoint repositories of past tasks within the user's retention
+ * settings: it applies them in the background every few hours, which also
+ * runs `git gc` on the repositories, and backs the storage usage list in the
+ * checkpoint settings. The checkpoints of open tasks are never touched.
+ */
+export class CheckpointRetentionManager {
+	private providerRef: WeakRef<ClineProvider>
+	private timer?: NodeJS.Timeout
+	private pending?: Promise<CheckpointRetentionResult | undefined>
+	private isDisposed = false
+
+	constructor(provider: ClineProvider) {
+		this.providerRef = new WeakRef(provider)
+	}
+
+	initialize(): void {
+		this.scheduleRun(INITIAL_DELAY_MS)
+	}
+
+	private scheduleRun(delay: number) {
+		this.timer = setTimeout(async () => {
+			await this.applyRetention()
+
+			if (!this.isDisposed) {
+				this.scheduleRun(INTERVAL_MS)
+			}
+		}, delay)
+	}
+
+	private get globalStorageDir() {
+		return this.providerRef.deref()?.context.globalStorageUri.fsPath
+	}
+
+	/**
+	 * Applies the retention settings now. Concurrent calls share one run.
+	 */
+	async applyRetention(): Promise<CheckpointRetentionResult | undefined> {
+		this.pending ??= this.runRetention().finally(() => {
+			this.pending = undefined
+		})
+
+		return this.pending
+	}
+
+	private async runRetention(): Promise<CheckpointRetentionResult | undefined> {
+		const provider = this.providerRef.deref()
+		const globalStorageDir = this.globalStorageDir
+
+		if (!provider || !globalStorageDir || this.isDisposed) {
+			return undefined
+		}
+
+		const { checkpointMaxAgeDays, checkpointMaxStorageMb, checkpointMaxPerTask } = await provider.getState()
+		const lastActivity = new Map(provider.taskHistoryStore.getAll().map((item) => [item.id, item.ts]))
+
+		try {
+			const result = await applyCheckpointRetention(
+				globalStorageDir,
+				{
+					maxAgeDays: checkpointMaxAgeDays,
+					maxStorageMb: checkpointMaxStorageMb,
+					maxCheckpointsPerTask: checkpointMaxPerTask,
+				},
+				{ lastActivity, excludeTaskIds: provider.getOpenTaskIds() },
+			)
+
+			if (result.deletedTaskIds.length > 0 || result.prunedCheckpoints > 0) {
+				provider.log(
+					`[CheckpointRetentionManager] deleted the checkpoints of ${result.deletedTaskIds.length} task(s) and pruned ${result.prunedCheckpoints} checkpoint(s), freeing ${result.freedBytes} bytes`,
+				)
+			}
+
+			return result
+		} catch (error) {
+			provider.log(
+				`[CheckpointRetentionManager] failed to apply checkpoint retention: ${error instanceof Error ? error.message : String(error)}`,
+			)
+
+			return undefined
+		}
+	}
+
+	/**
+	 * The disk usage of each task's checkpoints, largest first.
+	 */
+	async getUsage(): Promise<CheckpointStorageUsage> {
+		const provider = this.providerRef.deref()
+		const globalStorageDir = this.globalStorageDir
+
+		if (!provider || !globalStorageDir) {
+			return { tasks: [], totalSize: 0 }
+		}
+
+		const history = new Map(provider.taskHistoryStore.getAll().map((item) => [item.id, item]))
+		const openTaskIds = provider.getOpenTaskIds()
+
+		const tasks = (await getTaskCheckpointsDirs(globalStorageDir))
+			.map(({ taskId, size, modifiedAt }) => ({
+				taskId,
+				task: history.get(taskId)?.task,
+				size,
+				lastActivity: history.get(taskId)?.ts ?? modifiedAt,
+				isOpen: openTaskIds.has(taskId),
+			}))
+			.sort((a, b) => b.size - a.size)
+
+		return { tasks, totalSize: tasks.reduce((total, { size }) => total + size, 0) }
+	}
+
+	/**
+	 * Deletes the checkpoints of the given tasks, or of all tasks that aren't
+	 * open. The tasks themselves stay in the history.
+	 */
+	async deleteCheckpoints(taskIds?: string[]): Promise<void> {
+		const provider = this.providerRef.deref()
+		const globalStorageDir = this.globalStorageDir
+
+		if (!provider || !globalStorageDir) {
+			return
+		}
+
+		const openTaskIds = provider.getOpenTaskIds()
+		const ids = taskIds ?? (await getTaskCheckpointsDirs(globalStorageDir)).map(({ taskId }) => taskId)
+
+		for (const taskId of ids.filter((id) => !openTaskIds.has(id))) {
+			await deleteTaskCheckpoints(globalStorageDir, taskId)
+		}
+	}
+
+	dispose(): void {
+		this.isDisposed = true
+		clearTimeout(this.timer)
+		this.timer = undefined
+	}
+}
@@ -1,15 +1,9 @@
-import * as path from "path"
-
 import { CheckpointServiceOptions } from "./types"
 import { ShadowCheckpointService } from "./ShadowCheckpointService"
+import { getTaskCheckpointsDir } from "./storage"
 
 export class RepoPerTaskCheckpointService extends ShadowCheckpointService {
 	public static create({ taskId, workspaceDir, shadowDir, log = console.log }: CheckpointServiceOptions) {
-		return new RepoPerTaskCheckpointService(
-			taskId,
-			path.join(shadowDir, "tasks", taskId, "checkpoints"),
-			workspaceDir,
-			log,
-		)
+		return new RepoPerTaskCheckpointService(taskId, getTaskCheckpointsDir(shadowDir, taskId), workspaceDir, log)
 	}
 }
@@ -24,7 +24,7 @@ import { getExcludePatterns } from "./excludes"
  * @param baseDir - The directory where git operations should be executed
  * @returns A SimpleGit instance with sanitized environment
  */
-function createSanitizedGit(baseDir: string): SimpleGit {
+export function createSanitizedGit(baseDir: string): SimpleGit {
 	// Create a clean environment by explicitly unsetting git-related environment variables
 	// that could interfere with checkpoint operations
 	const sanitizedEnv: Record<string, string> = {}
@@ -371,6 +371,76 @@ export abstract class ShadowCheckpointService extends EventEmitter {
 		}
 	}
 
+	/**
+	 * Restores only the given workspace-relative files to their state in a
+	 * checkpoint, leaving the rest of the workspace and the checkpoint history
+	 * as they are. Files that didn't exist in the checkpoint are deleted.
+	 */
+	public async restoreFiles(commitHash: string, paths: string[]) {
+		try {
+			this.log(`[${this.constructor.name}#restoreFiles] restoring ${paths.length} file(s) from ${commitHash}`)
+
+			if (!this.git) {
+				throw new Error("Shadow git repo not initialized")
+			}
+
+			const cwdPath = (await this.getShadowGitConfigWorktree(this.git)) || this.workspaceDir
+
+			// Normalize the paths the way git lists them, so they can be compared with its output.
+			const relPaths = paths.map((relPath) => {
+				const relative = path.relative(cwdPath, path.resolve(cwdPath, relPath))
+
+				if (!relative || relative.startsWith("..") || path.isAbsolute(relative)) {
+					throw new Error(`Cannot restore a file outside of the workspace: ${relPath}`)
+				}
+
+				return relative.split(path.sep).join("/")
+			})
+
+			const start = Date.now()
+			const tracked = relPaths.length
+				? (await this.git.raw(["ls-tree", "-r", "-z", "--name-only", commitHash, "--", ...relPaths]))
+						.split("\0")
+						.filter(Boolean)
+				: []
+
+			if (tracked.length > 0) {
+				await this.git.checkout([commitHash, "--", ...tracked])
+			}
+
+			const removed = relPaths.filter((relPath) => !tracked.includes(relPath))
+
+			for (const relPath of removed) {
+				await fs.rm(path.join(cwdPath, relPath), { force: true })
+			}
+
+			const duration = Date.now() - start
+			this.log(
+				`[${this.constructor.name}#restoreFiles] restored ${tracked.length} and removed ${removed.length} file(s) in ${duration}ms`,
+			)
+		} catch (e) {
+			const error = e instanceof Error ? e : new Error(String(e))
+			this.log(`[${this.constructor.name}#restoreFiles] failed to restore files: ${error.message}`)
+			this.emit("error", { type: "error", error })
+			throw error
+		}
+	}
+
+	/**
+	 * Whether a checkpoint is still in the shadow repo; the retention policy
+	 * drops old ones (see `applyCheckpointRetention`).
+	 */
+	public async hasCheckpoint(commitHash: string) {
+		if (!this.git) {
+			throw new Error("Shadow git repo not initialized")
+		}
+
+		return this.git.raw(["cat-file", "-e", `${commitHash}^{commit}`]).then(
+			() => true,
+			() => false,
+		)
+	}
+
 	public async getDiff({ from, to }: { from?: string; to?: string }): Promise<CheckpointDiff[]> {
 		if (!this.git) {
 			throw new Error("Shadow git repo not initialized")
@@ -139,6 +139,45 @@ describe.each([[RepoPerTaskCheckpointService, "RepoPerTaskCheckpointService"]])(
 			})
 		})
 
+		describe(`${klass.name}#restoreFiles`, () => {
+			it("restores only the selected files", async () => {
+				const otherFile = path.join(service.workspaceDir, "other.txt")
+				await fs.writeFile(otherFile, "Other content")
+				const commit = await service.saveCheckpoint("Add other file")
+				expect(commit?.commit).toBeTruthy()
+
+				await fs.writeFile(testFile, "Changed test file")
+				await fs.writeFile(otherFile, "Changed other file")
+				await service.saveCheckpoint("Change both files")
+
+				await service.restoreFiles(commit!.commit, ["test.txt"])
+
+				expect(await fs.readFile(testFile, "utf-8")).toBe("Hello, world!")
+				expect(await fs.readFile(otherFile, "utf-8")).toBe("Changed other file")
+				expect(service.getCheckpoints()).toHaveLength(2)
+			})
+
+			it("deletes selected files that didn't exist in the checkpoint", async () => {
+				const commit = await service.saveCheckpoint("Empty checkpoint", { allowEmpty: true })
+				expect(commit?.commit).toBeTruthy()
+
+				const newFile = path.join(service.workspaceDir, "new.txt")
+				await fs.writeFile(newFile, "New file content")
+				await fs.writeFile(testFile, "Changed test file")
+
+				await service.restoreFiles(commit!.commit, ["new.txt"])
+
+				expect(await fileExistsAtPath(newFile)).toBe(false)
+				expect(await fs.readFile(testFile, "utf-8")).toBe("Changed test file")
+			})
+
+			it("rejects files outside of the workspace", async () => {
+				await expect(service.restoreFiles(service.baseHash!, ["../outside.txt"])).rejects.toThrow(
+					"Cannot restore a file outside of the workspace",
+				)
+			})
+		})
+
 		describe(`${klass.name}#saveCheckpoint`, () => {
 			it("creates a checkpoint if there are pending changes", async () => {
 				await fs.writeFile(testFile, "Ahoy, world!")
@@ -0,0 +1,168 @@
+// npx vitest run src/services/checkpoints/__tests__/storage.spec.ts
+
+import fs from "fs/promises"
+import path from "path"
+import os from "os"
+
+import { simpleGit } from "simple-git"
+
+import {
+	applyCheckpointRetention,
+	getTaskCheckpointsDir,
+	getTaskCheckpointsDirs,
+	pruneTaskCheckpoints,
+} from "../storage"
+
+const tmpDir = path.join(os.tmpdir(), "CheckpointStorage")
+const DAY_MS = 24 * 60 * 60 * 1000
+
+const initTaskCheckpoints = async (globalStorageDir: string, taskId: string, checkpoints = 1) => {
+	const dir = getTaskCheckpointsDir(globalStorageDir, taskId)
+	await fs.mkdir(dir, { recursive: true })
+
+	const git = simpleGit(dir)
+	await git.init()
+	await git.addConfig("user.name", "Roo Code")
+	await git.addConfig("user.email", "support@roocode.com")
+
+	const hashes: string[] = []
+
+	for (let i = 0; i < checkpoints; i++) {
+		await fs.writeFile(path.join(dir, "file.txt"), `checkpoint ${i}`)
+		await git.add(".")
+		await git.commit(`checkpoint ${i}`)
+		hashes.push((await git.revparse(["HEAD"])).trim())
+	}
+
+	return { dir, git, hashes }
+}
+
+describe("checkpoint storage", () => {
+	let globalStorageDir: string
+
+	beforeEach(async () => {
+		globalStorageDir = path.join(tmpDir, `storage-${Date.now()}`)
+		await fs.mkdir(globalStorageDir, { recursive: true })
+	})
+
+	afterAll(async () => {
+		await fs.rm(tmpDir, { recursive: true, force: true })
+	}, 60_000)
+
+	describe("getTaskCheckpointsDirs", () => {
+		it("lists only the tasks with a checkpoints repository", async () => {
+			await initTaskCheckpoints(globalStorageDir, "task-1")
+			await fs.mkdir(path.join(globalStorageDir, "tasks", "task-2"), { recursive: true })
+
+			const dirs = await getTaskCheckpointsDirs(globalStorageDir)
+
+			expect(dirs.map(({ taskId }) => taskId)).toEqual(["task-1"])
+			expect(dirs[0].size).toBeGreaterThan(0)
+		})
+
+		it("returns nothing when there are no tasks", async () => {
+			expect(await getTaskCheckpointsDirs(globalStorageDir)).toEqual([])
+		})
+	})
+
+	describe("pruneTaskCheckpoints", () => {
+		it("keeps the most recent checkpoints with their hashes", async () => {
+			const { dir, git, hashes } = await initTaskCheckpoints(globalStorageDir, "task-1", 5)
+
+			expect(await pruneTaskCheckpoints(dir, 2)).toBe(3)
+
+			const kept = (await git.raw(["rev-list", "HEAD"])).split("\n").filter(Boolean)
+			expect(kept).toEqual([hashes[4], hashes[3]])
+			expect(await git.show([`${hashes[3]}:file.txt`])).toBe("checkpoint 3")
+			await expect(git.raw(["cat-file", "-e", `${hashes[0]}^{commit}`])).rejects.toThrow()
+		})
+
+		it("keeps all checkpoints when there are no more than the limit", async () => {
+			const { dir, git } = await initTaskCheckpoints(globalStorageDir, "task-1", 2)
+
+			expect(await pruneTaskCheckpoints(dir, 2)).toBe(0)
+			expect((await git.raw(["rev-list", "HEAD"])).split("\n").filter(Boolean)).toHaveLength(2)
+		})
+	})
+
+	describe("applyCheckpointRetention", () => {
+		it("deletes the checkpoints of tasks inactive for longer than the maximum age", async () => {
+			await initTaskCheckpoints(globalStorageDir, "old")
+			await initTaskCheckpoints(globalStorageDir, "recent")
+			await initTaskCheckpoints(globalStorageDir, "open")
+
+			const lastActivity = new Map([
+				["old", Date.now() - 10 * DAY_MS],
+				["recent", Date.now() - DAY_MS],
+				["open", Date.now() - 10 * DAY_MS],
+			])
+
+			const result = await applyCheckpointRetention(
+				globalStorageDir,
+				{ maxAgeDays: 7 },
+				{ lastActivity, excludeTaskIds: new Set(["open"]) },
+			)
+
+			expect(result.deletedTaskIds).toEqual(["old"])
+			expect(result.freedBytes).toBeGreaterThan(0)
+
+			const remaining = (await getTaskCheckpointsDirs(globalStorageDir)).map(({ taskId }) => taskId).sort()
+			expect(remaining).toEqual(["open", "recent"])
+		})
+
+		it("deletes the least recently active tasks until under the storage limit", async () => {
+			await initTaskCheckpoints(globalStorageDir, "task-1")
+			await initTaskCheckpoints(globalStorageDir, "task-2")
+			await initTaskCheckpoints(globalStorageDir, "task-3")
+
+			const dirs = await getTaskCheckpointsDirs(globalStorageDir)
+			const largest = Math.max(...dirs.map(({ size }) => size))
+			const totalSize = dirs.reduce((total, { size }) => total + size, 0)
+			// Room for a bit more than one of the repositories.
+			const maxStorageMb = (largest * 1.5) / (1024 * 1024)
+
+			const lastActivity = new Map([
+				["task-1", Date.now() - 3000],
+				["task-2", Date.now() - 2000],
+				["task-3", Date.now() - 1000],
+			])
+
+			const result = await applyCheckpointRetention(
+				globalStorageDir,
+				{ maxStorageMb },
+				{ lastActivity, excludeTaskIds: new Set() },
+			)
+
+			expect(result.deletedTaskIds).toEqual(["task-1", "task-2"])
+			expect(result.freedBytes).toBeLessThanOrEqual(totalSize)
+			expect((await getTaskCheckpointsDirs(globalStorageDir)).map(({ taskId }) => taskId)).toEqual(["task-3"])
+		})
+
+		it("never deletes or prunes the checkpoints of open tasks", async () => {
+			const { git } = await initTaskCheckpoints(globalStorageDir, "open", 3)
+
+			const result = await applyCheckpointRetention(
+				globalStorageDir,
+				{ maxStorageMb: 0.000001, maxCheckpointsPerTask: 1 },
+				{ lastActivity: new Map(), excludeTaskIds: new Set(["open"]) },
+			)
+
+			expect(result).toEqual({ deletedTaskIds: [], prunedCheckpoints: 0, freedBytes: 0 })
+			expect((await git.raw(["rev-list", "HEAD"])).split("\n").filter(Boolean)).toHaveLength(3)
+		})
+
+		it("prunes each task to the maximum number of checkpoints", async () => {
+			await initTaskCheckpoints(globalStorageDir, "task-1", 3)
+			await initTaskCheckpoints(globalStorageDir, "task-2", 1)
+
+			const result = await applyCheckpointRetention(
+				globalStorageDir,
+				{ maxCheckpointsPerTask: 1 },
+				{ lastActivity: new Map(), excludeTaskIds: new Set() },
+			)
+
+			expect(result.deletedTaskIds).toEqual([])
+			expect(result.prunedCheckpoints).toBe(2)
+		})
+	})
+})
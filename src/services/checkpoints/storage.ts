@@ -0,0 +1,162 @@
+import fs from "fs/promises"
+import * as path from "path"
+
+import getFolderSize from "get-folder-size"
+
+import { createSanitizedGit } from "./ShadowCheckpointService"
+
+export interface CheckpointRetentionPolicy {
+	// Delete the checkpoints of tasks that haven't been active for this many days.
+	maxAgeDays?: number
+	// Delete the checkpoints of the least recently active tasks while all of them take more than this.
+	maxStorageMb?: number
+	// Keep only this many of the most recent checkpoints of each task.
+	maxCheckpointsPerTask?: number
+}
+
+export interface TaskCheckpointsDir {
+	taskId: string
+	dir: string
+	size: number
+	modifiedAt: number
+}
+
+export interface CheckpointRetentionResult {
+	deletedTaskIds: string[]
+	prunedCheckpoints: number
+	freedBytes: number
+}
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+export function getTaskCheckpointsDir(globalStorageDir: string, taskId: string) {
+	return path.join(globalStorageDir, "tasks", taskId, "checkpoints")
+}
+
+/**
+ * Lists the tasks that have a checkpoints repository, with its size on disk
+ * and when it last changed.
+ */
+export async function getTaskCheckpointsDirs(globalStorageDir: string): Promise<TaskCheckpointsDir[]> {
+	const tasksDir = path.join(globalStorageDir, "tasks")
+	const entries = await fs.readdir(tasksDir, { withFileTypes: true }).catch(() => [])
+	const result: TaskCheckpointsDir[] = []
+
+	for (const entry of entries) {
+		if (!entry.isDirectory()) {
+			continue
+		}
+
+		const dir = getTaskCheckpointsDir(globalStorageDir, entry.name)
+		const stats = await fs.stat(path.join(dir, ".git")).catch(() => undefined)
+
+		if (stats) {
+			const size = await getFolderSize.loose(dir).catch(() => 0)
+			result.push({ taskId: entry.name, dir, size, modifiedAt: stats.mtimeMs })
+		}
+	}
+
+	return result
+}
+
+export async function deleteTaskCheckpoints(globalStorageDir: string, taskId: string) {
+	await fs.rm(getTaskCheckpointsDir(globalStorageDir, taskId), { recursive: true, force: true })
+}
+
+/**
+ * Drops all but the `maxCheckpoints` most recent checkpoints of a task and
+ * collects their objects. Like a shallow clone, the oldest checkpoint that is
+ * kept becomes the root of the history, so the kept checkpoints keep their
+ * hashes and the task's messages still point at them. Returns the number of
+ * checkpoints dropped.
+ */
+export async function pruneTaskCheckpoints(dir: string, maxCheckpoints: number): Promise<number> {
+	const git = createSanitizedGit(dir)
+	const hashes = (await git.raw(["rev-list", "HEAD"])).split("\n").filter(Boolean)
+
+	if (hashes.length <= maxCheckpoints) {
+		await git.raw(["gc", "--auto", "--quiet"])
+		return 0
+	}
+
+	await fs.writeFile(path.join(dir, ".git", "shallow"), `${hashes[maxCheckpoints - 1]}\n`)
+	await git.raw(["reflog", "expire", "--expire=now", "--all"])
+	await git.raw(["gc", "--quiet", "--prune=now"])
+
+	return hashes.length - maxCheckpoints
+}
+
+/**
+ * Applies a retention policy to the checkpoints of all tasks except
+ * `excludeTaskIds` (the open ones). A task's last activity is taken from
+ * `lastActivity`, or from its repository if the task isn't there. Repositories
+ * that aren't pruned still get an automatic `git gc`.
+ */
+export async function applyCheckpointRetention(
+	globalStorageDir: string,
+	policy: CheckpointRetentionPolicy,
+	{ lastActivity, excludeTaskIds }: { lastActivity: Map<string, number>; excludeTaskIds: Set<string> },
+): Promise<CheckpointRetentionResult> {
+	const result: CheckpointRetentionResult = { deletedTaskIds: [], prunedCheckpoints: 0, freedBytes: 0 }
+	const { maxAgeDays, maxStorageMb, maxCheckpointsPerTask } = policy
+
+	const dirs = await getTaskCheckpointsDirs(globalStorageDir)
+	const getLastActivity = ({ taskId, modifiedAt }: TaskCheckpointsDir) => lastActivity.get(taskId) ?? modifiedAt
+
+	// Least recently active first.
+	let candidates = dirs
+		.filter(({ taskId }) => !excludeTaskIds.has(taskId))
+		.sort((a, b) => getLastActivity(a) - getLastActivity(b))
+
+	const deleteTask = async (entry: TaskCheckpointsDir) => {
+		await deleteTaskCheckpoints(globalStorageDir, entry.taskId)
+		result.deletedTaskIds.push(entry.taskId)
+		result.freedBytes += entry.size
+		candidates = candidates.filter(({ taskId }) => taskId !== entry.taskId)
+	}
+
+	if (maxAgeDays) {
+		const cutoff = Date.now() - maxAgeDays * DAY_MS
+
+		for (const entry of candidates.filter((entry) => getLastActivity(entry) < cutoff)) {
+			await deleteTask(entry)
+		}
+	}
+
+	for (const entry of candidates) {
+		try {
+			if (maxCheckpointsPerTask) {
+				result.prunedCheckpoints += await pruneTaskCheckpoints(entry.dir, maxCheckpointsPerTask)
+			} else {
+				await createSanitizedGit(entry.dir).raw(["gc", "--auto", "--quiet"])
+			}
+
+			const size = await getFolderSize.loose(entry.dir).catch(() => entry.size)
+			result.freedBytes += Math.max(0, entry.size - size)
+			entry.size = size
+		} catch (error) {
+			console.error(
+				`[applyCheckpointRetention] failed to prune checkpoints of ${entry.taskId}: ${error instanceof Error ? error.message : String(error)}`,
+			)
+		}
+	}
+
+	if (maxStorageMb) {
+		const maxBytes = maxStorageMb * 1024 * 1024
+		const excludedSize = dirs
+			.filter(({ taskId }) => excludeTaskIds.has(taskId))
+			.reduce((total, { size }) => total + size, 0)
+		let totalSize = excludedSize + candidates.reduce((total, { size }) => total + size, 0)
+
+		for (const entry of [...candidates]) {
+			if (totalSize <= maxBytes) {
+				break
+			}
+
+			totalSize -= entry.size
+			await deleteTask(entry)
+		}
+	}
+
+	return result
+}
@@ -193,4 +193,60 @@ describe("CacheManager", () => {
 			consoleErrorSpy.mockRestore()
 		})
 	})
+
+	describe("git state", () => {
+		beforeEach(() => {
+			;(safeWriteJson as Mock).mockResolvedValue(undefined)
+		})
+
+		it("persists the git state and merges pending paths", async () => {
+			await cacheManager.setGitState({ commit: "abc123", pendingPaths: ["a.ts"], rooIgnoreHash: "hash" })
+			await cacheManager.addGitPendingPaths(["a.ts", "b.ts"])
+
+			expect(cacheManager.getGitState()).toEqual({
+				commit: "abc123",
+				pendingPaths: ["a.ts", "b.ts"],
+				rooIgnoreHash: "hash",
+			})
+			expect(safeWriteJson).toHaveBeenLastCalledWith(mockCachePath.fsPath, {
+				commit: "abc123",
+				pendingPaths: ["a.ts", "b.ts"],
+				rooIgnoreHash: "hash",
+			})
+		})
+
+		it("drops a stored git state without a hash of the ignore patterns", async () => {
+			;(vscode.workspace.fs.readFile as Mock).mockImplementation(async () =>
+				Buffer.from(JSON.stringify({ commit: "abc123", pendingPaths: [] })),
+			)
+
+			await cacheManager.initialize()
+
+			expect(cacheManager.getGitState()).toBeUndefined()
+		})
+
+		it("restores a stored git state", async () => {
+			const state = { commit: "abc123", pendingPaths: ["a.ts"], rooIgnoreHash: "hash" }
+			;(vscode.workspace.fs.readFile as Mock).mockImplementation(async () => Buffer.from(JSON.stringify(state)))
+
+			await cacheManager.initialize()
+
+			expect(cacheManager.getGitState()).toEqual(state)
+		})
+
+		it("ignores pending paths until a commit has been recorded", async () => {
+			await cacheManager.addGitPendingPaths(["a.ts"])
+
+			expect(cacheManager.getGitState()).toBeUndefined()
+			expect(safeWriteJson).not.toHaveBeenCalled()
+		})
+
+		it("forgets the git state when the cache is cleared", async () => {
+			await cacheManager.setGitState({ commit: "abc123", pendingPaths: [], rooIgnoreHash: "hash" })
+
+			await cacheManager.clearCacheFile()
+
+			expect(cacheManager.getGitState()).toBeUndefined()
+		})
+	})
 })
@@ -1299,6 +1299,7 @@ describe("CodeIndexConfigManager", () => {
 				qdrantApiKey: "test-qdrant-key",
 				searchMinScore: 0.4,
 				searchMaxResults: 50,
+				hybridSearchWeight: 0.3,
 			})
 		})
 
@@ -1540,6 +1541,34 @@ describe("CodeIndexConfigManager", () => {
 			const result = configManager.doesConfigChangeRequireRestart(previousSnapshot)
 			expect(result).toBe(false)
 		})
+
+		it("should return true when indexed documentation types change", async () => {
+			mockContextProxy.getGlobalState.mockReturnValue({
+				codebaseIndexEnabled: true,
+				codebaseIndexEmbedderProvider: "openai",
+				codebaseIndexQdrantUrl: "http://localhost:6333",
+				codebaseIndexDocumentTypes: ["config"],
+			})
+			mockContextProxy.getSecret.mockImplementation((key: string) => {
+				if (key === "codeIndexOpenAiKey") return "test-key"
+				return undefined
+			})
+			configManager = new CodeIndexConfigManager(mockContextProxy)
+
+			const previousSnapshot: PreviousConfigSnapshot = {
+				enabled: true,
+				configured: true,
+				embedderProvider: "openai",
+				openAiKey: "test-key",
+				qdrantUrl: "http://localhost:6333",
+			}
+
+			expect(configManager.hasDocumentTypesChanged(previousSnapshot)).toBe(true)
+			expect(configManager.doesConfigChangeRequireRestart(previousSnapshot)).toBe(true)
+			expect(
+				configManager.doesConfigChangeRequireRestart({ ...previousSnapshot, documentTypes: ["config"] }),
+			).toBe(false)
+		})
 	})
 
 	describe("loadConfiguration", () => {
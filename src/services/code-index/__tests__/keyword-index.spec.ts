@@ -0,0 +1,193 @@
+import type { Mock } from "vitest"
+import * as vscode from "vscode"
+
+import { KeywordIndex, tokenize } from "../keyword-index"
+import { fuseSearchResults } from "../hybrid-search"
+
+vitest.mock("../../../utils/safeWriteJson", () => ({
+	safeWriteJson: vitest.fn().mockResolvedValue(undefined),
+}))
+
+import { safeWriteJson } from "../../../utils/safeWriteJson"
+
+vitest.mock("vscode", () => ({
+	Uri: {
+		joinPath: vitest.fn(),
+	},
+	workspace: {
+		fs: {
+			readFile: vitest.fn(),
+		},
+	},
+}))
+
+// Mock debounce to execute immediately
+vitest.mock("lodash.debounce", () => ({ default: vitest.fn((fn) => fn) }))
+
+const point = (id: string, filePath: string, codeChunk: string) => ({
+	id,
+	vector: [],
+	payload: { filePath, codeChunk, startLine: 1, endLine: 1 },
+})
+
+describe("tokenize", () => {
+	it("keeps whole identifiers and splits camelCase and snake_case parts", () => {
+		expect(tokenize("getUserById(max_retry_count)")).toEqual([
+			"getuserbyid",
+			"get",
+			"user",
+			"id",
+			"max_retry_count",
+			"max",
+			"retry",
+			"count",
+		])
+	})
+
+	it("drops stop words and single characters", () => {
+		expect(tokenize("how is the x parsed")).toEqual(["parsed"])
+	})
+})
+
+describe("KeywordIndex", () => {
+	let index: KeywordIndex
+
+	beforeEach(async () => {
+		vitest.clearAllMocks()
+		;(vscode.Uri.joinPath as Mock).mockReturnValue({ fsPath: "/mock/storage/keywords.json" })
+		;(vscode.workspace.fs.readFile as Mock).mockRejectedValue(new Error("ENOENT"))
+
+		index = new KeywordIndex(
+			{ globalStorageUri: { fsPath: "/mock/storage" } } as vscode.ExtensionContext,
+			"/mock/workspace",
+		)
+		await index.initialize()
+	})
+
+	it("ranks exact identifier matches first", () => {
+		index.upsert([
+			point("a", "src/auth.ts", "function validateSessionToken(token) { return check(token) }"),
+			point("b", "src/user.ts", "function loadUser(id) { return db.find(id) }"),
+			point("c", "src/session.ts", "const session = createSession()"),
+		])
+
+		const results = index.search("validateSessionToken", undefined, 10)
+
+		expect(results[0].id).toBe("a")
+		expect(results.map((r) => r.id)).not.toContain("b")
+	})
+
+	it("filters by directory prefix on whole segments", () => {
+		index.upsert([
+			point("a", "src/api/errors.ts", 'throw new Error("ECONNRESET")'),
+			point("b", "src/apiv2/errors.ts", 'throw new Error("ECONNRESET")'),
+		])
+
+		const results = index.search("ECONNRESET", "src/api", 10)
+
+		expect(results.map((r) => r.id)).toEqual(["a"])
+	})
+
+	it("filters by language and kind", () => {
+		const withFields = (id: string, filePath: string, language: string, kind: string) => {
+			const base = point(id, filePath, "func handleRequest(w http.ResponseWriter)")
+			return { ...base, payload: { ...base.payload, language, kind } }
+		}
+		index.upsert([
+			withFields("a", "api/handler.go", "go", "function"),
+			withFields("b", "api/server.go", "go", "method"),
+			withFields("c", "api/handler.ts", "typescript", "function"),
+			point("d", "api/legacy.go", "func handleRequest(w http.ResponseWriter)"),
+		])
+
+		const results = index.search("handleRequest", undefined, 10, { language: "go", kind: "function" })
+
+		expect(results.map((r) => r.id)).toEqual(["a"])
+	})
+
+	it("removes documents when their file is deleted", () => {
+		index.upsert([point("a", "src/a.ts", "uniqueSymbol"), point("b", "src/b.ts", "uniqueSymbol")])
+
+		index.deleteByFilePaths(["/mock/workspace/src/a.ts"])
+
+		expect(index.size).toBe(1)
+		expect(index.search("uniqueSymbol", undefined, 10).map((r) => r.id)).toEqual(["b"])
+	})
+
+	it("replaces a document that is upserted again", () => {
+		index.upsert([point("a", "src/a.ts", "obsolete")])
+		index.upsert([point("a", "src/a.ts", "replacement")])
+
+		expect(index.search("obsolete", undefined, 10)).toEqual([])
+		expect(index.search("replacement", undefined, 10).map((r) => r.id)).toEqual(["a"])
+	})
+
+	it("persists payloads and restores them on initialize", async () => {
+		index.upsert([point("a", "src/a.ts", "persistedSymbol")])
+
+		const saved = (safeWriteJson as Mock).mock.calls.at(-1)![1]
+		;(vscode.workspace.fs.readFile as Mock).mockResolvedValue(Buffer.from(JSON.stringify(saved)))
+
+		expect(await index.initialize()).toBe("loaded")
+
+		expect(index.search("persistedSymbol", undefined, 10).map((r) => r.id)).toEqual(["a"])
+	})
+
+	it("reports an index that can't be loaded as missing", async () => {
+		expect(await index.initialize()).toBe("missing")
+
+		// Indexes from before they were versioned only stored the payloads
+		const unversioned = { a: point("a", "src/a.ts", "oldSymbol").payload }
+		;(vscode.workspace.fs.readFile as Mock).mockResolvedValue(Buffer.from(JSON.stringify(unversioned)))
+		expect(await index.initialize()).toBe("missing")
+
+		;(vscode.workspace.fs.readFile as Mock).mockResolvedValue(Buffer.from("{"))
+		expect(await index.initialize()).toBe("missing")
+		expect(index.size).toBe(0)
+	})
+
+	it("reports an index of another version as outdated", async () => {
+		const stored = { version: 0, documents: { a: point("a", "src/a.ts", "oldSymbol").payload } }
+		;(vscode.workspace.fs.readFile as Mock).mockResolvedValue(Buffer.from(JSON.stringify(stored)))
+
+		expect(await index.initialize()).toBe("outdated")
+		expect(index.size).toBe(0)
+	})
+
+	it("clears all documents", async () => {
+		index.upsert([point("a", "src/a.ts", "someSymbol")])
+
+		await index.clear()
+
+		expect(index.size).toBe(0)
+		expect(safeWriteJson).toHaveBeenLastCalledWith("/mock/storage/keywords.json", { version: 1, documents: {} })
+	})
+})
+
+describe("fuseSearchResults", () => {
+	const result = (id: string, score = 0.5) => ({ id, score, payload: null })
+
+	it("boosts results found by both searches", () => {
+		const fused = fuseSearchResults([result("a"), result("b")], [result("b"), result("c")], 0.5, 10)
+
+		expect(fused.map((r) => r.id)).toEqual(["b", "a", "c"])
+	})
+
+	it("scores a result ranked first by both searches as 1", () => {
+		const fused = fuseSearchResults([result("a")], [result("a")], 0.3, 10)
+
+		expect(fused[0].score).toBeCloseTo(1)
+	})
+
+	it("ignores the keyword ranking when its weight is 0", () => {
+		const fused = fuseSearchResults([result("a")], [result("b")], 0, 10)
+
+		expect(fused.map((r) => r.id)).toEqual(["a"])
+	})
+
+	it("limits the number of results", () => {
+		const fused = fuseSearchResults([result("a"), result("b")], [result("c"), result("d")], 0.5, 3)
+
+		expect(fused).toHaveLength(3)
+	})
+})
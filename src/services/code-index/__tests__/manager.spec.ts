@@ -802,4 +802,242 @@ describe("CodeIndexManager - handleSettingsChange regression", () => {
 			expect(mockStateManager.setSystemState).toHaveBeenCalledWith("Standby", "Code indexing is disabled")
 		})
 	})
+
+	describe("searchAllWorkspaces", () => {
+		const folderAPath = path.join(path.sep, "test", "folderA")
+		const folderBPath = path.join(path.sep, "test", "folderB")
+		let originalFolders: any
+		let searchA: ReturnType<typeof vi.fn>
+		let searchB: ReturnType<typeof vi.fn>
+
+		const result = (id: string, score: number) => ({ id, score, payload: { filePath: `${id}.ts` } })
+
+		const setUpManager = (workspacePath: string, searchIndex: ReturnType<typeof vi.fn>) => {
+			const folderManager = CodeIndexManager.getInstance(mockContext, workspacePath)!
+			;(folderManager as any)._configManager = { isFeatureEnabled: true, currentSearchMaxResults: 3 }
+			;(folderManager as any)._orchestrator = { stopIndexing: vi.fn(), stopWatcher: vi.fn() }
+			;(folderManager as any)._cacheManager = {}
+			;(folderManager as any)._searchService = { searchIndex }
+			return folderManager
+		}
+
+		beforeEach(async () => {
+			CodeIndexManager.disposeAll()
+
+			const vscode = await import("vscode")
+			originalFolders = vscode.workspace.workspaceFolders
+			;(vscode.workspace as any).workspaceFolders = [
+				{ uri: mockUri(folderAPath), name: "folderA", index: 0 },
+				{ uri: mockUri(folderBPath), name: "folderB", index: 1 },
+			]
+
+			searchA = vi.fn().mockResolvedValue([result("a1", 0.9), result("a2", 0.5)])
+			searchB = vi.fn().mockResolvedValue([result("b1", 0.8), result("b2", 0.7)])
+			setUpManager(folderAPath, searchA)
+			setUpManager(folderBPath, searchB)
+		})
+
+		afterEach(async () => {
+			const vscode = await import("vscode")
+			;(vscode.workspace as any).workspaceFolders = originalFolders
+		})
+
+		it("merges results of all folders by score and tags them with their folder", async () => {
+			const results = await CodeIndexManager.searchAllWorkspaces("query")
+
+			expect(results.map((r) => [r.id, r.workspacePath])).toEqual([
+				["a1", folderAPath],
+				["b1", folderBPath],
+				["b2", folderBPath],
+			])
+			expect(searchA).toHaveBeenCalledWith("query", undefined, undefined)
+			expect(searchB).toHaveBeenCalledWith("query", undefined, undefined)
+		})
+
+		it("limits the search to the folder named at the start of the directory prefix", async () => {
+			await CodeIndexManager.searchAllWorkspaces("query", "folderB/src")
+
+			expect(searchA).not.toHaveBeenCalled()
+			expect(searchB).toHaveBeenCalledWith("query", "src", undefined)
+		})
+
+		it("limits the search to the folder containing an absolute directory prefix", async () => {
+			await CodeIndexManager.searchAllWorkspaces("query", path.join(folderAPath, "lib"))
+
+			expect(searchA).toHaveBeenCalledWith("query", "lib", undefined)
+			expect(searchB).not.toHaveBeenCalled()
+		})
+
+		it("applies other directory prefixes to every folder", async () => {
+			await CodeIndexManager.searchAllWorkspaces("query", "src")
+
+			expect(searchA).toHaveBeenCalledWith("query", "src", undefined)
+			expect(searchB).toHaveBeenCalledWith("query", "src", undefined)
+		})
+
+		it("passes glob filters and payload filters to each folder", async () => {
+			const filters = { language: "go", kind: "function" }
+			await CodeIndexManager.searchAllWorkspaces("query", "folderA/src/api/**", filters)
+
+			expect(searchA).toHaveBeenCalledWith("query", "src/api/**", filters)
+			expect(searchB).not.toHaveBeenCalled()
+		})
+
+		it("skips folders that fail as long as one folder can be searched", async () => {
+			searchB.mockRejectedValue(new Error("Qdrant unavailable"))
+
+			const results = await CodeIndexManager.searchAllWorkspaces("query")
+
+			expect(results.map((r) => r.id)).toEqual(["a1", "a2"])
+		})
+
+		it("throws when no folder can be searched", async () => {
+			searchA.mockRejectedValue(new Error("Qdrant unavailable"))
+			searchB.mockRejectedValue(new Error("Qdrant unavailable"))
+
+			await expect(CodeIndexManager.searchAllWorkspaces("query")).rejects.toThrow("Qdrant unavailable")
+		})
+
+		it("skips folders where indexing is disabled", async () => {
+			await CodeIndexManager.getInstance(mockContext, folderBPath)!.setWorkspaceEnabled(false)
+
+			await CodeIndexManager.searchAllWorkspaces("query")
+
+			expect(searchA).toHaveBeenCalled()
+			expect(searchB).not.toHaveBeenCalled()
+		})
+	})
+
+	describe("index control", () => {
+		let orchestrator: any
+
+		const setUpIndexedManager = (target: CodeIndexManager) => {
+			const folderOrchestrator = {
+				state: "Indexed",
+				reindexPaths: vi.fn(),
+				startIndexing: vi.fn(),
+				clearIndexData: vi.fn(),
+				stopIndexing: vi.fn(),
+				stopWatcher: vi.fn(),
+			}
+			;(target as any)._configManager = { isFeatureEnabled: true, isFeatureConfigured: true }
+			;(target as any)._orchestrator = folderOrchestrator
+			;(target as any)._cacheManager = {
+				clearCacheFile: vi.fn(),
+				getAllHashes: vi.fn().mockReturnValue({ "/test/workspace/a.ts": "h1", "/test/workspace/b.ts": "h2" }),
+			}
+			;(target as any)._searchService = {}
+			vi.spyOn(target, "initialize").mockResolvedValue({ requiresRestart: false })
+			return folderOrchestrator
+		}
+
+		beforeEach(() => {
+			orchestrator = setUpIndexedManager(manager)
+		})
+
+		it("re-indexes only the given paths of an indexed folder", async () => {
+			await manager.reindex({} as any, { paths: ["src/api", path.join(testWorkspacePath, "README.md")] })
+
+			expect(orchestrator.reindexPaths).toHaveBeenCalledWith([
+				path.join(testWorkspacePath, "src", "api"),
+				path.join(testWorkspacePath, "README.md"),
+			])
+			expect(orchestrator.startIndexing).not.toHaveBeenCalled()
+		})
+
+		it("clears the index before a full re-index", async () => {
+			await manager.reindex({} as any, { full: true, paths: ["src"] })
+
+			expect(orchestrator.clearIndexData).toHaveBeenCalled()
+			expect(orchestrator.startIndexing).toHaveBeenCalled()
+			expect(orchestrator.reindexPaths).not.toHaveBeenCalled()
+		})
+
+		it("scans the whole folder when it has not been indexed yet", async () => {
+			orchestrator.state = "Standby"
+
+			await manager.reindex({} as any, { paths: ["src"] })
+
+			expect(orchestrator.startIndexing).toHaveBeenCalled()
+			expect(orchestrator.reindexPaths).not.toHaveBeenCalled()
+		})
+
+		it("rejects when the run ends in an error", async () => {
+			orchestrator.startIndexing.mockImplementation(async () => {
+				orchestrator.state = "Error"
+			})
+			;(manager as any)._stateManager.getCurrentStatus.mockReturnValue({ message: "Qdrant unavailable" })
+
+			await expect(manager.reindex({} as any)).rejects.toThrow("Qdrant unavailable")
+		})
+
+		it("rejects when indexing is not configured", async () => {
+			;(manager as any)._configManager.isFeatureConfigured = false
+
+			await expect(manager.reindex({} as any)).rejects.toThrow("disabled or not configured")
+		})
+
+		it("re-indexes a named folder and enables indexing for it", async () => {
+			await manager.setWorkspaceEnabled(false)
+
+			await CodeIndexManager.reindexWorkspaces({} as any, { workspacePath: testWorkspacePath })
+
+			expect(manager.isWorkspaceEnabled).toBe(true)
+			expect(orchestrator.startIndexing).toHaveBeenCalled()
+		})
+
+		it("rejects when no folder has indexing enabled", async () => {
+			await manager.setWorkspaceEnabled(false)
+
+			await expect(CodeIndexManager.reindexWorkspaces({} as any)).rejects.toThrow("not enabled")
+			expect(orchestrator.startIndexing).not.toHaveBeenCalled()
+		})
+
+		it("reports progress, files, chunks and the last error", () => {
+			;(manager as any)._stateManager.getCurrentStatus.mockReturnValue({
+				systemStatus: "Indexed",
+				message: "File watcher started.",
+				processedItems: 10,
+				totalItems: 10,
+				currentItemUnit: "blocks",
+			})
+			;(manager as any)._stateManager.lastError = { message: "Rate limited", timestamp: 1 }
+			;(manager as any)._keywordIndex = { size: 7 }
+
+			expect(CodeIndexManager.getAllIndexStats()).toEqual([
+				{
+					workspacePath: testWorkspacePath,
+					systemStatus: "Indexed",
+					message: "File watcher started.",
+					processedItems: 10,
+					totalItems: 10,
+					currentItemUnit: "blocks",
+					filesIndexed: 2,
+					chunksIndexed: 7,
+					lastError: { message: "Rate limited", timestamp: 1 },
+				},
+			])
+		})
+
+		it("clears initialized folders and rejects unknown ones", async () => {
+			await CodeIndexManager.clearWorkspaces()
+
+			expect(orchestrator.clearIndexData).toHaveBeenCalled()
+			await expect(CodeIndexManager.clearWorkspaces(path.join(path.sep, "elsewhere"))).rejects.toThrow(
+				"No code index exists",
+			)
+		})
+	})
+
+	describe("disposeInstance", () => {
+		it("disposes the manager and creates a fresh one on the next lookup", () => {
+			const workspacePath = (manager as any).workspacePath
+			const disposeSpy = vi.spyOn(manager, "dispose")
+
+			CodeIndexManager.disposeInstance(workspacePath)
+
+			expect(disposeSpy).toHaveBeenCalled()
+			expect(CodeIndexManager.getInstance(mockContext, workspacePath)).not.toBe(manager)
+		})
+	})
 })
@@ -1,4 +1,5 @@
 import { describe, it, expect, beforeEach, vi } from "vitest"
+import { createHash } from "crypto"
 import { CodeIndexOrchestrator } from "../orchestrator"
 
 // Mock vscode workspace so startIndexing passes workspace check
@@ -37,6 +38,45 @@ vi.mock("@roo-code/telemetry", () => ({
 	},
 }))
 
+// Mock git so tests never shell out; individual tests opt into git-driven scans
+vi.mock("../processors/git-changes", () => ({
+	getHeadCommit: vi.fn().mockResolvedValue(undefined),
+	getUncommittedFiles: vi.fn().mockResolvedValue(undefined),
+	getChangedFilesSince: vi.fn().mockResolvedValue(undefined),
+}))
+
+// The effective .rooignore patterns, which tests change to simulate edits to .rooignore or .roo/config
+const rooIgnore = vi.hoisted(() => ({ content: undefined as string | undefined }))
+
+vi.mock("../../../core/ignore/RooIgnoreController", () => ({
+	RooIgnoreController: vi.fn().mockImplementation(() => ({
+		initialize: vi.fn().mockResolvedValue(undefined),
+		dispose: vi.fn(),
+		get rooIgnoreContent() {
+			return rooIgnore.content
+		},
+	})),
+}))
+
+const emptyRooIgnoreHash = createHash("sha256").update("").digest("hex")
+
+vi.mock("../../../utils/fs", () => ({
+	fileExistsAtPath: vi.fn().mockImplementation(async (filePath: string) => !filePath.includes("removed")),
+}))
+
+vi.mock("fs/promises", async (importOriginal) => ({
+	...(await importOriginal<typeof import("fs/promises")>()),
+	stat: vi.fn(),
+}))
+
+vi.mock("../../glob/list-files", () => ({
+	listFiles: vi.fn(),
+}))
+
+import { stat } from "fs/promises"
+import { listFiles } from "../../glob/list-files"
+import { getChangedFilesSince, getHeadCommit, getUncommittedFiles } from "../processors/git-changes"
+
 // Mock i18n translator used in orchestrator messages
 vi.mock("../../i18n", () => ({
 	t: (key: string, params?: any) => {
@@ -334,3 +374,261 @@ describe("CodeIndexOrchestrator - stopIndexing", () => {
 		expect(vectorStore.clearCollection).not.toHaveBeenCalled()
 	})
 })
+
+describe("CodeIndexOrchestrator - git-driven incremental scan", () => {
+	const workspacePath = "/test/workspace"
+
+	let stateManager: any
+	let cacheManager: any
+	let vectorStore: any
+	let scanner: any
+	let fileWatcher: any
+
+	const createOrchestrator = () =>
+		new CodeIndexOrchestrator(
+			{ isFeatureConfigured: true } as any,
+			stateManager,
+			workspacePath,
+			cacheManager,
+			vectorStore,
+			scanner,
+			fileWatcher,
+		)
+
+	beforeEach(() => {
+		vi.clearAllMocks()
+
+		let currentState = "Standby"
+		stateManager = {
+			get state() {
+				return currentState
+			},
+			setSystemState: vi.fn().mockImplementation((state: string, _msg: string) => {
+				currentState = state
+			}),
+			reportFileQueueProgress: vi.fn(),
+			reportBlockIndexingProgress: vi.fn(),
+		}
+
+		cacheManager = {
+			clearCacheFile: vi.fn().mockResolvedValue(undefined),
+			flush: vi.fn().mockResolvedValue(undefined),
+			getGitState: vi.fn().mockReturnValue({
+				commit: "abc123",
+				pendingPaths: ["/test/workspace/src/edited.ts"],
+				rooIgnoreHash: emptyRooIgnoreHash,
+			}),
+			setGitState: vi.fn().mockResolvedValue(undefined),
+			addGitPendingPaths: vi.fn().mockResolvedValue(undefined),
+		}
+
+		vectorStore = {
+			initialize: vi.fn().mockResolvedValue(false),
+			hasIndexedData: vi.fn().mockResolvedValue(true),
+			markIndexingIncomplete: vi.fn().mockResolvedValue(undefined),
+			markIndexingComplete: vi.fn().mockResolvedValue(undefined),
+			clearCollection: vi.fn().mockResolvedValue(undefined),
+		}
+
+		const emptyResult = { stats: { processed: 0, skipped: 0 }, totalBlockCount: 0 }
+		scanner = {
+			scanDirectory: vi.fn().mockResolvedValue(emptyResult),
+			scanChangedFiles: vi.fn().mockResolvedValue(emptyResult),
+		}
+
+		fileWatcher = {
+			initialize: vi.fn().mockResolvedValue(undefined),
+			onDidStartBatchProcessing: vi.fn().mockReturnValue({ dispose: vi.fn() }),
+			onBatchProgressUpdate: vi.fn().mockReturnValue({ dispose: vi.fn() }),
+			onDidFinishBatchProcessing: vi.fn().mockReturnValue({ dispose: vi.fn() }),
+			dispose: vi.fn(),
+		}
+
+		vi.mocked(getHeadCommit).mockResolvedValue("def456")
+		vi.mocked(getUncommittedFiles).mockResolvedValue(["/test/workspace/src/dirty.ts"])
+		rooIgnore.content = undefined
+	})
+
+	it("only scans files git reports as changed, plus previously pending files", async () => {
+		vi.mocked(getChangedFilesSince).mockResolvedValue({
+			changed: ["/test/workspace/src/a.ts"],
+			deleted: ["/test/workspace/src/b.ts"],
+		})
+
+		await createOrchestrator().startIndexing()
+
+		expect(getChangedFilesSince).toHaveBeenCalledWith(workspacePath, "abc123")
+		expect(scanner.scanDirectory).not.toHaveBeenCalled()
+		expect(scanner.scanChangedFiles).toHaveBeenCalledWith(
+			workspacePath,
+			["/test/workspace/src/a.ts", "/test/workspace/src/edited.ts"],
+			["/test/workspace/src/b.ts"],
+			expect.any(Function),
+			expect.any(Function),
+			expect.any(Function),
+			expect.any(AbortSignal),
+		)
+	})
+
+	it("treats pending files that no longer exist as deleted", async () => {
+		cacheManager.getGitState.mockReturnValue({
+			commit: "abc123",
+			pendingPaths: ["/test/workspace/removed.ts"],
+			rooIgnoreHash: emptyRooIgnoreHash,
+		})
+		vi.mocked(getChangedFilesSince).mockResolvedValue({ changed: [], deleted: [] })
+
+		await createOrchestrator().startIndexing()
+
+		expect(scanner.scanChangedFiles.mock.calls[0][1]).toEqual([])
+		expect(scanner.scanChangedFiles.mock.calls[0][2]).toEqual(["/test/workspace/removed.ts"])
+	})
+
+	it("records the commit captured before the scan once it succeeds", async () => {
+		vi.mocked(getChangedFilesSince).mockResolvedValue({ changed: [], deleted: [] })
+
+		await createOrchestrator().startIndexing()
+
+		expect(cacheManager.setGitState).toHaveBeenCalledWith({
+			commit: "def456",
+			pendingPaths: ["/test/workspace/src/dirty.ts"],
+			rooIgnoreHash: emptyRooIgnoreHash,
+		})
+		expect(stateManager.state).toBe("Indexed")
+	})
+
+	it("falls back to a full workspace scan when git cannot report changes", async () => {
+		vi.mocked(getChangedFilesSince).mockResolvedValue(undefined)
+
+		await createOrchestrator().startIndexing()
+
+		expect(scanner.scanChangedFiles).not.toHaveBeenCalled()
+		expect(scanner.scanDirectory).toHaveBeenCalledTimes(1)
+	})
+
+	it("falls back to a full workspace scan when the ignore patterns changed", async () => {
+		vi.mocked(getChangedFilesSince).mockResolvedValue({ changed: [], deleted: [] })
+		// e.g. rooignore patterns added to .roo/config, which git may not report
+		rooIgnore.content = "dist/"
+
+		await createOrchestrator().startIndexing()
+
+		expect(getChangedFilesSince).not.toHaveBeenCalled()
+		expect(scanner.scanDirectory).toHaveBeenCalledTimes(1)
+		expect(cacheManager.setGitState).toHaveBeenCalledWith({
+			commit: "def456",
+			pendingPaths: ["/test/workspace/src/dirty.ts"],
+			rooIgnoreHash: createHash("sha256").update("dist/").digest("hex"),
+		})
+	})
+
+	it("falls back to a full workspace scan when no commit was recorded", async () => {
+		cacheManager.getGitState.mockReturnValue(undefined)
+
+		await createOrchestrator().startIndexing()
+
+		expect(getChangedFilesSince).not.toHaveBeenCalled()
+		expect(scanner.scanDirectory).toHaveBeenCalledTimes(1)
+	})
+})
+
+describe("CodeIndexOrchestrator - reindexPaths", () => {
+	const workspacePath = "/test/workspace"
+
+	let stateManager: any
+	let cacheManager: any
+	let vectorStore: any
+	let scanner: any
+	let currentState: string
+
+	const createOrchestrator = () =>
+		new CodeIndexOrchestrator(
+			{ isFeatureConfigured: true } as any,
+			stateManager,
+			workspacePath,
+			cacheManager,
+			vectorStore,
+			scanner,
+			{} as any,
+		)
+
+	beforeEach(() => {
+		vi.clearAllMocks()
+
+		currentState = "Indexed"
+		stateManager = {
+			get state() {
+				return currentState
+			},
+			setSystemState: vi.fn().mockImplementation((state: string, _msg: string) => {
+				currentState = state
+			}),
+			reportBlockIndexingProgress: vi.fn(),
+		}
+
+		cacheManager = {
+			getAllHashes: vi.fn().mockReturnValue({
+				"/test/workspace/src/kept.ts": "h1",
+				"/test/workspace/src/gone.ts": "h2",
+				"/test/workspace/other.ts": "h3",
+			}),
+			deleteHash: vi.fn(),
+			addGitPendingPaths: vi.fn().mockResolvedValue(undefined),
+		}
+
+		vectorStore = {
+			deletePointsByMultipleFilePaths: vi.fn().mockResolvedValue(undefined),
+		}
+
+		scanner = {
+			scanChangedFiles: vi.fn().mockResolvedValue({ stats: { processed: 2, skipped: 0 }, totalBlockCount: 4 }),
+		}
+
+		vi.mocked(stat).mockImplementation(
+			async (target: any) => ({ isDirectory: () => target === "/test/workspace/src" }) as any,
+		)
+		vi.mocked(listFiles).mockResolvedValue([
+			["/test/workspace/src/kept.ts", "/test/workspace/src/new.ts", "/test/workspace/src/lib/"],
+			false,
+		])
+	})
+
+	it("re-embeds every file under the paths and removes files that no longer exist", async () => {
+		await createOrchestrator().reindexPaths(["/test/workspace/src"])
+
+		const changed = ["/test/workspace/src/kept.ts", "/test/workspace/src/new.ts"]
+		expect(vectorStore.deletePointsByMultipleFilePaths).toHaveBeenCalledWith(changed)
+		expect(cacheManager.deleteHash).toHaveBeenCalledTimes(2)
+		expect(cacheManager.addGitPendingPaths).toHaveBeenCalledWith(changed)
+		expect(scanner.scanChangedFiles).toHaveBeenCalledWith(
+			workspacePath,
+			changed,
+			["/test/workspace/src/gone.ts"],
+			expect.any(Function),
+			expect.any(Function),
+			expect.any(Function),
+		)
+		expect(currentState).toBe("Indexed")
+	})
+
+	it("rejects while the index is not ready", async () => {
+		currentState = "Indexing"
+
+		await expect(createOrchestrator().reindexPaths(["/test/workspace/src"])).rejects.toThrow("state Indexing")
+		expect(scanner.scanChangedFiles).not.toHaveBeenCalled()
+	})
+
+	it("moves to the error state when a batch fails", async () => {
+		scanner.scanChangedFiles.mockImplementation(
+			async (_directory: string, _changed: string[], _deleted: string[], onError: (error: Error) => void) => {
+				onError(new Error("Embedder unavailable"))
+				return { stats: { processed: 0, skipped: 0 }, totalBlockCount: 0 }
+			},
+		)
+
+		await expect(createOrchestrator().reindexPaths(["/test/workspace/src"])).rejects.toThrow(
+			"Re-indexing failed: Embedder unavailable",
+		)
+		expect(currentState).toBe("Error")
+	})
+})
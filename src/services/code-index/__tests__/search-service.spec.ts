@@ -0,0 +1,157 @@
+import { CodeIndexSearchService } from "../search-service"
+import { createReranker } from "../rerankers"
+
+vitest.mock("../rerankers", () => ({
+	createReranker: vitest.fn(),
+}))
+
+vitest.mock("@roo-code/telemetry", () => ({
+	TelemetryService: {
+		instance: {
+			captureEvent: vitest.fn(),
+		},
+	},
+}))
+
+const result = (id: string, score: number) => ({
+	id,
+	score,
+	payload: { filePath: `src/${id}.ts`, codeChunk: `chunk ${id}`, startLine: 1, endLine: 1 },
+})
+
+describe("CodeIndexSearchService reranking", () => {
+	let configManager: any
+	let vectorStore: any
+	let rerank: ReturnType<typeof vitest.fn>
+	let service: CodeIndexSearchService
+
+	beforeEach(() => {
+		vitest.clearAllMocks()
+
+		rerank = vitest.fn()
+		vitest.mocked(createReranker).mockReturnValue({ rerank, rerankerInfo: { name: "cohere" } })
+
+		configManager = {
+			isFeatureEnabled: true,
+			isFeatureConfigured: true,
+			currentSearchMinScore: 0.4,
+			currentSearchMaxResults: 2,
+			currentHybridSearchWeight: 0,
+			currentRerankerOptions: { provider: "cohere", modelId: "rerank-v3.5", apiKey: "key", candidates: 3 },
+		}
+		vectorStore = {
+			search: vitest.fn().mockResolvedValue([result("a", 0.9), result("b", 0.8), result("c", 0.7)]),
+		}
+		const stateManager: any = {
+			getCurrentStatus: () => ({ systemStatus: "Indexed" }),
+			setSystemState: vitest.fn(),
+		}
+		const embedder: any = { createEmbeddings: vitest.fn().mockResolvedValue({ embeddings: [[0.1, 0.2]] }) }
+
+		service = new CodeIndexSearchService(configManager, stateManager, embedder, vectorStore)
+	})
+
+	it("fetches the candidate pool and reorders it by reranker score", async () => {
+		rerank.mockResolvedValue([0.1, 0.3, 0.9])
+
+		const results = await service.searchIndex("query")
+
+		expect(vectorStore.search).toHaveBeenCalledWith([0.1, 0.2], undefined, 0.4, 3, undefined)
+		expect(rerank).toHaveBeenCalledWith("query", ["chunk a", "chunk b", "chunk c"])
+		expect(results.map((r) => r.id)).toEqual(["c", "b"])
+		expect(results[0].score).toBe(0.9)
+	})
+
+	it("falls back to the original order when reranking fails", async () => {
+		vitest.spyOn(console, "warn").mockImplementation(() => {})
+		rerank.mockRejectedValue(new Error("rate limited"))
+
+		const results = await service.searchIndex("query")
+
+		expect(results.map((r) => r.id)).toEqual(["a", "b"])
+	})
+
+	it("reuses the reranker until its settings change", async () => {
+		rerank.mockResolvedValue([0.1, 0.3, 0.9])
+
+		await service.searchIndex("query")
+		await service.searchIndex("query")
+		expect(createReranker).toHaveBeenCalledTimes(1)
+
+		configManager.currentRerankerOptions = { ...configManager.currentRerankerOptions, modelId: "rerank-v3.0" }
+		await service.searchIndex("query")
+		expect(createReranker).toHaveBeenCalledTimes(2)
+	})
+
+	it("skips reranking when no reranker is configured", async () => {
+		configManager.currentRerankerOptions = undefined
+
+		const results = await service.searchIndex("query")
+
+		expect(vectorStore.search).toHaveBeenCalledWith([0.1, 0.2], undefined, 0.4, 2, undefined)
+		expect(createReranker).not.toHaveBeenCalled()
+		expect(results.map((r) => r.id)).toEqual(["a", "b"])
+	})
+})
+
+describe("CodeIndexSearchService filters", () => {
+	let vectorStore: any
+	let service: CodeIndexSearchService
+
+	const fileResult = (id: string, filePath: string) => {
+		const base = result(id, 0.9)
+		return { ...base, payload: { ...base.payload, filePath } }
+	}
+
+	beforeEach(() => {
+		vitest.clearAllMocks()
+
+		const configManager: any = {
+			isFeatureEnabled: true,
+			isFeatureConfigured: true,
+			currentSearchMinScore: 0.4,
+			currentSearchMaxResults: 2,
+			currentHybridSearchWeight: 0,
+			currentRerankerOptions: undefined,
+		}
+		vectorStore = { search: vitest.fn().mockResolvedValue([]) }
+		const stateManager: any = { getCurrentStatus: () => ({ systemStatus: "Indexed" }), setSystemState: vitest.fn() }
+		const embedder: any = { createEmbeddings: vitest.fn().mockResolvedValue({ embeddings: [[0.1, 0.2]] }) }
+
+		service = new CodeIndexSearchService(configManager, stateManager, embedder, vectorStore)
+	})
+
+	it("normalizes the language and kind before passing them to the store", async () => {
+		await service.searchIndex("query", undefined, { language: "TS", kind: " Function " })
+
+		expect(vectorStore.search).toHaveBeenCalledWith([0.1, 0.2], undefined, 0.4, 2, {
+			language: "typescript",
+			kind: "function",
+		})
+	})
+
+	it("drops empty filters", async () => {
+		await service.searchIndex("query", undefined, { language: "", kind: "  " })
+
+		expect(vectorStore.search).toHaveBeenCalledWith([0.1, 0.2], undefined, 0.4, 2, undefined)
+	})
+
+	it("searches the literal directory of a glob and matches the glob on the results", async () => {
+		vectorStore.search.mockResolvedValue([
+			fileResult("a", "src/api/users/handler.test.ts"),
+			fileResult("b", "src/api/users/handler.ts"),
+			fileResult("c", "src/api/orders.test.ts"),
+		])
+
+		const results = await service.searchIndex("query", "src/api/**/*.test.ts")
+
+		expect(vectorStore.search).toHaveBeenCalledWith([0.1, 0.2], "src/api", 0.4, 8, undefined)
+		expect(results.map((r) => r.id)).toEqual(["a", "c"])
+	})
+
+	it("keeps plain directory prefixes as they are", async () => {
+		await service.searchIndex("query", "src/api")
+
+		expect(vectorStore.search).toHaveBeenCalledWith([0.1, 0.2], "src/api", 0.4, 2, undefined)
+	})
+})
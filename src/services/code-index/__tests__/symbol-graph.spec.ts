@@ -0,0 +1,137 @@
+import type { Mock } from "vitest"
+import * as vscode from "vscode"
+
+import { SymbolGraph, parseSymbolName } from "../symbol-graph"
+import type { BlockSymbols } from "../interfaces"
+
+vitest.mock("../../../utils/safeWriteJson", () => ({
+	safeWriteJson: vitest.fn().mockResolvedValue(undefined),
+}))
+
+import { safeWriteJson } from "../../../utils/safeWriteJson"
+
+vitest.mock("vscode", () => ({
+	Uri: {
+		joinPath: vitest.fn(),
+	},
+	workspace: {
+		fs: {
+			readFile: vitest.fn(),
+		},
+	},
+}))
+
+// Mock debounce to execute immediately
+vitest.mock("lodash.debounce", () => ({ default: vitest.fn((fn) => fn) }))
+
+const point = (id: string, filePath: string, symbols?: BlockSymbols) => ({
+	id,
+	vector: [],
+	payload: { filePath, codeChunk: "", startLine: 1, endLine: 10, ...(symbols && { symbols }) },
+})
+
+const repositorySymbols: BlockSymbols = {
+	definitions: [{ name: "FindByID", kind: "method", container: "Repository", line: 12 }],
+	references: [{ name: "db", line: 13, isCall: false, caller: "FindByID" }],
+}
+
+const serviceSymbols: BlockSymbols = {
+	definitions: [{ name: "GetUser", kind: "method", container: "UserService", line: 20 }],
+	references: [
+		{ name: "FindByID", line: 21, isCall: true, caller: "GetUser" },
+		{ name: "Repository", line: 8, isCall: false },
+	],
+}
+
+describe("parseSymbolName", () => {
+	it("splits qualified names into container and name", () => {
+		expect(parseSymbolName("Repository.FindByID")).toEqual({ name: "FindByID", container: "Repository" })
+		expect(parseSymbolName("repo::find_by_id")).toEqual({ name: "find_by_id", container: "repo" })
+		expect(parseSymbolName(" loadUser ")).toEqual({ name: "loadUser" })
+	})
+})
+
+describe("SymbolGraph", () => {
+	let graph: SymbolGraph
+
+	beforeEach(async () => {
+		vitest.clearAllMocks()
+		;(vscode.Uri.joinPath as Mock).mockReturnValue({ fsPath: "/mock/storage/symbols.json" })
+		;(vscode.workspace.fs.readFile as Mock).mockRejectedValue(new Error("ENOENT"))
+
+		graph = new SymbolGraph(
+			{ globalStorageUri: { fsPath: "/mock/storage" } } as vscode.ExtensionContext,
+			"/mock/workspace",
+		)
+		await graph.initialize()
+	})
+
+	it("finds the definition and callers of a method", () => {
+		graph.upsert([point("a", "repo/user.go", repositorySymbols), point("b", "service/user.go", serviceSymbols)])
+
+		const result = graph.findReferences("Repository.FindByID")
+
+		expect(result.definitions).toEqual([
+			{ name: "FindByID", kind: "method", container: "Repository", line: 12, filePath: "repo/user.go" },
+		])
+		expect(result.references).toEqual([
+			{ name: "FindByID", line: 21, isCall: true, caller: "GetUser", filePath: "service/user.go" },
+		])
+	})
+
+	it("only returns definitions of the requested container", () => {
+		graph.upsert([point("a", "repo/user.go", repositorySymbols)])
+
+		expect(graph.findReferences("Cache.FindByID").definitions).toEqual([])
+		expect(graph.findReferences("FindByID").definitions).toHaveLength(1)
+	})
+
+	it("filters by directory prefix", () => {
+		graph.upsert([point("a", "repo/user.go", repositorySymbols), point("b", "service/user.go", serviceSymbols)])
+
+		const result = graph.findReferences("FindByID", "repo")
+
+		expect(result.definitions).toHaveLength(1)
+		expect(result.references).toEqual([])
+	})
+
+	it("removes symbols when their file is deleted or re-indexed without them", () => {
+		graph.upsert([point("a", "repo/user.go", repositorySymbols), point("b", "service/user.go", serviceSymbols)])
+
+		graph.deleteByFilePaths(["/mock/workspace/service/user.go"])
+		graph.upsert([point("a", "repo/user.go")])
+
+		expect(graph.size).toBe(0)
+		expect(graph.findReferences("FindByID")).toEqual({ definitions: [], references: [] })
+	})
+
+	it("persists symbols and restores them on initialize", async () => {
+		graph.upsert([point("a", "repo/user.go", repositorySymbols)])
+
+		const saved = (safeWriteJson as Mock).mock.calls.at(-1)![1]
+		;(vscode.workspace.fs.readFile as Mock).mockResolvedValue(Buffer.from(JSON.stringify(saved)))
+
+		expect(await graph.initialize()).toBe("loaded")
+
+		expect(graph.findReferences("FindByID").definitions).toHaveLength(1)
+	})
+
+	it("reports a graph that can't be loaded as missing", async () => {
+		expect(await graph.initialize()).toBe("missing")
+
+		// Graphs from before they were versioned only stored the entries
+		const unversioned = { a: { filePath: "repo/user.go", symbols: repositorySymbols } }
+		;(vscode.workspace.fs.readFile as Mock).mockResolvedValue(Buffer.from(JSON.stringify(unversioned)))
+
+		expect(await graph.initialize()).toBe("missing")
+		expect(graph.size).toBe(0)
+	})
+
+	it("reports a graph of another version as outdated", async () => {
+		const stored = { version: 0, entries: { a: { filePath: "repo/user.go", symbols: repositorySymbols } } }
+		;(vscode.workspace.fs.readFile as Mock).mockResolvedValue(Buffer.from(JSON.stringify(stored)))
+
+		expect(await graph.initialize()).toBe("outdated")
+		expect(graph.size).toBe(0)
+	})
+})
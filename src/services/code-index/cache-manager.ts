@@ -1,6 +1,6 @@
 import * as vscode from "vscode"
 import { createHash } from "crypto"
-import { ICacheManager } from "./interfaces/cache"
+import { GitIndexState, ICacheManager } from "./interfaces/cache"
 import debounce from "lodash.debounce"
 import { safeWriteJson } from "../../utils/safeWriteJson"
 import { TelemetryService } from "@roo-code/telemetry"
@@ -11,7 +11,9 @@ import { TelemetryEventName } from "@roo-code/types"
  */
 export class CacheManager implements ICacheManager {
 	private cachePath: vscode.Uri
+	private gitStatePath: vscode.Uri
 	private fileHashes: Record<string, string> = {}
+	private gitState: GitIndexState | undefined
 	private _debouncedSaveCache: () => void
 
 	/**
@@ -27,6 +29,10 @@ export class CacheManager implements ICacheManager {
 			context.globalStorageUri,
 			`roo-index-cache-${createHash("sha256").update(workspacePath).digest("hex")}.json`,
 		)
+		this.gitStatePath = vscode.Uri.joinPath(
+			context.globalStorageUri,
+			`roo-index-git-${createHash("sha256").update(workspacePath).digest("hex")}.json`,
+		)
 		this._debouncedSaveCache = debounce(async () => {
 			await this._performSave()
 		}, 1500)
@@ -47,6 +53,20 @@ export class CacheManager implements ICacheManager {
 				location: "initialize",
 			})
 		}
+
+		try {
+			const stateData = await vscode.workspace.fs.readFile(this.gitStatePath)
+			const state = JSON.parse(stateData.toString())
+			this.gitState =
+				typeof state?.commit === "string" &&
+				Array.isArray(state.pendingPaths) &&
+				typeof state.rooIgnoreHash === "string"
+					? state
+					: undefined
+		} catch {
+			// No git state yet; the next scan walks the whole workspace
+			this.gitState = undefined
+		}
 	}
 
 	/**
@@ -72,6 +92,7 @@ export class CacheManager implements ICacheManager {
 		try {
 			await safeWriteJson(this.cachePath.fsPath, {})
 			this.fileHashes = {}
+			await this.setGitState(undefined)
 		} catch (error) {
 			console.error("Failed to clear cache file:", error, this.cachePath)
 			TelemetryService.instance.captureEvent(TelemetryEventName.CODE_INDEX_ERROR, {
@@ -124,4 +145,47 @@ export class CacheManager implements ICacheManager {
 	getAllHashes(): Record<string, string> {
 		return { ...this.fileHashes }
 	}
+
+	/**
+	 * Gets the git position the index was last synchronized with
+	 * @returns The stored git state or undefined if the index was never synchronized with git
+	 */
+	getGitState(): GitIndexState | undefined {
+		return this.gitState && { ...this.gitState, pendingPaths: [...this.gitState.pendingPaths] }
+	}
+
+	/**
+	 * Replaces the stored git state and persists it immediately
+	 * @param state New git state, or undefined to forget it
+	 */
+	async setGitState(state: GitIndexState | undefined): Promise<void> {
+		this.gitState = state
+		await this._performGitStateSave()
+	}
+
+	/**
+	 * Records files that were re-indexed outside a git-driven scan, so the next
+	 * scan re-checks them even if git no longer reports them as changed
+	 * @param filePaths Paths of the re-indexed files
+	 */
+	async addGitPendingPaths(filePaths: string[]): Promise<void> {
+		if (!this.gitState || filePaths.length === 0) {
+			return
+		}
+		this.gitState.pendingPaths = Array.from(new Set([...this.gitState.pendingPaths, ...filePaths]))
+		await this._performGitStateSave()
+	}
+
+	private async _performGitStateSave(): Promise<void> {
+		try {
+			await safeWriteJson(this.gitStatePath.fsPath, this.gitState ?? {})
+		} catch (error) {
+			console.error("Failed to save git index state:", error)
+			TelemetryService.instance.captureEvent(TelemetryEventName.CODE_INDEX_ERROR, {
+				error: error instanceof Error ? error.message : String(error),
+				stack: error instanceof Error ? error.stack : undefined,
+				location: "_performGitStateSave",
+			})
+		}
+	}
 }
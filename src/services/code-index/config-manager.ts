@@ -1,8 +1,16 @@
+import {
+	type CodebaseIndexDocumentType,
+	type RerankerProvider,
+	CODEBASE_INDEX_DEFAULTS,
+	codebaseIndexDocumentTypes,
+	defaultRerankerModels,
+} from "@roo-code/types"
+
 import { ApiHandlerOptions } from "../../shared/api"
 import { ContextProxy } from "../../core/config/ContextProxy"
 import { EmbedderProvider } from "./interfaces/manager"
-import { CodeIndexConfig, PreviousConfigSnapshot } from "./interfaces/config"
-import { DEFAULT_SEARCH_MIN_SCORE, DEFAULT_MAX_SEARCH_RESULTS } from "./constants"
+import { CodeIndexConfig, PreviousConfigSnapshot, RerankerOptions } from "./interfaces/config"
+import { DEFAULT_SEARCH_MIN_SCORE, DEFAULT_MAX_SEARCH_RESULTS, DEFAULT_HYBRID_SEARCH_WEIGHT } from "./constants"
 import { getDefaultModelId, getModelDimension, getModelScoreThreshold } from "../../shared/embeddingModels"
 
 /**
@@ -26,6 +34,9 @@ export class CodeIndexConfigManager {
 	private qdrantApiKey?: string
 	private searchMinScore?: number
 	private searchMaxResults?: number
+	private hybridSearchWeight?: number
+	private rerankerOptions?: RerankerOptions
+	private documentTypes?: CodebaseIndexDocumentType[]
 
 	constructor(private readonly contextProxy: ContextProxy) {
 		// Initialize with current configuration to avoid false restart triggers
@@ -65,6 +76,11 @@ export class CodeIndexConfigManager {
 			codebaseIndexEmbedderModelId,
 			codebaseIndexSearchMinScore,
 			codebaseIndexSearchMaxResults,
+			codebaseIndexHybridSearchWeight,
+			codebaseIndexRerankerProvider,
+			codebaseIndexRerankerModelId,
+			codebaseIndexRerankerCandidates,
+			codebaseIndexDocumentTypes,
 		} = codebaseIndexConfig
 
 		const openAiKey = this.contextProxy?.getSecret("codeIndexOpenAiKey") ?? ""
@@ -79,6 +95,10 @@ export class CodeIndexConfigManager {
 		const bedrockProfile = codebaseIndexConfig.codebaseIndexBedrockProfile ?? ""
 		const openRouterApiKey = this.contextProxy?.getSecret("codebaseIndexOpenRouterApiKey") ?? ""
 		const openRouterSpecificProvider = codebaseIndexConfig.codebaseIndexOpenRouterSpecificProvider ?? ""
+		const rerankerApiKeys: Partial<Record<RerankerProvider, string>> = {
+			cohere: this.contextProxy?.getSecret("codebaseIndexCohereApiKey"),
+			voyage: this.contextProxy?.getSecret("codebaseIndexVoyageApiKey"),
+		}
 
 		// Update instance variables with configuration
 		this.codebaseIndexEnabled = codebaseIndexEnabled ?? false
@@ -86,6 +106,16 @@ export class CodeIndexConfigManager {
 		this.qdrantApiKey = qdrantApiKey ?? ""
 		this.searchMinScore = codebaseIndexSearchMinScore
 		this.searchMaxResults = codebaseIndexSearchMaxResults
+		this.hybridSearchWeight = codebaseIndexHybridSearchWeight
+		this.rerankerOptions = codebaseIndexRerankerProvider
+			? {
+					provider: codebaseIndexRerankerProvider,
+					modelId: codebaseIndexRerankerModelId || defaultRerankerModels[codebaseIndexRerankerProvider],
+					apiKey: rerankerApiKeys[codebaseIndexRerankerProvider] || undefined,
+					candidates: codebaseIndexRerankerCandidates ?? CODEBASE_INDEX_DEFAULTS.DEFAULT_RERANK_CANDIDATES,
+				}
+			: undefined
+		this.documentTypes = codebaseIndexDocumentTypes
 
 		// Validate and set model dimension
 		const rawDimension = codebaseIndexConfig.codebaseIndexEmbedderModelDimension
@@ -194,6 +224,7 @@ export class CodeIndexConfigManager {
 			openRouterSpecificProvider: this.openRouterOptions?.specificProvider ?? "",
 			qdrantUrl: this.qdrantUrl ?? "",
 			qdrantApiKey: this.qdrantApiKey ?? "",
+			documentTypes: this.currentDocumentTypes,
 		}
 
 		// Refresh secrets from VSCode storage to ensure we have the latest values
@@ -285,6 +316,7 @@ export class CodeIndexConfigManager {
 	 * - Authentication changes (API keys, base URLs)
 	 * - Vector dimension changes (model changes that affect embedding size)
 	 * - Qdrant connection changes (URL, API key)
+	 * - Indexed documentation/config file types (files must be added or removed)
 	 * - Feature enable/disable transitions
 	 *
 	 * MINOR CHANGES (no restart needed):
@@ -405,6 +437,10 @@ export class CodeIndexConfigManager {
 			return true
 		}
 
+		if (this.hasDocumentTypesChanged(prev)) {
+			return true
+		}
+
 		// Vector dimension changes (still important for compatibility)
 		if (this._hasVectorDimensionChanged(prevProvider, prev?.modelId)) {
 			return true
@@ -413,6 +449,14 @@ export class CodeIndexConfigManager {
 		return false
 	}
 
+	/**
+	 * Checks whether the set of indexed documentation/config file types differs from the snapshot.
+	 */
+	public hasDocumentTypesChanged(prev: PreviousConfigSnapshot): boolean {
+		const prevTypes = [...(prev?.documentTypes ?? codebaseIndexDocumentTypes)].sort().join(",")
+		return prevTypes !== [...this.currentDocumentTypes].sort().join(",")
+	}
+
 	/**
 	 * Checks if model changes result in vector dimension changes that require restart.
 	 */
@@ -460,6 +504,9 @@ export class CodeIndexConfigManager {
 			qdrantApiKey: this.qdrantApiKey,
 			searchMinScore: this.currentSearchMinScore,
 			searchMaxResults: this.currentSearchMaxResults,
+			hybridSearchWeight: this.currentHybridSearchWeight,
+			rerankerOptions: this.rerankerOptions,
+			documentTypes: this.currentDocumentTypes,
 		}
 	}
 
@@ -541,4 +588,27 @@ export class CodeIndexConfigManager {
 	public get currentSearchMaxResults(): number {
 		return this.searchMaxResults ?? DEFAULT_MAX_SEARCH_RESULTS
 	}
+
+	/**
+	 * Gets the share of keyword (BM25) ranking blended into search results.
+	 * Returns user setting if configured, otherwise returns default.
+	 */
+	public get currentHybridSearchWeight(): number {
+		return this.hybridSearchWeight ?? DEFAULT_HYBRID_SEARCH_WEIGHT
+	}
+
+	/**
+	 * Gets the reranking configuration, or undefined when reranking is disabled.
+	 */
+	public get currentRerankerOptions(): RerankerOptions | undefined {
+		return this.rerankerOptions
+	}
+
+	/**
+	 * Gets the documentation/config file types included in the index.
+	 * Returns user setting if configured, otherwise all types.
+	 */
+	public get currentDocumentTypes(): CodebaseIndexDocumentType[] {
+		return this.documentTypes ?? [...codebaseIndexDocumentTypes]
+	}
 }
@@ -9,6 +9,9 @@ export const MAX_CHARS_TOLERANCE_FACTOR = 1.15 // 15% tolerance for max chars
 /**Search */
 export const DEFAULT_SEARCH_MIN_SCORE = CODEBASE_INDEX_DEFAULTS.DEFAULT_SEARCH_MIN_SCORE
 export const DEFAULT_MAX_SEARCH_RESULTS = CODEBASE_INDEX_DEFAULTS.DEFAULT_SEARCH_RESULTS
+export const DEFAULT_HYBRID_SEARCH_WEIGHT = CODEBASE_INDEX_DEFAULTS.DEFAULT_HYBRID_SEARCH_WEIGHT
+export const RRF_RANK_CONSTANT = 60 // Dampens the advantage of top ranks in reciprocal rank fusion
+export const PATH_PATTERN_CANDIDATE_FACTOR = 4 // Extra candidates fetched when a glob filters results after retrieval
 
 /**File Watcher */
 export const QDRANT_CODE_BLOCK_NAMESPACE = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
@@ -0,0 +1,47 @@
+import { VectorStoreSearchResult } from "./interfaces"
+import { RRF_RANK_CONSTANT } from "./constants"
+
+/**
+ * Merges vector and keyword rankings with weighted reciprocal rank fusion.
+ *
+ * Each list contributes `weight / (k + rank)` for every result it contains, so a chunk
+ * found by both searches outranks one found by only one of them. Scores are normalized
+ * so that a chunk ranked first by both lists scores 1.
+ *
+ * @param vectorResults Results of the semantic search, best first
+ * @param keywordResults Results of the BM25 search, best first
+ * @param keywordWeight Share of the keyword ranking, between 0 and 1
+ * @param maxResults Maximum number of fused results to return
+ */
+export function fuseSearchResults(
+	vectorResults: VectorStoreSearchResult[],
+	keywordResults: VectorStoreSearchResult[],
+	keywordWeight: number,
+	maxResults: number,
+): VectorStoreSearchResult[] {
+	const vectorWeight = 1 - keywordWeight
+	const fused = new Map<string | number, VectorStoreSearchResult>()
+
+	const accumulate = (results: VectorStoreSearchResult[], weight: number) => {
+		if (weight <= 0) {
+			return
+		}
+		results.forEach((result, index) => {
+			// Scaled by (k + 1) so the top rank contributes exactly its weight
+			const contribution = (weight * (RRF_RANK_CONSTANT + 1)) / (RRF_RANK_CONSTANT + index + 1)
+			const existing = fused.get(result.id)
+			if (existing) {
+				existing.score += contribution
+			} else {
+				fused.set(result.id, { ...result, score: contribution })
+			}
+		})
+	}
+
+	accumulate(vectorResults, vectorWeight)
+	accumulate(keywordResults, keywordWeight)
+
+	return Array.from(fused.values())
+		.sort((a, b) => b.score - a.score)
+		.slice(0, maxResults)
+}
@@ -5,3 +5,15 @@ export interface ICacheManager {
 	flush(): Promise<void>
 	getAllHashes(): Record<string, string>
 }
+
+/**
+ * Git position of the index, used to find changed files without rescanning the workspace
+ */
+export interface GitIndexState {
+	// HEAD commit when the index was last brought up to date
+	commit: string
+	// Files whose indexed content may differ from `commit` (uncommitted or edited since)
+	pendingPaths: string[]
+	// Hash of the effective .rooignore patterns, including the ones from .roo/config
+	rooIgnoreHash: string
+}
@@ -1,3 +1,5 @@
+import type { CodebaseIndexDocumentType, RerankerProvider } from "@roo-code/types"
+
 import { ApiHandlerOptions } from "../../../shared/api" // Adjust path if needed
 import { EmbedderProvider } from "./manager"
 
@@ -21,6 +23,19 @@ export interface CodeIndexConfig {
 	qdrantApiKey?: string
 	searchMinScore?: number
 	searchMaxResults?: number
+	hybridSearchWeight?: number
+	rerankerOptions?: RerankerOptions
+	documentTypes?: CodebaseIndexDocumentType[]
+}
+
+/**
+ * Settings for the optional reranking stage applied to search results
+ */
+export interface RerankerOptions {
+	provider: RerankerProvider
+	modelId: string
+	apiKey?: string
+	candidates: number // Number of top search hits passed to the reranker
 }
 
 /**
@@ -45,4 +60,5 @@ export type PreviousConfigSnapshot = {
 	openRouterSpecificProvider?: string
 	qdrantUrl?: string
 	qdrantApiKey?: string
+	documentTypes?: CodebaseIndexDocumentType[]
 }
@@ -1,5 +1,6 @@
 import * as vscode from "vscode"
 import { PointStruct } from "./vector-store"
+import { BlockSymbols } from "./symbols"
 
 /**
  * Interface for code file parser
@@ -114,4 +115,5 @@ export interface CodeBlock {
 	content: string
 	fileHash: string
 	segmentHash: string
+	symbols?: BlockSymbols
 }
@@ -2,3 +2,5 @@ export * from "./embedder"
 export * from "./vector-store"
 export * from "./file-processor"
 export * from "./manager"
+export * from "./reranker"
+export * from "./symbols"
@@ -1,4 +1,5 @@
 import { VectorStoreSearchResult } from "./vector-store"
+import { SymbolLookupResult } from "./symbols"
 import * as vscode from "vscode"
 
 /**
@@ -91,3 +92,18 @@ export interface IndexProgressUpdate {
 	processedBlockCount?: number
 	totalBlockCount?: number
 }
+
+/**
+ * Search result tagged with the workspace folder whose index it came from
+ */
+export interface WorkspaceSearchResult extends VectorStoreSearchResult {
+	workspacePath: string
+}
+
+/**
+ * Symbol lookup result whose entries are tagged with the workspace folder they came from
+ */
+export interface WorkspaceSymbolLookupResult {
+	definitions: Array<SymbolLookupResult["definitions"][number] & { workspacePath: string }>
+	references: Array<SymbolLookupResult["references"][number] & { workspacePath: string }>
+}
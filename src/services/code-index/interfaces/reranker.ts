@@ -0,0 +1,22 @@
+import type { RerankerProvider } from "@roo-code/types"
+
+/**
+ * Interface for code index rerankers.
+ * A reranker re-scores the top search hits with a cross-encoder that reads the
+ * query and each candidate together, which is more precise than vector similarity alone.
+ */
+export interface IReranker {
+	/**
+	 * Scores each document against the query.
+	 * @param query The search query
+	 * @param documents Candidate texts to score
+	 * @returns Promise resolving to one relevance score per document, in input order (higher is better)
+	 */
+	rerank(query: string, documents: string[]): Promise<number[]>
+
+	get rerankerInfo(): RerankerInfo
+}
+
+export interface RerankerInfo {
+	name: RerankerProvider
+}
@@ -0,0 +1,35 @@
+/**
+ * A symbol defined in a code block, e.g. a function, method, type or interface
+ */
+export interface SymbolDefinition {
+	name: string
+	kind: string // Taken from the tree-sitter query capture, e.g. "function", "method", "struct"
+	container?: string // Enclosing type or receiver, e.g. "Repository" for Repository.FindByID
+	line: number // 1-based
+}
+
+/**
+ * A use of an identifier in a code block
+ */
+export interface SymbolReference {
+	name: string
+	line: number // 1-based
+	isCall: boolean
+	caller?: string // Innermost definition containing the reference
+}
+
+/**
+ * Symbols found in one code block. Each symbol is stored with exactly one block of its file.
+ */
+export interface BlockSymbols {
+	definitions: SymbolDefinition[]
+	references: SymbolReference[]
+}
+
+/**
+ * Definitions and references of a symbol, found by name
+ */
+export interface SymbolLookupResult {
+	definitions: Array<SymbolDefinition & { filePath: string }>
+	references: Array<SymbolReference & { filePath: string }>
+}
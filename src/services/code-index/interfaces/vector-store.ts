@@ -20,12 +20,19 @@ export interface IVectorStore {
 	 */
 	upsertPoints(points: PointStruct[]): Promise<void>
 
+	/**
+	 * Lists the payloads of all indexed code blocks, without their vectors
+	 * @returns Promise resolving to the id and payload of every code block
+	 */
+	listPoints(): Promise<IndexedPoint[]>
+
 	/**
 	 * Searches for similar vectors
 	 * @param queryVector Vector to search for
 	 * @param directoryPrefix Optional directory prefix to filter results
 	 * @param minScore Optional minimum score threshold
 	 * @param maxResults Optional maximum number of results to return
+	 * @param filters Optional language and kind filters
 	 * @returns Promise resolving to search results
 	 */
 	search(
@@ -33,6 +40,7 @@ export interface IVectorStore {
 		directoryPrefix?: string,
 		minScore?: number,
 		maxResults?: number,
+		filters?: SearchFilters,
 	): Promise<VectorStoreSearchResult[]>
 
 	/**
@@ -82,6 +90,30 @@ export interface IVectorStore {
 	markIndexingIncomplete(): Promise<void>
 }
 
+/**
+ * Filters matched exactly against the `language` and `kind` payload fields
+ */
+export interface SearchFilters {
+	language?: string
+	kind?: string
+}
+
+/**
+ * A code block as stored in the vector store
+ */
+export interface IndexedPoint {
+	id: string
+	payload: Payload
+}
+
+/**
+ * Outcome of loading an index that is persisted next to the vector store:
+ * - `loaded`: an index of the current version was loaded
+ * - `missing`: no usable index was stored, so it can be rebuilt from the vector store
+ * - `outdated`: the index was stored by another version and the whole workspace must be indexed again
+ */
+export type StoredIndexState = "loaded" | "missing" | "outdated"
+
 export interface VectorStoreSearchResult {
 	id: string | number
 	score: number
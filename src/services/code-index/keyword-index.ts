@@ -0,0 +1,331 @@
+import * as vscode from "vscode"
+import { createHash } from "crypto"
+import debounce from "lodash.debounce"
+
+import { safeWriteJson } from "../../utils/safeWriteJson"
+import { Payload, SearchFilters, VectorStoreSearchResult } from "./interfaces"
+import { PointStruct, StoredIndexState } from "./interfaces/vector-store"
+import {
+	matchesDirectoryPrefix,
+	matchesSearchFilters,
+	normalizeDirectoryPrefix,
+	toPathKey,
+} from "./vector-store/path-filters"
+
+// Standard Okapi BM25 parameters
+const BM25_K1 = 1.2
+const BM25_B = 0.75
+
+const MIN_TOKEN_LENGTH = 2
+
+// Bump when the stored format or the tokenized payloads change, so existing indexes are rebuilt
+const KEYWORD_INDEX_VERSION = 1
+
+// Words that carry no signal in natural-language queries but appear in most comments
+const STOP_WORDS = new Set([
+	"a",
+	"an",
+	"and",
+	"are",
+	"as",
+	"by",
+	"do",
+	"does",
+	"for",
+	"how",
+	"in",
+	"is",
+	"it",
+	"of",
+	"on",
+	"or",
+	"the",
+	"to",
+	"what",
+	"where",
+	"which",
+	"with",
+])
+
+interface StoredKeywordIndex {
+	version: number
+	documents: Record<string, Payload>
+}
+
+interface KeywordDocument {
+	payload: Payload
+	pathKey: string
+	termFrequencies: Map<string, number>
+	length: number
+}
+
+/**
+ * Splits text into lowercase search terms. Identifiers are kept whole so exact symbol
+ * lookups match, and are additionally split on camelCase and snake_case boundaries so
+ * partial names still hit.
+ */
+export function tokenize(text: string): string[] {
+	const tokens: string[] = []
+	for (const word of text.match(/[A-Za-z0-9_$]+/g) ?? []) {
+		const whole = word.toLowerCase()
+		if (whole.length >= MIN_TOKEN_LENGTH && !STOP_WORDS.has(whole)) {
+			tokens.push(whole)
+		}
+
+		const parts = word
+			.replace(/([a-z0-9])([A-Z])/g, "$1 $2")
+			.replace(/([A-Z]+)([A-Z][a-z])/g, "$1 $2")
+			.split(/[\s_$]+/)
+		if (parts.length > 1) {
+			for (const part of parts) {
+				const lower = part.toLowerCase()
+				if (lower.length >= MIN_TOKEN_LENGTH && lower !== whole && !STOP_WORDS.has(lower)) {
+					tokens.push(lower)
+				}
+			}
+		}
+	}
+	return tokens
+}
+
+/**
+ * BM25 keyword index kept alongside the vector index so exact identifiers and error
+ * strings can be found even when they are semantically unremarkable.
+ *
+ * Only point payloads are persisted; term statistics are rebuilt in memory on load.
+ */
+export class KeywordIndex {
+	private readonly indexPath: vscode.Uri
+	private readonly documents = new Map<string, KeywordDocument>()
+	private readonly postings = new Map<string, Set<string>>()
+	private readonly idsByPath = new Map<string, Set<string>>()
+	private totalLength = 0
+	private readonly _debouncedSave: () => void
+
+	/**
+	 * Creates a new keyword index
+	 * @param context VS Code extension context
+	 * @param workspacePath Path to the workspace
+	 */
+	constructor(
+		context: vscode.ExtensionContext,
+		private readonly workspacePath: string,
+	) {
+		this.indexPath = vscode.Uri.joinPath(
+			context.globalStorageUri,
+			`roo-index-keywords-${createHash("sha256").update(workspacePath).digest("hex")}.json`,
+		)
+		this._debouncedSave = debounce(async () => {
+			await this._performSave()
+		}, 1500)
+	}
+
+	/**
+	 * Loads the persisted index, starting empty if none can be loaded
+	 * @returns Whether an index of the current version was loaded. An index that is missing, unreadable
+	 * or from before indexes were versioned is `missing`, since it can be rebuilt from the vector store.
+	 */
+	async initialize(): Promise<StoredIndexState> {
+		this.reset()
+		let stored: Partial<StoredKeywordIndex>
+		try {
+			const data = await vscode.workspace.fs.readFile(this.indexPath)
+			stored = JSON.parse(data.toString())
+		} catch {
+			// No keyword index yet
+			return "missing"
+		}
+
+		if (typeof stored?.version !== "number" || typeof stored.documents !== "object" || !stored.documents) {
+			return "missing"
+		}
+		if (stored.version !== KEYWORD_INDEX_VERSION) {
+			return "outdated"
+		}
+		for (const [id, payload] of Object.entries(stored.documents)) {
+			if (isPayload(payload)) {
+				this.addDocument(id, payload)
+			}
+		}
+		return "loaded"
+	}
+
+	get size(): number {
+		return this.documents.size
+	}
+
+	/**
+	 * Adds or replaces the documents for the given points
+	 */
+	upsert(points: PointStruct[]): void {
+		for (const point of points) {
+			if (isPayload(point.payload)) {
+				this.removeDocument(point.id)
+				this.addDocument(point.id, point.payload)
+			}
+		}
+		this._debouncedSave()
+	}
+
+	/**
+	 * Removes every document that belongs to one of the given files
+	 */
+	deleteByFilePaths(filePaths: string[]): void {
+		for (const filePath of filePaths) {
+			const ids = this.idsByPath.get(toPathKey(filePath, this.workspacePath))
+			for (const id of Array.from(ids ?? [])) {
+				this.removeDocument(id)
+			}
+		}
+		this._debouncedSave()
+	}
+
+	/**
+	 * Removes all documents and persists the empty index
+	 */
+	async clear(): Promise<void> {
+		this.reset()
+		await this._performSave()
+	}
+
+	/**
+	 * Ranks documents against the query with BM25
+	 * @param query Free-text query
+	 * @param directoryPrefix Optional directory prefix to filter results
+	 * @param limit Maximum number of results to return
+	 * @param filters Optional language and kind filters
+	 * @returns Results ordered by descending BM25 score
+	 */
+	search(
+		query: string,
+		directoryPrefix: string | undefined,
+		limit: number,
+		filters?: SearchFilters,
+	): VectorStoreSearchResult[] {
+		const terms = Array.from(new Set(tokenize(query)))
+		if (terms.length === 0 || this.documents.size === 0) {
+			return []
+		}
+
+		const prefix = normalizeDirectoryPrefix(directoryPrefix)
+		const documentCount = this.documents.size
+		const averageLength = this.totalLength / documentCount
+		const scores = new Map<string, number>()
+
+		for (const term of terms) {
+			const matching = this.postings.get(term)
+			if (!matching) {
+				continue
+			}
+
+			const idf = Math.log(1 + (documentCount - matching.size + 0.5) / (matching.size + 0.5))
+			for (const id of matching) {
+				const document = this.documents.get(id)!
+				if (prefix && !matchesDirectoryPrefix(document.pathKey, prefix)) {
+					continue
+				}
+				if (!matchesSearchFilters(document.payload, filters)) {
+					continue
+				}
+
+				const frequency = document.termFrequencies.get(term)!
+				const normalization = BM25_K1 * (1 - BM25_B + (BM25_B * document.length) / averageLength)
+				const termScore = (idf * frequency * (BM25_K1 + 1)) / (frequency + normalization)
+				scores.set(id, (scores.get(id) ?? 0) + termScore)
+			}
+		}
+
+		return Array.from(scores.entries())
+			.sort((a, b) => b[1] - a[1])
+			.slice(0, limit)
+			.map(([id, score]) => ({ id, score, payload: this.documents.get(id)!.payload }))
+	}
+
+	/**
+	 * Flushes any pending debounced writes to disk immediately
+	 */
+	async flush(): Promise<void> {
+		await this._performSave()
+	}
+
+	private addDocument(id: string, payload: Payload): void {
+		const tokens = tokenize(payload.codeChunk)
+		const termFrequencies = new Map<string, number>()
+		for (const token of tokens) {
+			termFrequencies.set(token, (termFrequencies.get(token) ?? 0) + 1)
+		}
+
+		const pathKey = toPathKey(payload.filePath, this.workspacePath)
+		this.documents.set(id, { payload, pathKey, termFrequencies, length: tokens.length })
+		this.totalLength += tokens.length
+
+		for (const term of termFrequencies.keys()) {
+			let ids = this.postings.get(term)
+			if (!ids) {
+				ids = new Set()
+				this.postings.set(term, ids)
+			}
+			ids.add(id)
+		}
+
+		let pathIds = this.idsByPath.get(pathKey)
+		if (!pathIds) {
+			pathIds = new Set()
+			this.idsByPath.set(pathKey, pathIds)
+		}
+		pathIds.add(id)
+	}
+
+	private removeDocument(id: string): void {
+		const document = this.documents.get(id)
+		if (!document) {
+			return
+		}
+
+		for (const term of document.termFrequencies.keys()) {
+			const ids = this.postings.get(term)
+			ids?.delete(id)
+			if (ids?.size === 0) {
+				this.postings.delete(term)
+			}
+		}
+
+		const pathIds = this.idsByPath.get(document.pathKey)
+		pathIds?.delete(id)
+		if (pathIds?.size === 0) {
+			this.idsByPath.delete(document.pathKey)
+		}
+
+		this.totalLength -= document.length
+		this.documents.delete(id)
+	}
+
+	private reset(): void {
+		this.documents.clear()
+		this.postings.clear()
+		this.idsByPath.clear()
+		this.totalLength = 0
+	}
+
+	private async _performSave(): Promise<void> {
+		try {
+			const stored: StoredKeywordIndex = { version: KEYWORD_INDEX_VERSION, documents: {} }
+			for (const [id, document] of this.documents) {
+				stored.documents[id] = document.payload
+			}
+			await safeWriteJson(this.indexPath.fsPath, stored)
+		} catch (error) {
+			console.error("[KeywordIndex] Failed to save keyword index:", error)
+		}
+	}
+}
+
+function isPayload(payload: Record<string, any> | undefined): payload is Payload {
+	return (
+		!!payload &&
+		typeof payload.filePath === "string" &&
+		typeof payload.codeChunk === "string" &&
+		"startLine" in payload &&
+		"endLine" in payload
+	)
+}
@@ -1,20 +1,24 @@
 import * as vscode from "vscode"
 import { ContextProxy } from "../../core/config/ContextProxy"
-import { VectorStoreSearchResult } from "./interfaces"
-import { IndexingState } from "./interfaces/manager"
+import { SearchFilters, SymbolLookupResult, VectorStoreSearchResult } from "./interfaces"
+import { IndexingState, WorkspaceSearchResult, WorkspaceSymbolLookupResult } from "./interfaces/manager"
+import { PreviousConfigSnapshot } from "./interfaces/config"
 import { CodeIndexConfigManager } from "./config-manager"
 import { CodeIndexStateManager } from "./state-manager"
 import { CodeIndexServiceFactory } from "./service-factory"
 import { CodeIndexSearchService } from "./search-service"
+import { SymbolGraph } from "./symbol-graph"
+import { KeywordIndex } from "./keyword-index"
 import { CodeIndexOrchestrator } from "./orchestrator"
 import { CacheManager } from "./cache-manager"
+import { DEFAULT_MAX_SEARCH_RESULTS } from "./constants"
 import { RooIgnoreController } from "../../core/ignore/RooIgnoreController"
 import fs from "fs/promises"
 import ignore from "ignore"
 import path from "path"
 import { t } from "../../i18n"
 import { TelemetryService } from "@roo-code/telemetry"
-import { TelemetryEventName } from "@roo-code/types"
+import { type CodebaseIndexStats, type CodebaseReindexOptions, TelemetryEventName } from "@roo-code/types"
 
 export class CodeIndexManager {
 	// --- Singleton Implementation ---
@@ -26,6 +30,8 @@ export class CodeIndexManager {
 	private _serviceFactory: CodeIndexServiceFactory | undefined
 	private _orchestrator: CodeIndexOrchestrator | undefined
 	private _searchService: CodeIndexSearchService | undefined
+	private _symbolGraph: SymbolGraph | undefined
+	private _keywordIndex: KeywordIndex | undefined
 	private _cacheManager: CacheManager | undefined
 
 	// Flag to prevent race conditions during error recovery
@@ -80,6 +86,145 @@ export class CodeIndexManager {
 		CodeIndexManager.instances.clear()
 	}
 
+	/**
+	 * Disposes and forgets the manager of a workspace folder, e.g. after the folder was removed.
+	 */
+	public static disposeInstance(workspacePath: string): void {
+		CodeIndexManager.instances.get(workspacePath)?.dispose()
+		CodeIndexManager.instances.delete(workspacePath)
+	}
+
+	/**
+	 * Searches the index of every workspace folder and merges the results by score.
+	 * Folders that are disabled or not initialized are skipped.
+	 * @param query The search query
+	 * @param directoryPrefix Optional directory or glob filter. In a multi-root workspace, a leading
+	 * folder name (e.g. "backend/src") or an absolute path limits the search to that folder.
+	 * @param filters Optional language and definition kind filters
+	 * @returns Results from all searched folders, best first, each tagged with its folder
+	 * @throws The first error if no folder could be searched
+	 */
+	public static async searchAllWorkspaces(
+		query: string,
+		directoryPrefix?: string,
+		filters?: SearchFilters,
+	): Promise<WorkspaceSearchResult[]> {
+		const folderNames = (vscode.workspace.workspaceFolders ?? []).map((folder) => folder.name)
+		const targets = CodeIndexManager.getAllInstances()
+			.filter((manager) => manager.isFeatureEnabled && manager.isWorkspaceEnabled)
+			.map((manager) => ({ manager, prefix: manager.scopeDirectoryPrefix(directoryPrefix, folderNames) }))
+			.filter(({ prefix }) => prefix !== null)
+
+		if (targets.length === 0) {
+			return []
+		}
+
+		const outcomes = await Promise.allSettled(
+			targets.map(({ manager, prefix }) => manager.searchIndex(query, prefix ?? undefined, filters)),
+		)
+
+		const results: WorkspaceSearchResult[] = []
+		outcomes.forEach((outcome, index) => {
+			if (outcome.status === "fulfilled") {
+				const workspacePath = targets[index].manager.workspacePath
+				results.push(...outcome.value.map((result) => ({ ...result, workspacePath })))
+			}
+		})
+
+		if (outcomes.every((outcome) => outcome.status === "rejected")) {
+			throw (outcomes[0] as PromiseRejectedResult).reason
+		}
+
+		const maxResults = Math.max(...targets.map(({ manager }) => manager.searchMaxResults))
+		return results.sort((a, b) => b.score - a.score).slice(0, maxResults)
+	}
+
+	/**
+	 * Looks up a symbol in the symbol graph of every enabled workspace folder.
+	 * @param symbol Symbol name, optionally qualified with its container
+	 * @param directoryPrefix Optional directory filter, scoped to folders like in searchAllWorkspaces
+	 * @returns Definitions and references of all folders, each tagged with its folder
+	 */
+	public static findReferencesInAllWorkspaces(symbol: string, directoryPrefix?: string): WorkspaceSymbolLookupResult {
+		const folderNames = (vscode.workspace.workspaceFolders ?? []).map((folder) => folder.name)
+		const result: WorkspaceSymbolLookupResult = { definitions: [], references: [] }
+
+		for (const manager of CodeIndexManager.getAllInstances()) {
+			if (!manager.isFeatureEnabled || !manager.isWorkspaceEnabled || !manager.isInitialized) {
+				continue
+			}
+			const prefix = manager.scopeDirectoryPrefix(directoryPrefix, folderNames)
+			if (prefix === null) {
+				continue
+			}
+
+			const { definitions, references } = manager.findReferences(symbol, prefix)
+			const workspacePath = manager.workspacePath
+			result.definitions.push(...definitions.map((definition) => ({ ...definition, workspacePath })))
+			result.references.push(...references.map((reference) => ({ ...reference, workspacePath })))
+		}
+
+		return result
+	}
+
+	/**
+	 * Re-indexes workspace folders one after another, for the index control commands and API.
+	 * @param contextProxy Settings used to initialize the services if needed
+	 * @param options Folder and paths to re-index, and whether to clear the index first. Without a
+	 * folder, every folder with indexing enabled is re-indexed; naming a folder enables it.
+	 * @throws Error if no folder can be re-indexed or re-indexing one fails
+	 */
+	public static async reindexWorkspaces(
+		contextProxy: ContextProxy,
+		{ workspacePath, ...options }: CodebaseReindexOptions = {},
+	): Promise<void> {
+		const managers = CodeIndexManager.getAllInstances().filter((manager) =>
+			workspacePath ? manager.workspacePath === path.resolve(workspacePath) : manager.isWorkspaceEnabled,
+		)
+
+		if (managers.length === 0) {
+			throw new Error(
+				workspacePath
+					? `No code index exists for the workspace folder ${workspacePath}.`
+					: "Code indexing is not enabled for any workspace folder.",
+			)
+		}
+
+		for (const manager of managers) {
+			if (workspacePath) {
+				await manager.setWorkspaceEnabled(true)
+			}
+			await manager.reindex(contextProxy, options)
+		}
+	}
+
+	/**
+	 * Gets the indexing progress and size of every workspace folder's index.
+	 */
+	public static getAllIndexStats(): CodebaseIndexStats[] {
+		return CodeIndexManager.getAllInstances().map((manager) => manager.getIndexStats())
+	}
+
+	/**
+	 * Clears the index of one or all initialized workspace folders.
+	 * @param workspacePath Folder to clear; every folder when omitted
+	 * @throws Error if the given folder has no initialized index
+	 */
+	public static async clearWorkspaces(workspacePath?: string): Promise<void> {
+		const managers = CodeIndexManager.getAllInstances().filter(
+			(manager) =>
+				manager.isInitialized && (!workspacePath || manager.workspacePath === path.resolve(workspacePath)),
+		)
+
+		if (workspacePath && managers.length === 0) {
+			throw new Error(`No code index exists for the workspace folder ${workspacePath}.`)
+		}
+
+		for (const manager of managers) {
+			await manager.clearIndexData()
+		}
+	}
+
 	private readonly workspacePath: string
 	private readonly _folderUri: vscode.Uri
 	private readonly context: vscode.ExtensionContext
@@ -166,7 +311,7 @@ export class CodeIndexManager {
 			this._configManager = new CodeIndexConfigManager(contextProxy)
 		}
 		// Load configuration once to get current state and restart requirements
-		const { requiresRestart } = await this._configManager.loadConfiguration()
+		const { configSnapshot, requiresRestart } = await this._configManager.loadConfiguration()
 
 		// 2. Check if feature is enabled
 		if (!this.isFeatureEnabled) {
@@ -194,6 +339,7 @@ export class CodeIndexManager {
 			this._cacheManager = new CacheManager(this.context, this.workspacePath)
 			await this._cacheManager.initialize()
 		}
+		await this._resetGitStateIfDocumentTypesChanged(configSnapshot)
 
 		// 6. Determine if Core Services Need Recreation
 		const needsServiceRecreation = !this._serviceFactory || requiresRestart
@@ -240,6 +386,65 @@ export class CodeIndexManager {
 		await this._orchestrator!.startIndexing()
 	}
 
+	/**
+	 * Re-indexes this workspace folder and waits for the run to finish, unlike startIndexing.
+	 * A run that is already in progress is awaited first.
+	 * @param contextProxy Settings used to initialize the services if needed
+	 * @param options Paths to re-index, and whether to clear the index first. Paths are only
+	 * re-indexed on their own when the folder is already indexed; otherwise the whole folder is scanned.
+	 * @throws Error if indexing is disabled or not configured, or the run fails
+	 */
+	public async reindex(
+		contextProxy: ContextProxy,
+		{ paths, full }: Omit<CodebaseReindexOptions, "workspacePath"> = {},
+	): Promise<void> {
+		if (this.isInitialized && this.state === "Error") {
+			await this.recoverFromError()
+		}
+		await this.initialize(contextProxy)
+
+		if (!this.isFeatureEnabled || !this.isFeatureConfigured || !this.isWorkspaceEnabled) {
+			throw new Error("Code indexing is disabled or not configured for this workspace folder.")
+		}
+		this.assertInitialized()
+		await this._waitUntilIdle()
+
+		if (paths?.length && !full && this.state === "Indexed") {
+			await this._orchestrator!.reindexPaths(paths.map((target) => path.resolve(this.workspacePath, target)))
+			return
+		}
+
+		if (full) {
+			await this.clearIndexData()
+		}
+		await this._orchestrator!.startIndexing()
+
+		if (this.state === "Error") {
+			throw new Error(this._stateManager.getCurrentStatus().message)
+		}
+	}
+
+	/**
+	 * Gets the indexing progress and size of this workspace folder's index.
+	 */
+	public getIndexStats(): CodebaseIndexStats {
+		const { systemStatus, message, processedItems, totalItems, currentItemUnit } =
+			this._stateManager.getCurrentStatus()
+		const lastError = this._stateManager.lastError
+
+		return {
+			workspacePath: this.workspacePath,
+			systemStatus,
+			message,
+			processedItems,
+			totalItems,
+			currentItemUnit,
+			filesIndexed: Object.keys(this._cacheManager?.getAllHashes() ?? {}).length,
+			chunksIndexed: this._keywordIndex?.size ?? 0,
+			...(lastError && { lastError }),
+		}
+	}
+
 	/**
 	 * Stops any in-progress indexing operation and the file watcher.
 	 */
@@ -295,6 +500,8 @@ export class CodeIndexManager {
 			this._serviceFactory = undefined
 			this._orchestrator = undefined
 			this._searchService = undefined
+			this._symbolGraph = undefined
+			this._keywordIndex = undefined
 
 			// Reset the flag after recovery is complete
 			this._isRecoveringFromError = false
@@ -334,12 +541,97 @@ export class CodeIndexManager {
 		}
 	}
 
-	public async searchIndex(query: string, directoryPrefix?: string): Promise<VectorStoreSearchResult[]> {
+	public async searchIndex(
+		query: string,
+		directoryPrefix?: string,
+		filters?: SearchFilters,
+	): Promise<VectorStoreSearchResult[]> {
 		if (!this.isFeatureEnabled) {
 			return []
 		}
 		this.assertInitialized()
-		return this._searchService!.searchIndex(query, directoryPrefix)
+		return this._searchService!.searchIndex(query, directoryPrefix, filters)
+	}
+
+	/**
+	 * Looks up the definitions and references of a symbol in this workspace folder's symbol graph.
+	 * @param symbol Symbol name, optionally qualified with its container (e.g. `Repository.FindByID`)
+	 * @param directoryPrefix Optional directory prefix to filter results
+	 */
+	public findReferences(symbol: string, directoryPrefix?: string): SymbolLookupResult {
+		if (!this.isFeatureEnabled) {
+			return { definitions: [], references: [] }
+		}
+		this.assertInitialized()
+		return this._symbolGraph!.findReferences(symbol, directoryPrefix)
+	}
+
+	public get searchMaxResults(): number {
+		return this._configManager?.currentSearchMaxResults ?? DEFAULT_MAX_SEARCH_RESULTS
+	}
+
+	/**
+	 * Maps a search directory filter onto this workspace folder.
+	 * @param directoryPrefix The filter as given to codebase_search
+	 * @param folderNames Names of all workspace folders
+	 * @returns The filter relative to this folder, undefined for no filter,
+	 * or null if the filter points into another folder
+	 */
+	private scopeDirectoryPrefix(
+		directoryPrefix: string | undefined,
+		folderNames: string[],
+	): string | undefined | null {
+		if (!directoryPrefix) {
+			return undefined
+		}
+
+		if (path.isAbsolute(directoryPrefix)) {
+			const relative = path.relative(this.workspacePath, directoryPrefix)
+			if (relative.startsWith("..") || path.isAbsolute(relative)) {
+				return null
+			}
+			return relative || undefined
+		}
+
+		// A leading folder name only selects a root in multi-root workspaces
+		const [first, ...rest] = directoryPrefix.split(/[\\/]/)
+		if (folderNames.length > 1 && folderNames.includes(first)) {
+			if (first !== this.folderName) {
+				return null
+			}
+			return rest.join("/") || undefined
+		}
+
+		return directoryPrefix
+	}
+
+	// Progress updates fire on every state change, so each one is a chance to re-check the state
+	private async _waitUntilIdle(): Promise<void> {
+		while (this.state === "Indexing" || this.state === "Stopping") {
+			await new Promise<void>((resolve) => {
+				const subscription = this.onProgressUpdate(() => {
+					subscription.dispose()
+					resolve()
+				})
+			})
+		}
+	}
+
+	private get folderName(): string {
+		return (
+			vscode.workspace.workspaceFolders?.find((folder) => folder.uri.fsPath === this.workspacePath)?.name ??
+			path.basename(this.workspacePath)
+		)
+	}
+
+	/**
+	 * Forces the next scan to be a full one when documentation/config types were included or
+	 * excluded, since a git-based incremental scan would only look at changed files.
+	 */
+	private async _resetGitStateIfDocumentTypesChanged(configSnapshot?: PreviousConfigSnapshot): Promise<void> {
+		if (configSnapshot && this._configManager?.hasDocumentTypesChanged(configSnapshot)) {
+			await this._cacheManager?.setGitState(undefined)
+		}
 	}
 
 	/**
@@ -354,6 +646,8 @@ export class CodeIndexManager {
 		// Clear existing services to ensure clean state
 		this._orchestrator = undefined
 		this._searchService = undefined
+		this._symbolGraph = undefined
+		this._keywordIndex = undefined
 
 		// (Re)Initialize service factory
 		this._serviceFactory = new CodeIndexServiceFactory(
@@ -391,12 +685,8 @@ export class CodeIndexManager {
 		await rooIgnoreController.initialize()
 
 		// (Re)Create shared service instances
-		const { embedder, vectorStore, scanner, fileWatcher } = this._serviceFactory.createServices(
-			this.context,
-			this._cacheManager!,
-			ignoreInstance,
-			rooIgnoreController,
-		)
+		const { embedder, vectorStore, keywordIndex, symbolGraph, scanner, fileWatcher } =
+			this._serviceFactory.createServices(this.context, this._cacheManager!, ignoreInstance, rooIgnoreController)
 
 		// Validate embedder configuration before proceeding
 		const validationResult = await this._serviceFactory.validateEmbedder(embedder)
@@ -423,7 +713,10 @@ export class CodeIndexManager {
 			this._stateManager,
 			embedder,
 			vectorStore,
+			keywordIndex,
 		)
+		this._symbolGraph = symbolGraph
+		this._keywordIndex = keywordIndex
 
 		// Clear any error state after successful recreation
 		this._stateManager.setSystemState("Standby", "")
@@ -437,7 +730,7 @@ export class CodeIndexManager {
 	 */
 	public async handleSettingsChange(): Promise<void> {
 		if (this._configManager) {
-			const { requiresRestart } = await this._configManager.loadConfiguration()
+			const { configSnapshot, requiresRestart } = await this._configManager.loadConfiguration()
 
 			const isFeatureEnabled = this.isFeatureEnabled
 			const isFeatureConfigured = this.isFeatureConfigured
@@ -456,6 +749,7 @@ export class CodeIndexManager {
 						this._cacheManager = new CacheManager(this.context, this.workspacePath)
 						await this._cacheManager.initialize()
 					}
+					await this._resetGitStateIfDocumentTypesChanged(configSnapshot)
 
 					// Recreate services with new configuration
 					await this._recreateServices()
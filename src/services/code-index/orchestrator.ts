@@ -1,10 +1,18 @@
 import * as vscode from "vscode"
 import * as path from "path"
+import { createHash } from "crypto"
+import { stat } from "fs/promises"
 import { CodeIndexConfigManager } from "./config-manager"
 import { CodeIndexStateManager, IndexingState } from "./state-manager"
 import { IFileWatcher, IVectorStore, BatchProcessingSummary } from "./interfaces"
+import { GitIndexState } from "./interfaces/cache"
 import { DirectoryScanner } from "./processors"
+import { GitFileChanges, getChangedFilesSince, getHeadCommit, getUncommittedFiles } from "./processors/git-changes"
 import { CacheManager } from "./cache-manager"
+import { RooIgnoreController } from "../../core/ignore/RooIgnoreController"
+import { fileExistsAtPath } from "../../utils/fs"
+import { listFiles } from "../glob/list-files"
+import { MAX_LIST_FILES_LIMIT_CODE_INDEX } from "./constants"
 import { TelemetryService } from "@roo-code/telemetry"
 import { TelemetryEventName } from "@roo-code/types"
 import { t } from "../../i18n"
@@ -65,6 +73,9 @@ export class CodeIndexOrchestrator {
 					}
 				}),
 				this.fileWatcher.onDidFinishBatchProcessing((summary: BatchProcessingSummary) => {
+					// Keep these files in the next git-driven scan even if git stops reporting them as changed
+					void this.cacheManager.addGitPendingPaths(summary.processedFiles.map((f) => f.path))
+
 					if (summary.batchError) {
 						console.error(`[CodeIndexOrchestrator] Batch processing failed:`, summary.batchError)
 					} else {
@@ -155,6 +166,10 @@ export class CodeIndexOrchestrator {
 				// Mark as incomplete at the start of incremental scan
 				await this.vectorStore.markIndexingIncomplete()
 
+				// Capture the git position before scanning so changes made during the scan are diffed next time
+				const gitState = await this._captureGitState()
+				const gitChanges = await this._getGitChanges()
+
 				let cumulativeBlocksIndexed = 0
 				let cumulativeBlocksFoundSoFar = 0
 				let batchErrors: Error[] = []
@@ -169,20 +184,33 @@ export class CodeIndexOrchestrator {
 					this.stateManager.reportBlockIndexingProgress(cumulativeBlocksIndexed, cumulativeBlocksFoundSoFar)
 				}
 
-				// Run incremental scan - scanner will skip unchanged files using cache
-				const result = await this.scanner.scanDirectory(
-					this.workspacePath,
-					(batchError: Error) => {
-						console.error(
-							`[CodeIndexOrchestrator] Error during incremental scan batch: ${batchError.message}`,
-							batchError,
+				const handleBatchError = (batchError: Error) => {
+					console.error(
+						`[CodeIndexOrchestrator] Error during incremental scan batch: ${batchError.message}`,
+						batchError,
+					)
+					batchErrors.push(batchError)
+				}
+
+				// Run incremental scan - scanner will skip unchanged files using cache. When git knows what
+				// changed since the last scan, only those files are checked instead of the whole workspace.
+				const result = gitChanges
+					? await this.scanner.scanChangedFiles(
+							this.workspacePath,
+							gitChanges.changed,
+							gitChanges.deleted,
+							handleBatchError,
+							handleBlocksIndexed,
+							handleFileParsed,
+							signal,
+						)
+					: await this.scanner.scanDirectory(
+							this.workspacePath,
+							handleBatchError,
+							handleBlocksIndexed,
+							handleFileParsed,
+							signal,
 						)
-						batchErrors.push(batchError)
-					},
-					handleBlocksIndexed,
-					handleFileParsed,
-					signal,
-				)
 
 				if (signal.aborted) {
 					await this.cacheManager.flush()
@@ -204,6 +232,8 @@ export class CodeIndexOrchestrator {
 					console.log("[CodeIndexOrchestrator] No new or changed files found")
 				}
 
+				await this.cacheManager.setGitState(gitState)
+
 				await this._startWatcher()
 
 				// Mark indexing as complete after successful incremental scan
@@ -217,6 +247,8 @@ export class CodeIndexOrchestrator {
 				// Mark as incomplete at the start of full scan
 				await this.vectorStore.markIndexingIncomplete()
 
+				const gitState = await this._captureGitState()
+
 				let cumulativeBlocksIndexed = 0
 				let cumulativeBlocksFoundSoFar = 0
 				let batchErrors: Error[] = []
@@ -293,6 +325,8 @@ export class CodeIndexOrchestrator {
 					throw new Error(t("embeddings:orchestrator.indexingFailedCritical"))
 				}
 
+				await this.cacheManager.setGitState(gitState)
+
 				await this._startWatcher()
 
 				// Mark indexing as complete after successful full scan
@@ -357,6 +391,153 @@ export class CodeIndexOrchestrator {
 		}
 	}
 
+	/**
+	 * Re-indexes the given files and directories even if their content did not change.
+	 * Indexed files that no longer exist under them are removed from the index.
+	 * @param targetPaths Absolute paths of files or directories inside the workspace
+	 * @throws Error if the index is not ready or another operation is in progress
+	 */
+	public async reindexPaths(targetPaths: string[]): Promise<void> {
+		if (this._isProcessing || this.stateManager.state !== "Indexed") {
+			throw new Error(`Cannot re-index paths while the index is in state ${this.stateManager.state}.`)
+		}
+
+		this._isProcessing = true
+		this.stateManager.setSystemState("Indexing", `Re-indexing ${targetPaths.length} paths...`)
+
+		try {
+			const cachedPaths = Object.keys(this.cacheManager.getAllHashes())
+			const changed = new Set<string>()
+			const deleted = new Set<string>()
+
+			for (const targetPath of targetPaths) {
+				const stats = await stat(targetPath).catch(() => undefined)
+				if (stats?.isDirectory()) {
+					const [listedPaths] = await listFiles(targetPath, true, MAX_LIST_FILES_LIMIT_CODE_INDEX)
+					listedPaths.filter((listedPath) => !listedPath.endsWith("/")).forEach((p) => changed.add(p))
+				} else if (stats) {
+					changed.add(targetPath)
+				}
+
+				for (const cachedPath of cachedPaths) {
+					const isInTarget = cachedPath === targetPath || cachedPath.startsWith(targetPath + path.sep)
+					if (isInTarget && !changed.has(cachedPath)) {
+						deleted.add(cachedPath)
+					}
+				}
+			}
+
+			// Drop the cached hashes so unchanged files are embedded again, and keep the files on the
+			// git pending list so a failed run is retried by the next incremental scan
+			const changedPaths = Array.from(changed)
+			await this.vectorStore.deletePointsByMultipleFilePaths(changedPaths)
+			changedPaths.forEach((filePath) => this.cacheManager.deleteHash(filePath))
+			await this.cacheManager.addGitPendingPaths(changedPaths)
+
+			let blocksIndexed = 0
+			let blocksFound = 0
+			const batchErrors: Error[] = []
+
+			await this.scanner.scanChangedFiles(
+				this.workspacePath,
+				changedPaths,
+				Array.from(deleted),
+				(batchError) => batchErrors.push(batchError),
+				(indexedCount) => {
+					blocksIndexed += indexedCount
+					this.stateManager.reportBlockIndexingProgress(blocksIndexed, blocksFound)
+				},
+				(fileBlockCount) => {
+					blocksFound += fileBlockCount
+					this.stateManager.reportBlockIndexingProgress(blocksIndexed, blocksFound)
+				},
+			)
+
+			if (batchErrors.length > 0) {
+				throw new Error(`Re-indexing failed: ${batchErrors[0].message}`)
+			}
+
+			this.stateManager.setSystemState("Indexed", t("embeddings:orchestrator.fileWatcherStarted"))
+		} catch (error: any) {
+			console.error("[CodeIndexOrchestrator] Error during re-indexing:", error)
+			TelemetryService.instance.captureEvent(TelemetryEventName.CODE_INDEX_ERROR, {
+				error: error instanceof Error ? error.message : String(error),
+				stack: error instanceof Error ? error.stack : undefined,
+				location: "reindexPaths",
+			})
+			this.stateManager.setSystemState("Error", error.message)
+			throw error
+		} finally {
+			this._isProcessing = false
+		}
+	}
+
+	/**
+	 * Reads the current git position of the workspace.
+	 * @returns The state to store once the scan succeeds, or undefined when git is unavailable
+	 */
+	private async _captureGitState(): Promise<GitIndexState | undefined> {
+		const commit = await getHeadCommit(this.workspacePath)
+		if (!commit) {
+			return undefined
+		}
+
+		const pendingPaths = await getUncommittedFiles(this.workspacePath)
+		return pendingPaths ? { commit, pendingPaths, rooIgnoreHash: await this._getRooIgnoreHash() } : undefined
+	}
+
+	/**
+	 * Hashes the effective .rooignore patterns. Git doesn't report every change to them,
+	 * since the patterns from .roo/config can change without any ignore file changing.
+	 */
+	private async _getRooIgnoreHash(): Promise<string> {
+		const rooIgnoreController = new RooIgnoreController(this.workspacePath)
+		try {
+			await rooIgnoreController.initialize()
+			return createHash("sha256").update(rooIgnoreController.rooIgnoreContent ?? "").digest("hex")
+		} finally {
+			rooIgnoreController.dispose()
+		}
+	}
+
+	/**
+	 * Determines which files changed since the last scan using git.
+	 * @returns The changed files, or undefined if a full workspace scan is needed
+	 */
+	private async _getGitChanges(): Promise<GitFileChanges | undefined> {
+		const previousState = this.cacheManager.getGitState()
+		if (!previousState) {
+			return undefined
+		}
+
+		// Files that are no longer ignored, or newly ignored ones, only turn up in a full scan
+		if (previousState.rooIgnoreHash !== (await this._getRooIgnoreHash())) {
+			console.log("[CodeIndexOrchestrator] Ignore patterns changed. Falling back to a full workspace scan.")
+			return undefined
+		}
+
+		const changes = await getChangedFilesSince(this.workspacePath, previousState.commit)
+		if (!changes) {
+			console.log("[CodeIndexOrchestrator] Git changes unavailable. Falling back to a full workspace scan.")
+			return undefined
+		}
+
+		// Files that were uncommitted or edited at the last scan may have been reverted since,
+		// which git no longer reports, so re-check them against their cached hash
+		const changed = new Set(changes.changed)
+		const deleted = new Set(changes.deleted)
+		for (const pendingPath of previousState.pendingPaths) {
+			if (!changed.has(pendingPath) && !deleted.has(pendingPath)) {
+				;((await fileExistsAtPath(pendingPath)) ? changed : deleted).add(pendingPath)
+			}
+		}
+
+		console.log(
+			`[CodeIndexOrchestrator] Git reports ${changed.size} changed and ${deleted.size} deleted files since ${previousState.commit}.`,
+		)
+		return { changed: Array.from(changed), deleted: Array.from(deleted) }
+	}
+
 	/**
 	 * Stops any in-progress indexing by aborting the scan and stopping the file watcher.
 	 */
@@ -0,0 +1,77 @@
+// npx vitest services/code-index/processors/__tests__/config-sections.spec.ts
+
+import { findConfigSections } from "../config-sections"
+
+describe("findConfigSections", () => {
+	it("splits YAML by top-level key and keeps leading comments with the following key", () => {
+		const content = [
+			"# Deployment settings",
+			"name: web",
+			"",
+			"# Where it runs",
+			"deploy:",
+			"  region: eu-west-1",
+			"  replicas: 3",
+			"",
+		].join("\n")
+
+		expect(findConfigSections(content, 0)).toEqual([
+			{ identifier: "name", type: "config_section", startLine: 1, endLine: 2 },
+			{ identifier: "deploy", type: "config_section", startLine: 4, endLine: 7 },
+		])
+	})
+
+	it("merges adjacent sections that are too small on their own", () => {
+		const content = ["name: web", "version: 1.2.3", "description: The public web frontend"].join("\n")
+
+		expect(findConfigSections(content, 30)).toEqual([
+			{ identifier: "name, version, description", type: "config_section", startLine: 1, endLine: 3 },
+		])
+	})
+
+	it("splits OpenAPI specs by path and component", () => {
+		const content = [
+			"openapi: 3.0.0",
+			"info:",
+			"  title: Deploy API",
+			"paths:",
+			"  /deployments:",
+			"    get:",
+			"      summary: List deployments",
+			"  /deployments/{id}:",
+			"    delete:",
+			"      summary: Cancel a deployment",
+			"components:",
+			"  schemas:",
+			"    Deployment:",
+			"      type: object",
+			"",
+		].join("\n")
+
+		expect(findConfigSections(content, 0)).toEqual([
+			{ identifier: "openapi", type: "config_section", startLine: 1, endLine: 1 },
+			{ identifier: "info", type: "config_section", startLine: 2, endLine: 3 },
+			{ identifier: "/deployments", type: "openapi_path", startLine: 4, endLine: 7 },
+			{ identifier: "/deployments/{id}", type: "openapi_path", startLine: 8, endLine: 10 },
+			{ identifier: "schemas.Deployment", type: "openapi_component", startLine: 11, endLine: 14 },
+		])
+	})
+
+	it("splits formatted JSON by top-level key", () => {
+		const content = JSON.stringify({ name: "app", scripts: { build: "tsc" } }, null, 2)
+
+		expect(findConfigSections(content, 0)).toEqual([
+			{ identifier: "name", type: "config_section", startLine: 1, endLine: 2 },
+			{ identifier: "scripts", type: "config_section", startLine: 3, endLine: 5 },
+		])
+	})
+
+	it("returns undefined for minified JSON", () => {
+		expect(findConfigSections('{"name":"app","version":"1.0.0"}', 0)).toBeUndefined()
+	})
+
+	it("returns undefined for content that is not a mapping", () => {
+		expect(findConfigSections("- one\n- two\n", 0)).toBeUndefined()
+		expect(findConfigSections("key: [unclosed", 0)).toBeUndefined()
+	})
+})
@@ -0,0 +1,92 @@
+import * as path from "path"
+import { execFile } from "child_process"
+
+import { getChangedFilesSince, getHeadCommit, getUncommittedFiles } from "../git-changes"
+
+vitest.mock("child_process", () => ({
+	execFile: vitest.fn(),
+}))
+
+const cwd = path.join(path.sep, "repo", "packages", "app")
+
+/**
+ * Answers git invocations by their subcommand; a missing entry fails like a git error
+ */
+function mockGit(outputs: Record<string, string>) {
+	vitest.mocked(execFile).mockImplementation(((_file: string, args: string[], _options: unknown, callback: any) => {
+		const output = outputs[args[0]]
+		if (output === undefined) {
+			callback(new Error(`git ${args[0]} failed`))
+		} else {
+			callback(null, { stdout: output, stderr: "" })
+		}
+	}) as any)
+}
+
+describe("git-changes", () => {
+	beforeEach(() => {
+		vitest.clearAllMocks()
+	})
+
+	describe("getHeadCommit", () => {
+		it("returns the trimmed HEAD hash", async () => {
+			mockGit({ "rev-parse": "abc123\n" })
+
+			expect(await getHeadCommit(cwd)).toBe("abc123")
+		})
+
+		it("returns undefined outside a repository", async () => {
+			mockGit({})
+
+			expect(await getHeadCommit(cwd)).toBeUndefined()
+		})
+	})
+
+	describe("getChangedFilesSince", () => {
+		it("splits changed and deleted files and includes untracked files", async () => {
+			mockGit({
+				"cat-file": "",
+				diff: "M\0src/a.ts\0A\0src/new.ts\0D\0src/old.ts\0",
+				"ls-files": "scratch.ts\0",
+			})
+
+			expect(await getChangedFilesSince(cwd, "abc123")).toEqual({
+				changed: [
+					path.resolve(cwd, "src/a.ts"),
+					path.resolve(cwd, "src/new.ts"),
+					path.resolve(cwd, "scratch.ts"),
+				],
+				deleted: [path.resolve(cwd, "src/old.ts")],
+			})
+		})
+
+		it("requires a full scan when a tracked ignore file changed", async () => {
+			mockGit({ "cat-file": "", diff: "M\0src/a.ts\0M\0.gitignore\0", "ls-files": "" })
+
+			expect(await getChangedFilesSince(cwd, "abc123")).toBeUndefined()
+		})
+
+		it("requires a full scan when there is an untracked ignore file", async () => {
+			mockGit({ "cat-file": "", diff: "M\0src/a.ts\0", "ls-files": "scratch.ts\0src/.rooignore\0" })
+
+			expect(await getChangedFilesSince(cwd, "abc123")).toBeUndefined()
+		})
+
+		it("requires a full scan when the commit no longer exists", async () => {
+			mockGit({ diff: "", "ls-files": "" })
+
+			expect(await getChangedFilesSince(cwd, "gone")).toBeUndefined()
+		})
+	})
+
+	describe("getUncommittedFiles", () => {
+		it("combines modified and untracked files", async () => {
+			mockGit({ diff: "src/a.ts\0", "ls-files": "scratch.ts\0" })
+
+			expect(await getUncommittedFiles(cwd)).toEqual([
+				path.resolve(cwd, "src/a.ts"),
+				path.resolve(cwd, "scratch.ts"),
+			])
+		})
+	})
+})
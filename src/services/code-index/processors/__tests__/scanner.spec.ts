@@ -1,6 +1,7 @@
 // npx vitest services/code-index/processors/__tests__/scanner.spec.ts
 
 import { DirectoryScanner } from "../scanner"
+import { getIndexedExtensions } from "../../shared/supported-extensions"
 import { stat } from "fs/promises"
 
 // Mock TelemetryService
@@ -259,6 +260,30 @@ describe("DirectoryScanner", () => {
 			expect(mockCodeParser.parseFile).toHaveBeenCalledTimes(2)
 		})
 
+		it("should skip documentation types that are not enabled and lock files", async () => {
+			const codeOnlyScanner = new DirectoryScanner(
+				null as any,
+				null as any,
+				mockCodeParser,
+				mockCacheManager,
+				mockIgnoreInstance,
+				undefined,
+				getIndexedExtensions(["config"]),
+			)
+
+			const { listFiles } = await import("../../../glob/list-files")
+			vi.mocked(listFiles).mockResolvedValue([
+				["test/README.md", "test/app.js", "deploy/values.yaml", "package-lock.json"],
+				false,
+			])
+			;(mockCodeParser.parseFile as any).mockResolvedValue([])
+
+			await codeOnlyScanner.scanDirectory("/test")
+
+			const parsedFiles = (mockCodeParser.parseFile as any).mock.calls.map((call: any[]) => call[0])
+			expect(parsedFiles).toEqual(["test/app.js", "deploy/values.yaml"])
+		})
+
 		it("should process markdown files alongside code files", async () => {
 			// Create scanner without embedder to test the non-embedding path
 			const scannerNoEmbeddings = new DirectoryScanner(
@@ -395,6 +420,46 @@ describe("DirectoryScanner", () => {
 			expect(points[2].payload.segmentHash).toBe("unique-segment-hash-3")
 		})
 
+		it("should store the language and definition kind of each block", async () => {
+			const { listFiles } = await import("../../../glob/list-files")
+			vi.mocked(listFiles).mockResolvedValue([["test/repo.go"], false])
+
+			const mockBlocks: any[] = [
+				{
+					file_path: "test/repo.go",
+					content: "func (r *Repository) FindByID(id string) (*User, error) {\n\treturn r.db.Find(id)\n}",
+					start_line: 1,
+					end_line: 3,
+					identifier: "FindByID",
+					type: "method_declaration",
+					fileHash: "go-file-hash",
+					segmentHash: "go-segment-hash",
+					symbols: {
+						definitions: [{ name: "FindByID", kind: "method", container: "Repository", line: 1 }],
+						references: [],
+					},
+				},
+				{
+					file_path: "test/repo.go",
+					content: "// A block without definitions falls back to its node type",
+					start_line: 5,
+					end_line: 5,
+					identifier: null,
+					type: "comment",
+					fileHash: "go-file-hash",
+					segmentHash: "go-comment-hash",
+				},
+			]
+
+			;(mockCodeParser.parseFile as any).mockResolvedValue(mockBlocks)
+
+			await scanner.scanDirectory("/test")
+
+			const points = mockVectorStore.upsertPoints.mock.calls[0][0]
+			expect(points[0].payload).toMatchObject({ language: "go", kind: "method" })
+			expect(points[1].payload).toMatchObject({ language: "go", kind: "comment" })
+		})
+
 		it("should stop processing files when signal is aborted", async () => {
 			const { listFiles } = await import("../../../glob/list-files")
 			vi.mocked(listFiles).mockResolvedValue([["test/file1.js", "test/file2.js", "test/file3.js"], false])
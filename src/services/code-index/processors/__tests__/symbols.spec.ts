@@ -0,0 +1,112 @@
+// npx vitest services/code-index/processors/__tests__/symbols.spec.ts
+
+import * as path from "path"
+import { Parser, Language, Query } from "web-tree-sitter"
+
+import { goQuery } from "../../../tree-sitter/queries"
+import { attachSymbols, extractSymbols } from "../symbols"
+import type { CodeBlock } from "../../interfaces"
+
+const userService = String.raw`package service
+
+type UserStore interface {
+	FindByID(id string) (*User, error)
+}
+
+type Repository struct {
+	db *sql.DB
+}
+
+func (r *Repository) FindByID(id string) (*User, error) {
+	return r.db.QueryUser(id)
+}
+
+type UserService struct {
+	repo UserStore
+}
+
+func (s *UserService) GetUser(id string) (*User, error) {
+	return s.repo.FindByID(id)
+}
+`
+
+describe("extractSymbols", () => {
+	let parser: Parser
+	let query: Query
+
+	beforeAll(async () => {
+		await Parser.init()
+		const language = await Language.load(path.join(process.cwd(), "dist/tree-sitter-go.wasm"))
+		parser = new Parser()
+		parser.setLanguage(language)
+		query = new Query(language, goQuery)
+	})
+
+	const extract = (content: string) => {
+		const tree = parser.parse(content)!
+		return extractSymbols(tree.rootNode, query.captures(tree.rootNode))
+	}
+
+	it("extracts structs, interfaces and methods with their receiver", () => {
+		const { definitions } = extract(userService)
+
+		expect(definitions).toEqual([
+			{ name: "UserStore", kind: "interface", line: 3 },
+			{ name: "Repository", kind: "struct", line: 7 },
+			{ name: "FindByID", kind: "method", container: "Repository", line: 11 },
+			{ name: "UserService", kind: "struct", line: 15 },
+			{ name: "GetUser", kind: "method", container: "UserService", line: 19 },
+		])
+	})
+
+	it("marks calls and attributes them to the calling function", () => {
+		const { references } = extract(userService)
+
+		expect(references).toContainEqual({ name: "FindByID", line: 20, isCall: true, caller: "GetUser" })
+		expect(references).toContainEqual({ name: "QueryUser", line: 12, isCall: true, caller: "FindByID" })
+		expect(references).toContainEqual({ name: "UserStore", line: 16, isCall: false, caller: "UserService" })
+		expect(references).toContainEqual({ name: "repo", line: 20, isCall: false, caller: "GetUser" })
+	})
+
+	it("does not report definitions as references", () => {
+		const { references } = extract(userService)
+
+		expect(references).not.toContainEqual(expect.objectContaining({ name: "GetUser" }))
+	})
+})
+
+describe("attachSymbols", () => {
+	const block = (start_line: number, end_line: number): CodeBlock => ({
+		file_path: "service.go",
+		identifier: null,
+		type: "method_declaration",
+		start_line,
+		end_line,
+		content: "",
+		fileHash: "hash",
+		segmentHash: `${start_line}-${end_line}`,
+	})
+
+	it("stores each symbol once, with the smallest block containing it", () => {
+		const outer = block(1, 20)
+		const inner = block(5, 8)
+
+		attachSymbols([outer, inner], {
+			definitions: [{ name: "GetUser", kind: "method", line: 5 }],
+			references: [
+				{ name: "FindByID", line: 6, isCall: true },
+				{ name: "UserStore", line: 15, isCall: false },
+				{ name: "unused", line: 30, isCall: false },
+			],
+		})
+
+		expect(inner.symbols).toEqual({
+			definitions: [{ name: "GetUser", kind: "method", line: 5 }],
+			references: [{ name: "FindByID", line: 6, isCall: true }],
+		})
+		expect(outer.symbols).toEqual({
+			definitions: [],
+			references: [{ name: "UserStore", line: 15, isCall: false }],
+		})
+	})
+})
@@ -0,0 +1,138 @@
+import { isMap, parseDocument, type Node, type Pair } from "yaml"
+
+/**
+ * A contiguous range of lines in a YAML or JSON file that describes one setting or spec entry
+ */
+export interface ConfigSection {
+	identifier: string
+	type: "config_section" | "openapi_path" | "openapi_component"
+	startLine: number // 1-based, inclusive
+	endLine: number // 1-based, inclusive
+}
+
+interface NodeRange {
+	identifier: string
+	type: ConfigSection["type"]
+	start: number // Character offsets
+	end: number
+}
+
+/**
+ * Splits a YAML or JSON document into sections, one per top-level key.
+ *
+ * OpenAPI and Swagger specs are split further so that every path and every schema or other
+ * component gets its own section. Adjacent sections shorter than `minSectionChars` are merged
+ * so small settings such as `name` or `version` are kept together instead of being dropped.
+ * Lines between two sections (comments, blank lines, parent keys) belong to the following one.
+ *
+ * @param content File content
+ * @param minSectionChars Minimum size of a section before it is merged with the next one
+ * @returns Sections in document order, or undefined if the content is not a mapping with one key per line
+ */
+export function findConfigSections(content: string, minSectionChars: number): ConfigSection[] | undefined {
+	let ranges: NodeRange[]
+	try {
+		const doc = parseDocument(content, { uniqueKeys: false })
+		if (doc.errors.length > 0 || !isMap(doc.contents)) {
+			return undefined
+		}
+
+		const root = doc.contents
+		const isOpenApi = root.has("openapi") || root.has("swagger")
+
+		ranges = root.items.flatMap((pair): NodeRange[] => {
+			const key = keyName(pair)
+			if (isOpenApi && key === "paths") {
+				return childRanges(pair.value, "openapi_path", "")
+			}
+			if (isOpenApi && key === "definitions") {
+				return childRanges(pair.value, "openapi_component", "definitions.")
+			}
+			if (isOpenApi && key === "components" && isMap(pair.value)) {
+				return pair.value.items.flatMap((group) =>
+					childRanges(group.value, "openapi_component", `${keyName(group)}.`),
+				)
+			}
+			const range = pairRange(pair)
+			return range ? [{ identifier: key, type: "config_section", ...range }] : []
+		})
+	} catch {
+		return undefined
+	}
+
+	const lineStarts = [0]
+	for (let i = 0; i < content.length; i++) {
+		if (content[i] === "\n") {
+			lineStarts.push(i + 1)
+		}
+	}
+	const lineAt = (offset: number) => {
+		let low = 0
+		let high = lineStarts.length - 1
+		while (low < high) {
+			const mid = Math.ceil((low + high) / 2)
+			if (lineStarts[mid] <= offset) {
+				low = mid
+			} else {
+				high = mid - 1
+			}
+		}
+		return low + 1
+	}
+
+	const merged: NodeRange[] = []
+	for (const range of ranges.sort((a, b) => a.start - b.start)) {
+		const previous = merged[merged.length - 1]
+		if (previous && previous.type === range.type && previous.end - previous.start < minSectionChars) {
+			previous.identifier = `${previous.identifier}, ${range.identifier}`
+			previous.end = range.end
+		} else {
+			merged.push({ ...range })
+		}
+	}
+
+	const sections: ConfigSection[] = []
+	for (const range of merged) {
+		const keyLine = lineAt(range.start)
+		const endLine = lineAt(Math.max(range.start, range.end - 1))
+		const previous = sections[sections.length - 1]
+
+		// Minified JSON puts several keys on one line, which cannot be split by lines
+		if (previous && keyLine <= previous.endLine) {
+			return undefined
+		}
+
+		let startLine = previous ? previous.endLine + 1 : 1
+		while (startLine < keyLine && content.slice(lineStarts[startLine - 1], lineStarts[startLine]).trim() === "") {
+			startLine++
+		}
+
+		sections.push({ identifier: range.identifier, type: range.type, startLine, endLine })
+	}
+
+	return sections
+}
+
+function keyName(pair: Pair): string {
+	const key = pair.key as { value?: unknown } | null
+	return String(key && typeof key === "object" && "value" in key ? key.value : key)
+}
+
+function pairRange(pair: Pair): { start: number; end: number } | undefined {
+	const keyRange = (pair.key as Node | null)?.range
+	if (!keyRange) {
+		return undefined
+	}
+	const valueRange = (pair.value as Node | null)?.range
+	return { start: keyRange[0], end: valueRange ? valueRange[1] : keyRange[1] }
+}
+
+function childRanges(value: unknown, type: NodeRange["type"], prefix: string): NodeRange[] {
+	if (!isMap(value)) {
+		return []
+	}
+	return value.items.flatMap((pair) => {
+		const range = pairRange(pair)
+		return range ? [{ identifier: `${prefix}${keyName(pair)}`, type, ...range }] : []
+	})
+}
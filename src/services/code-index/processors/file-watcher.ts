@@ -10,7 +10,7 @@ import { createHash } from "crypto"
 import { RooIgnoreController } from "../../../core/ignore/RooIgnoreController"
 import { v5 as uuidv5 } from "uuid"
 import { Ignore } from "ignore"
-import { scannerExtensions } from "../shared/supported-extensions"
+import { getFileLanguage, isIndexableFile, scannerExtensions } from "../shared/supported-extensions"
 import {
 	IFileWatcher,
 	FileProcessingResult,
@@ -20,6 +20,7 @@ import {
 	BatchProcessingSummary,
 } from "../interfaces"
 import { codeParser } from "./parser"
+import { getBlockKind } from "./symbols"
 import { CacheManager } from "../cache-manager"
 import { generateNormalizedAbsolutePath, generateRelativeFilePath } from "../shared/get-relative-path"
 import { isPathInIgnoredDirectory } from "../../glob/ignore-utils"
@@ -81,6 +82,7 @@ export class FileWatcher implements IFileWatcher {
 		ignoreInstance?: Ignore,
 		ignoreController?: RooIgnoreController,
 		batchSegmentThreshold?: number,
+		private readonly indexedExtensions: string[] = scannerExtensions,
 	) {
 		this.ignoreController = ignoreController || new RooIgnoreController(workspacePath)
 		if (ignoreInstance) {
@@ -109,7 +111,7 @@ export class FileWatcher implements IFileWatcher {
 		// Create file watcher
 		const filePattern = new vscode.RelativePattern(
 			this.workspacePath,
-			`**/*{${scannerExtensions.map((e) => e.substring(1)).join(",")}}`,
+			`**/*{${this.indexedExtensions.map((e) => e.substring(1)).join(",")}}`,
 		)
 		this.fileWatcher = vscode.workspace.createFileSystemWatcher(filePattern)
 
@@ -521,6 +523,15 @@ export class FileWatcher implements IFileWatcher {
 				}
 			}
 
+			// Check if the file type is indexed (e.g. documentation types can be turned off)
+			if (!isIndexableFile(filePath, this.indexedExtensions)) {
+				return {
+					path: filePath,
+					status: "skipped" as const,
+					reason: "File type is not indexed",
+				}
+			}
+
 			// Check if file should be ignored
 			if (
 				!this.ignoreController.validateAccess(filePath) ||
@@ -581,6 +592,9 @@ export class FileWatcher implements IFileWatcher {
 							codeChunk: block.content,
 							startLine: block.start_line,
 							endLine: block.end_line,
+							language: getFileLanguage(block.file_path),
+							kind: getBlockKind(block),
+							...(block.symbols && { symbols: block.symbols }),
 						},
 					}
 				})
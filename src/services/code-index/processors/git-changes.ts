@@ -0,0 +1,102 @@
+import { execFile } from "child_process"
+import * as path from "path"
+import { promisify } from "util"
+
+const execFileAsync = promisify(execFile)
+
+// Branch switches in large monorepos can touch tens of thousands of paths
+const GIT_MAX_BUFFER_BYTES = 64 * 1024 * 1024
+
+// Adding or editing these files can change which paths are indexed, so they require a full scan
+const IGNORE_FILE_NAMES = new Set([".gitignore", ".rooignore"])
+
+/**
+ * Files that differ between a commit and the working tree, as absolute paths
+ */
+export interface GitFileChanges {
+	changed: string[]
+	deleted: string[]
+}
+
+async function git(cwd: string, args: string[]): Promise<string> {
+	const { stdout } = await execFileAsync("git", args, { cwd, maxBuffer: GIT_MAX_BUFFER_BYTES })
+	return stdout
+}
+
+/**
+ * Splits NUL-separated git output (from `-z`) into entries
+ */
+function splitNul(output: string): string[] {
+	return output.split("\0").filter((entry) => entry.length > 0)
+}
+
+/**
+ * Gets the commit checked out in the given directory
+ * @returns The HEAD commit hash, or undefined if git is unavailable or the directory is not in a repository
+ */
+export async function getHeadCommit(cwd: string): Promise<string | undefined> {
+	try {
+		return (await git(cwd, ["rev-parse", "--verify", "HEAD"])).trim() || undefined
+	} catch {
+		return undefined
+	}
+}
+
+/**
+ * Lists files with uncommitted changes, including untracked files that are not ignored
+ * @returns Absolute paths, or undefined if git is unavailable
+ */
+export async function getUncommittedFiles(cwd: string): Promise<string[] | undefined> {
+	try {
+		const [modified, untracked] = await Promise.all([
+			git(cwd, ["diff", "--name-only", "--no-renames", "--relative", "-z", "HEAD", "--"]),
+			git(cwd, ["ls-files", "--others", "--exclude-standard", "-z"]),
+		])
+		return [...splitNul(modified), ...splitNul(untracked)].map((file) => path.resolve(cwd, file))
+	} catch {
+		return undefined
+	}
+}
+
+/**
+ * Lists the files that differ between a commit and the current working tree.
+ * Paths are limited to `cwd`, which may be a subdirectory of the repository.
+ * @param cwd Workspace directory
+ * @param commit Commit the index was last synchronized with
+ * @returns The changes, or undefined when git cannot answer reliably (git missing, commit
+ * no longer exists after a rebase or gc, or an ignore file changed) and a full scan is needed
+ */
+export async function getChangedFilesSince(cwd: string, commit: string): Promise<GitFileChanges | undefined> {
+	try {
+		await git(cwd, ["cat-file", "-e", `${commit}^{commit}`])
+
+		// Renames are reported as a deletion plus an addition, which is exactly how they are re-indexed
+		const [diff, untracked] = await Promise.all([
+			git(cwd, ["diff", "--name-status", "--no-renames", "--relative", "-z", commit, "--"]),
+			git(cwd, ["ls-files", "--others", "--exclude-standard", "-z"]),
+		])
+
+		const changed: string[] = []
+		const deleted: string[] = []
+		const entries = splitNul(diff)
+		for (let i = 0; i + 1 < entries.length; i += 2) {
+			const status = entries[i]
+			const file = entries[i + 1]
+			if (IGNORE_FILE_NAMES.has(path.basename(file))) {
+				return undefined
+			}
+			;(status === "D" ? deleted : changed).push(path.resolve(cwd, file))
+		}
+
+		for (const file of splitNul(untracked)) {
+			if (IGNORE_FILE_NAMES.has(path.basename(file))) {
+				return undefined
+			}
+			changed.push(path.resolve(cwd, file))
+		}
+
+		return { changed, deleted }
+	} catch {
+		return undefined
+	}
+}
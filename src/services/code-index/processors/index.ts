@@ -1,3 +1,4 @@
 export * from "./parser"
 export * from "./scanner"
 export * from "./file-watcher"
+export * from "./git-changes"
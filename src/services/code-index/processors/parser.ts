@@ -4,6 +4,8 @@ import * as path from "path"
 import { Node } from "web-tree-sitter"
 import { LanguageParser, loadRequiredLanguageParsers } from "../../tree-sitter/languageParser"
 import { parseMarkdown } from "../../tree-sitter/markdownParser"
+import { findConfigSections } from "./config-sections"
+import { attachSymbols, extractSymbols } from "./symbols"
 import { ICodeParser, CodeBlock } from "../interfaces"
 import { scannerExtensions, shouldUseFallbackChunking } from "../shared/supported-extensions"
 import { MAX_BLOCK_CHARS, MIN_BLOCK_CHARS, MIN_CHUNK_REMAINDER_CHARS, MAX_CHARS_TOLERANCE_FACTOR } from "../constants"
@@ -97,10 +99,22 @@ export class CodeParser implements ICodeParser {
 		const seenSegmentHashes = new Set<string>()
 
 		// Handle markdown files specially
-		if (ext === "md" || ext === "markdown") {
+		if (ext === "md" || ext === "markdown" || ext === "mdx") {
 			return this.parseMarkdownContent(filePath, content, fileHash, seenSegmentHashes)
 		}
 
+		// YAML and JSON are split by key; OpenAPI specs by path and schema
+		if (ext === "yaml" || ext === "yml" || ext === "json") {
+			const configBlocks = this.parseConfigContent(filePath, content, fileHash, seenSegmentHashes)
+			if (configBlocks) {
+				return configBlocks
+			}
+			// Unparseable YAML has no tree-sitter parser; JSON (e.g. with comments) still does
+			if (ext !== "json") {
+				return this._performFallbackChunking(filePath, content, fileHash, seenSegmentHashes)
+			}
+		}
+
 		// Check if this extension should use fallback chunking
 		if (shouldUseFallbackChunking(`.${ext}`)) {
 			return this._performFallbackChunking(filePath, content, fileHash, seenSegmentHashes)
@@ -226,6 +240,14 @@ export class CodeParser implements ICodeParser {
 			// Nodes smaller than minBlockChars are ignored
 		}
 
+		// Definitions and uses feed the symbol graph behind find_references
+		try {
+			attachSymbols(results, extractSymbols(tree!.rootNode, captures))
+		} catch (error) {
+			// The blocks are still worth indexing without symbols
+			console.warn(`Failed to extract symbols from ${filePath}:`, error)
+		}
+
 		return results
 	}
 
@@ -406,9 +428,9 @@ export class CodeParser implements ICodeParser {
 	}
 
 	/**
-	 * Helper method to process markdown content sections with consistent chunking logic
+	 * Helper method to process markdown and config file sections with consistent chunking logic
 	 */
-	private processMarkdownSection(
+	private processTextSection(
 		lines: string[],
 		filePath: string,
 		fileHash: string,
@@ -477,7 +499,7 @@ export class CodeParser implements ICodeParser {
 
 		if (markdownCaptures.length === 0) {
 			// No headers found, process entire content
-			return this.processMarkdownSection(lines, filePath, fileHash, "markdown_content", seenSegmentHashes, 1)
+			return this.processTextSection(lines, filePath, fileHash, "markdown_content", seenSegmentHashes, 1)
 		}
 
 		const results: CodeBlock[] = []
@@ -488,7 +510,7 @@ export class CodeParser implements ICodeParser {
 			const firstHeaderLine = markdownCaptures[0].node.startPosition.row
 			if (firstHeaderLine > 0) {
 				const preHeaderLines = lines.slice(0, firstHeaderLine)
-				const preHeaderBlocks = this.processMarkdownSection(
+				const preHeaderBlocks = this.processTextSection(
 					preHeaderLines,
 					filePath,
 					fileHash,
@@ -518,7 +540,7 @@ export class CodeParser implements ICodeParser {
 			const headerLevel = headerMatch ? parseInt(headerMatch[1]) : 1
 			const headerText = nameCapture.node.text
 
-			const sectionBlocks = this.processMarkdownSection(
+			const sectionBlocks = this.processTextSection(
 				sectionLines,
 				filePath,
 				fileHash,
@@ -535,7 +557,7 @@ export class CodeParser implements ICodeParser {
 		// Process any remaining content after the last header section
 		if (lastProcessedLine < lines.length) {
 			const remainingLines = lines.slice(lastProcessedLine)
-			const remainingBlocks = this.processMarkdownSection(
+			const remainingBlocks = this.processTextSection(
 				remainingLines,
 				filePath,
 				fileHash,
@@ -548,6 +570,35 @@ export class CodeParser implements ICodeParser {
 
 		return results
 	}
+
+	/**
+	 * Splits YAML/JSON content into one block per top-level key, or per path and schema for OpenAPI specs
+	 * @returns The blocks, or undefined if the content could not be split by keys
+	 */
+	private parseConfigContent(
+		filePath: string,
+		content: string,
+		fileHash: string,
+		seenSegmentHashes: Set<string>,
+	): CodeBlock[] | undefined {
+		const sections = findConfigSections(content, MIN_BLOCK_CHARS)
+		if (!sections || sections.length === 0) {
+			return undefined
+		}
+
+		const lines = content.split("\n")
+		return sections.flatMap((section) =>
+			this.processTextSection(
+				lines.slice(section.startLine - 1, section.endLine),
+				filePath,
+				fileHash,
+				section.type,
+				seenSegmentHashes,
+				section.startLine,
+				section.identifier,
+			),
+		)
+	}
 }
 
 // Export a singleton instance for convenience
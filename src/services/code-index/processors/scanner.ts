@@ -2,10 +2,9 @@ import { listFiles } from "../../glob/list-files"
 import { Ignore } from "ignore"
 import { RooIgnoreController } from "../../../core/ignore/RooIgnoreController"
 import { stat } from "fs/promises"
-import * as path from "path"
 import { generateNormalizedAbsolutePath, generateRelativeFilePath } from "../shared/get-relative-path"
 import { getWorkspacePathForContext } from "../../../utils/path"
-import { scannerExtensions } from "../shared/supported-extensions"
+import { getFileLanguage, isIndexableFile, scannerExtensions } from "../shared/supported-extensions"
 import * as vscode from "vscode"
 import { CodeBlock, ICodeParser, IEmbedder, IVectorStore, IDirectoryScanner } from "../interfaces"
 import { createHash } from "crypto"
@@ -13,6 +12,7 @@ import { v5 as uuidv5 } from "uuid"
 import pLimit from "p-limit"
 import { Mutex } from "async-mutex"
 import { CacheManager } from "../cache-manager"
+import { getBlockKind } from "./symbols"
 import { t } from "../../../i18n"
 import {
 	QDRANT_CODE_BLOCK_NAMESPACE,
@@ -41,6 +41,7 @@ export class DirectoryScanner implements IDirectoryScanner {
 		private readonly cacheManager: CacheManager,
 		private readonly ignoreInstance: Ignore,
 		batchSegmentThreshold?: number,
+		private readonly indexedExtensions: string[] = scannerExtensions,
 	) {
 		// Get the configurable batch size from VSCode settings, fallback to default
 		// If not provided in constructor, try to get from VSCode settings
@@ -83,6 +84,56 @@ export class DirectoryScanner implements IDirectoryScanner {
 		// Filter out directories (marked with trailing '/')
 		const filePaths = allPaths.filter((p) => !p.endsWith("/"))
 
+		const supportedPaths = await this.filterSupportedPaths(directoryPath, scanWorkspace, filePaths)
+
+		// Every cached file that is no longer found is removed from the index
+		return this.scanFiles(supportedPaths, undefined, scanWorkspace, onError, onBlocksIndexed, onFileParsed, signal)
+	}
+
+	/**
+	 * Re-indexes only the given files instead of walking the whole directory.
+	 * Used when git can tell which files changed since the index was last synchronized.
+	 * @param directory The workspace directory the paths belong to
+	 * @param changedPaths Absolute paths of added or modified files
+	 * @param deletedPaths Absolute paths of deleted files
+	 * @returns Processing stats in the same shape as scanDirectory
+	 */
+	public async scanChangedFiles(
+		directory: string,
+		changedPaths: string[],
+		deletedPaths: string[],
+		onError?: (error: Error) => void,
+		onBlocksIndexed?: (indexedCount: number) => void,
+		onFileParsed?: (fileBlockCount: number) => void,
+		signal?: AbortSignal,
+	): Promise<{ stats: { processed: number; skipped: number }; totalBlockCount: number }> {
+		const scanWorkspace = getWorkspacePathForContext(directory)
+
+		const supportedPaths = await this.filterSupportedPaths(directory, scanWorkspace, changedPaths)
+
+		// Changed files that are now filtered out (too large, ignored) are removed like deleted ones
+		const cachedHashes = this.cacheManager.getAllHashes()
+		const removalCandidates = [...deletedPaths, ...changedPaths].filter((filePath) => filePath in cachedHashes)
+
+		return this.scanFiles(
+			supportedPaths,
+			removalCandidates,
+			scanWorkspace,
+			onError,
+			onBlocksIndexed,
+			onFileParsed,
+			signal,
+		)
+	}
+
+	/**
+	 * Applies .rooignore, excluded directories, ignore patterns and supported extensions to the candidate files
+	 */
+	private async filterSupportedPaths(
+		directoryPath: string,
+		scanWorkspace: string,
+		filePaths: string[],
+	): Promise<string[]> {
 		// Initialize RooIgnoreController if not provided
 		const ignoreController = new RooIgnoreController(directoryPath)
 
@@ -93,7 +144,6 @@ export class DirectoryScanner implements IDirectoryScanner {
 
 		// Filter by supported extensions, ignore patterns, and excluded directories
 		const supportedPaths = allowedPaths.filter((filePath) => {
-			const ext = path.extname(filePath).toLowerCase()
 			const relativeFilePath = generateRelativeFilePath(filePath, scanWorkspace)
 
 			// Check if file is in an ignored directory using the shared helper
@@ -102,9 +152,26 @@ export class DirectoryScanner implements IDirectoryScanner {
 				return false
 			}
 
-			return scannerExtensions.includes(ext) && !this.ignoreInstance.ignores(relativeFilePath)
+			return isIndexableFile(filePath, this.indexedExtensions) && !this.ignoreInstance.ignores(relativeFilePath)
 		})
 
+		return supportedPaths
+	}
+
+	/**
+	 * Parses, embeds and stores the given files, skipping those whose hash matches the cache,
+	 * then removes index entries for candidates that were not processed.
+	 * @param removalCandidates Cached paths to remove if they were not processed; all cached paths when undefined
+	 */
+	private async scanFiles(
+		supportedPaths: string[],
+		removalCandidates: string[] | undefined,
+		scanWorkspace: string,
+		onError?: (error: Error) => void,
+		onBlocksIndexed?: (indexedCount: number) => void,
+		onFileParsed?: (fileBlockCount: number) => void,
+		signal?: AbortSignal,
+	): Promise<{ stats: { processed: number; skipped: number }; totalBlockCount: number }> {
 		// Initialize tracking variables
 		const processedFiles = new Set<string>()
 		let processedCount = 0
@@ -331,7 +398,7 @@ export class DirectoryScanner implements IDirectoryScanner {
 
 		// Handle deleted files
 		const oldHashes = this.cacheManager.getAllHashes()
-		for (const cachedFilePath of Object.keys(oldHashes)) {
+		for (const cachedFilePath of removalCandidates ?? Object.keys(oldHashes)) {
 			if (!processedFiles.has(cachedFilePath)) {
 				// File was deleted or is no longer supported/indexed
 				if (this.qdrantClient) {
@@ -461,6 +528,9 @@ export class DirectoryScanner implements IDirectoryScanner {
 							startLine: block.start_line,
 							endLine: block.end_line,
 							segmentHash: block.segmentHash,
+							language: getFileLanguage(block.file_path),
+							kind: getBlockKind(block),
+							...(block.symbols && { symbols: block.symbols }),
 						},
 					}
 				})
@@ -0,0 +1,202 @@
+import { Node, QueryCapture } from "web-tree-sitter"
+import { BlockSymbols, CodeBlock, SymbolDefinition, SymbolReference } from "../interfaces"
+
+// Leaf nodes that name something, e.g. identifier, type_identifier, field_identifier, property_identifier
+const IDENTIFIER_NODE_TYPE = /(^|_)identifier$|^constant$|^name$/
+const SYMBOL_NAME = /^[A-Za-z_$][\w$]*$/
+const CALL_NODE_TYPE = /call|invocation/
+// Definition captures that describe file structure rather than something worth looking up
+const IGNORED_DEFINITION_KINDS = new Set(["import", "package"])
+
+interface ScopedDefinition extends SymbolDefinition {
+	startIndex: number // Character range of the whole definition
+	endIndex: number
+	receiver?: string
+}
+
+/**
+ * Extracts definitions and identifier uses from a parsed file.
+ *
+ * Definitions come from the language's tree-sitter query captures, so they cover the same
+ * functions, methods and types that become code blocks. References are all other identifiers,
+ * flagged when they are the callee of a call and attributed to the innermost enclosing definition.
+ * Names are not resolved across files; lookups match them by name.
+ *
+ * @param root Root node of the parsed file
+ * @param captures Captures of the language's definition query
+ * @returns Symbols of the whole file
+ */
+export function extractSymbols(root: Node, captures: QueryCapture[]): BlockSymbols {
+	const definitionsByName = new Map<number, ScopedDefinition>() // Keyed by the start of the name node
+
+	for (const { name: captureName, node } of captures) {
+		if (!captureName.includes("definition")) {
+			continue
+		}
+		const kind = captureName.split(".").pop()!
+		if (IGNORED_DEFINITION_KINDS.has(kind)) {
+			continue
+		}
+
+		// Queries either capture the name of a definition or the whole definition node
+		const isNameNode = node.childCount === 0 && IDENTIFIER_NODE_TYPE.test(node.type)
+		const definitionNode = isNameNode ? node.parent : node
+		const nameNode = isNameNode ? node : findNameNode(node)
+		if (!definitionNode || !nameNode || !SYMBOL_NAME.test(nameNode.text)) {
+			continue
+		}
+		if (definitionsByName.has(nameNode.startIndex)) {
+			continue
+		}
+
+		definitionsByName.set(nameNode.startIndex, {
+			name: nameNode.text,
+			kind: kind === "definition" ? "symbol" : refineKind(kind, nameNode),
+			line: nameNode.startPosition.row + 1,
+			startIndex: definitionNode.startIndex,
+			endIndex: definitionNode.endIndex,
+			receiver: receiverType(definitionNode),
+		})
+	}
+
+	const scoped = Array.from(definitionsByName.values())
+	const innermostDefinition = (startIndex: number, endIndex: number, exclude?: ScopedDefinition) => {
+		let innermost: ScopedDefinition | undefined
+		for (const definition of scoped) {
+			if (definition === exclude || definition.startIndex > startIndex || definition.endIndex < endIndex) {
+				continue
+			}
+			if (!innermost || definition.endIndex - definition.startIndex < innermost.endIndex - innermost.startIndex) {
+				innermost = definition
+			}
+		}
+		return innermost
+	}
+
+	const definitions: SymbolDefinition[] = scoped.map((definition) => {
+		const container =
+			definition.receiver ?? innermostDefinition(definition.startIndex, definition.endIndex, definition)?.name
+		return {
+			name: definition.name,
+			kind: definition.kind,
+			...(container && { container }),
+			line: definition.line,
+		}
+	})
+
+	const references: SymbolReference[] = []
+	const seen = new Set<string>()
+	const cursor = root.walk()
+	let visiting = true
+	while (visiting) {
+		const node = cursor.currentNode
+		if (
+			node.childCount === 0 &&
+			IDENTIFIER_NODE_TYPE.test(node.type) &&
+			!definitionsByName.has(node.startIndex) &&
+			SYMBOL_NAME.test(node.text)
+		) {
+			const line = node.startPosition.row + 1
+			const key = `${node.text}:${line}`
+			if (!seen.has(key)) {
+				seen.add(key)
+				const caller = innermostDefinition(node.startIndex, node.endIndex)?.name
+				references.push({ name: node.text, line, isCall: isCallee(node), ...(caller && { caller }) })
+			}
+		}
+
+		if (cursor.gotoFirstChild()) {
+			continue
+		}
+		while (!cursor.gotoNextSibling()) {
+			if (!cursor.gotoParent()) {
+				visiting = false
+				break
+			}
+		}
+	}
+	cursor.delete()
+
+	return { definitions, references }
+}
+
+/**
+ * Stores each symbol with the smallest block of the file that contains its line
+ * @param blocks Code blocks of one file
+ * @param symbols Symbols of the same file
+ */
+export function attachSymbols(blocks: CodeBlock[], symbols: BlockSymbols): void {
+	const bySize = [...blocks].sort((a, b) => a.end_line - a.start_line - (b.end_line - b.start_line))
+	const symbolsFor = (line: number) => {
+		const block = bySize.find((candidate) => candidate.start_line <= line && line <= candidate.end_line)
+		if (block && !block.symbols) {
+			block.symbols = { definitions: [], references: [] }
+		}
+		return block?.symbols
+	}
+
+	for (const definition of symbols.definitions) {
+		symbolsFor(definition.line)?.definitions.push(definition)
+	}
+	for (const reference of symbols.references) {
+		symbolsFor(reference.line)?.references.push(reference)
+	}
+}
+
+/**
+ * Gets the kind a block is filtered by in codebase_search, e.g. "function" or "class"
+ * @param block Code block with its attached symbols
+ * @returns Kind of the first definition in the block, or its node type when it defines nothing
+ */
+export function getBlockKind(block: CodeBlock): string {
+	const [first] = [...(block.symbols?.definitions ?? [])].sort((a, b) => a.line - b.line)
+	return first?.kind ?? block.type
+}
+
+function findNameNode(node: Node, depth = 0): Node | null {
+	const name = node.childForFieldName("name")
+	if (name || depth > 0) {
+		return name
+	}
+	// Some declarations wrap a single spec that carries the name, e.g. Go's type_declaration
+	for (const child of node.namedChildren) {
+		const found = child && findNameNode(child, depth + 1)
+		if (found) {
+			return found
+		}
+	}
+	return null
+}
+
+function refineKind(kind: string, nameNode: Node): string {
+	const typeNode = kind === "type" ? nameNode.parent?.childForFieldName("type") : null
+	return typeNode && /^(struct|interface)_type$/.test(typeNode.type) ? typeNode.type.replace(/_type$/, "") : kind
+}
+
+// The type a Go-style method is declared on, e.g. "Repository" for `func (r *Repository) FindByID()`
+function receiverType(definitionNode: Node): string | undefined {
+	const receiver = definitionNode.childForFieldName("receiver")
+	return receiver?.text.match(/([A-Za-z_]\w*)(?:\[[^\]]*\])?\s*\)\s*$/)?.[1]
+}
+
+// Whether the identifier is the function being called rather than an argument or receiver
+function isCallee(node: Node): boolean {
+	let current = node
+	for (let depth = 0; depth < 3 && current.parent; depth++) {
+		const parent = current.parent
+		if (CALL_NODE_TYPE.test(parent.type)) {
+			const callee =
+				parent.childForFieldName("name") ??
+				parent.childForFieldName("function") ??
+				parent.childForFieldName("method") ??
+				parent.firstNamedChild
+			return callee?.id === current.id
+		}
+		// Only the last part of a member access such as repo.FindByID is the callee
+		if (parent.lastNamedChild?.id !== current.id) {
+			return false
+		}
+		current = parent
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+import type { MockedFunction } from "vitest"
+
+import { CohereReranker } from "../cohere"
+import { VoyageReranker } from "../voyage"
+import { createReranker } from "../index"
+
+// Mock fetch
+global.fetch = vitest.fn() as MockedFunction<typeof fetch>
+
+// Mock i18n
+vitest.mock("../../../../i18n", () => ({
+	t: (key: string, params?: Record<string, any>) => (params ? `${key} ${JSON.stringify(params)}` : key),
+}))
+
+const jsonResponse = (body: unknown) =>
+	({
+		ok: true,
+		status: 200,
+		json: () => Promise.resolve(body),
+	}) as Response
+
+describe("hosted rerankers", () => {
+	const mockFetch = global.fetch as MockedFunction<typeof fetch>
+
+	beforeEach(() => {
+		vitest.clearAllMocks()
+	})
+
+	it("maps Cohere relevance scores back to document order", async () => {
+		mockFetch.mockResolvedValue(
+			jsonResponse({
+				results: [
+					{ index: 1, relevance_score: 0.9 },
+					{ index: 0, relevance_score: 0.2 },
+				],
+			}),
+		)
+
+		const scores = await new CohereReranker("key", "rerank-v3.5").rerank("query", ["a", "b"])
+
+		expect(scores).toEqual([0.2, 0.9])
+		expect(mockFetch).toHaveBeenCalledWith(
+			"https://api.cohere.com/v2/rerank",
+			expect.objectContaining({
+				method: "POST",
+				headers: expect.objectContaining({ Authorization: "Bearer key" }),
+				body: JSON.stringify({ model: "rerank-v3.5", query: "query", documents: ["a", "b"] }),
+			}),
+		)
+	})
+
+	it("reads Voyage scores from the data array and ranks missing documents last", async () => {
+		mockFetch.mockResolvedValue(jsonResponse({ data: [{ index: 0, relevance_score: 0.7 }] }))
+
+		const scores = await new VoyageReranker("key", "rerank-2").rerank("query", ["a", "b"])
+
+		expect(scores).toEqual([0.7, Number.NEGATIVE_INFINITY])
+		expect(mockFetch.mock.calls[0][0]).toBe("https://api.voyageai.com/v1/rerank")
+	})
+
+	it("throws on a failed request", async () => {
+		mockFetch.mockResolvedValue({
+			ok: false,
+			status: 401,
+			statusText: "Unauthorized",
+			text: () => Promise.resolve("invalid api key"),
+		} as Response)
+
+		await expect(new CohereReranker("bad", "rerank-v3.5").rerank("query", ["a"])).rejects.toThrow(
+			"embeddings:reranker.requestFailed",
+		)
+	})
+
+	it("throws on a response without scores", async () => {
+		mockFetch.mockResolvedValue(jsonResponse({}))
+
+		await expect(new VoyageReranker("key", "rerank-2").rerank("query", ["a"])).rejects.toThrow(
+			"embeddings:reranker.invalidResponse",
+		)
+	})
+
+	it("skips the request when there is nothing to rerank", async () => {
+		expect(await new CohereReranker("key", "rerank-v3.5").rerank("query", [])).toEqual([])
+		expect(mockFetch).not.toHaveBeenCalled()
+	})
+})
+
+describe("createReranker", () => {
+	it("creates the reranker for the configured provider", () => {
+		expect(createReranker({ provider: "cohere", modelId: "m", apiKey: "k", candidates: 50 })).toBeInstanceOf(
+			CohereReranker,
+		)
+		expect(createReranker({ provider: "voyage", modelId: "m", apiKey: "k", candidates: 50 })).toBeInstanceOf(
+			VoyageReranker,
+		)
+	})
+
+	it("requires an API key for hosted providers", () => {
+		expect(() => createReranker({ provider: "cohere", modelId: "m", candidates: 50 })).toThrow(
+			"embeddings:reranker.apiKeyMissing",
+		)
+	})
+})
@@ -0,0 +1,39 @@
+import { IReranker, RerankerInfo } from "../interfaces"
+import { fetchRelevanceScores } from "./http"
+
+const COHERE_RERANK_URL = "https://api.cohere.com/v2/rerank"
+
+/**
+ * Implements the IReranker interface using the Cohere Rerank API.
+ */
+export class CohereReranker implements IReranker {
+	/**
+	 * Creates a new Cohere reranker
+	 * @param apiKey Cohere API key
+	 * @param modelId Rerank model to use, e.g. "rerank-v3.5"
+	 */
+	constructor(
+		private readonly apiKey: string,
+		private readonly modelId: string,
+	) {}
+
+	async rerank(query: string, documents: string[]): Promise<number[]> {
+		if (documents.length === 0) {
+			return []
+		}
+
+		return fetchRelevanceScores(
+			"cohere",
+			COHERE_RERANK_URL,
+			this.apiKey,
+			{ model: this.modelId, query, documents },
+			"results",
+		)
+	}
+
+	get rerankerInfo(): RerankerInfo {
+		return {
+			name: "cohere",
+		}
+	}
+}
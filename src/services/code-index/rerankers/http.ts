@@ -0,0 +1,77 @@
+import type { RerankerProvider } from "@roo-code/types"
+
+import { t } from "../../../i18n"
+
+// Reranking sits on the interactive search path, so fail fast and fall back to the unreranked results
+const RERANK_REQUEST_TIMEOUT_MS = 15000
+
+interface RelevanceResult {
+	index: number
+	relevance_score: number
+}
+
+/**
+ * Posts a rerank request to a hosted provider and maps the returned relevance
+ * scores back onto the input order of the documents.
+ * @param provider Provider name used in error messages
+ * @param url Rerank endpoint
+ * @param apiKey Bearer token for the provider
+ * @param body Request body; must contain the documents being scored
+ * @param resultsKey Response property holding the `{ index, relevance_score }` entries
+ */
+export async function fetchRelevanceScores(
+	provider: RerankerProvider,
+	url: string,
+	apiKey: string,
+	body: { documents: string[] } & Record<string, unknown>,
+	resultsKey: string,
+): Promise<number[]> {
+	const controller = new AbortController()
+	const timeoutId = setTimeout(() => controller.abort(), RERANK_REQUEST_TIMEOUT_MS)
+
+	try {
+		const response = await fetch(url, {
+			method: "POST",
+			headers: {
+				"Content-Type": "application/json",
+				Authorization: `Bearer ${apiKey}`,
+			},
+			body: JSON.stringify(body),
+			signal: controller.signal,
+		})
+
+		if (!response.ok) {
+			let errorBody = t("embeddings:reranker.couldNotReadErrorBody")
+			try {
+				errorBody = await response.text()
+			} catch {
+				// Ignore error reading body
+			}
+			throw new Error(
+				t("embeddings:reranker.requestFailed", {
+					provider,
+					status: response.status,
+					statusText: response.statusText,
+					errorBody,
+				}),
+			)
+		}
+
+		const data = await response.json()
+		const results: RelevanceResult[] | undefined = data?.[resultsKey]
+		if (!Array.isArray(results)) {
+			throw new Error(t("embeddings:reranker.invalidResponse", { provider }))
+		}
+
+		// Documents the provider did not return are ranked last
+		const scores = new Array<number>(body.documents.length).fill(Number.NEGATIVE_INFINITY)
+		for (const result of results) {
+			if (typeof result?.index === "number" && typeof result.relevance_score === "number") {
+				scores[result.index] = result.relevance_score
+			}
+		}
+		return scores
+	} finally {
+		clearTimeout(timeoutId)
+	}
+}
@@ -0,0 +1,24 @@
+import { RerankerOptions } from "../interfaces/config"
+import { IReranker } from "../interfaces"
+import { t } from "../../../i18n"
+import { CohereReranker } from "./cohere"
+import { VoyageReranker } from "./voyage"
+
+/**
+ * Creates the reranker described by the given options.
+ * @param options Reranker settings from the code index configuration
+ * @throws Error if a hosted provider is selected without an API key
+ */
+export function createReranker(options: RerankerOptions): IReranker {
+	switch (options.provider) {
+		case "cohere":
+		case "voyage": {
+			if (!options.apiKey) {
+				throw new Error(t("embeddings:reranker.apiKeyMissing", { provider: options.provider }))
+			}
+			return options.provider === "cohere"
+				? new CohereReranker(options.apiKey, options.modelId)
+				: new VoyageReranker(options.apiKey, options.modelId)
+		}
+	}
+}
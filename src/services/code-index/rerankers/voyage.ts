@@ -0,0 +1,39 @@
+import { IReranker, RerankerInfo } from "../interfaces"
+import { fetchRelevanceScores } from "./http"
+
+const VOYAGE_RERANK_URL = "https://api.voyageai.com/v1/rerank"
+
+/**
+ * Implements the IReranker interface using the Voyage AI rerank API.
+ */
+export class VoyageReranker implements IReranker {
+	/**
+	 * Creates a new Voyage reranker
+	 * @param apiKey Voyage AI API key
+	 * @param modelId Rerank model to use, e.g. "rerank-2"
+	 */
+	constructor(
+		private readonly apiKey: string,
+		private readonly modelId: string,
+	) {}
+
+	async rerank(query: string, documents: string[]): Promise<number[]> {
+		if (documents.length === 0) {
+			return []
+		}
+
+		return fetchRelevanceScores(
+			"voyage",
+			VOYAGE_RERANK_URL,
+			this.apiKey,
+			{ model: this.modelId, query, documents, truncation: true },
+			"data",
+		)
+	}
+
+	get rerankerInfo(): RerankerInfo {
+		return {
+			name: "voyage",
+		}
+	}
+}
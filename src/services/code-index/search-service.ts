@@ -1,9 +1,15 @@
 import * as path from "path"
-import { VectorStoreSearchResult } from "./interfaces"
+import { IReranker, SearchFilters, VectorStoreSearchResult } from "./interfaces"
 import { IEmbedder } from "./interfaces/embedder"
 import { IVectorStore } from "./interfaces/vector-store"
 import { CodeIndexConfigManager } from "./config-manager"
 import { CodeIndexStateManager } from "./state-manager"
+import { KeywordIndex } from "./keyword-index"
+import { fuseSearchResults } from "./hybrid-search"
+import { createReranker } from "./rerankers"
+import { normalizeLanguage } from "./shared/supported-extensions"
+import { createPathMatcher, splitPathPattern } from "./vector-store/path-filters"
+import { PATH_PATTERN_CANDIDATE_FACTOR } from "./constants"
 import { TelemetryService } from "@roo-code/telemetry"
 import { TelemetryEventName } from "@roo-code/types"
 
@@ -11,28 +17,41 @@ import { TelemetryEventName } from "@roo-code/types"
  * Service responsible for searching the code index.
  */
 export class CodeIndexSearchService {
+	// Reranker settings can change without restarting the index, so the instance is rebuilt on demand
+	private reranker?: { key: string; instance: IReranker }
+
 	constructor(
 		private readonly configManager: CodeIndexConfigManager,
 		private readonly stateManager: CodeIndexStateManager,
 		private readonly embedder: IEmbedder,
 		private readonly vectorStore: IVectorStore,
+		private readonly keywordIndex?: KeywordIndex,
 	) {}
 
 	/**
 	 * Searches the code index for relevant content.
 	 * @param query The search query
 	 * @param limit Maximum number of results to return
-	 * @param directoryPrefix Optional directory path to filter results by
+	 * @param directoryPrefix Optional directory path or gitignore-style glob to filter results by
+	 * @param filters Optional language and definition kind filters
 	 * @returns Array of search results
 	 * @throws Error if the service is not properly configured or ready
 	 */
-	public async searchIndex(query: string, directoryPrefix?: string): Promise<VectorStoreSearchResult[]> {
+	public async searchIndex(
+		query: string,
+		directoryPrefix?: string,
+		filters?: SearchFilters,
+	): Promise<VectorStoreSearchResult[]> {
 		if (!this.configManager.isFeatureEnabled || !this.configManager.isFeatureConfigured) {
 			throw new Error("Code index feature is disabled or not configured.")
 		}
 
 		const minScore = this.configManager.currentSearchMinScore
 		const maxResults = this.configManager.currentSearchMaxResults
+		const keywordWeight = this.configManager.currentHybridSearchWeight
+		const rerankerOptions = this.configManager.currentRerankerOptions
+		// Rerank a wider candidate pool than the final result count
+		const candidateCount = rerankerOptions ? Math.max(maxResults, rerankerOptions.candidates) : maxResults
 
 		const currentState = this.stateManager.getCurrentStatus().systemStatus
 		if (currentState !== "Indexed" && currentState !== "Indexing") {
@@ -48,15 +67,41 @@ export class CodeIndexSearchService {
 				throw new Error("Failed to generate embedding for query.")
 			}
 
-			// Handle directory prefix
+			// Stores filter on the literal directory of a glob; the glob itself is matched below
+			const { directoryPrefix: literalPrefix, pattern } = splitPathPattern(directoryPrefix)
 			let normalizedPrefix: string | undefined = undefined
-			if (directoryPrefix) {
-				normalizedPrefix = path.normalize(directoryPrefix)
+			if (literalPrefix) {
+				normalizedPrefix = path.normalize(literalPrefix)
 			}
+			const matchesPattern = pattern ? createPathMatcher(pattern) : undefined
+			const storeFilters = normalizeSearchFilters(filters)
+			const fetchCount = matchesPattern ? candidateCount * PATH_PATTERN_CANDIDATE_FACTOR : candidateCount
+			const inPattern = (result: VectorStoreSearchResult) =>
+				!matchesPattern || (!!result.payload && matchesPattern(result.payload.filePath))
 
 			// Perform search
-			const results = await this.vectorStore.search(vector, normalizedPrefix, minScore, maxResults)
-			return results
+			const vectorResults = await this.vectorStore.search(
+				vector,
+				normalizedPrefix,
+				minScore,
+				fetchCount,
+				storeFilters,
+			)
+			let results = vectorResults.filter(inPattern).slice(0, candidateCount)
+
+			// Blend in exact keyword matches when hybrid search is enabled and the keyword index has data
+			if (this.keywordIndex && keywordWeight > 0 && this.keywordIndex.size > 0) {
+				const keywordResults = this.keywordIndex
+					.search(query, normalizedPrefix, fetchCount, storeFilters)
+					.filter(inPattern)
+				results = fuseSearchResults(results, keywordResults, keywordWeight, candidateCount)
+			}
+
+			if (!rerankerOptions) {
+				return results.slice(0, maxResults)
+			}
+
+			return await this.rerankResults(query, results, maxResults)
 		} catch (error) {
 			console.error("[CodeIndexSearchService] Error during search:", error)
 			this.stateManager.setSystemState("Error", `Search failed: ${(error as Error).message}`)
@@ -71,4 +116,54 @@ export class CodeIndexSearchService {
 			throw error // Re-throw the error after setting state
 		}
 	}
+
+	/**
+	 * Re-scores the candidates with the configured reranker and keeps the best ones.
+	 * Reranking only refines the order, so on failure the original ranking is returned.
+	 */
+	private async rerankResults(
+		query: string,
+		results: VectorStoreSearchResult[],
+		maxResults: number,
+	): Promise<VectorStoreSearchResult[]> {
+		const documents = results.map((result) => result.payload?.codeChunk ?? "")
+
+		try {
+			const scores = await this.getReranker().rerank(query, documents)
+			return results
+				.map((result, index) => ({ ...result, score: scores[index] }))
+				.sort((a, b) => b.score - a.score)
+				.slice(0, maxResults)
+		} catch (error) {
+			console.warn("[CodeIndexSearchService] Reranking failed, using unreranked results:", error)
+
+			TelemetryService.instance.captureEvent(TelemetryEventName.CODE_INDEX_ERROR, {
+				error: (error as Error).message,
+				stack: (error as Error).stack,
+				location: "rerankResults",
+			})
+
+			return results.slice(0, maxResults)
+		}
+	}
+
+	private getReranker(): IReranker {
+		const options = this.configManager.currentRerankerOptions!
+		const key = `${options.provider}:${options.modelId}:${options.apiKey ?? ""}`
+
+		if (this.reranker?.key !== key) {
+			this.reranker = { key, instance: createReranker(options) }
+		}
+
+		return this.reranker.instance
+	}
+}
+
+/**
+ * Maps filter values to the form they are indexed in, dropping empty ones
+ */
+function normalizeSearchFilters(filters?: SearchFilters): SearchFilters | undefined {
+	const language = filters?.language?.trim() ? normalizeLanguage(filters.language) : undefined
+	const kind = filters?.kind?.trim().toLowerCase() || undefined
+	return language || kind ? { ...(language && { language }), ...(kind && { kind }) } : undefined
 }
@@ -21,11 +21,15 @@ import { VercelAiGatewayEmbedder } from "./embedders/vercel-ai-gateway"
 import { BedrockEmbedder } from "./embedders/bedrock"
 import { OpenRouterEmbedder } from "./embedders/openrouter"
 import { QdrantVectorStore } from "./vector-store/qdrant-client"
+import { HybridVectorStore } from "./vector-store/hybrid-vector-store"
+import { KeywordIndex } from "./keyword-index"
+import { SymbolGraph } from "./symbol-graph"
 import { codeParser, DirectoryScanner, FileWatcher } from "./processors"
 import { ICodeParser, IEmbedder, IFileWatcher, IVectorStore } from "./interfaces"
 import { CodeIndexConfigManager } from "./config-manager"
 import { CacheManager } from "./cache-manager"
 import { BATCH_SEGMENT_THRESHOLD } from "./constants"
+import { getIndexedExtensions } from "./shared/supported-extensions"
 
 /**
  * Factory class responsible for creating and configuring code indexing service dependencies.
@@ -192,7 +196,15 @@ export class CodeIndexServiceFactory {
 			// In test environment, vscode.workspace might not be available
 			batchSize = BATCH_SEGMENT_THRESHOLD
 		}
-		return new DirectoryScanner(embedder, vectorStore, parser, this.cacheManager, ignoreInstance, batchSize)
+		return new DirectoryScanner(
+			embedder,
+			vectorStore,
+			parser,
+			this.cacheManager,
+			ignoreInstance,
+			batchSize,
+			getIndexedExtensions(this.configManager.currentDocumentTypes),
+		)
 	}
 
 	/**
@@ -225,6 +237,7 @@ export class CodeIndexServiceFactory {
 			ignoreInstance,
 			rooIgnoreController,
 			batchSize,
+			getIndexedExtensions(this.configManager.currentDocumentTypes),
 		)
 	}
 
@@ -240,6 +253,8 @@ export class CodeIndexServiceFactory {
 	): {
 		embedder: IEmbedder
 		vectorStore: IVectorStore
+		keywordIndex: KeywordIndex
+		symbolGraph: SymbolGraph
 		parser: ICodeParser
 		scanner: DirectoryScanner
 		fileWatcher: IFileWatcher
@@ -249,8 +264,17 @@ export class CodeIndexServiceFactory {
 		}
 
 		const embedder = this.createEmbedder()
-		const vectorStore = this.createVectorStore()
+		// Writes go through the hybrid store so the keyword index and symbol graph stay in sync with the vector index
+		const keywordIndex = new KeywordIndex(context, this.workspacePath)
+		const symbolGraph = new SymbolGraph(context, this.workspacePath)
 		const parser = codeParser
+		const vectorStore = new HybridVectorStore(
+			this.createVectorStore(),
+			keywordIndex,
+			symbolGraph,
+			parser,
+			this.workspacePath,
+		)
 		const scanner = this.createDirectoryScanner(embedder, vectorStore, parser, ignoreInstance)
 		const fileWatcher = this.createFileWatcher(
 			context,
@@ -264,6 +288,8 @@ export class CodeIndexServiceFactory {
 		return {
 			embedder,
 			vectorStore,
+			keywordIndex,
+			symbolGraph,
 			parser,
 			scanner,
 			fileWatcher,
@@ -1,7 +1,100 @@
+import * as path from "path"
+import type { CodebaseIndexDocumentType } from "@roo-code/types"
+
 import { extensions as allExtensions } from "../../tree-sitter"
 
-// Include all extensions including markdown for the scanner
-export const scannerExtensions = allExtensions
+/**
+ * Documentation and config file extensions, grouped by the document type setting that controls them.
+ * YAML and MDX have no tree-sitter parser; the code parser chunks them itself.
+ */
+export const documentExtensions: Record<CodebaseIndexDocumentType, string[]> = {
+	markdown: [".md", ".markdown", ".mdx"],
+	config: [".json", ".yaml", ".yml", ".toml"],
+}
+
+// Include all extensions including markdown and config files for the scanner
+export const scannerExtensions = Array.from(new Set([...allExtensions, ...Object.values(documentExtensions).flat()]))
+
+/**
+ * Generated files that match a config extension but have no value for search
+ */
+export const excludedDocumentFileNames = ["package-lock.json", "pnpm-lock.yaml"]
+
+/**
+ * Gets the extensions to index when only some documentation/config types are enabled
+ * @param documentTypes Enabled document types
+ * @returns Scanner extensions without those of disabled document types
+ */
+export function getIndexedExtensions(documentTypes: CodebaseIndexDocumentType[]): string[] {
+	const disabled = (Object.keys(documentExtensions) as CodebaseIndexDocumentType[])
+		.filter((type) => !documentTypes.includes(type))
+		.flatMap((type) => documentExtensions[type])
+	return scannerExtensions.filter((ext) => !disabled.includes(ext))
+}
+
+/**
+ * Checks whether a file should be indexed based on its extension and name
+ * @param filePath Path of the file
+ * @param extensions Extensions to index
+ */
+export function isIndexableFile(filePath: string, extensions: string[] = scannerExtensions): boolean {
+	const ext = path.extname(filePath).toLowerCase()
+	return extensions.includes(ext) && !excludedDocumentFileNames.includes(path.basename(filePath))
+}
+
+/**
+ * Language names for extensions that don't already spell out their language.
+ * Other files use their extension without the dot, e.g. "go" or "java".
+ */
+const extensionLanguages: Record<string, string> = {
+	".js": "javascript",
+	".jsx": "javascript",
+	".ts": "typescript",
+	".tsx": "typescript",
+	".py": "python",
+	".rs": "rust",
+	".h": "c",
+	".hpp": "cpp",
+	".cs": "csharp",
+	".rb": "ruby",
+	".sol": "solidity",
+	".kt": "kotlin",
+	".kts": "kotlin",
+	".ex": "elixir",
+	".exs": "elixir",
+	".el": "elisp",
+	".htm": "html",
+	".md": "markdown",
+	".mdx": "markdown",
+	".yml": "yaml",
+	".ml": "ocaml",
+	".mli": "ocaml",
+	".erl": "erlang",
+	".hrl": "erlang",
+	".tf": "terraform",
+	".tfvars": "terraform",
+	".hcl": "terraform",
+}
+
+/**
+ * Gets the language a file is indexed under, used by the codebase_search language filter
+ * @param filePath Path of the file
+ * @returns Language name such as "typescript" or "go"
+ */
+export function getFileLanguage(filePath: string): string {
+	const ext = path.extname(filePath).toLowerCase()
+	return extensionLanguages[ext] ?? ext.slice(1)
+}
+
+/**
+ * Maps a language filter to the name files are indexed under, so extensions
+ * ("ts", ".py") work as well as language names ("TypeScript")
+ * @param language Language name or extension
+ */
+export function normalizeLanguage(language: string): string {
+	const name = language.trim().toLowerCase().replace(/^\./, "")
+	return extensionLanguages[`.${name}`] ?? name
+}
 
 /**
  * Extensions that should always use fallback chunking instead of tree-sitter parsing.
@@ -22,6 +115,11 @@ export const fallbackExtensions = [
 	".vb", // Visual Basic .NET - no dedicated WASM parser
 	".scala", // Scala - uses fallback chunking instead of Lua query workaround
 	".swift", // Swift - uses fallback chunking due to parser instability
+	".erl", // Erlang - no WASM parser in tree-sitter-wasms
+	".hrl",
+	".tf", // Terraform / HCL - no WASM parser in tree-sitter-wasms
+	".tfvars",
+	".hcl",
 ]
 
 /**
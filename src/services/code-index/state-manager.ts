@@ -8,6 +8,7 @@ export class CodeIndexStateManager {
 	private _processedItems: number = 0
 	private _totalItems: number = 0
 	private _currentItemUnit: string = "blocks"
+	private _lastError: { message: string; timestamp: number } | undefined
 	private _progressEmitter = new vscode.EventEmitter<ReturnType<typeof this.getCurrentStatus>>()
 
 	// --- Public API ---
@@ -18,6 +19,13 @@ export class CodeIndexStateManager {
 		return this._systemStatus
 	}
 
+	/**
+	 * The most recent error, kept after the state moves on so it can still be reported
+	 */
+	public get lastError(): { message: string; timestamp: number } | undefined {
+		return this._lastError
+	}
+
 	public getCurrentStatus() {
 		return {
 			systemStatus: this._systemStatus,
@@ -51,6 +59,10 @@ export class CodeIndexStateManager {
 				if (newState === "Error" && message === undefined) this._statusMessage = "An error occurred."
 			}
 
+			if (newState === "Error") {
+				this._lastError = { message: this._statusMessage, timestamp: Date.now() }
+			}
+
 			this._progressEmitter.fire(this.getCurrentStatus())
 		}
 	}
@@ -0,0 +1,250 @@
+import * as vscode from "vscode"
+import { createHash } from "crypto"
+import debounce from "lodash.debounce"
+
+import { safeWriteJson } from "../../utils/safeWriteJson"
+import { BlockSymbols, SymbolLookupResult } from "./interfaces"
+import { PointStruct, StoredIndexState } from "./interfaces/vector-store"
+import { matchesDirectoryPrefix, normalizeDirectoryPrefix, toPathKey } from "./vector-store/path-filters"
+
+// Bump when the stored format or the extracted symbols change, so existing indexes are rebuilt
+const SYMBOL_GRAPH_VERSION = 1
+
+interface StoredSymbolGraph {
+	version: number
+	entries: Record<string, { filePath: string; symbols: BlockSymbols }>
+}
+
+interface SymbolEntry {
+	filePath: string
+	pathKey: string
+	symbols: BlockSymbols
+}
+
+/**
+ * Splits a symbol such as `Repository.FindByID` or `Repository::find_by_id` into its
+ * container and name.
+ */
+export function parseSymbolName(symbol: string): { name: string; container?: string } {
+	const parts = symbol.trim().split(/\.|::|#|->/)
+	const name = parts.pop() ?? ""
+	const container = parts.pop()
+	return container ? { name, container } : { name }
+}
+
+/**
+ * Lightweight symbol graph kept alongside the vector index. It maps names to the blocks that
+ * define or use them, so callers of a function can be found without searching file contents.
+ *
+ * Entries are keyed by point id, like the keyword index, and are persisted as-is.
+ */
+export class SymbolGraph {
+	private readonly graphPath: vscode.Uri
+	private readonly entries = new Map<string, SymbolEntry>()
+	private readonly definitionIds = new Map<string, Set<string>>()
+	private readonly referenceIds = new Map<string, Set<string>>()
+	private readonly idsByPath = new Map<string, Set<string>>()
+	private readonly _debouncedSave: () => void
+
+	/**
+	 * Creates a new symbol graph
+	 * @param context VS Code extension context
+	 * @param workspacePath Path to the workspace
+	 */
+	constructor(
+		context: vscode.ExtensionContext,
+		private readonly workspacePath: string,
+	) {
+		this.graphPath = vscode.Uri.joinPath(
+			context.globalStorageUri,
+			`roo-index-symbols-${createHash("sha256").update(workspacePath).digest("hex")}.json`,
+		)
+		this._debouncedSave = debounce(async () => {
+			await this._performSave()
+		}, 1500)
+	}
+
+	/**
+	 * Loads the persisted graph, starting empty if none can be loaded
+	 * @returns Whether a graph of the current version was loaded. When it wasn't, files that are
+	 * already indexed have no symbols until the graph is rebuilt or they are indexed again.
+	 */
+	async initialize(): Promise<StoredIndexState> {
+		this.reset()
+		let stored: Partial<StoredSymbolGraph>
+		try {
+			const data = await vscode.workspace.fs.readFile(this.graphPath)
+			stored = JSON.parse(data.toString())
+		} catch {
+			// No symbol graph yet
+			return "missing"
+		}
+
+		if (typeof stored?.version !== "number" || typeof stored.entries !== "object" || !stored.entries) {
+			return "missing"
+		}
+		if (stored.version !== SYMBOL_GRAPH_VERSION) {
+			return "outdated"
+		}
+		for (const [id, { filePath, symbols }] of Object.entries(stored.entries)) {
+			this.addEntry(id, filePath, symbols)
+		}
+		return "loaded"
+	}
+
+	get size(): number {
+		return this.entries.size
+	}
+
+	/**
+	 * Adds or replaces the symbols of the given points. Points without symbols only
+	 * remove what was stored for them before.
+	 */
+	upsert(points: PointStruct[]): void {
+		for (const point of points) {
+			this.removeEntry(point.id)
+			const symbols = point.payload?.symbols as BlockSymbols | undefined
+			if (symbols && typeof point.payload?.filePath === "string") {
+				this.addEntry(point.id, point.payload.filePath, symbols)
+			}
+		}
+		this._debouncedSave()
+	}
+
+	/**
+	 * Removes every entry that belongs to one of the given files
+	 */
+	deleteByFilePaths(filePaths: string[]): void {
+		for (const filePath of filePaths) {
+			const ids = this.idsByPath.get(toPathKey(filePath, this.workspacePath))
+			for (const id of Array.from(ids ?? [])) {
+				this.removeEntry(id)
+			}
+		}
+		this._debouncedSave()
+	}
+
+	/**
+	 * Removes all entries and persists the empty graph
+	 */
+	async clear(): Promise<void> {
+		this.reset()
+		await this._performSave()
+	}
+
+	/**
+	 * Finds the definitions and references of a symbol
+	 * @param symbol Symbol name, optionally qualified with its container (e.g. `Repository.FindByID`)
+	 * @param directoryPrefix Optional directory prefix to filter results
+	 * @returns Definitions and references ordered by file and line
+	 */
+	findReferences(symbol: string, directoryPrefix?: string): SymbolLookupResult {
+		const { name, container } = parseSymbolName(symbol)
+		const prefix = normalizeDirectoryPrefix(directoryPrefix)
+		const inScope = (entry: SymbolEntry) => !prefix || matchesDirectoryPrefix(entry.pathKey, prefix)
+		const result: SymbolLookupResult = { definitions: [], references: [] }
+
+		for (const id of this.definitionIds.get(name) ?? []) {
+			const entry = this.entries.get(id)!
+			if (!inScope(entry)) {
+				continue
+			}
+			for (const definition of entry.symbols.definitions) {
+				if (definition.name === name && (!container || definition.container === container)) {
+					result.definitions.push({ ...definition, filePath: entry.filePath })
+				}
+			}
+		}
+
+		// References are not type-resolved, so a qualified lookup still returns every use of the name
+		for (const id of this.referenceIds.get(name) ?? []) {
+			const entry = this.entries.get(id)!
+			if (!inScope(entry)) {
+				continue
+			}
+			for (const reference of entry.symbols.references) {
+				if (reference.name === name) {
+					result.references.push({ ...reference, filePath: entry.filePath })
+				}
+			}
+		}
+
+		const byLocation = (a: { filePath: string; line: number }, b: { filePath: string; line: number }) =>
+			a.filePath.localeCompare(b.filePath) || a.line - b.line
+		result.definitions.sort(byLocation)
+		result.references.sort(byLocation)
+		return result
+	}
+
+	/**
+	 * Flushes any pending debounced writes to disk immediately
+	 */
+	async flush(): Promise<void> {
+		await this._performSave()
+	}
+
+	private addEntry(id: string, filePath: string, symbols: BlockSymbols): void {
+		const pathKey = toPathKey(filePath, this.workspacePath)
+		this.entries.set(id, { filePath, pathKey, symbols })
+
+		for (const definition of symbols.definitions) {
+			addToIndex(this.definitionIds, definition.name, id)
+		}
+		for (const reference of symbols.references) {
+			addToIndex(this.referenceIds, reference.name, id)
+		}
+		addToIndex(this.idsByPath, pathKey, id)
+	}
+
+	private removeEntry(id: string): void {
+		const entry = this.entries.get(id)
+		if (!entry) {
+			return
+		}
+
+		for (const definition of entry.symbols.definitions) {
+			removeFromIndex(this.definitionIds, definition.name, id)
+		}
+		for (const reference of entry.symbols.references) {
+			removeFromIndex(this.referenceIds, reference.name, id)
+		}
+		removeFromIndex(this.idsByPath, entry.pathKey, id)
+		this.entries.delete(id)
+	}
+
+	private reset(): void {
+		this.entries.clear()
+		this.definitionIds.clear()
+		this.referenceIds.clear()
+		this.idsByPath.clear()
+	}
+
+	private async _performSave(): Promise<void> {
+		try {
+			const stored: StoredSymbolGraph = { version: SYMBOL_GRAPH_VERSION, entries: {} }
+			for (const [id, { filePath, symbols }] of this.entries) {
+				stored.entries[id] = { filePath, symbols }
+			}
+			await safeWriteJson(this.graphPath.fsPath, stored)
+		} catch (error) {
+			console.error("[SymbolGraph] Failed to save symbol graph:", error)
+		}
+	}
+}
+
+function addToIndex(index: Map<string, Set<string>>, key: string, id: string): void {
+	let ids = index.get(key)
+	if (!ids) {
+		ids = new Set()
+		index.set(key, ids)
+	}
+	ids.add(id)
+}
+
+function removeFromIndex(index: Map<string, Set<string>>, key: string, id: string): void {
+	const ids = index.get(key)
+	ids?.delete(id)
+	if (ids?.size === 0) {
+		index.delete(key)
+	}
+}
@@ -0,0 +1,154 @@
+import type { Mock } from "vitest"
+import * as vscode from "vscode"
+
+import { HybridVectorStore } from "../hybrid-vector-store"
+import type { ICodeParser } from "../../interfaces"
+import type { IVectorStore } from "../../interfaces/vector-store"
+import type { KeywordIndex } from "../../keyword-index"
+import type { SymbolGraph } from "../../symbol-graph"
+
+vi.mock("vscode", () => ({
+	Uri: {
+		file: vi.fn((fsPath: string) => ({ fsPath })),
+	},
+	workspace: {
+		fs: {
+			readFile: vi.fn(),
+		},
+	},
+}))
+
+describe("HybridVectorStore", () => {
+	let vectorStore: {
+		initialize: ReturnType<typeof vi.fn>
+		clearCollection: ReturnType<typeof vi.fn>
+		listPoints: ReturnType<typeof vi.fn>
+	}
+	let keywordIndex: {
+		initialize: ReturnType<typeof vi.fn>
+		clear: ReturnType<typeof vi.fn>
+		upsert: ReturnType<typeof vi.fn>
+		flush: ReturnType<typeof vi.fn>
+	}
+	let symbolGraph: {
+		initialize: ReturnType<typeof vi.fn>
+		clear: ReturnType<typeof vi.fn>
+		upsert: ReturnType<typeof vi.fn>
+		flush: ReturnType<typeof vi.fn>
+	}
+	let codeParser: { parseFile: ReturnType<typeof vi.fn> }
+	let store: HybridVectorStore
+
+	const payload = { filePath: "src/a.ts", codeChunk: "const a = 1", startLine: 1, endLine: 1 }
+	const symbols = { definitions: [{ name: "a", kind: "variable", line: 1 }], references: [] }
+
+	beforeEach(() => {
+		vectorStore = {
+			initialize: vi.fn().mockResolvedValue(false),
+			clearCollection: vi.fn(),
+			listPoints: vi.fn().mockResolvedValue([{ id: "a", payload }]),
+		}
+		keywordIndex = {
+			initialize: vi.fn().mockResolvedValue("loaded"),
+			clear: vi.fn(),
+			upsert: vi.fn(),
+			flush: vi.fn(),
+		}
+		symbolGraph = {
+			initialize: vi.fn().mockResolvedValue("loaded"),
+			clear: vi.fn(),
+			upsert: vi.fn(),
+			flush: vi.fn(),
+		}
+		codeParser = {
+			parseFile: vi.fn().mockResolvedValue([
+				{ file_path: "/workspace/src/a.ts", content: "const a = 1", start_line: 1, end_line: 1, symbols },
+				{ file_path: "/workspace/src/a.ts", content: "const b = 2", start_line: 2, end_line: 2, symbols },
+			]),
+		}
+		;(vscode.workspace.fs.readFile as Mock).mockResolvedValue(Buffer.from("const a = 1\nconst b = 2\n"))
+		store = new HybridVectorStore(
+			vectorStore as unknown as IVectorStore,
+			keywordIndex as unknown as KeywordIndex,
+			symbolGraph as unknown as SymbolGraph,
+			codeParser as unknown as ICodeParser,
+			"/workspace",
+		)
+	})
+
+	afterEach(() => {
+		vi.restoreAllMocks()
+	})
+
+	it("keeps an existing collection that has a symbol graph", async () => {
+		expect(await store.initialize()).toBe(false)
+		expect(vectorStore.clearCollection).not.toHaveBeenCalled()
+		expect(vectorStore.listPoints).not.toHaveBeenCalled()
+		expect(symbolGraph.clear).not.toHaveBeenCalled()
+	})
+
+	it("rebuilds a missing symbol graph from the indexed files without starting over", async () => {
+		symbolGraph.initialize.mockResolvedValue("missing")
+
+		expect(await store.initialize()).toBe(false)
+		expect(vectorStore.clearCollection).not.toHaveBeenCalled()
+		expect(keywordIndex.clear).not.toHaveBeenCalled()
+		expect(codeParser.parseFile).toHaveBeenCalledWith("/workspace/src/a.ts", {
+			content: "const a = 1\nconst b = 2\n",
+			fileHash: expect.any(String),
+		})
+		// Only blocks that are in the collection get symbols
+		expect(symbolGraph.upsert).toHaveBeenCalledWith([{ id: "a", vector: [], payload: { ...payload, symbols } }])
+		expect(symbolGraph.flush).toHaveBeenCalled()
+	})
+
+	it("skips files that can't be read when rebuilding the symbol graph", async () => {
+		symbolGraph.initialize.mockResolvedValue("missing")
+		;(vscode.workspace.fs.readFile as Mock).mockRejectedValue(new Error("ENOENT"))
+		vi.spyOn(console, "warn").mockImplementation(() => {})
+
+		expect(await store.initialize()).toBe(false)
+		expect(vectorStore.clearCollection).not.toHaveBeenCalled()
+		expect(symbolGraph.upsert).not.toHaveBeenCalled()
+		expect(symbolGraph.flush).toHaveBeenCalled()
+	})
+
+	it("starts over when the symbol graph is of another version", async () => {
+		symbolGraph.initialize.mockResolvedValue("outdated")
+
+		expect(await store.initialize()).toBe(true)
+		expect(vectorStore.clearCollection).toHaveBeenCalled()
+		expect(keywordIndex.clear).toHaveBeenCalled()
+		expect(symbolGraph.clear).toHaveBeenCalled()
+		expect(codeParser.parseFile).not.toHaveBeenCalled()
+	})
+
+	it("restores a missing keyword index from the collection", async () => {
+		keywordIndex.initialize.mockResolvedValue("missing")
+
+		expect(await store.initialize()).toBe(false)
+		expect(vectorStore.clearCollection).not.toHaveBeenCalled()
+		expect(keywordIndex.upsert).toHaveBeenCalledWith([{ id: "a", vector: [], payload }])
+		expect(keywordIndex.flush).toHaveBeenCalled()
+		expect(symbolGraph.clear).not.toHaveBeenCalled()
+		expect(codeParser.parseFile).not.toHaveBeenCalled()
+	})
+
+	it("starts over when the keyword index is of another version", async () => {
+		keywordIndex.initialize.mockResolvedValue("outdated")
+
+		expect(await store.initialize()).toBe(true)
+		expect(vectorStore.clearCollection).toHaveBeenCalled()
+		expect(vectorStore.listPoints).not.toHaveBeenCalled()
+	})
+
+	it("doesn't restore the indexes of a new collection", async () => {
+		vectorStore.initialize.mockResolvedValue(true)
+		keywordIndex.initialize.mockResolvedValue("missing")
+		symbolGraph.initialize.mockResolvedValue("missing")
+
+		expect(await store.initialize()).toBe(true)
+		expect(vectorStore.listPoints).not.toHaveBeenCalled()
+		expect(keywordIndex.clear).toHaveBeenCalled()
+	})
+})
@@ -37,6 +37,7 @@ const mockQdrantClientInstance = {
 	createPayloadIndex: vitest.fn(),
 	upsert: vitest.fn(),
 	query: vitest.fn(),
+	scroll: vitest.fn(),
 	delete: vitest.fn(),
 }
 
@@ -542,18 +543,24 @@ describe("QdrantVectorStore", () => {
 			})
 			expect(mockQdrantClientInstance.deleteCollection).not.toHaveBeenCalled()
 
-			// Verify payload index creation - 'type' field first, then pathSegments
+			// Verify payload index creation - 'type' field first, then the filter fields and pathSegments
 			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledWith(expectedCollectionName, {
 				field_name: "type",
 				field_schema: "keyword",
 			})
+			for (const fieldName of ["language", "kind"]) {
+				expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledWith(expectedCollectionName, {
+					field_name: fieldName,
+					field_schema: "keyword",
+				})
+			}
 			for (let i = 0; i <= 4; i++) {
 				expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledWith(expectedCollectionName, {
 					field_name: `pathSegments.${i}`,
 					field_schema: "keyword",
 				})
 			}
-			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(6)
+			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(8)
 		})
 		it("should not create a new collection if one exists with matching vectorSize and return false", async () => {
 			// Mock getCollection to return existing collection info with matching vector size
@@ -587,7 +594,7 @@ describe("QdrantVectorStore", () => {
 					field_schema: "keyword",
 				})
 			}
-			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(6)
+			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(8)
 		})
 		it("should recreate collection if it exists but vectorSize mismatches and return true", async () => {
 			const differentVectorSize = 768
@@ -644,7 +651,7 @@ describe("QdrantVectorStore", () => {
 					field_schema: "keyword",
 				})
 			}
-			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(6)
+			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(8)
 			;(console.warn as any).mockRestore() // Restore console.warn
 		})
 		it("should log warning for non-404 errors but still create collection", async () => {
@@ -658,7 +665,7 @@ describe("QdrantVectorStore", () => {
 			expect(mockQdrantClientInstance.getCollection).toHaveBeenCalledTimes(1)
 			expect(mockQdrantClientInstance.createCollection).toHaveBeenCalledTimes(1)
 			expect(mockQdrantClientInstance.deleteCollection).not.toHaveBeenCalled()
-			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(6)
+			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(8)
 			expect(console.warn).toHaveBeenCalledWith(
 				expect.stringContaining(`Warning during getCollectionInfo for "${expectedCollectionName}"`),
 				genericError.message,
@@ -705,11 +712,11 @@ describe("QdrantVectorStore", () => {
 			expect(result).toBe(true)
 			expect(mockQdrantClientInstance.createCollection).toHaveBeenCalledTimes(1)
 
-			// Verify all payload index creations were attempted (6: type + 5 pathSegments)
-			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(6)
+			// Verify all payload index creations were attempted (8: type + language + kind + 5 pathSegments)
+			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(8)
 
-			// Verify warnings were logged for each failed index (now 6)
-			expect(console.warn).toHaveBeenCalledTimes(6)
+			// Verify warnings were logged for each failed index (now 8)
+			expect(console.warn).toHaveBeenCalledTimes(8)
 			// Verify warning for 'type' index
 			expect(console.warn).toHaveBeenCalledWith(
 				expect.stringContaining(`Could not create payload index for type`),
@@ -843,7 +850,7 @@ describe("QdrantVectorStore", () => {
 			expect(mockQdrantClientInstance.getCollection).toHaveBeenCalledTimes(2)
 			expect(mockQdrantClientInstance.deleteCollection).toHaveBeenCalledTimes(1)
 			expect(mockQdrantClientInstance.createCollection).toHaveBeenCalledTimes(1)
-			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(6)
+			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(8)
 			;(console.warn as any).mockRestore()
 		})
 
@@ -940,7 +947,7 @@ describe("QdrantVectorStore", () => {
 					on_disk: true,
 				},
 			})
-			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(6)
+			expect(mockQdrantClientInstance.createPayloadIndex).toHaveBeenCalledTimes(8)
 			;(console.warn as any).mockRestore()
 		})
 
@@ -1242,6 +1249,35 @@ describe("QdrantVectorStore", () => {
 		})
 	})
 
+	describe("listPoints", () => {
+		it("should page through the collection without vectors or metadata points", async () => {
+			const payload = (filePath: string) => ({
+				filePath,
+				codeChunk: "code",
+				startLine: 1,
+				endLine: 2,
+				pathSegments: { "0": filePath },
+			})
+			mockQdrantClientInstance.scroll
+				.mockResolvedValueOnce({ points: [{ id: "a", payload: payload("a.ts") }], next_page_offset: "b" })
+				.mockResolvedValueOnce({ points: [{ id: "b", payload: payload("b.ts") }], next_page_offset: null })
+
+			const points = await vectorStore.listPoints()
+
+			expect(points).toEqual([
+				{ id: "a", payload: { filePath: "a.ts", codeChunk: "code", startLine: 1, endLine: 2 } },
+				{ id: "b", payload: { filePath: "b.ts", codeChunk: "code", startLine: 1, endLine: 2 } },
+			])
+			expect(mockQdrantClientInstance.scroll).toHaveBeenCalledTimes(2)
+			expect(mockQdrantClientInstance.scroll.mock.calls[0][1]).toMatchObject({
+				filter: { must_not: [{ key: "type", match: { value: "metadata" } }] },
+				offset: undefined,
+				with_vector: false,
+			})
+			expect(mockQdrantClientInstance.scroll.mock.calls[1][1]).toMatchObject({ offset: "b" })
+		})
+	})
+
 	describe("search", () => {
 		it("should correctly call qdrantClient.query and transform results", async () => {
 			const queryVector = [0.1, 0.2, 0.3]
@@ -1287,7 +1323,7 @@ describe("QdrantVectorStore", () => {
 					exact: false,
 				},
 				with_payload: {
-					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 				},
 			})
 			expect(callArgs.filter).toEqual({
@@ -1326,7 +1362,7 @@ describe("QdrantVectorStore", () => {
 				score_threshold: DEFAULT_SEARCH_MIN_SCORE,
 				limit: DEFAULT_MAX_SEARCH_RESULTS,
 				params: { hnsw_ef: 128, exact: false },
-				with_payload: { include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"] },
+				with_payload: { include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"] },
 			})
 			expect(callArgs2.filter).toEqual({
 				must: [
@@ -1358,7 +1394,7 @@ describe("QdrantVectorStore", () => {
 					exact: false,
 				},
 				with_payload: {
-					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 				},
 			})
 			expect(callArgs3.filter).toEqual({
@@ -1385,7 +1421,7 @@ describe("QdrantVectorStore", () => {
 					exact: false,
 				},
 				with_payload: {
-					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 				},
 			})
 			expect(callArgs4.filter).toEqual({
@@ -1516,7 +1552,7 @@ describe("QdrantVectorStore", () => {
 					exact: false,
 				},
 				with_payload: {
-					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 				},
 			})
 			expect(callArgs5.filter).toEqual({
@@ -1530,6 +1566,37 @@ describe("QdrantVectorStore", () => {
 			})
 		})
 
+		it("should add language and kind conditions to the filter", async () => {
+			const queryVector = [0.1, 0.2, 0.3]
+			mockQdrantClientInstance.query.mockResolvedValue({ points: [] })
+
+			await vectorStore.search(queryVector, "src/api", undefined, undefined, { language: "go", kind: "function" })
+
+			const callArgs = mockQdrantClientInstance.query.mock.calls[0][1]
+			expect(callArgs.filter).toEqual({
+				must: [
+					{ key: "pathSegments.0", match: { value: "src" } },
+					{ key: "pathSegments.1", match: { value: "api" } },
+					{ key: "language", match: { value: "go" } },
+					{ key: "kind", match: { value: "function" } },
+				],
+				must_not: [{ key: "type", match: { value: "metadata" } }],
+			})
+		})
+
+		it("should filter by language without a directory prefix", async () => {
+			const queryVector = [0.1, 0.2, 0.3]
+			mockQdrantClientInstance.query.mockResolvedValue({ points: [] })
+
+			await vectorStore.search(queryVector, undefined, undefined, undefined, { language: "python" })
+
+			const callArgs = mockQdrantClientInstance.query.mock.calls[0][1]
+			expect(callArgs.filter).toEqual({
+				must: [{ key: "language", match: { value: "python" } }],
+				must_not: [{ key: "type", match: { value: "metadata" } }],
+			})
+		})
+
 		it("should handle error scenarios when qdrantClient.query fails", async () => {
 			const queryVector = [0.1, 0.2, 0.3]
 			const queryError = new Error("Query failed")
@@ -1590,7 +1657,7 @@ describe("QdrantVectorStore", () => {
 						exact: false,
 					},
 					with_payload: {
-						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 					},
 				})
 				expect(callArgs7.filter).toEqual({
@@ -1619,7 +1686,7 @@ describe("QdrantVectorStore", () => {
 						exact: false,
 					},
 					with_payload: {
-						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 					},
 				})
 				expect(callArgs6.filter).toEqual({
@@ -1646,7 +1713,7 @@ describe("QdrantVectorStore", () => {
 						exact: false,
 					},
 					with_payload: {
-						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 					},
 				})
 				expect(callArgs8.filter).toEqual({
@@ -1673,7 +1740,7 @@ describe("QdrantVectorStore", () => {
 						exact: false,
 					},
 					with_payload: {
-						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 					},
 				})
 				expect(callArgs9.filter).toEqual({
@@ -1700,7 +1767,7 @@ describe("QdrantVectorStore", () => {
 						exact: false,
 					},
 					with_payload: {
-						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 					},
 				})
 				expect(callArgs10.filter).toEqual({
@@ -1727,7 +1794,7 @@ describe("QdrantVectorStore", () => {
 						exact: false,
 					},
 					with_payload: {
-						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 					},
 				})
 				expect(callArgs11.filter).toEqual({
@@ -1760,7 +1827,7 @@ describe("QdrantVectorStore", () => {
 						exact: false,
 					},
 					with_payload: {
-						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+						include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 					},
 				})
 				expect(callArgs12.filter).toEqual({
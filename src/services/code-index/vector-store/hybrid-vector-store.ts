@@ -0,0 +1,181 @@
+import * as vscode from "vscode"
+import * as path from "path"
+import { createHash } from "crypto"
+import pLimit from "p-limit"
+
+import { IndexedPoint, IVectorStore, PointStruct } from "../interfaces/vector-store"
+import { BlockSymbols, ICodeParser, SearchFilters, VectorStoreSearchResult } from "../interfaces"
+import { PARSING_CONCURRENCY } from "../constants"
+import { KeywordIndex } from "../keyword-index"
+import { SymbolGraph } from "../symbol-graph"
+
+/**
+ * Wraps a vector store so every write is mirrored into the BM25 keyword index and the symbol graph.
+ * Vector search itself is passed through unchanged; fusion happens in the search service.
+ */
+export class HybridVectorStore implements IVectorStore {
+	constructor(
+		private readonly vectorStore: IVectorStore,
+		private readonly keywordIndex: KeywordIndex,
+		private readonly symbolGraph: SymbolGraph,
+		private readonly codeParser: ICodeParser,
+		private readonly workspacePath: string,
+	) {}
+
+	async initialize(): Promise<boolean> {
+		const keywordIndexState = await this.keywordIndex.initialize()
+		const symbolGraphState = await this.symbolGraph.initialize()
+		let collectionCreated = await this.vectorStore.initialize()
+		if (!collectionCreated && (keywordIndexState === "outdated" || symbolGraphState === "outdated")) {
+			// The collection was indexed by another version of the indexes, and unchanged files would never
+			// be indexed again. Start over as if the collection were new so the whole workspace is scanned.
+			await this.vectorStore.clearCollection()
+			collectionCreated = true
+		}
+		if (collectionCreated) {
+			await this.keywordIndex.clear()
+			await this.symbolGraph.clear()
+		} else if (keywordIndexState === "missing" || symbolGraphState === "missing") {
+			// Rebuild only what is missing from the points that are already indexed, without embedding again
+			const points = await this.vectorStore.listPoints()
+			if (keywordIndexState === "missing") {
+				await this.rebuildKeywordIndex(points)
+			}
+			if (symbolGraphState === "missing") {
+				await this.rebuildSymbolGraph(points)
+			}
+		}
+		return collectionCreated
+	}
+
+	/**
+	 * Restores the keyword index from the payloads in the vector store, which are the same
+	 * payloads the keyword index stores
+	 */
+	private async rebuildKeywordIndex(points: IndexedPoint[]): Promise<void> {
+		await this.keywordIndex.clear()
+		this.keywordIndex.upsert(points.map(({ id, payload }) => ({ id, vector: [], payload })))
+		await this.keywordIndex.flush()
+	}
+
+	/**
+	 * Restores the symbol graph by parsing the indexed files again. Symbols are matched to the points
+	 * by their lines and content, so a file that changed since it was indexed only gets symbols for the
+	 * blocks that are unchanged until the next scan indexes it again.
+	 */
+	private async rebuildSymbolGraph(points: IndexedPoint[]): Promise<void> {
+		const pointsByFile = new Map<string, IndexedPoint[]>()
+		for (const point of points) {
+			const filePoints = pointsByFile.get(point.payload.filePath) ?? []
+			filePoints.push(point)
+			pointsByFile.set(point.payload.filePath, filePoints)
+		}
+
+		await this.symbolGraph.clear()
+		const parseLimiter = pLimit(PARSING_CONCURRENCY)
+		await Promise.all(
+			Array.from(pointsByFile, ([filePath, filePoints]) =>
+				parseLimiter(() => this.rebuildFileSymbols(filePath, filePoints)),
+			),
+		)
+		await this.symbolGraph.flush()
+	}
+
+	private async rebuildFileSymbols(filePath: string, filePoints: IndexedPoint[]): Promise<void> {
+		const absolutePath = path.resolve(this.workspacePath, filePath)
+		try {
+			const data = await vscode.workspace.fs.readFile(vscode.Uri.file(absolutePath))
+			const content = Buffer.from(data).toString("utf-8")
+			const fileHash = createHash("sha256").update(content).digest("hex")
+			const blocks = await this.codeParser.parseFile(absolutePath, { content, fileHash })
+
+			const symbolsByBlock = new Map<string, BlockSymbols>()
+			for (const block of blocks) {
+				if (block.symbols) {
+					symbolsByBlock.set(blockKey(block.start_line, block.end_line, block.content), block.symbols)
+				}
+			}
+
+			this.symbolGraph.upsert(
+				filePoints.flatMap(({ id, payload }) => {
+					const symbols = symbolsByBlock.get(blockKey(payload.startLine, payload.endLine, payload.codeChunk))
+					return symbols ? [{ id, vector: [], payload: { ...payload, symbols } }] : []
+				}),
+			)
+		} catch (error) {
+			// Files that were deleted since they were indexed are removed by the next scan
+			console.warn(`[HybridVectorStore] Failed to parse ${filePath} for the symbol graph:`, error)
+		}
+	}
+
+	async upsertPoints(points: PointStruct[]): Promise<void> {
+		// Symbols only live in the graph, not in the vector store payloads
+		const storedPoints = points.map(({ payload, ...point }) => {
+			const { symbols: _symbols, ...rest } = payload ?? {}
+			return { ...point, payload: rest }
+		})
+		await this.vectorStore.upsertPoints(storedPoints)
+		this.keywordIndex.upsert(storedPoints)
+		this.symbolGraph.upsert(points)
+	}
+
+	listPoints(): Promise<IndexedPoint[]> {
+		return this.vectorStore.listPoints()
+	}
+
+	search(
+		queryVector: number[],
+		directoryPrefix?: string,
+		minScore?: number,
+		maxResults?: number,
+		filters?: SearchFilters,
+	): Promise<VectorStoreSearchResult[]> {
+		return this.vectorStore.search(queryVector, directoryPrefix, minScore, maxResults, filters)
+	}
+
+	async deletePointsByFilePath(filePath: string): Promise<void> {
+		await this.vectorStore.deletePointsByFilePath(filePath)
+		this.keywordIndex.deleteByFilePaths([filePath])
+		this.symbolGraph.deleteByFilePaths([filePath])
+	}
+
+	async deletePointsByMultipleFilePaths(filePaths: string[]): Promise<void> {
+		await this.vectorStore.deletePointsByMultipleFilePaths(filePaths)
+		this.keywordIndex.deleteByFilePaths(filePaths)
+		this.symbolGraph.deleteByFilePaths(filePaths)
+	}
+
+	async clearCollection(): Promise<void> {
+		await this.vectorStore.clearCollection()
+		await this.keywordIndex.clear()
+		await this.symbolGraph.clear()
+	}
+
+	async deleteCollection(): Promise<void> {
+		await this.vectorStore.deleteCollection()
+		await this.keywordIndex.clear()
+		await this.symbolGraph.clear()
+	}
+
+	collectionExists(): Promise<boolean> {
+		return this.vectorStore.collectionExists()
+	}
+
+	hasIndexedData(): Promise<boolean> {
+		return this.vectorStore.hasIndexedData()
+	}
+
+	async markIndexingComplete(): Promise<void> {
+		await this.vectorStore.markIndexingComplete()
+		await this.keywordIndex.flush()
+		await this.symbolGraph.flush()
+	}
+
+	markIndexingIncomplete(): Promise<void> {
+		return this.vectorStore.markIndexingIncomplete()
+	}
+}
+
+function blockKey(startLine: number, endLine: number, content: string): string {
+	return `${startLine}:${endLine}:${content}`
+}
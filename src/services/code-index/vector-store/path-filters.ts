@@ -0,0 +1,87 @@
+import * as path from "path"
+import ignore from "ignore"
+
+import type { Payload, SearchFilters } from "../interfaces/vector-store"
+
+/**
+ * Converts a stored or absolute file path into the workspace-relative, forward-slash
+ * form that the SQL-backed vector stores index and filter on.
+ * @param filePath File path as stored in the point payload (relative) or an absolute path
+ * @param workspacePath Root of the workspace the index belongs to
+ */
+export function toPathKey(filePath: string, workspacePath: string): string {
+	const relativePath = path.isAbsolute(filePath) ? path.relative(workspacePath, filePath) : filePath
+	return path.posix.normalize(path.normalize(relativePath).replace(/\\/g, "/"))
+}
+
+/**
+ * Normalizes a user supplied directory filter the same way the Qdrant store does.
+ * @param directoryPrefix Optional directory prefix (e.g. "./src", "src/", ".")
+ * @returns The cleaned forward-slash prefix, or undefined when the whole workspace should be searched
+ */
+export function normalizeDirectoryPrefix(directoryPrefix?: string): string | undefined {
+	if (!directoryPrefix) {
+		return undefined
+	}
+
+	const normalizedPrefix = path.posix.normalize(directoryPrefix.replace(/\\/g, "/"))
+	if (normalizedPrefix === "." || normalizedPrefix === "./") {
+		return undefined
+	}
+
+	const cleanedPrefix = path.posix
+		.normalize(normalizedPrefix.startsWith("./") ? normalizedPrefix.slice(2) : normalizedPrefix)
+		.replace(/\/+$/, "")
+
+	return cleanedPrefix.length > 0 ? cleanedPrefix : undefined
+}
+
+/**
+ * Checks whether a path key lies inside the given directory prefix, matching whole segments only.
+ */
+export function matchesDirectoryPrefix(pathKey: string, prefix: string): boolean {
+	return pathKey === prefix || pathKey.startsWith(`${prefix}/`)
+}
+
+/**
+ * Checks whether a payload has the language and kind asked for. Points indexed before these
+ * fields existed never match.
+ */
+export function matchesSearchFilters(payload: Payload, filters?: SearchFilters): boolean {
+	return (
+		(!filters?.language || payload.language === filters.language) &&
+		(!filters?.kind || payload.kind === filters.kind)
+	)
+}
+
+const GLOB_CHARACTERS = /[*?[{]/
+
+/**
+ * Splits a path filter into the directory the stores can filter on and a glob for the rest.
+ * @param pathFilter Directory (e.g. "src/api") or gitignore-style glob (e.g. "src/api/**", "*.test.ts")
+ * @returns The literal directory before the first wildcard, and the glob if the filter has one
+ */
+export function splitPathPattern(pathFilter?: string): { directoryPrefix?: string; pattern?: string } {
+	if (!pathFilter || !GLOB_CHARACTERS.test(pathFilter)) {
+		return { directoryPrefix: pathFilter }
+	}
+
+	const pattern = pathFilter.replace(/\\/g, "/").replace(/^\.\//, "")
+	const segments = pattern.split("/")
+	const literalSegments = segments.slice(0, segments.findIndex((segment) => GLOB_CHARACTERS.test(segment)))
+	return { directoryPrefix: literalSegments.join("/") || undefined, pattern }
+}
+
+/**
+ * Creates a matcher for a glob returned by splitPathPattern
+ * @param pattern Gitignore-style glob relative to the workspace
+ * @returns Function testing stored file paths against the glob
+ */
+export function createPathMatcher(pattern: string): (filePath: string) => boolean {
+	const matcher = ignore().add(pattern)
+	return (filePath) => {
+		const pathKey = path.posix.normalize(filePath.replace(/\\/g, "/"))
+		// The ignore library throws for paths outside the workspace
+		return ignore.isPathValid(pathKey) && matcher.ignores(pathKey)
+	}
+}
@@ -2,11 +2,14 @@ import { QdrantClient, Schemas } from "@qdrant/js-client-rest"
 import { createHash } from "crypto"
 import * as path from "path"
 import { v5 as uuidv5 } from "uuid"
-import { IVectorStore } from "../interfaces/vector-store"
-import { Payload, VectorStoreSearchResult } from "../interfaces"
+import { IndexedPoint, IVectorStore } from "../interfaces/vector-store"
+import { Payload, SearchFilters, VectorStoreSearchResult } from "../interfaces"
 import { DEFAULT_MAX_SEARCH_RESULTS, DEFAULT_SEARCH_MIN_SCORE, QDRANT_CODE_BLOCK_NAMESPACE } from "../constants"
 import { t } from "../../../i18n"
 
+// Points fetched per request when listing the whole collection
+const LIST_POINTS_PAGE_SIZE = 1000
+
 /**
  * Qdrant implementation of the vector store interface
  */
@@ -312,6 +315,24 @@ export class QdrantVectorStore implements IVectorStore {
 			}
 		}
 
+		// Create indexes for the fields codebase_search can filter on
+		for (const fieldName of ["language", "kind"]) {
+			try {
+				await this.client.createPayloadIndex(this.collectionName, {
+					field_name: fieldName,
+					field_schema: "keyword",
+				})
+			} catch (indexError: any) {
+				const errorMessage = (indexError?.message || "").toLowerCase()
+				if (!errorMessage.includes("already exists")) {
+					console.warn(
+						`[QdrantVectorStore] Could not create payload index for ${fieldName} on ${this.collectionName}. Details:`,
+						indexError?.message || indexError,
+					)
+				}
+			}
+		}
+
 		// Create indexes for pathSegments fields
 		for (let i = 0; i <= 4; i++) {
 			try {
@@ -374,6 +395,42 @@ export class QdrantVectorStore implements IVectorStore {
 		}
 	}
 
+	/**
+	 * Lists the payloads of all indexed code blocks, without their vectors
+	 * @returns Promise resolving to the id and payload of every code block
+	 */
+	async listPoints(): Promise<IndexedPoint[]> {
+		const points: IndexedPoint[] = []
+		let offset: string | number | undefined = undefined
+
+		try {
+			do {
+				const page: Schemas["ScrollResult"] = await this.client.scroll(this.collectionName, {
+					filter: { must_not: [{ key: "type", match: { value: "metadata" } }] },
+					limit: LIST_POINTS_PAGE_SIZE,
+					offset,
+					with_payload: true,
+					with_vector: false,
+				})
+
+				for (const point of page.points) {
+					if (this.isPayloadValid(point.payload)) {
+						const { pathSegments: _pathSegments, ...payload } = point.payload
+						points.push({ id: String(point.id), payload: payload as Payload })
+					}
+				}
+
+				const nextOffset = page.next_page_offset
+				offset = typeof nextOffset === "string" || typeof nextOffset === "number" ? nextOffset : undefined
+			} while (offset !== undefined)
+		} catch (error) {
+			console.error("Failed to list points:", error)
+			throw error
+		}
+
+		return points
+	}
+
 	/**
 	 * Checks if a payload is valid
 	 * @param payload Payload to check
@@ -394,6 +451,7 @@ export class QdrantVectorStore implements IVectorStore {
 	 * @param directoryPrefix Optional directory prefix to filter results
 	 * @param minScore Optional minimum score threshold
 	 * @param maxResults Optional maximum number of results to return
+	 * @param filters Optional language and kind filters
 	 * @returns Promise resolving to search results
 	 */
 	async search(
@@ -401,6 +459,7 @@ export class QdrantVectorStore implements IVectorStore {
 		directoryPrefix?: string,
 		minScore?: number,
 		maxResults?: number,
+		filters?: SearchFilters,
 	): Promise<VectorStoreSearchResult[]> {
 		try {
 			let filter:
@@ -434,6 +493,13 @@ export class QdrantVectorStore implements IVectorStore {
 				}
 			}
 
+			const payloadConditions = (["language", "kind"] as const)
+				.filter((key) => filters?.[key])
+				.map((key) => ({ key, match: { value: filters![key]! } }))
+			if (payloadConditions.length > 0) {
+				filter = { ...filter, must: [...(filter?.must ?? []), ...payloadConditions] }
+			}
+
 			// Always exclude metadata points at query-time to avoid wasting top-k
 			const metadataExclusion = {
 				must_not: [{ key: "type", match: { value: "metadata" } }],
@@ -453,7 +519,7 @@ export class QdrantVectorStore implements IVectorStore {
 					exact: false,
 				},
 				with_payload: {
-					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments"],
+					include: ["filePath", "codeChunk", "startLine", "endLine", "pathSegments", "language", "kind"],
 				},
 			}
 
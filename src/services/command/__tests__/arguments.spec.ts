@@ -0,0 +1,102 @@
+// npx vitest run src/services/command/__tests__/arguments.spec.ts
+
+import {
+	expandCommand,
+	expandCommandInvocations,
+	formatArgumentHint,
+	parseCommandArguments,
+	splitCommandArgs,
+} from "../arguments"
+import type { Command } from "../commands"
+
+describe("parseCommandArguments", () => {
+	it("accepts names and objects", () => {
+		expect(
+			parseCommandArguments([
+				"file",
+				{ name: "severity", description: " Lowest severity ", default: "medium" },
+				{ name: "limit", default: 10 },
+			]),
+		).toEqual([
+			{ name: "file" },
+			{ name: "severity", description: "Lowest severity", default: "medium" },
+			{ name: "limit", default: "10" },
+		])
+	})
+
+	it("skips invalid and duplicate entries", () => {
+		expect(parseCommandArguments(["file", "file", "two words", 42, { description: "no name" }])).toEqual([
+			{ name: "file" },
+		])
+		expect(parseCommandArguments("file")).toBeUndefined()
+		expect(parseCommandArguments([])).toBeUndefined()
+	})
+})
+
+describe("formatArgumentHint", () => {
+	it("marks optional arguments", () => {
+		expect(formatArgumentHint([{ name: "file" }, { name: "severity", default: "medium" }])).toBe(
+			"{file} [severity]",
+		)
+	})
+})
+
+describe("splitCommandArgs", () => {
+	it("splits at whitespace and keeps quoted values together", () => {
+		expect(splitCommandArgs(` src/app.ts  "very high" 'a b' severity="low risk" x`)).toEqual([
+			"src/app.ts",
+			"very high",
+			"a b",
+			"severity=low risk",
+			"x",
+		])
+	})
+})
+
+describe("expandCommand", () => {
+	const command = {
+		content: "Review @/{{file}} for {{ severity }} issues. {{unknown}}",
+		arguments: [{ name: "file" }, { name: "severity", default: "medium" }],
+	}
+
+	it("fills in positional arguments, with extra words going to the last one", () => {
+		expect(expandCommand(command, "src/app.ts security and style")).toEqual({
+			prompt: "Review @/src/app.ts for security and style issues. {{unknown}}",
+			missing: [],
+		})
+	})
+
+	it("fills in named arguments and defaults", () => {
+		expect(expandCommand(command, "file=@/src/app.ts").prompt).toBe(
+			"Review @/src/app.ts for medium issues. {{unknown}}",
+		)
+		expect(expandCommand(command, "severity=low src/app.ts").prompt).toBe(
+			"Review @/src/app.ts for low issues. {{unknown}}",
+		)
+	})
+
+	it("reports missing required arguments", () => {
+		expect(expandCommand(command, "")).toEqual({
+			prompt: "Review {{file}} for medium issues. {{unknown}}",
+			missing: ["file"],
+		})
+	})
+})
+
+describe("expandCommandInvocations", () => {
+	const command: Command = {
+		name: "fix.lint",
+		content: "Fix the lint errors in {{path}}.",
+		source: "project",
+		filePath: "/project/.roo/commands/fix.lint.md",
+		arguments: [{ name: "path" }],
+	}
+
+	it("replaces each invocation with the rest of its line as arguments", () => {
+		expect(expandCommandInvocations("/fix.lint src\nthen /fix.lint test please", command)).toEqual({
+			prompt: "Fix the lint errors in src.\nthen Fix the lint errors in test please.",
+			missing: [],
+		})
+		expect(expandCommandInvocations("/fixXlint src", command).prompt).toBe("/fixXlint src")
+	})
+})
@@ -0,0 +1,162 @@
+import type { Command, CommandArgument } from "./commands"
+
+const ARGUMENT_NAME_REGEX = /^[a-zA-Z_][\w-]*$/
+// A quoted value (optionally after `name=`) or a word
+const ARGUMENT_TOKEN_REGEX = /([\w-]+=)?(?:"([^"]*)"|'([^']*)')|(\S+)/g
+
+/**
+ * Parse the `arguments` frontmatter of a command. Each entry is either a name
+ * or an object with a name, a description and a default value; arguments with
+ * a default are optional. Invalid entries are skipped.
+ *
+ * @example
+ * ```yaml
+ * arguments:
+ *   - file
+ *   - name: severity
+ *     description: Lowest severity to report
+ *     default: medium
+ * ```
+ */
+export function parseCommandArguments(value: unknown): CommandArgument[] | undefined {
+	if (!Array.isArray(value)) {
+		return undefined
+	}
+
+	const args: CommandArgument[] = []
+
+	for (const entry of value) {
+		let arg: CommandArgument
+
+		if (typeof entry === "string") {
+			arg = { name: entry.trim() }
+		} else if (entry && typeof entry === "object" && typeof entry.name === "string") {
+			arg = { name: entry.name.trim() }
+
+			if (typeof entry.description === "string" && entry.description.trim()) {
+				arg.description = entry.description.trim()
+			}
+
+			if (entry.default !== undefined && entry.default !== null) {
+				arg.default = String(entry.default)
+			}
+		} else {
+			continue
+		}
+
+		if (ARGUMENT_NAME_REGEX.test(arg.name) && !args.some(({ name }) => name === arg.name)) {
+			args.push(arg)
+		}
+	}
+
+	return args.length > 0 ? args : undefined
+}
+
+/**
+ * The argument hint shown in the slash command menu, such as
+ * `{file} [severity]` for a required and an optional argument.
+ */
+export function formatArgumentHint(args: CommandArgument[]): string {
+	return args
+		.map(({ name, default: defaultValue }) => (defaultValue === undefined ? `{${name}}` : `[${name}]`))
+		.join(" ")
+}
+
+/**
+ * Split the text after a command into arguments at whitespace. Single or
+ * double quotes keep an argument with spaces together, also after `name=`.
+ */
+export function splitCommandArgs(text: string): string[] {
+	const args: string[] = []
+
+	for (const [, prefix, doubleQuoted, singleQuoted, bare] of text.matchAll(ARGUMENT_TOKEN_REGEX)) {
+		args.push(bare ?? `${prefix ?? ""}${doubleQuoted ?? singleQuoted}`)
+	}
+
+	return args
+}
+
+export interface ExpandedCommand {
+	prompt: string
+	// The required arguments that weren't given
+	missing: string[]
+}
+
+/**
+ * Fill in a command's `{{argument}}` placeholders from the text after the
+ * command. Arguments can be given in order or as `name=value`; words beyond
+ * the last argument are added to it, so it can take free text. A placeholder
+ * written as `@/{{file}}` stays a file mention even if the value already is one,
+ * and the placeholders of missing arguments are kept.
+ */
+export function expandCommand(command: Pick<Command, "content" | "arguments">, argsText: string): ExpandedCommand {
+	const args = command.arguments ?? []
+	const values = new Map<string, string>()
+	const positional: string[] = []
+
+	for (const token of splitCommandArgs(argsText)) {
+		const [, name, value] = token.match(/^([\w-]+)=(.*)$/) ?? []
+
+		if (name !== undefined && args.some((arg) => arg.name === name)) {
+			values.set(name, value)
+		} else {
+			positional.push(token)
+		}
+	}
+
+	const unassigned = args.filter(({ name }) => !values.has(name))
+
+	unassigned.forEach(({ name }, index) => {
+		const tokens = index === unassigned.length - 1 ? positional.splice(0) : positional.splice(0, 1)
+
+		if (tokens.length > 0) {
+			values.set(name, tokens.join(" "))
+		}
+	})
+
+	for (const { name, default: defaultValue } of args) {
+		if (!values.has(name) && defaultValue !== undefined) {
+			values.set(name, defaultValue)
+		}
+	}
+
+	const prompt = command.content.replace(
+		/(@\/)?\{\{\s*([\w-]+)\s*\}\}/g,
+		(match, mentionPrefix: string | undefined, name: string) => {
+			if (!args.some((arg) => arg.name === name)) {
+				return match
+			}
+
+			const value = values.get(name)
+
+			// Keep the placeholder of a missing argument, without a mention of the workspace root.
+			if (value === undefined) {
+				return `{{${name}}}`
+			}
+
+			return mentionPrefix ? `@/${value.replace(/^@\//, "")}` : value
+		},
+	)
+
+	return { prompt, missing: args.filter(({ name }) => !values.has(name)).map(({ name }) => name) }
+}
+
+/**
+ * Replace each invocation of a command that takes arguments in `text` with the
+ * expanded command, taking the rest of the invocation's line as its arguments.
+ */
+export function expandCommandInvocations(text: string, command: Command): ExpandedCommand {
+	const name = command.name.replace(/\./g, "\\.")
+	const missing = new Set<string>()
+
+	const prompt = text.replace(
+		new RegExp(`(^|\\s)\\/${name}(?=\\s|$)([^\\n]*)`, "g"),
+		(_match, prefix: string, argsText: string) => {
+			const expanded = expandCommand(command, argsText)
+			expanded.missing.forEach((arg) => missing.add(arg))
+			return `${prefix}${expanded.prompt}`
+		},
+	)
+
+	return { prompt, missing: [...missing] }
+}
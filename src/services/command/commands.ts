@@ -4,12 +4,23 @@ import { Dirent } from "fs"
 import matter from "gray-matter"
 import { getGlobalRooDirectory, getProjectRooDirectoryForCwd } from "../roo-config"
 import { getBuiltInCommands, getBuiltInCommand } from "./built-in-commands"
+import { formatArgumentHint, parseCommandArguments } from "./arguments"
 
 /**
  * Maximum depth for resolving symlinks to prevent cyclic symlink loops
  */
 const MAX_DEPTH = 5
 
+/**
+ * A named argument of a command, filled into its `{{name}}` placeholders
+ */
+export interface CommandArgument {
+	name: string
+	description?: string
+	/** Value used when the argument isn't given; arguments without one are required */
+	default?: string
+}
+
 export interface Command {
 	name: string
 	content: string
@@ -18,6 +29,7 @@ export interface Command {
 	description?: string
 	argumentHint?: string
 	mode?: string
+	arguments?: CommandArgument[]
 }
 
 /**
@@ -217,6 +229,7 @@ async function tryLoadCommand(
 		let description: string | undefined
 		let argumentHint: string | undefined
 		let mode: string | undefined
+		let commandArguments: CommandArgument[] | undefined
 		let commandContent: string
 
 		try {
@@ -231,12 +244,14 @@ async function tryLoadCommand(
 					? parsed.data["argument-hint"].trim()
 					: undefined
 			mode = typeof parsed.data.mode === "string" && parsed.data.mode.trim() ? parsed.data.mode.trim() : undefined
+			commandArguments = parseCommandArguments(parsed.data.arguments)
 			commandContent = parsed.content.trim()
 		} catch {
 			// If frontmatter parsing fails, treat the entire content as command content
 			description = undefined
 			argumentHint = undefined
 			mode = undefined
+			commandArguments = undefined
 			commandContent = content.trim()
 		}
 
@@ -246,8 +261,9 @@ async function tryLoadCommand(
 			source,
 			filePath: resolvedPath,
 			description,
-			argumentHint,
+			argumentHint: argumentHint ?? (commandArguments && formatArgumentHint(commandArguments)),
 			mode,
+			arguments: commandArguments,
 		}
 	} catch {
 		// Directory doesn't exist or can't be read
@@ -302,6 +318,7 @@ async function scanCommandDirectory(
 				let description: string | undefined
 				let argumentHint: string | undefined
 				let mode: string | undefined
+				let commandArguments: CommandArgument[] | undefined
 				let commandContent: string
 
 				try {
@@ -319,12 +336,14 @@ async function scanCommandDirectory(
 						typeof parsed.data.mode === "string" && parsed.data.mode.trim()
 							? parsed.data.mode.trim()
 							: undefined
+					commandArguments = parseCommandArguments(parsed.data.arguments)
 					commandContent = parsed.content.trim()
 				} catch {
 					// If frontmatter parsing fails, treat the entire content as command content
 					description = undefined
 					argumentHint = undefined
 					mode = undefined
+					commandArguments = undefined
 					commandContent = content.trim()
 				}
 
@@ -336,8 +355,9 @@ async function scanCommandDirectory(
 						source,
 						filePath: resolvedPath,
 						description,
-						argumentHint,
+						argumentHint: argumentHint ?? (commandArguments && formatArgumentHint(commandArguments)),
 						mode,
+						arguments: commandArguments,
 					})
 				}
 			} catch (error) {
@@ -2,6 +2,7 @@ import * as fs from "fs/promises"
 import * as path from "path"
 
 import * as vscode from "vscode"
+import { auth } from "@modelcontextprotocol/sdk/client/auth.js"
 import { Client } from "@modelcontextprotocol/sdk/client/index.js"
 import { StdioClientTransport, getDefaultEnvironment } from "@modelcontextprotocol/sdk/client/stdio.js"
 import { SSEClientTransport } from "@modelcontextprotocol/sdk/client/sse.js"
@@ -9,14 +10,18 @@ import { StreamableHTTPClientTransport } from "@modelcontextprotocol/sdk/client/
 import ReconnectingEventSource from "reconnecting-eventsource"
 import {
 	CallToolResultSchema,
+	EmptyResultSchema,
 	ListResourcesResultSchema,
 	ListResourceTemplatesResultSchema,
 	ListToolsResultSchema,
 	ReadResourceResultSchema,
+	ResourceListChangedNotificationSchema,
+	ResourceUpdatedNotificationSchema,
 } from "@modelcontextprotocol/sdk/types.js"
 import chokidar, { FSWatcher } from "chokidar"
 import delay from "delay"
 import deepEqual from "fast-deep-equal"
+import stringify from "safe-stable-stringify"
 import { z } from "zod"
 
 import type {
@@ -33,6 +38,7 @@ import { t } from "../../i18n"
 import { ClineProvider } from "../../core/webview/ClineProvider"
 
 import { GlobalFileNames } from "../../shared/globalFileNames"
+import { Package } from "../../shared/package"
 
 import { fileExistsAtPath } from "../../utils/fs"
 import { arePathsEqual, getWorkspacePath } from "../../utils/path"
@@ -40,12 +46,20 @@ import { injectVariables } from "../../utils/config"
 import { safeWriteJson } from "../../utils/safeWriteJson"
 import { sanitizeMcpName, toolNamesMatch } from "../../utils/mcp-name"
 
+import { McpOAuthClientProvider } from "./McpOAuthClientProvider"
+
 // Discriminated union for connection states
 export type ConnectedMcpConnection = {
 	type: "connected"
 	server: McpServer
 	client: Client
 	transport: StdioClientTransport | SSEClientTransport | StreamableHTTPClientTransport
+	// The OAuth client of a remote server, see McpOAuthClientProvider
+	authProvider?: McpOAuthClientProvider
+	// The latest contents of the resources subscribed to, by URI
+	resourceCache?: Map<string, McpResourceResponse>
+	// The cached tool results of a server with a toolCache, by tool and arguments
+	toolResultCache?: Map<string, { result: McpToolCallResponse; expiresAt: number }>
 }
 
 export type DisconnectedMcpConnection = {
@@ -57,6 +71,13 @@ export type DisconnectedMcpConnection = {
 
 export type McpConnection = ConnectedMcpConnection | DisconnectedMcpConnection
 
+// A resource that its server reported as changed
+export type McpResourceUpdate = {
+	serverName: string
+	source: "global" | "project"
+	uri: string
+}
+
 // Enum for disable reasons
 export enum DisableReason {
 	MCP_DISABLED = "mcpDisabled",
@@ -70,6 +91,14 @@ const BaseConfigSchema = z.object({
 	alwaysAllow: z.array(z.string()).default([]),
 	watchPaths: z.array(z.string()).optional(), // paths to watch for changes and restart server
 	disabledTools: z.array(z.string()).default([]),
+	// Opt-in cache of tool results: identical calls within `ttl` seconds reuse the
+	// earlier result. `tools` limits it to the listed tools, otherwise all are cached.
+	toolCache: z
+		.object({
+			ttl: z.number().min(1).max(86400),
+			tools: z.array(z.string()).optional(),
+		})
+		.optional(),
 })
 
 // Custom error messages for better user feedback
@@ -161,6 +190,7 @@ export class McpHub {
 	private isProgrammaticUpdate: boolean = false
 	private flagResetTimer?: NodeJS.Timeout
 	private sanitizedNameRegistry: Map<string, string> = new Map()
+	private resourceUpdateListeners: Set<(update: McpResourceUpdate) => void> = new Set()
 	private initializationPromise: Promise<void>
 
 	constructor(provider: ClineProvider) {
@@ -703,6 +733,11 @@ export class McpHub {
 				workspaceFolder: vscode.workspace.workspaceFolders?.[0]?.uri.fsPath ?? "",
 			})) as typeof config
 
+			const authProvider =
+				configInjected.type === "stdio"
+					? undefined
+					: this.createAuthProvider(name, source, configInjected.url, configInjected.headers)
+
 			if (configInjected.type === "stdio") {
 				// On Windows, wrap commands with cmd.exe to handle non-exe executables like npx.ps1
 				// This is necessary for node version managers (fnm, nvm-windows, volta) that implement
@@ -781,6 +816,7 @@ export class McpHub {
 			} else if (configInjected.type === "streamable-http") {
 				// Streamable HTTP connection
 				transport = new StreamableHTTPClientTransport(new URL(configInjected.url), {
+					authProvider,
 					requestInit: {
 						headers: configInjected.headers,
 					},
@@ -807,6 +843,7 @@ export class McpHub {
 			} else if (configInjected.type === "sse") {
 				// SSE connection
 				const sseOptions = {
+					authProvider,
 					requestInit: {
 						headers: configInjected.headers,
 					},
@@ -815,8 +852,12 @@ export class McpHub {
 				const reconnectingEventSourceOptions = {
 					max_retry_time: 5000, // Maximum retry time in milliseconds
 					withCredentials: configInjected.headers?.["Authorization"] ? true : false, // Enable credentials if Authorization header exists
-					fetch: (url: string | URL, init: RequestInit) => {
+					fetch: async (url: string | URL, init: RequestInit) => {
 						const headers = new Headers({ ...(init?.headers || {}), ...(configInjected.headers || {}) })
+						const tokens = await authProvider?.tokens()
+						if (tokens) {
+							headers.set("Authorization", `Bearer ${tokens.access_token}`)
+						}
 						return fetch(url, {
 							...init,
 							headers,
@@ -871,6 +912,7 @@ export class McpHub {
 				},
 				client,
 				transport,
+				authProvider,
 			}
 			this.connections.push(connection)
 
@@ -879,6 +921,8 @@ export class McpHub {
 			connection.server.status = "connected"
 			connection.server.error = ""
 			connection.server.instructions = client.getInstructions()
+			connection.server.authStatus = (await authProvider?.tokens()) ? "authorized" : undefined
+			this.setupResourceNotifications(connection)
 
 			// Initial fetch of tools and resources
 			connection.server.tools = await this.fetchToolsList(name, source)
@@ -890,11 +934,101 @@ export class McpHub {
 			if (connection) {
 				connection.server.status = "disconnected"
 				this.appendErrorMessage(connection, error instanceof Error ? error.message : `${error}`)
+
+				if (connection.type === "connected" && connection.authProvider?.authorizationUrl) {
+					connection.server.authStatus = "required"
+				}
 			}
 			throw error
 		}
 	}
 
+	private setupResourceNotifications(connection: ConnectedMcpConnection) {
+		connection.client.setNotificationHandler(ResourceUpdatedNotificationSchema, async (notification) => {
+			await this.handleResourceUpdated(connection, notification.params.uri)
+		})
+
+		connection.client.setNotificationHandler(ResourceListChangedNotificationSchema, async () => {
+			const { name, source } = connection.server
+			connection.server.resources = await this.fetchResourcesList(name, source)
+			connection.server.resourceTemplates = await this.fetchResourceTemplatesList(name, source)
+			await this.notifyWebviewOfServerChanges()
+		})
+	}
+
+	/**
+	 * Refreshes the cached contents of a resource that its server reported as
+	 * changed, then tells the listeners of onDidUpdateResource.
+	 */
+	private async handleResourceUpdated(connection: ConnectedMcpConnection, uri: string): Promise<void> {
+		const { resourceCache } = connection
+
+		if (resourceCache?.has(uri)) {
+			try {
+				const result = await connection.client.request(
+					{ method: "resources/read", params: { uri } },
+					ReadResourceResultSchema,
+				)
+				resourceCache.set(uri, result)
+			} catch (error) {
+				// Read it from the server again the next time instead.
+				resourceCache.delete(uri)
+				console.error(`Failed to refresh MCP resource ${uri} of ${connection.server.name}:`, error)
+			}
+		}
+
+		const update: McpResourceUpdate = {
+			serverName: connection.server.name,
+			source: connection.server.source ?? "global",
+			uri,
+		}
+
+		for (const listener of this.resourceUpdateListeners) {
+			listener(update)
+		}
+	}
+
+	/**
+	 * Registers a listener for changes to the resources read with
+	 * readResource, as reported by servers that support subscriptions.
+	 * Returns a function that unregisters it.
+	 */
+	public onDidUpdateResource(listener: (update: McpResourceUpdate) => void): () => void {
+		this.resourceUpdateListeners.add(listener)
+		return () => this.resourceUpdateListeners.delete(listener)
+	}
+
+	/**
+	 * Creates the OAuth client of a remote server, unless its config already
+	 * has an Authorization header. It only comes into play if the server
+	 * answers with a 401.
+	 */
+	private createAuthProvider(
+		name: string,
+		source: "global" | "project",
+		url: string,
+		headers?: Record<string, string>,
+	): McpOAuthClientProvider | undefined {
+		const context = this.providerRef.deref()?.context
+
+		if (!context || Object.keys(headers ?? {}).some((header) => header.toLowerCase() === "authorization")) {
+			return undefined
+		}
+
+		return new McpOAuthClientProvider(url, context.secrets, this.getOAuthCallbackUrl(), async () => {
+			const connection = this.findConnection(name, source)
+			if (connection) {
+				connection.server.authStatus = "required"
+				await this.notifyWebviewOfServerChanges()
+			}
+		})
+	}
+
+	// Handled by handleUri, which passes it on to handleOAuthCallback.
+	private getOAuthCallbackUrl(): string {
+		return `${vscode.env.uriScheme}://${Package.publisher}.${Package.name}/mcp/oauth/callback`
+	}
+
 	private appendErrorMessage(connection: McpConnection, error: string, level: "error" | "warn" | "info" = "error") {
 		const MAX_ERROR_LENGTH = 1000
 		const truncatedError =
@@ -1293,6 +1427,100 @@ export class McpHub {
 		this.isConnecting = false
 	}
 
+	/**
+	 * Signs in to a remote server that requires OAuth by opening its
+	 * authorization page in the browser. The flow finishes in handleOAuthCallback.
+	 */
+	async authenticateServer(serverName: string, source?: "global" | "project"): Promise<void> {
+		const connection = this.findConnection(serverName, source)
+		const authProvider = connection?.type === "connected" ? connection.authProvider : undefined
+
+		if (!connection || !authProvider) {
+			return
+		}
+
+		try {
+			// Start a new authorization if there's none pending, which may also just refresh the tokens.
+			if (!authProvider.authorizationUrl) {
+				const result = await auth(authProvider, { serverUrl: authProvider.serverUrl })
+
+				if (result === "AUTHORIZED") {
+					await this.restartConnection(serverName, connection.server.source)
+					return
+				}
+			}
+
+			if (authProvider.authorizationUrl) {
+				await vscode.env.openExternal(vscode.Uri.parse(authProvider.authorizationUrl.toString(), true))
+			}
+		} catch (error) {
+			this.showErrorMessage(`Failed to sign in to the ${serverName} MCP server`, error)
+			vscode.window.showErrorMessage(
+				t("mcp:errors.oauth_failed", {
+					serverName,
+					error: error instanceof Error ? error.message : String(error),
+				}),
+			)
+		}
+	}
+
+	/**
+	 * Finishes the authorization started by authenticateServer with the code
+	 * that the authorization server redirected back with, then reconnects.
+	 */
+	async handleOAuthCallback(code: string | null, state: string | null, oauthError: string | null): Promise<void> {
+		const connection = this.connections.find(
+			(conn): conn is ConnectedMcpConnection =>
+				conn.type === "connected" && !!state && conn.authProvider?.authorizationState === state,
+		)
+
+		if (!connection?.authProvider) {
+			vscode.window.showErrorMessage(t("mcp:errors.oauth_no_pending"))
+			return
+		}
+
+		const serverName = connection.server.name
+
+		try {
+			if (oauthError || !code) {
+				throw new Error(oauthError ?? "No authorization code")
+			}
+
+			await auth(connection.authProvider, {
+				serverUrl: connection.authProvider.serverUrl,
+				authorizationCode: code,
+			})
+			await this.restartConnection(serverName, connection.server.source)
+		} catch (error) {
+			this.showErrorMessage(`Failed to sign in to the ${serverName} MCP server`, error)
+			vscode.window.showErrorMessage(
+				t("mcp:errors.oauth_failed", {
+					serverName,
+					error: error instanceof Error ? error.message : String(error),
+				}),
+			)
+		}
+	}
+
+	/**
+	 * Forgets the OAuth tokens of a remote server and reconnects to it.
+	 */
+	async signOutServer(serverName: string, source?: "global" | "project"): Promise<void> {
+		const connection = this.findConnection(serverName, source)
+		const context = this.providerRef.deref()?.context
+
+		if (!connection || !context) {
+			return
+		}
+
+		const { url } = JSON.parse(connection.server.config)
+
+		if (typeof url === "string") {
+			await new McpOAuthClientProvider(url, context.secrets, this.getOAuthCallbackUrl()).clear()
+			await this.restartConnection(serverName, connection.server.source)
+		}
+	}
+
 	public async refreshAllConnections(): Promise<void> {
 		if (this.isConnecting) {
 			return
@@ -1716,7 +1944,14 @@ export class McpHub {
 		if (connection.server.disabled) {
 			throw new Error(`Server "${serverName}" is disabled`)
 		}
-		return await connection.client.request(
+
+		// A subscribed resource is kept up to date by handleResourceUpdated.
+		const cached = connection.resourceCache?.get(uri)
+		if (cached) {
+			return cached
+		}
+
+		const result = await connection.client.request(
 			{
 				method: "resources/read",
 				params: {
@@ -1725,6 +1960,18 @@ export class McpHub {
 			},
 			ReadResourceResultSchema,
 		)
+
+		if (connection.client.getServerCapabilities()?.resources?.subscribe) {
+			try {
+				await connection.client.request({ method: "resources/subscribe", params: { uri } }, EmptyResultSchema)
+				connection.resourceCache ??= new Map()
+				connection.resourceCache.set(uri, result)
+			} catch (error) {
+				console.error(`Failed to subscribe to MCP resource ${uri} of ${serverName}:`, error)
+			}
+		}
+
+		return result
 	}
 
 	async callTool(
@@ -1753,7 +2000,20 @@ export class McpHub {
 			timeout = 60 * 1000
 		}
 
-		return await connection.client.request(
+		const cacheTtl = this.getToolCacheTtl(connection, toolName)
+		const cacheKey = `${toolName}:${stringify(toolArguments ?? {})}`
+
+		if (cacheTtl) {
+			const cached = connection.toolResultCache?.get(cacheKey)
+
+			if (cached && cached.expiresAt > Date.now()) {
+				return cached.result
+			}
+
+			connection.toolResultCache?.delete(cacheKey)
+		}
+
+		const result = await connection.client.request(
 			{
 				method: "tools/call",
 				params: {
@@ -1766,6 +2026,38 @@ export class McpHub {
 				timeout,
 			},
 		)
+
+		// Errors may be transient, so only successful results are reused.
+		if (cacheTtl && !result.isError) {
+			connection.toolResultCache ??= new Map()
+			connection.toolResultCache.set(cacheKey, { result, expiresAt: Date.now() + cacheTtl * 1000 })
+		}
+
+		return result
+	}
+
+	/**
+	 * Whether the results of a tool are cached, per the server's `toolCache`
+	 * config, so repeated identical calls return the same result.
+	 */
+	isToolCacheable(serverName: string, toolName: string, source?: "global" | "project"): boolean {
+		const connection = this.findConnection(serverName, source)
+		return !!connection && this.getToolCacheTtl(connection, toolName) !== undefined
+	}
+
+	// The number of seconds to cache the results of a tool for, if at all.
+	private getToolCacheTtl(connection: McpConnection, toolName: string): number | undefined {
+		try {
+			const { toolCache } = ServerConfigSchema.parse(JSON.parse(connection.server.config))
+
+			if (toolCache && (!toolCache.tools || toolCache.tools.includes(toolName))) {
+				return toolCache.ttl
+			}
+		} catch {
+			// Invalid configs don't cache anything.
+		}
+
+		return undefined
 	}
 
 	/**
@@ -1969,6 +2261,7 @@ export class McpHub {
 
 		this.isProgrammaticUpdate = false
 		this.removeAllFileWatchers()
+		this.resourceUpdateListeners.clear()
 
 		for (const connection of this.connections) {
 			try {
@@ -0,0 +1,143 @@
+import * as crypto from "crypto"
+import type { SecretStorage } from "vscode"
+import { z } from "zod"
+
+import type { OAuthClientProvider } from "@modelcontextprotocol/sdk/client/auth.js"
+import type {
+	OAuthClientInformation,
+	OAuthClientInformationFull,
+	OAuthClientMetadata,
+	OAuthTokens,
+} from "@modelcontextprotocol/sdk/shared/auth.js"
+
+// The client registration and tokens of a server, stored as one secret.
+const storedAuthSchema = z.object({
+	clientInformation: z.object({ client_id: z.string() }).passthrough().optional(),
+	tokens: z.object({ access_token: z.string(), token_type: z.string() }).passthrough().optional(),
+})
+
+type StoredAuth = z.infer<typeof storedAuthSchema>
+
+/**
+ * The OAuth client of Roo Code for one remote MCP server, following the MCP
+ * authorization spec. The SDK's transports use it to add the access token to
+ * each request and, on a 401, to refresh the token or start a new
+ * authorization. The client registration and tokens are kept in VS Code's
+ * secret storage, keyed by the server URL, so they survive restarts and are
+ * shared by all servers with the same URL.
+ *
+ * Starting an authorization doesn't open the browser by itself, since it can
+ * happen in the background when the extension starts. It only records the
+ * authorization URL and calls `onAuthorizationRequired`, and the user opens
+ * it by signing in to the server.
+ */
+export class McpOAuthClientProvider implements OAuthClientProvider {
+	private _codeVerifier?: string
+	private _authorizationUrl?: URL
+	private _state?: string
+
+	constructor(
+		readonly serverUrl: string,
+		private readonly secrets: SecretStorage,
+		private readonly callbackUrl: string,
+		private readonly onAuthorizationRequired?: (authorizationUrl: URL) => void,
+	) {}
+
+	private get secretKey() {
+		return `mcp-oauth:${this.serverUrl}`
+	}
+
+	get redirectUrl(): string {
+		return this.callbackUrl
+	}
+
+	get clientMetadata(): OAuthClientMetadata {
+		return {
+			client_name: "Roo Code",
+			client_uri: "https://roocode.com",
+			redirect_uris: [this.callbackUrl],
+			grant_types: ["authorization_code", "refresh_token"],
+			response_types: ["code"],
+			token_endpoint_auth_method: "none",
+		}
+	}
+
+	/**
+	 * The URL of the pending authorization, if the server asked for one.
+	 */
+	get authorizationUrl(): URL | undefined {
+		return this._authorizationUrl
+	}
+
+	/**
+	 * The `state` of the pending authorization, which the callback must match.
+	 */
+	get authorizationState(): string | undefined {
+		return this._state
+	}
+
+	private async load(): Promise<StoredAuth> {
+		try {
+			const json = await this.secrets.get(this.secretKey)
+			return json ? storedAuthSchema.parse(JSON.parse(json)) : {}
+		} catch (error) {
+			console.error(`[McpOAuthClientProvider] Failed to load the credentials of ${this.serverUrl}:`, error)
+			return {}
+		}
+	}
+
+	private async save(update: StoredAuth): Promise<void> {
+		await this.secrets.store(this.secretKey, JSON.stringify({ ...(await this.load()), ...update }))
+	}
+
+	async clientInformation(): Promise<OAuthClientInformation | undefined> {
+		return (await this.load()).clientInformation as OAuthClientInformation | undefined
+	}
+
+	async saveClientInformation(clientInformation: OAuthClientInformationFull): Promise<void> {
+		await this.save({ clientInformation })
+	}
+
+	async tokens(): Promise<OAuthTokens | undefined> {
+		return (await this.load()).tokens as OAuthTokens | undefined
+	}
+
+	async saveTokens(tokens: OAuthTokens): Promise<void> {
+		await this.save({ tokens })
+		this._authorizationUrl = undefined
+		this._state = undefined
+	}
+
+	async redirectToAuthorization(authorizationUrl: URL): Promise<void> {
+		// Tie the callback to this authorization, unless the SDK already did.
+		if (!authorizationUrl.searchParams.has("state")) {
+			authorizationUrl.searchParams.set("state", crypto.randomBytes(16).toString("base64url"))
+		}
+
+		this._state = authorizationUrl.searchParams.get("state")!
+		this._authorizationUrl = authorizationUrl
+		this.onAuthorizationRequired?.(authorizationUrl)
+	}
+
+	saveCodeVerifier(codeVerifier: string): void {
+		this._codeVerifier = codeVerifier
+	}
+
+	codeVerifier(): string {
+		if (!this._codeVerifier) {
+			throw new Error("No pending authorization")
+		}
+
+		return this._codeVerifier
+	}
+
+	/**
+	 * Forgets the tokens and the client registration, signing out of the server.
+	 */
+	async clear(): Promise<void> {
+		await this.secrets.delete(this.secretKey)
+		this._codeVerifier = undefined
+		this._authorizationUrl = undefined
+		this._state = undefined
+	}
+}
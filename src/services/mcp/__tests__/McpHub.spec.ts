@@ -7,6 +7,7 @@ import type { ClineProvider } from "../../../core/webview/ClineProvider"
 
 import type { McpHub as McpHubType, McpConnection, ConnectedMcpConnection, DisconnectedMcpConnection } from "../McpHub"
 import { ServerConfigSchema, McpHub } from "../McpHub"
+import { McpOAuthClientProvider } from "../McpOAuthClientProvider"
 
 // Mock fs/promises before importing anything that uses it
 vi.mock("fs/promises", () => ({
@@ -72,6 +73,10 @@ vi.mock("vscode", () => ({
 	Disposable: {
 		from: vi.fn(),
 	},
+	env: {
+		uriScheme: "vscode",
+		openExternal: vi.fn(),
+	},
 }))
 vi.mock("fs/promises")
 vi.mock("../../../core/webview/ClineProvider")
@@ -211,6 +216,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}
 
@@ -379,6 +385,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}
 
@@ -452,6 +459,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}
 
@@ -662,6 +670,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}
 
@@ -734,6 +743,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}
 
@@ -1420,6 +1430,143 @@ describe("McpHub", () => {
 		})
 	})
 
+	describe("resource subscriptions", () => {
+		const createConnection = (subscribe: boolean) => {
+			const contents = { contents: [{ uri: "ticket://1", text: "Open" }] }
+			const connection: ConnectedMcpConnection = {
+				type: "connected",
+				server: {
+					name: "tickets",
+					config: JSON.stringify({}),
+					status: "connected",
+					source: "global",
+				},
+				client: {
+					request: vi.fn().mockResolvedValue(contents),
+					getServerCapabilities: vi.fn().mockReturnValue({ resources: { subscribe } }),
+				} as any,
+				transport: {} as any,
+			}
+			mcpHub.connections = [connection]
+			return connection
+		}
+
+		const notifyUpdated = async (connection: ConnectedMcpConnection, uri: string) => {
+			// handleResourceUpdated is what the client's notification handler calls.
+			await (mcpHub as any).handleResourceUpdated(connection, uri)
+		}
+
+		it("should subscribe to a resource when the server supports it and serve it from the cache", async () => {
+			const connection = createConnection(true)
+
+			await mcpHub.readResource("tickets", "ticket://1")
+			const second = await mcpHub.readResource("tickets", "ticket://1")
+
+			expect(connection.client.request).toHaveBeenCalledTimes(2)
+			expect(connection.client.request).toHaveBeenCalledWith(
+				{ method: "resources/subscribe", params: { uri: "ticket://1" } },
+				expect.anything(),
+			)
+			expect(second.contents[0].text).toBe("Open")
+		})
+
+		it("should read the resource every time when the server doesn't support subscriptions", async () => {
+			const connection = createConnection(false)
+
+			await mcpHub.readResource("tickets", "ticket://1")
+			await mcpHub.readResource("tickets", "ticket://1")
+
+			expect(connection.client.request).toHaveBeenCalledTimes(2)
+			expect(connection.client.request).not.toHaveBeenCalledWith(
+				expect.objectContaining({ method: "resources/subscribe" }),
+				expect.anything(),
+			)
+		})
+
+		it("should refresh the cached resource and tell the listeners when it changes", async () => {
+			const connection = createConnection(true)
+			const listener = vi.fn()
+			mcpHub.onDidUpdateResource(listener)
+
+			await mcpHub.readResource("tickets", "ticket://1")
+			;(connection.client.request as Mock).mockResolvedValueOnce({
+				contents: [{ uri: "ticket://1", text: "Closed" }],
+			})
+			await notifyUpdated(connection, "ticket://1")
+
+			expect(listener).toHaveBeenCalledWith({ serverName: "tickets", source: "global", uri: "ticket://1" })
+			expect((await mcpHub.readResource("tickets", "ticket://1")).contents[0].text).toBe("Closed")
+		})
+
+		it("should stop telling a listener once it unregisters", async () => {
+			const connection = createConnection(true)
+			const listener = vi.fn()
+			const unregister = mcpHub.onDidUpdateResource(listener)
+
+			unregister()
+			await notifyUpdated(connection, "ticket://1")
+
+			expect(listener).not.toHaveBeenCalled()
+		})
+	})
+
+	describe("OAuth", () => {
+		const secrets = { get: vi.fn(), store: vi.fn(), delete: vi.fn() }
+
+		const createRemoteConnection = (authProvider?: McpOAuthClientProvider): ConnectedMcpConnection => ({
+			type: "connected",
+			server: {
+				name: "remote-server",
+				config: JSON.stringify({ type: "streamable-http", url: "https://mcp.example.com/mcp" }),
+				status: "disconnected",
+				source: "global",
+				authStatus: "required",
+			},
+			client: {} as any,
+			transport: {} as any,
+			authProvider,
+		})
+
+		it("should reject a callback without a pending sign-in", async () => {
+			const vscode = await import("vscode")
+			const restartSpy = vi.spyOn(mcpHub, "restartConnection").mockResolvedValue()
+			mcpHub.connections = [createRemoteConnection()]
+
+			await mcpHub.handleOAuthCallback("code", "unknown-state", null)
+
+			expect(vscode.window.showErrorMessage).toHaveBeenCalled()
+			expect(restartSpy).not.toHaveBeenCalled()
+		})
+
+		it("should not reconnect when the authorization server returns an error", async () => {
+			const vscode = await import("vscode")
+			const restartSpy = vi.spyOn(mcpHub, "restartConnection").mockResolvedValue()
+			const authProvider = new McpOAuthClientProvider(
+				"https://mcp.example.com/mcp",
+				secrets as any,
+				"vscode://rooveterinaryinc.roo-cline/mcp/oauth/callback",
+			)
+			await authProvider.redirectToAuthorization(new URL("https://auth.example.com/authorize"))
+			mcpHub.connections = [createRemoteConnection(authProvider)]
+
+			await mcpHub.handleOAuthCallback(null, authProvider.authorizationState!, "access_denied")
+
+			expect(vscode.window.showErrorMessage).toHaveBeenCalled()
+			expect(restartSpy).not.toHaveBeenCalled()
+		})
+
+		it("should forget the tokens of a server when signing out", async () => {
+			const restartSpy = vi.spyOn(mcpHub, "restartConnection").mockResolvedValue()
+			Object.assign(mockProvider.context!, { secrets })
+			mcpHub.connections = [createRemoteConnection()]
+
+			await mcpHub.signOutServer("remote-server", "global")
+
+			expect(secrets.delete).toHaveBeenCalledWith("mcp-oauth:https://mcp.example.com/mcp")
+			expect(restartSpy).toHaveBeenCalledWith("remote-server", "global")
+		})
+	})
+
 	describe("callTool", () => {
 		it("should execute tool successfully", async () => {
 			// Mock the connection with a minimal client implementation
@@ -1535,6 +1682,89 @@ describe("McpHub", () => {
 			})
 		})
 
+		describe("tool cache", () => {
+			const createConnection = (toolCache?: { ttl: number; tools?: string[] }): ConnectedMcpConnection => ({
+				type: "connected",
+				server: {
+					name: "test-server",
+					config: JSON.stringify({ type: "stdio", command: "test", toolCache }),
+					status: "connected",
+				},
+				client: {
+					request: vi.fn().mockResolvedValue({ content: [{ type: "text", text: "result" }] }),
+				} as any,
+				transport: {} as any,
+			})
+
+			afterEach(() => {
+				vi.useRealTimers()
+			})
+
+			it("should validate the tool cache config", () => {
+				expect(() =>
+					ServerConfigSchema.parse({ type: "stdio", command: "test", toolCache: { ttl: 300 } }),
+				).not.toThrow()
+				expect(() =>
+					ServerConfigSchema.parse({ type: "stdio", command: "test", toolCache: { ttl: 0 } }),
+				).toThrow()
+			})
+
+			it("should not cache tool results by default", async () => {
+				const connection = createConnection()
+				mcpHub.connections = [connection]
+
+				await mcpHub.callTool("test-server", "list_projects", {})
+				await mcpHub.callTool("test-server", "list_projects", {})
+
+				expect(connection.client.request).toHaveBeenCalledTimes(2)
+				expect(mcpHub.isToolCacheable("test-server", "list_projects")).toBe(false)
+			})
+
+			it("should reuse the result of identical calls within the TTL", async () => {
+				vi.useFakeTimers()
+				const connection = createConnection({ ttl: 60 })
+				mcpHub.connections = [connection]
+
+				const first = await mcpHub.callTool("test-server", "get_schema", { table: "users", schema: "public" })
+				// The order of the arguments doesn't matter.
+				const second = await mcpHub.callTool("test-server", "get_schema", { schema: "public", table: "users" })
+				await mcpHub.callTool("test-server", "get_schema", { table: "orders", schema: "public" })
+
+				expect(second).toBe(first)
+				expect(connection.client.request).toHaveBeenCalledTimes(2)
+
+				vi.advanceTimersByTime(61_000)
+				await mcpHub.callTool("test-server", "get_schema", { table: "users", schema: "public" })
+
+				expect(connection.client.request).toHaveBeenCalledTimes(3)
+			})
+
+			it("should only cache the listed tools", async () => {
+				const connection = createConnection({ ttl: 60, tools: ["list_projects"] })
+				mcpHub.connections = [connection]
+
+				await mcpHub.callTool("test-server", "list_projects")
+				await mcpHub.callTool("test-server", "list_projects")
+				await mcpHub.callTool("test-server", "create_project")
+				await mcpHub.callTool("test-server", "create_project")
+
+				expect(connection.client.request).toHaveBeenCalledTimes(3)
+				expect(mcpHub.isToolCacheable("test-server", "list_projects")).toBe(true)
+				expect(mcpHub.isToolCacheable("test-server", "create_project")).toBe(false)
+			})
+
+			it("should not cache errors", async () => {
+				const connection = createConnection({ ttl: 60 })
+				vi.mocked(connection.client.request).mockResolvedValue({ content: [], isError: true })
+				mcpHub.connections = [connection]
+
+				await mcpHub.callTool("test-server", "list_projects")
+				await mcpHub.callTool("test-server", "list_projects")
+
+				expect(connection.client.request).toHaveBeenCalledTimes(2)
+			})
+		})
+
 		describe("updateServerTimeout", () => {
 			it("should update server timeout in settings file", async () => {
 				const mockConfig = {
@@ -1765,6 +1995,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}
 
@@ -1885,6 +2116,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}))
 
@@ -2072,6 +2304,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}))
 
@@ -2134,6 +2367,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}))
 
@@ -2196,6 +2430,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}))
 
@@ -2265,6 +2500,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}))
 
@@ -2338,6 +2574,7 @@ describe("McpHub", () => {
 				connect: vi.fn().mockResolvedValue(undefined),
 				close: vi.fn().mockResolvedValue(undefined),
 				getInstructions: vi.fn().mockReturnValue("test instructions"),
+				setNotificationHandler: vi.fn(),
 				request: vi.fn().mockResolvedValue({ tools: [], resources: [], resourceTemplates: [] }),
 			}))
 
@@ -0,0 +1,111 @@
+// npx vitest run src/services/mcp/__tests__/McpOAuthClientProvider.spec.ts
+
+import type { SecretStorage } from "vscode"
+
+import { McpOAuthClientProvider } from "../McpOAuthClientProvider"
+
+const SERVER_URL = "https://mcp.example.com/mcp"
+const CALLBACK_URL = "vscode://rooveterinaryinc.roo-cline/mcp/oauth/callback"
+
+const createSecrets = () => {
+	const store = new Map<string, string>()
+
+	return {
+		store,
+		secrets: {
+			get: vi.fn(async (key: string) => store.get(key)),
+			store: vi.fn(async (key: string, value: string) => {
+				store.set(key, value)
+			}),
+			delete: vi.fn(async (key: string) => {
+				store.delete(key)
+			}),
+		} as unknown as SecretStorage,
+	}
+}
+
+describe("McpOAuthClientProvider", () => {
+	it("registers the callback URL as its redirect URL", () => {
+		const { secrets } = createSecrets()
+		const provider = new McpOAuthClientProvider(SERVER_URL, secrets, CALLBACK_URL)
+
+		expect(provider.redirectUrl).toBe(CALLBACK_URL)
+		expect(provider.clientMetadata.redirect_uris).toEqual([CALLBACK_URL])
+		expect(provider.clientMetadata.grant_types).toContain("refresh_token")
+	})
+
+	it("keeps the client registration and tokens of a server URL in secret storage", async () => {
+		const { secrets, store } = createSecrets()
+		const provider = new McpOAuthClientProvider(SERVER_URL, secrets, CALLBACK_URL)
+
+		await provider.saveClientInformation({ client_id: "client-1", redirect_uris: [CALLBACK_URL] })
+		await provider.saveTokens({ access_token: "access-1", token_type: "Bearer", refresh_token: "refresh-1" })
+
+		expect(store.has(`mcp-oauth:${SERVER_URL}`)).toBe(true)
+
+		// Another connection to the same server sees them.
+		const other = new McpOAuthClientProvider(SERVER_URL, secrets, CALLBACK_URL)
+		expect(await other.clientInformation()).toMatchObject({ client_id: "client-1" })
+		expect(await other.tokens()).toMatchObject({ access_token: "access-1", refresh_token: "refresh-1" })
+
+		const unrelated = new McpOAuthClientProvider("https://other.example.com/mcp", secrets, CALLBACK_URL)
+		expect(await unrelated.tokens()).toBeUndefined()
+	})
+
+	it("records the authorization instead of opening it", async () => {
+		const { secrets } = createSecrets()
+		const onAuthorizationRequired = vi.fn()
+		const provider = new McpOAuthClientProvider(SERVER_URL, secrets, CALLBACK_URL, onAuthorizationRequired)
+
+		await provider.redirectToAuthorization(new URL("https://auth.example.com/authorize?client_id=client-1"))
+
+		expect(provider.authorizationState).toBeTruthy()
+		expect(provider.authorizationUrl?.searchParams.get("state")).toBe(provider.authorizationState)
+		expect(onAuthorizationRequired).toHaveBeenCalledWith(provider.authorizationUrl)
+	})
+
+	it("keeps a state the SDK already set", async () => {
+		const { secrets } = createSecrets()
+		const provider = new McpOAuthClientProvider(SERVER_URL, secrets, CALLBACK_URL)
+
+		await provider.redirectToAuthorization(new URL("https://auth.example.com/authorize?state=abc"))
+
+		expect(provider.authorizationState).toBe("abc")
+	})
+
+	it("ends the pending authorization once tokens are saved", async () => {
+		const { secrets } = createSecrets()
+		const provider = new McpOAuthClientProvider(SERVER_URL, secrets, CALLBACK_URL)
+
+		await provider.redirectToAuthorization(new URL("https://auth.example.com/authorize"))
+		provider.saveCodeVerifier("verifier")
+		expect(provider.codeVerifier()).toBe("verifier")
+
+		await provider.saveTokens({ access_token: "access-1", token_type: "Bearer" })
+
+		expect(provider.authorizationUrl).toBeUndefined()
+		expect(provider.authorizationState).toBeUndefined()
+	})
+
+	it("forgets everything when cleared", async () => {
+		const { secrets, store } = createSecrets()
+		const provider = new McpOAuthClientProvider(SERVER_URL, secrets, CALLBACK_URL)
+
+		await provider.saveTokens({ access_token: "access-1", token_type: "Bearer" })
+		await provider.clear()
+
+		expect(store.size).toBe(0)
+		expect(await provider.tokens()).toBeUndefined()
+		expect(() => provider.codeVerifier()).toThrow()
+	})
+
+	it("ignores stored credentials it can't read", async () => {
+		const { secrets, store } = createSecrets()
+		store.set(`mcp-oauth:${SERVER_URL}`, "not json")
+		vi.spyOn(console, "error").mockImplementation(() => {})
+
+		const provider = new McpOAuthClientProvider(SERVER_URL, secrets, CALLBACK_URL)
+
+		expect(await provider.tokens()).toBeUndefined()
+	})
+})
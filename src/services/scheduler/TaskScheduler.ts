@@ -0,0 +1,437 @@
+import * as fs from "fs/promises"
+import * as path from "path"
+import * as vscode from "vscode"
+
+import type { ScheduledTask, SchedulePromptVariable } from "@roo-code/types"
+
+import type { ClineProvider } from "../../core/webview/ClineProvider"
+import type { Task } from "../../core/task/Task"
+import { runBackgroundTask, type BackgroundTaskResult } from "../../core/task/background-tasks"
+import { EXPERIMENT_IDS, experiments } from "../../shared/experiments"
+import { getModeBySlug } from "../../shared/modes"
+import { getGlobalRooDirectory, getProjectRooDirectoryForCwd } from "../roo-config"
+import { t } from "../../i18n"
+import { parseCron } from "./cron"
+import { SCHEDULES_FILE_NAME, loadSchedules, renderSchedulePrompt } from "./schedules"
+
+// Changes to matching files are collected for a while so one save or checkout
+// runs a schedule once.
+const FILE_CHANGE_DEBOUNCE_MS = 5_000
+
+const LAST_RUNS_KEY = "scheduledTaskLastRuns"
+const TRUSTED_PROJECT_SCHEDULES_KEY = "trustedProjectSchedulesHash"
+
+/**
+ * Runs the tasks defined in `schedules.json` (see `loadSchedules`) when their
+ * triggers fire, while the "scheduledTasks" experiment is enabled. Each run is
+ * a background task in the schedule's mode and with its auto-approval profile,
+ * so it doesn't disturb the current task; it can be opened from the history
+ * once it's done. A schedule doesn't run again while its previous run is still
+ * going, and cron schedules missed while VS Code was closed don't catch up.
+ *
+ * The project's schedules only run in a trusted workspace, once the user has
+ * allowed the current content of its `schedules.json`.
+ */
+export class TaskScheduler {
+	private providerRef: WeakRef<ClineProvider>
+	private schedules: ScheduledTask[] = []
+	// The schedules that are running, with their task once it has been created
+	private running = new Map<string, Task | undefined>()
+	private changedFiles = new Map<string, Set<string>>()
+	private fileChangeTimers = new Map<string, NodeJS.Timeout>()
+	private cronTimer?: NodeJS.Timeout
+	private lastGitLogEntry?: string
+	private disposables: vscode.Disposable[] = []
+	private triggerDisposables: vscode.Disposable[] = []
+	private isDisposed = false
+	// The project schedules file content the user was last asked about
+	private promptedProjectFileHash?: string
+
+	constructor(provider: ClineProvider) {
+		this.providerRef = new WeakRef(provider)
+	}
+
+	async initialize(): Promise<void> {
+		await this.reload()
+		this.watchSchedulesFiles()
+
+		if (vscode.workspace.onDidGrantWorkspaceTrust) {
+			this.disposables.push(vscode.workspace.onDidGrantWorkspaceTrust(() => void this.reload()))
+		}
+
+		for (const schedule of this.schedules) {
+			if (schedule.trigger.type === "workspaceOpen") {
+				void this.run(schedule, "the workspace was opened")
+			}
+		}
+	}
+
+	/**
+	 * Loads the schedules again and sets up their triggers.
+	 */
+	async reload(): Promise<void> {
+		const provider = this.providerRef.deref()
+
+		if (!provider || this.isDisposed) {
+			return
+		}
+
+		const isWorkspaceTrusted = vscode.workspace.isTrusted !== false
+		const { schedules, errors, untrustedProjectFile } = await loadSchedules(provider.cwd, {
+			trustedProjectFileHash: isWorkspaceTrusted
+				? provider.context.workspaceState.get<string>(TRUSTED_PROJECT_SCHEDULES_KEY)
+				: undefined,
+		})
+
+		for (const error of errors) {
+			provider.log(`[TaskScheduler] ${error}`)
+		}
+
+		if (errors.length > 0) {
+			vscode.window.showWarningMessage(t("common:scheduler.invalid_schedules"))
+		}
+
+		this.schedules = schedules.filter((schedule) => schedule.enabled !== false)
+		this.disposeTriggers()
+		await this.setUpTriggers(provider.cwd)
+
+		if (isWorkspaceTrusted && untrustedProjectFile) {
+			this.askToTrustProjectSchedules(untrustedProjectFile.hash)
+		}
+	}
+
+	/**
+	 * Asks once per content of the project schedules file whether its
+	 * schedules may run, and loads them when the user allows it.
+	 */
+	private askToTrustProjectSchedules(hash: string) {
+		if (this.promptedProjectFileHash === hash) {
+			return
+		}
+
+		this.promptedProjectFileHash = hash
+		const allow = t("common:scheduler.allow")
+		const dontAllow = t("common:scheduler.dont_allow")
+
+		vscode.window
+			.showWarningMessage(t("common:scheduler.untrusted_project_schedules"), allow, dontAllow)
+			.then(async (selection) => {
+				const provider = this.providerRef.deref()
+
+				if (selection !== allow || !provider || this.isDisposed) {
+					return
+				}
+
+				await provider.context.workspaceState.update(TRUSTED_PROJECT_SCHEDULES_KEY, hash)
+				await this.reload()
+			})
+	}
+
+	private watchSchedulesFiles() {
+		if (process.env.NODE_ENV === "test" || !vscode.workspace.createFileSystemWatcher) {
+			return
+		}
+
+		const provider = this.providerRef.deref()
+
+		if (!provider) {
+			return
+		}
+
+		for (const dir of [getGlobalRooDirectory(), getProjectRooDirectoryForCwd(provider.cwd)]) {
+			const watcher = vscode.workspace.createFileSystemWatcher(
+				new vscode.RelativePattern(vscode.Uri.file(dir), SCHEDULES_FILE_NAME),
+			)
+			const reload = () => void this.reload()
+
+			watcher.onDidChange(reload)
+			watcher.onDidCreate(reload)
+			watcher.onDidDelete(reload)
+			this.disposables.push(watcher)
+		}
+	}
+
+	private async setUpTriggers(cwd: string) {
+		if (this.schedules.some(({ trigger }) => trigger.type === "cron")) {
+			this.scheduleCronTick()
+		}
+
+		if (process.env.NODE_ENV === "test" || !vscode.workspace.createFileSystemWatcher) {
+			return
+		}
+
+		for (const schedule of this.schedules) {
+			if (schedule.trigger.type !== "fileChanged") {
+				continue
+			}
+
+			const watcher = vscode.workspace.createFileSystemWatcher(
+				new vscode.RelativePattern(vscode.Uri.file(cwd), schedule.trigger.glob),
+			)
+			const onChange = (uri: vscode.Uri) => this.onFileChanged(schedule, path.relative(cwd, uri.fsPath))
+
+			watcher.onDidChange(onChange)
+			watcher.onDidCreate(onChange)
+			watcher.onDidDelete(onChange)
+			this.triggerDisposables.push(watcher)
+		}
+
+		if (this.schedules.some(({ trigger }) => trigger.type === "gitPull")) {
+			const gitDir = await resolveGitDir(cwd)
+
+			if (gitDir) {
+				const headLog = path.join(gitDir, "logs", "HEAD")
+				this.lastGitLogEntry = await readLastLine(headLog)
+
+				const watcher = vscode.workspace.createFileSystemWatcher(
+					new vscode.RelativePattern(vscode.Uri.file(gitDir), "logs/HEAD"),
+				)
+				const onChange = () => void this.onGitLogChanged(headLog)
+
+				watcher.onDidChange(onChange)
+				watcher.onDidCreate(onChange)
+				this.triggerDisposables.push(watcher)
+			}
+		}
+	}
+
+	/**
+	 * Checks the cron schedules at the start of every minute.
+	 */
+	private scheduleCronTick() {
+		const now = new Date()
+		const delay = 60_000 - (now.getSeconds() * 1_000 + now.getMilliseconds())
+
+		this.cronTimer = setTimeout(() => {
+			const date = new Date()
+
+			for (const schedule of this.schedules) {
+				if (schedule.trigger.type === "cron" && parseCron(schedule.trigger.cron).matches(date)) {
+					void this.run(schedule, `its schedule "${schedule.trigger.cron}" came up`)
+				}
+			}
+
+			this.scheduleCronTick()
+		}, delay)
+	}
+
+	private onFileChanged(schedule: ScheduledTask, relativePath: string) {
+		// Ignore the changes a schedule's own run makes.
+		if (this.isDisposed || this.running.has(schedule.name) || relativePath.split(path.sep)[0] === ".git") {
+			return
+		}
+
+		const files = this.changedFiles.get(schedule.name) ?? new Set()
+		files.add(relativePath.toPosix())
+		this.changedFiles.set(schedule.name, files)
+
+		clearTimeout(this.fileChangeTimers.get(schedule.name))
+
+		this.fileChangeTimers.set(
+			schedule.name,
+			setTimeout(() => {
+				this.fileChangeTimers.delete(schedule.name)
+				this.changedFiles.delete(schedule.name)
+
+				const changedFiles = [...files].sort()
+				void this.run(schedule, `${changedFiles.length} matching file(s) changed`, {
+					changedFiles: changedFiles.join("\n"),
+				})
+			}, FILE_CHANGE_DEBOUNCE_MS),
+		)
+	}
+
+	/**
+	 * Runs the gitPull schedules when the newest entry of the HEAD reflog is a
+	 * pull (e.g. "pull: Fast-forward" or "pull --rebase (finish): ...").
+	 */
+	private async onGitLogChanged(headLog: string) {
+		const entry = await readLastLine(headLog)
+
+		if (!entry || entry === this.lastGitLogEntry) {
+			return
+		}
+
+		this.lastGitLogEntry = entry
+		const message = entry.split("\t")[1] ?? ""
+
+		if (!message.startsWith("pull")) {
+			return
+		}
+
+		for (const schedule of this.schedules) {
+			if (schedule.trigger.type === "gitPull") {
+				void this.run(schedule, "a git pull updated the workspace")
+			}
+		}
+	}
+
+	/**
+	 * Runs a schedule as a background task and lets the user know how it went.
+	 */
+	async run(
+		schedule: ScheduledTask,
+		reason: string,
+		variables: Partial<Record<SchedulePromptVariable, string>> = {},
+	): Promise<BackgroundTaskResult | undefined> {
+		const provider = this.providerRef.deref()
+
+		if (!provider || this.isDisposed || this.running.has(schedule.name)) {
+			return undefined
+		}
+
+		// Claim the schedule before anything async, so a second trigger skips it.
+		this.running.set(schedule.name, undefined)
+
+		try {
+			return await this.execute(provider, schedule, reason, variables)
+		} finally {
+			this.running.delete(schedule.name)
+		}
+	}
+
+	private async execute(
+		provider: ClineProvider,
+		schedule: ScheduledTask,
+		reason: string,
+		variables: Partial<Record<SchedulePromptVariable, string>>,
+	): Promise<BackgroundTaskResult | undefined> {
+		const state = await provider.getState()
+
+		if (!experiments.isEnabled(state.experiments ?? {}, EXPERIMENT_IDS.SCHEDULED_TASKS)) {
+			return undefined
+		}
+
+		if (!getModeBySlug(schedule.mode, state.customModes)) {
+			const result: BackgroundTaskResult = { status: "failed", result: `Unknown mode "${schedule.mode}".` }
+			this.notify(schedule, result)
+			return result
+		}
+
+		const lastRun = provider.context.workspaceState.get<Record<string, number>>(LAST_RUNS_KEY, {})[schedule.name]
+		const now = new Date()
+
+		const message = renderSchedulePrompt(schedule.prompt, {
+			date: formatDate(now),
+			time: now.toTimeString().slice(0, 5),
+			lastRun: lastRun ? new Date(lastRun).toISOString() : "never",
+			workspace: provider.cwd,
+			trigger: reason,
+			changedFiles: "",
+			...variables,
+		})
+
+		let task: Task
+
+		try {
+			task = await provider.createBackgroundTask({
+				message,
+				mode: schedule.mode,
+				autoApproval: schedule.autoApprove,
+			})
+		} catch (error) {
+			const result: BackgroundTaskResult = {
+				status: "failed",
+				result: `It couldn't be started: ${error instanceof Error ? error.message : String(error)}`,
+			}
+			this.notify(schedule, result)
+			return result
+		}
+
+		provider.log(`[TaskScheduler] running "${schedule.name}" as task ${task.taskId} because ${reason}`)
+		this.running.set(schedule.name, task)
+
+		const result = await runBackgroundTask(task)
+
+		const lastRuns = provider.context.workspaceState.get<Record<string, number>>(LAST_RUNS_KEY, {})
+		await provider.context.workspaceState.update(LAST_RUNS_KEY, { ...lastRuns, [schedule.name]: now.getTime() })
+
+		if (result.status === "completed") {
+			await provider.completeBackgroundTask(task.taskId)
+		}
+
+		if (!this.isDisposed) {
+			this.notify(schedule, result, task.taskId)
+		}
+
+		return result
+	}
+
+	private notify(schedule: ScheduledTask, result: BackgroundTaskResult, taskId?: string) {
+		const viewTask = t("common:scheduler.view_task")
+		const actions = taskId ? [viewTask] : []
+
+		const isCompleted = result.status === "completed"
+		const message = isCompleted
+			? t("common:scheduler.completed", { name: schedule.name })
+			: t("common:scheduler.failed", { name: schedule.name, reason: result.result })
+		const choice = isCompleted
+			? vscode.window.showInformationMessage(message, ...actions)
+			: vscode.window.showWarningMessage(message, ...actions)
+
+		choice.then((selection) => {
+			if (selection === viewTask && taskId) {
+				void this.providerRef.deref()?.showTaskWithId(taskId)
+			}
+		})
+	}
+
+	private disposeTriggers() {
+		clearTimeout(this.cronTimer)
+		this.cronTimer = undefined
+		this.triggerDisposables.forEach((d) => d.dispose())
+		this.triggerDisposables = []
+
+		for (const timer of this.fileChangeTimers.values()) {
+			clearTimeout(timer)
+		}
+
+		this.fileChangeTimers.clear()
+		this.changedFiles.clear()
+	}
+
+	async dispose(): Promise<void> {
+		this.isDisposed = true
+		this.disposeTriggers()
+		this.disposables.forEach((d) => d.dispose())
+		this.disposables = []
+
+		await Promise.all([...this.running.values()].map((task) => task?.abortTask()))
+		this.running.clear()
+	}
+}
+
+function formatDate(date: Date): string {
+	const pad = (value: number) => String(value).padStart(2, "0")
+	return `${date.getFullYear()}-${pad(date.getMonth() + 1)}-${pad(date.getDate())}`
+}
+
+/**
+ * The git directory of the repository at `cwd`, following the `.git` file of
+ * a worktree.
+ */
+async function resolveGitDir(cwd: string): Promise<string | undefined> {
+	const dotGit = path.join(cwd, ".git")
+
+	try {
+		const stat = await fs.stat(dotGit)
+
+		if (stat.isDirectory()) {
+			return dotGit
+		}
+
+		const match = (await fs.readFile(dotGit, "utf-8")).match(/^gitdir:\s*(.+)$/m)
+		return match ? path.resolve(cwd, match[1].trim()) : undefined
+	} catch {
+		return undefined
+	}
+}
+
+async function readLastLine(filePath: string): Promise<string | undefined> {
+	try {
+		const content = await fs.readFile(filePath, "utf-8")
+		return content.trimEnd().split("\n").at(-1)
+	} catch {
+		return undefined
+	}
+}
@@ -0,0 +1,62 @@
+// npx vitest run src/services/scheduler/__tests__/cron.spec.ts
+
+import { parseCron } from "../cron"
+
+// Months are zero-based in the Date constructor; 2026-03-02 is a Monday.
+const at = (day: number, hours: number, minutes: number, month = 2) => new Date(2026, month, day, hours, minutes)
+
+describe("parseCron", () => {
+	it("matches single values and wildcards", () => {
+		const schedule = parseCron("30 9 * * *")
+
+		expect(schedule.matches(at(2, 9, 30))).toBe(true)
+		expect(schedule.matches(at(3, 9, 30))).toBe(true)
+		expect(schedule.matches(at(2, 9, 31))).toBe(false)
+		expect(schedule.matches(at(2, 10, 30))).toBe(false)
+	})
+
+	it("supports ranges, lists and steps", () => {
+		const schedule = parseCron("*/15 8-10,14 * * *")
+
+		expect(schedule.matches(at(2, 8, 0))).toBe(true)
+		expect(schedule.matches(at(2, 10, 45))).toBe(true)
+		expect(schedule.matches(at(2, 14, 15))).toBe(true)
+		expect(schedule.matches(at(2, 8, 20))).toBe(false)
+		expect(schedule.matches(at(2, 11, 0))).toBe(false)
+
+		expect(parseCron("5/20 * * * *").matches(at(2, 0, 45))).toBe(true)
+		expect(parseCron("5/20 * * * *").matches(at(2, 0, 40))).toBe(false)
+	})
+
+	it("supports month and weekday names, with 7 as Sunday", () => {
+		const weekdays = parseCron("0 9 * * mon-fri")
+
+		expect(weekdays.matches(at(2, 9, 0))).toBe(true)
+		expect(weekdays.matches(at(1, 9, 0))).toBe(false)
+
+		expect(parseCron("0 9 * * 7").matches(at(1, 9, 0))).toBe(true)
+		expect(parseCron("0 9 1 MAR *").matches(at(1, 9, 0))).toBe(true)
+		expect(parseCron("0 9 1 apr *").matches(at(1, 9, 0))).toBe(false)
+	})
+
+	it("matches either day field when both are restricted", () => {
+		const schedule = parseCron("0 0 15 * mon")
+
+		expect(schedule.matches(at(15, 0, 0))).toBe(true)
+		expect(schedule.matches(at(2, 0, 0))).toBe(true)
+		expect(schedule.matches(at(3, 0, 0))).toBe(false)
+	})
+
+	it("supports macros", () => {
+		expect(parseCron("@daily").matches(at(2, 0, 0))).toBe(true)
+		expect(parseCron("@hourly").matches(at(2, 13, 0))).toBe(true)
+		expect(parseCron("@weekly").matches(at(2, 0, 0))).toBe(false)
+	})
+
+	it.each(["", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"])(
+		"rejects %j",
+		(expression) => {
+			expect(() => parseCron(expression)).toThrow()
+		},
+	)
+})
@@ -0,0 +1,118 @@
+// npx vitest run src/services/scheduler/__tests__/schedules.spec.ts
+
+import { hashSchedulesFile, loadSchedules, renderSchedulePrompt } from "../schedules"
+import { readFileIfExists } from "../../roo-config"
+
+vi.mock("../../roo-config", () => ({
+	getGlobalRooDirectory: () => "/home/user/.roo",
+	getProjectRooDirectoryForCwd: (cwd: string) => `${cwd}/.roo`,
+	readFileIfExists: vi.fn(),
+}))
+
+const files: Record<string, unknown> = {}
+
+describe("loadSchedules", () => {
+	beforeEach(() => {
+		for (const key of Object.keys(files)) {
+			delete files[key]
+		}
+
+		vi.mocked(readFileIfExists).mockImplementation(async (filePath) => {
+			const content = files[filePath.replace(/\\/g, "/")]
+			return content === undefined ? null : typeof content === "string" ? content : JSON.stringify(content)
+		})
+	})
+
+	const schedule = (name: string, overrides: object = {}) => ({
+		name,
+		trigger: { type: "workspaceOpen" },
+		mode: "code",
+		prompt: "Do it",
+		...overrides,
+	})
+
+	// Loads the project schedules as if the user had allowed the current file.
+	const loadTrustedSchedules = (cwd: string) =>
+		loadSchedules(cwd, {
+			trustedProjectFileHash: hashSchedulesFile(JSON.stringify(files[`${cwd}/.roo/schedules.json`])),
+		})
+
+	it("returns no schedules without schedules files", async () => {
+		expect(await loadSchedules("/project")).toEqual({ schedules: [], errors: [] })
+	})
+
+	it("lets project schedules replace global ones with the same name", async () => {
+		files["/home/user/.roo/schedules.json"] = { schedules: [schedule("changelog"), schedule("lint")] }
+		files["/project/.roo/schedules.json"] = { schedules: [schedule("changelog", { mode: "architect" })] }
+
+		const { schedules, errors } = await loadTrustedSchedules("/project")
+
+		expect(errors).toEqual([])
+		expect(schedules.map(({ name, mode }) => [name, mode])).toEqual([
+			["changelog", "architect"],
+			["lint", "code"],
+		])
+	})
+
+	it("skips the project schedules until their file is trusted", async () => {
+		files["/home/user/.roo/schedules.json"] = { schedules: [schedule("lint")] }
+		files["/project/.roo/schedules.json"] = {
+			schedules: [schedule("install", { autoApprove: { alwaysAllowExecute: true, allowedCommands: ["*"] } })],
+		}
+		const content = JSON.stringify(files["/project/.roo/schedules.json"])
+
+		const untrusted = await loadSchedules("/project")
+
+		expect(untrusted.schedules.map(({ name }) => name)).toEqual(["lint"])
+		expect(untrusted.untrustedProjectFile).toEqual({
+			path: expect.stringContaining("schedules.json"),
+			hash: hashSchedulesFile(content),
+		})
+
+		// Trust doesn't carry over to a changed file.
+		const stale = await loadSchedules("/project", { trustedProjectFileHash: hashSchedulesFile("{}") })
+		expect(stale.schedules.map(({ name }) => name)).toEqual(["lint"])
+
+		const trusted = await loadSchedules("/project", { trustedProjectFileHash: hashSchedulesFile(content) })
+		expect(trusted.schedules.map(({ name }) => name)).toEqual(["lint", "install"])
+		expect(trusted.untrustedProjectFile).toBeUndefined()
+	})
+
+	it("reports invalid files and cron expressions", async () => {
+		files["/home/user/.roo/schedules.json"] = "{ not json"
+		files["/project/.roo/schedules.json"] = {
+			schedules: [
+				schedule("bad cron", { trigger: { type: "cron", cron: "61 * * * *" } }),
+				schedule("good cron", { trigger: { type: "cron", cron: "0 9 * * 1-5" } }),
+			],
+		}
+
+		const { schedules, errors } = await loadTrustedSchedules("/project")
+
+		expect(schedules.map(({ name }) => name)).toEqual(["good cron"])
+		expect(errors).toHaveLength(2)
+		expect(errors[0]).toContain("invalid JSON")
+		expect(errors[1]).toContain('"bad cron"')
+	})
+
+	it("rejects unknown auto-approval settings", async () => {
+		files["/project/.roo/schedules.json"] = {
+			schedules: [schedule("typo", { autoApprove: { alwaysAllowWrites: true } })],
+		}
+
+		const { schedules, errors } = await loadTrustedSchedules("/project")
+
+		expect(schedules).toEqual([])
+		expect(errors).toHaveLength(1)
+	})
+})
+
+describe("renderSchedulePrompt", () => {
+	it("fills in known variables and leaves the rest", () => {
+		const template = "Update the changelog for {{ date }} since {{lastRun}} ({{unknown}}, {{constructor}})"
+
+		expect(renderSchedulePrompt(template, { date: "2026-03-02", lastRun: "never" })).toBe(
+			"Update the changelog for 2026-03-02 since never ({{unknown}}, {{constructor}})",
+		)
+	})
+})
@@ -0,0 +1,129 @@
+/**
+ * A parsed five-field cron expression (minute, hour, day of month, month, day
+ * of week), evaluated in local time.
+ */
+export interface CronSchedule {
+	matches(date: Date): boolean
+}
+
+interface CronField {
+	name: string
+	min: number
+	max: number
+	aliases?: string[]
+}
+
+const FIELDS: CronField[] = [
+	{ name: "minute", min: 0, max: 59 },
+	{ name: "hour", min: 0, max: 23 },
+	{ name: "day of month", min: 1, max: 31 },
+	{
+		name: "month",
+		min: 1,
+		max: 12,
+		aliases: ["jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"],
+	},
+	// 7 is Sunday too.
+	{ name: "day of week", min: 0, max: 7, aliases: ["sun", "mon", "tue", "wed", "thu", "fri", "sat"] },
+]
+
+const MACROS: Record<string, string> = {
+	"@yearly": "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly": "0 0 1 * *",
+	"@weekly": "0 0 * * 0",
+	"@daily": "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly": "0 * * * *",
+}
+
+function parseValue(value: string, field: CronField): number {
+	const aliasIndex = field.aliases?.indexOf(value.toLowerCase()) ?? -1
+	const number = aliasIndex !== -1 ? aliasIndex + (field.name === "month" ? 1 : 0) : Number(value)
+
+	if (!/^\w+$/.test(value) || !Number.isInteger(number) || number < field.min || number > field.max) {
+		throw new Error(`Invalid ${field.name} "${value}" (expected ${field.min}-${field.max})`)
+	}
+
+	return number
+}
+
+/**
+ * Parses one field into the set of values it matches. Supports `*`, single
+ * values, ranges (`1-5`), steps (`*\/15`, `0-30/10`, `5/10`) and lists of these.
+ */
+function parseField(expression: string, field: CronField): Set<number> {
+	const values = new Set<number>()
+
+	for (const part of expression.split(",")) {
+		const [range, stepText, ...rest] = part.split("/")
+		const step = stepText === undefined ? 1 : Number(stepText)
+
+		if (rest.length > 0 || !range || !Number.isInteger(step) || step < 1) {
+			throw new Error(`Invalid ${field.name} "${part}"`)
+		}
+
+		let start = field.min
+		let end = field.max
+
+		if (range !== "*") {
+			const [from, to, ...extra] = range.split("-")
+
+			if (extra.length > 0) {
+				throw new Error(`Invalid ${field.name} "${part}"`)
+			}
+
+			start = parseValue(from, field)
+			end = to !== undefined ? parseValue(to, field) : stepText !== undefined ? field.max : start
+
+			if (end < start) {
+				throw new Error(`Invalid ${field.name} "${part}"`)
+			}
+		}
+
+		for (let value = start; value <= end; value += step) {
+			values.add(value)
+		}
+	}
+
+	return values
+}
+
+/**
+ * Parses a cron expression such as `0 9 * * 1-5` (9:00 on weekdays), or one of
+ * the macros `@hourly`, `@daily`, `@weekly`, `@monthly` and `@yearly`.
+ * Throws if the expression is invalid.
+ */
+export function parseCron(expression: string): CronSchedule {
+	const trimmed = expression.trim()
+	const parts = (MACROS[trimmed.toLowerCase()] ?? trimmed).split(/\s+/)
+
+	if (parts.length !== FIELDS.length) {
+		throw new Error(`Invalid cron expression "${expression}" (expected ${FIELDS.length} fields)`)
+	}
+
+	const [minutes, hours, daysOfMonth, months, daysOfWeek] = parts.map((part, index) =>
+		parseField(part, FIELDS[index]),
+	)
+
+	if (daysOfWeek.has(7)) {
+		daysOfWeek.add(0)
+	}
+
+	// Like cron, a day matches either field when both are restricted.
+	const isDayOfMonthRestricted = parts[2] !== "*"
+	const isDayOfWeekRestricted = parts[4] !== "*"
+
+	return {
+		matches(date: Date) {
+			const dayOfMonth = daysOfMonth.has(date.getDate())
+			const dayOfWeek = daysOfWeek.has(date.getDay())
+			const day =
+				isDayOfMonthRestricted && isDayOfWeekRestricted ? dayOfMonth || dayOfWeek : dayOfMonth && dayOfWeek
+
+			return (
+				minutes.has(date.getMinutes()) && hours.has(date.getHours()) && months.has(date.getMonth() + 1) && day
+			)
+		},
+	}
+}
@@ -0,0 +1,124 @@
+import * as crypto from "crypto"
+import * as path from "path"
+
+import { type ScheduledTask, type SchedulePromptVariable, schedulesFileSchema } from "@roo-code/types"
+
+import { getGlobalRooDirectory, getProjectRooDirectoryForCwd, readFileIfExists } from "../roo-config"
+import { parseCron } from "./cron"
+
+export const SCHEDULES_FILE_NAME = "schedules.json"
+
+export interface LoadedSchedules {
+	schedules: ScheduledTask[]
+	// One message per file or schedule that couldn't be loaded
+	errors: string[]
+	// The project's schedules file, when it has one that hasn't been trusted
+	untrustedProjectFile?: { path: string; hash: string }
+}
+
+export interface LoadSchedulesOptions {
+	// The hash of the project schedules file the user allowed to run, if any
+	trustedProjectFileHash?: string
+}
+
+async function readSchedulesFile(filePath: string, errors: string[]): Promise<string | null> {
+	try {
+		return await readFileIfExists(filePath)
+	} catch (error) {
+		errors.push(`${filePath}: ${error instanceof Error ? error.message : String(error)}`)
+		return null
+	}
+}
+
+function parseSchedulesFile(filePath: string, content: string, errors: string[]): ScheduledTask[] {
+	let json: unknown
+
+	try {
+		json = JSON.parse(content)
+	} catch (error) {
+		errors.push(`${filePath}: invalid JSON (${error instanceof Error ? error.message : String(error)})`)
+		return []
+	}
+
+	const result = schedulesFileSchema.safeParse(json)
+
+	if (!result.success) {
+		const issues = result.error.issues.map((issue) => `${issue.path.join(".")}: ${issue.message}`)
+		errors.push(`${filePath}: ${issues.join("; ")}`)
+		return []
+	}
+
+	return result.data.schedules.filter((schedule) => {
+		if (schedule.trigger.type !== "cron") {
+			return true
+		}
+
+		try {
+			parseCron(schedule.trigger.cron)
+			return true
+		} catch (error) {
+			errors.push(`${filePath}: "${schedule.name}": ${error instanceof Error ? error.message : String(error)}`)
+			return false
+		}
+	})
+}
+
+export function hashSchedulesFile(content: string): string {
+	return crypto.createHash("sha256").update(content).digest("hex")
+}
+
+/**
+ * Loads the scheduled tasks from `schedules.json` in the global `.roo`
+ * directory and in the project's. A project schedule replaces a global one
+ * with the same name.
+ *
+ * The project file comes with the repository, so its schedules (and their
+ * auto-approval) only load when its content matches the hash the user
+ * trusted; otherwise it's skipped and returned as `untrustedProjectFile`.
+ */
+export async function loadSchedules(cwd: string, options: LoadSchedulesOptions = {}): Promise<LoadedSchedules> {
+	const errors: string[] = []
+	const schedules = new Map<string, ScheduledTask>()
+	const globalFilePath = path.join(getGlobalRooDirectory(), SCHEDULES_FILE_NAME)
+	const projectFilePath = path.join(getProjectRooDirectoryForCwd(cwd), SCHEDULES_FILE_NAME)
+	let untrustedProjectFile: LoadedSchedules["untrustedProjectFile"]
+
+	const globalContent = await readSchedulesFile(globalFilePath, errors)
+
+	if (globalContent !== null) {
+		for (const schedule of parseSchedulesFile(globalFilePath, globalContent, errors)) {
+			schedules.set(schedule.name, schedule)
+		}
+	}
+
+	const projectContent = projectFilePath === globalFilePath ? null : await readSchedulesFile(projectFilePath, errors)
+
+	if (projectContent !== null) {
+		const hash = hashSchedulesFile(projectContent)
+
+		if (hash === options.trustedProjectFileHash) {
+			for (const schedule of parseSchedulesFile(projectFilePath, projectContent, errors)) {
+				schedules.set(schedule.name, schedule)
+			}
+		} else {
+			untrustedProjectFile = { path: projectFilePath, hash }
+		}
+	}
+
+	return { schedules: [...schedules.values()], errors, ...(untrustedProjectFile && { untrustedProjectFile }) }
+}
+
+/**
+ * Fills in the `{{variable}}` placeholders of a schedule's prompt. Unknown
+ * placeholders are left as they are.
+ */
+export function renderSchedulePrompt(
+	template: string,
+	variables: Partial<Record<SchedulePromptVariable, string>>,
+): string {
+	return template.replace(/\{\{\s*(\w+)\s*\}\}/g, (match, name: string) =>
+		Object.prototype.hasOwnProperty.call(variables, name)
+			? (variables[name as SchedulePromptVariable] ?? match)
+			: match,
+	)
+}
@@ -0,0 +1,52 @@
+export const sampleDart = String.raw`
+import 'dart:async';
+
+typedef UserCallback = void Function(User user);
+
+// Abstract class used as an interface
+abstract class UserRepository {
+  Future<User?> findById(String id);
+
+  Future<void> save(User user);
+}
+
+class User {
+  final String id;
+  final String name;
+
+  User(this.id, this.name);
+
+  factory User.guest() {
+    return User('guest', 'Guest');
+  }
+
+  String get displayName {
+    return name.isEmpty ? id : name;
+  }
+}
+
+enum Role {
+  admin,
+  editor,
+  viewer,
+}
+
+mixin Auditable {
+  final List<String> events = [];
+
+  void record(String event) {
+    events.add(event);
+  }
+}
+
+extension UserFormatting on User {
+  String describe() {
+    return '$displayName ($id)';
+  }
+}
+
+Future<User?> loadUser(UserRepository repository, String id) async {
+  final user = await repository.findById(id);
+  return user;
+}
+`
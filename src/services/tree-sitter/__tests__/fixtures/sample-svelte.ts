@@ -0,0 +1,29 @@
+export const sampleSvelte = String.raw`<script context="module" lang="ts">
+	export interface TodoItem {
+		id: number
+		title: string
+		done: boolean
+	}
+</script>
+
+<script lang="ts">
+	export let items: TodoItem[] = []
+
+	function toggle(item: TodoItem) {
+		item.done = !item.done
+		items = items
+	}
+</script>
+
+<ul>
+	{#each items as item (item.id)}
+		<li on:click={() => toggle(item)}>{item.title}</li>
+	{/each}
+</ul>
+
+<style>
+	li {
+		cursor: pointer;
+	}
+</style>
+`
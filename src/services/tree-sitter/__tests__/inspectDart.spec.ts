@@ -0,0 +1,19 @@
+import { testParseSourceCodeDefinitions, inspectTreeStructure } from "./helpers"
+import { sampleDart } from "./fixtures/sample-dart"
+import { dartQuery } from "../queries"
+
+describe("Dart Tree-sitter Parser", () => {
+	it("should inspect tree structure", async () => {
+		await inspectTreeStructure(sampleDart, "dart")
+	})
+
+	it("should parse source code definitions", async () => {
+		const result = await testParseSourceCodeDefinitions("file.dart", sampleDart, {
+			language: "dart",
+			wasmFile: "tree-sitter-dart.wasm",
+			queryString: dartQuery,
+			extKey: "dart",
+		})
+		expect(result).toBeDefined()
+	})
+})
@@ -49,6 +49,15 @@ describe("loadRequiredLanguageParsers", () => {
 		expect(parsers.kts.query).toBeDefined()
 	})
 
+	it("should handle Dart and Svelte files correctly", async () => {
+		const files = ["test.dart", "test.svelte"]
+		const parsers = await loadRequiredLanguageParsers(files, WASM_DIR)
+		expect(parsers.dart).toBeDefined()
+		expect(parsers.svelte).toBeDefined()
+		expect(parsers.dart.query).toBeDefined()
+		expect(parsers.svelte.query).toBeDefined()
+	})
+
 	it("should throw error for unsupported file extensions", async () => {
 		const files = ["test.unsupported"]
 		await expect(loadRequiredLanguageParsers(files, WASM_DIR)).rejects.toThrow("Unsupported language: unsupported")
@@ -0,0 +1,40 @@
+import { testParseSourceCodeDefinitions } from "./helpers"
+import { sampleDart } from "./fixtures/sample-dart"
+import { dartQuery } from "../queries"
+
+describe("Dart Source Code Definition Tests", () => {
+	let parseResult: string
+
+	beforeAll(async () => {
+		const result = await testParseSourceCodeDefinitions("file.dart", sampleDart, {
+			language: "dart",
+			wasmFile: "tree-sitter-dart.wasm",
+			queryString: dartQuery,
+			extKey: "dart",
+		})
+		expect(result).toBeDefined()
+		expect(typeof result).toBe("string")
+		parseResult = result as string
+	})
+
+	it("should parse class and interface definitions", () => {
+		expect(parseResult).toMatch(/\d+--\d+ \| abstract class UserRepository/)
+		expect(parseResult).toMatch(/\d+--\d+ \| class User/)
+	})
+
+	it("should parse function and method definitions", () => {
+		expect(parseResult).toMatch(/\d+--\d+ \| Future<User\?> loadUser\(UserRepository repository, String id\) async/)
+		expect(parseResult).toMatch(/\d+--\d+ \|   factory User\.guest\(\)/)
+		expect(parseResult).toMatch(/\d+--\d+ \|   String get displayName/)
+	})
+
+	it("should parse enum, mixin and extension definitions", () => {
+		expect(parseResult).toMatch(/\d+--\d+ \| enum Role/)
+		expect(parseResult).toMatch(/\d+--\d+ \| mixin Auditable/)
+		expect(parseResult).toMatch(/\d+--\d+ \| extension UserFormatting on User/)
+	})
+
+	it("should parse type aliases", () => {
+		expect(parseResult).toMatch(/\d+--\d+ \| typedef UserCallback/)
+	})
+})
@@ -0,0 +1,56 @@
+// npx vitest services/tree-sitter/__tests__/svelte.spec.ts
+
+import * as path from "path"
+import { Parser, Language, Query } from "web-tree-sitter"
+
+import { findScriptRanges, restrictToScriptBlocks } from "../svelte"
+import { typescriptQuery } from "../queries"
+import { sampleSvelte } from "./fixtures/sample-svelte"
+import { initializeTreeSitter } from "./helpers"
+
+describe("findScriptRanges", () => {
+	it("returns the contents of every script block", () => {
+		const ranges = findScriptRanges(sampleSvelte)
+
+		expect(ranges).toHaveLength(2)
+		expect(ranges.map((range) => range.startPosition)).toEqual([
+			{ row: 0, column: 35 },
+			{ row: 8, column: 18 },
+		])
+		expect(sampleSvelte.slice(ranges[1].startIndex, ranges[1].endIndex)).toContain("function toggle")
+	})
+
+	it("returns no ranges for markup-only components", () => {
+		expect(findScriptRanges("<h1>Hello</h1>\n<style>h1 { color: red; }</style>\n")).toEqual([])
+	})
+})
+
+describe("restrictToScriptBlocks", () => {
+	let parser: Parser
+	let query: Query
+
+	beforeAll(async () => {
+		await initializeTreeSitter()
+		const language = await Language.load(path.join(process.cwd(), "dist/tree-sitter-typescript.wasm"))
+		parser = restrictToScriptBlocks(new Parser())
+		parser.setLanguage(language)
+		query = new Query(language, typescriptQuery)
+	})
+
+	it("captures script definitions at their line in the component", () => {
+		const tree = parser.parse(sampleSvelte)!
+		const names = query
+			.captures(tree.rootNode)
+			.filter((capture) => capture.name.startsWith("name"))
+			.map((capture) => [capture.node.text, capture.node.startPosition.row])
+
+		expect(names).toContainEqual(["TodoItem", 1])
+		expect(names).toContainEqual(["toggle", 11])
+	})
+
+	it("parses nothing for components without scripts", () => {
+		const tree = parser.parse("<h1>Hello</h1>\n")!
+
+		expect(query.captures(tree.rootNode)).toEqual([])
+	})
+})
@@ -85,6 +85,17 @@ const extensions = [
 	"zig",
 	// Elm
 	"elm",
+	// Dart
+	"dart",
+	// Svelte
+	"svelte",
+	// Erlang
+	"erl",
+	"hrl",
+	// Terraform / HCL
+	"tf",
+	"tfvars",
+	"hcl",
 	// Embedded Template
 	"ejs",
 	"erb",
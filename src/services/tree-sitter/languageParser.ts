@@ -28,7 +28,9 @@ import {
 	embeddedTemplateQuery,
 	elispQuery,
 	elixirQuery,
+	dartQuery,
 } from "./queries"
+import { restrictToScriptBlocks } from "./svelte"
 
 export interface LanguageParser {
 	[key: string]: {
@@ -218,12 +220,24 @@ export async function loadRequiredLanguageParsers(filesToParse: string[], source
 				language = await loadLanguage("elixir", sourceDirectory)
 				query = new Query(language, elixirQuery)
 				break
+			case "dart":
+				language = await loadLanguage("dart", sourceDirectory)
+				query = new Query(language, dartQuery)
+				break
+			case "svelte":
+				// Svelte has no grammar; its <script> blocks are parsed as TypeScript
+				language = await loadLanguage("typescript", sourceDirectory)
+				query = new Query(language, typescriptQuery)
+				break
 			default:
 				throw new Error(`Unsupported language: ${ext}`)
 		}
 
 		const parser = new Parser()
 		parser.setLanguage(language)
+		if (ext === "svelte") {
+			restrictToScriptBlocks(parser)
+		}
 		parsers[parserKey] = { parser, query }
 	}
 
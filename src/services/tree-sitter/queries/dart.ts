@@ -0,0 +1,42 @@
+/*
+Dart language structures for tree-sitter parsing
+
+Function and method bodies are siblings of their signatures rather than children,
+so the bodies are captured separately for the code index.
+*/
+export const dartQuery = String.raw`
+; Classes, including abstract classes used as interfaces
+(class_definition
+  name: (identifier) @name.definition.class) @definition.class
+
+; Mixins
+(mixin_declaration
+  (identifier) @name.definition.mixin) @definition.mixin
+
+; Extensions
+(extension_declaration
+  name: (identifier) @name.definition.extension) @definition.extension
+
+; Enums
+(enum_declaration
+  name: (identifier) @name.definition.enum) @definition.enum
+
+; Type aliases
+(type_alias
+  (type_identifier) @name.definition.type) @definition.type
+
+; Top-level functions
+(function_signature
+  name: (identifier) @name.definition.function) @definition.function
+
+((function_signature)
+  .
+  (function_body) @function.body)
+
+; Methods, constructors, getters and setters
+(method_signature) @definition.method
+
+((method_signature)
+  .
+  (function_body) @method.body)
+`
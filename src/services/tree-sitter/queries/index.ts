@@ -26,3 +26,4 @@ export { zigQuery } from "./zig"
 export { default as embeddedTemplateQuery } from "./embedded_template"
 export { elispQuery } from "./elisp"
 export { scalaQuery } from "./scala"
+export { dartQuery } from "./dart"
@@ -0,0 +1,48 @@
+import type { Parser as ParserT, Range } from "web-tree-sitter"
+
+const SCRIPT_BLOCK_REGEX = /<script\b[^>]*>([\s\S]*?)<\/script>/gi
+
+/**
+ * Finds the contents of the `<script>` blocks of a Svelte component
+ *
+ * @param content - Component source
+ * @returns Ranges of the script contents, in tree-sitter coordinates
+ */
+export function findScriptRanges(content: string): Range[] {
+	const position = (index: number) => {
+		const before = content.slice(0, index)
+		const row = before.split("\n").length - 1
+		return { row, column: index - (before.lastIndexOf("\n") + 1) }
+	}
+
+	return Array.from(content.matchAll(SCRIPT_BLOCK_REGEX), (match) => {
+		const startIndex = match.index! + match[0].indexOf(">") + 1
+		const endIndex = startIndex + match[1].length
+		return { startIndex, endIndex, startPosition: position(startIndex), endPosition: position(endIndex) }
+	})
+}
+
+/**
+ * Restricts a parser to the `<script>` blocks of Svelte components.
+ *
+ * Svelte has no tree-sitter grammar of its own, but component logic lives in script blocks
+ * written in JavaScript or TypeScript. Parsing only those ranges with the TypeScript grammar
+ * keeps node positions relative to the whole file, so captures map to the right lines.
+ *
+ * @param parser - Parser configured with the TypeScript grammar
+ * @returns The same parser
+ */
+export function restrictToScriptBlocks(parser: ParserT): ParserT {
+	const parse = parser.parse.bind(parser)
+
+	parser.parse = (input, oldTree, options) => {
+		if (typeof input !== "string") {
+			return parse(input, oldTree, options)
+		}
+		const includedRanges = findScriptRanges(input)
+		// An empty range list would parse the whole file, markup included
+		return includedRanges.length > 0 ? parse(input, oldTree, { ...options, includedRanges }) : parse("")
+	}
+
+	return parser
+}
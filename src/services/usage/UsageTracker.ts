@@ -0,0 +1,167 @@
+import * as path from "path"
+import fs from "fs/promises"
+
+import {
+	type UsageCounter,
+	type UsageCounters,
+	type UsageEntry,
+	USAGE_COUNTERS,
+	usageEntrySchema,
+} from "@roo-code/types"
+import { safeJsonParse } from "@roo-code/core"
+
+import { GlobalFileNames } from "../../shared/globalFileNames"
+
+export type UsageKey = Pick<UsageEntry, "workspace" | "mode" | "provider" | "modelId">
+
+// Usage is held this long before it's appended to the log, so the updates of
+// a streaming request are written together.
+const FLUSH_DELAY_MS = 5_000
+
+const CSV_COLUMNS = ["date", "workspace", "mode", "provider", "modelId", ...USAGE_COUNTERS] as const
+
+export const emptyUsageCounters = () =>
+	Object.fromEntries(USAGE_COUNTERS.map((counter) => [counter, 0])) as UsageCounters
+
+/**
+ * The local day of a timestamp, as YYYY-MM-DD.
+ */
+export function getUsageDate(ts: number): string {
+	const date = new Date(ts)
+	const pad = (value: number) => String(value).padStart(2, "0")
+	return `${date.getFullYear()}-${pad(date.getMonth() + 1)}-${pad(date.getDate())}`
+}
+
+const getEntryKey = (entry: Omit<UsageEntry, UsageCounter>) =>
+	[entry.date, entry.workspace, entry.mode, entry.provider, entry.modelId].join("\n")
+
+/**
+ * Adds up the entries of the same day, workspace, mode and model.
+ *
+ * @returns the merged entries, oldest first
+ */
+export function mergeUsageEntries(entries: UsageEntry[]): UsageEntry[] {
+	const merged = new Map<string, UsageEntry>()
+
+	for (const entry of entries) {
+		const key = getEntryKey(entry)
+		const existing = merged.get(key)
+
+		if (existing) {
+			for (const counter of USAGE_COUNTERS) {
+				existing[counter] += entry[counter]
+			}
+		} else {
+			merged.set(key, { ...entry })
+		}
+	}
+
+	return [...merged.values()].sort((a, b) => a.date.localeCompare(b.date))
+}
+
+const formatCsvValue = (value: string | number) => {
+	// Costs are rounded to a millionth of a dollar, which hides floating-point noise.
+	const text = typeof value === "number" ? String(Math.round(value * 1_000_000) / 1_000_000) : value
+	return /[",\r\n]/.test(text) ? `"${text.replace(/"/g, '""')}"` : text
+}
+
+/**
+ * Formats usage entries as CSV, with a header row.
+ */
+export function formatUsageCsv(entries: UsageEntry[]): string {
+	const rows = entries.map((entry) => CSV_COLUMNS.map((column) => formatCsvValue(entry[column])).join(","))
+	return [CSV_COLUMNS.join(","), ...rows].join("\n") + "\n"
+}
+
+/**
+ * Keeps a local log of the API usage of all tasks, per day, workspace, mode
+ * and model, in `globalStorage/usage_log.jsonl`.
+ *
+ * The log is only ever appended to, so windows that share the global storage
+ * don't overwrite each other's usage; entries are added up when it's read.
+ */
+export class UsageTracker {
+	private pending = new Map<string, UsageEntry>()
+	private flushTimer?: NodeJS.Timeout
+	private writing: Promise<void> = Promise.resolve()
+
+	constructor(private readonly globalStoragePath: string) {}
+
+	private get logFilePath() {
+		return path.join(this.globalStoragePath, GlobalFileNames.usageLog)
+	}
+
+	/**
+	 * Adds usage to the day of `ts`.
+	 */
+	record(ts: number, key: UsageKey, usage: Partial<UsageCounters>): void {
+		const entry: UsageEntry = { date: getUsageDate(ts), ...key, ...emptyUsageCounters() }
+		const entryKey = getEntryKey(entry)
+		const pending = this.pending.get(entryKey) ?? entry
+
+		for (const counter of USAGE_COUNTERS) {
+			pending[counter] += usage[counter] ?? 0
+		}
+
+		this.pending.set(entryKey, pending)
+		this.flushTimer ??= setTimeout(() => this.flush(), FLUSH_DELAY_MS)
+	}
+
+	/**
+	 * Appends the pending usage to the log.
+	 */
+	flush(): Promise<void> {
+		clearTimeout(this.flushTimer)
+		this.flushTimer = undefined
+
+		const entries = [...this.pending.values()].filter((entry) => USAGE_COUNTERS.some((counter) => entry[counter]))
+		this.pending.clear()
+
+		if (entries.length === 0) {
+			return this.writing
+		}
+
+		const lines = entries.map((entry) => `${JSON.stringify(entry)}\n`).join("")
+
+		this.writing = this.writing.then(async () => {
+			try {
+				await fs.mkdir(this.globalStoragePath, { recursive: true })
+				await fs.appendFile(this.logFilePath, lines)
+			} catch (error) {
+				console.error(
+					`[UsageTracker] failed to write the usage log: ${error instanceof Error ? error.message : String(error)}`,
+				)
+			}
+		})
+
+		return this.writing
+	}
+
+	/**
+	 * All recorded usage, one entry per day, workspace, mode and model, oldest
+	 * first.
+	 */
+	async getEntries(): Promise<UsageEntry[]> {
+		await this.flush()
+
+		let content: string
+
+		try {
+			content = await fs.readFile(this.logFilePath, "utf8")
+		} catch {
+			return []
+		}
+
+		// Lines that can't be read, such as one cut short by a crash, are skipped.
+		const entries = content.split("\n").flatMap((line) => {
+			const result = usageEntrySchema.safeParse(safeJsonParse(line.trim()))
+			return result.success ? [result.data] : []
+		})
+
+		return mergeUsageEntries(entries)
+	}
+
+	dispose(): Promise<void> {
+		return this.flush()
+	}
+}
@@ -0,0 +1,117 @@
+// npx vitest run src/services/usage/__tests__/UsageTracker.spec.ts
+
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+
+import type { UsageEntry } from "@roo-code/types"
+
+import { UsageTracker, formatUsageCsv, getUsageDate, mergeUsageEntries } from "../UsageTracker"
+
+const key = { workspace: "/projects/app", mode: "code", provider: "anthropic", modelId: "claude-sonnet" }
+
+const entry = (overrides: Partial<UsageEntry>): UsageEntry => ({
+	date: "2026-10-01",
+	...key,
+	requests: 1,
+	tokensIn: 100,
+	tokensOut: 10,
+	cacheWrites: 0,
+	cacheReads: 0,
+	cacheSavings: 0,
+	cost: 0.01,
+	...overrides,
+})
+
+describe("UsageTracker", () => {
+	let globalStoragePath: string
+	let tracker: UsageTracker
+
+	beforeEach(async () => {
+		globalStoragePath = await fs.mkdtemp(path.join(os.tmpdir(), "usage-tracker-"))
+		tracker = new UsageTracker(globalStoragePath)
+	})
+
+	afterEach(async () => {
+		vi.restoreAllMocks()
+		await tracker.dispose()
+		await fs.rm(globalStoragePath, { recursive: true, force: true })
+	})
+
+	it("adds up the usage recorded for the same day, workspace, mode and model", async () => {
+		const ts = new Date(2026, 9, 1, 12).getTime()
+		tracker.record(ts, key, { requests: 1, tokensIn: 100, cost: 0.01 })
+		tracker.record(ts, key, { tokensIn: 50, tokensOut: 20, cost: 0.005 })
+		tracker.record(ts, { ...key, mode: "architect" }, { requests: 1, tokensIn: 10 })
+
+		const entries = await tracker.getEntries()
+
+		expect(entries).toHaveLength(2)
+		expect(entries.find(({ mode }) => mode === "code")).toMatchObject({
+			date: "2026-10-01",
+			requests: 1,
+			tokensIn: 150,
+			tokensOut: 20,
+		})
+		expect(entries.find(({ mode }) => mode === "code")?.cost).toBeCloseTo(0.015)
+	})
+
+	it("appends to the log, so other windows' usage is kept", async () => {
+		const ts = new Date(2026, 9, 1).getTime()
+		const otherWindow = new UsageTracker(globalStoragePath)
+		otherWindow.record(ts, key, { requests: 2, tokensIn: 200 })
+		await otherWindow.flush()
+
+		tracker.record(ts, key, { requests: 1, tokensIn: 100 })
+
+		expect(await tracker.getEntries()).toEqual([
+			expect.objectContaining({ date: "2026-10-01", requests: 3, tokensIn: 300 }),
+		])
+	})
+
+	it("skips lines that can't be read", async () => {
+		const logFilePath = path.join(globalStoragePath, "usage_log.jsonl")
+		await fs.writeFile(logFilePath, `${JSON.stringify(entry({}))}\n{"date":"2026-10-02","requ\n`)
+		vi.spyOn(console, "error").mockImplementation(() => {})
+
+		expect(await tracker.getEntries()).toEqual([entry({})])
+	})
+
+	it("returns no entries without a log", async () => {
+		expect(await tracker.getEntries()).toEqual([])
+	})
+})
+
+describe("getUsageDate", () => {
+	it("returns the local day", () => {
+		expect(getUsageDate(new Date(2026, 0, 5, 23, 59).getTime())).toBe("2026-01-05")
+	})
+})
+
+describe("mergeUsageEntries", () => {
+	it("merges entries of the same bucket and sorts them by date", () => {
+		const merged = mergeUsageEntries([
+			entry({ date: "2026-10-02" }),
+			entry({ date: "2026-10-01" }),
+			entry({ date: "2026-10-02", requests: 2 }),
+		])
+
+		expect(merged.map(({ date, requests }) => [date, requests])).toEqual([
+			["2026-10-01", 1],
+			["2026-10-02", 3],
+		])
+	})
+})
+
+describe("formatUsageCsv", () => {
+	it("writes a header and one row per entry", () => {
+		expect(formatUsageCsv([entry({ cost: 0.1 + 0.2 })])).toBe(
+			"date,workspace,mode,provider,modelId,requests,tokensIn,tokensOut,cacheWrites,cacheReads,cacheSavings,cost\n" +
+				"2026-10-01,/projects/app,code,anthropic,claude-sonnet,1,100,10,0,0,0,0.3\n",
+		)
+	})
+
+	it("quotes values with commas and quotes", () => {
+		expect(formatUsageCsv([entry({ workspace: 'C:\\my "app", v2' })])).toContain('"C:\\my ""app"", v2"')
+	})
+})
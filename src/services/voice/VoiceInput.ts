@@ -0,0 +1,78 @@
+import type { ProviderSettings, VoiceInputState } from "@roo-code/types"
+
+import { VoiceRecorder } from "./VoiceRecorder"
+import { transcribeAudio } from "./transcribe"
+
+/**
+ * Push-to-talk: records while the chat's microphone button is held and
+ * transcribes the recording when it's released.
+ */
+export class VoiceInput {
+	private recorder?: VoiceRecorder
+	private starting?: Promise<void>
+
+	constructor(private readonly onStateChange: (state: VoiceInputState) => void) {}
+
+	async start(): Promise<void> {
+		if (this.recorder) {
+			return
+		}
+
+		const recorder = new VoiceRecorder()
+		this.recorder = recorder
+		this.starting = recorder.start()
+
+		try {
+			await this.starting
+			this.onStateChange("recording")
+		} catch (error) {
+			await this.reset(recorder)
+			throw error
+		}
+	}
+
+	/**
+	 * Stops recording.
+	 *
+	 * @returns the transcript, or undefined when nothing was recorded
+	 */
+	async stop(settings: ProviderSettings): Promise<string | undefined> {
+		const recorder = this.recorder
+
+		if (!recorder) {
+			return undefined
+		}
+
+		// The button can be released before ffmpeg started.
+		try {
+			await this.starting
+		} catch {
+			// `start()` reports the failure.
+			return undefined
+		}
+
+		try {
+			this.onStateChange("transcribing")
+			const filePath = await recorder.stop()
+			return await transcribeAudio(filePath, settings)
+		} finally {
+			await this.reset(recorder)
+		}
+	}
+
+	async cancel(): Promise<void> {
+		if (this.recorder) {
+			await this.reset(this.recorder)
+		}
+	}
+
+	private async reset(recorder: VoiceRecorder): Promise<void> {
+		if (this.recorder === recorder) {
+			this.recorder = undefined
+			this.starting = undefined
+			this.onStateChange("idle")
+		}
+
+		await recorder.dispose()
+	}
+}
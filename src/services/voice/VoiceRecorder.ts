@@ -0,0 +1,132 @@
+import * as childProcess from "child_process"
+import * as os from "os"
+import * as path from "path"
+import fs from "fs/promises"
+
+// Whisper models expect 16 kHz mono audio.
+const OUTPUT_ARGS = ["-ac", "1", "-ar", "16000", "-y"]
+
+// A recording whose button is never released stops on its own.
+const MAX_RECORDING_SECONDS = 300
+
+const FFMPEG_NOT_FOUND = "Voice input records with ffmpeg, which was not found on the PATH"
+
+/**
+ * The first audio input device DirectShow lists, since it has no default
+ * device to record from.
+ */
+async function getWindowsAudioDevice(): Promise<string> {
+	const output = await new Promise<string>((resolve, reject) => {
+		// ffmpeg lists the devices on stderr and exits with an error, as "dummy" isn't a device.
+		childProcess.execFile(
+			"ffmpeg",
+			["-hide_banner", "-list_devices", "true", "-f", "dshow", "-i", "dummy"],
+			(error: NodeJS.ErrnoException | null, _stdout, stderr) =>
+				error?.code === "ENOENT" ? reject(new Error(FFMPEG_NOT_FOUND)) : resolve(stderr),
+		)
+	})
+
+	const device = output.match(/"([^"]+)"\s+\(audio\)/)?.[1]
+
+	if (!device) {
+		throw new Error("No microphone was found")
+	}
+
+	return device
+}
+
+/**
+ * The ffmpeg arguments that record from the default microphone.
+ */
+export async function getInputArgs(platform: NodeJS.Platform = process.platform): Promise<string[]> {
+	switch (platform) {
+		case "darwin":
+			return ["-f", "avfoundation", "-i", ":0"]
+		case "win32":
+			return ["-f", "dshow", "-i", `audio=${await getWindowsAudioDevice()}`]
+		default:
+			// PulseAudio, which PipeWire also provides.
+			return ["-f", "pulse", "-i", "default"]
+	}
+}
+
+/**
+ * Records the microphone to a WAV file with ffmpeg, which has to be on the
+ * PATH. Webviews can't access the microphone, so this runs in the extension
+ * host.
+ */
+export class VoiceRecorder {
+	private process?: childProcess.ChildProcess
+	private exited?: Promise<void>
+	private tmpDir?: string
+
+	async start(): Promise<void> {
+		this.tmpDir = await fs.mkdtemp(path.join(os.tmpdir(), "roo-voice-"))
+		const filePath = path.join(this.tmpDir, "recording.wav")
+		const args = [
+			"-hide_banner",
+			"-loglevel",
+			"error",
+			...(await getInputArgs()),
+			"-t",
+			String(MAX_RECORDING_SECONDS),
+			...OUTPUT_ARGS,
+			filePath,
+		]
+
+		const recorder = childProcess.spawn("ffmpeg", args, { stdio: ["pipe", "ignore", "pipe"] })
+		let stderr = ""
+		recorder.stderr?.on("data", (data) => (stderr += data.toString()))
+		// Writing to ffmpeg after it failed would throw an EPIPE otherwise.
+		recorder.stdin?.on("error", () => {})
+
+		this.exited = new Promise<void>((resolve, reject) => {
+			recorder.once("error", reject)
+			recorder.once("close", (code) =>
+				code === 0 ? resolve() : reject(new Error(stderr.trim() || `ffmpeg exited with code ${code}`)),
+			)
+		})
+
+		// Failures before the recording is stopped are reported by `stop()`.
+		this.exited.catch(() => {})
+
+		await new Promise<void>((resolve, reject) => {
+			recorder.once("spawn", resolve)
+			recorder.once("error", (error: NodeJS.ErrnoException) =>
+				reject(error.code === "ENOENT" ? new Error(FFMPEG_NOT_FOUND) : error),
+			)
+		})
+
+		this.process = recorder
+	}
+
+	/**
+	 * Stops recording.
+	 *
+	 * @returns the path of the recording, which `dispose()` deletes
+	 */
+	async stop(): Promise<string> {
+		if (!this.process || !this.tmpDir) {
+			throw new Error("Not recording")
+		}
+
+		// "q" makes ffmpeg finish writing the file before it exits.
+		this.process.stdin?.end("q")
+		await this.exited
+
+		return path.join(this.tmpDir, "recording.wav")
+	}
+
+	async dispose(): Promise<void> {
+		if (this.process && this.process.exitCode === null) {
+			this.process.kill()
+		}
+
+		this.process = undefined
+
+		if (this.tmpDir) {
+			await fs.rm(this.tmpDir, { recursive: true, force: true })
+			this.tmpDir = undefined
+		}
+	}
+}
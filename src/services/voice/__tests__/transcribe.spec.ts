@@ -0,0 +1,80 @@
+import * as childProcess from "child_process"
+import OpenAI from "openai"
+
+import { transcribeAudio } from "../transcribe"
+
+vi.mock("child_process", () => ({
+	execFile: vi.fn(),
+}))
+
+vi.mock("fs", () => ({
+	default: { createReadStream: vi.fn((filePath: string) => ({ path: filePath })) },
+}))
+
+const mockCreateTranscription = vi.fn()
+
+vi.mock("openai", () => ({
+	default: vi.fn().mockImplementation(() => ({
+		audio: { transcriptions: { create: mockCreateTranscription } },
+	})),
+}))
+
+describe("transcribeAudio", () => {
+	beforeEach(() => {
+		vi.clearAllMocks()
+	})
+
+	it("transcribes with the OpenAI endpoint of the profile", async () => {
+		mockCreateTranscription.mockResolvedValue({ text: " Run the tests \n" })
+
+		const transcript = await transcribeAudio("/tmp/recording.wav", {
+			apiProvider: "openai-native",
+			openAiNativeApiKey: "sk-test",
+			voiceInputProvider: "provider",
+			voiceInputLanguage: "en",
+		})
+
+		expect(transcript).toBe("Run the tests")
+		expect(OpenAI).toHaveBeenCalledWith(expect.objectContaining({ apiKey: "sk-test" }))
+		expect(mockCreateTranscription).toHaveBeenCalledWith({
+			file: { path: "/tmp/recording.wav" },
+			model: "whisper-1",
+			language: "en",
+		})
+	})
+
+	it("rejects providers without a speech to text endpoint", async () => {
+		await expect(
+			transcribeAudio("/tmp/recording.wav", { apiProvider: "anthropic", voiceInputProvider: "provider" }),
+		).rejects.toThrow("anthropic provider doesn't support speech to text")
+	})
+
+	it("transcribes locally with whisper.cpp", async () => {
+		vi.mocked(childProcess.execFile).mockImplementation(((_command: string, _args: string[], callback: any) => {
+			callback(null, "\n Run the\n tests\n", "")
+		}) as any)
+
+		const transcript = await transcribeAudio("/tmp/recording.wav", {
+			voiceInputProvider: "local",
+			voiceInputLocalModelPath: "/models/ggml-base.en.bin",
+		})
+
+		expect(transcript).toBe("Run the tests")
+		expect(childProcess.execFile).toHaveBeenCalledWith(
+			"whisper-cli",
+			["-m", "/models/ggml-base.en.bin", "-f", "/tmp/recording.wav", "-nt", "-np"],
+			expect.any(Function),
+		)
+	})
+
+	it("requires a model file to transcribe locally", async () => {
+		await expect(transcribeAudio("/tmp/recording.wav", { voiceInputProvider: "local" })).rejects.toThrow(
+			"Set the Whisper model file",
+		)
+		expect(childProcess.execFile).not.toHaveBeenCalled()
+	})
+
+	it("rejects when voice input isn't enabled", async () => {
+		await expect(transcribeAudio("/tmp/recording.wav", {})).rejects.toThrow("Voice input is not enabled")
+	})
+})
@@ -0,0 +1,94 @@
+import * as childProcess from "child_process"
+import fs from "fs"
+import OpenAI from "openai"
+
+import {
+	type ProviderSettings,
+	DEFAULT_VOICE_INPUT_MODEL_ID,
+	DEFAULT_WHISPER_COMMAND,
+} from "@roo-code/types"
+
+import { DEFAULT_HEADERS } from "../../api/providers/constants"
+
+/**
+ * The speech to text client of the profile's provider. Only OpenAI's
+ * transcriptions endpoint is supported, which OpenAI Compatible servers
+ * often implement as well.
+ */
+function createTranscriptionClient(settings: ProviderSettings): OpenAI {
+	switch (settings.apiProvider) {
+		case "openai-native":
+			return new OpenAI({
+				baseURL: settings.openAiNativeBaseUrl || undefined,
+				apiKey: settings.openAiNativeApiKey ?? "not-provided",
+				defaultHeaders: DEFAULT_HEADERS,
+			})
+		case "openai":
+			return new OpenAI({
+				baseURL: settings.openAiBaseUrl || "https://api.openai.com/v1",
+				apiKey: settings.openAiApiKey ?? "not-provided",
+				defaultHeaders: { ...DEFAULT_HEADERS, ...(settings.openAiHeaders || {}) },
+			})
+		default:
+			throw new Error(
+				`${settings.apiProvider ?? "This"} provider doesn't support speech to text, use a local Whisper model instead`,
+			)
+	}
+}
+
+async function transcribeWithProvider(filePath: string, settings: ProviderSettings): Promise<string> {
+	const client = createTranscriptionClient(settings)
+
+	const transcription = await client.audio.transcriptions.create({
+		file: fs.createReadStream(filePath),
+		model: settings.voiceInputModelId || DEFAULT_VOICE_INPUT_MODEL_ID,
+		...(settings.voiceInputLanguage ? { language: settings.voiceInputLanguage } : {}),
+	})
+
+	return transcription.text
+}
+
+async function transcribeLocally(filePath: string, settings: ProviderSettings): Promise<string> {
+	if (!settings.voiceInputLocalModelPath) {
+		throw new Error("Set the Whisper model file to transcribe voice input locally")
+	}
+
+	const command = settings.voiceInputLocalCommand || DEFAULT_WHISPER_COMMAND
+
+	// -nt and -np make whisper.cpp print only the text, without timestamps or progress.
+	const args = [
+		"-m",
+		settings.voiceInputLocalModelPath,
+		"-f",
+		filePath,
+		"-nt",
+		"-np",
+		...(settings.voiceInputLanguage ? ["-l", settings.voiceInputLanguage] : []),
+	]
+
+	return new Promise<string>((resolve, reject) => {
+		childProcess.execFile(command, args, (error: NodeJS.ErrnoException | null, stdout, stderr) => {
+			if (error?.code === "ENOENT") {
+				reject(new Error(`${command} was not found on the PATH`))
+			} else if (error) {
+				reject(new Error(stderr.trim() || error.message))
+			} else {
+				resolve(stdout)
+			}
+		})
+	})
+}
+
+/**
+ * Turns a recording into text with the voice input settings of a profile.
+ */
+export async function transcribeAudio(filePath: string, settings: ProviderSettings): Promise<string> {
+	switch (settings.voiceInputProvider) {
+		case "provider":
+			return (await transcribeWithProvider(filePath, settings)).trim()
+		case "local":
+			return (await transcribeLocally(filePath, settings)).replace(/\s+/g, " ").trim()
+		default:
+			throw new Error("Voice input is not enabled for this profile")
+	}
+}
@@ -115,4 +115,14 @@ export function calculateApiCostOpenAI(
 	)
 }
 
+// What reading tokens from the prompt cache saved compared to sending them as
+// uncached input tokens.
+export function calculateCacheSavings(modelInfo: ModelInfo, cacheReadInputTokens?: number): number {
+	if (modelInfo.inputPrice === undefined || modelInfo.cacheReadsPrice === undefined) {
+		return 0
+	}
+
+	return (Math.max(0, modelInfo.inputPrice - modelInfo.cacheReadsPrice) / 1_000_000) * (cacheReadInputTokens || 0)
+}
+
 export const parseApiPrice = (price: any) => (price ? parseFloat(price) * 1_000_000 : undefined)
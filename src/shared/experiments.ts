@@ -5,6 +5,10 @@ export const EXPERIMENT_IDS = {
 	IMAGE_GENERATION: "imageGeneration",
 	RUN_SLASH_COMMAND: "runSlashCommand",
 	CUSTOM_TOOLS: "customTools",
+	DRY_RUN_EDITS: "dryRunEdits",
+	FAST_APPLY: "fastApply",
+	PARALLEL_SUBTASKS: "parallelSubtasks",
+	SCHEDULED_TASKS: "scheduledTasks",
 } as const satisfies Record<string, ExperimentId>
 
 type _AssertExperimentIds = AssertEqual<Equals<ExperimentId, Values<typeof EXPERIMENT_IDS>>>
@@ -20,6 +24,10 @@ export const experimentConfigsMap: Record<ExperimentKey, ExperimentConfig> = {
 	IMAGE_GENERATION: { enabled: false },
 	RUN_SLASH_COMMAND: { enabled: false },
 	CUSTOM_TOOLS: { enabled: false },
+	DRY_RUN_EDITS: { enabled: false },
+	FAST_APPLY: { enabled: false },
+	PARALLEL_SUBTASKS: { enabled: false },
+	SCHEDULED_TASKS: { enabled: false },
 }
 
 export const experimentDefault = Object.fromEntries(
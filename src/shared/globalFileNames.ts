@@ -6,4 +6,6 @@ export const GlobalFileNames = {
 	taskMetadata: "task_metadata.json",
 	historyItem: "history_item.json",
 	historyIndex: "_index.json",
+	providerTraffic: "provider_traffic.jsonl",
+	usageLog: "usage_log.jsonl",
 }
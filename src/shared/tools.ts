@@ -51,6 +51,9 @@ export const toolParamNames = [
 	"task",
 	"size",
 	"query",
+	"language", // codebase_search filter parameters
+	"kind",
+	"symbol", // find_references parameter
 	"args",
 	"skill", // skill tool parameter
 	"start_line",
@@ -81,6 +84,19 @@ export const toolParamNames = [
 	// read_file legacy format parameter (backward compatibility)
 	"files",
 	"line_ranges",
+	// git parameters
+	"branch",
+	"title",
+	"body",
+	"base",
+	"process_id", // background_process parameter
+	"instructions", // fast_edit parameter
+	"code_edit", // fast_edit parameter
+	"cell_index", // edit_notebook parameter
+	"new_source", // edit_notebook parameter
+	"cell_type", // edit_notebook parameter
+	"edit_mode", // edit_notebook parameter
+	"tasks", // run_parallel_tasks parameter
 ] as const
 
 export type ToolParamName = (typeof toolParamNames)[number]
@@ -95,7 +111,31 @@ export type NativeToolArgs = {
 	read_command_output: { artifact_id: string; search?: string; offset?: number; limit?: number }
 	attempt_completion: { result: string }
 	execute_command: { command: string; cwd?: string; timeout?: number | null }
-	apply_diff: { path: string; diff: string }
+	background_process: {
+		action: "start" | "list" | "read_output" | "stop"
+		command?: string | null
+		cwd?: string | null
+		process_id?: number | null
+	}
+	run_tests: { path?: string | null; command?: string | null }
+	git: {
+		action: "create_branch" | "commit" | "create_pull_request"
+		branch?: string | null
+		files?: string[] | null
+		message?: string | null
+		title?: string | null
+		body?: string | null
+		base?: string | null
+	}
+	apply_diff: { path: string; diff: string; files?: Array<{ path: string; diff: string }> }
+	fast_edit: { path: string; instructions: string; code_edit: string }
+	edit_notebook: {
+		path: string
+		cell_index: number
+		new_source?: string | null
+		cell_type?: "code" | "markdown" | "raw" | null
+		edit_mode: "replace" | "insert" | "delete"
+	}
 	edit: { file_path: string; old_string: string; new_string: string; replace_all?: boolean }
 	search_and_replace: { file_path: string; old_string: string; new_string: string; replace_all?: boolean }
 	search_replace: { file_path: string; old_string: string; new_string: string }
@@ -103,11 +143,13 @@ export type NativeToolArgs = {
 	apply_patch: { patch: string }
 	list_files: { path: string; recursive?: boolean }
 	new_task: { mode: string; message: string; todos?: string }
+	run_parallel_tasks: { tasks: Array<{ mode: string; message: string }> }
 	ask_followup_question: {
 		question: string
 		follow_up: Array<{ text: string; mode?: string }>
 	}
-	codebase_search: { query: string; path?: string }
+	codebase_search: { query: string; path?: string; language?: string; kind?: string }
+	find_references: { symbol: string; path?: string }
 	generate_image: GenerateImageParams
 	run_slash_command: { command: string; args?: string }
 	skill: { skill: string; args?: string }
@@ -172,6 +214,21 @@ export interface ExecuteCommandToolUse extends ToolUse<"execute_command"> {
 	params: Partial<Pick<Record<ToolParamName, string>, "command" | "cwd" | "timeout">>
 }
 
+export interface BackgroundProcessToolUse extends ToolUse<"background_process"> {
+	name: "background_process"
+	params: Partial<Pick<Record<ToolParamName, string>, "action" | "command" | "cwd" | "process_id">>
+}
+
+export interface RunTestsToolUse extends ToolUse<"run_tests"> {
+	name: "run_tests"
+	params: Partial<Pick<Record<ToolParamName, string>, "path" | "command">>
+}
+
+export interface GitToolUse extends ToolUse<"git"> {
+	name: "git"
+	params: Partial<Pick<Record<ToolParamName, string>, "action" | "branch" | "message" | "title" | "body" | "base">>
+}
+
 export interface ReadFileToolUse extends ToolUse<"read_file"> {
 	name: "read_file"
 	params: Partial<
@@ -198,9 +255,26 @@ export interface WriteToFileToolUse extends ToolUse<"write_to_file"> {
 	params: Partial<Pick<Record<ToolParamName, string>, "path" | "content">>
 }
 
+export interface FastEditToolUse extends ToolUse<"fast_edit"> {
+	name: "fast_edit"
+	params: Partial<Pick<Record<ToolParamName, string>, "path" | "instructions" | "code_edit">>
+}
+
+export interface EditNotebookToolUse extends ToolUse<"edit_notebook"> {
+	name: "edit_notebook"
+	params: Partial<
+		Pick<Record<ToolParamName, string>, "path" | "cell_index" | "new_source" | "cell_type" | "edit_mode">
+	>
+}
+
 export interface CodebaseSearchToolUse extends ToolUse<"codebase_search"> {
 	name: "codebase_search"
-	params: Partial<Pick<Record<ToolParamName, string>, "query" | "path">>
+	params: Partial<Pick<Record<ToolParamName, string>, "query" | "path" | "language" | "kind">>
+}
+
+export interface FindReferencesToolUse extends ToolUse<"find_references"> {
+	name: "find_references"
+	params: Partial<Pick<Record<ToolParamName, string>, "symbol" | "path">>
 }
 
 export interface SearchFilesToolUse extends ToolUse<"search_files"> {
@@ -243,6 +317,11 @@ export interface NewTaskToolUse extends ToolUse<"new_task"> {
 	params: Partial<Pick<Record<ToolParamName, string>, "mode" | "message" | "todos">>
 }
 
+export interface RunParallelTasksToolUse extends ToolUse<"run_parallel_tasks"> {
+	name: "run_parallel_tasks"
+	params: Partial<Pick<Record<ToolParamName, string>, "tasks">>
+}
+
 export interface RunSlashCommandToolUse extends ToolUse<"run_slash_command"> {
 	name: "run_slash_command"
 	params: Partial<Pick<Record<ToolParamName, string>, "command" | "args">>
@@ -267,10 +346,15 @@ export type ToolGroupConfig = {
 
 export const TOOL_DISPLAY_NAMES: Record<ToolName, string> = {
 	execute_command: "run commands",
+	background_process: "manage background processes",
+	run_tests: "run tests",
+	git: "use git",
 	read_file: "read files",
 	read_command_output: "read command output",
 	write_to_file: "write files",
 	apply_diff: "apply changes",
+	fast_edit: "edit files with fast apply",
+	edit_notebook: "edit notebook cells",
 	edit: "edit files",
 	search_and_replace: "apply changes using search and replace",
 	search_replace: "apply single search and replace",
@@ -284,7 +368,9 @@ export const TOOL_DISPLAY_NAMES: Record<ToolName, string> = {
 	attempt_completion: "complete tasks",
 	switch_mode: "switch modes",
 	new_task: "create new task",
+	run_parallel_tasks: "run subtasks in parallel",
 	codebase_search: "codebase search",
+	find_references: "find symbol references",
 	update_todo_list: "update todo list",
 	run_slash_command: "run slash command",
 	skill: "load skill",
@@ -295,20 +381,20 @@ export const TOOL_DISPLAY_NAMES: Record<ToolName, string> = {
 // Define available tool groups.
 export const TOOL_GROUPS: Record<ToolGroup, ToolGroupConfig> = {
 	read: {
-		tools: ["read_file", "search_files", "list_files", "codebase_search"],
+		tools: ["read_file", "search_files", "list_files", "codebase_search", "find_references"],
 	},
 	edit: {
-		tools: ["apply_diff", "write_to_file", "generate_image"],
+		tools: ["apply_diff", "fast_edit", "edit_notebook", "write_to_file", "generate_image"],
 		customTools: ["edit", "search_replace", "edit_file", "apply_patch"],
 	},
 	command: {
-		tools: ["execute_command", "read_command_output"],
+		tools: ["execute_command", "background_process", "read_command_output", "run_tests", "git"],
 	},
 	mcp: {
 		tools: ["use_mcp_tool", "access_mcp_resource"],
 	},
 	modes: {
-		tools: ["switch_mode", "new_task"],
+		tools: ["switch_mode", "new_task", "run_parallel_tasks"],
 		alwaysAvailable: true,
 	},
 }
@@ -319,6 +405,7 @@ export const ALWAYS_AVAILABLE_TOOLS: ToolName[] = [
 	"attempt_completion",
 	"switch_mode",
 	"new_task",
+	"run_parallel_tasks",
 	"update_todo_list",
 	"run_slash_command",
 	"skill",
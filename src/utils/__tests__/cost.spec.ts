@@ -2,7 +2,7 @@
 
 import type { ModelInfo } from "@roo-code/types"
 
-import { calculateApiCostAnthropic, calculateApiCostOpenAI } from "../../shared/cost"
+import { calculateApiCostAnthropic, calculateApiCostOpenAI, calculateCacheSavings } from "../../shared/cost"
 
 describe("Cost Utility", () => {
 	describe("calculateApiCostAnthropic", () => {
@@ -303,4 +303,23 @@ describe("Cost Utility", () => {
 			expect(result.totalCost).toBeCloseTo(1.08, 6)
 		})
 	})
+
+	describe("calculateCacheSavings", () => {
+		const modelInfo: ModelInfo = {
+			contextWindow: 200_000,
+			supportsPromptCache: true,
+			inputPrice: 3.0,
+			cacheReadsPrice: 0.3,
+		}
+
+		it("should count the difference between the input and cache read prices", () => {
+			// (3.0 - 0.3) / 1_000_000 * 100_000 = 0.27
+			expect(calculateCacheSavings(modelInfo, 100_000)).toBeCloseTo(0.27, 6)
+		})
+
+		it("should return 0 without cache reads or cache pricing", () => {
+			expect(calculateCacheSavings(modelInfo)).toBe(0)
+			expect(calculateCacheSavings({ ...modelInfo, cacheReadsPrice: undefined }, 100_000)).toBe(0)
+		})
+	})
 })
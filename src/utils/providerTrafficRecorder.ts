@@ -0,0 +1,375 @@
+/**
+ * Provider Traffic Recorder
+ *
+ * Opt-in recorder for the requests Roo Code sends to model providers and the
+ * (streamed) responses it receives. Each task gets its own log in the task
+ * directory, which can be exported as JSON or HAR to attach to bug reports.
+ *
+ * Recording works by wrapping `globalThis.fetch`, so it covers the providers and
+ * SDKs that use fetch. Requests are attributed to a task through
+ * AsyncLocalStorage while the task iterates its API stream; fetch calls made
+ * outside of a recorded stream pass straight through. Credentials are redacted
+ * before anything is written to disk.
+ */
+
+import { AsyncLocalStorage } from "async_hooks"
+import * as path from "path"
+import * as fs from "fs/promises"
+import * as vscode from "vscode"
+
+import { Package } from "../shared/package"
+import { GlobalFileNames } from "../shared/globalFileNames"
+import type { ApiStream } from "../api/transform/stream"
+import { fileExistsAtPath } from "./fs"
+import { getTaskDirectoryPath } from "./storage"
+
+export interface ProviderTrafficEntry {
+	startedDateTime: string
+	/** Milliseconds from sending the request until the response body ended */
+	time: number
+	request: {
+		method: string
+		url: string
+		headers: Record<string, string>
+		body?: string
+	}
+	response?: {
+		status: number
+		statusText: string
+		headers: Record<string, string>
+		body: string
+	}
+	error?: string
+}
+
+export type ProviderTrafficExportFormat = "json" | "har"
+
+export const REDACTED = "[REDACTED]"
+
+// Header, query parameter and JSON property names whose values are credentials.
+const SENSITIVE_NAME =
+	/^(authorization|proxy-authorization|cookie|set-cookie|x-api-key|x-goog-api-key|api[-_]?key|key|access[-_]?token|token|secret|client[-_]?secret|password|x-amz-security-token)$/i
+
+const recordingContext = new AsyncLocalStorage<{ logFilePath: string }>()
+
+let recorderEnabled = false
+let fetchPatched = false
+
+/**
+ * Install the recording fetch wrapper and start following the
+ * `debugRecorder` setting. Must be called during activation, before any
+ * provider clients are created.
+ */
+export function initializeProviderTrafficRecorder(context: vscode.ExtensionContext): void {
+	recorderEnabled = isRecorderEnabledInSettings()
+
+	// Guard for test environments where onDidChangeConfiguration may not be mocked.
+	if (typeof vscode.workspace.onDidChangeConfiguration === "function") {
+		context.subscriptions.push(
+			vscode.workspace.onDidChangeConfiguration((e) => {
+				if (e.affectsConfiguration(`${Package.name}.debugRecorder`)) {
+					recorderEnabled = isRecorderEnabledInSettings()
+				}
+			}),
+		)
+	}
+
+	if (!fetchPatched && typeof globalThis.fetch === "function") {
+		const baseFetch = globalThis.fetch
+		globalThis.fetch = createRecordingFetch(baseFetch)
+		fetchPatched = true
+
+		context.subscriptions.push({
+			dispose: () => {
+				globalThis.fetch = baseFetch
+				fetchPatched = false
+			},
+		})
+	}
+}
+
+function isRecorderEnabledInSettings(): boolean {
+	return Boolean(vscode.workspace.getConfiguration(Package.name).get<boolean>("debugRecorder"))
+}
+
+/**
+ * Check if provider traffic is currently being recorded.
+ */
+export function isProviderTrafficRecorderEnabled(): boolean {
+	return recorderEnabled
+}
+
+export async function getProviderTrafficFilePath(globalStoragePath: string, taskId: string): Promise<string> {
+	return path.join(await getTaskDirectoryPath(globalStoragePath, taskId), GlobalFileNames.providerTraffic)
+}
+
+/**
+ * Record the provider requests made while `stream` is iterated to the task's
+ * traffic log. Returns the stream unchanged when the recorder is disabled.
+ */
+export function recordProviderTraffic(
+	stream: ApiStream,
+	{ taskId, globalStoragePath }: { taskId: string; globalStoragePath: string },
+): ApiStream {
+	if (!recorderEnabled) {
+		return stream
+	}
+
+	return recordStream(stream, getProviderTrafficFilePath(globalStoragePath, taskId))
+}
+
+async function* recordStream(stream: ApiStream, logFilePath: Promise<string>): ApiStream {
+	const store = { logFilePath: await logFilePath }
+	const iterator = stream[Symbol.asyncIterator]()
+
+	try {
+		while (true) {
+			// Provider code only runs while the stream is being pulled, so running
+			// each step in the recording context attributes its fetch calls to this task.
+			const { done, value } = await recordingContext.run(store, () => iterator.next())
+
+			if (done) {
+				return
+			}
+
+			yield value
+		}
+	} finally {
+		await iterator.return?.(undefined)
+	}
+}
+
+export function createRecordingFetch(baseFetch: typeof fetch): typeof fetch {
+	return async (input, init) => {
+		const store = recordingContext.getStore()
+
+		if (!store) {
+			return baseFetch(input, init)
+		}
+
+		const started = Date.now()
+		const entry: ProviderTrafficEntry = {
+			startedDateTime: new Date(started).toISOString(),
+			time: 0,
+			request: getRequestDetails(input, init),
+		}
+
+		let response: Response
+
+		try {
+			response = await baseFetch(input, init)
+		} catch (error) {
+			entry.time = Date.now() - started
+			entry.error = error instanceof Error ? error.message : String(error)
+			await appendEntry(store.logFilePath, entry)
+			throw error
+		}
+
+		// Read a copy of the body in the background so the caller still gets
+		// the stream as it arrives.
+		const copy = response.clone()
+		const { status, statusText } = response
+		const headers = redactHeaders(copy.headers)
+
+		void copy
+			.text()
+			.then(
+				(body) => {
+					entry.response = { status, statusText, headers, body: redactBody(body) }
+				},
+				(error) => {
+					entry.response = { status, statusText, headers, body: "" }
+					entry.error = error instanceof Error ? error.message : String(error)
+				},
+			)
+			.then(() => {
+				entry.time = Date.now() - started
+				return appendEntry(store.logFilePath, entry)
+			})
+
+		return response
+	}
+}
+
+function getRequestDetails(input: RequestInfo | URL, init?: RequestInit): ProviderTrafficEntry["request"] {
+	const request = input instanceof Request ? input : undefined
+	const url = typeof input === "string" ? input : input instanceof URL ? input.href : input.url
+	const body = init?.body
+
+	return {
+		method: (init?.method ?? request?.method ?? "GET").toUpperCase(),
+		url: redactUrl(url),
+		headers: redactHeaders(new Headers(init?.headers ?? request?.headers)),
+		body:
+			typeof body === "string"
+				? redactBody(body)
+				: body instanceof Uint8Array || body instanceof ArrayBuffer
+					? redactBody(new TextDecoder().decode(body))
+					: body
+						? "[unrecorded body]"
+						: undefined,
+	}
+}
+
+export function redactUrl(url: string): string {
+	try {
+		const parsed = new URL(url)
+
+		if (parsed.username || parsed.password) {
+			parsed.username = REDACTED
+			parsed.password = ""
+		}
+
+		for (const name of [...parsed.searchParams.keys()]) {
+			if (SENSITIVE_NAME.test(name)) {
+				parsed.searchParams.set(name, REDACTED)
+			}
+		}
+
+		return parsed.toString()
+	} catch {
+		return url
+	}
+}
+
+export function redactHeaders(headers: Headers): Record<string, string> {
+	const redacted: Record<string, string> = {}
+	headers.forEach((value, name) => {
+		redacted[name] = SENSITIVE_NAME.test(name) ? REDACTED : value
+	})
+	return redacted
+}
+
+export function redactBody(body: string): string {
+	let parsed: unknown
+
+	try {
+		parsed = JSON.parse(body)
+	} catch {
+		// Not JSON (e.g. a server-sent event stream), keep as is.
+		return body
+	}
+
+	return JSON.stringify(redactJson(parsed))
+}
+
+function redactJson(value: unknown): unknown {
+	if (Array.isArray(value)) {
+		return value.map(redactJson)
+	}
+
+	if (value && typeof value === "object") {
+		return Object.fromEntries(
+			// Only string values are credentials; this keeps e.g. a tool parameter called "key" intact.
+			Object.entries(value).map(([key, item]) => [
+				key,
+				SENSITIVE_NAME.test(key) && typeof item === "string" ? REDACTED : redactJson(item),
+			]),
+		)
+	}
+
+	return value
+}
+
+async function appendEntry(logFilePath: string, entry: ProviderTrafficEntry): Promise<void> {
+	try {
+		await fs.appendFile(logFilePath, JSON.stringify(entry) + "\n", "utf8")
+	} catch (error) {
+		console.error(
+			`[providerTrafficRecorder] Failed to write to ${logFilePath}: ${error instanceof Error ? error.message : String(error)}`,
+		)
+	}
+}
+
+export async function readProviderTraffic(logFilePath: string): Promise<ProviderTrafficEntry[]> {
+	if (!(await fileExistsAtPath(logFilePath))) {
+		return []
+	}
+
+	const content = await fs.readFile(logFilePath, "utf8")
+	const entries: ProviderTrafficEntry[] = []
+
+	for (const line of content.split("\n")) {
+		if (!line.trim()) {
+			continue
+		}
+
+		try {
+			entries.push(JSON.parse(line))
+		} catch {
+			// Skip lines that were cut off, e.g. when VS Code closed mid-write.
+		}
+	}
+
+	return entries
+}
+
+/**
+ * Serialize recorded traffic as plain JSON or as an HTTP Archive (HAR 1.2)
+ * that browser dev tools and HAR viewers can open.
+ */
+export function formatProviderTraffic(entries: ProviderTrafficEntry[], format: ProviderTrafficExportFormat): string {
+	if (format === "json") {
+		return JSON.stringify(entries, null, 2)
+	}
+
+	const toHarHeaders = (headers: Record<string, string>) =>
+		Object.entries(headers).map(([name, value]) => ({ name, value }))
+
+	const har = {
+		log: {
+			version: "1.2",
+			creator: { name: Package.name, version: Package.version },
+			entries: entries.map(({ startedDateTime, time, request, response, error }) => {
+				let queryString: { name: string; value: string }[] = []
+
+				try {
+					queryString = [...new URL(request.url).searchParams].map(([name, value]) => ({ name, value }))
+				} catch {
+					// Keep an empty query string for unparseable URLs.
+				}
+
+				return {
+					startedDateTime,
+					time,
+					request: {
+						method: request.method,
+						url: request.url,
+						httpVersion: "HTTP/1.1",
+						cookies: [],
+						headers: toHarHeaders(request.headers),
+						queryString,
+						...(request.body !== undefined && {
+							postData: {
+								mimeType: request.headers["content-type"] ?? "application/json",
+								text: request.body,
+							},
+						}),
+						headersSize: -1,
+						bodySize: request.body?.length ?? 0,
+					},
+					response: {
+						status: response?.status ?? 0,
+						statusText: response?.statusText ?? "",
+						httpVersion: "HTTP/1.1",
+						cookies: [],
+						headers: toHarHeaders(response?.headers ?? {}),
+						content: {
+							size: response?.body.length ?? 0,
+							mimeType: response?.headers["content-type"] ?? "",
+							text: response?.body ?? "",
+						},
+						redirectURL: "",
+						headersSize: -1,
+						bodySize: -1,
+						...(error && { _error: error }),
+					},
+					cache: {},
+					timings: { send: 0, wait: time, receive: 0 },
+				}
+			}),
+		},
+	}
+
+	return JSON.stringify(har, null, 2)
+}
@@ -0,0 +1,57 @@
+import { memo, useState } from "react"
+import { useTranslation } from "react-i18next"
+
+import type { BudgetLimit, ClineMessage } from "@roo-code/types"
+
+import { vscode } from "@src/utils/vscode"
+import { formatBudgetValue } from "@src/utils/format"
+import { Button } from "@src/components/ui"
+
+type BudgetLimitWarningProps = {
+	message: ClineMessage
+}
+
+type BudgetLimitReached = BudgetLimit & { mode?: string; usage: number }
+
+export const BudgetLimitWarning = memo(({ message }: BudgetLimitWarningProps) => {
+	const { t } = useTranslation()
+	const [buttonClicked, setButtonClicked] = useState(false)
+	const { metric = "cost", scope = "task", limit = 0, mode, usage = 0 }: BudgetLimitReached = JSON.parse(
+		message.text ?? "{}",
+	)
+
+	if (buttonClicked) {
+		return null
+	}
+
+	const formatAmount = (value: number) =>
+		t(`chat:ask.budgetLimitReached.amount.${metric}`, { value: formatBudgetValue(metric, value) })
+
+	return (
+		<>
+			<div className="flex items-center gap-2 text-vscode-foreground">
+				<span className="codicon codicon-warning" />
+				<span className="font-bold">{t("chat:ask.budgetLimitReached.title")}</span>
+			</div>
+
+			<div className="bg-vscode-panel-border flex flex-col gap-3 rounded mt-4 px-4 pt-3.5 pb-5">
+				<div>
+					{t(`chat:ask.budgetLimitReached.description.${scope}`, {
+						limit: formatAmount(limit),
+						usage: formatAmount(usage),
+						mode,
+					})}
+				</div>
+				<Button
+					className="w-full p-1.5 rounded"
+					onClick={(e) => {
+						e.preventDefault()
+						setButtonClicked(true)
+						vscode.postMessage({ type: "askResponse", askResponse: "yesButtonClicked" })
+					}}>
+					{t("chat:ask.budgetLimitReached.button")}
+				</Button>
+			</div>
+		</>
+	)
+})
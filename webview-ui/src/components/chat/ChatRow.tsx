@@ -46,6 +46,7 @@ import { Markdown } from "./Markdown"
 import { CommandExecution } from "./CommandExecution"
 import { CommandExecutionError } from "./CommandExecutionError"
 import { AutoApprovedRequestLimitWarning } from "./AutoApprovedRequestLimitWarning"
+import { BudgetLimitWarning } from "./BudgetLimitWarning"
 import { InProgressRow, CondensationResultRow, CondensationErrorRow, TruncationResultRow } from "./context-management"
 import CodebaseSearchResultsDisplay from "./CodebaseSearchResultsDisplay"
 import { appendImages } from "@src/utils/imageUtils"
@@ -70,11 +71,13 @@ import {
 	Repeat2,
 	Split,
 	ArrowRight,
+	ArrowRightLeft,
 	Check,
 } from "lucide-react"
 import { cn } from "@/lib/utils"
 import { PathTooltip } from "../ui/PathTooltip"
 import { OpenMarkdownPreviewButton } from "./OpenMarkdownPreviewButton"
+import { PinMessageButton } from "./PinMessageButton"
 
 // Helper function to get previous todos before a specific message
 function getPreviousTodos(messages: ClineMessage[], currentMessageTs: number): any[] {
@@ -489,6 +492,18 @@ export const ChatRowContent = ({
 						</div>
 					</>
 				)
+			case "pendingChanges":
+				return (
+					<>
+						<div style={headerStyle}>
+							<FileDiff className="w-4 shrink-0" aria-label="Batch diff icon" />
+							<span style={{ fontWeight: "bold" }}>
+								{t("chat:fileOperations.wantsToApplyPendingChanges")}
+							</span>
+						</div>
+						<BatchDiffApproval files={tool.batchDiffs ?? []} ts={message.ts} />
+					</>
+				)
 			case "insertContent":
 				return (
 					<>
@@ -546,6 +561,31 @@ export const ChatRowContent = ({
 								/>
 							)}
 						</span>
+						{(tool.language || tool.kind) && (
+							<span className="text-vscode-descriptionForeground">
+								{t("chat:codebaseSearch.filters", {
+									filters: [tool.language, tool.kind].filter(Boolean).join(", "),
+								})}
+							</span>
+						)}
+					</div>
+				)
+			}
+			case "findReferences": {
+				return (
+					<div style={headerStyle}>
+						{toolIcon("references")}
+						<span style={{ fontWeight: "bold" }}>
+							<Trans
+								i18nKey={
+									tool.path
+										? "chat:findReferences.wantsToFindWithPath"
+										: "chat:findReferences.wantsToFind"
+								}
+								components={{ code: <code></code> }}
+								values={{ symbol: tool.symbol, path: tool.path }}
+							/>
+						</span>
 					</div>
 				)
 			}
@@ -882,6 +922,29 @@ export const ChatRowContent = ({
 						</div>
 					</>
 				)
+			case "runParallelTasks":
+				return (
+					<>
+						<div style={headerStyle}>
+							<Split className="size-4" />
+							<span style={{ fontWeight: "bold" }}>
+								{t("chat:subtasks.wantsToRunParallel", { count: tool.subtasks?.length ?? 0 })}
+							</span>
+						</div>
+						{(tool.subtasks ?? []).map((subtask, index) => (
+							<div key={index} className="border-l border-muted-foreground/80 ml-2 pl-4 pb-1">
+								<div className="font-medium">
+									<Trans
+										i18nKey="chat:subtasks.parallelSubtask"
+										components={{ code: <code>{subtask.mode}</code> }}
+										values={{ index: index + 1, mode: subtask.mode }}
+									/>
+								</div>
+								<MarkdownBlock markdown={subtask.message} />
+							</div>
+						))}
+					</>
+				)
 			case "finishTask":
 				return (
 					<>
@@ -1176,6 +1239,17 @@ export const ChatRowContent = ({
 						</div>
 					) : null
 				}
+				case "api_req_failover": {
+					const failover = safeJsonParse<{ from?: string; to?: string; error?: string }>(message.text)
+					return (
+						<div style={headerStyle} title={failover?.error}>
+							<ArrowRightLeft className="w-4 shrink-0" aria-label="Failover icon" />
+							<span style={{ color: normalColor }}>
+								{t("chat:apiRequest.failover", { from: failover?.from, to: failover?.to })}
+							</span>
+						</div>
+					)
+				}
 				case "api_req_finished":
 					return null // we should never see this message type
 				case "text":
@@ -1185,6 +1259,7 @@ export const ChatRowContent = ({
 								<MessageCircle className="w-4 shrink-0" aria-label="Speech bubble icon" />
 								<span style={{ fontWeight: "bold" }}>{t("chat:text.rooSaid")}</span>
 								<div style={{ flexGrow: 1 }} />
+								{!message.partial && <PinMessageButton ts={message.ts} isPinned={message.isPinned} />}
 								<OpenMarkdownPreviewButton markdown={message.text} />
 							</div>
 							<div className="pl-6">
@@ -1205,6 +1280,8 @@ export const ChatRowContent = ({
 							<div style={headerStyle}>
 								<User className="w-4 shrink-0" aria-label="User icon" />
 								<span style={{ fontWeight: "bold" }}>{t("chat:feedback.youSaid")}</span>
+								<div style={{ flexGrow: 1 }} />
+								<PinMessageButton ts={message.ts} isPinned={message.isPinned} />
 							</div>
 							<div
 								className={cn(
@@ -1518,6 +1595,32 @@ export const ChatRowContent = ({
 								</div>
 							)
 						}
+						case "stagedChange":
+							return (
+								<>
+									<div style={headerStyle}>
+										<span
+											className="codicon codicon-diff"
+											style={{
+												color: "var(--vscode-foreground)",
+												marginBottom: "-1.5px",
+											}}></span>
+										<span style={{ fontWeight: "bold" }}>
+											{t("chat:fileOperations.stagedChange")}
+										</span>
+									</div>
+									<div className="pl-6">
+										<CodeAccordion
+											path={sayTool.path}
+											code={sayTool.content ?? ""}
+											language="diff"
+											isExpanded={isExpanded}
+											onToggleExpand={handleToggleExpand}
+											diffStats={sayTool.diffStats}
+										/>
+									</div>
+								</>
+							)
 						default:
 							return null
 					}
@@ -1691,6 +1794,9 @@ export const ChatRowContent = ({
 				case "auto_approval_max_req_reached": {
 					return <AutoApprovedRequestLimitWarning message={message} />
 				}
+				case "budget_limit_reached": {
+					return <BudgetLimitWarning message={message} />
+				}
 				default:
 					return null
 			}
@@ -31,6 +31,7 @@ import { AutoApproveDropdown } from "./AutoApproveDropdown"
 import { MAX_IMAGES_PER_MESSAGE } from "./ChatView"
 import ContextMenu from "./ContextMenu"
 import { IndexingStatusBadge } from "./IndexingStatusBadge"
+import { VoiceInputButton } from "./VoiceInputButton"
 import { usePromptHistory } from "./hooks/usePromptHistory"
 import { CloudAccountSwitcher } from "../cloud/CloudAccountSwitcher"
 
@@ -925,6 +926,14 @@ export const ChatTextArea = forwardRef<HTMLTextAreaElement, ChatTextAreaProps>(
 			}
 		})
 
+		const handleVoiceTranscript = useCallback(
+			(transcript: string) => {
+				setInputValue(inputValue.trim() ? `${inputValue.trimEnd()} ${transcript}` : transcript)
+				textAreaRef.current?.focus()
+			},
+			[inputValue, setInputValue],
+		)
+
 		const placeholderBottomText = `\n(${t("chat:addContext")}${shouldDisableImages ? `, ${t("chat:dragFiles")}` : `, ${t("chat:dragFilesImages")}`})`
 
 		// Common mode selector handler
@@ -1347,6 +1356,7 @@ export const ChatTextArea = forwardRef<HTMLTextAreaElement, ChatTextAreaProps>(
 								</button>
 							</StandardTooltip>
 						)}
+						{!isEditMode && <VoiceInputButton onTranscript={handleVoiceTranscript} />}
 						{!isEditMode ? <IndexingStatusBadge /> : null}
 						{!isEditMode && cloudUserInfo && <CloudAccountSwitcher />}
 					</div>
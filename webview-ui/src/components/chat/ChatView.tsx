@@ -90,6 +90,7 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 		telemetrySetting,
 		soundEnabled,
 		soundVolume,
+		ttsContent,
 		cloudIsAuthenticated,
 		messageQueue = [],
 		showWorktreesInHomeScreen,
@@ -268,6 +269,25 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 		vscode.postMessage({ type: "playTts", text })
 	}
 
+	// Reads out what a task waits for, so that long runs can be followed without watching them.
+	const announceInteraction = useCallback(() => {
+		if (lastMessage?.type !== "ask") {
+			return
+		}
+
+		let text = t("chat:voice.approvalNeeded")
+
+		if (lastMessage.ask === "followup") {
+			try {
+				text = JSON.parse(lastMessage.text || "{}").question || t("chat:voice.inputNeeded")
+			} catch {
+				text = t("chat:voice.inputNeeded")
+			}
+		}
+
+		vscode.postMessage({ type: "playTts", text })
+	}, [lastMessage, t])
+
 	useDeepCompareEffect(() => {
 		// if last message is an ask, show user ask UI
 		// if user finished a task, then start a new task with a new conversation history since in this moment that the extension is waiting for user response, the user could close the extension and the conversation history would be lost.
@@ -323,6 +343,10 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 										setSecondaryButtonText(t("chat:reject.title"))
 									}
 									break
+								case "pendingChanges":
+									setPrimaryButtonText(t("chat:edit-batch.approve.title"))
+									setSecondaryButtonText(t("chat:edit-batch.deny.title"))
+									break
 								case "generateImage":
 									setPrimaryButtonText(t("chat:save.title"))
 									setSecondaryButtonText(t("chat:reject.title"))
@@ -925,6 +949,7 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 					break
 				case "interactionRequired":
 					playSound("notification")
+					announceInteraction()
 					break
 				case "taskWithAggregatedCosts":
 					if (message.text && message.aggregatedCosts) {
@@ -952,6 +977,7 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 			handleSecondaryButtonClick,
 			setCheckpointWarning,
 			playSound,
+			announceInteraction,
 		],
 	)
 
@@ -1085,7 +1111,8 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 		if (lastMessage && messages.length > 1) {
 			if (
 				typeof lastMessage.text === "string" && // has text (must be string for startsWith)
-				(lastMessage.say === "text" || lastMessage.say === "completion_result") && // is a text message
+				(lastMessage.say === "completion_result" ||
+					(lastMessage.say === "text" && ttsContent !== "summaries")) && // is a text message to read
 				!lastMessage.partial && // not a partial message
 				!lastMessage.text.startsWith("{") // not a json object
 			) {
@@ -1110,7 +1137,7 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 
 		// Update previous value.
 		setWasStreaming(isStreaming)
-	}, [isStreaming, lastMessage, wasStreaming, messages.length])
+	}, [isStreaming, lastMessage, wasStreaming, messages.length, ttsContent])
 
 	const groupedMessages = useMemo(() => {
 		const filtered: ClineMessage[] = visibleMessages
@@ -1394,9 +1421,19 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 		vscode.postMessage({ type: "cancelAutoApproval" })
 	}, [])
 
+	// Only the last row reads the last message, so it's passed to that row
+	// alone: every other row is then unchanged by a streamed update and isn't
+	// re-rendered.
+	const lastModifiedMessage = modifiedMessages.at(-1)
+
+	const hasCheckpoint = useMemo(
+		() => modifiedMessages.some((message) => message.say === "checkpoint_saved"),
+		[modifiedMessages],
+	)
+
 	const itemContent = useCallback(
 		(index: number, messageOrGroup: ClineMessage) => {
-			const hasCheckpoint = modifiedMessages.some((message) => message.say === "checkpoint_saved")
+			const isLast = index === groupedMessages.length - 1
 
 			// regular message
 			return (
@@ -1405,8 +1442,8 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 					message={messageOrGroup}
 					isExpanded={expandedRows[messageOrGroup.ts] || false}
 					onToggleExpand={toggleRowExpansion} // This was already stabilized
-					lastModifiedMessage={modifiedMessages.at(-1)} // Original direct access
-					isLast={index === groupedMessages.length - 1} // Original direct access
+					lastModifiedMessage={isLast ? lastModifiedMessage : undefined}
+					isLast={isLast}
 					onHeightChange={handleRowHeightChange}
 					isStreaming={isStreaming}
 					onSuggestionClick={handleSuggestionClickInRow} // This was already stabilized
@@ -1436,7 +1473,8 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 		[
 			expandedRows,
 			toggleRowExpansion,
-			modifiedMessages,
+			lastModifiedMessage,
+			hasCheckpoint,
 			groupedMessages.length,
 			handleRowHeightChange,
 			isStreaming,
@@ -1450,6 +1488,10 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 		],
 	)
 
+	// Rows are keyed by message rather than position, so they keep their state
+	// when the messages before them are regrouped.
+	const computeItemKey = useCallback((_index: number, messageOrGroup: ClineMessage) => messageOrGroup.ts, [])
+
 	// Function to handle mode switching
 	const switchToNextMode = useCallback(() => {
 		const allModes = getAllModes(customModes)
@@ -1628,6 +1670,7 @@ const ChatViewComponent: React.ForwardRefRenderFunction<ChatViewRef, ChatViewPro
 							className="scrollable grow overflow-y-scroll mb-1"
 							increaseViewportBy={{ top: 3_000, bottom: 1000 }}
 							data={groupedMessages}
+							computeItemKey={computeItemKey}
 							itemContent={itemContent}
 							followOutput={followOutputCallback}
 							atBottomStateChange={atBottomStateChangeCallback}
@@ -12,7 +12,15 @@ import {
 import * as ProgressPrimitive from "@radix-ui/react-progress"
 import { AlertTriangle } from "lucide-react"
 
-import { type IndexingStatus, type EmbedderProvider, CODEBASE_INDEX_DEFAULTS } from "@roo-code/types"
+import {
+	type IndexingStatus,
+	type EmbedderProvider,
+	type RerankerProvider,
+	type CodebaseIndexDocumentType,
+	CODEBASE_INDEX_DEFAULTS,
+	codebaseIndexDocumentTypes,
+	defaultRerankerModels,
+} from "@roo-code/types"
 
 import { vscode } from "@src/utils/vscode"
 import { useExtensionState } from "@src/context/ExtensionStateContext"
@@ -66,6 +74,11 @@ interface LocalCodeIndexSettings {
 	codebaseIndexEmbedderModelDimension?: number // Generic dimension for all providers
 	codebaseIndexSearchMaxResults?: number
 	codebaseIndexSearchMinScore?: number
+	codebaseIndexHybridSearchWeight?: number
+	codebaseIndexRerankerProvider?: RerankerProvider
+	codebaseIndexRerankerModelId?: string
+	codebaseIndexRerankerCandidates?: number
+	codebaseIndexDocumentTypes?: CodebaseIndexDocumentType[]
 
 	// Bedrock-specific settings
 	codebaseIndexBedrockRegion?: string
@@ -81,6 +94,8 @@ interface LocalCodeIndexSettings {
 	codebaseIndexVercelAiGatewayApiKey?: string
 	codebaseIndexOpenRouterApiKey?: string
 	codebaseIndexOpenRouterSpecificProvider?: string
+	codebaseIndexCohereApiKey?: string
+	codebaseIndexVoyageApiKey?: string
 }
 
 // Validation schema for codebase index settings
@@ -214,6 +229,11 @@ export const CodeIndexPopover: React.FC<CodeIndexPopoverProps> = ({
 		codebaseIndexEmbedderModelDimension: undefined,
 		codebaseIndexSearchMaxResults: CODEBASE_INDEX_DEFAULTS.DEFAULT_SEARCH_RESULTS,
 		codebaseIndexSearchMinScore: CODEBASE_INDEX_DEFAULTS.DEFAULT_SEARCH_MIN_SCORE,
+		codebaseIndexHybridSearchWeight: CODEBASE_INDEX_DEFAULTS.DEFAULT_HYBRID_SEARCH_WEIGHT,
+		codebaseIndexRerankerProvider: undefined,
+		codebaseIndexRerankerModelId: "",
+		codebaseIndexRerankerCandidates: CODEBASE_INDEX_DEFAULTS.DEFAULT_RERANK_CANDIDATES,
+		codebaseIndexDocumentTypes: [...codebaseIndexDocumentTypes],
 		codebaseIndexBedrockRegion: "",
 		codebaseIndexBedrockProfile: "",
 		codeIndexOpenAiKey: "",
@@ -225,6 +245,8 @@ export const CodeIndexPopover: React.FC<CodeIndexPopoverProps> = ({
 		codebaseIndexVercelAiGatewayApiKey: "",
 		codebaseIndexOpenRouterApiKey: "",
 		codebaseIndexOpenRouterSpecificProvider: "",
+		codebaseIndexCohereApiKey: "",
+		codebaseIndexVoyageApiKey: "",
 	})
 
 	// Initial settings state - stores the settings when popover opens
@@ -253,6 +275,17 @@ export const CodeIndexPopover: React.FC<CodeIndexPopoverProps> = ({
 					codebaseIndexConfig.codebaseIndexSearchMaxResults ?? CODEBASE_INDEX_DEFAULTS.DEFAULT_SEARCH_RESULTS,
 				codebaseIndexSearchMinScore:
 					codebaseIndexConfig.codebaseIndexSearchMinScore ?? CODEBASE_INDEX_DEFAULTS.DEFAULT_SEARCH_MIN_SCORE,
+				codebaseIndexHybridSearchWeight:
+					codebaseIndexConfig.codebaseIndexHybridSearchWeight ??
+					CODEBASE_INDEX_DEFAULTS.DEFAULT_HYBRID_SEARCH_WEIGHT,
+				codebaseIndexRerankerProvider: codebaseIndexConfig.codebaseIndexRerankerProvider,
+				codebaseIndexRerankerModelId: codebaseIndexConfig.codebaseIndexRerankerModelId || "",
+				codebaseIndexRerankerCandidates:
+					codebaseIndexConfig.codebaseIndexRerankerCandidates ??
+					CODEBASE_INDEX_DEFAULTS.DEFAULT_RERANK_CANDIDATES,
+				codebaseIndexDocumentTypes: codebaseIndexConfig.codebaseIndexDocumentTypes ?? [
+					...codebaseIndexDocumentTypes,
+				],
 				codebaseIndexBedrockRegion: codebaseIndexConfig.codebaseIndexBedrockRegion || "",
 				codebaseIndexBedrockProfile: codebaseIndexConfig.codebaseIndexBedrockProfile || "",
 				codeIndexOpenAiKey: "",
@@ -265,6 +298,8 @@ export const CodeIndexPopover: React.FC<CodeIndexPopoverProps> = ({
 				codebaseIndexOpenRouterApiKey: "",
 				codebaseIndexOpenRouterSpecificProvider:
 					codebaseIndexConfig.codebaseIndexOpenRouterSpecificProvider || "",
+				codebaseIndexCohereApiKey: "",
+				codebaseIndexVoyageApiKey: "",
 			}
 			setInitialSettings(settings)
 			setCurrentSettings(settings)
@@ -389,6 +424,12 @@ export const CodeIndexPopover: React.FC<CodeIndexPopoverProps> = ({
 							? SECRET_PLACEHOLDER
 							: ""
 					}
+					if (!prev.codebaseIndexCohereApiKey || prev.codebaseIndexCohereApiKey === SECRET_PLACEHOLDER) {
+						updated.codebaseIndexCohereApiKey = secretStatus.hasCohereApiKey ? SECRET_PLACEHOLDER : ""
+					}
+					if (!prev.codebaseIndexVoyageApiKey || prev.codebaseIndexVoyageApiKey === SECRET_PLACEHOLDER) {
+						updated.codebaseIndexVoyageApiKey = secretStatus.hasVoyageApiKey ? SECRET_PLACEHOLDER : ""
+					}
 
 					return updated
 				}
@@ -426,6 +467,14 @@ export const CodeIndexPopover: React.FC<CodeIndexPopoverProps> = ({
 				continue
 			}
 
+			// Arrays are rebuilt on every change, so compare their contents
+			if (Array.isArray(currentValue) && Array.isArray(initialValue)) {
+				if (currentValue.join(",") !== initialValue.join(",")) {
+					return true
+				}
+				continue
+			}
+
 			// Compare values - handles all types including undefined
 			if (currentValue !== initialValue) {
 				return true
@@ -447,6 +496,15 @@ export const CodeIndexPopover: React.FC<CodeIndexPopoverProps> = ({
 		}
 	}
 
+	const toggleDocumentType = (type: CodebaseIndexDocumentType, enabled: boolean) => {
+		const current: readonly CodebaseIndexDocumentType[] =
+			currentSettings.codebaseIndexDocumentTypes ?? codebaseIndexDocumentTypes
+		updateSetting(
+			"codebaseIndexDocumentTypes",
+			codebaseIndexDocumentTypes.filter((other) => (other === type ? enabled : current.includes(other))),
+		)
+	}
+
 	// Validation function
 	const validateSettings = (): boolean => {
 		const schema = createValidationSchema(currentSettings.codebaseIndexEmbedderProvider, t)
@@ -1586,6 +1644,207 @@ export const CodeIndexPopover: React.FC<CodeIndexPopoverProps> = ({
 											</VSCodeButton>
 										</div>
 									</div>
+
+									{/* Hybrid Search Weight Slider */}
+									<div className="space-y-2">
+										<div className="flex items-center gap-2">
+											<label className="text-sm font-medium">
+												{t("settings:codeIndex.hybridSearchWeightLabel")}
+											</label>
+											<StandardTooltip
+												content={t("settings:codeIndex.hybridSearchWeightDescription")}>
+												<span className="codicon codicon-info text-xs text-vscode-descriptionForeground cursor-help" />
+											</StandardTooltip>
+										</div>
+										<div className="flex items-center gap-2">
+											<Slider
+												min={CODEBASE_INDEX_DEFAULTS.MIN_HYBRID_SEARCH_WEIGHT}
+												max={CODEBASE_INDEX_DEFAULTS.MAX_HYBRID_SEARCH_WEIGHT}
+												step={CODEBASE_INDEX_DEFAULTS.HYBRID_SEARCH_WEIGHT_STEP}
+												value={[
+													currentSettings.codebaseIndexHybridSearchWeight ??
+														CODEBASE_INDEX_DEFAULTS.DEFAULT_HYBRID_SEARCH_WEIGHT,
+												]}
+												onValueChange={(values) =>
+													updateSetting("codebaseIndexHybridSearchWeight", values[0])
+												}
+												className="flex-1"
+												data-testid="hybrid-search-weight-slider"
+											/>
+											<span className="w-12 text-center">
+												{(
+													currentSettings.codebaseIndexHybridSearchWeight ??
+													CODEBASE_INDEX_DEFAULTS.DEFAULT_HYBRID_SEARCH_WEIGHT
+												).toFixed(2)}
+											</span>
+											<VSCodeButton
+												appearance="icon"
+												title={t("settings:codeIndex.resetToDefault")}
+												onClick={() =>
+													updateSetting(
+														"codebaseIndexHybridSearchWeight",
+														CODEBASE_INDEX_DEFAULTS.DEFAULT_HYBRID_SEARCH_WEIGHT,
+													)
+												}>
+												<span className="codicon codicon-discard" />
+											</VSCodeButton>
+										</div>
+									</div>
+
+									{/* Documentation and config file types */}
+									<div className="space-y-2">
+										<div className="flex items-center gap-2">
+											<label className="text-sm font-medium">
+												{t("settings:codeIndex.documentTypesLabel")}
+											</label>
+											<StandardTooltip content={t("settings:codeIndex.documentTypesDescription")}>
+												<span className="codicon codicon-info text-xs text-vscode-descriptionForeground cursor-help" />
+											</StandardTooltip>
+										</div>
+										{codebaseIndexDocumentTypes.map((type) => (
+											<VSCodeCheckbox
+												key={type}
+												checked={currentSettings.codebaseIndexDocumentTypes?.includes(type)}
+												onChange={(e: any) => toggleDocumentType(type, e.target.checked)}
+												data-testid={`document-type-${type}-checkbox`}>
+												{t(`settings:codeIndex.documentTypes.${type}`)}
+											</VSCodeCheckbox>
+										))}
+									</div>
+
+									{/* Reranker */}
+									<div className="space-y-2">
+										<div className="flex items-center gap-2">
+											<label className="text-sm font-medium">
+												{t("settings:codeIndex.rerankerProviderLabel")}
+											</label>
+											<StandardTooltip content={t("settings:codeIndex.rerankerDescription")}>
+												<span className="codicon codicon-info text-xs text-vscode-descriptionForeground cursor-help" />
+											</StandardTooltip>
+										</div>
+										<Select
+											value={currentSettings.codebaseIndexRerankerProvider ?? "none"}
+											onValueChange={(value) => {
+												updateSetting(
+													"codebaseIndexRerankerProvider",
+													value === "none" ? undefined : (value as RerankerProvider),
+												)
+												updateSetting("codebaseIndexRerankerModelId", "")
+											}}>
+											<SelectTrigger className="w-full" data-testid="reranker-provider-select">
+												<SelectValue />
+											</SelectTrigger>
+											<SelectContent>
+												<SelectItem value="none">
+													{t("settings:codeIndex.rerankerNone")}
+												</SelectItem>
+												<SelectItem value="cohere">
+													{t("settings:codeIndex.rerankerCohere")}
+												</SelectItem>
+												<SelectItem value="voyage">
+													{t("settings:codeIndex.rerankerVoyage")}
+												</SelectItem>
+											</SelectContent>
+										</Select>
+									</div>
+
+									{currentSettings.codebaseIndexRerankerProvider && (
+										<>
+											<div className="space-y-2">
+												<label className="text-sm font-medium">
+													{t("settings:codeIndex.rerankerModelLabel")}
+												</label>
+												<VSCodeTextField
+													value={currentSettings.codebaseIndexRerankerModelId || ""}
+													onInput={(e: any) =>
+														updateSetting("codebaseIndexRerankerModelId", e.target.value)
+													}
+													placeholder={
+														defaultRerankerModels[currentSettings.codebaseIndexRerankerProvider]
+													}
+													className="w-full"
+												/>
+											</div>
+
+											{currentSettings.codebaseIndexRerankerProvider === "cohere" && (
+												<div className="space-y-2">
+													<label className="text-sm font-medium">
+														{t("settings:codeIndex.cohereApiKeyLabel")}
+													</label>
+													<VSCodeTextField
+														type="password"
+														value={currentSettings.codebaseIndexCohereApiKey || ""}
+														onInput={(e: any) =>
+															updateSetting("codebaseIndexCohereApiKey", e.target.value)
+														}
+														placeholder={t("settings:codeIndex.cohereApiKeyPlaceholder")}
+														className="w-full"
+													/>
+												</div>
+											)}
+
+											{currentSettings.codebaseIndexRerankerProvider === "voyage" && (
+												<div className="space-y-2">
+													<label className="text-sm font-medium">
+														{t("settings:codeIndex.voyageApiKeyLabel")}
+													</label>
+													<VSCodeTextField
+														type="password"
+														value={currentSettings.codebaseIndexVoyageApiKey || ""}
+														onInput={(e: any) =>
+															updateSetting("codebaseIndexVoyageApiKey", e.target.value)
+														}
+														placeholder={t("settings:codeIndex.voyageApiKeyPlaceholder")}
+														className="w-full"
+													/>
+												</div>
+											)}
+
+											{/* Rerank Candidates Slider */}
+											<div className="space-y-2">
+												<div className="flex items-center gap-2">
+													<label className="text-sm font-medium">
+														{t("settings:codeIndex.rerankerCandidatesLabel")}
+													</label>
+													<StandardTooltip
+														content={t("settings:codeIndex.rerankerCandidatesDescription")}>
+														<span className="codicon codicon-info text-xs text-vscode-descriptionForeground cursor-help" />
+													</StandardTooltip>
+												</div>
+												<div className="flex items-center gap-2">
+													<Slider
+														min={CODEBASE_INDEX_DEFAULTS.MIN_RERANK_CANDIDATES}
+														max={CODEBASE_INDEX_DEFAULTS.MAX_RERANK_CANDIDATES}
+														step={CODEBASE_INDEX_DEFAULTS.RERANK_CANDIDATES_STEP}
+														value={[
+															currentSettings.codebaseIndexRerankerCandidates ??
+																CODEBASE_INDEX_DEFAULTS.DEFAULT_RERANK_CANDIDATES,
+														]}
+														onValueChange={(values) =>
+															updateSetting("codebaseIndexRerankerCandidates", values[0])
+														}
+														className="flex-1"
+														data-testid="reranker-candidates-slider"
+													/>
+													<span className="w-12 text-center">
+														{currentSettings.codebaseIndexRerankerCandidates ??
+															CODEBASE_INDEX_DEFAULTS.DEFAULT_RERANK_CANDIDATES}
+													</span>
+													<VSCodeButton
+														appearance="icon"
+														title={t("settings:codeIndex.resetToDefault")}
+														onClick={() =>
+															updateSetting(
+																"codebaseIndexRerankerCandidates",
+																CODEBASE_INDEX_DEFAULTS.DEFAULT_RERANK_CANDIDATES,
+															)
+														}>
+														<span className="codicon codicon-discard" />
+													</VSCodeButton>
+												</div>
+											</div>
+										</>
+									)}
 								</div>
 							)}
 						</div>
@@ -0,0 +1,42 @@
+import React, { memo } from "react"
+import { Pin } from "lucide-react"
+
+import { vscode } from "@src/utils/vscode"
+import { useAppTranslation } from "@src/i18n/TranslationContext"
+import { StandardTooltip } from "@src/components/ui"
+import { cn } from "@src/lib/utils"
+
+interface PinMessageButtonProps {
+	ts: number
+	isPinned?: boolean
+	className?: string
+}
+
+// Pinned messages always show the pin, so it doubles as the indicator.
+export const PinMessageButton = memo(({ ts, isPinned, className }: PinMessageButtonProps) => {
+	const { t } = useAppTranslation()
+
+	const handleClick = (e: React.MouseEvent) => {
+		e.stopPropagation()
+		vscode.postMessage({ type: "pinMessage", messageTs: ts, bool: !isPinned })
+	}
+
+	const label = isPinned ? t("chat:pinnedContext.unpinMessage") : t("chat:pinnedContext.pinMessage")
+
+	return (
+		<StandardTooltip content={label}>
+			<button
+				onClick={handleClick}
+				className={cn(
+					"cursor-pointer transition-opacity",
+					isPinned ? "text-vscode-textLink-foreground" : "opacity-0 group-hover:opacity-100",
+					className,
+				)}
+				aria-label={label}
+				aria-pressed={!!isPinned}
+				data-testid="pin-message-button">
+				<Pin className={cn("w-4 h-4", isPinned && "fill-current")} />
+			</button>
+		</StandardTooltip>
+	)
+})
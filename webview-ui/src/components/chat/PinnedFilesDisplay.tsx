@@ -0,0 +1,53 @@
+import { useState } from "react"
+import { ChevronDown, ChevronUp, Pin, X } from "lucide-react"
+
+import { useAppTranslation } from "@src/i18n/TranslationContext"
+import { StandardTooltip } from "@src/components/ui"
+import { vscode } from "@src/utils/vscode"
+
+export function PinnedFilesDisplay({ files }: { files: string[] }) {
+	const { t } = useAppTranslation()
+	const [isCollapsed, setIsCollapsed] = useState(true)
+
+	if (files.length === 0) {
+		return null
+	}
+
+	return (
+		<div
+			data-testid="pinned-files"
+			className="mt-1 -mx-2.5 border-t border-vscode-sideBar-background overflow-hidden"
+			onClick={(e) => e.stopPropagation()}>
+			<div
+				className="flex items-center gap-2 pt-2 px-2.5 cursor-pointer select-none"
+				onClick={() => setIsCollapsed((v) => !v)}>
+				<Pin className="size-3 shrink-0" />
+				<span className="flex-1 overflow-hidden text-ellipsis whitespace-nowrap">
+					{t("chat:pinnedContext.pinnedFiles", { count: files.length })}
+				</span>
+				{isCollapsed ? <ChevronDown className="size-3 shrink-0" /> : <ChevronUp className="size-3 shrink-0" />}
+			</div>
+			{!isCollapsed && (
+				<ul className="list-none max-h-[200px] overflow-y-auto mt-2 -mb-1 pb-0 px-2 cursor-default">
+					{files.map((file) => (
+						<li key={file} className="flex items-center gap-2 min-h-[20px] mb-2">
+							<span
+								className="flex-1 font-light overflow-hidden text-ellipsis whitespace-nowrap cursor-pointer hover:underline"
+								onClick={() => vscode.postMessage({ type: "openFile", text: file })}>
+								{file}
+							</span>
+							<StandardTooltip content={t("chat:pinnedContext.unpinFile")}>
+								<button
+									className="shrink-0 cursor-pointer bg-transparent border-none p-0 opacity-70 hover:opacity-100"
+									aria-label={t("chat:pinnedContext.unpinFile")}
+									onClick={() => vscode.postMessage({ type: "pinFile", text: file, bool: false })}>
+									<X className="size-3" />
+								</button>
+							</StandardTooltip>
+						</li>
+					))}
+				</ul>
+			)}
+		</div>
+	)
+}
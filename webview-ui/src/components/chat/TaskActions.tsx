@@ -9,7 +9,16 @@ import { useExtensionState } from "@/context/ExtensionStateContext"
 
 import { DeleteTaskDialog } from "../history/DeleteTaskDialog"
 import { ShareButton } from "./ShareButton"
-import { CopyIcon, CheckIcon, DownloadIcon, Trash2Icon, FileJsonIcon, MessageSquareCodeIcon } from "lucide-react"
+import {
+	CopyIcon,
+	CheckIcon,
+	DownloadIcon,
+	Trash2Icon,
+	FileJsonIcon,
+	MessageSquareCodeIcon,
+	NetworkIcon,
+	ReceiptTextIcon,
+} from "lucide-react"
 import { LucideIconButton } from "./LucideIconButton"
 
 interface TaskActionsProps {
@@ -21,7 +30,7 @@ export const TaskActions = ({ item, buttonsDisabled }: TaskActionsProps) => {
 	const [deleteTaskId, setDeleteTaskId] = useState<string | null>(null)
 	const { t } = useTranslation()
 	const { copyWithFeedback, showCopyFeedback } = useCopyToClipboard()
-	const { debug } = useExtensionState()
+	const { debug, debugRecorder } = useExtensionState()
 
 	return (
 		<div className="flex flex-row items-center -ml-0.5 mt-1 gap-1">
@@ -30,6 +39,11 @@ export const TaskActions = ({ item, buttonsDisabled }: TaskActionsProps) => {
 				title={t("chat:task.export")}
 				onClick={() => vscode.postMessage({ type: "exportCurrentTask" })}
 			/>
+			<LucideIconButton
+				icon={ReceiptTextIcon}
+				title={t("chat:task.exportReport")}
+				onClick={() => vscode.postMessage({ type: "exportTaskReport" })}
+			/>
 
 			{item?.task && (
 				<LucideIconButton
@@ -77,6 +91,13 @@ export const TaskActions = ({ item, buttonsDisabled }: TaskActionsProps) => {
 					/>
 				</>
 			)}
+			{debugRecorder && item?.id && (
+				<LucideIconButton
+					icon={NetworkIcon}
+					title={t("chat:task.exportProviderTraffic")}
+					onClick={() => vscode.postMessage({ type: "exportProviderTraffic" })}
+				/>
+			)}
 		</div>
 	)
 }
@@ -6,12 +6,12 @@ import DismissibleUpsell from "@src/components/common/DismissibleUpsell"
 import { ChevronUp, ChevronDown, HardDriveDownload, HardDriveUpload, FoldVertical, ArrowLeft } from "lucide-react"
 import prettyBytes from "pretty-bytes"
 
-import type { ClineMessage } from "@roo-code/types"
+import { type ClineMessage, type BudgetUsage, getBudgetLimits } from "@roo-code/types"
 
 import { getModelMaxOutputTokens } from "@roo/api"
 import { findLastIndex } from "@roo/array"
 
-import { formatLargeNumber } from "@src/utils/format"
+import { formatBudgetValue, formatLargeNumber } from "@src/utils/format"
 import { cn } from "@src/lib/utils"
 import { StandardTooltip, Button, Table, TableBody, TableRow, TableCell, CircularProgress } from "@src/components/ui"
 import { useExtensionState } from "@src/context/ExtensionStateContext"
@@ -24,6 +24,7 @@ import { TaskActions } from "./TaskActions"
 import { ContextWindowProgress } from "./ContextWindowProgress"
 import { Mention } from "./Mention"
 import { TodoListDisplay } from "./TodoListDisplay"
+import { PinnedFilesDisplay } from "./PinnedFilesDisplay"
 import { LucideIconButton } from "./LucideIconButton"
 
 export interface TaskHeaderProps {
@@ -60,7 +61,7 @@ const TaskHeader = ({
 	todos,
 }: TaskHeaderProps) => {
 	const { t } = useTranslation()
-	const { apiConfiguration, currentTaskItem, clineMessages } = useExtensionState()
+	const { apiConfiguration, currentTaskItem, clineMessages, mode, taskBudget, modeBudgets } = useExtensionState()
 	const { id: modelId, info: model } = useSelectedModel(apiConfiguration)
 	const [isTaskExpanded, setIsTaskExpanded] = useState(false)
 	const [showLongRunningTaskMessage, setShowLongRunningTaskMessage] = useState(false)
@@ -110,6 +111,14 @@ const TaskHeader = ({
 	)
 	const reservedForOutput = maxTokens || 0
 
+	const budgetLimits = getBudgetLimits({ taskBudget, modeBudgets }, currentTaskItem?.mode ?? mode)
+	const costLimit = budgetLimits.find(({ metric }) => metric === "cost")
+	const budgetUsage: BudgetUsage = {
+		cost: totalCost,
+		tokens: tokensIn + tokensOut,
+		requests: clineMessages?.filter((m) => m.type === "say" && m.say === "api_req_started").length ?? 0,
+	}
+
 	const condenseButton = (
 		<LucideIconButton
 			title={t("chat:task.condenseContext")}
@@ -303,6 +312,7 @@ const TaskHeader = ({
 										<>
 											<span>
 												${(aggregatedCost ?? totalCost).toFixed(2)}
+												{costLimit && <> / {formatBudgetValue("cost", costLimit.limit)}</>}
 												{hasSubtasks && (
 													<span
 														className="text-xs ml-1"
@@ -447,6 +457,30 @@ const TaskHeader = ({
 										</tr>
 									)}
 
+									{budgetLimits.length > 0 && (
+										<tr>
+											<th className="font-medium text-left align-top w-1 whitespace-nowrap pr-3 h-[24px]">
+												{t("chat:task.budget")}
+											</th>
+											<td className="font-light align-top" data-testid="task-budget">
+												<div className="flex items-center gap-x-3 flex-wrap">
+													{budgetLimits.map(({ metric, limit }) => (
+														<span
+															key={metric}
+															className={cn(
+																budgetUsage[metric] > limit && "text-vscode-errorForeground",
+															)}>
+															{t(`chat:task.budgetUsage.${metric}`, {
+																usage: formatBudgetValue(metric, budgetUsage[metric]),
+																limit: formatBudgetValue(metric, limit),
+															})}
+														</span>
+													))}
+												</div>
+											</td>
+										</tr>
+									)}
+
 									{/* Size display */}
 									{!!currentTaskItem?.size && currentTaskItem.size > 0 && (
 										<tr>
@@ -465,6 +499,8 @@ const TaskHeader = ({
 				)}
 				{/* Todo list - always shown at bottom when todos exist */}
 				{hasTodos && <TodoListDisplay todos={todos ?? (task as any)?.tool?.todos ?? []} />}
+				{/* Pinned files - shown at bottom when files are pinned to the task */}
+				<PinnedFilesDisplay files={currentTaskItem?.pinnedFiles ?? []} />
 			</div>
 			<CloudUpsellDialog open={isOpen} onOpenChange={closeUpsell} onConnect={handleConnect} />
 		</div>
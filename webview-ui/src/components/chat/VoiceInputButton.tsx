@@ -0,0 +1,110 @@
+import React, { useCallback, useEffect, useState } from "react"
+import { useEvent } from "react-use"
+import { Loader2, Mic } from "lucide-react"
+
+import type { ExtensionMessage, VoiceInputState } from "@roo-code/types"
+
+import { cn } from "@src/lib/utils"
+import { vscode } from "@src/utils/vscode"
+import { useAppTranslation } from "@src/i18n/TranslationContext"
+import { useExtensionState } from "@src/context/ExtensionStateContext"
+import { StandardTooltip } from "@src/components/ui"
+
+interface VoiceInputButtonProps {
+	onTranscript: (transcript: string) => void
+	disabled?: boolean
+}
+
+/**
+ * Push-to-talk: records while the button, or Space / Enter on it, is held.
+ * The extension records and transcribes, since webviews can't access the
+ * microphone.
+ */
+export const VoiceInputButton: React.FC<VoiceInputButtonProps> = ({ onTranscript, disabled }) => {
+	const { t } = useAppTranslation()
+	const { apiConfiguration } = useExtensionState()
+	const [state, setState] = useState<VoiceInputState>("idle")
+	const [isHeld, setIsHeld] = useState(false)
+
+	useEvent("message", (event: MessageEvent) => {
+		const message: ExtensionMessage = event.data
+
+		if (message.type === "voiceInputState") {
+			setState(message.text as VoiceInputState)
+		} else if (message.type === "voiceInputTranscript" && message.text) {
+			onTranscript(message.text)
+		}
+	})
+
+	// Don't keep recording when the chat goes away mid-recording.
+	useEffect(() => () => vscode.postMessage({ type: "cancelVoiceInput" }), [])
+
+	const press = useCallback(() => {
+		if (!isHeld && state === "idle") {
+			setIsHeld(true)
+			vscode.postMessage({ type: "startVoiceInput" })
+		}
+	}, [isHeld, state])
+
+	const release = useCallback(() => {
+		if (isHeld) {
+			setIsHeld(false)
+			vscode.postMessage({ type: "stopVoiceInput" })
+		}
+	}, [isHeld])
+
+	if (!apiConfiguration?.voiceInputProvider) {
+		return null
+	}
+
+	const label =
+		state === "recording"
+			? t("chat:voice.recording")
+			: state === "transcribing"
+				? t("chat:voice.transcribing")
+				: t("chat:voice.holdToTalk")
+
+	return (
+		<StandardTooltip content={label}>
+			<button
+				aria-label={label}
+				aria-pressed={isHeld}
+				data-testid="voice-input-button"
+				disabled={disabled || state === "transcribing"}
+				onPointerDown={press}
+				onPointerUp={release}
+				onPointerLeave={release}
+				onKeyDown={(e) => {
+					if ((e.key === " " || e.key === "Enter") && !e.repeat) {
+						e.preventDefault()
+						press()
+					}
+				}}
+				onKeyUp={(e) => {
+					if (e.key === " " || e.key === "Enter") {
+						e.preventDefault()
+						release()
+					}
+				}}
+				className={cn(
+					"relative inline-flex items-center justify-center",
+					"bg-transparent border-none p-1.5",
+					"rounded-md min-w-[28px] min-h-[28px]",
+					"text-vscode-foreground opacity-85",
+					"transition-all duration-150",
+					"hover:opacity-100 hover:bg-[rgba(255,255,255,0.03)] hover:border-[rgba(255,255,255,0.15)]",
+					"focus:outline-none focus-visible:ring-1 focus-visible:ring-vscode-focusBorder",
+					"active:bg-[rgba(255,255,255,0.1)]",
+					"disabled:opacity-40 disabled:cursor-not-allowed",
+					state === "recording" && "text-vscode-errorForeground opacity-100",
+					"cursor-pointer",
+				)}>
+				{state === "transcribing" ? (
+					<Loader2 className="w-4 h-4 animate-spin" />
+				) : (
+					<Mic className={cn("w-4 h-4", state === "recording" && "animate-pulse")} />
+				)}
+			</button>
+		</StandardTooltip>
+	)
+}
@@ -46,8 +46,18 @@ vi.mock("use-sound", () => ({
 
 // Mock components that use ESM dependencies
 vi.mock("../ChatRow", () => ({
-	default: function MockChatRow({ message }: { message: ClineMessage }) {
-		return <div data-testid="chat-row">{JSON.stringify(message)}</div>
+	default: function MockChatRow({
+		message,
+		lastModifiedMessage,
+	}: {
+		message: ClineMessage
+		lastModifiedMessage?: ClineMessage
+	}) {
+		return (
+			<div data-testid="chat-row" data-last-modified-ts={lastModifiedMessage?.ts}>
+				{JSON.stringify(message)}
+			</div>
+		)
 	},
 }))
 
@@ -1208,3 +1218,30 @@ describe("ChatView - Context Condensing Indicator Tests", () => {
 		)
 	})
 })
+
+describe("ChatView - Message List Tests", () => {
+	beforeEach(() => {
+		vi.clearAllMocks()
+	})
+
+	it("passes the last message to the last row only", async () => {
+		const { container } = renderChatView()
+
+		mockPostMessage({
+			clineMessages: [
+				{ type: "say", say: "task", ts: Date.now() - 3000, text: "Initial task" },
+				{ type: "say", say: "text", ts: Date.now() - 2000, text: "First answer" },
+				{ type: "say", say: "text", ts: Date.now() - 1000, text: "Second answer", partial: true },
+			],
+		})
+
+		await waitFor(() => {
+			expect(container.querySelectorAll('[data-testid="chat-row"]')).toHaveLength(2)
+		})
+
+		const rows = container.querySelectorAll('[data-testid="chat-row"]')
+		expect(rows[0]).not.toHaveAttribute("data-last-modified-ts")
+		expect(rows[1]).toHaveAttribute("data-last-modified-ts")
+		expect(rows[1]).toHaveTextContent("Second answer")
+	})
+})
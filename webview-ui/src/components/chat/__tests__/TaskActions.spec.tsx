@@ -41,6 +41,7 @@ vi.mock("react-i18next", () => ({
 			const translations: Record<string, string> = {
 				"chat:task.share": "Share task",
 				"chat:task.export": "Export task history",
+				"chat:task.exportReport": "Export task report",
 				"chat:task.delete": "Delete Task (Shift + Click to skip confirmation)",
 				"chat:task.shareWithOrganization": "Share with Organization",
 				"chat:task.shareWithOrganizationDescription": "Only members of your organization can access",
@@ -350,6 +351,14 @@ describe("TaskActions", () => {
 			})
 		})
 
+		it("sends exportTaskReport message when export report button is clicked", () => {
+			render(<TaskActions item={mockItem} buttonsDisabled={false} />)
+
+			fireEvent.click(screen.getByLabelText("Export task report"))
+
+			expect(mockPostMessage).toHaveBeenCalledWith({ type: "exportTaskReport" })
+		})
+
 		it("renders delete button when item has size", () => {
 			render(<TaskActions item={mockItem} buttonsDisabled={false} />)
 
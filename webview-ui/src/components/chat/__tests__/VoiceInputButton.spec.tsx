@@ -0,0 +1,82 @@
+import { render, screen, fireEvent, act } from "@/utils/test-utils"
+
+import { VoiceInputButton } from "../VoiceInputButton"
+
+const { postMessageMock, extensionState } = vi.hoisted(() => ({
+	postMessageMock: vi.fn(),
+	extensionState: { apiConfiguration: {} as Record<string, unknown> },
+}))
+
+vi.mock("@src/utils/vscode", () => ({
+	vscode: { postMessage: postMessageMock },
+}))
+
+vi.mock("@src/i18n/TranslationContext", () => ({
+	useAppTranslation: () => ({ t: (key: string) => key }),
+}))
+
+vi.mock("@src/context/ExtensionStateContext", () => ({
+	useExtensionState: () => extensionState,
+}))
+
+const postExtensionMessage = (message: { type: string; text?: string }) =>
+	act(() => {
+		window.dispatchEvent(new MessageEvent("message", { data: message }))
+	})
+
+describe("VoiceInputButton", () => {
+	beforeEach(() => {
+		postMessageMock.mockClear()
+		extensionState.apiConfiguration = { voiceInputProvider: "local" }
+	})
+
+	it("isn't shown when the profile has no voice input", () => {
+		extensionState.apiConfiguration = {}
+		render(<VoiceInputButton onTranscript={vi.fn()} />)
+
+		expect(screen.queryByTestId("voice-input-button")).toBeNull()
+	})
+
+	it("records while the button is held", () => {
+		render(<VoiceInputButton onTranscript={vi.fn()} />)
+		const button = screen.getByTestId("voice-input-button")
+
+		fireEvent.pointerDown(button)
+		expect(postMessageMock).toHaveBeenCalledWith({ type: "startVoiceInput" })
+
+		fireEvent.pointerUp(button)
+		expect(postMessageMock).toHaveBeenCalledWith({ type: "stopVoiceInput" })
+	})
+
+	it("records while Space is held", () => {
+		render(<VoiceInputButton onTranscript={vi.fn()} />)
+		const button = screen.getByTestId("voice-input-button")
+
+		fireEvent.keyDown(button, { key: " " })
+		fireEvent.keyUp(button, { key: " " })
+
+		expect(postMessageMock.mock.calls.map(([message]) => message.type)).toEqual([
+			"startVoiceInput",
+			"stopVoiceInput",
+		])
+	})
+
+	it("shows the state of the recording and passes on the transcript", () => {
+		const onTranscript = vi.fn()
+		render(<VoiceInputButton onTranscript={onTranscript} />)
+
+		postExtensionMessage({ type: "voiceInputState", text: "transcribing" })
+		expect(screen.getByTestId("voice-input-button")).toBeDisabled()
+		expect(screen.getByLabelText("chat:voice.transcribing")).toBeInTheDocument()
+
+		postExtensionMessage({ type: "voiceInputTranscript", text: "Run the tests" })
+		expect(onTranscript).toHaveBeenCalledWith("Run the tests")
+	})
+
+	it("cancels the recording when it goes away", () => {
+		const { unmount } = render(<VoiceInputButton onTranscript={vi.fn()} />)
+		unmount()
+
+		expect(postMessageMock).toHaveBeenCalledWith({ type: "cancelVoiceInput" })
+	})
+})
@@ -73,11 +73,23 @@ export const CheckpointMenu = ({ ts, commitHash, checkpoint, onOpenChange }: Che
 		})
 	}, [ts, commitHash])
 
+	const onDiffWithCheckpoint = useCallback(() => {
+		vscode.postMessage({
+			type: "checkpointDiff",
+			payload: { ts, commitHash, mode: "compare" },
+		})
+	}, [ts, commitHash])
+
 	const onPreview = useCallback(() => {
 		vscode.postMessage({ type: "checkpointRestore", payload: { ts, commitHash, mode: "preview" } })
 		setRestoreOpen(false)
 	}, [ts, commitHash, setRestoreOpen])
 
+	const onRestoreSelectedFiles = useCallback(() => {
+		vscode.postMessage({ type: "checkpointRestoreFiles", payload: { commitHash } })
+		setRestoreOpen(false)
+	}, [commitHash, setRestoreOpen])
+
 	const onRestore = useCallback(() => {
 		vscode.postMessage({ type: "checkpointRestore", payload: { ts, commitHash, mode: "restore" } })
 		setRestoreOpen(false)
@@ -124,6 +136,17 @@ export const CheckpointMenu = ({ ts, commitHash, checkpoint, onOpenChange }: Che
 								{t("chat:checkpoint.menu.restoreFilesDescription")}
 							</div>
 						</div>
+						<div className="flex flex-col gap-1 group hover:text-foreground">
+							<Button
+								variant="secondary"
+								onClick={onRestoreSelectedFiles}
+								data-testid="restore-selected-files-btn">
+								{t("chat:checkpoint.menu.restoreSelectedFiles")}
+							</Button>
+							<div className="text-muted transition-colors group-hover:text-foreground">
+								{t("chat:checkpoint.menu.restoreSelectedFilesDescription")}
+							</div>
+						</div>
 						<div className="flex flex-col gap-1 group hover:text-foreground">
 							{!restoreConfirming ? (
 								<Button
@@ -193,6 +216,15 @@ export const CheckpointMenu = ({ ts, commitHash, checkpoint, onOpenChange }: Che
 							<span className="codicon codicon-diff mr-2" />
 							{t("chat:checkpoint.menu.viewDiffWithCurrent")}
 						</Button>
+						<Button
+							variant="secondary"
+							onClick={() => {
+								onDiffWithCheckpoint()
+								setMoreOpen(false)
+							}}>
+							<span className="codicon codicon-git-compare mr-2" />
+							{t("chat:checkpoint.menu.viewDiffWithCheckpoint")}
+						</Button>
 					</div>
 				</PopoverContent>
 			</Popover>
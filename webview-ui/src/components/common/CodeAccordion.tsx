@@ -3,11 +3,17 @@ import { VSCodeProgressRing } from "@vscode/webview-ui-toolkit/react"
 import { type ToolProgressStatus } from "@roo-code/types"
 import { getLanguageFromPath } from "@src/utils/getLanguageFromPath"
 import { formatPathTooltip } from "@src/utils/formatPathTooltip"
+import { vscode } from "@src/utils/vscode"
+import { cn } from "@src/lib/utils"
+import { useExtensionState } from "@src/context/ExtensionStateContext"
+import { useAppTranslation } from "@src/i18n/TranslationContext"
 
 import { ToolUseBlock, ToolUseBlockHeader } from "./ToolUseBlock"
 import CodeBlock from "./CodeBlock"
 import { PathTooltip } from "../ui/PathTooltip"
+import { StandardTooltip } from "../ui"
 import DiffView from "./DiffView"
+import DeferredRender from "./DeferredRender"
 
 interface CodeAccordionProps {
 	path?: string
@@ -37,10 +43,19 @@ const CodeAccordion = ({
 	onJumpToFile,
 	diffStats,
 }: CodeAccordionProps) => {
+	const { t } = useAppTranslation()
+	const { diffViewLayout = "inline", setDiffViewLayout } = useExtensionState()
 	const inferredLanguage = useMemo(() => language ?? (path ? getLanguageFromPath(path) : "txt"), [path, language])
 	const source = useMemo(() => code.trim(), [code])
 	const hasHeader = Boolean(path || isFeedback || header)
 
+	// The layout is shared by all diffs in the chat.
+	const toggleDiffViewLayout = () => {
+		const layout = diffViewLayout === "sideBySide" ? "inline" : "sideBySide"
+		setDiffViewLayout(layout)
+		vscode.postMessage({ type: "updateSettings", updatedSettings: { diffViewLayout: layout } })
+	}
+
 	// Use provided diff stats only (render-only)
 	const derivedStats = useMemo(() => {
 		if (diffStats && (diffStats.added > 0 || diffStats.removed > 0)) return diffStats
@@ -98,6 +113,25 @@ const CodeAccordion = ({
 							</>
 						)
 					)}
+					{inferredLanguage === "diff" && isExpanded && (
+						<StandardTooltip content={t("chat:diffView.sideBySide")}>
+							<span
+								className={cn(
+									"codicon codicon-split-horizontal mr-1",
+									diffViewLayout !== "sideBySide" && "opacity-60",
+								)}
+								style={{ fontSize: 13.5 }}
+								onClick={(e) => {
+									e.stopPropagation()
+									toggleDiffViewLayout()
+								}}
+								role="button"
+								aria-pressed={diffViewLayout === "sideBySide"}
+								aria-label={t("chat:diffView.sideBySide")}
+								data-testid="diff-layout-toggle"
+							/>
+						</StandardTooltip>
+					)}
 					{onJumpToFile && path && (
 						<span
 							className="codicon codicon-link-external mr-1"
@@ -117,11 +151,15 @@ const CodeAccordion = ({
 			)}
 			{(!hasHeader || isExpanded) && (
 				<div className="overflow-x-auto overflow-y-auto max-h-[300px] max-w-full">
-					{inferredLanguage === "diff" ? (
-						<DiffView source={source} filePath={path} />
-					) : (
-						<CodeBlock source={source} language={inferredLanguage} />
-					)}
+					<DeferredRender source={source}>
+						{() =>
+							inferredLanguage === "diff" ? (
+								<DiffView source={source} filePath={path} layout={diffViewLayout} />
+							) : (
+								<CodeBlock source={source} language={inferredLanguage} />
+							)
+						}
+					</DeferredRender>
 				</div>
 			)}
 		</ToolUseBlock>
@@ -0,0 +1,62 @@
+import { ReactNode, useState } from "react"
+
+import { useAppTranslation } from "@src/i18n/TranslationContext"
+
+import { CODE_BLOCK_BG_COLOR } from "./CodeBlock"
+
+// Code blocks and diffs longer than this are only highlighted once they're asked for.
+export const DEFERRED_RENDER_LINE_COUNT = 300
+
+const PREVIEW_LINE_COUNT = 12
+
+export const countLines = (source: string) => {
+	let count = 1
+
+	for (let index = source.indexOf("\n"); index !== -1; index = source.indexOf("\n", index + 1)) {
+		count++
+	}
+
+	return count
+}
+
+interface DeferredRenderProps {
+	source: string
+	/** Renders the full content, only called once it's shown */
+	children: () => ReactNode
+}
+
+/**
+ * Shows a plain preview of a large code block or diff instead of rendering it,
+ * until it's expanded, so that long tasks don't highlight every block they
+ * contain up front.
+ *
+ * Content that's small when it mounts stays rendered as it grows, so a block
+ * that's being streamed isn't replaced by the preview.
+ */
+const DeferredRender = ({ source, children }: DeferredRenderProps) => {
+	const { t } = useAppTranslation()
+	const [isRendered, setIsRendered] = useState(() => countLines(source) <= DEFERRED_RENDER_LINE_COUNT)
+
+	if (isRendered) {
+		return <>{children()}</>
+	}
+
+	const preview = source.split("\n", PREVIEW_LINE_COUNT).join("\n")
+
+	return (
+		<div className="rounded-xs overflow-hidden" style={{ backgroundColor: CODE_BLOCK_BG_COLOR }}>
+			<pre className="m-0 p-2.5 overflow-x-auto text-vscode-editor-font-size font-mono opacity-70">
+				{preview}
+			</pre>
+			<button
+				className="w-full py-1 border-0 cursor-pointer bg-vscode-button-secondaryBackground text-vscode-button-secondaryForeground hover:bg-vscode-button-secondaryHoverBackground"
+				onClick={() => setIsRendered(true)}
+				data-testid="deferred-render-expand">
+				<span className="codicon codicon-chevron-down mr-1 align-middle" />
+				{t("chat:codeblock.showAllLines", { count: countLines(source) })}
+			</button>
+		</div>
+	)
+}
+
+export default DeferredRender
@@ -1,12 +1,14 @@
 import { memo, useMemo, useEffect, useState } from "react"
-import { parseUnifiedDiff, type DiffLine } from "@src/utils/parseUnifiedDiff"
+import { parseUnifiedDiff, pairDiffLines, type DiffLine } from "@src/utils/parseUnifiedDiff"
 import { normalizeLanguage } from "@src/utils/highlighter"
 import { getLanguageFromPath } from "@src/utils/getLanguageFromPath"
-import { highlightHunks } from "@src/utils/highlightDiff"
+import { highlightHunks, type HunkWordChanges } from "@src/utils/highlightDiff"
+import type { WordRange } from "@src/utils/wordDiff"
 
 interface DiffViewProps {
 	source: string
 	filePath?: string
+	layout?: "inline" | "sideBySide"
 }
 
 // Interface for hunk data
@@ -14,15 +16,61 @@ interface Hunk {
 	lines: DiffLine[]
 	oldText: string
 	newText: string
+	wordChanges: HunkWordChanges
 	highlightedOldLines?: React.ReactNode[]
 	highlightedNewLines?: React.ReactNode[]
 }
 
+function createHunk(lines: DiffLine[]): Hunk {
+	const oldLines: string[] = []
+	const newLines: string[] = []
+	const wordChanges: HunkWordChanges = { oldLines: [], newLines: [] }
+
+	for (const hunkLine of lines) {
+		if (hunkLine.type === "deletion" || hunkLine.type === "context") {
+			oldLines.push(hunkLine.content)
+			wordChanges.oldLines.push(hunkLine.wordChanges)
+		}
+		if (hunkLine.type === "addition" || hunkLine.type === "context") {
+			newLines.push(hunkLine.content)
+			wordChanges.newLines.push(hunkLine.wordChanges)
+		}
+	}
+
+	return { lines: [...lines], oldText: oldLines.join("\n"), newText: newLines.join("\n"), wordChanges }
+}
+
+// Wraps the changed words of an unhighlighted line.
+function renderWordChanges(content: string, ranges: WordRange[] | undefined, className: string): React.ReactNode {
+	if (!ranges?.length) {
+		return content
+	}
+
+	const parts: React.ReactNode[] = []
+	let offset = 0
+
+	ranges.forEach(([start, end], index) => {
+		parts.push(content.slice(offset, start))
+		parts.push(
+			<span key={index} className={className}>
+				{content.slice(start, end)}
+			</span>,
+		)
+		offset = end
+	})
+
+	parts.push(content.slice(offset))
+
+	return parts
+}
+
 /**
  * DiffView component renders unified diffs with side-by-side line numbers
- * matching VSCode's diff editor style
+ * matching VSCode's diff editor style, highlighting the words that changed
+ * within a line. The "sideBySide" layout shows the old and new version in
+ * two columns instead.
  */
-const DiffView = memo(({ source, filePath }: DiffViewProps) => {
+const DiffView = memo(({ source, filePath, layout = "inline" }: DiffViewProps) => {
 	// Determine language from file path
 	const normalizedLang = useMemo(() => normalizeLanguage(getLanguageFromPath(filePath || "") || "txt"), [filePath])
 
@@ -49,23 +97,7 @@ const DiffView = memo(({ source, filePath }: DiffViewProps) => {
 			if (line.type === "gap") {
 				// Finish current hunk if it has content
 				if (currentHunk.length > 0) {
-					const oldLines: string[] = []
-					const newLines: string[] = []
-
-					for (const hunkLine of currentHunk) {
-						if (hunkLine.type === "deletion" || hunkLine.type === "context") {
-							oldLines.push(hunkLine.content)
-						}
-						if (hunkLine.type === "addition" || hunkLine.type === "context") {
-							newLines.push(hunkLine.content)
-						}
-					}
-
-					result.push({
-						lines: [...currentHunk],
-						oldText: oldLines.join("\n"),
-						newText: newLines.join("\n"),
-					})
+					result.push(createHunk(currentHunk))
 				}
 
 				// Start new hunk with the gap
@@ -77,23 +109,7 @@ const DiffView = memo(({ source, filePath }: DiffViewProps) => {
 
 		// Add the last hunk if it has content
 		if (currentHunk.length > 0 && currentHunk.some((line) => line.type !== "gap")) {
-			const oldLines: string[] = []
-			const newLines: string[] = []
-
-			for (const hunkLine of currentHunk) {
-				if (hunkLine.type === "deletion" || hunkLine.type === "context") {
-					oldLines.push(hunkLine.content)
-				}
-				if (hunkLine.type === "addition" || hunkLine.type === "context") {
-					newLines.push(hunkLine.content)
-				}
-			}
-
-			result.push({
-				lines: [...currentHunk],
-				oldText: oldLines.join("\n"),
-				newText: newLines.join("\n"),
-			})
+			result.push(createHunk(currentHunk))
 		}
 
 		return result
@@ -122,6 +138,7 @@ const DiffView = memo(({ source, filePath }: DiffViewProps) => {
 						isLightTheme ? "light" : "dark",
 						i,
 						filePath,
+						hunk.wordChanges,
 					)
 					processed.push({
 						...hunk,
@@ -142,29 +159,131 @@ const DiffView = memo(({ source, filePath }: DiffViewProps) => {
 
 	// Render helper that uses precomputed highlighting
 	const renderContent = (line: DiffLine, hunk: Hunk, lineIndexInHunk: number): React.ReactNode => {
+		const plainContent = renderWordChanges(
+			line.content,
+			line.wordChanges,
+			line.type === "deletion" ? "diff-word-removed" : "diff-word-inserted",
+		)
+
 		if (!shouldHighlight || !hunk.highlightedOldLines || !hunk.highlightedNewLines) {
-			return line.content
+			return plainContent
 		}
 
 		// Find the line index within the old/new text for this hunk
 		const hunkLinesBeforeThis = hunk.lines.slice(0, lineIndexInHunk).filter((l) => l.type !== "gap")
+		let highlighted: React.ReactNode
 
 		if (line.type === "deletion") {
 			// Count deletions and context lines before this line
 			const oldLineIndex = hunkLinesBeforeThis.filter((l) => l.type === "deletion" || l.type === "context").length
-			return hunk.highlightedOldLines[oldLineIndex] || line.content
+			highlighted = hunk.highlightedOldLines[oldLineIndex]
 		} else if (line.type === "addition") {
 			// Count additions and context lines before this line
 			const newLineIndex = hunkLinesBeforeThis.filter((l) => l.type === "addition" || l.type === "context").length
-			return hunk.highlightedNewLines[newLineIndex] || line.content
+			highlighted = hunk.highlightedNewLines[newLineIndex]
 		} else if (line.type === "context") {
 			// For context lines, prefer new-side highlighting, fall back to old-side
 			const newLineIndex = hunkLinesBeforeThis.filter((l) => l.type === "addition" || l.type === "context").length
 			const oldLineIndex = hunkLinesBeforeThis.filter((l) => l.type === "deletion" || l.type === "context").length
-			return hunk.highlightedNewLines[newLineIndex] || hunk.highlightedOldLines[oldLineIndex] || line.content
+			highlighted = hunk.highlightedNewLines[newLineIndex] || hunk.highlightedOldLines[oldLineIndex]
+		}
+
+		// Highlighting falls back to plain strings, which don't have the changed words.
+		return !highlighted || typeof highlighted === "string" ? plainContent : highlighted
+	}
+
+	// Use VSCode's built-in diff editor color variables as classes for gutters
+	const getGutterBgClass = (line: DiffLine) =>
+		line.type === "addition"
+			? "bg-[var(--vscode-diffEditor-insertedTextBackground)]"
+			: line.type === "deletion"
+				? "bg-[var(--vscode-diffEditor-removedTextBackground)]"
+				: "bg-[var(--vscode-editorGroup-border)]"
+
+	const getContentBgClass = (line: DiffLine) =>
+		line.type === "addition"
+			? "diff-content-inserted"
+			: line.type === "deletion"
+				? "diff-content-removed"
+				: "diff-content-context"
+
+	const getSign = (line: DiffLine) => (line.type === "addition" ? "+" : line.type === "deletion" ? "-" : "")
+
+	if (layout === "sideBySide") {
+		const renderSide = (hunk: Hunk, lineIndex: number | undefined, lineNum: "oldLineNum" | "newLineNum") => {
+			if (lineIndex === undefined) {
+				// The other side added or removed more lines.
+				return (
+					<>
+						<td className="bg-[var(--vscode-editor-background)]" />
+						<td className="bg-[var(--vscode-editor-background)]" />
+						<td className="bg-[var(--vscode-editor-background)]" />
+					</>
+				)
+			}
+
+			const line = hunk.lines[lineIndex]
+			const gutterBgClass = getGutterBgClass(line)
+
+			return (
+				<>
+					<td className={`text-right pr-1 pl-1 select-none align-top whitespace-nowrap ${gutterBgClass}`}>
+						{line[lineNum] || ""}
+					</td>
+					<td className={`text-center select-none whitespace-nowrap px-1 ${gutterBgClass}`}>
+						{getSign(line)}
+					</td>
+					<td className={`pl-1 pr-3 whitespace-pre-wrap break-words ${getContentBgClass(line)}`}>
+						{renderContent(line, hunk, lineIndex)}
+					</td>
+				</>
+			)
 		}
 
-		return line.content
+		return (
+			<div
+				className="diff-view bg-[var(--vscode-editor-background)] rounded-md overflow-hidden text-[0.95em]"
+				data-testid="diff-view-side-by-side">
+				<div className="overflow-x-hidden">
+					<table className="w-full border-collapse table-fixed">
+						<colgroup>
+							<col className="w-[45px]" />
+							<col className="w-[16px]" />
+							<col />
+							<col className="w-[45px]" />
+							<col className="w-[16px]" />
+							<col />
+						</colgroup>
+						<tbody>
+							{processedHunks.flatMap((hunk, hunkIndex) =>
+								pairDiffLines(hunk.lines).map(({ left, right }, rowIndex) => {
+									const line = left === undefined ? undefined : hunk.lines[left]
+
+									if (line?.type === "gap") {
+										return (
+											<tr key={`${hunkIndex}-${rowIndex}`}>
+												<td
+													colSpan={6}
+													className="pl-2 pr-3 italic bg-[var(--vscode-editor-background)]">
+													{`${line.hiddenCount ?? 0} hidden lines`}
+												</td>
+											</tr>
+										)
+									}
+
+									return (
+										<tr key={`${hunkIndex}-${rowIndex}`}>
+											{renderSide(hunk, left, "oldLineNum")}
+											{renderSide(hunk, right, "newLineNum")}
+										</tr>
+									)
+								}),
+							)}
+						</tbody>
+					</table>
+				</div>
+			</div>
+		)
 	}
 
 	return (
@@ -192,22 +311,9 @@ const DiffView = memo(({ source, filePath }: DiffViewProps) => {
 									)
 								}
 
-								// Use VSCode's built-in diff editor color variables as classes for gutters
-								const gutterBgClass =
-									line.type === "addition"
-										? "bg-[var(--vscode-diffEditor-insertedTextBackground)]"
-										: line.type === "deletion"
-											? "bg-[var(--vscode-diffEditor-removedTextBackground)]"
-											: "bg-[var(--vscode-editorGroup-border)]"
-
-								const contentBgClass =
-									line.type === "addition"
-										? "diff-content-inserted"
-										: line.type === "deletion"
-											? "diff-content-removed"
-											: "diff-content-context"
-
-								const sign = line.type === "addition" ? "+" : line.type === "deletion" ? "-" : ""
+								const gutterBgClass = getGutterBgClass(line)
+								const contentBgClass = getContentBgClass(line)
+								const sign = getSign(line)
 
 								return (
 									<tr key={globalIndex}>
@@ -9,6 +9,7 @@ import remarkGfm from "remark-gfm"
 import { vscode } from "@src/utils/vscode"
 
 import CodeBlock from "./CodeBlock"
+import DeferredRender from "./DeferredRender"
 import MermaidBlock from "./MermaidBlock"
 
 interface MarkdownBlockProps {
@@ -287,7 +288,9 @@ const MarkdownBlock = memo(({ markdown }: MarkdownBlockProps) => {
 				// Wrap CodeBlock in a div to ensure proper separation
 				return (
 					<div style={{ margin: "1em 0" }}>
-						<CodeBlock source={codeString} language={language} />
+						<DeferredRender source={codeString}>
+							{() => <CodeBlock source={codeString} language={language} />}
+						</DeferredRender>
 					</div>
 				)
 			},
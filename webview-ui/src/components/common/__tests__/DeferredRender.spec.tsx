@@ -0,0 +1,54 @@
+import { render, screen, fireEvent } from "@/utils/test-utils"
+
+import DeferredRender, { DEFERRED_RENDER_LINE_COUNT, countLines } from "../DeferredRender"
+
+vi.mock("@src/i18n/TranslationContext", () => ({
+	useAppTranslation: () => ({
+		t: (key: string, options?: { count?: number }) => `${key} ${options?.count}`,
+	}),
+}))
+
+const lines = (count: number) => Array.from({ length: count }, (_, index) => `line ${index + 1}`).join("\n")
+
+describe("DeferredRender", () => {
+	it("renders small content right away", () => {
+		const renderContent = vi.fn(() => <div data-testid="content" />)
+		render(<DeferredRender source={lines(10)}>{renderContent}</DeferredRender>)
+
+		expect(screen.getByTestId("content")).toBeInTheDocument()
+		expect(screen.queryByTestId("deferred-render-expand")).not.toBeInTheDocument()
+	})
+
+	it("shows a preview of large content until it's expanded", () => {
+		const source = lines(DEFERRED_RENDER_LINE_COUNT + 1)
+		const renderContent = vi.fn(() => <div data-testid="content" />)
+		render(<DeferredRender source={source}>{renderContent}</DeferredRender>)
+
+		expect(renderContent).not.toHaveBeenCalled()
+		expect(screen.getByText(/^line 1 line 2 .* line 12$/)).toBeInTheDocument()
+		expect(screen.queryByText(/line 13/)).not.toBeInTheDocument()
+		expect(screen.getByTestId("deferred-render-expand")).toHaveTextContent(
+			`chat:codeblock.showAllLines ${DEFERRED_RENDER_LINE_COUNT + 1}`,
+		)
+
+		fireEvent.click(screen.getByTestId("deferred-render-expand"))
+
+		expect(screen.getByTestId("content")).toBeInTheDocument()
+	})
+
+	it("keeps content that grows past the limit rendered", () => {
+		const renderContent = () => <div data-testid="content" />
+		const { rerender } = render(<DeferredRender source={lines(10)}>{renderContent}</DeferredRender>)
+
+		rerender(<DeferredRender source={lines(DEFERRED_RENDER_LINE_COUNT * 2)}>{renderContent}</DeferredRender>)
+
+		expect(screen.getByTestId("content")).toBeInTheDocument()
+	})
+})
+
+describe("countLines", () => {
+	it("counts the lines of a source", () => {
+		expect(countLines("")).toBe(1)
+		expect(countLines("a\nb\nc")).toBe(3)
+	})
+})
@@ -0,0 +1,42 @@
+import { render, screen, waitFor } from "@/utils/test-utils"
+
+import DiffView from "../DiffView"
+
+// Without syntax highlighting the lines are rendered as plain text.
+vi.mock("@src/utils/highlightDiff", () => ({
+	highlightHunks: vi.fn().mockRejectedValue(new Error("no highlighter")),
+}))
+
+const source = [
+	"--- a/total.ts",
+	"+++ b/total.ts",
+	"@@ -1,3 +1,3 @@",
+	" import { sum } from './sum'",
+	"-const total = sum(items, 1)",
+	"+const total = sum(values, 1)",
+	" export default total",
+	"",
+].join("\n")
+
+describe("DiffView", () => {
+	it("highlights the words that changed within a line", async () => {
+		const { container } = render(<DiffView source={source} filePath="total.ts" />)
+
+		await waitFor(() => {
+			expect(container.querySelector(".diff-word-removed")?.textContent).toBe("items")
+		})
+		expect(container.querySelector(".diff-word-inserted")?.textContent).toBe("values")
+	})
+
+	it("shows the old and new version next to each other", async () => {
+		const { container } = render(<DiffView source={source} filePath="total.ts" layout="sideBySide" />)
+
+		expect(screen.getByTestId("diff-view-side-by-side")).toBeInTheDocument()
+
+		// The context lines, and the changed line paired with its replacement.
+		const rows = container.querySelectorAll("tbody tr")
+		expect(rows).toHaveLength(3)
+		expect(rows[1].querySelector(".diff-content-removed")?.textContent).toBe("const total = sum(items, 1)")
+		expect(rows[1].querySelector(".diff-content-inserted")?.textContent).toBe("const total = sum(values, 1)")
+	})
+})
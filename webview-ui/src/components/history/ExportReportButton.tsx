@@ -0,0 +1,29 @@
+import { vscode } from "@/utils/vscode"
+import { Button, StandardTooltip } from "@/components/ui"
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { useCallback } from "react"
+
+export const ExportReportButton = ({ itemId }: { itemId: string }) => {
+	const { t } = useAppTranslation()
+
+	const handleExportClick = useCallback(
+		(e: React.MouseEvent) => {
+			e.stopPropagation()
+			vscode.postMessage({ type: "exportTaskReport", text: itemId })
+		},
+		[itemId],
+	)
+
+	return (
+		<StandardTooltip content={t("history:exportReport")}>
+			<Button
+				data-testid="export-report"
+				variant="ghost"
+				size="icon"
+				className="group-hover:opacity-100 opacity-50 transition-opacity"
+				onClick={handleExportClick}>
+				<span className="codicon codicon-graph scale-80" />
+			</Button>
+		</StandardTooltip>
+	)
+}
@@ -0,0 +1,135 @@
+import { useMemo } from "react"
+
+import { getAllModes } from "@roo/modes"
+
+import { Button, Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui"
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { useExtensionState } from "@/context/ExtensionStateContext"
+
+import type { DateFilter, TaskFilters } from "./useTaskSearch"
+
+// Radix selects can't have an empty value, so this one stands for "any".
+const ANY = "any"
+
+const DATE_FILTERS: DateFilter[] = ["all", "day", "week", "month"]
+const MIN_COSTS = [0.1, 1, 5]
+
+interface HistoryFiltersProps {
+	filters: TaskFilters
+	onChange: (filters: TaskFilters) => void
+	options: { modes: string[]; apiConfigNames: string[]; tags: string[] }
+}
+
+export const countActiveFilters = (filters: TaskFilters) =>
+	[filters.mode, filters.apiConfigName, filters.tag, filters.minCost].filter((value) => value !== undefined).length +
+	(filters.date === "all" ? 0 : 1)
+
+export const HistoryFilters = ({ filters, onChange, options }: HistoryFiltersProps) => {
+	const { t } = useAppTranslation()
+	const { customModes } = useExtensionState()
+
+	const modeNames = useMemo(
+		() => new Map(getAllModes(customModes).map((mode) => [mode.slug, mode.name])),
+		[customModes],
+	)
+
+	const update = (changes: Partial<TaskFilters>) => onChange({ ...filters, ...changes })
+
+	return (
+		<div className="grid grid-cols-2 gap-2" data-testid="history-filters">
+			<Select
+				value={filters.mode ?? ANY}
+				onValueChange={(value) => update({ mode: value === ANY ? undefined : value })}>
+				<SelectTrigger data-testid="filter-mode">
+					<SelectValue>
+						{t("history:filters.mode")}{" "}
+						{filters.mode ? (modeNames.get(filters.mode) ?? filters.mode) : t("history:filters.any")}
+					</SelectValue>
+				</SelectTrigger>
+				<SelectContent>
+					<SelectItem value={ANY}>{t("history:filters.any")}</SelectItem>
+					{options.modes.map((mode) => (
+						<SelectItem key={mode} value={mode}>
+							{modeNames.get(mode) ?? mode}
+						</SelectItem>
+					))}
+				</SelectContent>
+			</Select>
+			<Select
+				value={filters.apiConfigName ?? ANY}
+				onValueChange={(value) => update({ apiConfigName: value === ANY ? undefined : value })}>
+				<SelectTrigger data-testid="filter-provider">
+					<SelectValue>
+						{t("history:filters.provider")} {filters.apiConfigName ?? t("history:filters.any")}
+					</SelectValue>
+				</SelectTrigger>
+				<SelectContent>
+					<SelectItem value={ANY}>{t("history:filters.any")}</SelectItem>
+					{options.apiConfigNames.map((name) => (
+						<SelectItem key={name} value={name}>
+							{name}
+						</SelectItem>
+					))}
+				</SelectContent>
+			</Select>
+			<Select value={filters.date} onValueChange={(value) => update({ date: value as DateFilter })}>
+				<SelectTrigger data-testid="filter-date">
+					<SelectValue>
+						{t("history:filters.date")} {t(`history:filters.dates.${filters.date}`)}
+					</SelectValue>
+				</SelectTrigger>
+				<SelectContent>
+					{DATE_FILTERS.map((date) => (
+						<SelectItem key={date} value={date}>
+							{t(`history:filters.dates.${date}`)}
+						</SelectItem>
+					))}
+				</SelectContent>
+			</Select>
+			<Select
+				value={filters.minCost?.toString() ?? ANY}
+				onValueChange={(value) => update({ minCost: value === ANY ? undefined : Number(value) })}>
+				<SelectTrigger data-testid="filter-cost">
+					<SelectValue>
+						{t("history:filters.cost")}{" "}
+						{filters.minCost !== undefined
+							? t("history:filters.minCost", { cost: filters.minCost.toFixed(2) })
+							: t("history:filters.any")}
+					</SelectValue>
+				</SelectTrigger>
+				<SelectContent>
+					<SelectItem value={ANY}>{t("history:filters.any")}</SelectItem>
+					{MIN_COSTS.map((cost) => (
+						<SelectItem key={cost} value={cost.toString()}>
+							{t("history:filters.minCost", { cost: cost.toFixed(2) })}
+						</SelectItem>
+					))}
+				</SelectContent>
+			</Select>
+			{options.tags.length > 0 && (
+				<Select
+					value={filters.tag ?? ANY}
+					onValueChange={(value) => update({ tag: value === ANY ? undefined : value })}>
+					<SelectTrigger data-testid="filter-tag">
+						<SelectValue>
+							{t("history:filters.tag")} {filters.tag ?? t("history:filters.any")}
+						</SelectValue>
+					</SelectTrigger>
+					<SelectContent>
+						<SelectItem value={ANY}>{t("history:filters.any")}</SelectItem>
+						{options.tags.map((tag) => (
+							<SelectItem key={tag} value={tag}>
+								{tag}
+							</SelectItem>
+						))}
+					</SelectContent>
+				</Select>
+			)}
+			{countActiveFilters(filters) > 0 && (
+				<Button variant="secondary" onClick={() => onChange({ date: "all" })} data-testid="clear-filters">
+					{t("history:filters.clear")}
+				</Button>
+			)}
+		</div>
+	)
+}
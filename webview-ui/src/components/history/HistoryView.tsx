@@ -21,6 +21,7 @@ import { useAppTranslation } from "@/i18n/TranslationContext"
 import { Tab, TabContent, TabHeader } from "../common/Tab"
 import { useTaskSearch } from "./useTaskSearch"
 import { useGroupedTasks } from "./useGroupedTasks"
+import { HistoryFilters, countActiveFilters } from "./HistoryFilters"
 import { countAllSubtasks } from "./types"
 import TaskItem from "./TaskItem"
 import TaskGroupItem from "./TaskGroupItem"
@@ -41,6 +42,9 @@ const HistoryView = ({ onDone }: HistoryViewProps) => {
 		setLastNonRelevantSort,
 		showAllWorkspaces,
 		setShowAllWorkspaces,
+		filters,
+		setFilters,
+		filterOptions,
 	} = useTaskSearch()
 	const { t } = useAppTranslation()
 
@@ -52,6 +56,8 @@ const HistoryView = ({ onDone }: HistoryViewProps) => {
 	const [isSelectionMode, setIsSelectionMode] = useState(false)
 	const [selectedTaskIds, setSelectedTaskIds] = useState<string[]>([])
 	const [showBatchDeleteDialog, setShowBatchDeleteDialog] = useState<boolean>(false)
+	const [showFilters, setShowFilters] = useState(false)
+	const activeFilterCount = countActiveFilters(filters)
 
 	// Get subtask count for a task (recursive total)
 	const getSubtaskCount = useMemo(() => {
@@ -223,8 +229,20 @@ const HistoryView = ({ onDone }: HistoryViewProps) => {
 								</SelectItem>
 							</SelectContent>
 						</Select>
+						<StandardTooltip content={t("history:filters.title")}>
+							<Button
+								variant={activeFilterCount > 0 ? "primary" : "secondary"}
+								onClick={() => setShowFilters(!showFilters)}
+								aria-label={t("history:filters.title")}
+								data-testid="toggle-filters-button">
+								<span className="codicon codicon-filter" />
+								{activeFilterCount > 0 && <span className="ml-1">{activeFilterCount}</span>}
+							</Button>
+						</StandardTooltip>
 					</div>
 
+					{showFilters && <HistoryFilters filters={filters} onChange={setFilters} options={filterOptions} />}
+
 					{/* Select all control in selection mode */}
 					{isSelectionMode && tasks.length > 0 && (
 						<div className="flex items-center py-1">
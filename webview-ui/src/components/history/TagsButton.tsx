@@ -0,0 +1,81 @@
+import { useCallback, useState } from "react"
+import { X } from "lucide-react"
+
+import { vscode } from "@/utils/vscode"
+import { Badge, Button, Input, Popover, PopoverContent, PopoverTrigger, StandardTooltip } from "@/components/ui"
+import { useAppTranslation } from "@/i18n/TranslationContext"
+
+export const TagsButton = ({ itemId, tags }: { itemId: string; tags: string[] }) => {
+	const { t } = useAppTranslation()
+	const [isOpen, setIsOpen] = useState(false)
+	const [newTag, setNewTag] = useState("")
+
+	const updateTags = useCallback(
+		(updatedTags: string[]) => {
+			vscode.postMessage({ type: "updateTaskTags", text: itemId, values: { tags: updatedTags } })
+		},
+		[itemId],
+	)
+
+	const addTags = () => {
+		// Several tags can be added at once, separated by commas.
+		const added = newTag
+			.split(",")
+			.map((tag) => tag.trim())
+			.filter((tag) => tag && !tags.includes(tag))
+
+		if (added.length > 0) {
+			updateTags([...tags, ...added])
+		}
+
+		setNewTag("")
+	}
+
+	return (
+		<Popover open={isOpen} onOpenChange={setIsOpen}>
+			<StandardTooltip content={t("history:tags.edit")}>
+				<PopoverTrigger asChild>
+					<Button
+						data-testid="tags"
+						variant="ghost"
+						size="icon"
+						className="group-hover:opacity-100 opacity-50 transition-opacity"
+						onClick={(e) => e.stopPropagation()}>
+						<span className="codicon codicon-tag scale-80" />
+					</Button>
+				</PopoverTrigger>
+			</StandardTooltip>
+			<PopoverContent className="w-64 p-2 flex flex-col gap-2" onClick={(e) => e.stopPropagation()}>
+				{tags.length > 0 && (
+					<div className="flex flex-wrap gap-1">
+						{tags.map((tag) => (
+							<Badge key={tag} variant="outline" className="font-normal gap-1">
+								{tag}
+								<button
+									aria-label={t("history:tags.remove", { tag })}
+									className="cursor-pointer opacity-60 hover:opacity-100"
+									onClick={() => updateTags(tags.filter((item) => item !== tag))}>
+									<X className="size-3" />
+								</button>
+							</Badge>
+						))}
+					</div>
+				)}
+				<Input
+					autoFocus
+					className="text-sm"
+					value={newTag}
+					placeholder={t("history:tags.placeholder")}
+					data-testid="tags-input"
+					onChange={(e) => setNewTag(e.target.value)}
+					onKeyDown={(e) => {
+						if (e.key === "Enter") {
+							e.preventDefault()
+							addTags()
+						}
+					}}
+				/>
+			</PopoverContent>
+		</Popover>
+	)
+}
@@ -7,7 +7,7 @@ import { cn } from "@/lib/utils"
 import { Checkbox } from "@/components/ui/checkbox"
 
 import TaskItemFooter from "./TaskItemFooter"
-import { StandardTooltip } from "../ui"
+import { Badge, StandardTooltip } from "../ui"
 
 interface TaskItemProps {
 	item: DisplayHistoryItem
@@ -102,6 +102,25 @@ const TaskItem = ({
 						<ArrowRight className="size-4 shrink-0 opacity-0 group-hover:opacity-100 transition-opacity" />
 					</div>
 
+					{item.contentMatch && (
+						<div
+							className="flex items-start gap-1 text-vscode-descriptionForeground text-xs mt-1"
+							data-testid="task-content-match">
+							<span className="codicon codicon-comment-discussion text-xs! mt-px" />
+							<span className="line-clamp-2 break-all">{item.contentMatch}</span>
+						</div>
+					)}
+
+					{!isCompact && item.tags && item.tags.length > 0 && (
+						<div className="flex flex-wrap gap-1 mt-1" data-testid="task-tags">
+							{item.tags.map((tag) => (
+								<Badge key={tag} variant="outline" className="font-normal">
+									{tag}
+								</Badge>
+							))}
+						</div>
+					)}
+
 					{showWorkspace && item.workspace && (
 						<div className="flex items-center font-mono gap-1 text-vscode-descriptionForeground text-xs mt-1">
 							<Folder className="size-3" />
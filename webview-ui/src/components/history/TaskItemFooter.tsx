@@ -3,7 +3,9 @@ import type { HistoryItem } from "@roo-code/types"
 import { formatTimeAgo } from "@/utils/format"
 import { CopyButton } from "./CopyButton"
 import { ExportButton } from "./ExportButton"
+import { ExportReportButton } from "./ExportReportButton"
 import { DeleteButton } from "./DeleteButton"
+import { TagsButton } from "./TagsButton"
 import { StandardTooltip } from "../ui/standard-tooltip"
 import { useAppTranslation } from "@/i18n/TranslationContext"
 import { Split } from "lucide-react"
@@ -56,7 +58,9 @@ const TaskItemFooter: React.FC<TaskItemFooterProps> = ({
 			{!isSelectionMode && (
 				<div className="flex flex-row gap-0 -mx-1.5 items-center text-vscode-descriptionForeground/60 hover:text-vscode-descriptionForeground opacity-0 group-hover:opacity-100">
 					<CopyButton itemTask={item.task} />
+					{variant === "full" && <TagsButton itemId={item.id} tags={item.tags ?? []} />}
 					{variant === "full" && <ExportButton itemId={item.id} />}
+					{variant === "full" && <ExportReportButton itemId={item.id} />}
 					{onDelete && <DeleteButton itemId={item.id} onDelete={onDelete} />}
 				</div>
 			)}
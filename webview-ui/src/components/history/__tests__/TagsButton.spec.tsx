@@ -0,0 +1,48 @@
+import { render, screen, fireEvent } from "@/utils/test-utils"
+
+import { vscode } from "@src/utils/vscode"
+
+import { TagsButton } from "../TagsButton"
+
+vi.mock("@src/utils/vscode")
+
+vi.mock("@src/i18n/TranslationContext", () => ({
+	useAppTranslation: () => ({
+		t: (key: string) => key,
+	}),
+}))
+
+describe("TagsButton", () => {
+	beforeEach(() => {
+		vi.clearAllMocks()
+	})
+
+	it("adds the comma-separated tags that are new", () => {
+		render(<TagsButton itemId="1" tags={["frontend"]} />)
+
+		fireEvent.click(screen.getByTestId("tags"))
+		const input = screen.getByTestId("tags-input")
+		fireEvent.change(input, { target: { value: "bug, frontend ,  release" } })
+		fireEvent.keyDown(input, { key: "Enter" })
+
+		expect(vscode.postMessage).toHaveBeenCalledWith({
+			type: "updateTaskTags",
+			text: "1",
+			values: { tags: ["frontend", "bug", "release"] },
+		})
+		expect(input).toHaveValue("")
+	})
+
+	it("removes a tag", () => {
+		render(<TagsButton itemId="1" tags={["frontend", "bug"]} />)
+
+		fireEvent.click(screen.getByTestId("tags"))
+		fireEvent.click(screen.getByLabelText("history:tags.remove"))
+
+		expect(vscode.postMessage).toHaveBeenCalledWith({
+			type: "updateTaskTags",
+			text: "1",
+			values: { tags: ["bug"] },
+		})
+	})
+})
@@ -109,4 +109,16 @@ describe("TaskItem", () => {
 		const taskItem = screen.getByTestId("task-item-1")
 		expect(taskItem).toHaveClass("hover:text-vscode-foreground")
 	})
+
+	it("shows the tags of the task", () => {
+		render(<TaskItem item={{ ...mockTask, tags: ["frontend", "bug"] }} variant="full" />)
+
+		expect(screen.getByTestId("task-tags")).toHaveTextContent("frontendbug")
+	})
+
+	it("shows the excerpt of the conversation that matches the search", () => {
+		render(<TaskItem item={{ ...mockTask, contentMatch: "…the failing test…" }} variant="full" />)
+
+		expect(screen.getByTestId("task-content-match")).toHaveTextContent("…the failing test…")
+	})
 })
@@ -46,6 +46,7 @@ describe("TaskItemFooter", () => {
 		// Should show copy and export buttons
 		expect(screen.getByTestId("copy-prompt-button")).toBeInTheDocument()
 		expect(screen.getByTestId("export")).toBeInTheDocument()
+		expect(screen.getByTestId("export-report")).toBeInTheDocument()
 	})
 
 	it("hides export button in compact variant", () => {
@@ -12,7 +12,12 @@ vi.mock("@/utils/highlight", () => ({
 	highlightFzfMatch: vi.fn((text) => `<mark>${text}</mark>`),
 }))
 
+vi.mock("@/utils/vscode", () => ({
+	vscode: { postMessage: vi.fn() },
+}))
+
 import { useExtensionState } from "@/context/ExtensionStateContext"
+import { vscode } from "@/utils/vscode"
 
 const mockUseExtensionState = useExtensionState as ReturnType<typeof vi.fn>
 
@@ -26,6 +31,9 @@ const mockTaskHistory: HistoryItem[] = [
 		tokensOut: 50,
 		totalCost: 0.01,
 		workspace: "/workspace/project1",
+		mode: "code",
+		apiConfigName: "default",
+		tags: ["frontend"],
 	},
 	{
 		id: "task-2",
@@ -38,6 +46,8 @@ const mockTaskHistory: HistoryItem[] = [
 		cacheWrites: 25,
 		cacheReads: 10,
 		workspace: "/workspace/project1",
+		mode: "debug",
+		apiConfigName: "fast",
 	},
 	{
 		id: "task-3",
@@ -284,4 +294,129 @@ describe("useTaskSearch", () => {
 		// When not searching, it should fall back to newest
 		expect(result.current.sortOption).toBe("mostRelevant")
 	})
+
+	describe("filters", () => {
+		it("lists the modes, profiles and tags of the tasks", () => {
+			const { result } = renderHook(() => useTaskSearch())
+
+			expect(result.current.filterOptions).toEqual({
+				modes: ["code", "debug"],
+				apiConfigNames: ["default", "fast"],
+				tags: ["frontend"],
+			})
+		})
+
+		it("filters by mode, profile and tag", () => {
+			const { result } = renderHook(() => useTaskSearch())
+
+			act(() => {
+				result.current.setFilters({ date: "all", mode: "debug" })
+			})
+			expect(result.current.tasks.map((task) => task.id)).toEqual(["task-2"])
+
+			act(() => {
+				result.current.setFilters({ date: "all", apiConfigName: "default" })
+			})
+			expect(result.current.tasks.map((task) => task.id)).toEqual(["task-1"])
+
+			act(() => {
+				result.current.setFilters({ date: "all", tag: "frontend" })
+			})
+			expect(result.current.tasks.map((task) => task.id)).toEqual(["task-1"])
+		})
+
+		it("filters by date and cost", () => {
+			const recentTask = { ...mockTaskHistory[0], id: "task-4", ts: Date.now(), totalCost: 2 }
+
+			mockUseExtensionState.mockReturnValue({
+				taskHistory: [...mockTaskHistory, recentTask],
+				cwd: "/workspace/project1",
+			} as any)
+
+			const { result } = renderHook(() => useTaskSearch())
+
+			act(() => {
+				result.current.setFilters({ date: "week" })
+			})
+			expect(result.current.tasks.map((task) => task.id)).toEqual(["task-4"])
+
+			act(() => {
+				result.current.setFilters({ date: "all", minCost: 0.02 })
+			})
+			expect(result.current.tasks.map((task) => task.id)).toEqual(["task-4", "task-2"])
+		})
+	})
+
+	describe("content search", () => {
+		beforeEach(() => {
+			vi.useFakeTimers()
+		})
+
+		afterEach(() => {
+			vi.useRealTimers()
+		})
+
+		it("adds the tasks whose conversation matches after the title matches", () => {
+			const { result } = renderHook(() => useTaskSearch())
+
+			act(() => {
+				result.current.setSearchQuery("component")
+				result.current.setSortOption("mostRelevant")
+			})
+
+			expect(vscode.postMessage).not.toHaveBeenCalled()
+
+			act(() => {
+				vi.advanceTimersByTime(300)
+			})
+
+			const { requestId } = vi.mocked(vscode.postMessage).mock.calls[0][0]
+			expect(vscode.postMessage).toHaveBeenCalledWith({
+				type: "searchTaskHistory",
+				query: "component",
+				requestId,
+			})
+
+			act(() => {
+				window.dispatchEvent(
+					new MessageEvent("message", {
+						data: {
+							type: "taskHistorySearchResults",
+							requestId,
+							taskSearchResults: [
+								{ taskId: "task-1", snippet: "a component" },
+								{ taskId: "task-2", snippet: "the component tests" },
+							],
+						},
+					}),
+				)
+			})
+
+			expect(result.current.tasks.map((task) => task.id)).toEqual(["task-1", "task-2"])
+			expect(result.current.tasks[0].contentMatch).toBeUndefined()
+			expect(result.current.tasks[1].contentMatch).toBe("the component tests")
+		})
+
+		it("ignores the results of earlier searches", () => {
+			const { result } = renderHook(() => useTaskSearch())
+
+			act(() => {
+				result.current.setSearchQuery("tests")
+			})
+
+			act(() => {
+				window.dispatchEvent(
+					new MessageEvent("message", {
+						data: {
+							type: "taskHistorySearchResults",
+							requestId: "stale",
+							taskSearchResults: [{ taskId: "task-1", snippet: "tests" }],
+						},
+					}),
+				)
+			})
+
+			expect(result.current.tasks.map((task) => task.id)).toEqual(["task-2"])
+		})
+	})
 })
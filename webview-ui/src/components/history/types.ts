@@ -6,6 +6,8 @@ import type { HistoryItem } from "@roo-code/types"
 export interface DisplayHistoryItem extends HistoryItem {
 	/** HTML string with search match highlighting */
 	highlight?: string
+	/** Excerpt of the conversation when only it matches the search */
+	contentMatch?: string
 	/** Whether this task is a subtask (has a parent in the current task list) */
 	isSubtask?: boolean
 }
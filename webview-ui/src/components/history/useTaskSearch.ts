@@ -1,17 +1,46 @@
 import { useState, useEffect, useMemo } from "react"
 import { Fzf } from "fzf"
 
+import type { ExtensionMessage } from "@roo-code/types"
+
 import { highlightFzfMatch } from "@/utils/highlight"
+import { vscode } from "@/utils/vscode"
 import { useExtensionState } from "@/context/ExtensionStateContext"
 
+import type { DisplayHistoryItem } from "./types"
+
 type SortOption = "newest" | "oldest" | "mostExpensive" | "mostTokens" | "mostRelevant"
 
+export type DateFilter = "all" | "day" | "week" | "month"
+
+export interface TaskFilters {
+	mode?: string
+	apiConfigName?: string
+	tag?: string
+	date: DateFilter
+	minCost?: number
+}
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+const DATE_FILTER_MS: Record<Exclude<DateFilter, "all">, number> = {
+	day: DAY_MS,
+	week: 7 * DAY_MS,
+	month: 30 * DAY_MS,
+}
+
+// Wait for the user to stop typing before searching the conversations.
+const CONTENT_SEARCH_DEBOUNCE_MS = 300
+
 export const useTaskSearch = () => {
 	const { taskHistory, cwd } = useExtensionState()
 	const [searchQuery, setSearchQuery] = useState("")
 	const [sortOption, setSortOption] = useState<SortOption>("newest")
 	const [lastNonRelevantSort, setLastNonRelevantSort] = useState<SortOption | null>("newest")
 	const [showAllWorkspaces, setShowAllWorkspaces] = useState(false)
+	const [filters, setFilters] = useState<TaskFilters>({ date: "all" })
+	// The excerpts of the tasks whose conversation matches the search, by task id.
+	const [contentMatches, setContentMatches] = useState<Map<string, string>>(new Map())
 
 	useEffect(() => {
 		if (searchQuery && sortOption !== "mostRelevant" && !lastNonRelevantSort) {
@@ -23,7 +52,38 @@ export const useTaskSearch = () => {
 		}
 	}, [searchQuery, sortOption, lastNonRelevantSort])
 
-	const presentableTasks = useMemo(() => {
+	useEffect(() => {
+		setContentMatches(new Map())
+
+		if (!searchQuery.trim()) {
+			return
+		}
+
+		const requestId = `${Date.now()}-${Math.random().toString(36).slice(2, 9)}`
+
+		const handleMessage = (event: MessageEvent) => {
+			const message: ExtensionMessage = event.data
+
+			if (message.type === "taskHistorySearchResults" && message.requestId === requestId) {
+				setContentMatches(
+					new Map((message.taskSearchResults ?? []).map(({ taskId, snippet }) => [taskId, snippet])),
+				)
+			}
+		}
+
+		window.addEventListener("message", handleMessage)
+
+		const timer = setTimeout(() => {
+			vscode.postMessage({ type: "searchTaskHistory", query: searchQuery, requestId })
+		}, CONTENT_SEARCH_DEBOUNCE_MS)
+
+		return () => {
+			clearTimeout(timer)
+			window.removeEventListener("message", handleMessage)
+		}
+	}, [searchQuery])
+
+	const workspaceTasks = useMemo(() => {
 		let tasks = taskHistory.filter((item) => item.ts && item.task)
 		if (!showAllWorkspaces) {
 			tasks = tasks.filter((item) => item.workspace === cwd)
@@ -31,6 +91,31 @@ export const useTaskSearch = () => {
 		return tasks
 	}, [taskHistory, showAllWorkspaces, cwd])
 
+	// The values the filters can take, from the tasks they apply to.
+	const filterOptions = useMemo(() => {
+		const unique = (values: (string | undefined)[]) =>
+			[...new Set(values.filter((value): value is string => !!value))].sort()
+
+		return {
+			modes: unique(workspaceTasks.map((item) => item.mode)),
+			apiConfigNames: unique(workspaceTasks.map((item) => item.apiConfigName)),
+			tags: unique(workspaceTasks.flatMap((item) => item.tags ?? [])),
+		}
+	}, [workspaceTasks])
+
+	const presentableTasks = useMemo(() => {
+		const since = filters.date === "all" ? undefined : Date.now() - DATE_FILTER_MS[filters.date]
+
+		return workspaceTasks.filter(
+			(item) =>
+				(!filters.mode || item.mode === filters.mode) &&
+				(!filters.apiConfigName || item.apiConfigName === filters.apiConfigName) &&
+				(!filters.tag || item.tags?.includes(filters.tag)) &&
+				(since === undefined || item.ts >= since) &&
+				(filters.minCost === undefined || (item.totalCost || 0) >= filters.minCost),
+		)
+	}, [workspaceTasks, filters])
+
 	const fzf = useMemo(() => {
 		return new Fzf(presentableTasks, {
 			selector: (item) => item.task,
@@ -38,7 +123,7 @@ export const useTaskSearch = () => {
 	}, [presentableTasks])
 
 	const tasks = useMemo(() => {
-		let results = presentableTasks
+		let results: DisplayHistoryItem[] = presentableTasks
 
 		if (searchQuery) {
 			const searchResults = fzf.find(searchQuery)
@@ -55,6 +140,15 @@ export const useTaskSearch = () => {
 					workspace: result.item.workspace,
 				}
 			})
+
+			// Then the tasks that only match in their conversation, newest first.
+			const titleMatchIds = new Set(results.map((item) => item.id))
+			const contentResults = presentableTasks
+				.filter((item) => contentMatches.has(item.id) && !titleMatchIds.has(item.id))
+				.sort((a, b) => (b.ts || 0) - (a.ts || 0))
+				.map((item) => ({ ...item, contentMatch: contentMatches.get(item.id) }))
+
+			results = [...results, ...contentResults]
 		}
 
 		// Then sort the results
@@ -76,7 +170,7 @@ export const useTaskSearch = () => {
 					return (b.ts || 0) - (a.ts || 0)
 			}
 		})
-	}, [presentableTasks, searchQuery, fzf, sortOption])
+	}, [presentableTasks, searchQuery, fzf, sortOption, contentMatches])
 
 	return {
 		tasks,
@@ -88,5 +182,8 @@ export const useTaskSearch = () => {
 		setLastNonRelevantSort,
 		showAllWorkspaces,
 		setShowAllWorkspaces,
+		filters,
+		setFilters,
+		filterOptions,
 	}
 }
@@ -1,6 +1,12 @@
 import React, { useState } from "react"
 import { Trans } from "react-i18next"
-import { VSCodeLink, VSCodePanels, VSCodePanelTab, VSCodePanelView } from "@vscode/webview-ui-toolkit/react"
+import {
+	VSCodeCheckbox,
+	VSCodeLink,
+	VSCodePanels,
+	VSCodePanelTab,
+	VSCodePanelView,
+} from "@vscode/webview-ui-toolkit/react"
 
 import type { McpServer } from "@roo-code/types"
 
@@ -29,7 +35,7 @@ import McpEnabledToggle from "./McpEnabledToggle"
 import { McpErrorRow } from "./McpErrorRow"
 
 const McpView = () => {
-	const { mcpServers: servers, alwaysAllowMcp, mcpEnabled } = useExtensionState()
+	const { mcpServers: servers, alwaysAllowMcp, mcpEnabled, mcpResourceUpdateNotifications } = useExtensionState()
 
 	const { t } = useAppTranslation()
 	const { isOverThreshold, title, message } = useTooManyTools()
@@ -59,6 +65,28 @@ const McpView = () => {
 
 				{mcpEnabled && (
 					<>
+						<div style={{ marginBottom: "20px" }}>
+							<VSCodeCheckbox
+								checked={mcpResourceUpdateNotifications ?? true}
+								onChange={(e: any) =>
+									vscode.postMessage({
+										type: "updateSettings",
+										updatedSettings: { mcpResourceUpdateNotifications: e.target.checked },
+									})
+								}
+								data-testid="mcp-resource-update-notifications">
+								<span style={{ fontWeight: "500" }}>{t("mcp:resourceUpdates.title")}</span>
+							</VSCodeCheckbox>
+							<p
+								style={{
+									fontSize: "12px",
+									marginTop: "5px",
+									color: "var(--vscode-descriptionForeground)",
+								}}>
+								{t("mcp:resourceUpdates.description")}
+							</p>
+						</div>
+
 						{/* Too Many Tools Warning */}
 						{isOverThreshold && (
 							<div style={{ marginBottom: 15 }}>
@@ -228,6 +256,22 @@ const ServerRow = ({ server, alwaysAllowMcp }: { server: McpServer; alwaysAllowM
 		})
 	}
 
+	const handleSignIn = () => {
+		vscode.postMessage({
+			type: "authenticateMcpServer",
+			serverName: server.name,
+			source: server.source || "global",
+		})
+	}
+
+	const handleSignOut = () => {
+		vscode.postMessage({
+			type: "signOutMcpServer",
+			serverName: server.name,
+			source: server.source || "global",
+		})
+	}
+
 	const handleTimeoutChange = (event: React.ChangeEvent<HTMLSelectElement>) => {
 		const seconds = parseInt(event.target.value)
 		setTimeoutValue(seconds)
@@ -301,6 +345,17 @@ const ServerRow = ({ server, alwaysAllowMcp }: { server: McpServer; alwaysAllowM
 						style={{ marginRight: "8px" }}>
 						<span className="codicon codicon-refresh" style={{ fontSize: "14px" }}></span>
 					</Button>
+					{server.authStatus === "authorized" && (
+						<Button
+							variant="ghost"
+							size="icon"
+							onClick={handleSignOut}
+							title={t("mcp:serverStatus.signOut")}
+							aria-label={t("mcp:serverStatus.signOut")}
+							style={{ marginRight: "8px" }}>
+							<span className="codicon codicon-sign-out" style={{ fontSize: "14px" }}></span>
+						</Button>
+					)}
 				</div>
 				<div
 					style={{
@@ -498,14 +553,25 @@ const ServerRow = ({ server, alwaysAllowMcp }: { server: McpServer; alwaysAllowM
 									overflowWrap: "break-word",
 									wordBreak: "break-word",
 								}}>
-								{server.error &&
-									server.error.split("\n").map((item, index) => (
-										<React.Fragment key={index}>
-											{index > 0 && <br />}
-											{item}
-										</React.Fragment>
-									))}
+								{server.authStatus === "required"
+									? t("mcp:serverStatus.authRequired")
+									: server.error &&
+										server.error.split("\n").map((item, index) => (
+											<React.Fragment key={index}>
+												{index > 0 && <br />}
+												{item}
+											</React.Fragment>
+										))}
 							</div>
+							{server.authStatus === "required" && (
+								<Button
+									variant="primary"
+									onClick={handleSignIn}
+									disabled={server.status === "connecting"}
+									style={{ width: "calc(100% - 20px)", margin: "0 10px 10px 10px" }}>
+									{t("mcp:serverStatus.signIn")}
+								</Button>
+							)}
 							<Button
 								variant="secondary"
 								onClick={handleRestart}
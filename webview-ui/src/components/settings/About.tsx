@@ -21,9 +21,20 @@ type AboutProps = HTMLAttributes<HTMLDivElement> & {
 	setTelemetrySetting: (setting: TelemetrySetting) => void
 	debug?: boolean
 	setDebug?: (debug: boolean) => void
+	debugRecorder?: boolean
+	setDebugRecorder?: (debugRecorder: boolean) => void
 }
 
-export const About = ({ telemetrySetting, setTelemetrySetting, debug, setDebug, className, ...props }: AboutProps) => {
+export const About = ({
+	telemetrySetting,
+	setTelemetrySetting,
+	debug,
+	setDebug,
+	debugRecorder,
+	setDebugRecorder,
+	className,
+	...props
+}: AboutProps) => {
 	const { t } = useAppTranslation()
 
 	return (
@@ -127,6 +138,25 @@ export const About = ({ telemetrySetting, setTelemetrySetting, debug, setDebug,
 							</p>
 						</SearchableSetting>
 					)}
+					{setDebugRecorder && (
+						<SearchableSetting
+							settingId="about-debug-recorder"
+							section="about"
+							label={t("settings:about.debugRecorder.label")}
+							className="mt-4">
+							<VSCodeCheckbox
+								checked={debugRecorder ?? false}
+								onChange={(e: any) => {
+									const checked = e.target.checked === true
+									setDebugRecorder(checked)
+								}}>
+								{t("settings:about.debugRecorder.label")}
+							</VSCodeCheckbox>
+							<p className="text-vscode-descriptionForeground text-sm mt-0">
+								{t("settings:about.debugRecorder.description")}
+							</p>
+						</SearchableSetting>
+					)}
 				</div>
 			</Section>
 
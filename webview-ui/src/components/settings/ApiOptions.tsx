@@ -74,6 +74,7 @@ import {
 	Gemini,
 	LMStudio,
 	LiteLLM,
+	LlamaCpp,
 	Mistral,
 	Moonshot,
 	Ollama,
@@ -105,7 +106,9 @@ import { Verbosity } from "./Verbosity"
 import { TodoListSettingsControl } from "./TodoListSettingsControl"
 import { TemperatureControl } from "./TemperatureControl"
 import { RateLimitSecondsControl } from "./RateLimitSecondsControl"
+import { RequestsPerMinuteControl } from "./RequestsPerMinuteControl"
 import { ConsecutiveMistakeLimitControl } from "./ConsecutiveMistakeLimitControl"
+import { VoiceInputSettingsControl } from "./VoiceInputSettingsControl"
 import { BedrockCustomArn } from "./providers/BedrockCustomArn"
 import { RooBalanceDisplay } from "./providers/RooBalanceDisplay"
 import { buildDocLink } from "@src/utils/docLinks"
@@ -366,6 +369,7 @@ const ApiOptions = ({
 				openai: { field: "openAiModelId" },
 				ollama: { field: "ollamaModelId" },
 				lmstudio: { field: "lmStudioModelId" },
+				llamacpp: { field: "llamaCppModelId" },
 			}
 
 			const config = PROVIDER_MODEL_CONFIG[value]
@@ -620,6 +624,13 @@ const ApiOptions = ({
 						/>
 					)}
 
+					{selectedProvider === "llamacpp" && (
+						<LlamaCpp
+							apiConfiguration={apiConfiguration}
+							setApiConfigurationField={setApiConfigurationField}
+						/>
+					)}
+
 					{selectedProvider === "deepseek" && (
 						<DeepSeek
 							apiConfiguration={apiConfiguration}
@@ -807,6 +818,10 @@ const ApiOptions = ({
 									value={apiConfiguration.rateLimitSeconds || 0}
 									onChange={(value) => setApiConfigurationField("rateLimitSeconds", value)}
 								/>
+								<RequestsPerMinuteControl
+									value={apiConfiguration.requestsPerMinute}
+									onChange={(value) => setApiConfigurationField("requestsPerMinute", value)}
+								/>
 								<ConsecutiveMistakeLimitControl
 									value={
 										apiConfiguration.consecutiveMistakeLimit !== undefined
@@ -815,6 +830,10 @@ const ApiOptions = ({
 									}
 									onChange={(value) => setApiConfigurationField("consecutiveMistakeLimit", value)}
 								/>
+								<VoiceInputSettingsControl
+									apiConfiguration={apiConfiguration}
+									onChange={(field, value) => setApiConfigurationField(field, value)}
+								/>
 								{selectedProvider === "poe" && (
 									<VSCodeTextField
 										value={apiConfiguration?.poeBaseUrl || ""}
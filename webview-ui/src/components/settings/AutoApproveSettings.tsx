@@ -14,6 +14,7 @@ import { Section } from "./Section"
 import { SearchableSetting } from "./SearchableSetting"
 import { AutoApproveToggle } from "./AutoApproveToggle"
 import { MaxLimitInputs } from "./MaxLimitInputs"
+import { ProjectConfigNotice } from "./ProjectConfigNotice"
 import { useExtensionState } from "@/context/ExtensionStateContext"
 import { useAutoApprovalState } from "@/hooks/useAutoApprovalState"
 import { useAutoApprovalToggles } from "@/hooks/useAutoApprovalToggles"
@@ -24,6 +25,8 @@ type AutoApproveSettingsProps = HTMLAttributes<HTMLDivElement> & {
 	alwaysAllowWrite?: boolean
 	alwaysAllowWriteOutsideWorkspace?: boolean
 	alwaysAllowWriteProtected?: boolean
+	allowedWritePaths?: string[]
+	deniedWritePaths?: string[]
 	alwaysAllowMcp?: boolean
 	alwaysAllowModeSwitch?: boolean
 	alwaysAllowSubtasks?: boolean
@@ -40,6 +43,8 @@ type AutoApproveSettingsProps = HTMLAttributes<HTMLDivElement> & {
 		| "alwaysAllowWrite"
 		| "alwaysAllowWriteOutsideWorkspace"
 		| "alwaysAllowWriteProtected"
+		| "allowedWritePaths"
+		| "deniedWritePaths"
 		| "alwaysAllowMcp"
 		| "alwaysAllowModeSwitch"
 		| "alwaysAllowSubtasks"
@@ -59,6 +64,8 @@ export const AutoApproveSettings = ({
 	alwaysAllowWrite,
 	alwaysAllowWriteOutsideWorkspace,
 	alwaysAllowWriteProtected,
+	allowedWritePaths,
+	deniedWritePaths,
 	alwaysAllowMcp,
 	alwaysAllowModeSwitch,
 	alwaysAllowSubtasks,
@@ -75,6 +82,8 @@ export const AutoApproveSettings = ({
 	const { t } = useAppTranslation()
 	const [commandInput, setCommandInput] = useState("")
 	const [deniedCommandInput, setDeniedCommandInput] = useState("")
+	const [writePathInput, setWritePathInput] = useState("")
+	const [deniedWritePathInput, setDeniedWritePathInput] = useState("")
 	const { autoApprovalEnabled, setAutoApprovalEnabled } = useExtensionState()
 
 	const toggles = useAutoApprovalToggles()
@@ -92,6 +101,12 @@ export const AutoApproveSettings = ({
 		}
 	}
 
+	const handleAllowSuggestedCommands = (commands: string[]) => {
+		const newCommands = [...(allowedCommands ?? []), ...commands]
+		setCachedStateField("allowedCommands", newCommands)
+		vscode.postMessage({ type: "updateSettings", updatedSettings: { allowedCommands: newCommands } })
+	}
+
 	const handleAddDeniedCommand = () => {
 		const currentCommands = deniedCommands ?? []
 
@@ -103,11 +118,33 @@ export const AutoApproveSettings = ({
 		}
 	}
 
+	const handleAddWritePath = (key: "allowedWritePaths" | "deniedWritePaths", input: string) => {
+		const pattern = input.trim()
+		const currentPatterns = (key === "allowedWritePaths" ? allowedWritePaths : deniedWritePaths) ?? []
+
+		if (pattern && !currentPatterns.includes(pattern)) {
+			const newPatterns = [...currentPatterns, pattern]
+			setCachedStateField(key, newPatterns)
+			vscode.postMessage({ type: "updateSettings", updatedSettings: { [key]: newPatterns } })
+		}
+	}
+
+	const handleRemoveWritePath = (key: "allowedWritePaths" | "deniedWritePaths", index: number) => {
+		const currentPatterns = (key === "allowedWritePaths" ? allowedWritePaths : deniedWritePaths) ?? []
+		const newPatterns = currentPatterns.filter((_, i) => i !== index)
+		setCachedStateField(key, newPatterns)
+		vscode.postMessage({ type: "updateSettings", updatedSettings: { [key]: newPatterns } })
+	}
+
 	return (
 		<div {...props}>
 			<SectionHeader>{t("settings:sections.autoApprove")}</SectionHeader>
 
 			<Section>
+				<ProjectConfigNotice
+					allowedCommands={allowedCommands}
+					onAllowCommands={handleAllowSuggestedCommands}
+				/>
 				<div className="space-y-4">
 					<SearchableSetting
 						settingId="auto-approve-enabled"
@@ -237,6 +274,113 @@ export const AutoApproveSettings = ({
 								{t("settings:autoApprove.write.protected.description")}
 							</div>
 						</SearchableSetting>
+
+						<SearchableSetting
+							settingId="auto-approve-allowed-write-paths"
+							section="autoApprove"
+							label={t("settings:autoApprove.write.allowedPaths.label")}>
+							<label className="block font-medium mb-1" data-testid="allowed-write-paths-heading">
+								{t("settings:autoApprove.write.allowedPaths.label")}
+							</label>
+							<div className="text-vscode-descriptionForeground text-sm mt-1">
+								{t("settings:autoApprove.write.allowedPaths.description")}
+							</div>
+						</SearchableSetting>
+
+						<div className="flex gap-2">
+							<Input
+								value={writePathInput}
+								onChange={(e: any) => setWritePathInput(e.target.value)}
+								onKeyDown={(e: any) => {
+									if (e.key === "Enter") {
+										e.preventDefault()
+										handleAddWritePath("allowedWritePaths", writePathInput)
+										setWritePathInput("")
+									}
+								}}
+								placeholder={t("settings:autoApprove.write.allowedPaths.placeholder")}
+								className="grow"
+								data-testid="write-path-input"
+							/>
+							<Button
+								className="h-8"
+								onClick={() => {
+									handleAddWritePath("allowedWritePaths", writePathInput)
+									setWritePathInput("")
+								}}
+								data-testid="add-write-path-button">
+								{t("settings:autoApprove.write.addButton")}
+							</Button>
+						</div>
+
+						<div className="flex flex-wrap gap-2">
+							{(allowedWritePaths ?? []).map((pattern, index) => (
+								<Button
+									key={index}
+									variant="secondary"
+									data-testid={`remove-write-path-${index}`}
+									onClick={() => handleRemoveWritePath("allowedWritePaths", index)}>
+									<div className="flex flex-row items-center gap-1">
+										<div>{pattern}</div>
+										<X className="text-foreground scale-75" />
+									</div>
+								</Button>
+							))}
+						</div>
+
+						<SearchableSetting
+							settingId="auto-approve-denied-write-paths"
+							section="autoApprove"
+							label={t("settings:autoApprove.write.deniedPaths.label")}
+							className="mt-3">
+							<label className="block font-medium mb-1" data-testid="denied-write-paths-heading">
+								{t("settings:autoApprove.write.deniedPaths.label")}
+							</label>
+							<div className="text-vscode-descriptionForeground text-sm mt-1">
+								{t("settings:autoApprove.write.deniedPaths.description")}
+							</div>
+						</SearchableSetting>
+
+						<div className="flex gap-2">
+							<Input
+								value={deniedWritePathInput}
+								onChange={(e: any) => setDeniedWritePathInput(e.target.value)}
+								onKeyDown={(e: any) => {
+									if (e.key === "Enter") {
+										e.preventDefault()
+										handleAddWritePath("deniedWritePaths", deniedWritePathInput)
+										setDeniedWritePathInput("")
+									}
+								}}
+								placeholder={t("settings:autoApprove.write.deniedPaths.placeholder")}
+								className="grow"
+								data-testid="denied-write-path-input"
+							/>
+							<Button
+								className="h-8"
+								onClick={() => {
+									handleAddWritePath("deniedWritePaths", deniedWritePathInput)
+									setDeniedWritePathInput("")
+								}}
+								data-testid="add-denied-write-path-button">
+								{t("settings:autoApprove.write.addButton")}
+							</Button>
+						</div>
+
+						<div className="flex flex-wrap gap-2 mb-3">
+							{(deniedWritePaths ?? []).map((pattern, index) => (
+								<Button
+									key={index}
+									variant="secondary"
+									data-testid={`remove-denied-write-path-${index}`}
+									onClick={() => handleRemoveWritePath("deniedWritePaths", index)}>
+									<div className="flex flex-row items-center gap-1">
+										<div>{pattern}</div>
+										<X className="text-foreground scale-75" />
+									</div>
+								</Button>
+							))}
+						</div>
 					</div>
 				)}
 
@@ -0,0 +1,112 @@
+import { useMemo, useState } from "react"
+
+import type { BudgetLimits } from "@roo-code/types"
+import { getAllModes } from "@roo/modes"
+
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { useExtensionState } from "@/context/ExtensionStateContext"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui"
+
+import { FormattedTextField, unlimitedDecimalFormatter, unlimitedIntegerFormatter } from "../common/FormattedTextField"
+import { SearchableSetting } from "./SearchableSetting"
+
+// Select value of the budget that applies to every task
+const ALL_TASKS = "*"
+
+interface BudgetSettingsProps {
+	taskBudget?: BudgetLimits
+	modeBudgets?: Record<string, BudgetLimits>
+	setTaskBudget: (budget: BudgetLimits) => void
+	setModeBudgets: (budgets: Record<string, BudgetLimits>) => void
+}
+
+export const BudgetSettings = ({ taskBudget, modeBudgets, setTaskBudget, setModeBudgets }: BudgetSettingsProps) => {
+	const { t } = useAppTranslation()
+	const { customModes } = useExtensionState()
+	const [scope, setScope] = useState(ALL_TASKS)
+
+	const modes = useMemo(() => getAllModes(customModes), [customModes])
+	const budget = (scope === ALL_TASKS ? taskBudget : modeBudgets?.[scope]) ?? {}
+
+	const updateBudget = <K extends keyof BudgetLimits>(key: K, value: BudgetLimits[K]) => {
+		const updated = { ...budget, [key]: value }
+
+		if (scope === ALL_TASKS) {
+			setTaskBudget(updated)
+			return
+		}
+
+		const { [scope]: _, ...otherBudgets } = modeBudgets ?? {}
+		const hasLimits = Object.values(updated).some((limit) => limit !== undefined)
+		setModeBudgets(hasLimits ? { ...otherBudgets, [scope]: updated } : otherBudgets)
+	}
+
+	return (
+		<SearchableSetting
+			settingId="auto-approve-budget"
+			section="autoApprove"
+			label={t("settings:autoApprove.budget.label")}
+			data-testid="budget-settings">
+			<label className="block font-medium mb-1">{t("settings:autoApprove.budget.label")}</label>
+			<div className="text-sm text-vscode-descriptionForeground mb-2">
+				{t("settings:autoApprove.budget.description")}
+			</div>
+
+			<Select value={scope} onValueChange={setScope}>
+				<SelectTrigger className="w-full mb-2" data-testid="budget-scope">
+					<SelectValue />
+				</SelectTrigger>
+				<SelectContent>
+					<SelectItem value={ALL_TASKS}>{t("settings:autoApprove.budget.allTasks")}</SelectItem>
+					{modes.map((mode) => (
+						<SelectItem key={mode.slug} value={mode.slug}>
+							{modeBudgets?.[mode.slug]
+								? t("settings:autoApprove.budget.modeWithBudget", { mode: mode.name })
+								: mode.name}
+						</SelectItem>
+					))}
+				</SelectContent>
+			</Select>
+
+			<div className="grid grid-cols-[auto_1fr] gap-x-2 gap-y-2 items-center">
+				<label className="flex items-center gap-2 text-sm font-medium whitespace-nowrap">
+					<span className="codicon codicon-credit-card" />
+					{t("settings:autoApprove.budget.maxCost")}:
+				</label>
+				<FormattedTextField
+					value={budget.maxCost}
+					onValueChange={(value) => updateBudget("maxCost", value)}
+					formatter={unlimitedDecimalFormatter}
+					placeholder={t("settings:autoApprove.budget.unlimited")}
+					style={{ maxWidth: "200px" }}
+					data-testid="budget-max-cost"
+					leftNodes={[<span key="dollar">$</span>]}
+				/>
+				<label className="flex items-center gap-2 text-sm font-medium whitespace-nowrap">
+					<span className="codicon codicon-symbol-numeric" />
+					{t("settings:autoApprove.budget.maxTokens")}:
+				</label>
+				<FormattedTextField
+					value={budget.maxTokens}
+					onValueChange={(value) => updateBudget("maxTokens", value)}
+					formatter={unlimitedIntegerFormatter}
+					placeholder={t("settings:autoApprove.budget.unlimited")}
+					style={{ maxWidth: "200px" }}
+					data-testid="budget-max-tokens"
+				/>
+				<label className="flex items-center gap-2 text-sm font-medium whitespace-nowrap">
+					<span className="codicon codicon-pulse" />
+					{t("settings:autoApprove.budget.maxRequests")}:
+				</label>
+				<FormattedTextField
+					value={budget.maxRequests}
+					onValueChange={(value) => updateBudget("maxRequests", value)}
+					formatter={unlimitedIntegerFormatter}
+					placeholder={t("settings:autoApprove.budget.unlimited")}
+					style={{ maxWidth: "200px" }}
+					data-testid="budget-max-requests"
+				/>
+			</div>
+		</SearchableSetting>
+	)
+}
@@ -1,33 +1,53 @@
-import { HTMLAttributes } from "react"
+import { HTMLAttributes, useEffect, useState } from "react"
 import { useAppTranslation } from "@/i18n/TranslationContext"
 import { VSCodeCheckbox, VSCodeLink } from "@vscode/webview-ui-toolkit/react"
 import { Trans } from "react-i18next"
+import prettyBytes from "pretty-bytes"
 import { buildDocLink } from "@src/utils/docLinks"
-import { Slider } from "@/components/ui"
+import { formatDate } from "@src/utils/format"
+import { vscode } from "@src/utils/vscode"
+import { Button, Slider, StandardTooltip } from "@/components/ui"
 
 import { SetCachedStateField } from "./types"
 import { SectionHeader } from "./SectionHeader"
 import { Section } from "./Section"
 import { SearchableSetting } from "./SearchableSetting"
+import { FormattedTextField, unlimitedIntegerFormatter } from "../common/FormattedTextField"
 import {
+	type CheckpointStorageUsage,
 	DEFAULT_CHECKPOINT_TIMEOUT_SECONDS,
 	MAX_CHECKPOINT_TIMEOUT_SECONDS,
 	MIN_CHECKPOINT_TIMEOUT_SECONDS,
 } from "@roo-code/types"
 
+type RetentionField = "checkpointMaxAgeDays" | "checkpointMaxStorageMb" | "checkpointMaxPerTask"
+
+const RETENTION_FIELDS: { field: RetentionField; key: string }[] = [
+	{ field: "checkpointMaxAgeDays", key: "maxAgeDays" },
+	{ field: "checkpointMaxStorageMb", key: "maxStorageMb" },
+	{ field: "checkpointMaxPerTask", key: "maxPerTask" },
+]
+
 type CheckpointSettingsProps = HTMLAttributes<HTMLDivElement> & {
 	enableCheckpoints?: boolean
 	checkpointTimeout?: number
-	setCachedStateField: SetCachedStateField<"enableCheckpoints" | "checkpointTimeout">
+	checkpointMaxAgeDays?: number
+	checkpointMaxStorageMb?: number
+	checkpointMaxPerTask?: number
+	setCachedStateField: SetCachedStateField<"enableCheckpoints" | "checkpointTimeout" | RetentionField>
 }
 
 export const CheckpointSettings = ({
 	enableCheckpoints,
 	checkpointTimeout,
+	checkpointMaxAgeDays,
+	checkpointMaxStorageMb,
+	checkpointMaxPerTask,
 	setCachedStateField,
 	...props
 }: CheckpointSettingsProps) => {
 	const { t } = useAppTranslation()
+	const retention = { checkpointMaxAgeDays, checkpointMaxStorageMb, checkpointMaxPerTask }
 	return (
 		<div {...props}>
 			<SectionHeader>{t("settings:sections.checkpoints")}</SectionHeader>
@@ -85,7 +105,123 @@ export const CheckpointSettings = ({
 						</div>
 					</SearchableSetting>
 				)}
+
+				<SearchableSetting
+					settingId="checkpoints-retention"
+					section="checkpoints"
+					label={t("settings:checkpoints.retention.label")}
+					className="mt-4">
+					<label className="block text-sm font-medium mb-1">
+						{t("settings:checkpoints.retention.label")}
+					</label>
+					<div className="text-vscode-descriptionForeground text-sm mb-2">
+						{t("settings:checkpoints.retention.description")}
+					</div>
+					{RETENTION_FIELDS.map(({ field, key }) => (
+						<div key={field} className="flex items-center justify-between gap-2 mt-2">
+							<span className="text-sm">{t(`settings:checkpoints.retention.${key}`)}</span>
+							<FormattedTextField
+								// 0 means no limit as well.
+								value={retention[field] || undefined}
+								onValueChange={(value) => setCachedStateField(field, value)}
+								formatter={unlimitedIntegerFormatter}
+								placeholder={t("settings:checkpoints.retention.unlimited")}
+								style={{ maxWidth: "120px" }}
+								data-testid={`checkpoint-retention-${key}`}
+							/>
+						</div>
+					))}
+				</SearchableSetting>
+
+				<SearchableSetting
+					settingId="checkpoints-storage"
+					section="checkpoints"
+					label={t("settings:checkpoints.storage.label")}
+					className="mt-4">
+					<CheckpointStorage />
+				</SearchableSetting>
 			</Section>
 		</div>
 	)
 }
+
+const CheckpointStorage = () => {
+	const { t } = useAppTranslation()
+	const [usage, setUsage] = useState<CheckpointStorageUsage>()
+
+	useEffect(() => {
+		const handleMessage = (event: MessageEvent) => {
+			if (event.data.type === "checkpointStorageUsage") {
+				setUsage(event.data.checkpointStorage ?? { tasks: [], totalSize: 0 })
+			}
+		}
+
+		window.addEventListener("message", handleMessage)
+		vscode.postMessage({ type: "requestCheckpointStorageUsage" })
+		return () => window.removeEventListener("message", handleMessage)
+	}, [])
+
+	const hasDeletableTasks = usage?.tasks.some(({ isOpen }) => !isOpen)
+
+	return (
+		<div data-testid="checkpoint-storage">
+			<div className="flex items-center justify-between gap-2 mb-2">
+				<label className="block text-sm font-medium">
+					{usage
+						? t("settings:checkpoints.storage.total", { size: prettyBytes(usage.totalSize) })
+						: t("settings:checkpoints.storage.label")}
+				</label>
+				<Button
+					variant="secondary"
+					disabled={!hasDeletableTasks}
+					onClick={() => vscode.postMessage({ type: "deleteTaskCheckpoints" })}
+					data-testid="delete-all-checkpoints">
+					{t("settings:checkpoints.storage.deleteAll")}
+				</Button>
+			</div>
+			<div className="text-vscode-descriptionForeground text-sm mb-2">
+				{t("settings:checkpoints.storage.description")}
+			</div>
+			{!usage ? (
+				<div className="text-vscode-descriptionForeground text-sm">
+					{t("settings:checkpoints.storage.loading")}
+				</div>
+			) : usage.tasks.length === 0 ? (
+				<div className="text-vscode-descriptionForeground text-sm">
+					{t("settings:checkpoints.storage.empty")}
+				</div>
+			) : (
+				<div className="flex flex-col gap-1 max-h-64 overflow-y-auto">
+					{usage.tasks.map(({ taskId, task, size, lastActivity, isOpen }) => (
+						<div key={taskId} className="flex items-center gap-2 text-sm">
+							<div className="flex-1 min-w-0">
+								<div className="truncate" title={task ?? taskId}>
+									{task ?? t("settings:checkpoints.storage.unknownTask")}
+								</div>
+								<div className="text-vscode-descriptionForeground text-xs">
+									{formatDate(lastActivity)}
+								</div>
+							</div>
+							<span className="shrink-0">{prettyBytes(size)}</span>
+							<StandardTooltip
+								content={
+									isOpen
+										? t("settings:checkpoints.storage.taskOpen")
+										: t("settings:checkpoints.storage.delete")
+								}>
+								<Button
+									variant="ghost"
+									size="icon"
+									disabled={isOpen}
+									onClick={() => vscode.postMessage({ type: "deleteTaskCheckpoints", text: taskId })}
+									aria-label={t("settings:checkpoints.storage.delete")}>
+									<span className="codicon codicon-trash" />
+								</Button>
+							</StandardTooltip>
+						</div>
+					))}
+				</div>
+			)}
+		</div>
+	)
+}
@@ -0,0 +1,222 @@
+import { useMemo, useState } from "react"
+import { VSCodeCheckbox, VSCodeTextArea } from "@vscode/webview-ui-toolkit/react"
+
+import {
+	type CondensingStrategy,
+	DEFAULT_CONDENSING_STRATEGY_ID,
+	builtInCondensingStrategies,
+	getAllCondensingStrategies,
+} from "@roo-code/types"
+import { getAllModes } from "@roo/modes"
+
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { useExtensionState } from "@/context/ExtensionStateContext"
+import { Button, Input, Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui"
+
+import { FormattedTextField, unlimitedIntegerFormatter } from "../common/FormattedTextField"
+import { SearchableSetting } from "./SearchableSetting"
+
+// Scope value of the strategy that applies to every mode
+const ALL_MODES = "*"
+// Strategy value of modes that use the strategy for all modes
+const INHERIT = "*"
+
+interface CondensingStrategySettingsProps {
+	condensingStrategy?: string
+	modeCondensingStrategies?: Record<string, string>
+	customCondensingStrategies?: CondensingStrategy[]
+	setCondensingStrategy: (id: string) => void
+	setModeCondensingStrategies: (strategies: Record<string, string>) => void
+	setCustomCondensingStrategies: (strategies: CondensingStrategy[]) => void
+}
+
+export const CondensingStrategySettings = ({
+	condensingStrategy,
+	modeCondensingStrategies,
+	customCondensingStrategies,
+	setCondensingStrategy,
+	setModeCondensingStrategies,
+	setCustomCondensingStrategies,
+}: CondensingStrategySettingsProps) => {
+	const { t } = useAppTranslation()
+	const { customModes } = useExtensionState()
+	const [scope, setScope] = useState(ALL_MODES)
+
+	const modes = useMemo(() => getAllModes(customModes), [customModes])
+	const strategies = useMemo(
+		() => getAllCondensingStrategies(customCondensingStrategies),
+		[customCondensingStrategies],
+	)
+
+	const isBuiltIn = (id: string) => builtInCondensingStrategies.some((strategy) => strategy.id === id)
+	const getName = (strategy: CondensingStrategy) =>
+		isBuiltIn(strategy.id)
+			? t(`settings:contextManagement.condensingStrategy.builtIn.${strategy.id}`)
+			: strategy.name
+
+	const selectedId =
+		scope === ALL_MODES
+			? (condensingStrategy ?? DEFAULT_CONDENSING_STRATEGY_ID)
+			: (modeCondensingStrategies?.[scope] ?? INHERIT)
+	const selectedStrategy = (customCondensingStrategies ?? []).find((strategy) => strategy.id === selectedId)
+
+	const selectStrategy = (id: string) => {
+		if (scope === ALL_MODES) {
+			setCondensingStrategy(id)
+			return
+		}
+
+		const { [scope]: _, ...otherModes } = modeCondensingStrategies ?? {}
+		setModeCondensingStrategies(id === INHERIT ? otherModes : { ...otherModes, [scope]: id })
+	}
+
+	const addStrategy = () => {
+		const strategy: CondensingStrategy = {
+			id: `custom-${Date.now()}`,
+			name: t("settings:contextManagement.condensingStrategy.newStrategyName"),
+			condenseOldToolResults: true,
+			preserveFileEdits: true,
+		}
+
+		setCustomCondensingStrategies([...(customCondensingStrategies ?? []), strategy])
+		selectStrategy(strategy.id)
+	}
+
+	const updateStrategy = <K extends keyof CondensingStrategy>(key: K, value: CondensingStrategy[K]) => {
+		setCustomCondensingStrategies(
+			(customCondensingStrategies ?? []).map((strategy) =>
+				strategy.id === selectedId ? { ...strategy, [key]: value } : strategy,
+			),
+		)
+	}
+
+	const deleteStrategy = () => {
+		setCustomCondensingStrategies(
+			(customCondensingStrategies ?? []).filter((strategy) => strategy.id !== selectedId),
+		)
+
+		if (condensingStrategy === selectedId) {
+			setCondensingStrategy(DEFAULT_CONDENSING_STRATEGY_ID)
+		}
+
+		setModeCondensingStrategies(
+			Object.fromEntries(Object.entries(modeCondensingStrategies ?? {}).filter(([, id]) => id !== selectedId)),
+		)
+	}
+
+	return (
+		<SearchableSetting
+			settingId="context-condensing-strategy"
+			section="contextManagement"
+			label={t("settings:contextManagement.condensingStrategy.label")}
+			data-testid="condensing-strategy-settings">
+			<label className="block font-medium mb-1">{t("settings:contextManagement.condensingStrategy.label")}</label>
+			<div className="text-sm text-vscode-descriptionForeground mb-2">
+				{t("settings:contextManagement.condensingStrategy.description")}
+			</div>
+
+			<div className="flex gap-2 mb-2">
+				<Select value={scope} onValueChange={setScope}>
+					<SelectTrigger className="flex-1" data-testid="condensing-strategy-scope">
+						<SelectValue />
+					</SelectTrigger>
+					<SelectContent>
+						<SelectItem value={ALL_MODES}>
+							{t("settings:contextManagement.condensingStrategy.allModes")}
+						</SelectItem>
+						{modes.map((mode) => (
+							<SelectItem key={mode.slug} value={mode.slug}>
+								{mode.name}
+							</SelectItem>
+						))}
+					</SelectContent>
+				</Select>
+				<Select value={selectedId} onValueChange={selectStrategy}>
+					<SelectTrigger className="flex-1" data-testid="condensing-strategy-select">
+						<SelectValue />
+					</SelectTrigger>
+					<SelectContent>
+						{scope !== ALL_MODES && (
+							<SelectItem value={INHERIT}>
+								{t("settings:contextManagement.condensingStrategy.inherit")}
+							</SelectItem>
+						)}
+						{strategies.map((strategy) => (
+							<SelectItem key={strategy.id} value={strategy.id}>
+								{getName(strategy)}
+							</SelectItem>
+						))}
+					</SelectContent>
+				</Select>
+				<Button
+					variant="secondary"
+					onClick={addStrategy}
+					title={t("settings:contextManagement.condensingStrategy.add")}
+					data-testid="condensing-strategy-add">
+					<span className="codicon codicon-add" />
+				</Button>
+			</div>
+
+			{isBuiltIn(selectedId) && (
+				<div className="text-sm text-vscode-descriptionForeground">
+					{t(`settings:contextManagement.condensingStrategy.builtInDescription.${selectedId}`)}
+				</div>
+			)}
+
+			{selectedStrategy && (
+				<div className="flex flex-col gap-2 pl-3 border-l-2 border-vscode-button-background">
+					<div className="flex gap-2 items-center">
+						<Input
+							value={selectedStrategy.name}
+							onChange={(e) => updateStrategy("name", e.target.value)}
+							placeholder={t("settings:contextManagement.condensingStrategy.name")}
+							className="flex-1"
+							data-testid="condensing-strategy-name"
+						/>
+						<Button
+							variant="ghost"
+							size="icon"
+							onClick={deleteStrategy}
+							title={t("settings:contextManagement.condensingStrategy.delete")}
+							data-testid="condensing-strategy-delete">
+							<span className="codicon codicon-trash" />
+						</Button>
+					</div>
+					<VSCodeCheckbox
+						checked={selectedStrategy.condenseOldToolResults ?? false}
+						onChange={(e: any) => updateStrategy("condenseOldToolResults", e.target.checked)}>
+						{t("settings:contextManagement.condensingStrategy.condenseOldToolResults")}
+					</VSCodeCheckbox>
+					<VSCodeCheckbox
+						checked={selectedStrategy.preserveFileEdits ?? false}
+						onChange={(e: any) => updateStrategy("preserveFileEdits", e.target.checked)}>
+						{t("settings:contextManagement.condensingStrategy.preserveFileEdits")}
+					</VSCodeCheckbox>
+					<div className="flex items-center gap-2">
+						<label className="text-sm whitespace-nowrap">
+							{t("settings:contextManagement.condensingStrategy.keepRecentUserMessages")}
+						</label>
+						<FormattedTextField
+							value={selectedStrategy.keepRecentUserMessages}
+							onValueChange={(value) => updateStrategy("keepRecentUserMessages", value)}
+							formatter={unlimitedIntegerFormatter}
+							placeholder={t("settings:contextManagement.condensingStrategy.none")}
+							style={{ maxWidth: "100px" }}
+						/>
+					</div>
+					<label className="text-sm">{t("settings:contextManagement.condensingStrategy.prompt")}</label>
+					<VSCodeTextArea
+						resize="vertical"
+						value={selectedStrategy.prompt ?? ""}
+						onInput={(e) =>
+							updateStrategy("prompt", ((e as any).target as HTMLTextAreaElement).value || undefined)
+						}
+						placeholder={t("settings:contextManagement.condensingStrategy.promptPlaceholder")}
+						rows={4}
+						className="w-full"
+					/>
+				</div>
+			)}
+		</SearchableSetting>
+	)
+}
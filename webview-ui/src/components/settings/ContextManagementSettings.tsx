@@ -4,6 +4,7 @@ import { useAppTranslation } from "@/i18n/TranslationContext"
 import { VSCodeCheckbox, VSCodeTextArea } from "@vscode/webview-ui-toolkit/react"
 import { FoldVertical } from "lucide-react"
 
+import { DEFAULT_READ_FILE_TOKEN_BUDGET } from "@roo-code/types"
 import { supportPrompt } from "@roo/support-prompt"
 
 import { cn } from "@/lib/utils"
@@ -35,6 +36,7 @@ type ContextManagementSettingsProps = HTMLAttributes<HTMLDivElement> & {
 	enableSubfolderRules?: boolean
 	maxImageFileSize?: number
 	maxTotalImageSize?: number
+	readFileTokenBudget?: number
 	profileThresholds?: Record<string, number>
 	includeDiagnosticMessages?: boolean
 	maxDiagnosticMessages?: number
@@ -53,6 +55,7 @@ type ContextManagementSettingsProps = HTMLAttributes<HTMLDivElement> & {
 		| "enableSubfolderRules"
 		| "maxImageFileSize"
 		| "maxTotalImageSize"
+		| "readFileTokenBudget"
 		| "profileThresholds"
 		| "includeDiagnosticMessages"
 		| "maxDiagnosticMessages"
@@ -74,6 +77,7 @@ export const ContextManagementSettings = ({
 	setCachedStateField,
 	maxImageFileSize,
 	maxTotalImageSize,
+	readFileTokenBudget,
 	profileThresholds = {},
 	includeDiagnosticMessages,
 	maxDiagnosticMessages,
@@ -308,6 +312,36 @@ export const ContextManagementSettings = ({
 					</div>
 				</SearchableSetting>
 
+				<SearchableSetting
+					settingId="context-read-file-token-budget"
+					section="contextManagement"
+					label={t("settings:contextManagement.readFileTokenBudget.label")}>
+					<div className="flex flex-col gap-2">
+						<span className="font-medium">{t("settings:contextManagement.readFileTokenBudget.label")}</span>
+						<div className="flex items-center gap-4">
+							<Input
+								type="number"
+								pattern="[0-9]*"
+								className="w-24 bg-vscode-input-background text-vscode-input-foreground border border-vscode-input-border px-2 py-1 rounded text-right [appearance:textfield] [&::-webkit-outer-spin-button]:appearance-none [&::-webkit-inner-spin-button]:appearance-none"
+								value={readFileTokenBudget ?? DEFAULT_READ_FILE_TOKEN_BUDGET}
+								min={0}
+								onChange={(e) => {
+									const newValue = parseInt(e.target.value, 10)
+									if (!isNaN(newValue) && newValue >= 0) {
+										setCachedStateField("readFileTokenBudget", newValue)
+									}
+								}}
+								onClick={(e) => e.currentTarget.select()}
+								data-testid="read-file-token-budget-input"
+							/>
+							<span>{t("settings:contextManagement.readFileTokenBudget.tokens")}</span>
+						</div>
+					</div>
+					<div className="text-vscode-descriptionForeground text-sm mt-2">
+						{t("settings:contextManagement.readFileTokenBudget.description")}
+					</div>
+				</SearchableSetting>
+
 				<SearchableSetting
 					settingId="context-include-diagnostic-messages"
 					section="contextManagement"
@@ -1,6 +1,6 @@
 import { HTMLAttributes } from "react"
 
-import type { Experiments, ImageGenerationProvider } from "@roo-code/types"
+import type { Experiments, ImageGenerationProvider, ProviderSettingsEntry } from "@roo-code/types"
 
 import { EXPERIMENT_IDS, experimentConfigsMap } from "@roo/experiments"
 
@@ -14,6 +14,8 @@ import { SearchableSetting } from "./SearchableSetting"
 import { ExperimentalFeature } from "./ExperimentalFeature"
 import { ImageGenerationSettings } from "./ImageGenerationSettings"
 import { CustomToolsSettings } from "./CustomToolsSettings"
+import { FastApplySettings } from "./FastApplySettings"
+import { ParallelSubtasksSettings } from "./ParallelSubtasksSettings"
 
 type ExperimentalSettingsProps = HTMLAttributes<HTMLDivElement> & {
 	experiments: Experiments
@@ -26,6 +28,11 @@ type ExperimentalSettingsProps = HTMLAttributes<HTMLDivElement> & {
 	setImageGenerationProvider?: (provider: ImageGenerationProvider) => void
 	setOpenRouterImageApiKey?: (apiKey: string) => void
 	setImageGenerationSelectedModel?: (model: string) => void
+	listApiConfigMeta?: ProviderSettingsEntry[]
+	fastApplyApiConfigId?: string
+	setFastApplyApiConfigId?: (configId: string) => void
+	maxParallelSubtasks?: number
+	setMaxParallelSubtasks?: (value: number) => void
 }
 
 export const ExperimentalSettings = ({
@@ -39,6 +46,11 @@ export const ExperimentalSettings = ({
 	setImageGenerationProvider,
 	setOpenRouterImageApiKey,
 	setImageGenerationSelectedModel,
+	listApiConfigMeta,
+	fastApplyApiConfigId,
+	setFastApplyApiConfigId,
+	maxParallelSubtasks,
+	setMaxParallelSubtasks,
 	className,
 	...props
 }: ExperimentalSettingsProps) => {
@@ -83,6 +95,41 @@ export const ExperimentalSettings = ({
 								</SearchableSetting>
 							)
 						}
+						if (config[0] === "FAST_APPLY" && setFastApplyApiConfigId) {
+							return (
+								<SearchableSetting
+									key={config[0]}
+									settingId={`experimental-${config[0].toLowerCase()}`}
+									section="experimental"
+									label={label}>
+									<FastApplySettings
+										enabled={experiments[EXPERIMENT_IDS.FAST_APPLY] ?? false}
+										onChange={(enabled) => setExperimentEnabled(EXPERIMENT_IDS.FAST_APPLY, enabled)}
+										listApiConfigMeta={listApiConfigMeta}
+										fastApplyApiConfigId={fastApplyApiConfigId}
+										setFastApplyApiConfigId={setFastApplyApiConfigId}
+									/>
+								</SearchableSetting>
+							)
+						}
+						if (config[0] === "PARALLEL_SUBTASKS" && setMaxParallelSubtasks) {
+							return (
+								<SearchableSetting
+									key={config[0]}
+									settingId={`experimental-${config[0].toLowerCase()}`}
+									section="experimental"
+									label={label}>
+									<ParallelSubtasksSettings
+										enabled={experiments[EXPERIMENT_IDS.PARALLEL_SUBTASKS] ?? false}
+										onChange={(enabled) =>
+											setExperimentEnabled(EXPERIMENT_IDS.PARALLEL_SUBTASKS, enabled)
+										}
+										maxParallelSubtasks={maxParallelSubtasks}
+										setMaxParallelSubtasks={setMaxParallelSubtasks}
+									/>
+								</SearchableSetting>
+							)
+						}
 						if (config[0] === "CUSTOM_TOOLS") {
 							return (
 								<SearchableSetting
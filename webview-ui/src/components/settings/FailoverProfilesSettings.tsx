@@ -0,0 +1,94 @@
+import type { ProviderSettingsEntry } from "@roo-code/types"
+
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { Button, Select, SelectContent, SelectItem, SelectTrigger, SelectValue, StandardTooltip } from "@/components/ui"
+
+import { SearchableSetting } from "./SearchableSetting"
+
+interface FailoverProfilesSettingsProps {
+	listApiConfigMeta: ProviderSettingsEntry[]
+	failoverApiConfigIds: string[]
+	setFailoverApiConfigIds: (ids: string[]) => void
+}
+
+export const FailoverProfilesSettings = ({
+	listApiConfigMeta,
+	failoverApiConfigIds,
+	setFailoverApiConfigIds,
+}: FailoverProfilesSettingsProps) => {
+	const { t } = useAppTranslation()
+
+	// Profiles deleted since they were added to the chain are dropped from the list
+	const chain = failoverApiConfigIds.flatMap((id) => listApiConfigMeta.find((profile) => profile.id === id) ?? [])
+	const available = listApiConfigMeta.filter((profile) => !failoverApiConfigIds.includes(profile.id))
+
+	const move = (index: number, offset: number) => {
+		const ids = chain.map((profile) => profile.id)
+		const [id] = ids.splice(index, 1)
+		ids.splice(index + offset, 0, id)
+		setFailoverApiConfigIds(ids)
+	}
+
+	return (
+		<SearchableSetting
+			settingId="providers-failover"
+			section="providers"
+			label={t("settings:providers.failover.label")}
+			data-testid="failover-profiles">
+			<label className="block font-medium mb-1">{t("settings:providers.failover.label")}</label>
+			<div className="text-sm text-vscode-descriptionForeground mb-2">
+				{t("settings:providers.failover.description")}
+			</div>
+
+			{chain.map((profile, index) => (
+				<div key={profile.id} className="flex items-center gap-1" data-testid={`failover-${profile.id}`}>
+					<span className="grow truncate">
+						{index + 1}. {profile.name}
+					</span>
+					<StandardTooltip content={t("settings:providers.failover.moveUp")}>
+						<Button variant="ghost" size="icon" disabled={index === 0} onClick={() => move(index, -1)}>
+							<span className="codicon codicon-arrow-up" />
+						</Button>
+					</StandardTooltip>
+					<StandardTooltip content={t("settings:providers.failover.moveDown")}>
+						<Button
+							variant="ghost"
+							size="icon"
+							disabled={index === chain.length - 1}
+							onClick={() => move(index, 1)}>
+							<span className="codicon codicon-arrow-down" />
+						</Button>
+					</StandardTooltip>
+					<StandardTooltip content={t("settings:providers.failover.remove")}>
+						<Button
+							variant="ghost"
+							size="icon"
+							onClick={() =>
+								setFailoverApiConfigIds(chain.filter(({ id }) => id !== profile.id).map(({ id }) => id))
+							}
+							data-testid={`remove-failover-${profile.id}`}>
+							<span className="codicon codicon-close" />
+						</Button>
+					</StandardTooltip>
+				</div>
+			))}
+
+			{available.length > 0 && (
+				<Select
+					value=""
+					onValueChange={(id) => setFailoverApiConfigIds([...chain.map((profile) => profile.id), id])}>
+					<SelectTrigger className="w-full mt-1" data-testid="add-failover-profile">
+						<SelectValue placeholder={t("settings:providers.failover.add")} />
+					</SelectTrigger>
+					<SelectContent>
+						{available.map((profile) => (
+							<SelectItem key={profile.id} value={profile.id}>
+								{profile.name}
+							</SelectItem>
+						))}
+					</SelectContent>
+				</Select>
+			)}
+		</SearchableSetting>
+	)
+}
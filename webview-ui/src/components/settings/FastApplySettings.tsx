@@ -0,0 +1,69 @@
+import { VSCodeCheckbox } from "@vscode/webview-ui-toolkit/react"
+
+import type { ProviderSettingsEntry } from "@roo-code/types"
+
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@src/components/ui"
+
+interface FastApplySettingsProps {
+	enabled: boolean
+	onChange: (enabled: boolean) => void
+	listApiConfigMeta?: ProviderSettingsEntry[]
+	fastApplyApiConfigId?: string
+	setFastApplyApiConfigId: (configId: string) => void
+}
+
+export const FastApplySettings = ({
+	enabled,
+	onChange,
+	listApiConfigMeta,
+	fastApplyApiConfigId,
+	setFastApplyApiConfigId,
+}: FastApplySettingsProps) => {
+	const { t } = useAppTranslation()
+
+	return (
+		<div className="space-y-4">
+			<div>
+				<div className="flex items-center gap-2">
+					<VSCodeCheckbox checked={enabled} onChange={(e: any) => onChange(e.target.checked)}>
+						<span className="font-medium">{t("settings:experimental.FAST_APPLY.name")}</span>
+					</VSCodeCheckbox>
+				</div>
+				<p className="text-vscode-descriptionForeground text-sm mt-0">
+					{t("settings:experimental.FAST_APPLY.description")}
+				</p>
+			</div>
+
+			{enabled && (
+				<div className="ml-2 space-y-3">
+					<div>
+						<label className="block font-medium mb-1">
+							{t("settings:experimental.FAST_APPLY.apiConfigurationLabel")}
+						</label>
+						<Select
+							value={fastApplyApiConfigId || "-"}
+							onValueChange={(value) => setFastApplyApiConfigId(value === "-" ? "" : value)}>
+							<SelectTrigger data-testid="fast-apply-api-config-select" className="w-full">
+								<SelectValue placeholder={t("settings:experimental.FAST_APPLY.useCurrentConfig")} />
+							</SelectTrigger>
+							<SelectContent>
+								<SelectItem value="-">
+									{t("settings:experimental.FAST_APPLY.useCurrentConfig")}
+								</SelectItem>
+								{(listApiConfigMeta || []).map((config) => (
+									<SelectItem key={config.id} value={config.id}>
+										{config.name}
+									</SelectItem>
+								))}
+							</SelectContent>
+						</Select>
+						<p className="text-vscode-descriptionForeground text-xs mt-1">
+							{t("settings:experimental.FAST_APPLY.apiConfigurationDescription")}
+						</p>
+					</div>
+				</div>
+			)}
+		</div>
+	)
+}
@@ -0,0 +1,29 @@
+import { Checkbox } from "vscrui"
+
+import { useAppTranslation } from "@/i18n/TranslationContext"
+
+interface GeminiContextCachingSettingProps {
+	onChange: (value: boolean) => void
+	geminiContextCachingEnabled?: boolean
+}
+
+export const GeminiContextCachingSetting = ({
+	onChange,
+	geminiContextCachingEnabled,
+}: GeminiContextCachingSettingProps) => {
+	const { t } = useAppTranslation()
+
+	return (
+		<div>
+			<Checkbox
+				data-testid="checkbox-gemini-context-caching"
+				checked={geminiContextCachingEnabled ?? false}
+				onChange={onChange}>
+				{t("settings:providers.geminiContextCaching.label")}
+			</Checkbox>
+			<div className="text-sm text-vscode-descriptionForeground mt-1 ml-6">
+				{t("settings:providers.geminiContextCaching.description")}
+			</div>
+		</div>
+	)
+}
@@ -2,23 +2,27 @@ import { HTMLAttributes } from "react"
 import { useAppTranslation } from "@/i18n/TranslationContext"
 import { VSCodeCheckbox } from "@vscode/webview-ui-toolkit/react"
 
+import { type TtsContent, ttsContents } from "@roo-code/types"
+
 import { SetCachedStateField } from "./types"
 import { SectionHeader } from "./SectionHeader"
 import { Section } from "./Section"
 import { SearchableSetting } from "./SearchableSetting"
-import { Slider } from "../ui"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue, Slider } from "../ui"
 
 type NotificationSettingsProps = HTMLAttributes<HTMLDivElement> & {
 	ttsEnabled?: boolean
 	ttsSpeed?: number
+	ttsContent?: TtsContent
 	soundEnabled?: boolean
 	soundVolume?: number
-	setCachedStateField: SetCachedStateField<"ttsEnabled" | "ttsSpeed" | "soundEnabled" | "soundVolume">
+	setCachedStateField: SetCachedStateField<"ttsEnabled" | "ttsSpeed" | "ttsContent" | "soundEnabled" | "soundVolume">
 }
 
 export const NotificationSettings = ({
 	ttsEnabled,
 	ttsSpeed,
+	ttsContent,
 	soundEnabled,
 	soundVolume,
 	setCachedStateField,
@@ -66,6 +70,32 @@ export const NotificationSettings = ({
 								<span className="w-10">{((ttsSpeed ?? 1.0) * 100).toFixed(0)}%</span>
 							</div>
 						</SearchableSetting>
+
+						<SearchableSetting
+							settingId="notifications-tts-content"
+							section="notifications"
+							label={t("settings:notifications.tts.contentLabel")}>
+							<label className="block font-medium mb-1">
+								{t("settings:notifications.tts.contentLabel")}
+							</label>
+							<Select
+								value={ttsContent ?? "all"}
+								onValueChange={(value) => setCachedStateField("ttsContent", value as TtsContent)}>
+								<SelectTrigger className="w-full" data-testid="tts-content-select">
+									<SelectValue />
+								</SelectTrigger>
+								<SelectContent>
+									{ttsContents.map((content) => (
+										<SelectItem key={content} value={content}>
+											{t(`settings:notifications.tts.content.${content}`)}
+										</SelectItem>
+									))}
+								</SelectContent>
+							</Select>
+							<div className="text-vscode-descriptionForeground text-sm mt-1">
+								{t("settings:notifications.tts.contentDescription")}
+							</div>
+						</SearchableSetting>
 					</div>
 				)}
 
@@ -0,0 +1,62 @@
+import { VSCodeCheckbox } from "@vscode/webview-ui-toolkit/react"
+
+import { DEFAULT_MAX_PARALLEL_SUBTASKS } from "@roo-code/types"
+
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { Slider } from "@src/components/ui"
+
+interface ParallelSubtasksSettingsProps {
+	enabled: boolean
+	onChange: (enabled: boolean) => void
+	maxParallelSubtasks?: number
+	setMaxParallelSubtasks: (value: number) => void
+}
+
+export const ParallelSubtasksSettings = ({
+	enabled,
+	onChange,
+	maxParallelSubtasks,
+	setMaxParallelSubtasks,
+}: ParallelSubtasksSettingsProps) => {
+	const { t } = useAppTranslation()
+	const value = maxParallelSubtasks ?? DEFAULT_MAX_PARALLEL_SUBTASKS
+
+	return (
+		<div className="space-y-4">
+			<div>
+				<div className="flex items-center gap-2">
+					<VSCodeCheckbox checked={enabled} onChange={(e: any) => onChange(e.target.checked)}>
+						<span className="font-medium">{t("settings:experimental.PARALLEL_SUBTASKS.name")}</span>
+					</VSCodeCheckbox>
+				</div>
+				<p className="text-vscode-descriptionForeground text-sm mt-0">
+					{t("settings:experimental.PARALLEL_SUBTASKS.description")}
+				</p>
+			</div>
+
+			{enabled && (
+				<div className="ml-2 space-y-3">
+					<div>
+						<label className="block font-medium mb-1">
+							{t("settings:experimental.PARALLEL_SUBTASKS.maxConcurrencyLabel")}
+						</label>
+						<div className="flex items-center gap-2">
+							<Slider
+								min={1}
+								max={10}
+								step={1}
+								value={[value]}
+								onValueChange={([newValue]) => setMaxParallelSubtasks(newValue)}
+								data-testid="max-parallel-subtasks-slider"
+							/>
+							<span className="w-10">{value}</span>
+						</div>
+						<p className="text-vscode-descriptionForeground text-xs mt-1">
+							{t("settings:experimental.PARALLEL_SUBTASKS.maxConcurrencyDescription")}
+						</p>
+					</div>
+				</div>
+			)}
+		</div>
+	)
+}
@@ -0,0 +1,96 @@
+import type { ProjectConfig } from "@roo-code/types"
+
+import { vscode } from "@/utils/vscode"
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { useExtensionState } from "@/context/ExtensionStateContext"
+
+// What the configuration sets, as the translation keys and values of its summary lines.
+const summarize = ({
+	customModes,
+	allowedCommands,
+	deniedCommands,
+	autoApprove,
+	rooignore,
+	allowedProviders,
+}: ProjectConfig) => {
+	const disabledApprovals = Object.entries(autoApprove ?? {}).filter(([, value]) => value === false).length
+
+	return [
+		{ key: "modes", count: customModes?.length ?? 0 },
+		{ key: "allowedCommands", count: allowedCommands?.length ?? 0 },
+		{ key: "deniedCommands", count: deniedCommands?.length ?? 0 },
+		{ key: "disabledApprovals", count: disabledApprovals },
+		{ key: "deniedWritePaths", count: autoApprove?.deniedWritePaths?.length ?? 0 },
+		{ key: "rooignore", count: rooignore?.length ?? 0 },
+		{ key: "providers", count: Object.keys(allowedProviders ?? {}).length },
+	].filter(({ count }) => count > 0)
+}
+
+interface ProjectConfigNoticeProps {
+	// The user's allowed commands, and how to add the ones the repository suggests
+	allowedCommands?: string[]
+	onAllowCommands?: (commands: string[]) => void
+}
+
+/**
+ * Tells that the repository's `.roo/config` applies shared settings, which
+ * the user's settings can't loosen, and what it sets. The commands it allows
+ * are only suggestions, which the user can add to their allowed commands.
+ */
+export const ProjectConfigNotice = ({ allowedCommands, onAllowCommands }: ProjectConfigNoticeProps) => {
+	const { t } = useAppTranslation()
+	const { projectConfig } = useExtensionState()
+
+	if (!projectConfig) {
+		return null
+	}
+
+	const { filePath, config, error } = projectConfig
+	const suggestedCommands = (config?.allowedCommands ?? []).filter(
+		(command) => !(allowedCommands ?? []).includes(command),
+	)
+
+	return (
+		<div
+			className="flex flex-col gap-1 px-3 py-2 rounded-lg bg-vscode-inputValidation-warningBackground border border-vscode-inputValidation-warningBorder text-sm"
+			data-testid="project-config-notice">
+			<div className="flex items-center gap-2">
+				<span className="codicon codicon-law" />
+				<span className="font-bold grow">{t("settings:projectConfig.title")}</span>
+				<button
+					className="text-vscode-textLink-foreground hover:underline cursor-pointer"
+					onClick={() => vscode.postMessage({ type: "openFile", text: filePath })}>
+					{t("settings:projectConfig.open")}
+				</button>
+			</div>
+			{error ? (
+				<div className="text-vscode-errorForeground">{t("settings:projectConfig.invalid", { error })}</div>
+			) : (
+				<>
+					<div className="text-vscode-descriptionForeground">{t("settings:projectConfig.description")}</div>
+					{config && (
+						<ul className="list-disc pl-5 m-0">
+							{summarize(config).map(({ key, count }) => (
+								<li key={key}>{t(`settings:projectConfig.summary.${key}`, { count })}</li>
+							))}
+						</ul>
+					)}
+					{onAllowCommands && suggestedCommands.length > 0 && (
+						<div className="flex items-center gap-2" data-testid="project-config-suggested-commands">
+							<span className="grow">
+								{t("settings:projectConfig.suggestedCommands", {
+									commands: suggestedCommands.join(", "),
+								})}
+							</span>
+							<button
+								className="text-vscode-textLink-foreground hover:underline cursor-pointer"
+								onClick={() => onAllowCommands(suggestedCommands)}>
+								{t("settings:projectConfig.allowSuggestedCommands")}
+							</button>
+						</div>
+					)}
+				</>
+			)}
+		</div>
+	)
+}
@@ -0,0 +1,29 @@
+import { useAppTranslation } from "@/i18n/TranslationContext"
+
+import { FormattedTextField, unlimitedIntegerFormatter } from "../common/FormattedTextField"
+
+interface RequestsPerMinuteControlProps {
+	value?: number
+	onChange: (value: number | undefined) => void
+}
+
+export const RequestsPerMinuteControl = ({ value, onChange }: RequestsPerMinuteControlProps) => {
+	const { t } = useAppTranslation()
+
+	return (
+		<div className="flex flex-col gap-1">
+			<label className="block font-medium mb-1">{t("settings:providers.requestsPerMinute.label")}</label>
+			<FormattedTextField
+				value={value}
+				onValueChange={onChange}
+				formatter={unlimitedIntegerFormatter}
+				placeholder={t("settings:providers.requestsPerMinute.unlimited")}
+				style={{ maxWidth: "200px" }}
+				data-testid="requests-per-minute"
+			/>
+			<div className="text-sm text-vscode-descriptionForeground">
+				{t("settings:providers.requestsPerMinute.description")}
+			</div>
+		</div>
+	)
+}
@@ -29,6 +29,7 @@ import {
 	ArrowLeft,
 	GitCommitVertical,
 	GraduationCap,
+	ChartColumn,
 } from "lucide-react"
 
 import {
@@ -64,11 +65,16 @@ import { Tab, TabContent, TabHeader, TabList, TabTrigger } from "../common/Tab"
 import { SetCachedStateField, SetExperimentEnabled } from "./types"
 import { SectionHeader } from "./SectionHeader"
 import ApiConfigManager from "./ApiConfigManager"
+import { ProjectConfigNotice } from "./ProjectConfigNotice"
 import ApiOptions from "./ApiOptions"
+import { FailoverProfilesSettings } from "./FailoverProfilesSettings"
 import { AutoApproveSettings } from "./AutoApproveSettings"
+import { BudgetSettings } from "./BudgetSettings"
+import { UsageSettings } from "./UsageSettings"
 import { CheckpointSettings } from "./CheckpointSettings"
 import { NotificationSettings } from "./NotificationSettings"
 import { ContextManagementSettings } from "./ContextManagementSettings"
+import { CondensingStrategySettings } from "./CondensingStrategySettings"
 import { TerminalSettings } from "./TerminalSettings"
 import { ExperimentalSettings } from "./ExperimentalSettings"
 import { LanguageSettings } from "./LanguageSettings"
@@ -98,6 +104,7 @@ export interface SettingsViewRef {
 export const sectionNames = [
 	"providers",
 	"autoApprove",
+	"usage",
 	"slashCommands",
 	"skills",
 	"checkpoints",
@@ -153,6 +160,8 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 		deniedCommands,
 		allowedMaxRequests,
 		allowedMaxCost,
+		taskBudget,
+		modeBudgets,
 		language,
 		alwaysAllowExecute,
 		alwaysAllowMcp,
@@ -161,10 +170,18 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 		alwaysAllowWrite,
 		alwaysAllowWriteOutsideWorkspace,
 		alwaysAllowWriteProtected,
+		allowedWritePaths,
+		deniedWritePaths,
 		autoCondenseContext,
 		autoCondenseContextPercent,
+		condensingStrategy,
+		modeCondensingStrategies,
+		customCondensingStrategies,
 		enableCheckpoints,
 		checkpointTimeout,
+		checkpointMaxAgeDays,
+		checkpointMaxStorageMb,
+		checkpointMaxPerTask,
 		experiments,
 		maxOpenTabsContext,
 		maxWorkspaceFiles,
@@ -172,6 +189,7 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 		soundEnabled,
 		ttsEnabled,
 		ttsSpeed,
+		ttsContent,
 		soundVolume,
 		telemetrySetting,
 		terminalOutputPreviewSize,
@@ -188,8 +206,10 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 		enableSubfolderRules,
 		maxImageFileSize,
 		maxTotalImageSize,
+		readFileTokenBudget,
 		customSupportPrompts,
 		profileThresholds,
+		failoverApiConfigIds,
 		alwaysAllowFollowupQuestions,
 		followupAutoApproveTimeoutMs,
 		includeDiagnosticMessages,
@@ -198,6 +218,8 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 		imageGenerationProvider,
 		openRouterImageApiKey,
 		openRouterImageGenerationSelectedModel,
+		fastApplyApiConfigId,
+		maxParallelSubtasks,
 		reasoningBlockCollapsed,
 		enterBehavior,
 		includeCurrentTime,
@@ -312,6 +334,17 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 		})
 	}, [])
 
+	const setDebugRecorder = useCallback((debugRecorder: boolean) => {
+		setCachedState((prevState) => {
+			if (prevState.debugRecorder === debugRecorder) {
+				return prevState
+			}
+
+			setChangeDetected(true)
+			return { ...prevState, debugRecorder }
+		})
+	}, [])
+
 	const setImageGenerationProvider = useCallback((provider: ImageGenerationProvider) => {
 		setCachedState((prevState) => {
 			if (prevState.imageGenerationProvider !== provider) {
@@ -342,6 +375,26 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 		})
 	}, [])
 
+	const setFastApplyApiConfigId = useCallback((configId: string) => {
+		setCachedState((prevState) => {
+			if (prevState.fastApplyApiConfigId !== configId) {
+				setChangeDetected(true)
+			}
+
+			return { ...prevState, fastApplyApiConfigId: configId }
+		})
+	}, [])
+
+	const setMaxParallelSubtasks = useCallback((value: number) => {
+		setCachedState((prevState) => {
+			if (prevState.maxParallelSubtasks !== value) {
+				setChangeDetected(true)
+			}
+
+			return { ...prevState, maxParallelSubtasks: value }
+		})
+	}, [])
+
 	const setCustomSupportPromptsField = useCallback((prompts: Record<string, string | undefined>) => {
 		setCachedState((prevState) => {
 			const previousStr = JSON.stringify(prevState.customSupportPrompts)
@@ -369,6 +422,8 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 					alwaysAllowWrite: alwaysAllowWrite ?? undefined,
 					alwaysAllowWriteOutsideWorkspace: alwaysAllowWriteOutsideWorkspace ?? undefined,
 					alwaysAllowWriteProtected: alwaysAllowWriteProtected ?? undefined,
+					allowedWritePaths: allowedWritePaths ?? [],
+					deniedWritePaths: deniedWritePaths ?? [],
 					alwaysAllowExecute: alwaysAllowExecute ?? undefined,
 					alwaysAllowMcp,
 					alwaysAllowModeSwitch,
@@ -379,14 +434,23 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 					// extension host. We may need to do the same for other nullable fields.
 					allowedMaxRequests: allowedMaxRequests ?? null,
 					allowedMaxCost: allowedMaxCost ?? null,
+					taskBudget: taskBudget ?? {},
+					modeBudgets: modeBudgets ?? {},
 					autoCondenseContext,
 					autoCondenseContextPercent,
+					condensingStrategy,
+					modeCondensingStrategies: modeCondensingStrategies ?? {},
+					customCondensingStrategies: customCondensingStrategies ?? [],
 					soundEnabled: soundEnabled ?? true,
 					soundVolume: soundVolume ?? 0.5,
 					ttsEnabled,
 					ttsSpeed,
+					ttsContent,
 					enableCheckpoints: enableCheckpoints ?? false,
 					checkpointTimeout: checkpointTimeout ?? DEFAULT_CHECKPOINT_TIMEOUT_SECONDS,
+					checkpointMaxAgeDays: checkpointMaxAgeDays ?? 0,
+					checkpointMaxStorageMb: checkpointMaxStorageMb ?? 0,
+					checkpointMaxPerTask: checkpointMaxPerTask ?? 0,
 					writeDelayMs,
 					terminalShellIntegrationTimeout: terminalShellIntegrationTimeout ?? 30_000,
 					terminalShellIntegrationDisabled,
@@ -404,6 +468,7 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 					enableSubfolderRules: enableSubfolderRules ?? false,
 					maxImageFileSize: maxImageFileSize ?? 5,
 					maxTotalImageSize: maxTotalImageSize ?? 20,
+					readFileTokenBudget,
 					includeDiagnosticMessages:
 						includeDiagnosticMessages !== undefined ? includeDiagnosticMessages : true,
 					maxDiagnosticMessages: maxDiagnosticMessages ?? 50,
@@ -417,9 +482,12 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 					includeCurrentCost: includeCurrentCost ?? true,
 					maxGitStatusFiles: maxGitStatusFiles ?? 0,
 					profileThresholds,
+					failoverApiConfigIds: failoverApiConfigIds ?? [],
 					imageGenerationProvider,
 					openRouterImageApiKey,
 					openRouterImageGenerationSelectedModel,
+								fastApplyApiConfigId,
+					maxParallelSubtasks,
 					experiments,
 					customSupportPrompts,
 				},
@@ -430,6 +498,7 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 			vscode.postMessage({ type: "upsertApiConfiguration", text: currentApiConfigName, apiConfiguration })
 			vscode.postMessage({ type: "telemetrySetting", text: telemetrySetting })
 			vscode.postMessage({ type: "debugSetting", bool: cachedState.debug })
+			vscode.postMessage({ type: "debugRecorderSetting", bool: cachedState.debugRecorder })
 
 			setChangeDetected(false)
 		}
@@ -513,6 +582,7 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 			{ id: "skills", icon: GraduationCap },
 			{ id: "slashCommands", icon: SquareSlash },
 			{ id: "autoApprove", icon: CheckCheck },
+			{ id: "usage", icon: ChartColumn },
 			{ id: "mcp", icon: Server },
 			{ id: "checkpoints", icon: GitCommitVertical },
 			{ id: "notifications", icon: Bell },
@@ -739,6 +809,7 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 								<SectionHeader>{t("settings:sections.providers")}</SectionHeader>
 
 								<Section>
+									<ProjectConfigNotice />
 									<ApiConfigManager
 										currentApiConfigName={currentApiConfigName}
 										listApiConfigMeta={listApiConfigMeta}
@@ -774,6 +845,16 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 										setErrorMessage={setErrorMessage}
 									/>
 								</Section>
+
+								<Section>
+									<FailoverProfilesSettings
+										listApiConfigMeta={listApiConfigMeta ?? []}
+										failoverApiConfigIds={failoverApiConfigIds ?? []}
+										setFailoverApiConfigIds={(ids) =>
+											setCachedStateField("failoverApiConfigIds", ids)
+										}
+									/>
+								</Section>
 							</div>
 						)}
 
@@ -785,6 +866,8 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 								alwaysAllowWrite={alwaysAllowWrite}
 								alwaysAllowWriteOutsideWorkspace={alwaysAllowWriteOutsideWorkspace}
 								alwaysAllowWriteProtected={alwaysAllowWriteProtected}
+								allowedWritePaths={allowedWritePaths}
+								deniedWritePaths={deniedWritePaths}
 								alwaysAllowMcp={alwaysAllowMcp}
 								alwaysAllowModeSwitch={alwaysAllowModeSwitch}
 								alwaysAllowSubtasks={alwaysAllowSubtasks}
@@ -799,6 +882,20 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 							/>
 						)}
 
+						{renderTab === "autoApprove" && (
+							<Section>
+								<BudgetSettings
+									taskBudget={taskBudget}
+									modeBudgets={modeBudgets}
+									setTaskBudget={(budget) => setCachedStateField("taskBudget", budget)}
+									setModeBudgets={(budgets) => setCachedStateField("modeBudgets", budgets)}
+								/>
+							</Section>
+						)}
+
+						{/* Usage Section */}
+						{renderTab === "usage" && <UsageSettings />}
+
 						{/* Slash Commands Section */}
 						{renderTab === "slashCommands" && <SlashCommandsSettings />}
 
@@ -810,6 +907,9 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 							<CheckpointSettings
 								enableCheckpoints={enableCheckpoints}
 								checkpointTimeout={checkpointTimeout}
+								checkpointMaxAgeDays={checkpointMaxAgeDays}
+								checkpointMaxStorageMb={checkpointMaxStorageMb}
+								checkpointMaxPerTask={checkpointMaxPerTask}
 								setCachedStateField={setCachedStateField}
 							/>
 						)}
@@ -819,6 +919,7 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 							<NotificationSettings
 								ttsEnabled={ttsEnabled}
 								ttsSpeed={ttsSpeed}
+								ttsContent={ttsContent}
 								soundEnabled={soundEnabled}
 								soundVolume={soundVolume}
 								setCachedStateField={setCachedStateField}
@@ -837,6 +938,7 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 								enableSubfolderRules={enableSubfolderRules}
 								maxImageFileSize={maxImageFileSize}
 								maxTotalImageSize={maxTotalImageSize}
+								readFileTokenBudget={readFileTokenBudget}
 								profileThresholds={profileThresholds}
 								includeDiagnosticMessages={includeDiagnosticMessages}
 								maxDiagnosticMessages={maxDiagnosticMessages}
@@ -850,6 +952,23 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 							/>
 						)}
 
+						{renderTab === "contextManagement" && (
+							<Section>
+								<CondensingStrategySettings
+									condensingStrategy={condensingStrategy}
+									modeCondensingStrategies={modeCondensingStrategies}
+									customCondensingStrategies={customCondensingStrategies}
+									setCondensingStrategy={(id) => setCachedStateField("condensingStrategy", id)}
+									setModeCondensingStrategies={(strategies) =>
+										setCachedStateField("modeCondensingStrategies", strategies)
+									}
+									setCustomCondensingStrategies={(strategies) =>
+										setCachedStateField("customCondensingStrategies", strategies)
+									}
+								/>
+							</Section>
+						)}
+
 						{/* Terminal Section */}
 						{renderTab === "terminal" && (
 							<TerminalSettings
@@ -911,6 +1030,11 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 								setImageGenerationProvider={setImageGenerationProvider}
 								setOpenRouterImageApiKey={setOpenRouterImageApiKey}
 								setImageGenerationSelectedModel={setImageGenerationSelectedModel}
+								listApiConfigMeta={listApiConfigMeta}
+								fastApplyApiConfigId={fastApplyApiConfigId}
+								setFastApplyApiConfigId={setFastApplyApiConfigId}
+								maxParallelSubtasks={maxParallelSubtasks}
+								setMaxParallelSubtasks={setMaxParallelSubtasks}
 							/>
 						)}
 
@@ -926,6 +1050,8 @@ const SettingsView = forwardRef<SettingsViewRef, SettingsViewProps>(({ onDone, t
 								setTelemetrySetting={setTelemetrySetting}
 								debug={cachedState.debug}
 								setDebug={setDebug}
+								debugRecorder={cachedState.debugRecorder}
+								setDebugRecorder={setDebugRecorder}
 							/>
 						)}
 					</SearchIndexProvider>
@@ -0,0 +1,220 @@
+import { HTMLAttributes, useEffect, useMemo, useState } from "react"
+
+import { type UsageCounters, type UsageEntry, USAGE_COUNTERS } from "@roo-code/types"
+
+import { useAppTranslation } from "@/i18n/TranslationContext"
+import { vscode } from "@/utils/vscode"
+import { formatLargeNumber } from "@/utils/format"
+import { Button, Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui"
+
+import { SectionHeader } from "./SectionHeader"
+import { Section } from "./Section"
+
+const RANGES = ["7", "30", "90", "all"] as const
+
+type UsageRange = (typeof RANGES)[number]
+
+const GROUPS = ["workspace", "mode", "model"] as const
+
+type UsageGroup = (typeof GROUPS)[number]
+
+type UsageRow = UsageCounters & { label: string }
+
+const COLUMNS = ["requests", "tokensIn", "tokensOut", "cacheSavings", "cost"] as const
+
+const pad = (value: number) => String(value).padStart(2, "0")
+
+const emptyRow = (label: string) =>
+	({ label, ...Object.fromEntries(USAGE_COUNTERS.map((counter) => [counter, 0])) }) as UsageRow
+
+// Workspaces are shown by folder name, with the full path on hover.
+const getFolderName = (workspace: string) => workspace.split(/[\\/]/).pop() || workspace
+
+/**
+ * The first day of a range, as YYYY-MM-DD, or undefined for all time.
+ */
+export function getRangeStart(range: UsageRange, now = new Date()): string | undefined {
+	if (range === "all") {
+		return undefined
+	}
+
+	const start = new Date(now.getFullYear(), now.getMonth(), now.getDate() - Number(range) + 1)
+	return `${start.getFullYear()}-${pad(start.getMonth() + 1)}-${pad(start.getDate())}`
+}
+
+/**
+ * Adds up usage entries by label.
+ */
+export function sumUsageBy(entries: UsageEntry[], getLabel: (entry: UsageEntry) => string): UsageRow[] {
+	const rows = new Map<string, UsageRow>()
+
+	for (const entry of entries) {
+		const label = getLabel(entry)
+		const row = rows.get(label) ?? emptyRow(label)
+
+		for (const counter of USAGE_COUNTERS) {
+			row[counter] += entry[counter]
+		}
+
+		rows.set(label, row)
+	}
+
+	return [...rows.values()]
+}
+
+const getGroupLabel = (entry: UsageEntry, group: UsageGroup) =>
+	group === "workspace" ? entry.workspace : group === "mode" ? entry.mode : `${entry.provider} / ${entry.modelId}`
+
+const formatColumn = (column: (typeof COLUMNS)[number], value: number) =>
+	column === "cost" || column === "cacheSavings" ? `$${value.toFixed(2)}` : formatLargeNumber(value)
+
+type UsageTableProps = {
+	labelTitle: string
+	rows: UsageRow[]
+	formatLabel?: (label: string) => string
+}
+
+const UsageTable = ({ labelTitle, rows, formatLabel }: UsageTableProps) => {
+	const { t } = useAppTranslation()
+
+	return (
+		<table className="w-full table-fixed text-sm border-collapse">
+			<thead>
+				<tr className="text-vscode-descriptionForeground">
+					<th className="w-1/3 text-left font-normal py-1">{labelTitle}</th>
+					{COLUMNS.map((column) => (
+						<th key={column} className="text-right font-normal py-1">
+							{t(`settings:usage.columns.${column}`)}
+						</th>
+					))}
+				</tr>
+			</thead>
+			<tbody>
+				{rows.map((row) => (
+					<tr key={row.label} className="border-t border-vscode-panel-border">
+						<td className="truncate py-1" title={row.label}>
+							{formatLabel ? formatLabel(row.label) : row.label}
+						</td>
+						{COLUMNS.map((column) => (
+							<td key={column} className="text-right py-1 tabular-nums">
+								{formatColumn(column, row[column])}
+							</td>
+						))}
+					</tr>
+				))}
+			</tbody>
+		</table>
+	)
+}
+
+/**
+ * The API usage of all tasks over a period, by workspace, mode or model and
+ * over time, from the usage log the extension keeps.
+ */
+export const UsageSettings = (props: HTMLAttributes<HTMLDivElement>) => {
+	const { t } = useAppTranslation()
+	const [entries, setEntries] = useState<UsageEntry[]>()
+	const [range, setRange] = useState<UsageRange>("30")
+	const [group, setGroup] = useState<UsageGroup>("workspace")
+
+	useEffect(() => {
+		const handleMessage = (event: MessageEvent) => {
+			if (event.data.type === "usageStats") {
+				setEntries(event.data.usageEntries ?? [])
+			}
+		}
+
+		window.addEventListener("message", handleMessage)
+		vscode.postMessage({ type: "requestUsageStats" })
+		return () => window.removeEventListener("message", handleMessage)
+	}, [])
+
+	const rangeStart = getRangeStart(range)
+
+	const { totals, byGroup, overTime } = useMemo(() => {
+		const inRange = (entries ?? []).filter((entry) => !rangeStart || entry.date >= rangeStart)
+		// Long periods are shown by month, which is what budgets are usually set for.
+		const byMonth = range === "90" || range === "all"
+		const getPeriod = (entry: UsageEntry) => (byMonth ? entry.date.slice(0, 7) : entry.date)
+
+		return {
+			totals: sumUsageBy(inRange, () => "")[0],
+			byGroup: sumUsageBy(inRange, (entry) => getGroupLabel(entry, group)).sort((a, b) => b.cost - a.cost),
+			overTime: sumUsageBy(inRange, getPeriod).sort((a, b) => b.label.localeCompare(a.label)),
+		}
+	}, [entries, rangeStart, range, group])
+
+	return (
+		<div {...props}>
+			<SectionHeader>{t("settings:sections.usage")}</SectionHeader>
+
+			<Section>
+				<div className="text-vscode-descriptionForeground text-sm">{t("settings:usage.description")}</div>
+
+				<div className="flex flex-wrap items-center gap-2">
+					<Select value={range} onValueChange={(value) => setRange(value as UsageRange)}>
+						<SelectTrigger className="w-40" data-testid="usage-range">
+							<SelectValue />
+						</SelectTrigger>
+						<SelectContent>
+							{RANGES.map((value) => (
+								<SelectItem key={value} value={value}>
+									{t(`settings:usage.range.${value}`)}
+								</SelectItem>
+							))}
+						</SelectContent>
+					</Select>
+					<Select value={group} onValueChange={(value) => setGroup(value as UsageGroup)}>
+						<SelectTrigger className="w-40" data-testid="usage-group">
+							<SelectValue />
+						</SelectTrigger>
+						<SelectContent>
+							{GROUPS.map((value) => (
+								<SelectItem key={value} value={value}>
+									{t("settings:usage.groupBy", { group: t(`settings:usage.groups.${value}`) })}
+								</SelectItem>
+							))}
+						</SelectContent>
+					</Select>
+					<Button
+						variant="secondary"
+						className="ml-auto"
+						disabled={!totals}
+						onClick={() => vscode.postMessage({ type: "exportUsageCsv", text: rangeStart })}
+						data-testid="export-usage">
+						{t("settings:usage.export")}
+					</Button>
+				</div>
+
+				{!entries ? (
+					<div className="text-vscode-descriptionForeground text-sm">{t("settings:usage.loading")}</div>
+				) : !totals ? (
+					<div className="text-vscode-descriptionForeground text-sm">{t("settings:usage.empty")}</div>
+				) : (
+					<>
+						<div className="grid grid-cols-3 gap-2" data-testid="usage-totals">
+							{(["cost", "cacheSavings", "requests"] as const).map((column) => (
+								<div key={column} className="rounded border border-vscode-panel-border px-3 py-2">
+									<div className="text-vscode-descriptionForeground text-xs">
+										{t(`settings:usage.columns.${column}`)}
+									</div>
+									<div className="text-lg font-medium tabular-nums">
+										{formatColumn(column, totals[column])}
+									</div>
+								</div>
+							))}
+						</div>
+
+						<UsageTable
+							labelTitle={t(`settings:usage.groups.${group}`)}
+							rows={byGroup}
+							formatLabel={group === "workspace" ? getFolderName : undefined}
+						/>
+
+						<UsageTable labelTitle={t("settings:usage.period")} rows={overTime} />
+					</>
+				)}
+			</Section>
+		</div>
+	)
+}
@@ -0,0 +1,103 @@
+import { VSCodeTextField } from "@vscode/webview-ui-toolkit/react"
+
+import {
+	type ProviderSettings,
+	type VoiceInputProvider,
+	DEFAULT_VOICE_INPUT_MODEL_ID,
+	DEFAULT_WHISPER_COMMAND,
+	voiceInputProviders,
+} from "@roo-code/types"
+
+import { useAppTranslation } from "@/i18n/TranslationContext"
+
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "../ui"
+
+type VoiceInputField =
+	| "voiceInputProvider"
+	| "voiceInputModelId"
+	| "voiceInputLanguage"
+	| "voiceInputLocalCommand"
+	| "voiceInputLocalModelPath"
+
+interface VoiceInputSettingsControlProps {
+	apiConfiguration: ProviderSettings
+	onChange: <K extends VoiceInputField>(field: K, value: ProviderSettings[K]) => void
+}
+
+export const VoiceInputSettingsControl = ({ apiConfiguration, onChange }: VoiceInputSettingsControlProps) => {
+	const { t } = useAppTranslation()
+	const { voiceInputProvider } = apiConfiguration
+
+	// Empty text fields fall back to the defaults.
+	const handleInput = (field: Exclude<VoiceInputField, "voiceInputProvider">) => (e: any) =>
+		onChange(field, (e.target as HTMLInputElement).value || undefined)
+
+	return (
+		<div className="flex flex-col gap-1">
+			<label className="block font-medium mb-1">{t("settings:providers.voiceInput.label")}</label>
+			<Select
+				value={voiceInputProvider ?? "off"}
+				onValueChange={(value) =>
+					onChange("voiceInputProvider", value === "off" ? undefined : (value as VoiceInputProvider))
+				}>
+				<SelectTrigger className="w-full" data-testid="voice-input-provider">
+					<SelectValue />
+				</SelectTrigger>
+				<SelectContent>
+					<SelectItem value="off">{t("settings:providers.voiceInput.provider.off")}</SelectItem>
+					{voiceInputProviders.map((provider) => (
+						<SelectItem key={provider} value={provider}>
+							{t(`settings:providers.voiceInput.provider.${provider}`)}
+						</SelectItem>
+					))}
+				</SelectContent>
+			</Select>
+			<div className="text-sm text-vscode-descriptionForeground">
+				{t("settings:providers.voiceInput.description")}
+			</div>
+			{voiceInputProvider === "provider" && (
+				<VSCodeTextField
+					value={apiConfiguration.voiceInputModelId || ""}
+					onInput={handleInput("voiceInputModelId")}
+					placeholder={DEFAULT_VOICE_INPUT_MODEL_ID}
+					className="w-full mt-2">
+					<label className="block font-medium mb-1">{t("settings:providers.voiceInput.model")}</label>
+				</VSCodeTextField>
+			)}
+			{voiceInputProvider === "local" && (
+				<>
+					<VSCodeTextField
+						value={apiConfiguration.voiceInputLocalModelPath || ""}
+						onInput={handleInput("voiceInputLocalModelPath")}
+						placeholder="~/whisper.cpp/models/ggml-base.en.bin"
+						className="w-full mt-2">
+						<label className="block font-medium mb-1">
+							{t("settings:providers.voiceInput.localModelPath")}
+						</label>
+					</VSCodeTextField>
+					<VSCodeTextField
+						value={apiConfiguration.voiceInputLocalCommand || ""}
+						onInput={handleInput("voiceInputLocalCommand")}
+						placeholder={DEFAULT_WHISPER_COMMAND}
+						className="w-full">
+						<label className="block font-medium mb-1">
+							{t("settings:providers.voiceInput.localCommand")}
+						</label>
+					</VSCodeTextField>
+					<div className="text-sm text-vscode-descriptionForeground">
+						{t("settings:providers.voiceInput.localDescription")}
+					</div>
+				</>
+			)}
+			{voiceInputProvider && (
+				<VSCodeTextField
+					value={apiConfiguration.voiceInputLanguage || ""}
+					onInput={handleInput("voiceInputLanguage")}
+					placeholder={t("settings:providers.voiceInput.languagePlaceholder")}
+					className="w-full mt-2">
+					<label className="block font-medium mb-1">{t("settings:providers.voiceInput.language")}</label>
+				</VSCodeTextField>
+			)}
+		</div>
+	)
+}
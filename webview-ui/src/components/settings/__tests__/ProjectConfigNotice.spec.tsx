@@ -0,0 +1,105 @@
+import { render, screen, fireEvent } from "@/utils/test-utils"
+
+import { vscode } from "@/utils/vscode"
+import { useExtensionState } from "@/context/ExtensionStateContext"
+
+import { ProjectConfigNotice } from "../ProjectConfigNotice"
+
+vi.mock("@/utils/vscode", () => ({
+	vscode: { postMessage: vi.fn() },
+}))
+
+vi.mock("@/context/ExtensionStateContext", () => ({
+	useExtensionState: vi.fn(),
+}))
+
+vi.mock("@/i18n/TranslationContext", () => ({
+	useAppTranslation: () => ({
+		t: (key: string, options?: Record<string, unknown>) => (options ? `${key} ${JSON.stringify(options)}` : key),
+	}),
+}))
+
+const filePath = "/workspace/.roo/config.yaml"
+
+describe("ProjectConfigNotice", () => {
+	beforeEach(() => {
+		vi.clearAllMocks()
+	})
+
+	it("renders nothing without a project configuration", () => {
+		;(useExtensionState as any).mockReturnValue({})
+
+		render(<ProjectConfigNotice />)
+
+		expect(screen.queryByTestId("project-config-notice")).not.toBeInTheDocument()
+	})
+
+	it("summarizes what the configuration sets", () => {
+		;(useExtensionState as any).mockReturnValue({
+			projectConfig: {
+				filePath,
+				config: {
+					deniedCommands: ["git push", "rm -rf"],
+					autoApprove: { alwaysAllowExecute: false, alwaysAllowWrite: true, deniedWritePaths: [".env"] },
+				},
+			},
+		})
+
+		render(<ProjectConfigNotice />)
+
+		expect(screen.getByText("settings:projectConfig.description")).toBeInTheDocument()
+		expect(screen.getAllByRole("listitem").map((item) => item.textContent)).toEqual([
+			'settings:projectConfig.summary.deniedCommands {"count":2}',
+			'settings:projectConfig.summary.disabledApprovals {"count":1}',
+			'settings:projectConfig.summary.deniedWritePaths {"count":1}',
+		])
+	})
+
+	it("shows why the configuration is invalid", () => {
+		;(useExtensionState as any).mockReturnValue({
+			projectConfig: { filePath, error: "deniedCommands: Expected array" },
+		})
+
+		render(<ProjectConfigNotice />)
+
+		expect(
+			screen.getByText('settings:projectConfig.invalid {"error":"deniedCommands: Expected array"}'),
+		).toBeInTheDocument()
+		expect(screen.queryByRole("list")).not.toBeInTheDocument()
+	})
+
+	it("suggests the commands the repository allows", () => {
+		;(useExtensionState as any).mockReturnValue({
+			projectConfig: { filePath, config: { allowedCommands: ["git", "npm test", "npm run lint"] } },
+		})
+		const onAllowCommands = vi.fn()
+
+		render(<ProjectConfigNotice allowedCommands={["git"]} onAllowCommands={onAllowCommands} />)
+
+		expect(
+			screen.getByText('settings:projectConfig.suggestedCommands {"commands":"npm test, npm run lint"}'),
+		).toBeInTheDocument()
+
+		fireEvent.click(screen.getByText("settings:projectConfig.allowSuggestedCommands"))
+		expect(onAllowCommands).toHaveBeenCalledWith(["npm test", "npm run lint"])
+	})
+
+	it("doesn't suggest commands that are already allowed", () => {
+		;(useExtensionState as any).mockReturnValue({
+			projectConfig: { filePath, config: { allowedCommands: ["git"] } },
+		})
+
+		render(<ProjectConfigNotice allowedCommands={["git"]} onAllowCommands={vi.fn()} />)
+
+		expect(screen.queryByTestId("project-config-suggested-commands")).not.toBeInTheDocument()
+	})
+
+	it("opens the configuration file", () => {
+		;(useExtensionState as any).mockReturnValue({ projectConfig: { filePath, config: {} } })
+
+		render(<ProjectConfigNotice />)
+		fireEvent.click(screen.getByText("settings:projectConfig.open"))
+
+		expect(vscode.postMessage).toHaveBeenCalledWith({ type: "openFile", text: filePath })
+	})
+})
@@ -0,0 +1,107 @@
+import { act, render, screen, fireEvent } from "@/utils/test-utils"
+
+import type { UsageEntry } from "@roo-code/types"
+
+import { vscode } from "@/utils/vscode"
+
+import { UsageSettings, getRangeStart, sumUsageBy } from "../UsageSettings"
+
+vi.mock("@/utils/vscode", () => ({
+	vscode: { postMessage: vi.fn() },
+}))
+
+vi.mock("@/i18n/TranslationContext", () => ({
+	useAppTranslation: () => ({
+		t: (key: string) => key,
+	}),
+}))
+
+const pad = (value: number) => String(value).padStart(2, "0")
+const now = new Date()
+const today = `${now.getFullYear()}-${pad(now.getMonth() + 1)}-${pad(now.getDate())}`
+
+const entry = (overrides: Partial<UsageEntry>): UsageEntry => ({
+	date: today,
+	workspace: "/projects/app",
+	mode: "code",
+	provider: "anthropic",
+	modelId: "claude-sonnet",
+	requests: 1,
+	tokensIn: 100,
+	tokensOut: 10,
+	cacheWrites: 0,
+	cacheReads: 0,
+	cacheSavings: 0,
+	cost: 0.1,
+	...overrides,
+})
+
+const sendUsage = (usageEntries: UsageEntry[]) =>
+	act(() => {
+		window.dispatchEvent(new MessageEvent("message", { data: { type: "usageStats", usageEntries } }))
+	})
+
+describe("UsageSettings", () => {
+	beforeEach(() => {
+		vi.clearAllMocks()
+	})
+
+	it("requests the usage when it's shown", () => {
+		render(<UsageSettings />)
+
+		expect(vscode.postMessage).toHaveBeenCalledWith({ type: "requestUsageStats" })
+		expect(screen.getByText("settings:usage.loading")).toBeInTheDocument()
+	})
+
+	it("shows the usage of the last 30 days by workspace", () => {
+		render(<UsageSettings />)
+		sendUsage([
+			entry({}),
+			entry({ workspace: "/projects/api", cost: 0.5 }),
+			entry({ workspace: "/projects/api", mode: "architect", cost: 0.25 }),
+			// Outside of the period
+			entry({ date: "2020-01-01", cost: 100 }),
+		])
+
+		expect(screen.getByTestId("usage-totals")).toHaveTextContent("$0.85")
+
+		const rows = screen.getAllByRole("row").map((row) => row.textContent)
+		expect(rows[1]).toBe("api220020$0.00$0.75")
+		expect(rows[2]).toBe("app110010$0.00$0.10")
+		expect(screen.getByTitle("/projects/api")).toBeInTheDocument()
+	})
+
+	it("shows a message when there's no usage in the period", () => {
+		render(<UsageSettings />)
+		sendUsage([entry({ date: "2020-01-01" })])
+
+		expect(screen.getByText("settings:usage.empty")).toBeInTheDocument()
+		expect(screen.getByTestId("export-usage")).toBeDisabled()
+	})
+
+	it("exports the usage of the period", () => {
+		render(<UsageSettings />)
+		sendUsage([entry({})])
+		fireEvent.click(screen.getByTestId("export-usage"))
+
+		expect(vscode.postMessage).toHaveBeenCalledWith({ type: "exportUsageCsv", text: getRangeStart("30") })
+	})
+})
+
+describe("getRangeStart", () => {
+	it("returns the first day of the period", () => {
+		expect(getRangeStart("7", new Date(2026, 2, 3))).toBe("2026-02-25")
+		expect(getRangeStart("all")).toBeUndefined()
+	})
+})
+
+describe("sumUsageBy", () => {
+	it("adds up the entries with the same label", () => {
+		const rows = sumUsageBy([entry({}), entry({ mode: "ask" }), entry({ requests: 2 })], ({ mode }) => mode)
+
+		expect(rows.map(({ label, requests }) => [label, requests])).toEqual([
+			["code", 3],
+			["ask", 1],
+		])
+	})
+})
@@ -27,6 +27,8 @@ type BedrockProps = {
 export const Bedrock = ({ apiConfiguration, setApiConfigurationField, selectedModelInfo }: BedrockProps) => {
 	const { t } = useAppTranslation()
 	const [awsEndpointSelected, setAwsEndpointSelected] = useState(!!apiConfiguration?.awsBedrockEndpointEnabled)
+	// Kept as typed so separators aren't dropped while editing the list
+	const [fallbackRegions, setFallbackRegions] = useState(apiConfiguration?.awsFallbackRegions?.join(", ") ?? "")
 
 	// Check if the selected model supports 1M context (supported Claude 4 models)
 	const supports1MContextBeta =
@@ -195,6 +197,26 @@ export const Bedrock = ({ apiConfiguration, setApiConfigurationField, selectedMo
 				}}>
 				{t("settings:providers.awsCrossRegion")}
 			</Checkbox>
+			<div>
+				<VSCodeTextField
+					value={fallbackRegions}
+					onInput={(e) => {
+						const value = (e.target as HTMLInputElement).value
+						const regions = value
+							.split(",")
+							.map((region) => region.trim())
+							.filter(Boolean)
+						setFallbackRegions(value)
+						setApiConfigurationField("awsFallbackRegions", regions.length > 0 ? regions : undefined)
+					}}
+					placeholder="us-west-2, us-east-2"
+					className="w-full">
+					<label className="block font-medium mb-1">{t("settings:providers.awsFallbackRegions")}</label>
+				</VSCodeTextField>
+				<div className="text-sm text-vscode-descriptionForeground mt-1">
+					{t("settings:providers.awsFallbackRegionsDescription")}
+				</div>
+			</div>
 			{selectedModelInfo?.supportsPromptCache && (
 				<>
 					<Checkbox
@@ -1,10 +1,13 @@
-import { useMemo } from "react"
+import { useCallback, useMemo, useState } from "react"
+import { useEvent } from "react-use"
 import { VSCodeTextField } from "@vscode/webview-ui-toolkit/react"
 
-import type { ProviderSettings } from "@roo-code/types"
+import type { BedrockInferenceProfile, ExtensionMessage, ProviderSettings } from "@roo-code/types"
 
 import { validateBedrockArn } from "@src/utils/validate"
+import { vscode } from "@src/utils/vscode"
 import { useAppTranslation } from "@src/i18n/TranslationContext"
+import { Button, Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@src/components/ui"
 
 type BedrockCustomArnProps = {
 	apiConfiguration: ProviderSettings
@@ -13,12 +16,37 @@ type BedrockCustomArnProps = {
 
 export const BedrockCustomArn = ({ apiConfiguration, setApiConfigurationField }: BedrockCustomArnProps) => {
 	const { t } = useAppTranslation()
+	const [profiles, setProfiles] = useState<BedrockInferenceProfile[] | undefined>()
+	const [profilesStatus, setProfilesStatus] = useState<"idle" | "loading" | "error">("idle")
+	const [profilesError, setProfilesError] = useState<string | undefined>()
 
 	const validation = useMemo(() => {
 		const { awsCustomArn, awsRegion } = apiConfiguration
 		return awsCustomArn ? validateBedrockArn(awsCustomArn, awsRegion) : { isValid: true, errorMessage: undefined }
 	}, [apiConfiguration])
 
+	const onMessage = useCallback((event: MessageEvent) => {
+		const message: ExtensionMessage = event.data
+
+		if (message.type === "bedrockInferenceProfiles") {
+			setProfiles(message.bedrockInferenceProfiles ?? [])
+			setProfilesStatus(message.error ? "error" : "idle")
+			setProfilesError(message.error)
+		}
+	}, [])
+
+	useEvent("message", onMessage)
+
+	const selectedProfileArn = profiles?.some(({ arn }) => arn === apiConfiguration.awsCustomArn)
+		? apiConfiguration.awsCustomArn
+		: ""
+
+	const handleLoadProfiles = useCallback(() => {
+		setProfilesStatus("loading")
+		setProfilesError(undefined)
+		vscode.postMessage({ type: "requestBedrockInferenceProfiles", apiConfiguration })
+	}, [apiConfiguration])
+
 	return (
 		<>
 			<VSCodeTextField
@@ -34,6 +62,7 @@ export const BedrockCustomArn = ({ apiConfiguration, setApiConfigurationField }:
 					<li>
 						arn:aws:bedrock:eu-west-1:123456789012:inference-profile/eu.anthropic.claude-3-7-sonnet-20250219-v1:0
 					</li>
+					<li>arn:aws:bedrock:us-east-1:123456789012:application-inference-profile/a1b2c3d4e5f6</li>
 					<li>arn:aws:bedrock:us-west-2:123456789012:provisioned-model/my-provisioned-model</li>
 					<li>arn:aws:bedrock:us-east-1:123456789012:default-prompt-router/anthropic.claude:1</li>
 				</ul>
@@ -48,6 +77,51 @@ export const BedrockCustomArn = ({ apiConfiguration, setApiConfigurationField }:
 					<div className="text-sm text-vscode-errorForeground mt-2">{validation.errorMessage}</div>
 				)
 			)}
+			<Button
+				variant="outline"
+				onClick={handleLoadProfiles}
+				disabled={profilesStatus === "loading"}
+				className="w-full">
+				<div className="flex items-center gap-2">
+					{profilesStatus === "loading" ? (
+						<span className="codicon codicon-loading codicon-modifier-spin" />
+					) : (
+						<span className="codicon codicon-refresh" />
+					)}
+					{t("settings:providers.awsInferenceProfiles.load")}
+				</div>
+			</Button>
+			{profilesStatus === "error" && (
+				<div className="text-sm text-vscode-errorForeground">
+					{t("settings:providers.awsInferenceProfiles.error", { error: profilesError })}
+				</div>
+			)}
+			{profilesStatus !== "error" && profiles?.length === 0 && (
+				<div className="text-sm text-vscode-descriptionForeground">
+					{t("settings:providers.awsInferenceProfiles.empty")}
+				</div>
+			)}
+			{profiles && profiles.length > 0 && (
+				<div>
+					<label className="block font-medium mb-1">
+						{t("settings:providers.awsInferenceProfiles.label")}
+					</label>
+					<Select
+						value={selectedProfileArn}
+						onValueChange={(value) => setApiConfigurationField("awsCustomArn", value)}>
+						<SelectTrigger className="w-full">
+							<SelectValue placeholder={t("settings:common.select")} />
+						</SelectTrigger>
+						<SelectContent>
+							{profiles.map(({ arn, name, modelId }) => (
+								<SelectItem key={arn} value={arn}>
+									{modelId ? `${name} (${modelId})` : name}
+								</SelectItem>
+							))}
+						</SelectContent>
+					</Select>
+				</div>
+			)}
 		</>
 	)
 }
@@ -8,6 +8,7 @@ import { useAppTranslation } from "@src/i18n/TranslationContext"
 import { VSCodeButtonLink } from "@src/components/common/VSCodeButtonLink"
 
 import { inputEventTransform } from "../transforms"
+import { GeminiContextCachingSetting } from "../GeminiContextCachingSetting"
 
 type GeminiProps = {
 	apiConfiguration: ProviderSettings
@@ -73,6 +74,11 @@ export const Gemini = ({ apiConfiguration, setApiConfigurationField }: GeminiPro
 					/>
 				)}
 			</div>
+
+			<GeminiContextCachingSetting
+				geminiContextCachingEnabled={apiConfiguration?.geminiContextCachingEnabled}
+				onChange={(checked: boolean) => setApiConfigurationField("geminiContextCachingEnabled", checked)}
+			/>
 		</>
 	)
 }
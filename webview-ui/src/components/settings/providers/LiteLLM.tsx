@@ -18,6 +18,8 @@ import { Button } from "@src/components/ui"
 import { inputEventTransform } from "../transforms"
 import { ModelPicker } from "../ModelPicker"
 
+import { PromptCacheBreakpoints } from "./PromptCacheBreakpoints"
+
 type LiteLLMProps = {
 	apiConfiguration: ProviderSettings
 	setApiConfigurationField: (field: keyof ProviderSettings, value: ProviderSettings[keyof ProviderSettings]) => void
@@ -182,6 +184,13 @@ export const LiteLLM = ({
 				}
 				return null
 			})()}
+
+			<div className="mt-4">
+				<PromptCacheBreakpoints
+					value={apiConfiguration.litellmPromptCacheBreakpoints}
+					onChange={(value) => setApiConfigurationField("litellmPromptCacheBreakpoints", value)}
+				/>
+			</div>
 		</>
 	)
 }
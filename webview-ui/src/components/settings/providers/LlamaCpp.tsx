@@ -0,0 +1,97 @@
+import { useCallback } from "react"
+import { Checkbox } from "vscrui"
+import { VSCodeTextField } from "@vscode/webview-ui-toolkit/react"
+
+import type { ProviderSettings } from "@roo-code/types"
+
+import { useAppTranslation } from "@src/i18n/TranslationContext"
+
+import { inputEventTransform } from "../transforms"
+
+type LlamaCppProps = {
+	apiConfiguration: ProviderSettings
+	setApiConfigurationField: (field: keyof ProviderSettings, value: ProviderSettings[keyof ProviderSettings]) => void
+}
+
+export const LlamaCpp = ({ apiConfiguration, setApiConfigurationField }: LlamaCppProps) => {
+	const { t } = useAppTranslation()
+
+	const handleInputChange = useCallback(
+		<K extends keyof ProviderSettings, E>(
+			field: K,
+			transform: (event: E) => ProviderSettings[K] = inputEventTransform,
+		) =>
+			(event: E | Event) => {
+				setApiConfigurationField(field, transform(event as E))
+			},
+		[setApiConfigurationField],
+	)
+
+	return (
+		<>
+			<VSCodeTextField
+				value={apiConfiguration?.llamaCppBaseUrl || ""}
+				type="url"
+				onInput={handleInputChange("llamaCppBaseUrl")}
+				placeholder={t("settings:defaults.llamaCppUrl")}
+				className="w-full">
+				<label className="block font-medium mb-1">{t("settings:providers.llamaCpp.baseUrl")}</label>
+			</VSCodeTextField>
+			<VSCodeTextField
+				value={apiConfiguration?.llamaCppApiKey || ""}
+				type="password"
+				onInput={handleInputChange("llamaCppApiKey")}
+				placeholder={t("settings:placeholders.apiKey")}
+				className="w-full">
+				<label className="block font-medium mb-1">{t("settings:providers.llamaCpp.apiKey")}</label>
+				<div className="text-xs text-vscode-descriptionForeground mt-1">
+					{t("settings:providers.llamaCpp.apiKeyHelp")}
+				</div>
+			</VSCodeTextField>
+			<VSCodeTextField
+				value={apiConfiguration?.llamaCppModelId || ""}
+				onInput={handleInputChange("llamaCppModelId")}
+				placeholder={t("settings:placeholders.modelId.llamaCpp")}
+				className="w-full">
+				<label className="block font-medium mb-1">{t("settings:providers.llamaCpp.modelId")}</label>
+				<div className="text-xs text-vscode-descriptionForeground mt-1">
+					{t("settings:providers.llamaCpp.modelIdHelp")}
+				</div>
+			</VSCodeTextField>
+			<div>
+				<Checkbox
+					checked={apiConfiguration?.llamaCppGrammarToolCalls ?? true}
+					onChange={(checked) => setApiConfigurationField("llamaCppGrammarToolCalls", checked)}>
+					{t("settings:providers.llamaCpp.grammarToolCalls")}
+				</Checkbox>
+				<div className="text-sm text-vscode-descriptionForeground mt-1">
+					{t("settings:providers.llamaCpp.grammarToolCallsDescription")}
+				</div>
+			</div>
+			<VSCodeTextField
+				value={apiConfiguration?.llamaCppSlotId?.toString() || ""}
+				onInput={(e) => {
+					const value = (e.target as HTMLInputElement)?.value
+					if (value === "") {
+						setApiConfigurationField("llamaCppSlotId", undefined)
+					} else {
+						const numValue = parseInt(value, 10)
+						if (!isNaN(numValue) && numValue >= 0) {
+							setApiConfigurationField("llamaCppSlotId", numValue)
+						}
+					}
+				}}
+				placeholder="e.g., 0"
+				className="w-full">
+				<label className="block font-medium mb-1">{t("settings:providers.llamaCpp.slotId")}</label>
+				<div className="text-xs text-vscode-descriptionForeground mt-1">
+					{t("settings:providers.llamaCpp.slotIdHelp")}
+				</div>
+			</VSCodeTextField>
+			<div className="text-sm text-vscode-descriptionForeground">
+				{t("settings:providers.llamaCpp.description")}
+				<span className="text-vscode-errorForeground ml-1">{t("settings:providers.llamaCpp.warning")}</span>
+			</div>
+		</>
+	)
+}
@@ -1,5 +1,6 @@
 import { useState, useCallback, useMemo, useEffect } from "react"
 import { useEvent } from "react-use"
+import { Checkbox } from "vscrui"
 import { VSCodeTextField } from "@vscode/webview-ui-toolkit/react"
 
 import type { ProviderSettings, ExtensionMessage, ModelRecord } from "@roo-code/types"
@@ -131,6 +132,38 @@ export const Ollama = ({ apiConfiguration, setApiConfigurationField }: OllamaPro
 					{t("settings:providers.ollama.numCtxHelp")}
 				</div>
 			</VSCodeTextField>
+			{apiConfiguration?.ollamaNumCtx === undefined && (
+				<div>
+					<Checkbox
+						checked={apiConfiguration?.ollamaAutoNumCtx === true}
+						onChange={(checked) => setApiConfigurationField("ollamaAutoNumCtx", checked)}>
+						{t("settings:providers.ollama.autoNumCtx")}
+					</Checkbox>
+					<div className="text-sm text-vscode-descriptionForeground mt-1">
+						{t("settings:providers.ollama.autoNumCtxHelp")}
+					</div>
+				</div>
+			)}
+			<VSCodeTextField
+				value={apiConfiguration?.ollamaKeepAlive || ""}
+				onInput={handleInputChange("ollamaKeepAlive")}
+				placeholder="e.g., 30m"
+				className="w-full">
+				<label className="block font-medium mb-1">{t("settings:providers.ollama.keepAlive")}</label>
+				<div className="text-xs text-vscode-descriptionForeground mt-1">
+					{t("settings:providers.ollama.keepAliveHelp")}
+				</div>
+			</VSCodeTextField>
+			<div>
+				<Checkbox
+					checked={apiConfiguration?.ollamaPreloadModel === true}
+					onChange={(checked) => setApiConfigurationField("ollamaPreloadModel", checked)}>
+					{t("settings:providers.ollama.preloadModel")}
+				</Checkbox>
+				<div className="text-sm text-vscode-descriptionForeground mt-1">
+					{t("settings:providers.ollama.preloadModelHelp")}
+				</div>
+			</div>
 			<div className="text-sm text-vscode-descriptionForeground">
 				{t("settings:providers.ollama.description")}
 				<span className="text-vscode-errorForeground ml-1">{t("settings:providers.ollama.warning")}</span>
@@ -9,12 +9,23 @@ import {
 	type ReasoningEffort,
 	type OrganizationAllowList,
 	type ExtensionMessage,
+	type AzureAuthMethod,
+	azureAuthMethods,
 	azureOpenAiDefaultApiVersion,
 	openAiModelInfoSaneDefaults,
+	openAiResponsesBuiltInTools,
 } from "@roo-code/types"
 
 import { useAppTranslation } from "@src/i18n/TranslationContext"
-import { Button, StandardTooltip } from "@src/components/ui"
+import {
+	Button,
+	Select,
+	SelectContent,
+	SelectItem,
+	SelectTrigger,
+	SelectValue,
+	StandardTooltip,
+} from "@src/components/ui"
 
 import { convertHeadersToObject } from "../utils/headers"
 import { inputEventTransform, noTransform } from "../transforms"
@@ -22,6 +33,8 @@ import { ModelPicker } from "../ModelPicker"
 import { R1FormatSetting } from "../R1FormatSetting"
 import { ThinkingBudget } from "../ThinkingBudget"
 
+import { PromptCacheBreakpoints } from "./PromptCacheBreakpoints"
+
 type OpenAICompatibleProps = {
 	apiConfiguration: ProviderSettings
 	setApiConfigurationField: <K extends keyof ProviderSettings>(
@@ -45,6 +58,10 @@ export const OpenAICompatible = ({
 
 	const [azureApiVersionSelected, setAzureApiVersionSelected] = useState(!!apiConfiguration?.azureApiVersion)
 
+	// Entra ID authentication is offered for Azure endpoints only
+	const isAzure =
+		!!apiConfiguration?.openAiUseAzure || /\.azure\.com(\/|:|$)/.test(apiConfiguration?.openAiBaseUrl ?? "")
+
 	const [openAiModels, setOpenAiModels] = useState<Record<string, ModelInfo> | null>(null)
 
 	const [customHeaders, setCustomHeaders] = useState<[string, string][]>(() => {
@@ -162,6 +179,54 @@ export const OpenAICompatible = ({
 				onChange={handleInputChange("openAiStreamingEnabled", noTransform)}>
 				{t("settings:modelInfo.enableStreaming")}
 			</Checkbox>
+			<div>
+				<Checkbox
+					checked={apiConfiguration?.openAiUseResponsesApi ?? false}
+					onChange={handleInputChange("openAiUseResponsesApi", noTransform)}
+					data-testid="checkbox-openai-responses-api">
+					{t("settings:providers.openAiResponses.useResponsesApi")}
+				</Checkbox>
+				<div className="text-sm text-vscode-descriptionForeground ml-6">
+					{t("settings:providers.openAiResponses.useResponsesApiDescription")}
+				</div>
+				{apiConfiguration?.openAiUseResponsesApi && (
+					<div className="flex flex-col gap-1 ml-6 mt-2">
+						<Checkbox
+							checked={apiConfiguration?.openAiResponsesStoreEnabled ?? false}
+							onChange={handleInputChange("openAiResponsesStoreEnabled", noTransform)}>
+							{t("settings:providers.openAiResponses.store")}
+						</Checkbox>
+						<div className="text-sm text-vscode-descriptionForeground ml-6">
+							{t("settings:providers.openAiResponses.storeDescription")}
+						</div>
+						<label className="block font-medium mt-2">
+							{t("settings:providers.openAiResponses.builtInTools")}
+						</label>
+						{openAiResponsesBuiltInTools.map((tool) => {
+							const enabledTools = apiConfiguration?.openAiResponsesBuiltInTools ?? []
+							return (
+								<Checkbox
+									key={tool}
+									checked={enabledTools.includes(tool)}
+									onChange={(checked: boolean) =>
+										setApiConfigurationField(
+											"openAiResponsesBuiltInTools",
+											checked
+												? [...enabledTools, tool]
+												: enabledTools.filter((enabled) => enabled !== tool),
+										)
+									}>
+									{t(`settings:providers.openAiResponses.tools.${tool}`)}
+								</Checkbox>
+							)
+						})}
+					</div>
+				)}
+			</div>
+			<PromptCacheBreakpoints
+				value={apiConfiguration?.openAiPromptCacheBreakpoints}
+				onChange={(value) => setApiConfigurationField("openAiPromptCacheBreakpoints", value)}
+			/>
 			<div>
 				<Checkbox
 					checked={apiConfiguration?.includeMaxTokens ?? true}
@@ -198,6 +263,41 @@ export const OpenAICompatible = ({
 					/>
 				)}
 			</div>
+			{isAzure && (
+				<div>
+					<label className="block font-medium mb-1">{t("settings:providers.azureAuth.label")}</label>
+					<Select
+						value={apiConfiguration?.azureAuthMethod ?? "apiKey"}
+						onValueChange={(value) =>
+							setApiConfigurationField("azureAuthMethod", value as AzureAuthMethod)
+						}>
+						<SelectTrigger className="w-full" data-testid="azure-auth-method">
+							<SelectValue />
+						</SelectTrigger>
+						<SelectContent>
+							{azureAuthMethods.map((method) => (
+								<SelectItem key={method} value={method}>
+									{t(`settings:providers.azureAuth.methods.${method}`)}
+								</SelectItem>
+							))}
+						</SelectContent>
+					</Select>
+					<div className="text-sm text-vscode-descriptionForeground mt-1">
+						{t("settings:providers.azureAuth.description")}
+					</div>
+					{apiConfiguration?.azureAuthMethod === "managedIdentity" && (
+						<VSCodeTextField
+							value={apiConfiguration?.azureManagedIdentityClientId || ""}
+							onInput={handleInputChange("azureManagedIdentityClientId")}
+							placeholder={t("settings:providers.azureAuth.clientIdPlaceholder")}
+							className="w-full mt-2">
+							<label className="block font-medium mb-1">
+								{t("settings:providers.azureAuth.clientId")}
+							</label>
+						</VSCodeTextField>
+					)}
+				</div>
+			)}
 
 			{/* Custom Headers UI */}
 			<div className="mb-4">
@@ -0,0 +1,49 @@
+import { Checkbox } from "vscrui"
+
+import { type PromptCacheBreakpoint, promptCacheBreakpoints } from "@roo-code/types"
+
+import { useAppTranslation } from "@src/i18n/TranslationContext"
+
+// Segments a custom configuration starts with, matching the default caching.
+const DEFAULT_BREAKPOINTS: PromptCacheBreakpoint[] = ["systemPrompt", "environmentDetails"]
+
+type PromptCacheBreakpointsProps = {
+	value?: PromptCacheBreakpoint[]
+	onChange: (value: PromptCacheBreakpoint[] | undefined) => void
+}
+
+export const PromptCacheBreakpoints = ({ value, onChange }: PromptCacheBreakpointsProps) => {
+	const { t } = useAppTranslation()
+
+	return (
+		<div>
+			<Checkbox
+				checked={value !== undefined}
+				onChange={(checked: boolean) => onChange(checked ? DEFAULT_BREAKPOINTS : undefined)}
+				data-testid="checkbox-prompt-cache-breakpoints">
+				{t("settings:providers.promptCacheBreakpoints.label")}
+			</Checkbox>
+			<div className="text-sm text-vscode-descriptionForeground ml-6">
+				{t("settings:providers.promptCacheBreakpoints.description")}
+			</div>
+			{value && (
+				<div className="flex flex-col gap-1 ml-6 mt-2">
+					{promptCacheBreakpoints.map((breakpoint) => (
+						<Checkbox
+							key={breakpoint}
+							checked={value.includes(breakpoint)}
+							onChange={(checked: boolean) =>
+								onChange(
+									checked
+										? [...value, breakpoint]
+										: value.filter((enabled) => enabled !== breakpoint),
+								)
+							}>
+							{t(`settings:providers.promptCacheBreakpoints.segments.${breakpoint}`)}
+						</Checkbox>
+					))}
+				</div>
+			)}
+		</div>
+	)
+}
@@ -8,6 +8,7 @@ import { useAppTranslation } from "@src/i18n/TranslationContext"
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@src/components/ui"
 
 import { inputEventTransform } from "../transforms"
+import { GeminiContextCachingSetting } from "../GeminiContextCachingSetting"
 
 type VertexProps = {
 	apiConfiguration: ProviderSettings
@@ -115,6 +116,13 @@ export const Vertex = ({ apiConfiguration, setApiConfigurationField }: VertexPro
 					</div>
 				</div>
 			)}
+
+			{!apiConfiguration?.apiModelId?.startsWith("claude") && (
+				<GeminiContextCachingSetting
+					geminiContextCachingEnabled={apiConfiguration?.geminiContextCachingEnabled}
+					onChange={(checked: boolean) => setApiConfigurationField("geminiContextCachingEnabled", checked)}
+				/>
+			)}
 		</>
 	)
 }
@@ -2,6 +2,7 @@ export { Anthropic } from "./Anthropic"
 export { Bedrock } from "./Bedrock"
 export { DeepSeek } from "./DeepSeek"
 export { Gemini } from "./Gemini"
+export { LlamaCpp } from "./LlamaCpp"
 export { LMStudio } from "./LMStudio"
 export { Mistral } from "./Mistral"
 export { Moonshot } from "./Moonshot"
@@ -43,6 +43,7 @@ export const PROVIDER_SERVICE_CONFIG: Partial<Record<ProviderName, ProviderServi
 	baseten: { serviceName: "Baseten", serviceUrl: "https://baseten.co" },
 	ollama: { serviceName: "Ollama", serviceUrl: "https://ollama.ai" },
 	lmstudio: { serviceName: "LM Studio", serviceUrl: "https://lmstudio.ai/docs" },
+	llamacpp: { serviceName: "llama.cpp", serviceUrl: "https://github.com/ggml-org/llama.cpp" },
 	"vscode-lm": {
 		serviceName: "VS Code LM",
 		serviceUrl: "https://code.visualstudio.com/api/extension-guides/language-model",
@@ -126,6 +127,7 @@ export const PROVIDERS_WITH_CUSTOM_MODEL_UI: ProviderName[] = [
 	"roo",
 	"ollama",
 	"lmstudio",
+	"llamacpp",
 	"vscode-lm",
 ]
 
@@ -0,0 +1,66 @@
+import { getWordChanges } from "../wordDiff"
+import { pairDiffLines, parseUnifiedDiff } from "../parseUnifiedDiff"
+
+describe("getWordChanges", () => {
+	it("returns the ranges of the words that changed", () => {
+		const oldLine = "const total = sum(items, 1)"
+		const newLine = "const total = sum(values, 1)"
+
+		const changes = getWordChanges(oldLine, newLine)
+
+		expect(changes).toEqual({ oldRanges: [[18, 23]], newRanges: [[18, 24]] })
+		expect(oldLine.slice(18, 23)).toBe("items")
+		expect(newLine.slice(18, 24)).toBe("values")
+	})
+
+	it("only highlights the new side of an insertion", () => {
+		expect(getWordChanges("sum(items)", "sum(items, 1)")).toEqual({ oldRanges: [], newRanges: [[9, 12]] })
+	})
+
+	it("skips lines that were rewritten rather than edited", () => {
+		expect(getWordChanges("return first", "throw new Error(message)")).toBeUndefined()
+	})
+
+	it("skips identical and very long lines", () => {
+		expect(getWordChanges("same", "same")).toBeUndefined()
+		expect(getWordChanges("a".repeat(3000), "b".repeat(3000))).toBeUndefined()
+	})
+})
+
+describe("parseUnifiedDiff", () => {
+	const source = [
+		"--- a/file.ts",
+		"+++ b/file.ts",
+		"@@ -1,3 +1,3 @@",
+		" import { sum } from './sum'",
+		"-const total = sum(items, 1)",
+		"+const total = sum(values, 1)",
+		" export default total",
+		"",
+	].join("\n")
+
+	it("adds the changed words to lines that replace each other", () => {
+		const lines = parseUnifiedDiff(source)
+
+		expect(lines.map((line) => [line.type, line.wordChanges])).toEqual([
+			["context", undefined],
+			["deletion", [[18, 23]]],
+			["addition", [[18, 24]]],
+			["context", undefined],
+		])
+	})
+})
+
+describe("pairDiffLines", () => {
+	it("pairs deletions with the additions that follow them", () => {
+		const lines = parseUnifiedDiff(
+			["--- a/f", "+++ b/f", "@@ -1,3 +1,2 @@", " a", "-b", "-c", "+d", ""].join("\n"),
+		)
+
+		expect(pairDiffLines(lines)).toEqual([
+			{ left: 0, right: 0 },
+			{ left: 1, right: 3 },
+			{ left: 2, right: undefined },
+		])
+	})
+})
@@ -1,5 +1,7 @@
 import i18next from "i18next"
 
+import type { BudgetMetric } from "@roo-code/types"
+
 export function formatLargeNumber(num: number): string {
 	if (num >= 1e9) {
 		return (num / 1e9).toFixed(1) + i18next.t("common:number_format.billion_suffix")
@@ -13,6 +15,10 @@ export function formatLargeNumber(num: number): string {
 	return num.toString()
 }
 
+export function formatBudgetValue(metric: BudgetMetric, value: number): string {
+	return metric === "cost" ? `$${value.toFixed(2)}` : formatLargeNumber(value)
+}
+
 export const formatDate = (timestamp: number) => {
 	const date = new Date(timestamp)
 	const locale = i18next.language || "en"
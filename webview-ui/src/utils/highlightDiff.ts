@@ -2,10 +2,31 @@ import { ReactNode } from "react"
 import { getHighlighter } from "./highlighter"
 import { toJsxRuntime } from "hast-util-to-jsx-runtime"
 import { Fragment, jsx, jsxs } from "react/jsx-runtime"
+import type { DecorationItem } from "shiki"
+
+import type { WordRange } from "./wordDiff"
+
+/**
+ * The changed words of each line of the old and new text.
+ */
+export interface HunkWordChanges {
+	oldLines: (WordRange[] | undefined)[]
+	newLines: (WordRange[] | undefined)[]
+}
+
+const toDecorations = (lineRanges: (WordRange[] | undefined)[], className: string): DecorationItem[] =>
+	lineRanges.flatMap((ranges, line) =>
+		(ranges ?? []).map(([start, end]) => ({
+			start: { line, character: start },
+			end: { line, character: end },
+			properties: { class: className },
+		})),
+	)
 
 /**
  * Highlight two pieces of code (old and new) in a single pass and return
- * arrays of ReactNode representing each line
+ * arrays of ReactNode representing each line. Changed words are wrapped in
+ * `diff-word-removed` and `diff-word-inserted` spans.
  */
 export async function highlightHunks(
 	oldText: string,
@@ -14,13 +35,18 @@ export async function highlightHunks(
 	theme: "light" | "dark",
 	_hunkIndex = 0,
 	_filePath?: string,
+	wordChanges?: HunkWordChanges,
 ): Promise<{ oldLines: ReactNode[]; newLines: ReactNode[] }> {
 	try {
 		const highlighter = await getHighlighter(lang)
 		const shikiTheme = theme === "light" ? "github-light" : "github-dark"
 
 		// Helper to highlight text and extract lines
-		const highlightAndExtractLines = (text: string): ReactNode[] => {
+		const highlightAndExtractLines = (
+			text: string,
+			lineRanges: (WordRange[] | undefined)[] = [],
+			className = "",
+		): ReactNode[] => {
 			const textLines = text.split("\n")
 
 			if (!text.trim()) {
@@ -32,6 +58,7 @@ export async function highlightHunks(
 				const hast: any = highlighter.codeToHast(text, {
 					lang,
 					theme: shikiTheme,
+					decorations: toDecorations(lineRanges, className),
 					transformers: [
 						{
 							pre(node: any) {
@@ -74,13 +101,14 @@ export async function highlightHunks(
 				// If we didn't get the expected structure, fall back to simple approach
 				if (highlightedLines.length !== textLines.length) {
 					// For each line, highlight it individually (fallback)
-					return textLines.map((line) => {
+					return textLines.map((line, lineIndex) => {
 						if (!line.trim()) return line
 
 						try {
 							const lineHast: any = highlighter.codeToHast(line, {
 								lang,
 								theme: shikiTheme,
+								decorations: toDecorations(lineRanges.slice(lineIndex, lineIndex + 1), className),
 								transformers: [
 									{
 										pre(node: any) {
@@ -117,8 +145,8 @@ export async function highlightHunks(
 		}
 
 		// Process both old and new text
-		const oldLines = highlightAndExtractLines(oldText)
-		const newLines = highlightAndExtractLines(newText)
+		const oldLines = highlightAndExtractLines(oldText, wordChanges?.oldLines, "diff-word-removed")
+		const newLines = highlightAndExtractLines(newText, wordChanges?.newLines, "diff-word-inserted")
 
 		return { oldLines, newLines }
 	} catch {
@@ -1,17 +1,81 @@
 import { parsePatch } from "diff"
 
+import { type WordRange, getWordChanges } from "./wordDiff"
+
 export interface DiffLine {
 	oldLineNum: number | null
 	newLineNum: number | null
 	type: "context" | "addition" | "deletion" | "gap"
 	content: string
 	hiddenCount?: number
+	// The words that changed from, or to, the line it replaces or is replaced by.
+	wordChanges?: WordRange[]
+}
+
+/**
+ * A row of a side-by-side diff, as indexes into the diff's lines. Context
+ * lines and gaps are on both sides.
+ */
+export interface DiffLinePair {
+	left?: number
+	right?: number
+}
+
+/**
+ * Pairs each run of deletions with the run of additions that follows it,
+ * line by line, the way VS Code's diff editor lines them up.
+ */
+export function pairDiffLines(lines: DiffLine[]): DiffLinePair[] {
+	const pairs: DiffLinePair[] = []
+	let i = 0
+
+	while (i < lines.length) {
+		if (lines[i].type === "context" || lines[i].type === "gap") {
+			pairs.push({ left: i, right: i })
+			i++
+			continue
+		}
+
+		const deletions: number[] = []
+		const additions: number[] = []
+
+		while (i < lines.length && lines[i].type === "deletion") {
+			deletions.push(i++)
+		}
+
+		while (i < lines.length && lines[i].type === "addition") {
+			additions.push(i++)
+		}
+
+		for (let j = 0; j < Math.max(deletions.length, additions.length); j++) {
+			pairs.push({ left: deletions[j], right: additions[j] })
+		}
+	}
+
+	return pairs
+}
+
+function addWordChanges(lines: DiffLine[]): DiffLine[] {
+	for (const { left, right } of pairDiffLines(lines)) {
+		if (left === undefined || right === undefined || lines[left].type !== "deletion") {
+			continue
+		}
+
+		const changes = getWordChanges(lines[left].content, lines[right].content)
+
+		if (changes) {
+			lines[left].wordChanges = changes.oldRanges
+			lines[right].wordChanges = changes.newRanges
+		}
+	}
+
+	return lines
 }
 
 /**
  * Parse a unified diff string into a flat list of renderable lines with
  * line numbers, addition/deletion/context flags, and compact "gap" separators
- * between hunks.
+ * between hunks. Lines that replace each other get the words that changed.
  */
 export function parseUnifiedDiff(source: string, filePath?: string): DiffLine[] {
 	if (!source) return []
@@ -88,7 +152,7 @@ export function parseUnifiedDiff(source: string, filePath?: string): DiffLine[]
 			prevHunk = hunk
 		}
 
-		return lines
+		return addWordChanges(lines)
 	} catch {
 		// swallow parse errors and render nothing rather than breaking the UI
 		return []
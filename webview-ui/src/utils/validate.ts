@@ -11,6 +11,7 @@ import {
 	isDynamicProvider,
 	isFauxProvider,
 	isCustomProvider,
+	isAzureEntraAuth,
 } from "@roo-code/types"
 
 export function validateApiConfiguration(
@@ -89,7 +90,12 @@ function validateModelsAndKeysProvided(apiConfiguration: ProviderSettings): stri
 			}
 			break
 		case "openai":
-			if (!apiConfiguration.openAiBaseUrl || !apiConfiguration.openAiApiKey || !apiConfiguration.openAiModelId) {
+			// Entra ID authentication replaces the API key
+			if (
+				!apiConfiguration.openAiBaseUrl ||
+				(!apiConfiguration.openAiApiKey && !isAzureEntraAuth(apiConfiguration.azureAuthMethod)) ||
+				!apiConfiguration.openAiModelId
+			) {
 				return i18next.t("settings:validation.openAi")
 			}
 			break
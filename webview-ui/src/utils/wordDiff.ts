@@ -0,0 +1,53 @@
+import { diffWordsWithSpace } from "diff"
+
+/**
+ * A [start, end) character range of a line.
+ */
+export type WordRange = [start: number, end: number]
+
+// Lines sharing less than this are rewritten rather than edited, and highlighting their words would only add noise.
+const MIN_UNCHANGED_RATIO = 0.4
+
+// Diffing words is quadratic, so longer lines are only compared as a whole.
+const MAX_LINE_LENGTH = 2000
+
+/**
+ * The words that changed between two versions of a line, or undefined when
+ * the lines have too little in common for that to help.
+ */
+export function getWordChanges(
+	oldLine: string,
+	newLine: string,
+): { oldRanges: WordRange[]; newRanges: WordRange[] } | undefined {
+	if (oldLine === newLine || oldLine.length > MAX_LINE_LENGTH || newLine.length > MAX_LINE_LENGTH) {
+		return undefined
+	}
+
+	const oldRanges: WordRange[] = []
+	const newRanges: WordRange[] = []
+	let oldOffset = 0
+	let newOffset = 0
+	let unchanged = 0
+
+	for (const part of diffWordsWithSpace(oldLine, newLine)) {
+		const length = part.value.length
+
+		if (part.removed) {
+			oldRanges.push([oldOffset, oldOffset + length])
+			oldOffset += length
+		} else if (part.added) {
+			newRanges.push([newOffset, newOffset + length])
+			newOffset += length
+		} else {
+			unchanged += length
+			oldOffset += length
+			newOffset += length
+		}
+	}
+
+	if (unchanged < Math.max(oldLine.length, newLine.length) * MIN_UNCHANGED_RATIO) {
+		return undefined
+	}
+
+	return { oldRanges, newRanges }
+}